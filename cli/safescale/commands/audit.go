@@ -0,0 +1,128 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+
+	"github.com/CS-SI/SafeScale/lib/client"
+	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
+	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/exitcode"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/strprocess"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+var auditCmdName = "audit"
+
+// AuditCommand command
+var AuditCommand = &cli.Command{
+	Name:  "audit",
+	Usage: "audit COMMAND",
+	Subcommands: []*cli.Command{
+		auditList,
+	},
+}
+
+var auditList = &cli.Command{
+	Name:    "list",
+	Aliases: []string{"ls"},
+	Usage:   "List the records of the mutating operations done by safescaled",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "since",
+			Usage: "Lists only the records newer than a date (2006-01-02 or RFC3339) or than a duration ago (ex: 90m, 24h, 7d)",
+		},
+		&cli.StringFlag{
+			Name:  "resource",
+			Usage: "Lists only the records on the resources matching a pattern (ex: my-host, my-cluster-*)",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", auditCmdName, c.Command.Name, c.Args())
+
+		var since time.Time
+		if value := c.String("since"); value != "" {
+			var xerr fail.Error
+			if since, xerr = parseSince(value, time.Now()); xerr != nil {
+				return clitools.FailureResponse(clitools.ExitOnInvalidOption(xerr.Error()))
+			}
+		}
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		list, err := clientSession.Audit.List(since, c.String("resource"), temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "list of audit records", false).Error())))
+		}
+
+		var out []map[string]interface{}
+		for _, v := range list.GetRecords() {
+			item := map[string]interface{}{
+				"time":        v.GetTime(),
+				"identity":    v.GetIdentity(),
+				"tenant":      v.GetTenant(),
+				"action":      v.GetAction(),
+				"resource":    v.GetResource(),
+				"job_id":      v.GetJobId(),
+				"duration_ms": v.GetDurationMs(),
+				"outcome":     v.GetOutcome(),
+			}
+			if v.GetError() != "" {
+				item["error"] = v.GetError()
+			}
+			if v.GetParameters() != "" {
+				var params map[string]interface{}
+				if err = json.Unmarshal([]byte(v.GetParameters()), &params); err == nil {
+					item["parameters"] = params
+				}
+			}
+			out = append(out, item)
+		}
+		return clitools.SuccessResponse(out)
+	},
+}
+
+// parseSince converts the value of flag --since to a date: either a date (2006-01-02 or RFC3339), or a duration
+// before now (as accepted by time.ParseDuration, or a number of days suffixed by 'd')
+func parseSince(value string, now time.Time) (time.Time, fail.Error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if strings.HasSuffix(value, "d") {
+		if days, err := strconv.Atoi(strings.TrimSuffix(value, "d")); err == nil && days >= 0 {
+			return now.AddDate(0, 0, -days), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fail.SyntaxError("invalid value '%s' for --since: must be a date (2006-01-02 or RFC3339) or a duration (ex: 90m, 24h, 7d)", value)
+}
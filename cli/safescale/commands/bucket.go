@@ -51,7 +51,7 @@ var bucketList = &cli.Command{
 	Action: func(c *cli.Context) error {
 		logrus.Tracef("SafeScale command: %s %s with args '%s'", bucketCmdLabel, c.Command.Name, c.Args())
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(xerr)
 		}
@@ -77,7 +77,7 @@ var bucketCreate = &cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Bucket_name>."))
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(xerr)
 		}
@@ -107,7 +107,7 @@ var bucketDelete = &cli.Command{
 		bucketList = append(bucketList, c.Args().First())
 		bucketList = append(bucketList, c.Args().Tail()...)
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(xerr)
 		}
@@ -133,7 +133,7 @@ var bucketInspect = &cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Bucket_name>."))
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(xerr)
 		}
@@ -165,7 +165,7 @@ var bucketMount = &cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Bucket_name> and/or <Host_name>."))
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(xerr)
 		}
@@ -191,7 +191,7 @@ var bucketUnmount = &cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Bucket_name> and/or <Host_name>."))
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(xerr)
 		}
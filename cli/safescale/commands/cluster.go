@@ -18,6 +18,7 @@ package commands
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -58,9 +59,13 @@ var ClusterCommand = &cli.Command{
 	Subcommands: []*cli.Command{
 		clusterNodeCommands,
 		clusterMasterCommands,
+		clusterNodePoolCommands,
+		clusterAutoscalingCommands,
+		clusterHealthCommands,
 		clusterFeatureCommands,
 		clusterListCommand,
 		clusterCreateCommand,
+		clusterResumeCommand,
 		clusterDeleteCommand,
 		clusterInspectCommand,
 		clusterStateCommand,
@@ -70,8 +75,12 @@ var ClusterCommand = &cli.Command{
 		clusterStopCommand,
 		clusterExpandCommand,
 		clusterShrinkCommand,
+		clusterUpgradeCommand,
+		clusterBackupCommand,
+		clusterRestoreCommand,
 		clusterKubectlCommand,
 		clusterHelmCommand,
+		clusterKubeconfigCommand,
 		clusterListFeaturesCommand,
 		clusterCheckFeatureCommand,
 		clusterAddFeatureCommand,
@@ -86,15 +95,27 @@ var clusterListCommand = &cli.Command{
 	Aliases: []string{"ls"},
 	Usage:   "ErrorList available clusters",
 
+	Flags: []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:  "label",
+			Usage: "Lists only clusters having this label, in the form key=value or key (may be used several times)",
+		},
+	},
+
 	Action: func(c *cli.Context) error {
 		logrus.Tracef("SafeScale command: %s %s with args '%s'", clusterCmdLabel, c.Command.Name, c.Args())
 
-		clientSession, xerr := client.New(c.String("server"))
+		labels, err := constructLabelsFromCLI(c, true)
+		if err != nil {
+			return err
+		}
+
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
 
-		list, err := clientSession.Cluster.List(temporal.DefaultExecutionTimeout)
+		list, err := clientSession.Cluster.List(labels, temporal.DefaultExecutionTimeout)
 		if err != nil {
 			err := fail.FromGRPCStatus(err)
 			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "failed to get cluster list", false).Error())))
@@ -183,7 +204,7 @@ var clusterInspectCommand = &cli.Command{
 			return clitools.FailureResponse(err)
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -243,6 +264,10 @@ func convertToMap(c *protocol.ClusterResponse) (map[string]interface{}, fail.Err
 		// "ssh_private_key": c.GetIdentity().GetPrivateKey(),
 	}
 
+	if len(c.GetLabels()) > 0 {
+		result["labels"] = c.GetLabels()
+	}
+
 	if c.Composite != nil && len(c.Composite.Tenants) > 0 {
 		result["tenants"] = strings.Join(c.Composite.Tenants, ", ")
 	}
@@ -308,6 +333,7 @@ var clusterCreateCommand = &cli.Command{
 	ArgsUsage: "CLUSTERNAME",
 
 	Flags: []cli.Flag{
+		asyncFlag,
 		&cli.StringFlag{
 			Name:    "complexity",
 			Aliases: []string{"C"},
@@ -316,16 +342,18 @@ var clusterCreateCommand = &cli.Command{
 	Default number of machines (#master, #nodes) depending of flavor are:
 		BOH: Small(1,1), Normal(3,3), Large(5,6)
 		K8S: Small(1,1), Normal(3,3), Large(5,6)
+		K3S: Small(1,1), Normal(3,2), Large(3,5)
 	`,
 		},
 		&cli.StringFlag{
 			Name:    "flavor",
 			Aliases: []string{"F"},
 			Value:   "K8S",
-			Usage: `Defines the type of the cluster; can be BOH, K8S
+			Usage: `Defines the type of the cluster; can be BOH, K8S, K3S
 	Default sizing for each cluster type is:
 		BOH: gws(cpu=[2-4], ram=[7-16], disk=[50]), masters(cpu=[4-8], ram=[15-32], disk=[100]), nodes(cpu=[2-4], ram=[15-32], disk=[80])
 		K8S: gws(cpu=[2-4], ram=[7-16], disk=[50]), masters(cpu=[4-8], ram=[15-32], disk=[100]), nodes(cpu=[4-8], ram=[15-32], disk=[80])
+		K3S: gws(cpu=[1-2], ram=[2-4], disk=[20]), masters(cpu=[2-4], ram=[4-8], disk=[40]), nodes(cpu=[2-4], ram=[4-8], disk=[40])
 	`,
 		},
 		&cli.BoolFlag{
@@ -398,6 +426,10 @@ var clusterCreateCommand = &cli.Command{
 	example:
 		--node-sizing "cpu~4, ram~15, count=8" will create 8 nodes`,
 		},
+		&cli.StringSliceFlag{
+			Name:  "label",
+			Usage: "Label to set on the cluster and all its hosts, in the form key=value (may be used several times)",
+		},
 	},
 
 	Action: func(c *cli.Context) (err error) {
@@ -426,6 +458,10 @@ var clusterCreateCommand = &cli.Command{
 		cidr := c.String("cidr")
 		disable := c.StringSlice("disable")
 		los := c.String("os")
+		labels, err := constructLabelsFromCLI(c, false)
+		if err != nil {
+			return err
+		}
 
 		var (
 			globalDef   string
@@ -458,7 +494,7 @@ var clusterCreateCommand = &cli.Command{
 			}
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -476,6 +512,7 @@ var clusterCreateCommand = &cli.Command{
 			MasterSizing:  mastersDef,
 			NodeSizing:    nodesDef,
 			Force:         force,
+			Labels:        labels,
 			// NodeCount:     uint32(c.Int("initial-node-count")),
 		}
 		res, err := clientSession.Cluster.Create(&req, temporal.GetLongOperationTimeout())
@@ -484,6 +521,9 @@ var clusterCreateCommand = &cli.Command{
 			err = fail.FromGRPCStatus(err)
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, err.Error()))
 		}
+		if c.Bool("async") {
+			return asyncResponse(clientSession)
+		}
 		if res == nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, "failed to create cluster: unknown reason"))
 		}
@@ -501,6 +541,54 @@ var clusterCreateCommand = &cli.Command{
 	},
 }
 
+// clusterResumeCommand handles 'safescale cluster resume CLUSTERNAME'
+var clusterResumeCommand = &cli.Command{
+	Name:      "resume",
+	Aliases:   []string{"continue"},
+	Usage:     "resume CLUSTERNAME",
+	ArgsUsage: "CLUSTERNAME",
+
+	Flags: []cli.Flag{
+		asyncFlag,
+	},
+
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", clusterCmdLabel, c.Command.Name, c.Args())
+		err := extractClusterName(c)
+		if err != nil {
+			return clitools.FailureResponse(err)
+		}
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		res, err := clientSession.Cluster.Resume(clusterName, temporal.GetLongOperationTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, err.Error()))
+		}
+		if c.Bool("async") {
+			return asyncResponse(clientSession)
+		}
+		if res == nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, "failed to resume creation of cluster: unknown reason"))
+		}
+
+		toFormat, err := convertToMap(res)
+		if err != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, err.Error()))
+		}
+
+		formatted := formatClusterConfig(toFormat, true)
+		if !Debug {
+			delete(formatted, "defaults")
+		}
+		return clitools.SuccessResponse(formatted)
+	},
+}
+
 // clusterDeleteCmd handles 'deploy cluster <clustername> delete'
 var clusterDeleteCommand = &cli.Command{
 	Name:      "delete",
@@ -509,6 +597,7 @@ var clusterDeleteCommand = &cli.Command{
 	ArgsUsage: "CLUSTERNAME",
 
 	Flags: []cli.Flag{
+		asyncFlag,
 		&cli.BoolFlag{
 			Name:    "assume-yes",
 			Aliases: []string{"yes", "y"},
@@ -535,7 +624,7 @@ var clusterDeleteCommand = &cli.Command{
 			logrus.Println("'-f,--force' does nothing yet")
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -545,6 +634,9 @@ var clusterDeleteCommand = &cli.Command{
 			err = fail.FromGRPCStatus(err)
 			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
 		}
+		if c.Bool("async") {
+			return asyncResponse(clientSession)
+		}
 		return clitools.SuccessResponse(nil)
 	},
 }
@@ -563,7 +655,7 @@ var clusterStopCommand = &cli.Command{
 			return clitools.FailureResponse(err)
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -591,7 +683,7 @@ var clusterStartCommand = &cli.Command{
 		}
 		clusterRef := c.Args().First()
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -617,7 +709,7 @@ var clusterStateCommand = &cli.Command{
 			return clitools.FailureResponse(err)
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -643,6 +735,7 @@ var clusterExpandCommand = &cli.Command{
 	ArgsUsage: "CLUSTERNAME",
 
 	Flags: []cli.Flag{
+		asyncFlag,
 		&cli.UintFlag{
 			Name:    "count",
 			Aliases: []string{"n"},
@@ -700,7 +793,7 @@ var clusterExpandCommand = &cli.Command{
 			KeepOnFailure: keepOnFailure,
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -710,6 +803,9 @@ var clusterExpandCommand = &cli.Command{
 			err = fail.FromGRPCStatus(err)
 			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
 		}
+		if c.Bool("async") {
+			return asyncResponse(clientSession)
+		}
 		return clitools.SuccessResponse(hosts)
 	},
 }
@@ -721,6 +817,7 @@ var clusterShrinkCommand = &cli.Command{
 	ArgsUsage: "CLUSTERNAME",
 
 	Flags: []cli.Flag{
+		asyncFlag,
 		&cli.UintFlag{
 			Name:    "count",
 			Aliases: []string{"n"},
@@ -761,7 +858,7 @@ var clusterShrinkCommand = &cli.Command{
 			Count: int32(count),
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -770,6 +867,9 @@ var clusterShrinkCommand = &cli.Command{
 			err = fail.FromGRPCStatus(err)
 			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
 		}
+		if c.Bool("async") {
+			return asyncResponse(clientSession)
+		}
 		return clitools.SuccessResponse(nil)
 	},
 }
@@ -858,7 +958,7 @@ var clusterKubectlCommand = &cli.Command{
 			cmdStr += ` ` + strings.Join(filteredArgs, " ")
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -959,7 +1059,7 @@ var clusterHelmCommand = &cli.Command{
 		}
 		cmdStr := `sudo -u cladm -i helm ` + strings.Join(filteredArgs, " ") // + useTLS
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -1125,7 +1225,7 @@ var clusterNodeListCommand = &cli.Command{
 
 		var formatted []map[string]interface{}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -1163,7 +1263,7 @@ var clusterNodeInspectCommand = &cli.Command{
 			return clitools.FailureResponse(err)
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -1207,7 +1307,7 @@ var clusterNodeDeleteCommand = &cli.Command{
 		yes := c.Bool("yes")
 		force := c.Bool("force")
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -1256,7 +1356,7 @@ var clusterNodeStopCommand = &cli.Command{
 			return clitools.FailureResponse(err)
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -1288,7 +1388,7 @@ var clusterNodeStartCommand = &cli.Command{
 			return clitools.FailureResponse(err)
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -1319,7 +1419,7 @@ var clusterNodeStateCommand = &cli.Command{
 			return clitools.FailureResponse(err)
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -1369,7 +1469,7 @@ var clusterMasterListCommand = &cli.Command{
 
 		var formatted []map[string]interface{}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -1408,7 +1508,7 @@ var clusterMasterInspectCommand = &cli.Command{
 			return clitools.FailureResponse(err)
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -1440,7 +1540,7 @@ var clusterMasterStopCommand = &cli.Command{
 			return clitools.FailureResponse(err)
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -1472,7 +1572,7 @@ var clusterMasterStartCommand = &cli.Command{
 			return clitools.FailureResponse(err)
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -1503,7 +1603,7 @@ var clusterMasterStateCommand = &cli.Command{
 			return clitools.FailureResponse(err)
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -1523,151 +1623,923 @@ var clusterMasterStateCommand = &cli.Command{
 	},
 }
 
-const clusterFeatureCmdLabel = "feature"
+const clusterNodePoolCmdLabel = "nodepool"
 
-// clusterFeatureCommands commands
-var clusterFeatureCommands = &cli.Command{
-	Name:      clusterFeatureCmdLabel,
-	Usage:     "create and manage features on a cluster",
+// clusterNodePoolCommands handles 'safescale cluster nodepool ...'
+var clusterNodePoolCommands = &cli.Command{
+	Name:      clusterNodePoolCmdLabel,
+	Aliases:   []string{"pool"},
+	Usage:     "manage node pools of a cluster",
 	ArgsUsage: "COMMAND",
+
 	Subcommands: []*cli.Command{
-		clusterFeatureListCommand,
-		clusterFeatureCheckCommand,
-		clusterFeatureAddCommand,
-		clusterFeatureRemoveCommand,
+		clusterNodePoolListCommand,
+		clusterNodePoolAddCommand,
+		clusterNodePoolResizeCommand,
+		clusterNodePoolDeleteCommand,
 	},
 }
 
-// clusterFeatureListCommand handles 'safescale cluster <cluster name or id> list-features'
-var clusterFeatureListCommand = &cli.Command{
-	Name:      "list",
-	Aliases:   []string{"ls"},
-	Usage:     "List features installed on the cluster",
-	ArgsUsage: "",
-
-	Flags: []cli.Flag{
-		&cli.BoolFlag{
-			Name:    "all",
-			Aliases: []string{"a"},
-			Value:   false,
-			Usage:   "if used, list all features that are eligible to be installed on the cluster",
-		},
-		// &cli.StringSliceFlag{
-		// 	Name:    "param",
-		// 	Aliases: []string{"p"},
-		// 	Usage:   "Allow to define content of feature parameters",
-		// },
-	},
-
-	Action: clusterFeatureListAction,
+// extractNodePoolArgument reads the name of the node pool at position poolPos in arguments
+func extractNodePoolArgument(c *cli.Context, poolPos int) (string, error) {
+	poolName := c.Args().Get(poolPos)
+	if poolName == "" {
+		_ = cli.ShowSubcommandHelp(c)
+		return "", clitools.ExitOnInvalidArgument("Missing mandatory argument POOLNAME.")
+	}
+	return poolName, nil
 }
 
-func clusterFeatureListAction(c *cli.Context) error {
-	logrus.Tracef("SafeScale command: %s %s with args '%s'", clusterCmdLabel, c.Command.Name, c.Args())
-
-	clientSession, xerr := client.New(c.String("server"))
-	if xerr != nil {
-		return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
-	}
+// clusterNodePoolListCommand handles 'safescale cluster nodepool list CLUSTERNAME'
+var clusterNodePoolListCommand = &cli.Command{
+	Name:      "list",
+	Aliases:   []string{"ls"},
+	Usage:     "Lists the node pools of a cluster",
+	ArgsUsage: "CLUSTERNAME",
 
-	if err := extractClusterName(c); err != nil {
-		return clitools.FailureResponse(err)
-	}
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s %s with args '%s'", clusterCmdLabel, clusterNodePoolCmdLabel, c.Command.Name, c.Args())
+		err := extractClusterName(c)
+		if err != nil {
+			return clitools.FailureResponse(err)
+		}
 
-	features, err := clientSession.Cluster.ListInstalledFeatures(clusterName, c.Bool("all"), 0) // FIXME: set timeout
-	if err != nil {
-		err = fail.FromGRPCStatus(err)
-		return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
-	}
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
 
-	return clitools.SuccessResponse(features)
+		list, err := clientSession.Cluster.ListNodePools(clusterName, temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+		}
+		return clitools.SuccessResponse(list.GetPools())
+	},
 }
 
-// clusterFeatureAddCommand handles 'safescale cluster feature add CLUSTERNAME FEATURENAME'
-var clusterFeatureAddCommand = &cli.Command{
+// clusterNodePoolAddCommand handles 'safescale cluster nodepool add CLUSTERNAME POOLNAME'
+var clusterNodePoolAddCommand = &cli.Command{
 	Name:      "add",
-	Aliases:   []string{"install"},
-	Usage:     "Installs a feature on a cluster",
-	ArgsUsage: "CLUSTERNAME FEATURENAME",
+	Aliases:   []string{"create"},
+	Usage:     "Creates a node pool in a cluster, with its own sizing, image, count bounds and Kubernetes labels and taints",
+	ArgsUsage: "CLUSTERNAME POOLNAME",
 
 	Flags: []cli.Flag{
+		asyncFlag,
+		&cli.UintFlag{
+			Name:    "count",
+			Aliases: []string{"n"},
+			Usage:   "Define the number of nodes created in the pool (default: 1, or --min-count if greater)",
+			Value:   1,
+		},
+		&cli.UintFlag{
+			Name:  "min-count",
+			Usage: "Define the minimum number of nodes in the pool",
+		},
+		&cli.UintFlag{
+			Name:  "max-count",
+			Usage: "Define the maximum number of nodes in the pool (default: no limit)",
+		},
+		&cli.StringFlag{
+			Name:  "os",
+			Usage: "Define the Operating System of the nodes of the pool (default: the one of the cluster)",
+		},
+		&cli.StringFlag{
+			Name: "node-sizing",
+			Usage: `Describe node sizing in format "<component><operator><value>[,...]" where:
+	<component> can be cpu, cpufreq, gpu, ram, disk, os
+	<operator> can be =,<,> (except for disk where valid operators are only = or >)
+	<value> can be an integer (for cpu and disk) or a float (for ram) or an including interval "[<lower value>-<upper value>]"`,
+		},
 		&cli.StringSliceFlag{
-			Name:    "param",
-			Aliases: []string{"p"},
-			Usage:   "Define value of feature parameters, in format <name>=<value>",
+			Name:  "label",
+			Usage: "Kubernetes label to set on the nodes of the pool, in the form key=value (may be used several times; flavors K8S and K3S)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "taint",
+			Usage: "Kubernetes taint to set on the nodes of the pool, in the form key=value:Effect (may be used several times; flavors K8S and K3S)",
 		},
 		&cli.BoolFlag{
-			Name:  "skip-proxy",
-			Usage: "Disables reverse proxy rules",
+			Name:    "keep-on-failure",
+			Aliases: []string{"k"},
+			Usage:   `do not delete resources on failure`,
 		},
 	},
 
-	Action: clusterFeatureAddAction,
-}
-
-func clusterFeatureAddAction(c *cli.Context) error {
-	logrus.Tracef("SafeScale command: %s %s %s with args '%s'", clusterCmdLabel, clusterFeatureCmdLabel, c.Command.Name, c.Args())
-	if err := extractClusterName(c); err != nil {
-		return clitools.FailureResponse(err)
-	}
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s %s with args '%s'", clusterCmdLabel, clusterNodePoolCmdLabel, c.Command.Name, c.Args())
+		err := extractClusterName(c)
+		if err != nil {
+			return clitools.FailureResponse(err)
+		}
+		poolName, err := extractNodePoolArgument(c, 1)
+		if err != nil {
+			return clitools.FailureResponse(err)
+		}
 
-	if err := extractFeatureArgument(c); err != nil {
-		return clitools.FailureResponse(err)
-	}
+		nodesDef, err := constructHostDefinitionStringFromCLI(c, "node-sizing")
+		if err != nil {
+			return err
+		}
+		labels, err := constructLabelsFromCLI(c, false)
+		if err != nil {
+			return err
+		}
 
-	values := map[string]string{}
-	params := c.StringSlice("param")
-	for _, k := range params {
-		res := strings.Split(k, "=")
-		if len(res[0]) > 0 {
-			values[res[0]] = strings.Join(res[1:], "=")
+		count := c.Uint("count")
+		if !c.IsSet("count") && c.Uint("min-count") > count {
+			count = c.Uint("min-count")
 		}
-	}
 
-	settings := protocol.FeatureSettings{}
-	settings.SkipProxy = c.Bool("skip-proxy")
+		req := protocol.ClusterNodePoolCreateRequest{
+			Name:          clusterName,
+			PoolName:      poolName,
+			NodeSizing:    nodesDef,
+			ImageId:       c.String("os"),
+			Count:         uint32(count),
+			MinCount:      uint32(c.Uint("min-count")),
+			MaxCount:      uint32(c.Uint("max-count")),
+			Labels:        labels,
+			Taints:        c.StringSlice("taint"),
+			KeepOnFailure: c.Bool("keep-on-failure"),
+		}
 
-	clientSession, xerr := client.New(c.String("server"))
-	if xerr != nil {
-		return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
-	}
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
 
-	if err := clientSession.Cluster.AddFeature(clusterName, featureName, values, &settings, 0); err != nil {
-		err = fail.FromGRPCStatus(err)
-		msg := fmt.Sprintf("error adding feature '%s' on cluster '%s': %s", featureName, clusterName, err.Error())
-		return clitools.FailureResponse(clitools.ExitOnRPC(msg))
-	}
-	return clitools.SuccessResponse(nil)
+		pool, err := clientSession.Cluster.AddNodePool(&req, temporal.GetLongOperationTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+		}
+		if c.Bool("async") {
+			return asyncResponse(clientSession)
+		}
+		return clitools.SuccessResponse(pool)
+	},
 }
 
-// clusterFeatureCheckCommand handles 'deploy cluster check-feature CLUSTERNAME FEATURENAME'
-var clusterFeatureCheckCommand = &cli.Command{
-	Name:      "check",
-	Aliases:   []string{"verify"},
-	Usage:     "Checks if a eature is already installed on cluster",
-	ArgsUsage: "CLUSTERNAME FEATURENAME",
+// clusterNodePoolResizeCommand handles 'safescale cluster nodepool resize CLUSTERNAME POOLNAME'
+var clusterNodePoolResizeCommand = &cli.Command{
+	Name:      "resize",
+	Aliases:   []string{"scale"},
+	Usage:     "Adds or removes nodes of a node pool to reach the requested count (nodes are removed from the last created)",
+	ArgsUsage: "CLUSTERNAME POOLNAME",
+
 	Flags: []cli.Flag{
-		&cli.StringSliceFlag{
-			Name:    "param",
-			Aliases: []string{"p"},
-			Usage:   "Allow to define content of feature parameters",
+		asyncFlag,
+		&cli.UintFlag{
+			Name:     "count",
+			Aliases:  []string{"n"},
+			Usage:    "Define the number of nodes wanted in the pool",
+			Required: true,
+		},
+		&cli.BoolFlag{
+			Name:    "assume-yes",
+			Aliases: []string{"yes", "y"},
+			Usage:   "Don't ask confirmation when nodes have to be deleted",
+		},
+		&cli.BoolFlag{
+			Name:    "keep-on-failure",
+			Aliases: []string{"k"},
+			Usage:   `do not delete resources on failure`,
 		},
 	},
-	Action: clusterFeatureCheckAction,
-}
-
-func clusterFeatureCheckAction(c *cli.Context) error {
-	logrus.Tracef("SafeScale command: %s %s %s with args '%s'", clusterCmdLabel, clusterFeatureCmdLabel, c.Command.Name, c.Args())
 
-	if err := extractClusterName(c); err != nil {
-		return clitools.FailureResponse(err)
-	}
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s %s with args '%s'", clusterCmdLabel, clusterNodePoolCmdLabel, c.Command.Name, c.Args())
+		err := extractClusterName(c)
+		if err != nil {
+			return clitools.FailureResponse(err)
+		}
+		poolName, err := extractNodePoolArgument(c, 1)
+		if err != nil {
+			return clitools.FailureResponse(err)
+		}
 
-	if err := extractFeatureArgument(c); err != nil {
-		return clitools.FailureResponse(err)
-	}
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
 
-	values := map[string]string{}
+		count := c.Uint("count")
+		if !c.Bool("assume-yes") {
+			list, err := clientSession.Cluster.ListNodePools(clusterName, temporal.GetExecutionTimeout())
+			if err != nil {
+				err = fail.FromGRPCStatus(err)
+				return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+			}
+			for _, v := range list.GetPools() {
+				if current := uint(len(v.GetNodes())); v.GetName() == poolName && current > count {
+					msg := fmt.Sprintf("Are you sure you want to delete %d node%s of node pool '%s' of Cluster %s", current-count, strprocess.Plural(current-count), poolName, clusterName)
+					if !utils.UserConfirmed(msg) {
+						return clitools.SuccessResponse("Aborted")
+					}
+				}
+			}
+		}
+
+		req := protocol.ClusterNodePoolRequest{
+			Name:          clusterName,
+			PoolName:      poolName,
+			Count:         uint32(count),
+			KeepOnFailure: c.Bool("keep-on-failure"),
+		}
+		pool, err := clientSession.Cluster.ResizeNodePool(&req, temporal.GetLongOperationTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+		}
+		if c.Bool("async") {
+			return asyncResponse(clientSession)
+		}
+		return clitools.SuccessResponse(pool)
+	},
+}
+
+// clusterNodePoolDeleteCommand handles 'safescale cluster nodepool delete CLUSTERNAME POOLNAME'
+var clusterNodePoolDeleteCommand = &cli.Command{
+	Name:      "delete",
+	Aliases:   []string{"destroy", "remove", "rm"},
+	Usage:     "Deletes a node pool of a cluster, with all its nodes",
+	ArgsUsage: "CLUSTERNAME POOLNAME",
+
+	Flags: []cli.Flag{
+		asyncFlag,
+		&cli.BoolFlag{
+			Name:    "assume-yes",
+			Aliases: []string{"yes", "y"},
+			Usage:   "Don't ask deletion confirmation",
+		},
+	},
+
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s %s with args '%s'", clusterCmdLabel, clusterNodePoolCmdLabel, c.Command.Name, c.Args())
+		err := extractClusterName(c)
+		if err != nil {
+			return clitools.FailureResponse(err)
+		}
+		poolName, err := extractNodePoolArgument(c, 1)
+		if err != nil {
+			return clitools.FailureResponse(err)
+		}
+
+		if !c.Bool("assume-yes") && !utils.UserConfirmed(fmt.Sprintf("Are you sure you want to delete node pool '%s' of Cluster %s, with all its nodes", poolName, clusterName)) {
+			return clitools.SuccessResponse("Aborted")
+		}
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		err = clientSession.Cluster.DeleteNodePool(clusterName, poolName, temporal.GetLongOperationTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+		}
+		if c.Bool("async") {
+			return asyncResponse(clientSession)
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
+
+const clusterAutoscalingCmdLabel = "autoscaling"
+
+// clusterAutoscalingCommands handles 'safescale cluster autoscaling ...'
+var clusterAutoscalingCommands = &cli.Command{
+	Name:      clusterAutoscalingCmdLabel,
+	Aliases:   []string{"autoscale"},
+	Usage:     "manage the automatic expansion and shrink of a cluster by safescaled, depending on the load of its nodes",
+	ArgsUsage: "COMMAND",
+
+	Subcommands: []*cli.Command{
+		clusterAutoscalingShowCommand,
+		clusterAutoscalingSetCommand,
+		clusterAutoscalingDisableCommand,
+	},
+}
+
+// clusterAutoscalingShowCommand handles 'safescale cluster autoscaling show CLUSTERNAME'
+var clusterAutoscalingShowCommand = &cli.Command{
+	Name:      "show",
+	Aliases:   []string{"inspect"},
+	Usage:     "Shows the autoscaling settings of a cluster and its last scale decisions",
+	ArgsUsage: "CLUSTERNAME",
+
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s %s with args '%s'", clusterCmdLabel, clusterAutoscalingCmdLabel, c.Command.Name, c.Args())
+		err := extractClusterName(c)
+		if err != nil {
+			return clitools.FailureResponse(err)
+		}
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		settings, err := clientSession.Cluster.InspectAutoscaling(clusterName, temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+		}
+		return clitools.SuccessResponse(settings)
+	},
+}
+
+// clusterAutoscalingSetCommand handles 'safescale cluster autoscaling set CLUSTERNAME'
+var clusterAutoscalingSetCommand = &cli.Command{
+	Name:      "set",
+	Aliases:   []string{"enable"},
+	Usage:     "Enables autoscaling of the general nodes of a cluster (the nodes not belonging to a node pool) with the given settings",
+	ArgsUsage: "CLUSTERNAME",
+
+	Flags: []cli.Flag{
+		&cli.UintFlag{
+			Name:     "min",
+			Usage:    "Define the minimum number of nodes",
+			Required: true,
+		},
+		&cli.UintFlag{
+			Name:     "max",
+			Usage:    "Define the maximum number of nodes",
+			Required: true,
+		},
+		&cli.Float64Flag{
+			Name:  "scale-up",
+			Usage: "Define the load of the nodes (in percent of CPU or memory) above which nodes are added",
+			Value: 80,
+		},
+		&cli.Float64Flag{
+			Name:  "scale-down",
+			Usage: "Define the load of the nodes (in percent of CPU or memory) under which nodes are removed",
+			Value: 30,
+		},
+		&cli.DurationFlag{
+			Name:  "cooldown",
+			Usage: "Define the minimum delay between 2 scale actions",
+			Value: 5 * time.Minute,
+		},
+		&cli.UintFlag{
+			Name:  "step",
+			Usage: "Define the number of nodes added or removed by a scale action",
+			Value: 1,
+		},
+	},
+
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s %s with args '%s'", clusterCmdLabel, clusterAutoscalingCmdLabel, c.Command.Name, c.Args())
+		err := extractClusterName(c)
+		if err != nil {
+			return clitools.FailureResponse(err)
+		}
+
+		req := protocol.ClusterAutoscaling{
+			Name:               clusterName,
+			Enabled:            true,
+			MinNodes:           uint32(c.Uint("min")),
+			MaxNodes:           uint32(c.Uint("max")),
+			ScaleUpThreshold:   c.Float64("scale-up"),
+			ScaleDownThreshold: c.Float64("scale-down"),
+			Cooldown:           uint32(c.Duration("cooldown") / time.Second),
+			Step:               uint32(c.Uint("step")),
+		}
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		settings, err := clientSession.Cluster.SetAutoscaling(&req, temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+		}
+		return clitools.SuccessResponse(settings)
+	},
+}
+
+// clusterAutoscalingDisableCommand handles 'safescale cluster autoscaling disable CLUSTERNAME'
+var clusterAutoscalingDisableCommand = &cli.Command{
+	Name:      "disable",
+	Usage:     "Disables autoscaling of a cluster, keeping its settings",
+	ArgsUsage: "CLUSTERNAME",
+
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s %s with args '%s'", clusterCmdLabel, clusterAutoscalingCmdLabel, c.Command.Name, c.Args())
+		err := extractClusterName(c)
+		if err != nil {
+			return clitools.FailureResponse(err)
+		}
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		settings, err := clientSession.Cluster.InspectAutoscaling(clusterName, temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+		}
+
+		settings.Enabled = false
+		settings, err = clientSession.Cluster.SetAutoscaling(settings, temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+		}
+		return clitools.SuccessResponse(settings)
+	},
+}
+
+const clusterHealthCmdLabel = "health"
+
+// clusterHealthCommands handles 'safescale cluster health ...'
+var clusterHealthCommands = &cli.Command{
+	Name:      clusterHealthCmdLabel,
+	Usage:     "manage the health checks of the masters and nodes of a cluster done by safescaled, and the replacement of failed nodes",
+	ArgsUsage: "COMMAND",
+
+	Subcommands: []*cli.Command{
+		clusterHealthShowCommand,
+		clusterHealthCheckCommand,
+		clusterHealthSetCommand,
+	},
+}
+
+// clusterHealthShowCommand handles 'safescale cluster health show CLUSTERNAME'
+var clusterHealthShowCommand = &cli.Command{
+	Name:      "show",
+	Aliases:   []string{"inspect"},
+	Usage:     "Shows the health of the masters and nodes of a cluster as of the last check, the health check settings and the last repairs",
+	ArgsUsage: "CLUSTERNAME",
+
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s %s with args '%s'", clusterCmdLabel, clusterHealthCmdLabel, c.Command.Name, c.Args())
+		err := extractClusterName(c)
+		if err != nil {
+			return clitools.FailureResponse(err)
+		}
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		health, err := clientSession.Cluster.InspectHealth(clusterName, temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+		}
+		return clitools.SuccessResponse(health)
+	},
+}
+
+// clusterHealthCheckCommand handles 'safescale cluster health check CLUSTERNAME'
+var clusterHealthCheckCommand = &cli.Command{
+	Name:      "check",
+	Usage:     "Checks now the masters and nodes of a cluster; failed nodes are replaced if auto repair is enabled",
+	ArgsUsage: "CLUSTERNAME",
+
+	Flags: []cli.Flag{
+		asyncFlag,
+	},
+
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s %s with args '%s'", clusterCmdLabel, clusterHealthCmdLabel, c.Command.Name, c.Args())
+		err := extractClusterName(c)
+		if err != nil {
+			return clitools.FailureResponse(err)
+		}
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		health, err := clientSession.Cluster.CheckHealth(clusterName, temporal.GetLongOperationTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+		}
+		if c.Bool("async") {
+			return asyncResponse(clientSession)
+		}
+		return clitools.SuccessResponse(health)
+	},
+}
+
+// clusterHealthSetCommand handles 'safescale cluster health set CLUSTERNAME'
+var clusterHealthSetCommand = &cli.Command{
+	Name:      "set",
+	Usage:     "Changes the health check settings of a cluster; settings not given are kept",
+	ArgsUsage: "CLUSTERNAME",
+
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "auto-repair",
+			Usage: "Enable (--auto-repair) or disable (--auto-repair=false) the replacement of failed nodes",
+		},
+		&cli.UintFlag{
+			Name:  "threshold",
+			Usage: "Define the number of consecutive failed checks after which a node is considered as failed",
+		},
+	},
+
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s %s with args '%s'", clusterCmdLabel, clusterHealthCmdLabel, c.Command.Name, c.Args())
+		err := extractClusterName(c)
+		if err != nil {
+			return clitools.FailureResponse(err)
+		}
+		if !c.IsSet("auto-repair") && !c.IsSet("threshold") {
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory option --auto-repair or --threshold."))
+		}
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		health, err := clientSession.Cluster.InspectHealth(clusterName, temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+		}
+
+		req := protocol.ClusterHealth{
+			Name:             clusterName,
+			AutoRepair:       health.GetAutoRepair(),
+			FailureThreshold: health.GetFailureThreshold(),
+		}
+		if c.IsSet("auto-repair") {
+			req.AutoRepair = c.Bool("auto-repair")
+		}
+		if c.IsSet("threshold") {
+			req.FailureThreshold = uint32(c.Uint("threshold"))
+		}
+		health, err = clientSession.Cluster.SetHealth(&req, temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+		}
+		return clitools.SuccessResponse(health)
+	},
+}
+
+// clusterUpgradeCommand handles 'safescale cluster upgrade CLUSTERNAME'
+var clusterUpgradeCommand = &cli.Command{
+	Name:      "upgrade",
+	Usage:     "Upgrades the hosts of a cluster one batch after the other (masters first), applying updates of the operating system or installing again a feature; stops on first failure",
+	ArgsUsage: "CLUSTERNAME",
+
+	Flags: []cli.Flag{
+		asyncFlag,
+		&cli.StringFlag{
+			Name:    "feature",
+			Aliases: []string{"f"},
+			Usage:   "Define the feature to install again on each host instead of the updates of the operating system",
+		},
+		&cli.StringSliceFlag{
+			Name:    "param",
+			Aliases: []string{"p"},
+			Usage:   "Allow to define content of feature parameters",
+		},
+		&cli.UintFlag{
+			Name:  "parallelism",
+			Usage: "Define the maximum number of nodes upgraded at the same time",
+			Value: 1,
+		},
+		&cli.BoolFlag{
+			Name:  "skip-masters",
+			Usage: "Upgrades only the nodes",
+		},
+		&cli.BoolFlag{
+			Name:  "resume",
+			Usage: "Resumes the last upgrade from the first host not yet upgraded",
+		},
+		&cli.BoolFlag{
+			Name:  "status",
+			Usage: "Shows the progress of the last upgrade instead of starting one",
+		},
+	},
+
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", clusterCmdLabel, c.Command.Name, c.Args())
+		err := extractClusterName(c)
+		if err != nil {
+			return clitools.FailureResponse(err)
+		}
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		if c.Bool("status") {
+			upgrade, err := clientSession.Cluster.InspectUpgrade(clusterName, temporal.GetExecutionTimeout())
+			if err != nil {
+				err = fail.FromGRPCStatus(err)
+				return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+			}
+			return clitools.SuccessResponse(upgrade)
+		}
+
+		values := map[string]string{}
+		for _, k := range c.StringSlice("param") {
+			res := strings.Split(k, "=")
+			if len(res[0]) > 0 {
+				values[res[0]] = strings.Join(res[1:], "=")
+			}
+		}
+
+		req := protocol.ClusterUpgradeRequest{
+			Name:        clusterName,
+			Feature:     c.String("feature"),
+			Params:      values,
+			Parallelism: uint32(c.Uint("parallelism")),
+			SkipMasters: c.Bool("skip-masters"),
+			Resume:      c.Bool("resume"),
+		}
+		upgrade, err := clientSession.Cluster.Upgrade(&req, temporal.GetLongOperationTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+		}
+		if c.Bool("async") {
+			return asyncResponse(clientSession)
+		}
+		return clitools.SuccessResponse(upgrade)
+	},
+}
+
+// clusterBackupCommand handles 'safescale cluster backup CLUSTERNAME'
+var clusterBackupCommand = &cli.Command{
+	Name:      "backup",
+	Aliases:   []string{"save"},
+	Usage:     "Saves in a bucket the control plane of a cluster: metadata, parameters of the installed features, certificates and, for K8S flavor, etcd",
+	ArgsUsage: "CLUSTERNAME",
+
+	Flags: []cli.Flag{
+		asyncFlag,
+		&cli.StringFlag{
+			Name:  "bucket",
+			Usage: "Define the bucket where the backup is saved (created if needed; default: <cluster name>-backups)",
+		},
+		&cli.BoolFlag{
+			Name:  "list",
+			Usage: "Lists the backups of the cluster instead of saving a new one",
+		},
+	},
+
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", clusterCmdLabel, c.Command.Name, c.Args())
+		err := extractClusterName(c)
+		if err != nil {
+			return clitools.FailureResponse(err)
+		}
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		if c.Bool("list") {
+			list, err := clientSession.Cluster.ListBackups(clusterName, temporal.GetExecutionTimeout())
+			if err != nil {
+				err = fail.FromGRPCStatus(err)
+				return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+			}
+			return clitools.SuccessResponse(list)
+		}
+
+		req := protocol.ClusterBackupRequest{
+			Name:   clusterName,
+			Bucket: c.String("bucket"),
+		}
+		backup, err := clientSession.Cluster.Backup(&req, temporal.GetLongOperationTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+		}
+		if c.Bool("async") {
+			return asyncResponse(clientSession)
+		}
+		return clitools.SuccessResponse(backup)
+	},
+}
+
+// clusterRestoreCommand handles 'safescale cluster restore CLUSTERNAME'
+var clusterRestoreCommand = &cli.Command{
+	Name:      "restore",
+	Usage:     "Rebuilds the control plane of a cluster from a backup: lost masters are created again, then certificates, features and, for K8S flavor, etcd are restored",
+	ArgsUsage: "CLUSTERNAME",
+
+	Flags: []cli.Flag{
+		asyncFlag,
+		&cli.StringFlag{
+			Name:  "backup",
+			Usage: "Define the id of the backup to restore (default: the newest backup recorded)",
+		},
+		&cli.StringFlag{
+			Name:  "bucket",
+			Usage: "Define the bucket containing the backup, if it is not recorded in the metadata of the cluster (default: <cluster name>-backups)",
+		},
+		&cli.BoolFlag{
+			Name:    "assume-yes",
+			Aliases: []string{"yes", "y"},
+			Usage:   "Don't ask restoration confirmation",
+		},
+	},
+
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", clusterCmdLabel, c.Command.Name, c.Args())
+		err := extractClusterName(c)
+		if err != nil {
+			return clitools.FailureResponse(err)
+		}
+
+		if !c.Bool("assume-yes") {
+			msg := fmt.Sprintf("Are you sure you want to restore the control plane of Cluster '%s' (its current state will be lost)", clusterName)
+			if !utils.UserConfirmed(msg) {
+				return clitools.SuccessResponse("Aborted")
+			}
+		}
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		req := protocol.ClusterBackupRequest{
+			Name:   clusterName,
+			Bucket: c.String("bucket"),
+			Id:     c.String("backup"),
+		}
+		backup, err := clientSession.Cluster.Restore(&req, temporal.GetLongOperationTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+		}
+		if c.Bool("async") {
+			return asyncResponse(clientSession)
+		}
+		return clitools.SuccessResponse(backup)
+	},
+}
+
+const clusterFeatureCmdLabel = "feature"
+
+// clusterFeatureCommands commands
+var clusterFeatureCommands = &cli.Command{
+	Name:      clusterFeatureCmdLabel,
+	Usage:     "create and manage features on a cluster",
+	ArgsUsage: "COMMAND",
+	Subcommands: []*cli.Command{
+		clusterFeatureListCommand,
+		clusterFeatureCheckCommand,
+		clusterFeatureAddCommand,
+		clusterFeatureRemoveCommand,
+		clusterFeatureUpgradeCommand,
+	},
+}
+
+// clusterFeatureListCommand handles 'safescale cluster <cluster name or id> list-features'
+var clusterFeatureListCommand = &cli.Command{
+	Name:      "list",
+	Aliases:   []string{"ls"},
+	Usage:     "List features installed on the cluster",
+	ArgsUsage: "",
+
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:    "all",
+			Aliases: []string{"a"},
+			Value:   false,
+			Usage:   "if used, list all features that are eligible to be installed on the cluster",
+		},
+		// &cli.StringSliceFlag{
+		// 	Name:    "param",
+		// 	Aliases: []string{"p"},
+		// 	Usage:   "Allow to define content of feature parameters",
+		// },
+	},
+
+	Action: clusterFeatureListAction,
+}
+
+func clusterFeatureListAction(c *cli.Context) error {
+	logrus.Tracef("SafeScale command: %s %s with args '%s'", clusterCmdLabel, c.Command.Name, c.Args())
+
+	clientSession, xerr := newClientSession(c)
+	if xerr != nil {
+		return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+	}
+
+	if err := extractClusterName(c); err != nil {
+		return clitools.FailureResponse(err)
+	}
+
+	features, err := clientSession.Cluster.ListInstalledFeatures(clusterName, c.Bool("all"), 0) // FIXME: set timeout
+	if err != nil {
+		err = fail.FromGRPCStatus(err)
+		return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+	}
+
+	return clitools.SuccessResponse(features)
+}
+
+// clusterFeatureAddCommand handles 'safescale cluster feature add CLUSTERNAME FEATURENAME'
+var clusterFeatureAddCommand = &cli.Command{
+	Name:      "add",
+	Aliases:   []string{"install"},
+	Usage:     "Installs a feature on a cluster",
+	ArgsUsage: "CLUSTERNAME FEATURENAME",
+
+	Flags: []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:    "param",
+			Aliases: []string{"p"},
+			Usage:   "Define value of feature parameters, in format <name>=<value>",
+		},
+		&cli.BoolFlag{
+			Name:  "skip-proxy",
+			Usage: "Disables reverse proxy rules",
+		},
+	},
+
+	Action: clusterFeatureAddAction,
+}
+
+func clusterFeatureAddAction(c *cli.Context) error {
+	logrus.Tracef("SafeScale command: %s %s %s with args '%s'", clusterCmdLabel, clusterFeatureCmdLabel, c.Command.Name, c.Args())
+	if err := extractClusterName(c); err != nil {
+		return clitools.FailureResponse(err)
+	}
+
+	if err := extractFeatureArgument(c); err != nil {
+		return clitools.FailureResponse(err)
+	}
+
+	values := map[string]string{}
+	params := c.StringSlice("param")
+	for _, k := range params {
+		res := strings.Split(k, "=")
+		if len(res[0]) > 0 {
+			values[res[0]] = strings.Join(res[1:], "=")
+		}
+	}
+
+	settings := protocol.FeatureSettings{}
+	settings.SkipProxy = c.Bool("skip-proxy")
+
+	clientSession, xerr := newClientSession(c)
+	if xerr != nil {
+		return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+	}
+
+	if err := clientSession.Cluster.AddFeature(clusterName, featureName, values, &settings, 0); err != nil {
+		err = fail.FromGRPCStatus(err)
+		msg := fmt.Sprintf("error adding feature '%s' on cluster '%s': %s", featureName, clusterName, err.Error())
+		return clitools.FailureResponse(clitools.ExitOnRPC(msg))
+	}
+	return clitools.SuccessResponse(nil)
+}
+
+// clusterFeatureCheckCommand handles 'deploy cluster check-feature CLUSTERNAME FEATURENAME'
+var clusterFeatureCheckCommand = &cli.Command{
+	Name:      "check",
+	Aliases:   []string{"verify"},
+	Usage:     "Checks if a eature is already installed on cluster",
+	ArgsUsage: "CLUSTERNAME FEATURENAME",
+	Flags: []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:    "param",
+			Aliases: []string{"p"},
+			Usage:   "Allow to define content of feature parameters",
+		},
+	},
+	Action: clusterFeatureCheckAction,
+}
+
+func clusterFeatureCheckAction(c *cli.Context) error {
+	logrus.Tracef("SafeScale command: %s %s %s with args '%s'", clusterCmdLabel, clusterFeatureCmdLabel, c.Command.Name, c.Args())
+
+	if err := extractClusterName(c); err != nil {
+		return clitools.FailureResponse(err)
+	}
+
+	if err := extractFeatureArgument(c); err != nil {
+		return clitools.FailureResponse(err)
+	}
+
+	values := map[string]string{}
 	params := c.StringSlice("param")
 	for _, k := range params {
 		res := strings.Split(k, "=")
@@ -1678,7 +2550,7 @@ func clusterFeatureCheckAction(c *cli.Context) error {
 
 	settings := protocol.FeatureSettings{}
 
-	clientSession, xerr := client.New(c.String("server"))
+	clientSession, xerr := newClientSession(c)
 	if xerr != nil {
 		return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 	}
@@ -1733,7 +2605,7 @@ func clusterFeatureRemoveAction(c *cli.Context) error {
 	// will try to apply them... Quick fix: Setting SkipProxy to true prevent this
 	settings.SkipProxy = true
 
-	clientSession, xerr := client.New(c.String("server"))
+	clientSession, xerr := newClientSession(c)
 	if xerr != nil {
 		return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 	}
@@ -1745,3 +2617,189 @@ func clusterFeatureRemoveAction(c *cli.Context) error {
 	}
 	return clitools.SuccessResponse(nil)
 }
+
+// clusterFeatureUpgradeCommand handles 'safescale cluster feature upgrade CLUSTERNAME FEATURENAME'
+var clusterFeatureUpgradeCommand = &cli.Command{
+	Name:      "upgrade",
+	Aliases:   []string{"update"},
+	Usage:     "Upgrades a feature installed on a cluster to the version of its specification file",
+	ArgsUsage: "CLUSTERNAME FEATURENAME",
+	Flags: []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:    "param",
+			Aliases: []string{"p"},
+			Usage:   "Allow to define content of feature parameters",
+		},
+		&cli.BoolFlag{
+			Name:  "skip-requirements",
+			Usage: "Do not install or upgrade the features required by the feature",
+		},
+	},
+	Action: clusterFeatureUpgradeAction,
+}
+
+func clusterFeatureUpgradeAction(c *cli.Context) error {
+	logrus.Tracef("SafeScale command: %s %s %s with args '%s'", clusterCmdLabel, clusterFeatureCmdLabel, c.Command.Name, c.Args())
+	if err := extractClusterName(c); err != nil {
+		return clitools.FailureResponse(err)
+	}
+
+	if err := extractFeatureArgument(c); err != nil {
+		return clitools.FailureResponse(err)
+	}
+
+	values := map[string]string{}
+	params := c.StringSlice("param")
+	for _, k := range params {
+		res := strings.Split(k, "=")
+		if len(res[0]) > 0 {
+			values[res[0]] = strings.Join(res[1:], "=")
+		}
+	}
+
+	settings := protocol.FeatureSettings{}
+	settings.IgnoreFeatureRequirements = c.Bool("skip-requirements")
+
+	clientSession, xerr := newClientSession(c)
+	if xerr != nil {
+		return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+	}
+
+	if err := clientSession.Cluster.UpgradeFeature(clusterName, featureName, values, &settings, 0); err != nil {
+		err = fail.FromGRPCStatus(err)
+		msg := fmt.Sprintf("failed to upgrade Feature '%s' on Cluster '%s': %s", featureName, clusterName, err.Error())
+		return clitools.FailureResponse(clitools.ExitOnRPC(msg))
+	}
+	return clitools.SuccessResponse(nil)
+}
+
+// clusterKubeconfigCommand handles 'safescale cluster kubeconfig CLUSTERNAME'
+var clusterKubeconfigCommand = &cli.Command{
+	Name:      "kubeconfig",
+	Category:  "Administrative commands",
+	Usage:     "Exports the kubeconfig of the admin of a Kubernetes cluster, to use kubectl, helm, ... from this workstation",
+	ArgsUsage: "CLUSTERNAME",
+
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:    "output",
+			Aliases: []string{"o"},
+			Usage:   "Writes the kubeconfig in this file instead of displaying it",
+		},
+		&cli.StringFlag{
+			Name:  "server",
+			Usage: "URL of the API server to set in the kubeconfig (default: https://<public endpoint of the cluster>:6443)",
+		},
+		&cli.BoolFlag{
+			Name:  "tunnel",
+			Usage: "Reaches the API server through a SSH tunnel via the primary gateway, kept open until the command is stopped",
+		},
+		&cli.IntFlag{
+			Name:  "local-port",
+			Value: 0,
+			Usage: "With --tunnel, local port of the tunnel (0 to use any free port)",
+		},
+	},
+
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", clusterCmdLabel, c.Command.Name, c.Args())
+		err := extractClusterName(c)
+		if err != nil {
+			return clitools.FailureResponse(err)
+		}
+
+		tunnel := c.Bool("tunnel")
+		server := c.String("server")
+		localPort := c.Int("local-port")
+		if tunnel {
+			if server != "" {
+				return clitools.FailureResponse(clitools.ExitOnInvalidArgument("--server and --tunnel are mutually exclusive"))
+			}
+			if localPort < 0 || localPort > 65535 {
+				return clitools.FailureResponse(clitools.ExitOnInvalidOption(fmt.Sprintf("local port value is wrong, %d is not a valid port", localPort)))
+			}
+			if localPort == 0 {
+				localPort, err = freeLocalPort()
+				if err != nil {
+					return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, err.Error()))
+				}
+			}
+			server = fmt.Sprintf("https://127.0.0.1:%d", localPort)
+		}
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		kc, err := clientSession.Cluster.Kubeconfig(clusterName, server, temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+		}
+
+		var done <-chan error
+		if tunnel {
+			done, err = startKubeconfigTunnel(clientSession, kc, localPort)
+			if err != nil {
+				return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, fmt.Sprintf("failed to open tunnel to API server of cluster '%s': %v", clusterName, err)))
+			}
+		}
+
+		output := c.String("output")
+		if output != "" {
+			err = os.WriteFile(output, []byte(kc.GetContent()), 0600)
+			if err != nil {
+				return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, fmt.Sprintf("failed to write kubeconfig in '%s': %v", output, err)))
+			}
+			if !tunnel {
+				return clitools.SuccessResponse(map[string]string{"file": output, "server": kc.GetServer()})
+			}
+		} else {
+			fmt.Print(kc.GetContent())
+		}
+		if !tunnel {
+			return nil
+		}
+
+		// the tunnel is reopened each time it is closed, until the command is interrupted
+		fmt.Fprintf(os.Stderr, "API server of cluster '%s' reachable at %s through gateway '%s'; stop with Ctrl+C\n", clusterName, kc.GetServer(), kc.GetGateway())
+		for {
+			err = <-done
+			logrus.Warnf("tunnel to API server of cluster '%s' closed (%v), reopening it", clusterName, err)
+			time.Sleep(temporal.GetMinDelay())
+			done, err = startKubeconfigTunnel(clientSession, kc, localPort)
+			if err != nil {
+				return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, fmt.Sprintf("failed to reopen tunnel to API server of cluster '%s': %v", clusterName, err)))
+			}
+		}
+	},
+}
+
+// freeLocalPort returns a TCP port currently free on loopback
+func freeLocalPort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = listener.Close() }()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+// startKubeconfigTunnel starts the tunnel to the API server described by 'kc' on local port 'localPort', and returns
+// once the tunnel accepts connections a channel receiving the end of the tunnel
+func startKubeconfigTunnel(clientSession *client.Session, kc *protocol.ClusterKubeconfig, localPort int) (<-chan error, error) {
+	tunnel, err := clientSession.Cluster.KubeconfigTunnel(kc, fmt.Sprintf("127.0.0.1:%d", localPort))
+	if err != nil {
+		return nil, err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- tunnel.Start()
+	}()
+	if !<-tunnel.Ready() {
+		return nil, <-done
+	}
+	return done, nil
+}
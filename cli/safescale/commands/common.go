@@ -21,7 +21,6 @@ import (
 
 	"github.com/urfave/cli/v2"
 
-	"github.com/CS-SI/SafeScale/lib/client"
 	"github.com/CS-SI/SafeScale/lib/protocol"
 	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
 	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/exitcode"
@@ -58,7 +57,7 @@ func extractHostArgument(c *cli.Context, hostnamePos int) error {
 		return clitools.ExitOnInvalidArgument("argument HOSTNAME invalid")
 	}
 
-	clientSession, xerr := client.New(c.String("server"))
+	clientSession, xerr := newClientSession(c)
 	if xerr != nil {
 		return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 	}
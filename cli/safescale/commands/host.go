@@ -19,6 +19,7 @@ package commands
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"strings"
 
 	"github.com/CS-SI/SafeScale/lib/server/resources/operations/converters"
@@ -46,6 +47,7 @@ var HostCommand = &cli.Command{
 	Subcommands: []*cli.Command{
 		hostList,
 		hostCreate,
+		hostImport,
 		//		hostResize,
 		hostDelete,
 		hostInspect,
@@ -74,7 +76,7 @@ var hostStart = &cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Host_name>."))
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -100,7 +102,7 @@ var hostStop = &cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Host_name>."))
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -126,7 +128,7 @@ var hostReboot = &cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Host_name>."))
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -150,16 +152,25 @@ var hostList = &cli.Command{
 			Name:    "all",
 			Aliases: []string{"a"},
 			Usage:   "ErrorList all hosts on tenant (not only those created by SafeScale)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "label",
+			Usage: "Lists only hosts having this label, in the form key=value or key (may be used several times)",
 		}},
 	Action: func(c *cli.Context) error {
 		logrus.Tracef("SafeScale command: %s %s with args '%s'", hostCmdLabel, c.Command.Name, c.Args())
 
-		clientSession, xerr := client.New(c.String("server"))
+		labels, err := constructLabelsFromCLI(c, true)
+		if err != nil {
+			return err
+		}
+
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
 
-		hosts, err := clientSession.Host.List(c.Bool("all"), temporal.GetExecutionTimeout())
+		hosts, err := clientSession.Host.List(c.Bool("all"), labels, temporal.GetExecutionTimeout())
 		if err != nil {
 			err = fail.FromGRPCStatus(err)
 			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "list of hosts", false).Error())))
@@ -192,7 +203,7 @@ var hostInspect = &cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Host_name>."))
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -218,7 +229,7 @@ var hostStatus = &cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Host_name>."))
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -243,6 +254,7 @@ var hostCreate = &cli.Command{
 	Usage:     "create a new host",
 	ArgsUsage: "<Host_name>",
 	Flags: []cli.Flag{
+		asyncFlag,
 		&cli.StringFlag{
 			Name:    "network",
 			Aliases: []string{"net"},
@@ -304,6 +316,10 @@ May be used multiple times, the first occurrence becoming the default subnet by
 				--sizing "cpu ~ 4, ram = [14-32]" (is identical to --sizing "cpu=[4-8], ram=[14-32]")
 				--sizing "cpu <= 8, ram ~ 16"`,
 		},
+		&cli.StringSliceFlag{
+			Name:  "label",
+			Usage: "Label to set on the host, in the form key=value (may be used several times)",
+		},
 	},
 	Action: func(c *cli.Context) error {
 		logrus.Tracef("SafeScale command: %s %s with args '%v", hostCmdLabel, c.Command.Name, c.Args())
@@ -317,7 +333,12 @@ May be used multiple times, the first occurrence becoming the default subnet by
 			return err
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		labels, err := constructLabelsFromCLI(c, false)
+		if err != nil {
+			return err
+		}
+
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -331,12 +352,91 @@ May be used multiple times, the first occurrence becoming the default subnet by
 			Force:          c.Bool("force"),
 			SizingAsString: sizing,
 			KeepOnFailure:  c.Bool("keep-on-failure"),
+			Labels:         labels,
 		}
 		resp, err := clientSession.Host.Create(&req, temporal.GetExecutionTimeout())
 		if err != nil {
 			err = fail.FromGRPCStatus(err)
 			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "creation of host", true).Error())))
 		}
+		if c.Bool("async") {
+			return asyncResponse(clientSession)
+		}
+		return clitools.SuccessResponse(resp)
+	},
+}
+
+var hostImport = &cli.Command{
+	Name:      "import",
+	Aliases:   []string{"adopt"},
+	Usage:     "registers in SafeScale an existing host of the provider",
+	ArgsUsage: "<Provider_host_id>",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "ssh-user",
+			Usage: "User to use to connect to the host (default: the operator user of SafeScale)",
+		},
+		&cli.StringFlag{
+			Name:  "ssh-key-file",
+			Usage: "File containing the private key to use to connect to the host",
+		},
+		&cli.UintFlag{
+			Name:  "ssh-port",
+			Value: 22,
+			Usage: "Port of the SSH service of the host",
+		},
+		&cli.BoolFlag{
+			Name:  "inject-key",
+			Usage: "Injects the SSH key of SafeScale in the host, connecting with --ssh-user and --ssh-key-file",
+		},
+		&cli.BoolFlag{
+			Name:  "install-binaries",
+			Usage: "Installs the feature safescale-binaries on the host",
+		},
+		&cli.StringSliceFlag{
+			Name:    "security-group",
+			Aliases: []string{"sg"},
+			Usage:   "Security Group to bind to the host once imported (can be used several times)",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", hostCmdLabel, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Provider_host_id>."))
+		}
+
+		var privateKey string
+		if keyFile := c.String("ssh-key-file"); keyFile != "" {
+			content, err := ioutil.ReadFile(keyFile)
+			if err != nil {
+				return clitools.FailureResponse(clitools.ExitOnInvalidOption(fmt.Sprintf("failed to read SSH key file '%s': %s", keyFile, err.Error())))
+			}
+			privateKey = string(content)
+		}
+		if (c.Bool("inject-key") || c.Bool("install-binaries")) && privateKey == "" {
+			return clitools.FailureResponse(clitools.ExitOnInvalidOption("--inject-key and --install-binaries require --ssh-key-file"))
+		}
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		req := protocol.HostImportRequest{
+			Ref:             c.Args().First(),
+			SshUser:         c.String("ssh-user"),
+			SshPrivateKey:   privateKey,
+			SshPort:         uint32(c.Uint("ssh-port")),
+			InjectKey:       c.Bool("inject-key"),
+			InstallBinaries: c.Bool("install-binaries"),
+			SecurityGroups:  c.StringSlice("security-group"),
+		}
+		resp, err := clientSession.Host.Import(&req, temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "import of host", true).Error())))
+		}
 		return clitools.SuccessResponse(resp)
 	},
 }
@@ -384,7 +484,7 @@ var hostResize = &cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing arguments, a resize command requires that at least one argument (cpu, ram, disk, gpu, freq) is specified"))
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -422,7 +522,7 @@ var hostDelete = &cli.Command{
 		hostList = append(hostList, c.Args().First())
 		hostList = append(hostList, c.Args().Tail()...)
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -446,7 +546,7 @@ var hostSSH = &cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Host_name>."))
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -603,7 +703,7 @@ var hostSecurityGroupAddCommand = &cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory arguments."))
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -629,7 +729,7 @@ var hostSecurityGroupRemoveCommand = &cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory arguments."))
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -673,7 +773,7 @@ var hostSecurityGroupListCommand = &cli.Command{
 			state = "all"
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -717,7 +817,7 @@ var hostSecurityGroupEnableCommand = &cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory arguments."))
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -743,7 +843,7 @@ var hostSecurityGroupDisableCommand = &cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory arguments."))
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -767,6 +867,7 @@ var hostFeatureCommands = &cli.Command{
 		hostFeatureCheckCommand,
 		hostFeatureAddCommand,
 		hostFeatureRemoveCommand,
+		hostFeatureUpgradeCommand,
 		hostFeatureListCommand,
 	},
 }
@@ -791,7 +892,7 @@ var hostFeatureListCommand = &cli.Command{
 func hostFeatureListAction(c *cli.Context) error {
 	logrus.Tracef("SafeScale command: %s %s %s with args '%s'", hostCmdLabel, hostFeatureCmdLabel, c.Command.Name, c.Args())
 
-	clientSession, xerr := client.New(c.String("server"))
+	clientSession, xerr := newClientSession(c)
 	if xerr != nil {
 		return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 	}
@@ -850,7 +951,7 @@ func hostFeatureAddAction(c *cli.Context) error {
 	settings := protocol.FeatureSettings{}
 	settings.SkipProxy = c.Bool("skip-proxy")
 
-	clientSession, xerr := client.New(c.String("server"))
+	clientSession, xerr := newClientSession(c)
 	if xerr != nil {
 		return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 	}
@@ -911,7 +1012,7 @@ func hostFeatureCheckAction(c *cli.Context) error {
 	}
 	settings := protocol.FeatureSettings{}
 
-	clientSession, xerr := client.New(c.String("server"))
+	clientSession, xerr := newClientSession(c)
 	if xerr != nil {
 		return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 	}
@@ -973,7 +1074,7 @@ func hostFeatureRemoveAction(c *cli.Context) error {
 	}
 	settings := protocol.FeatureSettings{}
 
-	clientSession, xerr := client.New(c.String("server"))
+	clientSession, xerr := newClientSession(c)
 	if xerr != nil {
 		return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 	}
@@ -994,3 +1095,70 @@ func hostFeatureRemoveAction(c *cli.Context) error {
 	}
 	return clitools.SuccessResponse(nil)
 }
+
+// hostFeatureUpgradeCommand handles 'safescale host feature upgrade <host name or id> <feature name>'
+var hostFeatureUpgradeCommand = &cli.Command{
+	Name:      "upgrade",
+	Aliases:   []string{"update"},
+	Usage:     "Upgrades a feature installed on host to the version of its specification file",
+	ArgsUsage: "HOSTNAME FEATURENAME",
+
+	Flags: []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:    "param",
+			Aliases: []string{"p"},
+			Usage:   "Define value of feature parameter (can be used multiple times)",
+		},
+		&cli.BoolFlag{
+			Name:  "skip-requirements",
+			Usage: "Do not install or upgrade the features required by the feature",
+		},
+	},
+
+	Action: hostFeatureUpgradeAction,
+}
+
+func hostFeatureUpgradeAction(c *cli.Context) error {
+	logrus.Tracef("SafeScale command: %s %s %s with args '%s'", hostCmdLabel, hostFeatureCmdLabel, c.Command.Name, c.Args())
+	err := extractHostArgument(c, 0)
+	if err != nil {
+		return clitools.FailureResponse(err)
+	}
+
+	err = extractFeatureArgument(c)
+	if err != nil {
+		return clitools.FailureResponse(err)
+	}
+
+	values := map[string]string{}
+	params := c.StringSlice("param")
+	for _, k := range params {
+		res := strings.Split(k, "=")
+		if len(res[0]) > 0 {
+			values[res[0]] = strings.Join(res[1:], "=")
+		}
+	}
+	settings := protocol.FeatureSettings{}
+	settings.IgnoreFeatureRequirements = c.Bool("skip-requirements")
+
+	clientSession, xerr := newClientSession(c)
+	if xerr != nil {
+		return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+	}
+
+	// Wait for SSH service on remote host first
+	err = clientSession.SSH.WaitReady(hostInstance.Id, temporal.GetConnectionTimeout())
+	if err != nil {
+		err = fail.FromGRPCStatus(err)
+		msg := fmt.Sprintf("failed to reach '%s': %s", hostName, client.DecorateTimeoutError(err, "waiting ssh on host", false))
+		return clitools.FailureResponse(clitools.ExitOnRPC(msg))
+	}
+
+	err = clientSession.Host.UpgradeFeature(hostInstance.Id, featureName, values, &settings, 0)
+	if err != nil {
+		err = fail.FromGRPCStatus(err)
+		msg := fmt.Sprintf("failed to upgrade Feature '%s' on Host '%s': %s", featureName, hostName, err.Error())
+		return clitools.FailureResponse(clitools.ExitOnRPC(msg))
+	}
+	return clitools.SuccessResponse(nil)
+}
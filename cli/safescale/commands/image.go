@@ -21,6 +21,7 @@ import (
 	"github.com/urfave/cli/v2"
 
 	"github.com/CS-SI/SafeScale/lib/client"
+	"github.com/CS-SI/SafeScale/lib/protocol"
 	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
 	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/exitcode"
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
@@ -36,6 +37,8 @@ var ImageCommand = &cli.Command{
 	Usage: "image COMMAND",
 	Subcommands: []*cli.Command{
 		imageList,
+		imageCreate,
+		imageDelete,
 	},
 }
 
@@ -51,7 +54,7 @@ var imageList = &cli.Command{
 	Action: func(c *cli.Context) error {
 		logrus.Tracef("SafeScale command: %s %s with args '%s'", imageCmdName, c.Command.Name, c.Args())
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -64,3 +67,78 @@ var imageList = &cli.Command{
 		return clitools.SuccessResponse(images.GetImages())
 	},
 }
+
+var imageCreate = &cli.Command{
+	Name:      "create",
+	Aliases:   []string{"new"},
+	Usage:     "Create a custom image from an existing host",
+	ArgsUsage: "<Image_name>",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "from-host",
+			Usage: "Name or ID of the host to create the image from",
+		},
+		&cli.StringFlag{
+			Name:  "description",
+			Usage: "Description of the image",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", imageCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Image_name>."))
+		}
+		if c.String("from-host") == "" {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory flag --from-host."))
+		}
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		def := protocol.ImageCreateRequest{
+			Name:        c.Args().First(),
+			Host:        &protocol.Reference{Name: c.String("from-host")},
+			Description: c.String("description"),
+		}
+		image, err := clientSession.Image.Create(&def, temporal.GetLongOperationTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "creation of image", true).Error())))
+		}
+		return clitools.SuccessResponse(image)
+	},
+}
+
+var imageDelete = &cli.Command{
+	Name:      "delete",
+	Aliases:   []string{"rm", "remove"},
+	Usage:     "Remove custom image",
+	ArgsUsage: "<Image_name|Image_ID> [<Image_name|Image_ID>...]",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", imageCmdName, c.Command.Name, c.Args())
+		if c.NArg() < 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Image_name|Image_ID>."))
+		}
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		var imageNames []string
+		imageNames = append(imageNames, c.Args().First())
+		imageNames = append(imageNames, c.Args().Tail()...)
+
+		err := clientSession.Image.Delete(imageNames, temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "deletion of image", false).Error())))
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
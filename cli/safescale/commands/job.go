@@ -0,0 +1,240 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+
+	"github.com/CS-SI/SafeScale/lib/client"
+	"github.com/CS-SI/SafeScale/lib/protocol"
+	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
+	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/exitcode"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/strprocess"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+var jobCmdName = "job"
+
+// JobCommand command
+var JobCommand = &cli.Command{
+	Name:  "job",
+	Usage: "job COMMAND",
+	Subcommands: []*cli.Command{
+		jobList,
+		jobInspect,
+		jobWait,
+		jobWatch,
+		jobStop,
+	},
+}
+
+// asyncFlag is the flag of the commands able to run asynchronously
+var asyncFlag = &cli.BoolFlag{
+	Name:  "async",
+	Usage: "Returns the id of the job as soon as the operation is started, without waiting for its end (see 'safescale job')",
+}
+
+var jobList = &cli.Command{
+	Name:    "list",
+	Aliases: []string{"ls"},
+	Usage:   "List the running jobs",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:    "all",
+			Aliases: []string{"a"},
+			Usage:   "List also the jobs kept in history (ended, or interrupted by a stop of safescaled)",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", jobCmdName, c.Command.Name, c.Args())
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		if c.Bool("all") {
+			list, err := clientSession.JobManager.History(temporal.GetExecutionTimeout())
+			if err != nil {
+				err = fail.FromGRPCStatus(err)
+				return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "list of job history", false).Error())))
+			}
+			return clitools.SuccessResponse(list.GetList())
+		}
+
+		list, err := clientSession.JobManager.List(temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "list of jobs", false).Error())))
+		}
+		return clitools.SuccessResponse(list.GetList())
+	},
+}
+
+var jobInspect = &cli.Command{
+	Name:      "inspect",
+	Aliases:   []string{"show"},
+	Usage:     "Show the state and the last progress of a job, running, ended or interrupted",
+	ArgsUsage: "<Job_ID>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", jobCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Job_ID>."))
+		}
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		status, err := clientSession.JobManager.Inspect(c.Args().First(), temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "inspection of job", false).Error())))
+		}
+		return clitools.SuccessResponse(status)
+	},
+}
+
+var jobWait = &cli.Command{
+	Name:      "wait",
+	Usage:     "Wait for the end of a job, then show its state; fails if the job did not succeed",
+	ArgsUsage: "<Job_ID>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", jobCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Job_ID>."))
+		}
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		status, err := clientSession.JobManager.Wait(c.Args().First(), 0)
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "wait of job", false).Error())))
+		}
+		return jobEndResponse(status)
+	},
+}
+
+var jobWatch = &cli.Command{
+	Name:      "watch",
+	Usage:     "Display the progress of a job until its end (on stderr), then show its state; fails if the job did not succeed",
+	ArgsUsage: "<Job_ID>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", jobCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Job_ID>."))
+		}
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		id := c.Args().First()
+		err := clientSession.JobManager.Watch(id, func(event *protocol.JobProgress) {
+			_, _ = fmt.Fprintln(os.Stderr, formatJobProgress(event))
+		})
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "watch of job", false).Error())))
+		}
+
+		status, err := clientSession.JobManager.Inspect(id, temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "inspection of job", false).Error())))
+		}
+		return jobEndResponse(status)
+	},
+}
+
+var jobStop = &cli.Command{
+	Name:      "stop",
+	Aliases:   []string{"abort"},
+	Usage:     "Abort a running job",
+	ArgsUsage: "<Job_ID>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", jobCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Job_ID>."))
+		}
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		err := clientSession.JobManager.Stop(c.Args().First(), temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "stop of job", false).Error())))
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
+
+// jobEndResponse returns the response of the command depending on the final state of a job
+func jobEndResponse(status *protocol.JobStatus) error {
+	switch status.GetState() {
+	case "succeeded", "ended":
+		return clitools.SuccessResponse(status)
+	default:
+		msg := fmt.Sprintf("job '%s' %s", status.GetUuid(), status.GetState())
+		if status.GetError() != "" {
+			msg += ": " + status.GetError()
+		}
+		return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, msg))
+	}
+}
+
+// formatJobProgress returns a progress event as a line of text
+func formatJobProgress(event *protocol.JobProgress) string {
+	var parts []string
+	for _, v := range []string{event.GetPhase(), event.GetStep()} {
+		if v != "" {
+			parts = append(parts, v)
+		}
+	}
+	line := fmt.Sprintf("%s [%3d%%] %s", event.GetTime(), event.GetPercent(), strings.Join(parts, ": "))
+	if event.GetHost() != "" {
+		line += fmt.Sprintf(" (host '%s')", event.GetHost())
+	}
+	if event.GetMessage() != "" {
+		line += " - " + event.GetMessage()
+	}
+	return line
+}
+
+// asyncResponse returns the response of a command started asynchronously, containing the id of the job
+func asyncResponse(clientSession *client.Session) error {
+	return clitools.SuccessResponse(map[string]string{"job_id": clientSession.JobID()})
+}
@@ -0,0 +1,138 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+
+	"github.com/CS-SI/SafeScale/lib/protocol"
+	"github.com/CS-SI/SafeScale/lib/server/resources/operations/converters"
+	"github.com/CS-SI/SafeScale/lib/utils"
+	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
+	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/exitcode"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+// ApplyCommand handles 'safescale apply -f <manifest>'
+var ApplyCommand = &cli.Command{
+	Name:  "apply",
+	Usage: "apply -f MANIFEST",
+	Description: `Creates the resources described in MANIFEST (YAML, JSON or TOML file) that do not exist yet.
+   The plan of what will be done is displayed first, and confirmation is requested.
+   The plan also reports the drifts, marked as not applied: attributes of existing resources differing from MANIFEST,
+   and resources removed from MANIFEST (if named) since its last apply, deleted only with --prune.`,
+	Flags: append(manifestFlags(), &cli.BoolFlag{
+		Name:  "prune",
+		Usage: "Deletes the resources removed from the manifest since its last apply",
+	}),
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s with args '%s'", c.Command.Name, c.Args())
+		return manifestAction(c, false)
+	},
+}
+
+// DestroyCommand handles 'safescale destroy -f <manifest>'
+var DestroyCommand = &cli.Command{
+	Name:  "destroy",
+	Usage: "destroy -f MANIFEST",
+	Description: `Deletes the resources described in MANIFEST (YAML, JSON or TOML file) that exist.
+   The plan of what will be done is displayed first, and confirmation is requested.`,
+	Flags: manifestFlags(),
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s with args '%s'", c.Command.Name, c.Args())
+		return manifestAction(c, true)
+	},
+}
+
+// manifestFlags returns the flags shared by 'apply' and 'destroy'
+func manifestFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:     "file",
+			Aliases:  []string{"f"},
+			Required: true,
+			Usage:    "Manifest to use; format is deduced from extension (.yml, .yaml, .json or .toml)",
+		},
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "Displays what would be done, without doing it",
+		},
+		&cli.BoolFlag{
+			Name:    "assume-yes",
+			Aliases: []string{"yes", "y"},
+			Usage:   "Does not ask for confirmation",
+		},
+		asyncFlag,
+	}
+}
+
+// manifestAction plans then, if confirmed, applies or destroys the content of the manifest
+func manifestAction(c *cli.Context, destroy bool) error {
+	filename := c.String("file")
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.InvalidOption, fmt.Sprintf("failed to read manifest '%s': %s", filename, err.Error())))
+	}
+	format := strings.ToLower(strings.TrimPrefix(filepath.Ext(filename), "."))
+
+	clientSession, xerr := newClientSession(c)
+	if xerr != nil {
+		return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+	}
+
+	prune := !destroy && c.Bool("prune")
+	plan, err := clientSession.Manifest.Plan(content, format, destroy, prune, temporal.GetExecutionTimeout())
+	if err != nil {
+		err = fail.FromGRPCStatus(err)
+		return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+	}
+	steps := converters.ManifestPlanFromProtocolToAbstract(plan)
+	if c.Bool("dry-run") || steps.Applicable().IsEmpty() {
+		return clitools.SuccessResponse(steps)
+	}
+
+	if !c.Bool("assume-yes") {
+		for _, v := range steps {
+			fmt.Println(v.String())
+		}
+		if !utils.UserConfirmed(fmt.Sprintf("Are you sure you want to proceed with these %d step(s)", len(steps.Applicable()))) {
+			return clitools.SuccessResponse("Aborted")
+		}
+	}
+
+	var done *protocol.ManifestPlan
+	if destroy {
+		done, err = clientSession.Manifest.Destroy(content, format, temporal.GetLongOperationTimeout())
+	} else {
+		done, err = clientSession.Manifest.Apply(content, format, prune, temporal.GetLongOperationTimeout())
+	}
+	if err != nil {
+		err = fail.FromGRPCStatus(err)
+		return clitools.FailureResponse(clitools.ExitOnRPC(err.Error()))
+	}
+	if c.Bool("async") {
+		return asyncResponse(clientSession)
+	}
+	return clitools.SuccessResponse(converters.ManifestPlanFromProtocolToAbstract(done))
+}
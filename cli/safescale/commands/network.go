@@ -46,6 +46,7 @@ var NetworkCommand = &cli.Command{
 	Usage:   "network COMMAND",
 	Subcommands: []*cli.Command{
 		networkCreate,
+		networkImport,
 		networkDelete,
 		networkInspect,
 		networkList,
@@ -63,16 +64,25 @@ var networkList = &cli.Command{
 			Name:    "provider",
 			Aliases: []string{"all", "a"},
 			Usage:   "Lists all Networks available on tenant (not only those created by SafeScale)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "label",
+			Usage: "Lists only Networks having this label, in the form key=value or key (may be used several times)",
 		}},
 	Action: func(c *cli.Context) error {
 		logrus.Tracef("SafeScale command: %s %s with args '%s'", networkCmdLabel, c.Command.Name, c.Args())
 
-		clientSession, xerr := client.New(c.String("server"))
+		labels, err := constructLabelsFromCLI(c, true)
+		if err != nil {
+			return err
+		}
+
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
 
-		networks, err := clientSession.Network.List(c.Bool("all"), temporal.GetExecutionTimeout())
+		networks, err := clientSession.Network.List(c.Bool("all"), labels, temporal.GetExecutionTimeout())
 		if err != nil {
 			err = fail.FromGRPCStatus(err)
 			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "list of networks", false).Error())))
@@ -99,7 +109,7 @@ var networkDelete = &cli.Command{
 		networkList = append(networkList, c.Args().First())
 		networkList = append(networkList, c.Args().Tail()...)
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -113,6 +123,32 @@ var networkDelete = &cli.Command{
 	},
 }
 
+var networkImport = &cli.Command{
+	Name:      "import",
+	Aliases:   []string{"adopt"},
+	Usage:     "registers in SafeScale an existing network of the provider, with its subnets",
+	ArgsUsage: "NETWORKREF",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", networkCmdLabel, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument NETWORKREF."))
+		}
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		resp, err := clientSession.Network.Import(c.Args().First(), temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "import of network", true).Error())))
+		}
+		return clitools.SuccessResponse(resp)
+	},
+}
+
 var networkInspect = &cli.Command{
 	Name:      "inspect",
 	Aliases:   []string{"show"},
@@ -125,7 +161,7 @@ var networkInspect = &cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <network_name>."))
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -285,6 +321,10 @@ var networkCreate = &cli.Command{
 						--sizing "cpu <= 8, ram ~ 16"
 			Meaningful only if --empty is not used`,
 		},
+		&cli.StringSliceFlag{
+			Name:  "label",
+			Usage: "Label to set on the Network and its gateway(s), in the form key=value (may be used several times)",
+		},
 	},
 	Action: func(c *cli.Context) error {
 		logrus.Tracef("SafeScale command: %s %s with args '%s'", networkCmdLabel, c.Command.Name, c.Args())
@@ -305,7 +345,11 @@ var networkCreate = &cli.Command{
 				return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, err.Error()))
 			}
 		}
-		clientSession, xerr := client.New(c.String("server"))
+		labels, err := constructLabelsFromCLI(c, false)
+		if err != nil {
+			return err
+		}
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -314,7 +358,7 @@ var networkCreate = &cli.Command{
 		network, err := clientSession.Network.Create(
 			c.Args().Get(0), c.String("cidr"), c.Bool("empty"),
 			c.String("gwname"), gatewaySSHPort, c.String("os"), sizing,
-			c.Bool("keep-on-failure"),
+			c.Bool("keep-on-failure"), labels,
 			temporal.GetExecutionTimeout(),
 		)
 		if err != nil {
@@ -342,9 +386,11 @@ var networkSecurityGroupCommands = &cli.Command{
 	Subcommands: []*cli.Command{
 		networkSecurityGroupList,
 		networkSecurityGroupCreate,
+		networkSecurityGroupImport,
 		networkSecurityGroupDelete,
 		networkSecurityGroupInspect,
 		networkSecurityGroupClear,
+		networkSecurityGroupCheck,
 		networkSecurityGroupBonds,
 		networkSecurityGroupRuleCommand,
 	},
@@ -366,7 +412,7 @@ var networkSecurityGroupList = &cli.Command{
 
 		// networkRef := c.Args().First()
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -408,7 +454,7 @@ var networkSecurityGroupInspect = &cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument GROUPREF."))
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -486,7 +532,7 @@ var networkSecurityGroupCreate = &cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument GROUPREF."))
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -504,6 +550,37 @@ var networkSecurityGroupCreate = &cli.Command{
 	},
 }
 
+var networkSecurityGroupImport = &cli.Command{
+	Name:      "import",
+	Aliases:   []string{"adopt"},
+	Usage:     "registers in SafeScale an existing Security Group of the provider",
+	ArgsUsage: "NETWORKREF GROUPREF",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s %s %s with args '%s'", networkCmdLabel, securityCmdLabel, groupCmdLabel, c.Command.Name, c.Args())
+
+		switch c.NArg() {
+		case 0:
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument NETWORKREF."))
+		case 1:
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument GROUPREF."))
+		}
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		resp, err := clientSession.SecurityGroup.Import(c.Args().First(), c.Args().Get(1), temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "import of security-group", true).Error())))
+		}
+		return clitools.SuccessResponse(resp)
+	},
+}
+
 // networkSecurityGroupClear ...
 var networkSecurityGroupClear = &cli.Command{
 	Name:      "clear",
@@ -522,7 +599,7 @@ var networkSecurityGroupClear = &cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument GROUPREF."))
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -536,6 +613,48 @@ var networkSecurityGroupClear = &cli.Command{
 	},
 }
 
+// networkSecurityGroupCheck handles 'safescale network security group check'
+var networkSecurityGroupCheck = &cli.Command{
+	Name:      "check",
+	Usage:     "Checks that the rules of a Security Group on provider side are the ones registered in metadata",
+	ArgsUsage: "NETWORKREF|- GROUPREF",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "fix",
+			Usage: "Re-applies the rules registered in metadata if differences are found",
+		},
+		&cli.BoolFlag{
+			Name:  "adopt",
+			Usage: "Registers in metadata the rules found on provider side if differences are found (implies --fix)",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s %s %s with args '%s'", networkCmdLabel, securityCmdLabel, groupCmdLabel, c.Command.Name, c.Args())
+
+		switch c.NArg() {
+		case 0:
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument NETWORKREF."))
+		case 1:
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument GROUPREF."))
+		}
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		adopt := c.Bool("adopt")
+		resp, err := clientSession.SecurityGroup.Check(c.Args().Get(1), c.Bool("fix") || adopt, adopt, temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "check of security-group", true).Error())))
+		}
+		return clitools.SuccessResponse(resp)
+	},
+}
+
 var networkSecurityGroupDelete = &cli.Command{
 	Name:      "delete",
 	Aliases:   []string{"rm", "remove"},
@@ -560,7 +679,7 @@ var networkSecurityGroupDelete = &cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument GROUPREF."))
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -598,7 +717,7 @@ var networkSecurityGroupBonds = &cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument GROUPREF."))
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -715,7 +834,7 @@ var networkSecurityGroupRuleAdd = &cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.InvalidOption, xerr.Error()))
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -806,7 +925,7 @@ var networkSecurityGroupRuleDelete = &cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.InvalidOption, xerr.Error()))
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -875,7 +994,7 @@ var subnetList = &cli.Command{
 			networkRef = ""
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -935,7 +1054,7 @@ var subnetDelete = &cli.Command{
 		var list []string
 		list = append(list, c.Args().Tail()...)
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -970,7 +1089,7 @@ var subnetInspect = &cli.Command{
 			networkRef = ""
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -1076,7 +1195,7 @@ var subnetCreate = &cli.Command{
 			return err
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -1327,7 +1446,7 @@ var subnetSecurityGroupAddCommand = &cli.Command{
 			networkRef = ""
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -1365,7 +1484,7 @@ var subnetSecurityGroupRemoveCommand = &cli.Command{
 			networkRef = ""
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -1420,7 +1539,7 @@ var subnetSecurityGroupListCommand = &cli.Command{
 			state = c.String("state")
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -1458,7 +1577,7 @@ var subnetSecurityGroupEnableCommand = &cli.Command{
 			networkRef = ""
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -1496,7 +1615,7 @@ var subnetSecurityGroupDisableCommand = &cli.Command{
 			networkRef = ""
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
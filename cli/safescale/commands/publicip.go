@@ -0,0 +1,223 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package commands
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+
+	"github.com/CS-SI/SafeScale/lib/client"
+	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
+	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/exitcode"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/strprocess"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+var publicIPCmdName = "publicip"
+
+// PublicIPCommand publicip command
+var PublicIPCommand = &cli.Command{
+	Name:    "publicip",
+	Aliases: []string{"pip"},
+	Usage:   "publicip COMMAND",
+	Subcommands: []*cli.Command{
+		publicIPList,
+		publicIPInspect,
+		publicIPDelete,
+		publicIPCreate,
+		publicIPBind,
+		publicIPUnbind,
+	},
+}
+
+var publicIPList = &cli.Command{
+	Name:    "list",
+	Aliases: []string{"ls"},
+	Usage:   "List available public IPs",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:    "all",
+			Aliases: []string{"a"},
+			Usage:   "List all public IPs on tenant (not only those created by SafeScale)",
+		}},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", publicIPCmdName, c.Command.Name, c.Args())
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		list, err := clientSession.PublicIP.List(c.Bool("all"), temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "list of public IPs", false).Error())))
+		}
+		return clitools.SuccessResponse(list.PublicIps)
+	},
+}
+
+var publicIPInspect = &cli.Command{
+	Name:      "inspect",
+	Aliases:   []string{"show"},
+	Usage:     "Inspect public IP",
+	ArgsUsage: "<PublicIP_name|PublicIP_ID>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", publicIPCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <PublicIP_name|PublicIP_ID>."))
+		}
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		pip, err := clientSession.PublicIP.Inspect(c.Args().First(), temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "inspection of public IP", false).Error())))
+		}
+		return clitools.SuccessResponse(pip)
+	},
+}
+
+var publicIPDelete = &cli.Command{
+	Name:      "delete",
+	Aliases:   []string{"rm", "remove"},
+	Usage:     "Release public IP",
+	ArgsUsage: "<PublicIP_name|PublicIP_ID> [<PublicIP_name|PublicIP_ID>...]",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "force",
+			Usage: "Unbind the public IP from its host before releasing it",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", publicIPCmdName, c.Command.Name, c.Args())
+		if c.NArg() < 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <PublicIP_name|PublicIP_ID>."))
+		}
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		var list []string
+		list = append(list, c.Args().First())
+		list = append(list, c.Args().Tail()...)
+
+		err := clientSession.PublicIP.Delete(list, c.Bool("force"), temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "deletion of public IP", false).Error())))
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
+
+var publicIPCreate = &cli.Command{
+	Name:      "create",
+	Aliases:   []string{"new", "allocate"},
+	Usage:     "Allocate a public IP",
+	ArgsUsage: "<PublicIP_name>",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "type",
+			Usage: "Provider-specific kind of public IP (floating IP pool on OpenStack, domain on AWS, ...); defaults to provider default",
+		},
+		&cli.StringFlag{
+			Name:  "description",
+			Usage: "Description of the public IP",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", publicIPCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <PublicIP_name>."))
+		}
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		pip, err := clientSession.PublicIP.Create(c.Args().First(), c.String("type"), c.String("description"), temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "creation of public IP", true).Error())))
+		}
+		return clitools.SuccessResponse(pip)
+	},
+}
+
+var publicIPBind = &cli.Command{
+	Name:      "bind",
+	Aliases:   []string{"attach"},
+	Usage:     "Bind a public IP to an host, moving it from the host currently using it if needed",
+	ArgsUsage: "<PublicIP_name|PublicIP_ID> <Host_name|Host_ID>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", publicIPCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 2 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <PublicIP_name> and/or <Host_name>."))
+		}
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		err := clientSession.PublicIP.Bind(c.Args().Get(0), c.Args().Get(1), temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "bind of public IP", true).Error())))
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
+
+var publicIPUnbind = &cli.Command{
+	Name:      "unbind",
+	Aliases:   []string{"detach"},
+	Usage:     "Unbind a public IP from an host",
+	ArgsUsage: "<PublicIP_name|PublicIP_ID> <Host_name|Host_ID>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", publicIPCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 2 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <PublicIP_name> and/or <Host_name>."))
+		}
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		err := clientSession.PublicIP.Unbind(c.Args().Get(0), c.Args().Get(1), temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "unbind of public IP", true).Error())))
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
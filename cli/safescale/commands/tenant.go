@@ -17,6 +17,8 @@
 package commands
 
 import (
+	"fmt"
+
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
 
@@ -25,6 +27,8 @@ import (
 	"github.com/CS-SI/SafeScale/lib/utils/strprocess"
 
 	"github.com/CS-SI/SafeScale/lib/client"
+	"github.com/CS-SI/SafeScale/lib/protocol"
+	"github.com/CS-SI/SafeScale/lib/utils"
 	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
 	"github.com/CS-SI/SafeScale/lib/utils/temporal"
 )
@@ -41,6 +45,8 @@ var TenantCommand = &cli.Command{
 		tenantSetCommand,
 		tenantInspectCommand,
 		tenantScanCommand,
+		tenantDriftCommand,
+		tenantReconcileCommand,
 		tenantMetadataCommands,
 	},
 }
@@ -53,7 +59,7 @@ var tenantListCommand = &cli.Command{
 	Action: func(c *cli.Context) error {
 		logrus.Tracef("SafeScale command: %s %s with args '%s'", tenantCmdLabel, c.Command.Name, c.Args())
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -75,7 +81,7 @@ var tenantGetCommand = &cli.Command{
 	Action: func(c *cli.Context) error {
 		logrus.Tracef("SafeScale command: %s %s with args '%s'", tenantCmdLabel, c.Command.Name, c.Args())
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -101,7 +107,7 @@ var tenantSetCommand = &cli.Command{
 
 		logrus.Tracef("SafeScale command: %s %s with args '%s'", tenantCmdLabel, c.Command.Name, c.Args())
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -128,7 +134,7 @@ var tenantInspectCommand = &cli.Command{
 
 		logrus.Tracef("SafeScale command: %s %s with args '%s'", tenantCmdLabel, c.Command.Name, c.Args())
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -158,7 +164,7 @@ var tenantScanCommand = &cli.Command{
 
 		logrus.Tracef("SafeScale command: %s %s with args '%s'", tenantCmdLabel, c.Command.Name, c.Args())
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -172,6 +178,129 @@ var tenantScanCommand = &cli.Command{
 	},
 }
 
+// tenantDriftCommand handles 'safescale tenant drift'
+var tenantDriftCommand = &cli.Command{
+	Name:      "drift",
+	Usage:     "Checks that the rules of all the Security Groups on provider side are the ones registered in metadata",
+	ArgsUsage: "[TENANTNAME]",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "fix",
+			Usage: "Re-applies the rules registered in metadata on the Security Groups having differences",
+		},
+		&cli.BoolFlag{
+			Name:  "adopt",
+			Usage: "Registers in metadata the rules found on provider side for the Security Groups having differences (implies --fix)",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", tenantCmdLabel, c.Command.Name, c.Args())
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		adopt := c.Bool("adopt")
+		results, err := clientSession.Tenant.Drift(c.Args().First(), c.Bool("fix") || adopt, adopt, temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "drift of tenant", false).Error())))
+		}
+		return clitools.SuccessResponse(results)
+	},
+}
+
+// tenantReconcileCommand handles 'safescale tenant reconcile'
+var tenantReconcileCommand = &cli.Command{
+	Name:      "reconcile",
+	Usage:     "Compares the metadata with the resources on provider side, and fixes the differences on demand",
+	ArgsUsage: "[TENANTNAME]",
+	Flags: []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:  "kind",
+			Usage: "Kind of resources to reconcile (clusters, shares, hosts, volumes, subnets, security-groups, networks); can be repeated; all if not set",
+		},
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "Reports the actions without applying them",
+		},
+		&cli.BoolFlag{
+			Name:  "import",
+			Usage: "Registers in metadata the resources found on provider side only",
+		},
+		&cli.BoolFlag{
+			Name:  "purge-cloud",
+			Usage: "Deletes on provider side the resources not registered in metadata (cannot be used with --import)",
+		},
+		&cli.BoolFlag{
+			Name:  "purge",
+			Usage: "Deletes the metadata of the resources not found on provider side",
+		},
+		&cli.BoolFlag{
+			Name:  "repair",
+			Usage: "Fixes the resources having differences between metadata and provider side",
+		},
+		&cli.BoolFlag{
+			Name:    "assume-yes",
+			Aliases: []string{"yes", "y"},
+			Usage:   "Does not ask confirmation before deleting resources or metadata",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", tenantCmdLabel, c.Command.Name, c.Args())
+
+		if c.Bool("import") && c.Bool("purge-cloud") {
+			return clitools.FailureResponse(clitools.ExitOnInvalidOption("--import and --purge-cloud cannot be used together"))
+		}
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		req := &protocol.TenantReconcileRequest{
+			Kinds:      c.StringSlice("kind"),
+			DryRun:     c.Bool("dry-run"),
+			Import:     c.Bool("import"),
+			PurgeCloud: c.Bool("purge-cloud"),
+			Purge:      c.Bool("purge"),
+			Repair:     c.Bool("repair"),
+		}
+
+		// deletions are previewed and confirmed, then applied only to the resources previewed
+		if (req.Purge || req.PurgeCloud) && !req.DryRun && !c.Bool("assume-yes") {
+			req.DryRun = true
+			results, err := clientSession.Tenant.Reconcile(c.Args().First(), req, temporal.GetExecutionTimeout())
+			req.DryRun = false
+			if err != nil {
+				err = fail.FromGRPCStatus(err)
+				return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "reconciliation of tenant", false).Error())))
+			}
+
+			for _, v := range results.GetEntries() {
+				if v.GetAction() != "" {
+					fmt.Printf("%s %s '%s' (%s): %s\n", v.GetAction(), v.GetKind(), v.GetName(), v.GetId(), v.GetDetails())
+					req.Only = append(req.Only, v.GetKind()+"/"+v.GetId())
+				}
+			}
+			if len(req.Only) == 0 {
+				return clitools.SuccessResponse(results)
+			}
+			if !utils.UserConfirmed(fmt.Sprintf("Are you sure you want to apply these %d action(s)", len(req.Only))) {
+				return clitools.SuccessResponse("Aborted")
+			}
+		}
+
+		results, err := clientSession.Tenant.Reconcile(c.Args().First(), req, temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "reconciliation of tenant", false).Error())))
+		}
+		return clitools.SuccessResponse(results)
+	},
+}
+
 const tenantMetadataCmdLabel = "metadata"
 
 // tenantMetadataCommands handles 'safescale tenant metadata' commands
@@ -207,7 +336,7 @@ var tenantMetadataUpgradeCommand = &cli.Command{
 
 		logrus.Tracef("SafeScale command: %s %s %s with args '%s'", tenantCmdLabel, tenantMetadataCmdLabel, c.Command.Name, c.Args())
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -236,7 +365,7 @@ var tenantMetadataDeleteCommand = &cli.Command{
 
 		logrus.Tracef("SafeScale command: %s %s with args '%s'", tenantCmdLabel, c.Command.Name, c.Args())
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
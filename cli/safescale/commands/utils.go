@@ -25,7 +25,9 @@ import (
 	"github.com/denisbrodbeck/machineid"
 	"github.com/urfave/cli/v2"
 
+	"github.com/CS-SI/SafeScale/lib/client"
 	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
 )
 
 // GenerateClientIdentity builds a string identifying the client
@@ -34,6 +36,56 @@ func GenerateClientIdentity() string {
 	return id
 }
 
+// ClientOptions returns the options of client.New corresponding to the global options of the command line
+func ClientOptions(c *cli.Context) []client.Option {
+	config := client.TLSConfig{
+		CAFile:     c.String("tls-ca"),
+		CertFile:   c.String("tls-cert"),
+		KeyFile:    c.String("tls-key"),
+		ServerName: c.String("tls-server-name"),
+	}
+	var options []client.Option
+	if c.Bool("tls") || config != (client.TLSConfig{}) {
+		options = append(options, client.WithTLS(config))
+	}
+	if c.Bool("async") {
+		options = append(options, client.WithAsync())
+	}
+	return options
+}
+
+// newClientSession creates a session with the safescaled designated by the global options of the command line
+func newClientSession(c *cli.Context) (*client.Session, fail.Error) {
+	return client.New(c.String("server"), ClientOptions(c)...)
+}
+
+// constructLabelsFromCLI parses the values of --label into a map
+// If keyOnly is true, a value without '=' is accepted and means "any value for this key" (used as selector)
+func constructLabelsFromCLI(c *cli.Context, keyOnly bool) (map[string]string, error) {
+	values := c.StringSlice("label")
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	labels := make(map[string]string, len(values))
+	for _, v := range values {
+		splitted := strings.SplitN(v, "=", 2)
+		key := strings.TrimSpace(splitted[0])
+		if key == "" {
+			return nil, clitools.FailureResponse(clitools.ExitOnInvalidArgument(fmt.Sprintf("invalid label '%s': key cannot be empty", v)))
+		}
+		if len(splitted) == 1 {
+			if !keyOnly {
+				return nil, clitools.FailureResponse(clitools.ExitOnInvalidArgument(fmt.Sprintf("invalid label '%s': expected key=value", v)))
+			}
+			labels[key] = ""
+			continue
+		}
+		labels[key] = strings.TrimSpace(splitted[1])
+	}
+	return labels, nil
+}
+
 // constructHostDefinitionStringFromCLI ...
 func constructHostDefinitionStringFromCLI(c *cli.Context, key string) (string, error) {
 	var sizing string
@@ -42,10 +94,10 @@ func constructHostDefinitionStringFromCLI(c *cli.Context, key string) (string, e
 			return "", clitools.FailureResponse(clitools.ExitOnInvalidArgument(fmt.Sprintf("cannot use simultaneously --%s and --cpu|--cpufreq|--gpu|--ram|--disk", key)))
 		}
 		sizing = c.String(key)
-		splitted := strings.Split(key, ",")
+		splitted := strings.Split(sizing, ",")
 		found := false
 		for _, v := range splitted {
-			if strings.HasPrefix(v, "gpu") {
+			if strings.HasPrefix(strings.TrimSpace(v), "gpu") {
 				found = true
 				break
 			}
@@ -45,8 +45,11 @@ var VolumeCommand = &cli.Command{
 		volumeInspect,
 		volumeDelete,
 		volumeCreate,
+		volumeImport,
+		volumeResize,
 		volumeAttach,
 		volumeDetach,
+		volumeSnapshotCommand,
 	},
 }
 
@@ -59,16 +62,25 @@ var volumeList = &cli.Command{
 			Name:    "all",
 			Aliases: []string{"a"},
 			Usage:   "ErrorList all Volumes on tenant (not only those created by SafeScale)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "label",
+			Usage: "Lists only volumes having this label, in the form key=value or key (may be used several times)",
 		}},
 	Action: func(c *cli.Context) error {
 		logrus.Tracef("SafeScale command: %s %s with args '%s'", volumeCmdName, c.Command.Name, c.Args())
 
-		clientSession, xerr := client.New(c.String("server"))
+		labels, err := constructLabelsFromCLI(c, true)
+		if err != nil {
+			return err
+		}
+
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
 
-		volumes, err := clientSession.Volume.List(c.Bool("all"), temporal.GetExecutionTimeout())
+		volumes, err := clientSession.Volume.List(c.Bool("all"), labels, temporal.GetExecutionTimeout())
 		if err != nil {
 			err = fail.FromGRPCStatus(err)
 			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "list of volumes", false).Error())))
@@ -89,7 +101,7 @@ var volumeInspect = &cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Volume_name|Volume_ID>."))
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -115,7 +127,7 @@ var volumeDelete = &cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Volume_name|Volume_ID>."))
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -133,6 +145,32 @@ var volumeDelete = &cli.Command{
 	},
 }
 
+var volumeImport = &cli.Command{
+	Name:      "import",
+	Aliases:   []string{"adopt"},
+	Usage:     "Registers in SafeScale an existing volume of the provider",
+	ArgsUsage: "<Provider_volume_id>",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", volumeCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Provider_volume_id>."))
+		}
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		resp, err := clientSession.Volume.Import(c.Args().First(), temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "import of volume", true).Error())))
+		}
+		return clitools.SuccessResponse(resp)
+	},
+}
+
 var volumeCreate = &cli.Command{
 	Name:      "create",
 	Aliases:   []string{"new"},
@@ -149,6 +187,10 @@ var volumeCreate = &cli.Command{
 			Value: "HDD",
 			Usage: fmt.Sprintf("Allowed values: %s", getAllowedSpeeds()),
 		},
+		&cli.StringSliceFlag{
+			Name:  "label",
+			Usage: "Label to set on the volume, in the form key=value (may be used several times)",
+		},
 	},
 	Action: func(c *cli.Context) error {
 		logrus.Tracef("SafeScale command: %s %s with args '%s'", volumeCmdName, c.Command.Name, c.Args())
@@ -157,7 +199,12 @@ var volumeCreate = &cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Volume_name>. "))
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		labels, err := constructLabelsFromCLI(c, false)
+		if err != nil {
+			return err
+		}
+
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -172,9 +219,10 @@ var volumeCreate = &cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnInvalidOption(fmt.Sprintf("Invalid volume size '%d', should be at least 1", volSize)))
 		}
 		def := protocol.VolumeCreateRequest{
-			Name:  c.Args().First(),
-			Size:  volSize,
-			Speed: protocol.VolumeSpeed(volSpeed),
+			Name:   c.Args().First(),
+			Size:   volSize,
+			Speed:  protocol.VolumeSpeed(volSpeed),
+			Labels: labels,
 		}
 
 		volume, err := clientSession.Volume.Create(&def, temporal.GetExecutionTimeout())
@@ -186,6 +234,44 @@ var volumeCreate = &cli.Command{
 	},
 }
 
+var volumeResize = &cli.Command{
+	Name:      "resize",
+	Aliases:   []string{"grow", "extend"},
+	Usage:     "Grow a volume, and its file system on the hosts where it is attached",
+	ArgsUsage: "<Volume_name|Volume_ID>",
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:     "size",
+			Required: true,
+			Usage:    "New size of the volume (in Go), must be greater than the current one",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s %s with args '%s'", volumeCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Volume_name|Volume_ID>."))
+		}
+
+		volSize := c.Int("size")
+		if volSize <= 0 {
+			return clitools.FailureResponse(clitools.ExitOnInvalidOption(fmt.Sprintf("Invalid volume size '%d', should be at least 1", volSize)))
+		}
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		volume, err := clientSession.Volume.Resize(c.Args().First(), volSize, temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "resize of volume", true).Error())))
+		}
+		return clitools.SuccessResponse(toDisplayableVolume(volume))
+	},
+}
+
 var volumeAttach = &cli.Command{
 	Name:      "attach",
 	Aliases:   []string{"bind"},
@@ -214,7 +300,7 @@ var volumeAttach = &cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Volume_name> and/or <Host_name>."))
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -247,7 +333,7 @@ var volumeDetach = &cli.Command{
 			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Volume_name> and/or <Host_name>."))
 		}
 
-		clientSession, xerr := client.New(c.String("server"))
+		clientSession, xerr := newClientSession(c)
 		if xerr != nil {
 			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
 		}
@@ -261,6 +347,169 @@ var volumeDetach = &cli.Command{
 	},
 }
 
+var volumeSnapshotCommand = &cli.Command{
+	Name:  "snapshot",
+	Usage: "snapshot COMMAND",
+	Subcommands: []*cli.Command{
+		volumeSnapshotCreate,
+		volumeSnapshotList,
+		volumeSnapshotDelete,
+		volumeSnapshotRestore,
+	},
+}
+
+var volumeSnapshotCreate = &cli.Command{
+	Name:      "create",
+	Aliases:   []string{"new"},
+	Usage:     "Take a snapshot of a volume",
+	ArgsUsage: "<Volume_name|Volume_ID> <Snapshot_name>",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "description",
+			Usage: "Description of the snapshot",
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s snapshot %s with args '%s'", volumeCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 2 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Volume_name> and/or <Snapshot_name>."))
+		}
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		def := protocol.VolumeSnapshotCreateRequest{
+			Volume:      &protocol.Reference{Name: c.Args().Get(0)},
+			Name:        c.Args().Get(1),
+			Description: c.String("description"),
+		}
+		snapshot, err := clientSession.Volume.SnapshotCreate(&def, temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "creation of volume snapshot", true).Error())))
+		}
+		return clitools.SuccessResponse(snapshot)
+	},
+}
+
+var volumeSnapshotList = &cli.Command{
+	Name:      "list",
+	Aliases:   []string{"ls"},
+	Usage:     "List the snapshots of a volume (or of all volumes)",
+	ArgsUsage: "[<Volume_name|Volume_ID>]",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:    "all",
+			Aliases: []string{"a"},
+			Usage:   "List all snapshots on tenant (not only those created by SafeScale)",
+		}},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s snapshot %s with args '%s'", volumeCmdName, c.Command.Name, c.Args())
+		if c.NArg() > 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Too many arguments."))
+		}
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		snapshots, err := clientSession.Volume.SnapshotList(c.Args().First(), c.Bool("all"), temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "list of volume snapshots", false).Error())))
+		}
+		return clitools.SuccessResponse(snapshots.Snapshots)
+	},
+}
+
+var volumeSnapshotDelete = &cli.Command{
+	Name:      "delete",
+	Aliases:   []string{"rm", "remove"},
+	Usage:     "Remove volume snapshot",
+	ArgsUsage: "<Snapshot_name|Snapshot_ID> [<Snapshot_name|Snapshot_ID>...]",
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s snapshot %s with args '%s'", volumeCmdName, c.Command.Name, c.Args())
+		if c.NArg() < 1 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Snapshot_name|Snapshot_ID>."))
+		}
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		var snapshotList []string
+		snapshotList = append(snapshotList, c.Args().First())
+		snapshotList = append(snapshotList, c.Args().Tail()...)
+
+		err := clientSession.Volume.SnapshotDelete(snapshotList, temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "deletion of volume snapshot", false).Error())))
+		}
+		return clitools.SuccessResponse(nil)
+	},
+}
+
+var volumeSnapshotRestore = &cli.Command{
+	Name:      "restore",
+	Usage:     "Create a new volume from a snapshot",
+	ArgsUsage: "<Snapshot_name|Snapshot_ID> <Volume_name>",
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:  "size",
+			Value: 0,
+			Usage: "Size of the new volume (in Go); defaults to the size of the snapshot",
+		},
+		&cli.StringFlag{
+			Name:  "speed",
+			Value: "HDD",
+			Usage: fmt.Sprintf("Allowed values: %s", getAllowedSpeeds()),
+		},
+	},
+	Action: func(c *cli.Context) error {
+		logrus.Tracef("SafeScale command: %s snapshot %s with args '%s'", volumeCmdName, c.Command.Name, c.Args())
+		if c.NArg() != 2 {
+			_ = cli.ShowSubcommandHelp(c)
+			return clitools.FailureResponse(clitools.ExitOnInvalidArgument("Missing mandatory argument <Snapshot_name> and/or <Volume_name>."))
+		}
+
+		clientSession, xerr := newClientSession(c)
+		if xerr != nil {
+			return clitools.FailureResponse(clitools.ExitOnErrorWithMessage(exitcode.Run, xerr.Error()))
+		}
+
+		speed := c.String("speed")
+		volSpeed, ok := protocol.VolumeSpeed_value["VS_"+speed]
+		if !ok {
+			return clitools.FailureResponse(clitools.ExitOnInvalidOption(fmt.Sprintf("Invalid speed '%s'", speed)))
+		}
+		volSize := int32(c.Int("size"))
+		if volSize < 0 {
+			return clitools.FailureResponse(clitools.ExitOnInvalidOption(fmt.Sprintf("Invalid volume size '%d'", volSize)))
+		}
+		def := protocol.VolumeSnapshotRestoreRequest{
+			Snapshot: &protocol.Reference{Name: c.Args().Get(0)},
+			Name:     c.Args().Get(1),
+			Size:     volSize,
+			Speed:    protocol.VolumeSpeed(volSpeed),
+		}
+
+		volume, err := clientSession.Volume.SnapshotRestore(&def, temporal.GetExecutionTimeout())
+		if err != nil {
+			err = fail.FromGRPCStatus(err)
+			return clitools.FailureResponse(clitools.ExitOnRPC(strprocess.Capitalize(client.DecorateTimeoutError(err, "restoration of volume snapshot", true).Error())))
+		}
+		return clitools.SuccessResponse(toDisplayableVolume(volume))
+	},
+}
+
 type volumeInfoDisplayable struct {
 	ID        string
 	Name      string
@@ -270,21 +519,24 @@ type volumeInfoDisplayable struct {
 	MountPath string
 	Format    string
 	Device    string
+	Labels    map[string]string `json:",omitempty"`
 }
 
 type volumeDisplayable struct {
-	ID    string
-	Name  string
-	Speed string
-	Size  int32
+	ID     string
+	Name   string
+	Speed  string
+	Size   int32
+	Labels map[string]string `json:",omitempty"`
 }
 
 func toDisplayableVolumeInfo(volumeInfo *protocol.VolumeInspectResponse) *volumeInfoDisplayable {
 	out := &volumeInfoDisplayable{
-		ID:    volumeInfo.GetId(),
-		Name:  volumeInfo.GetName(),
-		Speed: protocol.VolumeSpeed_name[int32(volumeInfo.GetSpeed())],
-		Size:  volumeInfo.GetSize(),
+		ID:     volumeInfo.GetId(),
+		Name:   volumeInfo.GetName(),
+		Speed:  protocol.VolumeSpeed_name[int32(volumeInfo.GetSpeed())],
+		Size:   volumeInfo.GetSize(),
+		Labels: volumeInfo.GetLabels(),
 	}
 	attachments := volumeInfo.GetAttachments()
 	if len(attachments) > 0 {
@@ -304,6 +556,7 @@ func toDisplayableVolume(volumeInfo *protocol.VolumeInspectResponse) *volumeDisp
 		volumeInfo.GetName(),
 		protocol.VolumeSpeed_name[int32(volumeInfo.GetSpeed())],
 		volumeInfo.GetSize(),
+		volumeInfo.GetLabels(),
 	}
 }
 
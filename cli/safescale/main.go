@@ -136,6 +136,31 @@ func main() {
 			Aliases: []string{"T"},
 			Usage:   "Use tenant TENANT (default: none)",
 		},
+		&cli.BoolFlag{
+			Name:    "tls",
+			Usage:   "Connect to daemon using TLS (implied by the other --tls-* options)",
+			EnvVars: []string{"SAFESCALE_TLS"},
+		},
+		&cli.StringFlag{
+			Name:    "tls-ca",
+			Usage:   "Verify the certificate of daemon with the CA in `FILE` (default: CAs of the system)",
+			EnvVars: []string{"SAFESCALE_TLS_CA"},
+		},
+		&cli.StringFlag{
+			Name:    "tls-cert",
+			Usage:   "Present the client certificate in `FILE` to daemon (mutual TLS)",
+			EnvVars: []string{"SAFESCALE_TLS_CERT"},
+		},
+		&cli.StringFlag{
+			Name:    "tls-key",
+			Usage:   "Use the key in `FILE` for the client certificate",
+			EnvVars: []string{"SAFESCALE_TLS_KEY"},
+		},
+		&cli.StringFlag{
+			Name:    "tls-server-name",
+			Usage:   "Expect `NAME` in the certificate of daemon instead of the host part of --server",
+			EnvVars: []string{"SAFESCALE_TLS_SERVER_NAME"},
+		},
 	}
 
 	app.Before = func(c *cli.Context) error {
@@ -174,7 +199,7 @@ func main() {
 			}
 		}
 
-		clientSession, err = client.New(c.String("server"))
+		clientSession, err = client.New(c.String("server"), commands.ClientOptions(c)...)
 		if err != nil {
 			return err
 		}
@@ -209,6 +234,9 @@ func main() {
 	app.Commands = append(app.Commands, commands.VolumeCommand)
 	sort.Sort(cli.CommandsByName(commands.VolumeCommand.Subcommands))
 
+	app.Commands = append(app.Commands, commands.PublicIPCommand)
+	sort.Sort(cli.CommandsByName(commands.PublicIPCommand.Subcommands))
+
 	app.Commands = append(app.Commands, commands.SSHCommand)
 	sort.Sort(cli.CommandsByName(commands.SSHCommand.Subcommands))
 
@@ -227,6 +255,15 @@ func main() {
 	app.Commands = append(app.Commands, commands.ClusterCommand)
 	sort.Sort(cli.CommandsByName(commands.ClusterCommand.Subcommands))
 
+	app.Commands = append(app.Commands, commands.JobCommand)
+	sort.Sort(cli.CommandsByName(commands.JobCommand.Subcommands))
+
+	app.Commands = append(app.Commands, commands.AuditCommand)
+	sort.Sort(cli.CommandsByName(commands.AuditCommand.Subcommands))
+
+	app.Commands = append(app.Commands, commands.ApplyCommand)
+	app.Commands = append(app.Commands, commands.DestroyCommand)
+
 	sort.Sort(cli.CommandsByName(app.Commands))
 
 	err := app.RunContext(mainCtx, os.Args)
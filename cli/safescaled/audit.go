@@ -0,0 +1,58 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+
+	"github.com/CS-SI/SafeScale/lib/server/audit"
+	"github.com/CS-SI/SafeScale/lib/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+const defaultAuditFile = "$HOME/.safescale/audit.jsonl"
+
+// auditFlags returns the flags configuring the audit log
+func auditFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    "audit-file",
+			Usage:   "Append the audit records of mutating operations to `FILE` (default: " + defaultAuditFile + ")",
+			EnvVars: []string{"SAFESCALED_AUDIT_FILE"},
+		},
+		&cli.BoolFlag{
+			Name:    "audit-bucket",
+			Usage:   "Copy also each audit record in folder '" + audit.BucketFolder + "' of the metadata bucket of the tenant",
+			EnvVars: []string{"SAFESCALED_AUDIT_BUCKET"},
+		},
+	}
+}
+
+// auditRecorder returns the recorder of the audit log corresponding to audit flags
+func auditRecorder(c *cli.Context) (*audit.Recorder, fail.Error) {
+	file := c.String("audit-file")
+	if file == "" {
+		file = defaultAuditFile
+	}
+	recorder, xerr := audit.NewRecorder(utils.AbsPathify(file), c.Bool("audit-bucket"))
+	if xerr != nil {
+		return nil, fail.Wrap(xerr, "failed to initialize audit log")
+	}
+	logrus.Infof("Recording audit of mutating operations in '%s'", recorder.File())
+	return recorder, nil
+}
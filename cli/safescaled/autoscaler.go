@@ -0,0 +1,66 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/operations"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+const defaultAutoscalerInterval = time.Minute
+
+// autoscalerFlags returns the flags configuring the cluster autoscaler
+func autoscalerFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.DurationFlag{
+			Name:    "autoscaler-interval",
+			Usage:   "Evaluate every `DURATION` the load of the clusters of all the tenants having autoscaling enabled; 0 disables the autoscaler",
+			Value:   defaultAutoscalerInterval,
+			EnvVars: []string{"SAFESCALED_AUTOSCALER_INTERVAL"},
+		},
+	}
+}
+
+// startAutoscaler starts in background the loop expanding or shrinking the clusters having autoscaling enabled
+// (cf. 'safescale cluster autoscaling')
+func startAutoscaler(c *cli.Context) {
+	interval := c.Duration("autoscaler-interval")
+	if interval <= 0 {
+		logrus.Infof("Cluster autoscaler disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		// a tick happening while clusters are scaled is dropped by the ticker
+		tenants := newTenantLoop("cluster autoscaler")
+		for range ticker.C {
+			tenants.forEach(func(tenant *operations.Tenant) fail.Error {
+				return operations.AutoscaleClusters(context.Background(), tenant.Service)
+			})
+		}
+	}()
+	logrus.Infof("Cluster autoscaler evaluating clusters every %s", interval)
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/operations"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+const defaultHealthCheckInterval = 5 * time.Minute
+
+// healthCheckerFlags returns the flags configuring the cluster health checker
+func healthCheckerFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.DurationFlag{
+			Name:    "health-check-interval",
+			Usage:   "Check every `DURATION` the masters and nodes of the clusters of all the tenants; 0 disables the health checker",
+			Value:   defaultHealthCheckInterval,
+			EnvVars: []string{"SAFESCALED_HEALTH_CHECK_INTERVAL"},
+		},
+	}
+}
+
+// startHealthChecker starts in background the loop checking the health of the clusters and replacing their failed nodes
+// when auto repair is enabled (cf. 'safescale cluster health')
+func startHealthChecker(c *cli.Context) {
+	interval := c.Duration("health-check-interval")
+	if interval <= 0 {
+		logrus.Infof("Cluster health checker disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		// a tick happening while clusters are checked or repaired is dropped by the ticker
+		tenants := newTenantLoop("cluster health checker")
+		for range ticker.C {
+			tenants.forEach(func(tenant *operations.Tenant) fail.Error {
+				return operations.CheckClustersHealth(context.Background(), tenant.Service)
+			})
+		}
+	}()
+	logrus.Infof("Cluster health checker checking clusters every %s", interval)
+}
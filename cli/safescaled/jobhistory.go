@@ -0,0 +1,94 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+
+	"github.com/CS-SI/SafeScale/lib/server"
+	"github.com/CS-SI/SafeScale/lib/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+const (
+	defaultJobHistoryDir       = "$HOME/.safescale/jobs"
+	defaultJobHistoryRetention = 30 * 24 * time.Hour
+)
+
+// jobHistoryFlags returns the flags configuring job history
+func jobHistoryFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    "job-history",
+			Usage:   "Keep the jobs doing changes in folder `DIR` (default: " + defaultJobHistoryDir + ")",
+			EnvVars: []string{"SAFESCALED_JOB_HISTORY"},
+		},
+		&cli.DurationFlag{
+			Name:    "job-history-retention",
+			Usage:   "Forget the jobs ended for more than `DURATION`; interrupted jobs are kept",
+			Value:   defaultJobHistoryRetention,
+			EnvVars: []string{"SAFESCALED_JOB_HISTORY_RETENTION"},
+		},
+	}
+}
+
+// useJobHistory sets up the job history corresponding to job history flags, then reports the jobs interrupted by
+// the previous stop of safescaled
+func useJobHistory(c *cli.Context) fail.Error {
+	dir := c.String("job-history")
+	if dir == "" {
+		dir = defaultJobHistoryDir
+	}
+	history, xerr := server.NewJobHistory(utils.AbsPathify(dir))
+	if xerr != nil {
+		return fail.Wrap(xerr, "failed to initialize job history")
+	}
+
+	if retention := c.Duration("job-history-retention"); retention > 0 {
+		if xerr = history.Purge(time.Now().Add(-retention)); xerr != nil {
+			logrus.Warnf("failed to purge job history: %v", xerr)
+		}
+	}
+
+	interrupted, xerr := history.Recover()
+	if xerr != nil {
+		return fail.Wrap(xerr, "failed to recover job history")
+	}
+	for _, v := range interrupted {
+		msg := "job '" + v.ID + "' (" + v.Description + ") has been interrupted by the stop of safescaled; review "
+		switch {
+		case len(v.References) > 0:
+			msg += "'" + strings.Join(v.References, "', '") + "'"
+		case v.Resource != "":
+			msg += "'" + v.Resource + "'"
+		default:
+			msg += "its resources"
+		}
+		if len(v.Hosts) > 0 {
+			msg += " and hosts '" + strings.Join(v.Hosts, "', '") + "'"
+		}
+		logrus.Warn(msg + " (see 'safescale job inspect " + v.ID + "')")
+	}
+
+	server.UseJobHistory(history)
+	logrus.Infof("Keeping jobs in '%s'", history.Dir())
+	return nil
+}
@@ -35,8 +35,11 @@ import (
 
 	"github.com/CS-SI/SafeScale/lib/protocol"
 	_ "github.com/CS-SI/SafeScale/lib/server"
+	"github.com/CS-SI/SafeScale/lib/server/audit"
 	"github.com/CS-SI/SafeScale/lib/server/iaas"
 	"github.com/CS-SI/SafeScale/lib/server/listeners"
+	"github.com/CS-SI/SafeScale/lib/server/rbac"
+	"github.com/CS-SI/SafeScale/lib/server/resources/operations"
 	app2 "github.com/CS-SI/SafeScale/lib/utils/app"
 	"github.com/CS-SI/SafeScale/lib/utils/debug"
 	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
@@ -95,14 +98,52 @@ func work(c *cli.Context) {
 		}
 	}
 
+	serverOptions, xerr := tlsServerOptions(c, listen)
+	if xerr != nil {
+		logrus.Fatalf(xerr.Error())
+	}
+	tlsEnabled := serverOptions != nil
+
+	// audit interceptor comes first, to record also the calls refused by access control
+	auditor, xerr := auditRecorder(c)
+	if xerr != nil {
+		logrus.Fatalf(xerr.Error())
+	}
+	serverOptions = append(serverOptions, grpc.ChainUnaryInterceptor(audit.UnaryServerInterceptor(auditor, currentTenantName)))
+	if xerr = useJobHistory(c); xerr != nil {
+		logrus.Fatalf(xerr.Error())
+	}
+	startAutoscaler(c)
+	startHealthChecker(c)
+
+	if c.String("rbac-policy") != "" {
+		policy, xerr := rbac.LoadPolicy(c.String("rbac-policy"))
+		if xerr != nil {
+			logrus.Fatalf(xerr.Error())
+		}
+		if !c.Bool("tls-bootstrap") && c.String("tls-client-ca") == "" {
+			logrus.Warnf("Mutual TLS is not enabled: every caller will be considered as '%s' by the access policy", rbac.AnonymousIdentity)
+		}
+		logrus.Infof("Access control enabled using policy '%s'", c.String("rbac-policy"))
+		resolver := newResourceResolver()
+		serverOptions = append(serverOptions,
+			grpc.ChainUnaryInterceptor(rbac.UnaryServerInterceptor(policy, currentTenantName, resolver)),
+			grpc.ChainStreamInterceptor(rbac.StreamServerInterceptor(policy, currentTenantName, resolver)),
+		)
+	}
+	if !tlsEnabled && !strings.HasPrefix(listen, defaultDaemonHost+":") && !strings.HasPrefix(listen, "127.0.0.1:") {
+		logrus.Warnf("TLS is not enabled: anyone able to reach '%s' can use safescaled", listen)
+	}
+
 	logrus.Infof("Starting server, listening on '%s', using metadata suffix '%s'", listen, suffix)
 	lis, err := net.Listen("tcp", listen)
 	if err != nil {
 		logrus.Fatalf("failed to listen: %v", err)
 	}
-	s := grpc.NewServer()
+	s := grpc.NewServer(serverOptions...)
 
 	logrus.Infoln("Registering services")
+	protocol.RegisterAuditServiceServer(s, &listeners.AuditListener{Recorder: auditor})
 	protocol.RegisterBucketServiceServer(s, &listeners.BucketListener{})
 	protocol.RegisterClusterServiceServer(s, &listeners.ClusterListener{})
 	protocol.RegisterHostServiceServer(s, &listeners.HostListener{})
@@ -110,6 +151,8 @@ func work(c *cli.Context) {
 	protocol.RegisterImageServiceServer(s, &listeners.ImageListener{})
 	protocol.RegisterJobServiceServer(s, &listeners.JobManagerListener{})
 	protocol.RegisterNetworkServiceServer(s, &listeners.NetworkListener{})
+	protocol.RegisterPublicIPServiceServer(s, &listeners.PublicIPListener{})
+	protocol.RegisterManifestServiceServer(s, &listeners.ManifestListener{})
 	protocol.RegisterSubnetServiceServer(s, &listeners.SubnetListener{})
 	protocol.RegisterSecurityGroupServiceServer(s, &listeners.SecurityGroupListener{})
 	protocol.RegisterShareServiceServer(s, &listeners.ShareListener{})
@@ -134,6 +177,14 @@ func work(c *cli.Context) {
 	}
 }
 
+// currentTenantName returns the name of the tenant currently set, used when a request does not specify one
+func currentTenantName() string {
+	if tenant := operations.CurrentTenant(); tenant != nil {
+		return tenant.Name
+	}
+	return ""
+}
+
 // assembleListenString constructs the listen string we will use in net.Listen()
 func assembleListenString(c *cli.Context) string {
 	// Get listen from parameters
@@ -222,6 +273,16 @@ func main() {
 			Usage:   "Listen on specified port `IP:PORT` (default: localhost:50051)",
 		},
 	}
+	app.Flags = append(app.Flags, tlsFlags()...)
+	app.Flags = append(app.Flags, auditFlags()...)
+	app.Flags = append(app.Flags, jobHistoryFlags()...)
+	app.Flags = append(app.Flags, autoscalerFlags()...)
+	app.Flags = append(app.Flags, healthCheckerFlags()...)
+	app.Flags = append(app.Flags, &cli.StringFlag{
+		Name:    "rbac-policy",
+		Usage:   "Enable access control using the policy in `FILE` (YAML, JSON or TOML)",
+		EnvVars: []string{"SAFESCALED_RBAC_POLICY"},
+	})
 
 	app.Before = func(c *cli.Context) error {
 		// Sets profiling
@@ -253,6 +314,10 @@ func main() {
 	}
 
 	app.Action = func(c *cli.Context) error {
+		if c.IsSet("tls-issue-client") {
+			return issueClientCertificate(c)
+		}
+
 		work(c)
 		return nil
 	}
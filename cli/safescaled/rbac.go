@@ -0,0 +1,108 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"sync"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/rbac"
+	"github.com/CS-SI/SafeScale/lib/server/resources/operations"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// resourceLoaders gives, by kind of resource, the function loading a resource from its name or its ID
+var resourceLoaders = map[string]func(svc iaas.Service, ref string) (interface{ GetName() string }, fail.Error){
+	"host": func(svc iaas.Service, ref string) (interface{ GetName() string }, fail.Error) {
+		return operations.LoadHost(svc, ref)
+	},
+	"volume": func(svc iaas.Service, ref string) (interface{ GetName() string }, fail.Error) {
+		return operations.LoadVolume(svc, ref)
+	},
+	"network": func(svc iaas.Service, ref string) (interface{ GetName() string }, fail.Error) {
+		return operations.LoadNetwork(svc, ref)
+	},
+	"subnet": func(svc iaas.Service, ref string) (interface{ GetName() string }, fail.Error) {
+		return operations.LoadSubnet(svc, "", ref)
+	},
+	"securitygroup": func(svc iaas.Service, ref string) (interface{ GetName() string }, fail.Error) {
+		return operations.LoadSecurityGroup(svc, ref)
+	},
+	"share": func(svc iaas.Service, ref string) (interface{ GetName() string }, fail.Error) {
+		return operations.LoadShare(svc, ref)
+	},
+	"publicip": func(svc iaas.Service, ref string) (interface{ GetName() string }, fail.Error) {
+		return operations.LoadPublicIP(svc, ref)
+	},
+	"snapshot": func(svc iaas.Service, ref string) (interface{ GetName() string }, fail.Error) {
+		return operations.LoadSnapshot(svc, ref)
+	},
+}
+
+// resourceResolver finds in metadata the names of the resources designated by the requests, for access control
+type resourceResolver struct {
+	lock    sync.Mutex
+	tenants map[string]*operations.Tenant
+}
+
+// newResourceResolver returns the rbac.Resolver used by the access control
+func newResourceResolver() rbac.Resolver {
+	r := &resourceResolver{tenants: map[string]*operations.Tenant{}}
+	return r.resolve
+}
+
+// resolve returns the name of the resource of kind 'kind' designated by ref in tenant 'tenant'
+// Resources designated only by name (clusters, buckets, ...) are returned unchanged.
+func (r *resourceResolver) resolve(tenant, kind, ref string) (string, fail.Error) {
+	load, ok := resourceLoaders[kind]
+	if !ok {
+		return ref, nil
+	}
+	if tenant == "" {
+		return "", fail.NotFoundError("no tenant set")
+	}
+
+	t, xerr := r.tenant(tenant)
+	if xerr != nil {
+		return "", xerr
+	}
+	instance, xerr := load(t.Service, ref)
+	if xerr != nil {
+		return "", xerr
+	}
+	return instance.GetName(), nil
+}
+
+// tenant returns the tenant named 'name', loading it once
+func (r *resourceResolver) tenant(name string) (*operations.Tenant, fail.Error) {
+	if current := operations.CurrentTenant(); current != nil && current.Name == name {
+		return current, nil
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if t, ok := r.tenants[name]; ok {
+		return t, nil
+	}
+	t, xerr := operations.LoadTenant(name)
+	if xerr != nil {
+		return nil, xerr
+	}
+	r.tenants[name] = t
+	return t, nil
+}
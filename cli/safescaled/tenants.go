@@ -0,0 +1,67 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/resources/operations"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// tenantLoop gives to a background loop the tenants of the configuration, loading each of them once
+// Used by one goroutine only, it is not protected against concurrent use.
+type tenantLoop struct {
+	name    string // name of the loop, used in logs
+	tenants map[string]*operations.Tenant
+}
+
+// newTenantLoop creates a tenantLoop for the background loop named name
+func newTenantLoop(name string) *tenantLoop {
+	return &tenantLoop{name: name, tenants: map[string]*operations.Tenant{}}
+}
+
+// forEach calls fn with each tenant of the configuration
+// A tenant that cannot be loaded, or for which fn fails, is logged and skipped; it is tried again at the next call.
+func (l *tenantLoop) forEach(fn func(tenant *operations.Tenant) fail.Error) {
+	list, xerr := iaas.GetTenants()
+	if xerr != nil {
+		logrus.Warnf("%s: failed to list tenants: %v", l.name, xerr)
+		return
+	}
+
+	for _, v := range list {
+		name, ok := v["name"].(string)
+		if !ok || name == "" {
+			continue
+		}
+
+		tenant, ok := l.tenants[name]
+		if !ok {
+			if tenant, xerr = operations.LoadTenant(name); xerr != nil {
+				logrus.Warnf("%s: failed to load tenant '%s': %v", l.name, name, xerr)
+				continue
+			}
+			l.tenants[name] = tenant
+		}
+
+		if xerr = fn(tenant); xerr != nil {
+			logrus.Warnf("%s: tenant '%s': %v", l.name, name, xerr)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/CS-SI/SafeScale/lib/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/crypt"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+const defaultTLSFolder = "$HOME/.safescale/tls"
+
+// tlsFlags returns the flags configuring TLS
+func tlsFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    "tls-cert",
+			Usage:   "Enable TLS, using the server certificate in `FILE`",
+			EnvVars: []string{"SAFESCALED_TLS_CERT"},
+		},
+		&cli.StringFlag{
+			Name:    "tls-key",
+			Usage:   "Use the key in `FILE` for the server certificate",
+			EnvVars: []string{"SAFESCALED_TLS_KEY"},
+		},
+		&cli.StringFlag{
+			Name:    "tls-client-ca",
+			Usage:   "Require clients to present a certificate signed by the CA in `FILE` (mutual TLS)",
+			EnvVars: []string{"SAFESCALED_TLS_CLIENT_CA"},
+		},
+		&cli.BoolFlag{
+			Name: "tls-bootstrap",
+			Usage: `Enable mutual TLS with a self-signed CA, generated on first start with a server and a client certificate
+            in the folder defined by --tls-folder; files ca.crt, client.crt and client.key have to be given to users`,
+			EnvVars: []string{"SAFESCALED_TLS_BOOTSTRAP"},
+		},
+		&cli.StringFlag{
+			Name: "tls-issue-client",
+			Usage: `Issue a client certificate with common name ` + "`IDENTITY`" + ` (the identity used by --rbac-policy), signed by the CA
+            of --tls-bootstrap, in files client-<IDENTITY>.crt and client-<IDENTITY>.key of --tls-folder, then exit`,
+		},
+		&cli.StringFlag{
+			Name:    "tls-folder",
+			Usage:   "Folder used by --tls-bootstrap (default: " + defaultTLSFolder + ")",
+			EnvVars: []string{"SAFESCALED_TLS_FOLDER"},
+		},
+	}
+}
+
+// tlsServerOptions returns the options of the gRPC server corresponding to TLS flags
+// Returns nil if TLS is not wanted.
+func tlsServerOptions(c *cli.Context, listen string) ([]grpc.ServerOption, fail.Error) {
+	certFile := c.String("tls-cert")
+	keyFile := c.String("tls-key")
+	clientCAFile := c.String("tls-client-ca")
+
+	if c.Bool("tls-bootstrap") {
+		if certFile != "" || keyFile != "" || clientCAFile != "" {
+			return nil, fail.InvalidRequestError("--tls-bootstrap cannot be used with --tls-cert, --tls-key or --tls-client-ca")
+		}
+
+		folder := tlsFolder(c)
+		if xerr := crypt.BootstrapTLS(folder, certificateHosts(listen)); xerr != nil {
+			return nil, fail.Wrap(xerr, "failed to bootstrap TLS")
+		}
+		logrus.Infof("Using TLS material in '%s'; give '%s' and a client certificate to users (shared '%s' and '%s', or one issued by --tls-issue-client)", folder, crypt.TLSCAFile, crypt.TLSClientCertFile, crypt.TLSClientKeyFile)
+
+		certFile = filepath.Join(folder, crypt.TLSServerCertFile)
+		keyFile = filepath.Join(folder, crypt.TLSServerKeyFile)
+		clientCAFile = filepath.Join(folder, crypt.TLSCAFile)
+	}
+
+	if certFile == "" && keyFile == "" {
+		if clientCAFile != "" {
+			return nil, fail.InvalidRequestError("--tls-client-ca needs --tls-cert and --tls-key")
+		}
+		return nil, nil
+	}
+
+	config, xerr := crypt.ServerTLSConfig(certFile, keyFile, clientCAFile)
+	if xerr != nil {
+		return nil, xerr
+	}
+	if clientCAFile != "" {
+		logrus.Infof("Mutual TLS enabled, clients must present a certificate signed by '%s'", clientCAFile)
+	} else {
+		logrus.Infof("TLS enabled")
+	}
+	return []grpc.ServerOption{grpc.Creds(credentials.NewTLS(config))}, nil
+}
+
+// tlsFolder returns the absolute path of the folder used by --tls-bootstrap
+func tlsFolder(c *cli.Context) string {
+	folder := c.String("tls-folder")
+	if folder == "" {
+		folder = defaultTLSFolder
+	}
+	return utils.AbsPathify(folder)
+}
+
+// issueClientCertificate issues the client certificate asked by --tls-issue-client
+func issueClientCertificate(c *cli.Context) fail.Error {
+	identity := c.String("tls-issue-client")
+	certPath, keyPath, xerr := crypt.IssueClientCertificate(tlsFolder(c), identity)
+	if xerr != nil {
+		return fail.Wrap(xerr, "failed to issue client certificate for '%s'", identity)
+	}
+	fmt.Printf("Client certificate of '%s' written in '%s' and '%s'\n", identity, certPath, keyPath)
+	return nil
+}
+
+// certificateHosts returns the names and addresses the bootstrapped server certificate is valid for
+func certificateHosts(listen string) []string {
+	hosts := []string{"localhost", "127.0.0.1", "::1"}
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		hosts = append(hosts, hostname)
+	}
+	if host, _, err := net.SplitHostPort(listen); err == nil && host != "" && host != "localhost" && host != "0.0.0.0" && host != "::" {
+		hosts = append(hosts, host)
+	}
+	return hosts
+}
@@ -0,0 +1,48 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"time"
+
+	"github.com/CS-SI/SafeScale/lib/protocol"
+	"github.com/CS-SI/SafeScale/lib/server/utils"
+)
+
+// audit is the part of safescale client querying the audit log
+type audit struct {
+	// session is not used currently
+	session *Session
+}
+
+// List returns the audit records newer than since (if not zero) on the resources matching the pattern resource (if not empty)
+func (a audit) List(since time.Time, resource string, timeout time.Duration) (*protocol.AuditListResponse, error) {
+	a.session.Connect()
+	defer a.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	req := &protocol.AuditListRequest{Resource: resource}
+	if !since.IsZero() {
+		req.Since = since.Format(time.RFC3339)
+	}
+	service := protocol.NewAuditServiceClient(a.session.connection)
+	return service.List(ctx, req)
+}
@@ -17,6 +17,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strconv"
@@ -25,21 +26,27 @@ import (
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/status"
 
+	"github.com/CS-SI/SafeScale/lib/server/utils"
 	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/crypt"
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
 	"github.com/CS-SI/SafeScale/lib/utils/temporal"
 )
 
 // Session units the different resources proposed by safescaled as safescale client
 type Session struct {
+	Audit         audit
 	Bucket        bucket
 	Cluster       cluster
 	Host          host
 	Image         image
 	JobManager    jobManager
+	Manifest      manifest
 	Network       network
+	PublicIP      publicIP
 	SecurityGroup securityGroup
 	Share         share
 	SSH           ssh
@@ -48,8 +55,10 @@ type Session struct {
 	Tenant        tenant
 	Volume        volume
 
-	server     string
-	connection *grpc.ClientConn
+	server      string
+	connection  *grpc.ClientConn
+	credentials grpc.DialOption
+	async       bool
 
 	tenantName string
 
@@ -67,8 +76,60 @@ const (
 	defaultServerPort string = "50051"
 )
 
+// TLSConfig contains the settings used to secure the connection with safescaled
+type TLSConfig struct {
+	CAFile     string // CA used to verify the certificate of safescaled; if empty, the CAs of the system are used
+	CertFile   string // client certificate presented to safescaled when mutual TLS is required
+	KeyFile    string // key of the client certificate
+	ServerName string // overrides the name expected in the certificate of safescaled
+}
+
+// Option is an option of New
+type Option func(*Session) fail.Error
+
+// WithTLS makes the Session connect to safescaled using TLS
+func WithTLS(config TLSConfig) Option {
+	return func(s *Session) fail.Error {
+		tlsConfig, xerr := crypt.ClientTLSConfig(config.CAFile, config.CertFile, config.KeyFile, config.ServerName)
+		if xerr != nil {
+			return fail.Wrap(xerr, "invalid TLS configuration")
+		}
+		s.credentials = grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))
+		return nil
+	}
+}
+
+// WithAsync makes the long operations of the Session (creation, deletion and resizing of clusters, creation of hosts,
+// application and destruction of manifests) return as soon as safescaled has started them; their progress is then
+// followed with JobManager, using the id of the job returned by JobID()
+func WithAsync() Option {
+	return func(s *Session) fail.Error {
+		s.async = true
+		return nil
+	}
+}
+
+// JobID returns the id of the job of the last call done by the session
+func (s *Session) JobID() string {
+	return utils.GetUUID()
+}
+
+// asyncContext returns the context of a call to a long operation, asking for an asynchronous execution if the session
+// has been created with WithAsync()
+func (s *Session) asyncContext() (context.Context, fail.Error) {
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+	if s.async {
+		ctx = utils.WithAsync(ctx)
+	}
+	return ctx, nil
+}
+
 // New returns an instance of safescale Client
-func New(server string) (_ *Session, xerr fail.Error) {
+// Without option, the connection with safescaled is not encrypted.
+func New(server string, options ...Option) (_ *Session, xerr fail.Error) {
 	// Validate server parameter (can be empty string...)
 	if server != "" {
 		if server, xerr = validateServerString(server); xerr != nil {
@@ -102,17 +163,26 @@ func New(server string) (_ *Session, xerr fail.Error) {
 		}
 	}
 
-	s := &Session{server: server}
+	s := &Session{server: server, credentials: grpc.WithInsecure()}
+	for _, opt := range options {
+		if xerr = opt(s); xerr != nil {
+			return nil, xerr
+		}
+	}
+
 	s.task, xerr = concurrency.VoidTask()
 	if xerr != nil {
 		return nil, xerr
 	}
 
+	s.Audit = audit{session: s}
 	s.Bucket = bucket{session: s}
 	s.Cluster = cluster{session: s}
 	s.Host = host{session: s}
 	s.Image = image{session: s}
+	s.Manifest = manifest{session: s}
 	s.Network = network{session: s}
+	s.PublicIP = publicIP{session: s}
 	s.Subnet = subnet{session: s}
 	s.JobManager = jobManager{session: s}
 	s.SecurityGroup = securityGroup{session: s}
@@ -154,14 +224,14 @@ func validateServerString(server string) (string, fail.Error) {
 // Connect establishes connection with safescaled
 func (s *Session) Connect() {
 	if s.connection == nil {
-		s.connection = dial(s.server)
+		s.connection = dial(s.server, s.credentials)
 	}
 }
 
 // dial returns a connection to GRPC server
-func dial(server string) *grpc.ClientConn {
+func dial(server string, credentials grpc.DialOption) *grpc.ClientConn {
 	// Set up a connection to the server.
-	conn, err := grpc.Dial(server, grpc.WithInsecure())
+	conn, err := grpc.Dial(server, credentials)
 	if err != nil {
 		logrus.Fatalf("failed to connect to safescaled (%s): %v", server, err)
 	}
@@ -17,12 +17,16 @@
 package client
 
 import (
+	"fmt"
+	"net"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/CS-SI/SafeScale/lib/protocol"
 	"github.com/CS-SI/SafeScale/lib/server/utils"
+	"github.com/CS-SI/SafeScale/lib/system/sshtunnel"
 	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
 	googleprotobuf "github.com/golang/protobuf/ptypes/empty"
@@ -36,8 +40,8 @@ type cluster struct {
 	session *Session
 }
 
-// List ...
-func (c cluster) List(timeout time.Duration) (*protocol.ClusterListResponse, error) {
+// List returns the clusters, restricted to the ones matching all the labels if any
+func (c cluster) List(labels map[string]string, timeout time.Duration) (*protocol.ClusterListResponse, error) {
 	c.session.Connect()
 	defer c.session.Disconnect()
 
@@ -47,7 +51,7 @@ func (c cluster) List(timeout time.Duration) (*protocol.ClusterListResponse, err
 		return nil, xerr
 	}
 
-	result, err := service.List(ctx, &protocol.Reference{})
+	result, err := service.List(ctx, &protocol.ClusterListRequest{Labels: labels})
 	if err != nil {
 		return nil, err
 	}
@@ -130,7 +134,7 @@ func (c cluster) Create(def *protocol.ClusterCreateRequest, timeout time.Duratio
 	c.session.Connect()
 	defer c.session.Disconnect()
 
-	ctx, xerr := utils.GetContext(true)
+	ctx, xerr := c.session.asyncContext()
 	if xerr != nil {
 		return nil, xerr
 	}
@@ -139,6 +143,24 @@ func (c cluster) Create(def *protocol.ClusterCreateRequest, timeout time.Duratio
 	return service.Create(ctx, def)
 }
 
+// Resume resumes an unfinished creation of a cluster from its last successful step
+func (c cluster) Resume(clusterName string, timeout time.Duration) (*protocol.ClusterResponse, error) {
+	if clusterName == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("clusterName")
+	}
+
+	c.session.Connect()
+	defer c.session.Disconnect()
+
+	ctx, xerr := c.session.asyncContext()
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewClusterServiceClient(c.session.connection)
+	return service.Resume(ctx, &protocol.Reference{Name: clusterName})
+}
+
 // Delete deletes a cluster
 func (c cluster) Delete(clusterName string, force bool, timeout time.Duration) error {
 	if clusterName == "" {
@@ -148,7 +170,7 @@ func (c cluster) Delete(clusterName string, force bool, timeout time.Duration) e
 	c.session.Connect()
 	defer c.session.Disconnect()
 
-	ctx, xerr := utils.GetContext(true)
+	ctx, xerr := c.session.asyncContext()
 	if xerr != nil {
 		return xerr
 	}
@@ -171,7 +193,7 @@ func (c cluster) Expand(req *protocol.ClusterResizeRequest, duration time.Durati
 	c.session.Connect()
 	defer c.session.Disconnect()
 
-	ctx, xerr := utils.GetContext(true)
+	ctx, xerr := c.session.asyncContext()
 	if xerr != nil {
 		return nil, xerr
 	}
@@ -189,7 +211,7 @@ func (c cluster) Shrink(req *protocol.ClusterResizeRequest, duration time.Durati
 	c.session.Connect()
 	defer c.session.Disconnect()
 
-	ctx, xerr := utils.GetContext(true)
+	ctx, xerr := c.session.asyncContext()
 	if xerr != nil {
 		return nil, xerr
 	}
@@ -284,6 +306,35 @@ func (c cluster) RemoveFeature(clusterName, featureName string, params map[strin
 	return err
 }
 
+// UpgradeFeature updates a Feature installed on the cluster to the version of its specification file
+func (c cluster) UpgradeFeature(clusterName, featureName string, params map[string]string, settings *protocol.FeatureSettings, duration time.Duration) error {
+	if clusterName == "" {
+		return fail.InvalidParameterError("clusterName", "cannot be empty string")
+	}
+	if featureName == "" {
+		return fail.InvalidParameterError("featureName", "cannot be empty string")
+	}
+
+	c.session.Connect()
+	defer c.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return xerr
+	}
+
+	req := &protocol.FeatureActionRequest{
+		Name:       featureName,
+		TargetType: protocol.FeatureTargetType_FT_CLUSTER,
+		TargetRef:  &protocol.Reference{Name: clusterName},
+		Variables:  params,
+		Settings:   settings,
+	}
+	service := protocol.NewFeatureServiceClient(c.session.connection)
+	_, err := service.Upgrade(ctx, req)
+	return err
+}
+
 // ListInstalledFeatures ...
 func (c cluster) ListInstalledFeatures(clusterName string, all bool, duration time.Duration) (*protocol.FeatureListResponse, error) {
 	if clusterName == "" {
@@ -605,3 +656,327 @@ func (c cluster) StateMaster(clusterName string, masterRef string, duration time
 	service := protocol.NewClusterServiceClient(c.session.connection)
 	return service.StateMaster(ctx, &protocol.ClusterNodeRequest{Name: clusterName, Host: &protocol.Reference{Name: masterRef}})
 }
+
+// AddNodePool creates a node pool in a cluster
+func (c cluster) AddNodePool(req *protocol.ClusterNodePoolCreateRequest, duration time.Duration) (*protocol.ClusterNodePool, error) {
+	if req == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("req")
+	}
+
+	c.session.Connect()
+	defer c.session.Disconnect()
+
+	ctx, xerr := c.session.asyncContext()
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewClusterServiceClient(c.session.connection)
+	return service.AddNodePool(ctx, req)
+}
+
+// ListNodePools lists the node pools of a cluster
+func (c cluster) ListNodePools(clusterName string, duration time.Duration) (*protocol.ClusterNodePoolListResponse, error) {
+	if clusterName == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("clusterName")
+	}
+
+	c.session.Connect()
+	defer c.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewClusterServiceClient(c.session.connection)
+	return service.ListNodePools(ctx, &protocol.Reference{Name: clusterName})
+}
+
+// ResizeNodePool adds or removes nodes of a node pool to reach the requested count
+func (c cluster) ResizeNodePool(req *protocol.ClusterNodePoolRequest, duration time.Duration) (*protocol.ClusterNodePool, error) {
+	if req == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("req")
+	}
+
+	c.session.Connect()
+	defer c.session.Disconnect()
+
+	ctx, xerr := c.session.asyncContext()
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewClusterServiceClient(c.session.connection)
+	return service.ResizeNodePool(ctx, req)
+}
+
+// DeleteNodePool deletes a node pool of a cluster, with its nodes
+func (c cluster) DeleteNodePool(clusterName, poolName string, duration time.Duration) error {
+	if clusterName == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("clusterName")
+	}
+	if poolName == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("poolName")
+	}
+
+	c.session.Connect()
+	defer c.session.Disconnect()
+
+	ctx, xerr := c.session.asyncContext()
+	if xerr != nil {
+		return xerr
+	}
+
+	service := protocol.NewClusterServiceClient(c.session.connection)
+	_, err := service.DeleteNodePool(ctx, &protocol.ClusterNodePoolRequest{Name: clusterName, PoolName: poolName})
+	return err
+}
+
+// InspectAutoscaling returns the autoscaling settings of a cluster, with its last scale decisions
+func (c cluster) InspectAutoscaling(clusterName string, duration time.Duration) (*protocol.ClusterAutoscaling, error) {
+	if clusterName == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("clusterName")
+	}
+
+	c.session.Connect()
+	defer c.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewClusterServiceClient(c.session.connection)
+	return service.InspectAutoscaling(ctx, &protocol.Reference{Name: clusterName})
+}
+
+// SetAutoscaling replaces the autoscaling settings of a cluster
+func (c cluster) SetAutoscaling(req *protocol.ClusterAutoscaling, duration time.Duration) (*protocol.ClusterAutoscaling, error) {
+	if req == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("req")
+	}
+	if req.GetName() == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("req.Name")
+	}
+
+	c.session.Connect()
+	defer c.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewClusterServiceClient(c.session.connection)
+	return service.SetAutoscaling(ctx, req)
+}
+
+// Upgrade upgrades the hosts of a cluster, or resumes the last upgrade
+func (c cluster) Upgrade(req *protocol.ClusterUpgradeRequest, duration time.Duration) (*protocol.ClusterUpgradeResponse, error) {
+	if req == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("req")
+	}
+	if req.GetName() == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("req.Name")
+	}
+
+	c.session.Connect()
+	defer c.session.Disconnect()
+
+	ctx, xerr := c.session.asyncContext()
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewClusterServiceClient(c.session.connection)
+	return service.Upgrade(ctx, req)
+}
+
+// InspectUpgrade returns the progress of the last upgrade of a cluster
+func (c cluster) InspectUpgrade(clusterName string, duration time.Duration) (*protocol.ClusterUpgradeResponse, error) {
+	if clusterName == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("clusterName")
+	}
+
+	c.session.Connect()
+	defer c.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewClusterServiceClient(c.session.connection)
+	return service.InspectUpgrade(ctx, &protocol.Reference{Name: clusterName})
+}
+
+// Backup saves the control plane of a cluster in a bucket
+func (c cluster) Backup(req *protocol.ClusterBackupRequest, duration time.Duration) (*protocol.ClusterBackup, error) {
+	if req == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("req")
+	}
+	if req.GetName() == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("req.Name")
+	}
+
+	c.session.Connect()
+	defer c.session.Disconnect()
+
+	ctx, xerr := c.session.asyncContext()
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewClusterServiceClient(c.session.connection)
+	return service.Backup(ctx, req)
+}
+
+// ListBackups lists the backups of a cluster
+func (c cluster) ListBackups(clusterName string, duration time.Duration) (*protocol.ClusterBackupList, error) {
+	if clusterName == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("clusterName")
+	}
+
+	c.session.Connect()
+	defer c.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewClusterServiceClient(c.session.connection)
+	return service.ListBackups(ctx, &protocol.Reference{Name: clusterName})
+}
+
+// Restore rebuilds the control plane of a cluster from a backup
+func (c cluster) Restore(req *protocol.ClusterBackupRequest, duration time.Duration) (*protocol.ClusterBackup, error) {
+	if req == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("req")
+	}
+	if req.GetName() == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("req.Name")
+	}
+
+	c.session.Connect()
+	defer c.session.Disconnect()
+
+	ctx, xerr := c.session.asyncContext()
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewClusterServiceClient(c.session.connection)
+	return service.Restore(ctx, req)
+}
+
+// InspectHealth returns the health check settings of a cluster, the health of its masters and nodes and its last repairs
+func (c cluster) InspectHealth(clusterName string, duration time.Duration) (*protocol.ClusterHealth, error) {
+	if clusterName == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("clusterName")
+	}
+
+	c.session.Connect()
+	defer c.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewClusterServiceClient(c.session.connection)
+	return service.InspectHealth(ctx, &protocol.Reference{Name: clusterName})
+}
+
+// SetHealth replaces the health check settings of a cluster
+func (c cluster) SetHealth(req *protocol.ClusterHealth, duration time.Duration) (*protocol.ClusterHealth, error) {
+	if req == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("req")
+	}
+	if req.GetName() == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("req.Name")
+	}
+
+	c.session.Connect()
+	defer c.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewClusterServiceClient(c.session.connection)
+	return service.SetHealth(ctx, req)
+}
+
+// CheckHealth checks the masters and nodes of a cluster now, replacing the failed nodes if auto repair is enabled
+func (c cluster) CheckHealth(clusterName string, duration time.Duration) (*protocol.ClusterHealth, error) {
+	if clusterName == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("clusterName")
+	}
+
+	c.session.Connect()
+	defer c.session.Disconnect()
+
+	ctx, xerr := c.session.asyncContext()
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewClusterServiceClient(c.session.connection)
+	return service.CheckHealth(ctx, &protocol.Reference{Name: clusterName})
+}
+
+// Kubeconfig returns the kubeconfig of the admin of a cluster, whose API server is 'server' or the endpoint of the cluster if empty
+func (c cluster) Kubeconfig(clusterName, server string, duration time.Duration) (*protocol.ClusterKubeconfig, error) {
+	if clusterName == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("clusterName")
+	}
+
+	c.session.Connect()
+	defer c.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewClusterServiceClient(c.session.connection)
+	return service.Kubeconfig(ctx, &protocol.ClusterKubeconfigRequest{Name: clusterName, Server: server})
+}
+
+// KubeconfigTunnel returns a SSH tunnel through the gateway described in 'kc', forwarding 'localAddress' to the API server of
+// the cluster; the tunnel has to be started by the caller
+func (c cluster) KubeconfigTunnel(kc *protocol.ClusterKubeconfig, localAddress string) (*sshtunnel.SSHTunnel, error) {
+	if kc == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("kc")
+	}
+	if kc.GetGateway() == "" || kc.GetApiServer() == "" {
+		return nil, fail.InvalidParameterError("kc", "must contain gateway and API server")
+	}
+	if localAddress == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("localAddress")
+	}
+
+	cfg, err := host{session: c.session}.SSHConfig(kc.GetGateway())
+	if err != nil {
+		return nil, err
+	}
+
+	auth, err := sshtunnel.AuthMethodFromPrivateKey([]byte(cfg.PrivateKey), nil)
+	if err != nil {
+		return nil, fail.Wrap(err, "failed to parse private key of gateway '%s'", kc.GetGateway())
+	}
+
+	port := cfg.Port
+	if port == 0 {
+		port = 22
+	}
+	gateway := fmt.Sprintf("%s@%s", cfg.User, net.JoinHostPort(cfg.IPAddress, strconv.Itoa(port)))
+	tunnel, err := sshtunnel.NewSSHTunnelWithLocalBinding(gateway, auth, kc.GetApiServer(), localAddress, sshtunnel.TunnelOptionWithDefaultKeepAlive())
+	if err != nil {
+		return nil, fail.ConvertError(err)
+	}
+	return tunnel, nil
+}
@@ -37,8 +37,8 @@ type host struct {
 	session *Session
 }
 
-// List ...
-func (h host) List(all bool, timeout time.Duration) (*protocol.HostList, error) {
+// List returns the hosts, restricted to the ones matching all the labels if any
+func (h host) List(all bool, labels map[string]string, timeout time.Duration) (*protocol.HostList, error) {
 	h.session.Connect()
 	defer h.session.Disconnect()
 
@@ -48,7 +48,7 @@ func (h host) List(all bool, timeout time.Duration) (*protocol.HostList, error)
 	}
 
 	service := protocol.NewHostServiceClient(h.session.connection)
-	return service.List(ctx, &protocol.HostListRequest{All: all})
+	return service.List(ctx, &protocol.HostListRequest{All: all, Labels: labels})
 }
 
 // Inspect ...
@@ -128,7 +128,7 @@ func (h host) Create(req *protocol.HostDefinition, timeout time.Duration) (*prot
 	h.session.Connect()
 	defer h.session.Disconnect()
 
-	ctx, xerr := utils.GetContext(true)
+	ctx, xerr := h.session.asyncContext()
 	if xerr != nil {
 		return nil, xerr
 	}
@@ -300,6 +300,28 @@ func (h host) RemoveFeature(hostRef, featureName string, params map[string]strin
 	return err
 }
 
+// UpgradeFeature updates a Feature installed on the host to the version of its specification file
+func (h host) UpgradeFeature(hostRef, featureName string, params map[string]string, settings *protocol.FeatureSettings, duration time.Duration) error {
+	h.session.Connect()
+	defer h.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return xerr
+	}
+
+	req := &protocol.FeatureActionRequest{
+		Name:       featureName,
+		TargetType: protocol.FeatureTargetType_FT_HOST,
+		TargetRef:  &protocol.Reference{Name: hostRef},
+		Variables:  params,
+		Settings:   settings,
+	}
+	service := protocol.NewFeatureServiceClient(h.session.connection)
+	_, err := service.Upgrade(ctx, req)
+	return err
+}
+
 // BindSecurityGroup calls the gRPC server to bind a security group to a host
 func (h host) BindSecurityGroup(hostRef, sgRef string, enable bool, duration time.Duration) error {
 	h.session.Connect()
@@ -411,3 +433,17 @@ func (h host) ListSecurityGroups(hostRef, state string, duration time.Duration)
 	}
 	return service.ListSecurityGroups(ctx, req)
 }
+
+// Import registers in SafeScale metadata an existing host of the provider
+func (h host) Import(req *protocol.HostImportRequest, duration time.Duration) (*protocol.Host, error) {
+	h.session.Connect()
+	defer h.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewHostServiceClient(h.session.connection)
+	return service.Import(ctx, req)
+}
@@ -17,10 +17,13 @@
 package client
 
 import (
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/CS-SI/SafeScale/lib/protocol"
 	"github.com/CS-SI/SafeScale/lib/server/utils"
+	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
 )
 
 // host is the safescale client part handling hosts
@@ -41,3 +44,58 @@ func (img image) List(all bool, timeout time.Duration) (*protocol.ImageList, err
 
 	return service.List(ctx, &protocol.ImageListRequest{All: all})
 }
+
+// Create creates a custom image from an existing host
+func (img image) Create(def *protocol.ImageCreateRequest, timeout time.Duration) (*protocol.Image, error) {
+	img.session.Connect()
+	defer img.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewImageServiceClient(img.session.connection)
+	return service.Create(ctx, def)
+}
+
+// Delete deletes custom images
+func (img image) Delete(names []string, timeout time.Duration) error {
+	img.session.Connect()
+	defer img.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return xerr
+	}
+
+	var (
+		mutex sync.Mutex
+		wg    sync.WaitGroup
+		errs  []string
+	)
+
+	service := protocol.NewImageServiceClient(img.session.connection)
+
+	imageDeleter := func(aname string) {
+		defer wg.Done()
+		_, err := service.Delete(ctx, &protocol.Reference{Name: aname})
+
+		if err != nil {
+			mutex.Lock()
+			errs = append(errs, err.Error())
+			mutex.Unlock()
+		}
+	}
+
+	wg.Add(len(names))
+	for _, target := range names {
+		go imageDeleter(target)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return clitools.ExitOnRPC(strings.Join(errs, ", "))
+	}
+	return nil
+}
@@ -17,6 +17,7 @@
 package client
 
 import (
+	"io"
 	"time"
 
 	googleprotobuf "github.com/golang/protobuf/ptypes/empty"
@@ -59,3 +60,74 @@ func (c jobManager) Stop(uuid string, timeout time.Duration) error {
 	_, err := service.Stop(ctx, &protocol.JobDefinition{Uuid: uuid})
 	return err
 }
+
+// Inspect returns the status of a job, running, recently ended or kept in job history
+func (c jobManager) Inspect(uuid string, timeout time.Duration) (*protocol.JobStatus, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+
+	service := protocol.NewJobServiceClient(c.session.connection)
+	ctx, xerr := utils.GetContext(false)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return service.Inspect(ctx, &protocol.JobDefinition{Uuid: uuid})
+}
+
+// History returns the jobs kept in job history, oldest first
+func (c jobManager) History(timeout time.Duration) (*protocol.JobStatusList, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+
+	service := protocol.NewJobServiceClient(c.session.connection)
+	ctx, xerr := utils.GetContext(false)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return service.History(ctx, &googleprotobuf.Empty{})
+}
+
+// Wait waits for the end of a job and returns its status
+func (c jobManager) Wait(uuid string, timeout time.Duration) (*protocol.JobStatus, error) {
+	c.session.Connect()
+	defer c.session.Disconnect()
+
+	service := protocol.NewJobServiceClient(c.session.connection)
+	ctx, xerr := utils.GetContext(false)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return service.Wait(ctx, &protocol.JobDefinition{Uuid: uuid})
+}
+
+// Watch calls callback for each progress event of a job (starting from the first one), until the end of the job
+func (c jobManager) Watch(uuid string, callback func(*protocol.JobProgress)) error {
+	c.session.Connect()
+	defer c.session.Disconnect()
+
+	service := protocol.NewJobServiceClient(c.session.connection)
+	ctx, xerr := utils.GetContext(false)
+	if xerr != nil {
+		return xerr
+	}
+
+	stream, err := service.Watch(ctx, &protocol.JobDefinition{Uuid: uuid})
+	if err != nil {
+		return err
+	}
+	for {
+		event, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if callback != nil {
+			callback(event)
+		}
+	}
+}
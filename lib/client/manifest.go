@@ -0,0 +1,79 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"time"
+
+	"github.com/CS-SI/SafeScale/lib/protocol"
+	"github.com/CS-SI/SafeScale/lib/server/utils"
+)
+
+// manifest is the part of safescale client handling manifests
+type manifest struct {
+	// session is not used currently
+	session *Session
+}
+
+// Plan returns the steps needed to apply (or to destroy if destroy is true) the content of a manifest
+// prune tells if the resources removed from the manifest since its last apply would be deleted by apply
+func (m manifest) Plan(content []byte, format string, destroy, prune bool, timeout time.Duration) (*protocol.ManifestPlan, error) {
+	m.session.Connect()
+	defer m.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	req := &protocol.ManifestRequest{
+		Content: content,
+		Format:  format,
+		Destroy: destroy,
+		Prune:   prune,
+	}
+	service := protocol.NewManifestServiceClient(m.session.connection)
+	return service.Plan(ctx, req)
+}
+
+// Apply creates the resources of a manifest that are missing, deletes the ones removed from it if prune is true, and returns the steps done
+func (m manifest) Apply(content []byte, format string, prune bool, timeout time.Duration) (*protocol.ManifestPlan, error) {
+	m.session.Connect()
+	defer m.session.Disconnect()
+
+	ctx, xerr := m.session.asyncContext()
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewManifestServiceClient(m.session.connection)
+	return service.Apply(ctx, &protocol.ManifestRequest{Content: content, Format: format, Prune: prune})
+}
+
+// Destroy deletes the resources of a manifest that exist, and returns the steps done
+func (m manifest) Destroy(content []byte, format string, timeout time.Duration) (*protocol.ManifestPlan, error) {
+	m.session.Connect()
+	defer m.session.Disconnect()
+
+	ctx, xerr := m.session.asyncContext()
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewManifestServiceClient(m.session.connection)
+	return service.Destroy(ctx, &protocol.ManifestRequest{Content: content, Format: format, Destroy: true})
+}
@@ -32,8 +32,8 @@ type network struct {
 	session *Session
 }
 
-// List ...
-func (n network) List(all bool, timeout time.Duration) (*protocol.NetworkList, error) {
+// List returns the networks, restricted to the ones matching all the labels if any
+func (n network) List(all bool, labels map[string]string, timeout time.Duration) (*protocol.NetworkList, error) {
 	n.session.Connect()
 	defer n.session.Disconnect()
 	service := protocol.NewNetworkServiceClient(n.session.connection)
@@ -43,7 +43,8 @@ func (n network) List(all bool, timeout time.Duration) (*protocol.NetworkList, e
 	}
 
 	return service.List(ctx, &protocol.NetworkListRequest{
-		All: all,
+		All:    all,
+		Labels: labels,
 	})
 }
 
@@ -107,6 +108,7 @@ func (n network) Create(
 	noSubnet bool,
 	gwname string, gwSSHPort uint32, os, sizing string,
 	keepOnFailure bool,
+	labels map[string]string,
 	timeout time.Duration,
 ) (*protocol.Network, error) {
 
@@ -123,6 +125,7 @@ func (n network) Create(
 		Cidr:          cidr,
 		NoSubnet:      noSubnet,
 		KeepOnFailure: keepOnFailure,
+		Labels:        labels,
 		Gateway: &protocol.GatewayDefinition{
 			Name:           gwname,
 			SshPort:        gwSSHPort,
@@ -132,3 +135,17 @@ func (n network) Create(
 	}
 	return service.Create(ctx, def)
 }
+
+// Import registers in SafeScale metadata an existing network of the provider, with its subnets
+func (n network) Import(ref string, timeout time.Duration) (*protocol.Network, error) {
+	n.session.Connect()
+	defer n.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewNetworkServiceClient(n.session.connection)
+	return service.Import(ctx, &protocol.Reference{Name: ref})
+}
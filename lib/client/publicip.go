@@ -0,0 +1,161 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/CS-SI/SafeScale/lib/protocol"
+	"github.com/CS-SI/SafeScale/lib/server/utils"
+	clitools "github.com/CS-SI/SafeScale/lib/utils/cli"
+)
+
+// publicIP is the part of safescale client handling public IPs
+type publicIP struct {
+	// session is not used currently
+	session *Session
+}
+
+// List ...
+func (pip publicIP) List(all bool, timeout time.Duration) (*protocol.PublicIPListResponse, error) {
+	pip.session.Connect()
+	defer pip.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewPublicIPServiceClient(pip.session.connection)
+	return service.List(ctx, &protocol.PublicIPListRequest{All: all})
+}
+
+// Inspect ...
+func (pip publicIP) Inspect(ref string, timeout time.Duration) (*protocol.PublicIPResponse, error) {
+	pip.session.Connect()
+	defer pip.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewPublicIPServiceClient(pip.session.connection)
+	return service.Inspect(ctx, &protocol.Reference{Name: ref})
+}
+
+// Create allocates a new public IP
+func (pip publicIP) Create(name, kind, description string, timeout time.Duration) (*protocol.PublicIPResponse, error) {
+	pip.session.Connect()
+	defer pip.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	req := &protocol.PublicIPCreateRequest{
+		Name:        name,
+		Type:        kind,
+		Description: description,
+	}
+	service := protocol.NewPublicIPServiceClient(pip.session.connection)
+	return service.Create(ctx, req)
+}
+
+// Delete releases several public IPs at the same time in goroutines
+func (pip publicIP) Delete(names []string, force bool, timeout time.Duration) error {
+	pip.session.Connect()
+	defer pip.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return xerr
+	}
+
+	var (
+		mutex sync.Mutex
+		wg    sync.WaitGroup
+		errs  []string
+	)
+
+	service := protocol.NewPublicIPServiceClient(pip.session.connection)
+	taskDeletePublicIP := func(aname string) {
+		defer wg.Done()
+		req := &protocol.PublicIPDeleteRequest{
+			Ip:    &protocol.Reference{Name: aname},
+			Force: force,
+		}
+		_, err := service.Delete(ctx, req)
+		if err != nil {
+			mutex.Lock()
+			errs = append(errs, err.Error())
+			mutex.Unlock()
+		}
+	}
+
+	wg.Add(len(names))
+	for _, target := range names {
+		go taskDeletePublicIP(target)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return clitools.ExitOnRPC(strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// Bind binds a public IP to a host, moving it from the host currently using it if needed
+func (pip publicIP) Bind(ipRef, hostRef string, timeout time.Duration) error {
+	pip.session.Connect()
+	defer pip.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return xerr
+	}
+
+	req := &protocol.PublicIPBindRequest{
+		Ip:   &protocol.Reference{Name: ipRef},
+		Host: &protocol.Reference{Name: hostRef},
+	}
+	service := protocol.NewPublicIPServiceClient(pip.session.connection)
+	_, err := service.Bind(ctx, req)
+	return err
+}
+
+// Unbind unbinds a public IP from a host
+func (pip publicIP) Unbind(ipRef, hostRef string, timeout time.Duration) error {
+	pip.session.Connect()
+	defer pip.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return xerr
+	}
+
+	req := &protocol.PublicIPBindRequest{
+		Ip:   &protocol.Reference{Name: ipRef},
+		Host: &protocol.Reference{Name: hostRef},
+	}
+	service := protocol.NewPublicIPServiceClient(pip.session.connection)
+	_, err := service.Unbind(ctx, req)
+	return err
+}
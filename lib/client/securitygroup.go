@@ -220,3 +220,42 @@ func (sg securityGroup) Bonds(group, kind string, duration time.Duration) (*prot
 	service := protocol.NewSecurityGroupServiceClient(sg.session.connection)
 	return service.Bonds(ctx, req)
 }
+
+// Check compares the rules of a Security Group on provider side with the ones registered in metadata, fixing the differences if requested
+func (sg securityGroup) Check(ref string, fix, adopt bool, timeout time.Duration) (*protocol.SecurityGroupDrift, error) {
+	sg.session.Connect()
+	defer sg.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewSecurityGroupServiceClient(sg.session.connection)
+	return service.Check(ctx, &protocol.SecurityGroupCheckRequest{Group: &protocol.Reference{Name: ref}, Fix: fix, Adopt: adopt})
+}
+
+// Import registers in SafeScale metadata an existing security group of the provider
+func (sg securityGroup) Import(networkRef, ref string, timeout time.Duration) (*abstract.SecurityGroup, error) {
+	sg.session.Connect()
+	defer sg.session.Disconnect()
+
+	nullSg := abstract.NewSecurityGroup()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nullSg, xerr
+	}
+
+	protoRequest := &protocol.SecurityGroupImportRequest{
+		Network: &protocol.Reference{Name: networkRef},
+		Group:   &protocol.Reference{Name: ref},
+	}
+	service := protocol.NewSecurityGroupServiceClient(sg.session.connection)
+	resp, err := service.Import(ctx, protoRequest)
+	if err != nil {
+		return nullSg, err
+	}
+
+	return converters.SecurityGroupFromProtocolToAbstract(resp)
+}
@@ -136,3 +136,32 @@ func (t tenant) Upgrade(name string, dryRun bool, timeout time.Duration) ([]stri
 	}
 	return nil, err
 }
+
+// Drift checks the consistency of the rules of all the Security Groups of a tenant, fixing them if requested
+func (t tenant) Drift(name string, fix, adopt bool, timeout time.Duration) (*protocol.TenantDriftResponse, error) {
+	t.session.Connect()
+	defer t.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewTenantServiceClient(t.session.connection)
+	return service.Drift(ctx, &protocol.TenantDriftRequest{Name: name, Fix: fix, Adopt: adopt})
+}
+
+// Reconcile compares the metadata of a tenant with the resources on provider side, and fixes the differences on demand
+func (t tenant) Reconcile(name string, req *protocol.TenantReconcileRequest, timeout time.Duration) (*protocol.TenantReconcileResponse, error) {
+	t.session.Connect()
+	defer t.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	req.Name = name
+	service := protocol.NewTenantServiceClient(t.session.connection)
+	return service.Reconcile(ctx, req)
+}
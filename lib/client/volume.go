@@ -32,8 +32,8 @@ type volume struct {
 	session *Session
 }
 
-// List ...
-func (v volume) List(all bool, timeout time.Duration) (*protocol.VolumeListResponse, error) {
+// List returns the volumes, restricted to the ones matching all the labels if any
+func (v volume) List(all bool, labels map[string]string, timeout time.Duration) (*protocol.VolumeListResponse, error) {
 	v.session.Connect()
 	defer v.session.Disconnect()
 
@@ -43,7 +43,7 @@ func (v volume) List(all bool, timeout time.Duration) (*protocol.VolumeListRespo
 	}
 
 	service := protocol.NewVolumeServiceClient(v.session.connection)
-	return service.List(ctx, &protocol.VolumeListRequest{All: all})
+	return service.List(ctx, &protocol.VolumeListRequest{All: all, Labels: labels})
 }
 
 // Inspect ...
@@ -149,3 +149,118 @@ func (v volume) Detach(volumeName string, hostName string, timeout time.Duration
 	})
 	return err
 }
+
+// SnapshotCreate ...
+func (v volume) SnapshotCreate(def *protocol.VolumeSnapshotCreateRequest, timeout time.Duration) (*protocol.VolumeSnapshotResponse, error) {
+	v.session.Connect()
+	defer v.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewVolumeServiceClient(v.session.connection)
+	return service.SnapshotCreate(ctx, def)
+}
+
+// SnapshotList ...
+func (v volume) SnapshotList(volumeName string, all bool, timeout time.Duration) (*protocol.VolumeSnapshotListResponse, error) {
+	v.session.Connect()
+	defer v.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	req := &protocol.VolumeSnapshotListRequest{All: all}
+	if volumeName != "" {
+		req.Volume = &protocol.Reference{Name: volumeName}
+	}
+	service := protocol.NewVolumeServiceClient(v.session.connection)
+	return service.SnapshotList(ctx, req)
+}
+
+// SnapshotDelete ...
+func (v volume) SnapshotDelete(names []string, timeout time.Duration) error {
+	v.session.Connect()
+	defer v.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return xerr
+	}
+
+	var (
+		mutex sync.Mutex
+		wg    sync.WaitGroup
+		errs  []string
+	)
+
+	service := protocol.NewVolumeServiceClient(v.session.connection)
+
+	snapshotDeleter := func(aname string) {
+		defer wg.Done()
+		_, err := service.SnapshotDelete(ctx, &protocol.Reference{Name: aname})
+
+		if err != nil {
+			mutex.Lock()
+			errs = append(errs, err.Error())
+			mutex.Unlock()
+		}
+	}
+
+	wg.Add(len(names))
+	for _, target := range names {
+		go snapshotDeleter(target)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return clitools.ExitOnRPC(strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// SnapshotRestore ...
+func (v volume) SnapshotRestore(def *protocol.VolumeSnapshotRestoreRequest, timeout time.Duration) (*protocol.VolumeInspectResponse, error) {
+	v.session.Connect()
+	defer v.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewVolumeServiceClient(v.session.connection)
+	return service.SnapshotRestore(ctx, def)
+}
+
+// Import registers in SafeScale metadata an existing volume of the provider
+func (v volume) Import(ref string, timeout time.Duration) (*protocol.VolumeInspectResponse, error) {
+	v.session.Connect()
+	defer v.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewVolumeServiceClient(v.session.connection)
+	return service.Import(ctx, &protocol.Reference{Id: ref})
+}
+
+// Resize grows a volume to size GB, and its file system on the hosts where it is attached
+func (v volume) Resize(ref string, size int, timeout time.Duration) (*protocol.VolumeInspectResponse, error) {
+	v.session.Connect()
+	defer v.session.Disconnect()
+
+	ctx, xerr := utils.GetContext(true)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	service := protocol.NewVolumeServiceClient(v.session.connection)
+	return service.Resize(ctx, &protocol.VolumeResizeRequest{Volume: &protocol.Reference{Id: ref}, Size: int32(size)})
+}
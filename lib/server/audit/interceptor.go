@@ -0,0 +1,117 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+
+	"github.com/CS-SI/SafeScale/lib/protocol"
+	"github.com/CS-SI/SafeScale/lib/server/rbac"
+)
+
+// readOnlyMethods are the RPC methods (as '/<Service>/<Method>') not changing anything, that are not recorded
+var readOnlyMethods = map[string]struct{}{
+	"/ClusterService/CheckHealth":         {},
+	"/ClusterService/FindAvailableMaster": {},
+	"/ClusterService/Kubeconfig":          {},
+	"/FeatureService/Check":               {},
+	"/HostService/SSH":                    {},
+	"/HostService/Status":                 {},
+	"/JobService/History":                 {},
+	"/JobService/Wait":                    {},
+	"/JobService/Watch":                   {},
+	"/ManifestService/Plan":               {},
+	"/SecurityGroupService/Bonds":         {},
+	"/SecurityGroupService/Check":         {},
+	"/TemplateService/Match":              {},
+	"/TenantService/Get":                  {},
+	"/TenantService/Reconcile":            {},
+	"/VolumeService/SnapshotList":         {},
+}
+
+// requestDependentMethods are the read-only RPC methods (as '/<Service>/<Method>') that change something when
+// their request asks for it
+var requestDependentMethods = map[string]func(req interface{}) bool{
+	"/SecurityGroupService/Check": func(req interface{}) bool {
+		in, ok := req.(*protocol.SecurityGroupCheckRequest)
+		return ok && (in.GetFix() || in.GetAdopt())
+	},
+	"/TenantService/Reconcile": func(req interface{}) bool {
+		in, ok := req.(*protocol.TenantReconcileRequest)
+		return ok && !in.GetDryRun() && (in.GetImport() || in.GetPurge() || in.GetPurgeCloud() || in.GetRepair())
+	},
+}
+
+// readOnlyPrefixes are the prefixes of the names of the RPC methods not changing anything
+var readOnlyPrefixes = []string{"Inspect", "List", "State"}
+
+// IsMutating tells if the gRPC method (as '/<Service>/<Method>') changes something
+func IsMutating(fullMethod string) bool {
+	if _, ok := readOnlyMethods[fullMethod]; ok {
+		return false
+	}
+	method := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	for _, v := range readOnlyPrefixes {
+		if strings.HasPrefix(method, v) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsMutatingCall tells if the call of the gRPC method (as '/<Service>/<Method>') with the request req changes something
+func IsMutatingCall(fullMethod string, req interface{}) bool {
+	if mutates, ok := requestDependentMethods[fullMethod]; ok {
+		return mutates(req)
+	}
+	return IsMutating(fullMethod)
+}
+
+// UnaryServerInterceptor returns a gRPC interceptor recording every mutating call with recorder
+// currentTenant gives the name of the current tenant, used when the request does not designate a tenant
+// and no job is created for the call
+func UnaryServerInterceptor(recorder *Recorder, currentTenant func() string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if recorder == nil || !IsMutatingCall(info.FullMethod, req) {
+			return handler(ctx, req)
+		}
+
+		record := &Record{
+			Time:       time.Now(),
+			Identity:   rbac.Identity(ctx),
+			Action:     rbac.Action(info.FullMethod),
+			Resource:   rbac.Resource(req),
+			References: rbac.References(info.FullMethod, req),
+			Parameters: Parameters(req),
+			Tenant:     rbac.Tenant(info.FullMethod, req, currentTenant),
+			recorder:   recorder,
+		}
+
+		resp, err := handler(WithRecord(ctx, record), req)
+
+		record.finish(time.Since(record.Time), err)
+		if xerr := recorder.Write(record); xerr != nil {
+			logrus.Errorf("failed to record audit of '%s': %v", info.FullMethod, xerr)
+		}
+		return resp, err
+	}
+}
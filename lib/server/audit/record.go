@@ -0,0 +1,229 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package audit records who did what and when in safescaled
+//
+// Every mutating gRPC call produces a Record, appended as a JSON line to a local file and optionally
+// copied in the metadata bucket of the tenant.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+)
+
+// Outcomes of a Record
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+	OutcomeAborted = "aborted"
+	OutcomeStarted = "started" // the call started an asynchronous job; another record gives the outcome of the job
+)
+
+// redactedValue replaces the value of secret parameters
+const redactedValue = "<redacted>"
+
+// secretMarkers are the (lowercase) parts of parameter names that reveal a secret
+var secretMarkers = []string{"password", "secret", "token", "private", "credential"}
+
+// Record describes a call
+type Record struct {
+	Time       time.Time              `json:"time"`
+	Identity   string                 `json:"identity"`
+	Tenant     string                 `json:"tenant,omitempty"`
+	Action     string                 `json:"action"`
+	Resource   string                 `json:"resource,omitempty"`
+	References []string               `json:"references,omitempty"` // references ('<kind>:<name or id>') of all the resources targeted
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	JobID      string                 `json:"job_id,omitempty"`
+	DurationMs int64                  `json:"duration_ms"`
+	Outcome    string                 `json:"outcome"`
+	Error      string                 `json:"error,omitempty"`
+	Async      bool                   `json:"async,omitempty"`
+
+	lock     sync.Mutex
+	aborted  bool
+	service  iaas.Service // service of the job, used to copy the record in metadata bucket
+	recorder *Recorder    // recorder used to write the outcome of an asynchronous job
+}
+
+type contextKey struct{}
+
+// WithRecord returns a copy of ctx carrying the record
+func WithRecord(ctx context.Context, r *Record) context.Context {
+	return context.WithValue(ctx, contextKey{}, r)
+}
+
+// FromContext returns the record carried by ctx, or nil
+func FromContext(ctx context.Context) *Record {
+	if ctx == nil {
+		return nil
+	}
+	r, _ := ctx.Value(contextKey{}).(*Record)
+	return r
+}
+
+// BindJob completes the record with the information of the job created to serve the call
+func (r *Record) BindJob(id string, svc iaas.Service) {
+	if r == nil {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.JobID = id
+	if svc != nil {
+		r.Tenant = svc.GetName()
+		r.service = svc
+	}
+}
+
+// MarkAborted tells the job of the call has been aborted
+func (r *Record) MarkAborted() {
+	if r == nil {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.aborted = true
+}
+
+// Detach tells the call continues asynchronously in a job; the outcome of the job will be recorded by Complete()
+func (r *Record) Detach() {
+	if r == nil {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.Async = true
+}
+
+// Complete records the outcome of the asynchronous job started by the call; does nothing if the call is not detached
+func (r *Record) Complete(err error) {
+	if r == nil {
+		return
+	}
+
+	r.lock.Lock()
+	if !r.Async || r.recorder == nil {
+		r.lock.Unlock()
+		return
+	}
+	outcome := &Record{
+		Time:       r.Time,
+		Identity:   r.Identity,
+		Tenant:     r.Tenant,
+		Action:     r.Action,
+		Resource:   r.Resource,
+		Parameters: r.Parameters,
+		JobID:      r.JobID,
+		aborted:    r.aborted,
+		service:    r.service,
+	}
+	recorder := r.recorder
+	r.lock.Unlock()
+
+	outcome.finish(time.Since(outcome.Time), err)
+	outcome.Async = true
+	if xerr := recorder.Write(outcome); xerr != nil {
+		logrus.Errorf("failed to record audit of job '%s': %v", outcome.JobID, xerr)
+	}
+}
+
+// finish sets the duration and the outcome of the call
+func (r *Record) finish(duration time.Duration, err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.DurationMs = duration.Milliseconds()
+	switch {
+	case r.aborted:
+		r.Outcome = OutcomeAborted
+	case err != nil:
+		r.Outcome = OutcomeFailure
+	case r.Async:
+		r.Outcome = OutcomeStarted
+	default:
+		r.Outcome = OutcomeSuccess
+	}
+	if err != nil {
+		r.Error = err.Error()
+	}
+}
+
+// Parameters converts a gRPC request to a map of its parameters, with the value of secrets redacted
+func Parameters(req interface{}) map[string]interface{} {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return nil
+	}
+
+	jsoned, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+	if err != nil {
+		return nil
+	}
+	out := map[string]interface{}{}
+	if err = json.Unmarshal(jsoned, &out); err != nil || len(out) == 0 {
+		return nil
+	}
+	redact(out)
+	return out
+}
+
+// redact replaces recursively the value of secret parameters
+func redact(in map[string]interface{}) {
+	for k, v := range in {
+		if isSecret(k) {
+			in[k] = redactedValue
+			continue
+		}
+		switch casted := v.(type) {
+		case map[string]interface{}:
+			redact(casted)
+		case []interface{}:
+			for _, item := range casted {
+				if m, ok := item.(map[string]interface{}); ok {
+					redact(m)
+				}
+			}
+		}
+	}
+}
+
+// isSecret tells if a parameter name designates a secret
+func isSecret(name string) bool {
+	lowered := strings.ToLower(name)
+	for _, v := range secretMarkers {
+		if strings.Contains(lowered, v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,182 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// BucketFolder is the folder of the metadata bucket receiving the copies of the records
+const BucketFolder = "audit"
+
+// Recorder appends records to a local file, one JSON document per line
+type Recorder struct {
+	file     string
+	toBucket bool
+	lock     sync.Mutex
+}
+
+// Filter selects records
+type Filter struct {
+	Since    time.Time // records older than Since are skipped, if not zero
+	Resource string    // pattern (as accepted by path.Match) the resource must match, if not empty
+}
+
+// NewRecorder creates a Recorder writing to file; if toBucket is true, each record is also copied in the metadata bucket
+// of the tenant of the call
+func NewRecorder(file string, toBucket bool) (*Recorder, fail.Error) {
+	if file == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("file")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return nil, fail.Wrap(err, "failed to create folder of audit file")
+	}
+	// Checks the file can be opened for append
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fail.Wrap(err, "failed to open audit file '%s'", file)
+	}
+	_ = f.Close()
+
+	return &Recorder{file: file, toBucket: toBucket}, nil
+}
+
+// File returns the path of the audit file
+func (r *Recorder) File() string {
+	if r == nil {
+		return ""
+	}
+	return r.file
+}
+
+// Write appends the record to the audit file, then copies it in metadata bucket if requested
+func (r *Recorder) Write(record *Record) fail.Error {
+	if r == nil {
+		return fail.InvalidInstanceError()
+	}
+	if record == nil {
+		return fail.InvalidParameterCannotBeNilError("record")
+	}
+
+	record.lock.Lock()
+	content, err := json.Marshal(record)
+	svc := record.service
+	record.lock.Unlock()
+	if err != nil {
+		return fail.ConvertError(err)
+	}
+
+	if xerr := r.append(content); xerr != nil {
+		return xerr
+	}
+
+	if r.toBucket && svc != nil {
+		name := path.Join(BucketFolder, record.Time.UTC().Format("2006/01/02"), fmt.Sprintf("%s-%s.json", record.Time.UTC().Format("150405.000000000"), record.JobID))
+		if _, xerr := svc.WriteObject(svc.GetMetadataBucket().GetName(), name, bytes.NewReader(content), int64(len(content)), nil); xerr != nil {
+			// The local file is the reference; a failed copy must not fail the call
+			logrus.Warnf("failed to copy audit record in metadata bucket: %v", xerr)
+		}
+	}
+	return nil
+}
+
+// append writes a line in the audit file, opened in append-only mode
+func (r *Recorder) append(content []byte) fail.Error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	f, err := os.OpenFile(r.file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fail.Wrap(err, "failed to open audit file '%s'", r.file)
+	}
+	defer func() { _ = f.Close() }()
+
+	if _, err = f.Write(append(content, '\n')); err != nil {
+		return fail.Wrap(err, "failed to write in audit file '%s'", r.file)
+	}
+	if err = f.Sync(); err != nil {
+		return fail.Wrap(err, "failed to flush audit file '%s'", r.file)
+	}
+	return nil
+}
+
+// List returns the records of the audit file selected by filter, oldest first
+func (r *Recorder) List(filter Filter) ([]*Record, fail.Error) {
+	if r == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	f, err := os.Open(r.file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*Record{}, nil
+		}
+		return nil, fail.Wrap(err, "failed to open audit file '%s'", r.file)
+	}
+	defer func() { _ = f.Close() }()
+
+	out := []*Record{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		record := &Record{}
+		if err = json.Unmarshal(scanner.Bytes(), record); err != nil {
+			return nil, fail.SyntaxError("invalid record at line %d of audit file '%s': %v", line, r.file, err)
+		}
+		if filter.match(record) {
+			out = append(out, record)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fail.Wrap(err, "failed to read audit file '%s'", r.file)
+	}
+	return out, nil
+}
+
+// match tells if the record is selected by the filter
+func (f Filter) match(record *Record) bool {
+	if !f.Since.IsZero() && record.Time.Before(f.Since) {
+		return false
+	}
+	if f.Resource != "" {
+		if ok, _ := path.Match(f.Resource, record.Resource); !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -18,7 +18,10 @@ package handlers
 
 import (
 	"github.com/CS-SI/SafeScale/lib/server"
+	"github.com/CS-SI/SafeScale/lib/server/resources"
 	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	hostfactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/host"
+	imagefactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/image"
 	"github.com/CS-SI/SafeScale/lib/utils/debug"
 	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
@@ -33,6 +36,8 @@ type ImageHandler interface {
 	List(all bool) ([]abstract.Image, fail.Error)
 	Select(osfilter string) (*abstract.Image, fail.Error)
 	Filter(osfilter string) ([]abstract.Image, fail.Error)
+	Create(name string, hostRef string, description string) (resources.Image, fail.Error)
+	Delete(ref string) fail.Error
 }
 
 // FIXME: ROBUSTNESS All functions MUST propagate context
@@ -78,3 +83,78 @@ func (handler *imageHandler) Filter(osname string) (image []abstract.Image, xerr
 
 	return nil, fail.NotImplementedError("ImageHandler.Filter() not yet implemented")
 }
+
+// Create creates a custom image named name from the host identified by hostRef, ref can be the name or the id
+func (handler *imageHandler) Create(name, hostRef, description string) (_ resources.Image, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if handler.job == nil {
+		return nil, fail.InvalidInstanceContentError("handler.job", "cannot be nil")
+	}
+	if name == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("name")
+	}
+	if hostRef == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("hostRef")
+	}
+
+	tracer := debug.NewTracer(handler.job.Task(), tracing.ShouldTrace("handlers.image"), "('%s', '%s')", name, hostRef).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&xerr, tracer.TraceMessage())
+
+	svc := handler.job.Service()
+	hostInstance, xerr := hostfactory.Load(svc, hostRef)
+	if xerr != nil {
+		if _, ok := xerr.(*fail.ErrNotFound); ok {
+			return nil, abstract.ResourceNotFoundError("host", hostRef)
+		}
+		return nil, xerr
+	}
+	defer hostInstance.Released()
+
+	imageInstance, xerr := imagefactory.New(svc)
+	if xerr != nil {
+		return nil, xerr
+	}
+	request := abstract.ImageRequest{
+		Name:        name,
+		HostID:      hostInstance.GetID(),
+		Description: description,
+	}
+	if xerr = imageInstance.Create(handler.job.Context(), request); xerr != nil {
+		return nil, xerr
+	}
+	return imageInstance, nil
+}
+
+// Delete deletes the custom image identified by ref, ref can be the name or the id
+func (handler *imageHandler) Delete(ref string) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if handler == nil {
+		return fail.InvalidInstanceError()
+	}
+	if handler.job == nil {
+		return fail.InvalidInstanceContentError("handler.job", "cannot be nil")
+	}
+	if ref == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("ref")
+	}
+
+	tracer := debug.NewTracer(handler.job.Task(), tracing.ShouldTrace("handlers.image"), "('%s')", ref).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&xerr, tracer.TraceMessage())
+
+	imageInstance, xerr := imagefactory.Load(handler.job.Service(), ref)
+	if xerr != nil {
+		if _, ok := xerr.(*fail.ErrNotFound); ok {
+			return abstract.ResourceNotFoundError("image", ref)
+		}
+		return xerr
+	}
+
+	return imageInstance.Delete(handler.job.Context())
+}
@@ -0,0 +1,1321 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/protocol"
+	"github.com/CS-SI/SafeScale/lib/server"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
+	"github.com/CS-SI/SafeScale/lib/server/manifest"
+	"github.com/CS-SI/SafeScale/lib/server/resources"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clustercomplexity"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterflavor"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/securitygroupstate"
+	bucketfactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/bucket"
+	clusterfactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/cluster"
+	hostfactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/host"
+	networkfactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/network"
+	securitygroupfactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/securitygroup"
+	sharefactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/share"
+	subnetfactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/subnet"
+	volumefactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/volume"
+	"github.com/CS-SI/SafeScale/lib/server/resources/operations"
+	"github.com/CS-SI/SafeScale/lib/server/resources/operations/converters"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/data/serialize"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+const (
+	// defaultManifestNetworkCIDR is the CIDR used for a Network without one, same as 'safescale network create'
+	defaultManifestNetworkCIDR = "192.168.0.0/23"
+	// defaultManifestVolumeSize is the size in GB used for a Volume without one, same as 'safescale volume create'
+	defaultManifestVolumeSize = 10
+	// defaultManifestVolumeFormat is the filesystem used to format an attached Volume, same as 'safescale volume attach'
+	defaultManifestVolumeFormat = "ext4"
+	// manifestStateFolder is the folder of the metadata bucket keeping the resources declared by named manifests at their last apply
+	manifestStateFolder = "manifests"
+)
+
+//go:generate minimock -i github.com/CS-SI/SafeScale/lib/server/handlers.ManifestHandler -o ../mocks/mock_manifestapi.go
+
+// ManifestHandler defines API to converge the infrastructure described by a manifest
+type ManifestHandler interface {
+	Plan(m *manifest.Manifest, destroy, prune bool) (manifest.Plan, fail.Error)
+	Apply(m *manifest.Manifest, prune bool) (manifest.Plan, fail.Error)
+	Destroy(m *manifest.Manifest) (manifest.Plan, fail.Error)
+}
+
+// manifestHandler is an implementation of ManifestHandler
+type manifestHandler struct {
+	job server.Job
+}
+
+// NewManifestHandler creates a ManifestHandler
+func NewManifestHandler(job server.Job) ManifestHandler {
+	return &manifestHandler{job: job}
+}
+
+// plannedStep associates a step of the plan with the function realizing it
+type plannedStep struct {
+	manifest.Step
+	run func() fail.Error
+}
+
+// Plan computes the steps needed to converge to the manifest (or to remove what it describes if destroy is true),
+// without doing anything
+// The plan also reports the drifts: attributes of existing resources differing from the manifest, and resources removed
+// from the manifest since its last apply, whose deletion is done by apply only if prune is true.
+func (handler *manifestHandler) Plan(m *manifest.Manifest, destroy, prune bool) (_ manifest.Plan, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if handler.job == nil {
+		return nil, fail.InvalidInstanceContentError("handler.job", "cannot be nil")
+	}
+	if m == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("m")
+	}
+
+	tracer := debug.NewTracer(handler.job.Task(), tracing.ShouldTrace("handlers.manifest"), "(%v, %v)", destroy, prune).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&xerr, tracer.TraceMessage())
+
+	state, xerr := handler.loadState(m.Name)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	var steps []plannedStep
+	if destroy {
+		steps, xerr = handler.destroySteps(m.Merge(m.Removed(state)), nil)
+	} else {
+		steps, xerr = handler.applySteps(m, state, prune)
+	}
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	out := make(manifest.Plan, 0, len(steps))
+	for _, v := range steps {
+		out = append(out, v.Step)
+	}
+	return out, nil
+}
+
+// Apply creates what is missing to converge to the manifest, and returns the steps done
+// Existing resources are not modified; the ones removed from the manifest since its last apply are deleted only if prune is true.
+// On failure, what has been done is kept, so applying again resumes the work.
+func (handler *manifestHandler) Apply(m *manifest.Manifest, prune bool) (_ manifest.Plan, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if handler.job == nil {
+		return nil, fail.InvalidInstanceContentError("handler.job", "cannot be nil")
+	}
+	if m == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("m")
+	}
+
+	tracer := debug.NewTracer(handler.job.Task(), tracing.ShouldTrace("handlers.manifest"), "(%v)", prune).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&xerr, tracer.TraceMessage())
+
+	state, xerr := handler.loadState(m.Name)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	steps, xerr := handler.applySteps(m, state, prune)
+	if xerr != nil {
+		return nil, xerr
+	}
+	done, xerr := handler.execute(steps)
+	if xerr != nil {
+		return done, xerr
+	}
+
+	// Resources removed from the manifest and not pruned are remembered, to be reported until deleted
+	if !prune {
+		m = m.Merge(m.Removed(state))
+	}
+	return done, handler.saveState(m)
+}
+
+// Destroy deletes the resources described by the manifest that exist, and returns the steps done
+func (handler *manifestHandler) Destroy(m *manifest.Manifest) (_ manifest.Plan, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if handler.job == nil {
+		return nil, fail.InvalidInstanceContentError("handler.job", "cannot be nil")
+	}
+	if m == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("m")
+	}
+
+	tracer := debug.NewTracer(handler.job.Task(), tracing.ShouldTrace("handlers.manifest"), "").WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&xerr, tracer.TraceMessage())
+
+	state, xerr := handler.loadState(m.Name)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	steps, xerr := handler.destroySteps(m.Merge(m.Removed(state)), nil)
+	if xerr != nil {
+		return nil, xerr
+	}
+	done, xerr := handler.execute(steps)
+	if xerr != nil {
+		return done, xerr
+	}
+	return done, handler.forgetState(m.Name)
+}
+
+// loadState returns the resources declared by the manifest named name at its last apply, nil if unknown
+func (handler *manifestHandler) loadState(name string) (*manifest.Manifest, fail.Error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	folder, xerr := operations.NewMetadataFolder(handler.job.Service(), manifestStateFolder)
+	if xerr != nil {
+		return nil, xerr
+	}
+	if xerr = folder.Lookup("", name); xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			return nil, nil
+		default:
+			return nil, xerr
+		}
+	}
+
+	state := &manifest.Manifest{}
+	xerr = folder.Read("", name, func(content []byte) fail.Error {
+		if err := json.Unmarshal(content, state); err != nil {
+			return fail.ConvertError(err)
+		}
+		return nil
+	})
+	if xerr != nil {
+		return nil, fail.Wrap(xerr, "failed to read state of manifest '%s'", name)
+	}
+	return state, nil
+}
+
+// saveState remembers the resources declared by a named manifest
+func (handler *manifestHandler) saveState(m *manifest.Manifest) fail.Error {
+	if m.Name == "" {
+		return nil
+	}
+
+	folder, xerr := operations.NewMetadataFolder(handler.job.Service(), manifestStateFolder)
+	if xerr != nil {
+		return xerr
+	}
+	content, err := json.Marshal(m.Merge(nil))
+	if err != nil {
+		return fail.ConvertError(err)
+	}
+	if xerr = folder.Write("", m.Name, content); xerr != nil {
+		return fail.Wrap(xerr, "failed to save state of manifest '%s'", m.Name)
+	}
+	return nil
+}
+
+// forgetState removes the resources remembered for the manifest named name
+func (handler *manifestHandler) forgetState(name string) fail.Error {
+	if name == "" {
+		return nil
+	}
+
+	folder, xerr := operations.NewMetadataFolder(handler.job.Service(), manifestStateFolder)
+	if xerr != nil {
+		return xerr
+	}
+	if xerr = folder.Lookup("", name); xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			return nil
+		default:
+			return xerr
+		}
+	}
+	return folder.Delete("", name)
+}
+
+// execute runs the steps in order, stopping at the first failure; drifts are not run
+func (handler *manifestHandler) execute(steps []plannedStep) (manifest.Plan, fail.Error) {
+	done := make(manifest.Plan, 0, len(steps))
+	for _, v := range steps {
+		if v.Drift {
+			continue
+		}
+		if handler.job.Task().Aborted() {
+			return done, fail.AbortedError(nil, "aborted")
+		}
+
+		logrus.Infof("Manifest: %s", v.Step.String())
+		if xerr := v.run(); xerr != nil {
+			return done, fail.Wrap(xerr, "failed to %s %s '%s'", v.Action, v.Kind, v.Name)
+		}
+		done = append(done, v.Step)
+	}
+	return done, nil
+}
+
+// found converts the error returned by the load of a resource to a boolean telling if the resource exists
+func found(xerr fail.Error) (bool, fail.Error) {
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			return false, nil
+		default:
+			return false, xerr
+		}
+	}
+	return true, nil
+}
+
+// applySteps computes the steps needed to create what is missing, in dependency order, followed by the deletion of
+// the resources removed from the manifest since state has been saved; the drifts are reported as steps not to run
+func (handler *manifestHandler) applySteps(m *manifest.Manifest, state *manifest.Manifest, prune bool) ([]plannedStep, fail.Error) {
+	var steps []plannedStep
+	svc := handler.job.Service()
+
+	for _, n := range m.Networks {
+		n := n
+		networkInstance, xerr := networkfactory.Load(svc, n.Name)
+		exists, xerr := found(xerr)
+		if xerr != nil {
+			return nil, xerr
+		}
+		networkExists := exists
+		if exists {
+			diff, xerr := networkDifferences(networkInstance, n)
+			networkInstance.Released()
+			if xerr != nil {
+				return nil, xerr
+			}
+			steps = append(steps, diff.steps(manifest.KindNetwork, n.Name, "")...)
+		} else {
+			steps = append(steps, plannedStep{
+				Step: manifest.Step{Kind: manifest.KindNetwork, Name: n.Name, Action: manifest.ActionCreate},
+				run:  func() fail.Error { return handler.createNetwork(n) },
+			})
+		}
+
+		for _, s := range n.Subnets {
+			s := s
+			exists := false
+			if networkExists {
+				subnetInstance, xerr := subnetfactory.Load(svc, n.Name, s.Name)
+				if exists, xerr = found(xerr); xerr != nil {
+					return nil, xerr
+				}
+				if exists {
+					diff, xerr := subnetDifferences(subnetInstance, s)
+					subnetInstance.Released()
+					if xerr != nil {
+						return nil, xerr
+					}
+					steps = append(steps, diff.steps(manifest.KindSubnet, s.Name, "in network '"+n.Name+"'")...)
+				}
+			}
+			if !exists {
+				steps = append(steps, plannedStep{
+					Step: manifest.Step{Kind: manifest.KindSubnet, Name: s.Name, Action: manifest.ActionCreate, Detail: "in network '" + n.Name + "'"},
+					run:  func() fail.Error { return handler.createSubnet(n, s) },
+				})
+			}
+		}
+	}
+
+	for _, sg := range m.SecurityGroups {
+		sg := sg
+		sgInstance, xerr := securitygroupfactory.Load(svc, sg.Name)
+		exists, xerr := found(xerr)
+		if xerr != nil {
+			return nil, xerr
+		}
+		if exists {
+			diff, xerr := securityGroupDifferences(sgInstance, sg)
+			sgInstance.Released()
+			if xerr != nil {
+				return nil, xerr
+			}
+			steps = append(steps, diff.steps(manifest.KindSecurityGroup, sg.Name, "")...)
+			continue
+		}
+		steps = append(steps, plannedStep{
+			Step: manifest.Step{Kind: manifest.KindSecurityGroup, Name: sg.Name, Action: manifest.ActionCreate, Detail: "in network '" + sg.Network + "'"},
+			run:  func() fail.Error { return handler.createSecurityGroup(sg) },
+		})
+	}
+
+	for _, h := range m.Hosts {
+		h := h
+		var bound []string
+		hostInstance, xerr := hostfactory.Load(svc, h.Name)
+		exists, xerr := found(xerr)
+		if xerr != nil {
+			return nil, xerr
+		}
+		if exists {
+			bonds, xerr := hostInstance.ListSecurityGroups(securitygroupstate.All)
+			if xerr != nil {
+				hostInstance.Released()
+				return nil, xerr
+			}
+			for _, v := range bonds {
+				bound = append(bound, v.Name)
+			}
+			diff, xerr := hostDifferences(hostInstance, h)
+			hostInstance.Released()
+			if xerr != nil {
+				return nil, xerr
+			}
+			steps = append(steps, diff.steps(manifest.KindHost, h.Name, "")...)
+		} else {
+			steps = append(steps, plannedStep{
+				Step: manifest.Step{Kind: manifest.KindHost, Name: h.Name, Action: manifest.ActionCreate},
+				run:  func() fail.Error { return handler.createHost(h) },
+			})
+		}
+
+		for _, sg := range h.SecurityGroups {
+			sg := sg
+			if contains(bound, sg) {
+				continue
+			}
+			steps = append(steps, plannedStep{
+				Step: manifest.Step{Kind: manifest.KindSecurityGroup, Name: sg, Action: manifest.ActionBind, Detail: "to host '" + h.Name + "'"},
+				run:  func() fail.Error { return handler.bindSecurityGroup(sg, h.Name) },
+			})
+		}
+	}
+
+	volumeHandler := NewVolumeHandler(handler.job)
+	for _, v := range m.Volumes {
+		v := v
+		var attachedTo []string
+		volumeInstance, xerr := volumefactory.Load(svc, v.Name)
+		exists, xerr := found(xerr)
+		if xerr != nil {
+			return nil, xerr
+		}
+		if exists {
+			attachments, xerr := volumeInstance.GetAttachments()
+			if xerr != nil {
+				volumeInstance.Released()
+				return nil, xerr
+			}
+			for _, name := range attachments.Hosts {
+				attachedTo = append(attachedTo, name)
+			}
+			diff, xerr := volumeDifferences(volumeInstance, v)
+			volumeInstance.Released()
+			if xerr != nil {
+				return nil, xerr
+			}
+			steps = append(steps, diff.steps(manifest.KindVolume, v.Name, "")...)
+		} else {
+			steps = append(steps, plannedStep{
+				Step: manifest.Step{Kind: manifest.KindVolume, Name: v.Name, Action: manifest.ActionCreate},
+				run: func() fail.Error {
+					speed, xerr := v.AbstractSpeed()
+					if xerr != nil {
+						return xerr
+					}
+					size := v.Size
+					if size == 0 {
+						size = defaultManifestVolumeSize
+					}
+					volumeInstance, xerr := volumeHandler.Create(v.Name, size, speed, v.Labels)
+					if xerr != nil {
+						return xerr
+					}
+					volumeInstance.Released()
+					return nil
+				},
+			})
+		}
+
+		if v.Attach != nil && !contains(attachedTo, v.Attach.Host) {
+			steps = append(steps, plannedStep{
+				Step: manifest.Step{Kind: manifest.KindVolume, Name: v.Name, Action: manifest.ActionAttach, Detail: "to host '" + v.Attach.Host + "'"},
+				run: func() fail.Error {
+					path := v.Attach.Path
+					if path == "" {
+						path = abstract.DefaultVolumeMountPoint
+					}
+					format := v.Attach.Format
+					if format == "" {
+						format = defaultManifestVolumeFormat
+					}
+					return volumeHandler.Attach(v.Name, v.Attach.Host, path, format, v.Attach.DoNotFormat)
+				},
+			})
+		}
+	}
+
+	shareHandler := NewShareHandler(handler.job)
+	for _, s := range m.Shares {
+		s := s
+		var mountedOn []string
+		shareInstance, xerr := sharefactory.Load(svc, s.Name)
+		exists, xerr := found(xerr)
+		if xerr != nil {
+			return nil, xerr
+		}
+		if exists {
+			mountedOn, xerr = shareClients(shareInstance)
+			shareInstance.Released()
+			if xerr != nil {
+				return nil, xerr
+			}
+		} else {
+			steps = append(steps, plannedStep{
+				Step: manifest.Step{Kind: manifest.KindShare, Name: s.Name, Action: manifest.ActionCreate, Detail: "on host '" + s.Host + "'"},
+				run: func() fail.Error {
+					shareInstance, xerr := shareHandler.Create(s.Name, s.Host, s.Path, s.Options)
+					if xerr != nil {
+						return xerr
+					}
+					shareInstance.Released()
+					return nil
+				},
+			})
+		}
+
+		for _, mount := range s.Mounts {
+			mount := mount
+			if contains(mountedOn, mount.Host) {
+				continue
+			}
+			steps = append(steps, plannedStep{
+				Step: manifest.Step{Kind: manifest.KindShare, Name: s.Name, Action: manifest.ActionMount, Detail: "on host '" + mount.Host + "'"},
+				run: func() fail.Error {
+					path := mount.Path
+					if path == "" {
+						path = abstract.DefaultShareMountPath
+					}
+					_, xerr := shareHandler.Mount(s.Name, mount.Host, path, mount.WithCache)
+					return xerr
+				},
+			})
+		}
+	}
+
+	bucketHandler := NewBucketHandler(handler.job)
+	for _, b := range m.Buckets {
+		b := b
+		mountedOn := ""
+		bucketInstance, xerr := bucketfactory.Load(svc, b.Name)
+		exists, xerr := found(xerr)
+		if xerr != nil {
+			return nil, xerr
+		}
+		if exists {
+			mountedOn, xerr = bucketInstance.GetHost(handler.job.Context())
+			bucketInstance.Released()
+			if xerr != nil {
+				return nil, xerr
+			}
+		} else {
+			steps = append(steps, plannedStep{
+				Step: manifest.Step{Kind: manifest.KindBucket, Name: b.Name, Action: manifest.ActionCreate},
+				run:  func() fail.Error { return bucketHandler.Create(b.Name) },
+			})
+		}
+
+		if b.Mount != nil && mountedOn != b.Mount.Host {
+			steps = append(steps, plannedStep{
+				Step: manifest.Step{Kind: manifest.KindBucket, Name: b.Name, Action: manifest.ActionMount, Detail: "on host '" + b.Mount.Host + "'"},
+				run: func() fail.Error {
+					path := b.Mount.Path
+					if path == "" {
+						path = abstract.DefaultBucketMountPoint
+					}
+					return bucketHandler.Mount(b.Name, b.Mount.Host, path)
+				},
+			})
+		}
+	}
+
+	for _, c := range m.Clusters {
+		c := c
+		clusterInstance, xerr := clusterfactory.Load(svc, c.Name)
+		exists, xerr := found(xerr)
+		if xerr != nil {
+			return nil, xerr
+		}
+		if exists {
+			diff, xerr := clusterDifferences(clusterInstance, c)
+			clusterInstance.Released()
+			if xerr != nil {
+				return nil, xerr
+			}
+			steps = append(steps, diff.steps(manifest.KindCluster, c.Name, "")...)
+			continue
+		}
+		steps = append(steps, plannedStep{
+			Step: manifest.Step{Kind: manifest.KindCluster, Name: c.Name, Action: manifest.ActionCreate},
+			run:  func() fail.Error { return handler.createCluster(c) },
+		})
+	}
+
+	removal, xerr := handler.destroySteps(m.Removed(state), m)
+	if xerr != nil {
+		return nil, xerr
+	}
+	for _, v := range removal {
+		v.Drift = !prune
+		steps = append(steps, v)
+	}
+	return steps, nil
+}
+
+// destroySteps computes the steps needed to delete what exists, in reverse dependency order
+// The Networks still declared by kept (if not nil) are not deleted, only the Subnets listed inside them.
+func (handler *manifestHandler) destroySteps(m *manifest.Manifest, kept *manifest.Manifest) ([]plannedStep, fail.Error) {
+	var steps []plannedStep
+	svc := handler.job.Service()
+	ctx := handler.job.Context()
+
+	for _, c := range m.Clusters {
+		c := c
+		clusterInstance, xerr := clusterfactory.Load(svc, c.Name)
+		exists, xerr := found(xerr)
+		if xerr != nil {
+			return nil, xerr
+		}
+		if !exists {
+			continue
+		}
+		clusterInstance.Released()
+		steps = append(steps, plannedStep{
+			Step: manifest.Step{Kind: manifest.KindCluster, Name: c.Name, Action: manifest.ActionDelete},
+			run: func() fail.Error {
+				clusterInstance, xerr := clusterfactory.Load(svc, c.Name)
+				if xerr != nil {
+					return xerr
+				}
+				defer clusterInstance.Released()
+				return clusterInstance.Delete(ctx, false)
+			},
+		})
+	}
+
+	bucketHandler := NewBucketHandler(handler.job)
+	for _, b := range m.Buckets {
+		b := b
+		bucketInstance, xerr := bucketfactory.Load(svc, b.Name)
+		exists, xerr := found(xerr)
+		if xerr != nil {
+			return nil, xerr
+		}
+		if !exists {
+			continue
+		}
+		mountedOn, xerr := bucketInstance.GetHost(ctx)
+		bucketInstance.Released()
+		if xerr != nil {
+			return nil, xerr
+		}
+		if mountedOn != "" {
+			steps = append(steps, plannedStep{
+				Step: manifest.Step{Kind: manifest.KindBucket, Name: b.Name, Action: manifest.ActionUnmount, Detail: "from host '" + mountedOn + "'"},
+				run:  func() fail.Error { return bucketHandler.Unmount(b.Name, mountedOn) },
+			})
+		}
+		steps = append(steps, plannedStep{
+			Step: manifest.Step{Kind: manifest.KindBucket, Name: b.Name, Action: manifest.ActionDelete},
+			run:  func() fail.Error { return bucketHandler.Delete(b.Name) },
+		})
+	}
+
+	shareHandler := NewShareHandler(handler.job)
+	for _, s := range m.Shares {
+		s := s
+		shareInstance, xerr := sharefactory.Load(svc, s.Name)
+		exists, xerr := found(xerr)
+		if xerr != nil {
+			return nil, xerr
+		}
+		if !exists {
+			continue
+		}
+		mountedOn, xerr := shareClients(shareInstance)
+		shareInstance.Released()
+		if xerr != nil {
+			return nil, xerr
+		}
+		for _, h := range mountedOn {
+			h := h
+			steps = append(steps, plannedStep{
+				Step: manifest.Step{Kind: manifest.KindShare, Name: s.Name, Action: manifest.ActionUnmount, Detail: "from host '" + h + "'"},
+				run:  func() fail.Error { return shareHandler.Unmount(s.Name, h) },
+			})
+		}
+		steps = append(steps, plannedStep{
+			Step: manifest.Step{Kind: manifest.KindShare, Name: s.Name, Action: manifest.ActionDelete},
+			run:  func() fail.Error { return shareHandler.Delete(s.Name) },
+		})
+	}
+
+	volumeHandler := NewVolumeHandler(handler.job)
+	for _, v := range m.Volumes {
+		v := v
+		volumeInstance, xerr := volumefactory.Load(svc, v.Name)
+		exists, xerr := found(xerr)
+		if xerr != nil {
+			return nil, xerr
+		}
+		if !exists {
+			continue
+		}
+		attachments, xerr := volumeInstance.GetAttachments()
+		volumeInstance.Released()
+		if xerr != nil {
+			return nil, xerr
+		}
+		for _, h := range attachments.Hosts {
+			h := h
+			steps = append(steps, plannedStep{
+				Step: manifest.Step{Kind: manifest.KindVolume, Name: v.Name, Action: manifest.ActionDetach, Detail: "from host '" + h + "'"},
+				run:  func() fail.Error { return volumeHandler.Detach(v.Name, h) },
+			})
+		}
+		steps = append(steps, plannedStep{
+			Step: manifest.Step{Kind: manifest.KindVolume, Name: v.Name, Action: manifest.ActionDelete},
+			run:  func() fail.Error { return volumeHandler.Delete(v.Name) },
+		})
+	}
+
+	for _, h := range m.Hosts {
+		h := h
+		hostInstance, xerr := hostfactory.Load(svc, h.Name)
+		exists, xerr := found(xerr)
+		if xerr != nil {
+			return nil, xerr
+		}
+		if !exists {
+			continue
+		}
+		hostInstance.Released()
+		steps = append(steps, plannedStep{
+			Step: manifest.Step{Kind: manifest.KindHost, Name: h.Name, Action: manifest.ActionDelete},
+			run: func() fail.Error {
+				hostInstance, xerr := hostfactory.Load(svc, h.Name)
+				if xerr != nil {
+					return xerr
+				}
+				defer hostInstance.Released()
+				return hostInstance.Delete(ctx)
+			},
+		})
+	}
+
+	for _, sg := range m.SecurityGroups {
+		sg := sg
+		sgInstance, xerr := securitygroupfactory.Load(svc, sg.Name)
+		exists, xerr := found(xerr)
+		if xerr != nil {
+			return nil, xerr
+		}
+		if !exists {
+			continue
+		}
+		sgInstance.Released()
+		steps = append(steps, plannedStep{
+			Step: manifest.Step{Kind: manifest.KindSecurityGroup, Name: sg.Name, Action: manifest.ActionDelete},
+			run: func() fail.Error {
+				sgInstance, xerr := securitygroupfactory.Load(svc, sg.Name)
+				if xerr != nil {
+					return xerr
+				}
+				defer sgInstance.Released()
+				return sgInstance.Delete(ctx, false)
+			},
+		})
+	}
+
+	for _, n := range m.Networks {
+		n := n
+		for _, s := range n.Subnets {
+			s := s
+			subnetInstance, xerr := subnetfactory.Load(svc, n.Name, s.Name)
+			exists, xerr := found(xerr)
+			if xerr != nil {
+				return nil, xerr
+			}
+			if !exists {
+				continue
+			}
+			subnetInstance.Released()
+			steps = append(steps, plannedStep{
+				Step: manifest.Step{Kind: manifest.KindSubnet, Name: s.Name, Action: manifest.ActionDelete, Detail: "in network '" + n.Name + "'"},
+				run: func() fail.Error {
+					subnetInstance, xerr := subnetfactory.Load(svc, n.Name, s.Name)
+					if xerr != nil {
+						return xerr
+					}
+					defer subnetInstance.Released()
+
+					subnetID := subnetInstance.GetID()
+					networkInstance, xerr := subnetInstance.InspectNetwork()
+					if xerr != nil {
+						return xerr
+					}
+					defer networkInstance.Released()
+
+					if xerr = subnetInstance.Delete(ctx); xerr != nil {
+						return xerr
+					}
+					// The Network has to forget the Subnet to be deletable, as done by 'safescale subnet delete'
+					return networkInstance.AbandonSubnet(ctx, subnetID)
+				},
+			})
+		}
+	}
+
+	for _, n := range m.Networks {
+		n := n
+		if kept.Declares(manifest.KindNetwork, n.Name) {
+			continue
+		}
+		networkInstance, xerr := networkfactory.Load(svc, n.Name)
+		exists, xerr := found(xerr)
+		if xerr != nil {
+			return nil, xerr
+		}
+		if !exists {
+			continue
+		}
+		networkInstance.Released()
+		steps = append(steps, plannedStep{
+			Step: manifest.Step{Kind: manifest.KindNetwork, Name: n.Name, Action: manifest.ActionDelete},
+			run: func() fail.Error {
+				networkInstance, xerr := networkfactory.Load(svc, n.Name)
+				if xerr != nil {
+					return xerr
+				}
+				defer networkInstance.Released()
+				return networkInstance.Delete(ctx)
+			},
+		})
+	}
+
+	return steps, nil
+}
+
+// createNetwork creates a Network described in the manifest, without default Subnet
+func (handler *manifestHandler) createNetwork(n manifest.Network) fail.Error {
+	cidr := n.CIDR
+	if cidr == "" {
+		cidr = defaultManifestNetworkCIDR
+	}
+
+	networkInstance, xerr := networkfactory.New(handler.job.Service())
+	if xerr != nil {
+		return xerr
+	}
+
+	req := abstract.NetworkRequest{
+		Name:       n.Name,
+		CIDR:       cidr,
+		DNSServers: n.DNSServers,
+		Labels:     n.Labels,
+	}
+	if xerr = networkInstance.Create(handler.job.Context(), req); xerr != nil {
+		return xerr
+	}
+
+	networkInstance.Released()
+	return nil
+}
+
+// createSubnet creates a Subnet described in the manifest, with its gateway(s)
+func (handler *manifestHandler) createSubnet(n manifest.Network, s manifest.Subnet) fail.Error {
+	svc := handler.job.Service()
+	networkInstance, xerr := networkfactory.Load(svc, n.Name)
+	if xerr != nil {
+		return xerr
+	}
+	defer networkInstance.Released()
+
+	sizing := &abstract.HostSizingRequirements{MinGPU: -1}
+	if s.Gateway.Sizing != "" {
+		if sizing, _, xerr = converters.HostSizingRequirementsFromStringToAbstract(s.Gateway.Sizing); xerr != nil {
+			return xerr
+		}
+	}
+
+	subnetInstance, xerr := subnetfactory.New(svc)
+	if xerr != nil {
+		return xerr
+	}
+
+	req := abstract.SubnetRequest{
+		NetworkID:      networkInstance.GetID(),
+		Name:           s.Name,
+		CIDR:           s.CIDR,
+		HA:             s.Failover,
+		ImageRef:       s.Gateway.OS,
+		DefaultSSHPort: s.Gateway.SSHPort,
+		GatewayLabels:  n.Labels,
+	}
+	if xerr = subnetInstance.Create(handler.job.Context(), req, s.Gateway.Name, sizing); xerr != nil {
+		return xerr
+	}
+
+	subnetInstance.Released()
+	return nil
+}
+
+// createSecurityGroup creates a Security Group described in the manifest
+func (handler *manifestHandler) createSecurityGroup(sg manifest.SecurityGroup) fail.Error {
+	svc := handler.job.Service()
+	networkInstance, xerr := networkfactory.Load(svc, sg.Network)
+	if xerr != nil {
+		return xerr
+	}
+	defer networkInstance.Released()
+
+	rules, xerr := sg.AbstractRules()
+	if xerr != nil {
+		return xerr
+	}
+
+	sgInstance, xerr := securitygroupfactory.New(svc)
+	if xerr != nil {
+		return xerr
+	}
+
+	if xerr = sgInstance.Create(handler.job.Context(), networkInstance.GetID(), sg.Name, sg.Description, rules); xerr != nil {
+		return xerr
+	}
+
+	sgInstance.Released()
+	return nil
+}
+
+// createHost creates a Host described in the manifest
+func (handler *manifestHandler) createHost(h manifest.Host) fail.Error {
+	svc := handler.job.Service()
+
+	sizing := &abstract.HostSizingRequirements{MinGPU: -1}
+	if h.Sizing != "" {
+		var xerr fail.Error
+		if sizing, _, xerr = converters.HostSizingRequirementsFromStringToAbstract(h.Sizing); xerr != nil {
+			return xerr
+		}
+	}
+
+	var subnets []*abstract.Subnet
+	if !h.Single {
+		// Like 'safescale host create', the Subnet defaults to the one named as the Network
+		subnetName := h.Subnet
+		if subnetName == "" {
+			subnetName = h.Network
+		}
+		subnetInstance, xerr := subnetfactory.Load(svc, h.Network, subnetName)
+		if xerr != nil {
+			return xerr
+		}
+		defer subnetInstance.Released()
+
+		xerr = subnetInstance.Review(func(clonable data.Clonable, _ *serialize.JSONProperties) fail.Error {
+			as, ok := clonable.(*abstract.Subnet)
+			if !ok {
+				return fail.InconsistentError("'*abstract.Subnet' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			subnets = append(subnets, as)
+			return nil
+		})
+		if xerr != nil {
+			return xerr
+		}
+	}
+
+	domain := strings.Trim(h.Domain, ".")
+	if domain != "" {
+		domain = "." + domain
+	}
+
+	req := abstract.HostRequest{
+		ResourceName: h.Name,
+		HostName:     h.Name + domain,
+		Single:       h.Single,
+		Subnets:      subnets,
+		ImageRef:     h.OS,
+		Labels:       h.Labels,
+	}
+
+	hostInstance, xerr := hostfactory.New(svc)
+	if xerr != nil {
+		return xerr
+	}
+
+	if _, xerr = hostInstance.Create(handler.job.Context(), req, *sizing); xerr != nil {
+		return xerr
+	}
+
+	hostInstance.Released()
+	return nil
+}
+
+// bindSecurityGroup binds and enables a Security Group on a Host
+func (handler *manifestHandler) bindSecurityGroup(sgName, hostName string) fail.Error {
+	svc := handler.job.Service()
+	hostInstance, xerr := hostfactory.Load(svc, hostName)
+	if xerr != nil {
+		return xerr
+	}
+	defer hostInstance.Released()
+
+	sgInstance, xerr := securitygroupfactory.Load(svc, sgName)
+	if xerr != nil {
+		return xerr
+	}
+	defer sgInstance.Released()
+
+	return hostInstance.BindSecurityGroup(handler.job.Context(), sgInstance, resources.SecurityGroupEnable)
+}
+
+// createCluster creates a Cluster described in the manifest
+func (handler *manifestHandler) createCluster(c manifest.Cluster) fail.Error {
+	complexity := clustercomplexity.Small
+	if c.Complexity != "" {
+		var err error
+		if complexity, err = clustercomplexity.Parse(c.Complexity); err != nil {
+			return fail.ConvertError(err)
+		}
+	}
+	flavor := clusterflavor.Enum(clusterflavor.K8S)
+	if c.Flavor != "" {
+		var err error
+		if flavor, err = clusterflavor.Parse(c.Flavor); err != nil {
+			return fail.ConvertError(err)
+		}
+	}
+	cidr := c.CIDR
+	if cidr == "" {
+		cidr = stacks.DefaultNetworkCIDR
+	}
+
+	in := &protocol.ClusterCreateRequest{
+		Name:          c.Name,
+		Complexity:    protocol.ClusterComplexity(complexity),
+		Flavor:        protocol.ClusterFlavor(flavor),
+		Cidr:          cidr,
+		Disabled:      c.Disabled,
+		Os:            c.OS,
+		GlobalSizing:  c.Sizing,
+		GatewaySizing: c.GatewaySizing,
+		MasterSizing:  c.MasterSizing,
+		NodeSizing:    c.NodeSizing,
+		Domain:        c.Domain,
+		Labels:        c.Labels,
+	}
+	req, xerr := converters.ClusterRequestFromProtocolToAbstract(in)
+	if xerr != nil {
+		return xerr
+	}
+	req.Tenant = handler.job.Tenant()
+
+	clusterInstance, xerr := clusterfactory.New(handler.job.Service())
+	if xerr != nil {
+		return xerr
+	}
+
+	if xerr = clusterInstance.Create(handler.job.Context(), req); xerr != nil {
+		return xerr
+	}
+
+	clusterInstance.Released()
+	return nil
+}
+
+// differences lists the attributes of an existing resource differing from the manifest, as '<attribute>: <current> -> <wanted>'
+type differences []string
+
+// add records the attribute if its current and wanted values differ
+func (d *differences) add(attribute string, current, wanted interface{}) {
+	if !reflect.DeepEqual(current, wanted) {
+		*d = append(*d, fmt.Sprintf("%s: %v -> %v", attribute, current, wanted))
+	}
+}
+
+// addLabels records the labels if they differ, no label being the same as an empty set of labels
+func (d *differences) addLabels(current, wanted map[string]string) {
+	if len(current) == 0 && len(wanted) == 0 {
+		return
+	}
+	if !reflect.DeepEqual(current, wanted) {
+		*d = append(*d, fmt.Sprintf("labels: %s -> %s", labelsString(current), labelsString(wanted)))
+	}
+}
+
+// steps returns the drift reporting the differences, if any; SafeScale cannot change these attributes on an existing resource
+func (d differences) steps(kind, name, detail string) []plannedStep {
+	if len(d) == 0 {
+		return nil
+	}
+
+	list := []string(d)
+	if detail != "" {
+		list = append([]string{detail}, list...)
+	}
+	return []plannedStep{{Step: manifest.Step{Kind: kind, Name: name, Action: manifest.ActionUpdate, Detail: strings.Join(list, ", "), Drift: true}}}
+}
+
+// labelsString returns a stable representation of labels, as '{key=value,...}'
+func labelsString(labels map[string]string) string {
+	list := make([]string, 0, len(labels))
+	for k, v := range labels {
+		list = append(list, k+"="+v)
+	}
+	sort.Strings(list)
+	return "{" + strings.Join(list, ",") + "}"
+}
+
+// networkDifferences compares an existing Network with its description in the manifest
+func networkDifferences(networkInstance resources.Network, n manifest.Network) (differences, fail.Error) {
+	var (
+		diff differences
+		cidr string
+	)
+	xerr := networkInstance.Review(func(clonable data.Clonable, _ *serialize.JSONProperties) fail.Error {
+		an, ok := clonable.(*abstract.Network)
+		if !ok {
+			return fail.InconsistentError("'*abstract.Network' expected, '%s' provided", reflect.TypeOf(clonable).String())
+		}
+
+		cidr = an.CIDR
+		return nil
+	})
+	if xerr != nil {
+		return nil, xerr
+	}
+	if n.CIDR != "" {
+		diff.add("cidr", cidr, n.CIDR)
+	}
+
+	labels, xerr := networkInstance.GetLabels()
+	if xerr != nil {
+		return nil, xerr
+	}
+	diff.addLabels(labels, n.Labels)
+	return diff, nil
+}
+
+// subnetDifferences compares an existing Subnet with its description in the manifest
+func subnetDifferences(subnetInstance resources.Subnet, s manifest.Subnet) (differences, fail.Error) {
+	var (
+		diff differences
+		cidr string
+	)
+	xerr := subnetInstance.Review(func(clonable data.Clonable, _ *serialize.JSONProperties) fail.Error {
+		as, ok := clonable.(*abstract.Subnet)
+		if !ok {
+			return fail.InconsistentError("'*abstract.Subnet' expected, '%s' provided", reflect.TypeOf(clonable).String())
+		}
+
+		cidr = as.CIDR
+		return nil
+	})
+	if xerr != nil {
+		return nil, xerr
+	}
+	diff.add("cidr", cidr, s.CIDR)
+	return diff, nil
+}
+
+// securityGroupDifferences compares an existing Security Group with its description in the manifest
+func securityGroupDifferences(sgInstance resources.SecurityGroup, sg manifest.SecurityGroup) (differences, fail.Error) {
+	var (
+		diff        differences
+		description string
+		current     abstract.SecurityGroupRules
+	)
+	xerr := sgInstance.Review(func(clonable data.Clonable, _ *serialize.JSONProperties) fail.Error {
+		asg, ok := clonable.(*abstract.SecurityGroup)
+		if !ok {
+			return fail.InconsistentError("'*abstract.SecurityGroup' expected, '%s' provided", reflect.TypeOf(clonable).String())
+		}
+
+		description = asg.Description
+		current = asg.Rules
+		return nil
+	})
+	if xerr != nil {
+		return nil, xerr
+	}
+	if sg.Description != "" {
+		diff.add("description", description, sg.Description)
+	}
+
+	wanted, xerr := sg.AbstractRules()
+	if xerr != nil {
+		return nil, xerr
+	}
+	missing, undeclared := unmatchedRules(wanted, current), unmatchedRules(current, wanted)
+	if missing > 0 || undeclared > 0 {
+		diff = append(diff, fmt.Sprintf("rules: %d missing, %d not declared", missing, undeclared))
+	}
+	return diff, nil
+}
+
+// unmatchedRules counts the rules of list without equivalent in other
+func unmatchedRules(list, other abstract.SecurityGroupRules) int {
+	count := 0
+	for _, v := range list {
+		found := false
+		for _, w := range other {
+			if v.EquivalentTo(w) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			count++
+		}
+	}
+	return count
+}
+
+// hostDifferences compares an existing Host with its description in the manifest
+func hostDifferences(hostInstance resources.Host, h manifest.Host) (differences, fail.Error) {
+	var diff differences
+	labels, xerr := hostInstance.GetLabels()
+	if xerr != nil {
+		return nil, xerr
+	}
+	diff.addLabels(labels, h.Labels)
+	return diff, nil
+}
+
+// volumeDifferences compares an existing Volume with its description in the manifest
+func volumeDifferences(volumeInstance resources.Volume, v manifest.Volume) (differences, fail.Error) {
+	var diff differences
+	if v.Size != 0 {
+		size, xerr := volumeInstance.GetSize()
+		if xerr != nil {
+			return nil, xerr
+		}
+		diff.add("size", size, v.Size)
+	}
+	if v.Speed != "" {
+		speed, xerr := volumeInstance.GetSpeed()
+		if xerr != nil {
+			return nil, xerr
+		}
+		wanted, xerr := v.AbstractSpeed()
+		if xerr != nil {
+			return nil, xerr
+		}
+		diff.add("speed", speed, wanted)
+	}
+
+	labels, xerr := volumeInstance.GetLabels()
+	if xerr != nil {
+		return nil, xerr
+	}
+	diff.addLabels(labels, v.Labels)
+	return diff, nil
+}
+
+// clusterDifferences compares an existing Cluster with its description in the manifest
+func clusterDifferences(clusterInstance resources.Cluster, c manifest.Cluster) (differences, fail.Error) {
+	var diff differences
+	if c.Flavor != "" {
+		flavor, xerr := clusterInstance.GetFlavor()
+		if xerr != nil {
+			return nil, xerr
+		}
+		wanted, err := clusterflavor.Parse(c.Flavor)
+		if err != nil {
+			return nil, fail.ConvertError(err)
+		}
+		diff.add("flavor", flavor, wanted)
+	}
+	if c.Complexity != "" {
+		complexity, xerr := clusterInstance.GetComplexity()
+		if xerr != nil {
+			return nil, xerr
+		}
+		wanted, err := clustercomplexity.Parse(c.Complexity)
+		if err != nil {
+			return nil, fail.ConvertError(err)
+		}
+		diff.add("complexity", complexity, wanted)
+	}
+
+	labels, xerr := clusterInstance.GetLabels()
+	if xerr != nil {
+		return nil, xerr
+	}
+	diff.addLabels(labels, c.Labels)
+	return diff, nil
+}
+
+// shareClients returns the names of the hosts mounting a Share
+func shareClients(shareInstance resources.Share) ([]string, fail.Error) {
+	list, xerr := shareInstance.ToProtocol()
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	out := make([]string, 0, len(list.GetMountList()))
+	for _, v := range list.GetMountList() {
+		out = append(out, v.GetHost().GetName())
+	}
+	return out, nil
+}
+
+// contains tells if a string is in a slice of strings
+func contains(list []string, item string) bool {
+	for _, v := range list {
+		if v == item {
+			return true
+		}
+	}
+	return false
+}
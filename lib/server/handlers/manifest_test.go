@@ -0,0 +1,178 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server"
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	_ "github.com/CS-SI/SafeScale/lib/server/iaas/providers/memory"
+	memorystack "github.com/CS-SI/SafeScale/lib/server/iaas/stacks/memory"
+	"github.com/CS-SI/SafeScale/lib/server/manifest"
+)
+
+// getManifestJob returns a job working on a fresh in-memory tenant
+func getManifestJob(t *testing.T) server.Job {
+	memorystack.Forget("TestManifest")
+
+	tenant := map[string]interface{}{
+		"name":   "TestManifest",
+		"client": "memory",
+		"compute": map[string]interface{}{
+			"Region": "test",
+		},
+		"objectstorage": map[string]interface{}{
+			"Type":     "memory",
+			"Endpoint": "TestManifest",
+		},
+	}
+	svc, xerr := iaas.BuildService(tenant, "v21.05.0")
+	require.Nil(t, xerr)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	job, xerr := server.NewJob(ctx, cancel, svc, "manifest test")
+	require.Nil(t, xerr)
+	t.Cleanup(job.Close)
+	return job
+}
+
+func TestManifestHandler_ApplyDestroy(t *testing.T) {
+	content := `
+networks:
+  - name: net-manifest
+    cidr: 192.168.10.0/24
+volumes:
+  - name: vol-manifest
+    size: 20
+    labels:
+      Env: test
+buckets:
+  - name: bucket-manifest
+`
+	m, xerr := manifest.Decode([]byte(content), "yaml")
+	require.Nil(t, xerr)
+
+	handler := NewManifestHandler(getManifestJob(t))
+
+	plan, xerr := handler.Plan(m, false, false)
+	require.Nil(t, xerr)
+	require.Len(t, plan, 3)
+	assert.Equal(t, manifest.Step{Kind: manifest.KindNetwork, Name: "net-manifest", Action: manifest.ActionCreate}, plan[0])
+	assert.Equal(t, manifest.KindVolume, plan[1].Kind)
+	assert.Equal(t, manifest.KindBucket, plan[2].Kind)
+
+	done, xerr := handler.Apply(m, false)
+	require.Nil(t, xerr)
+	assert.Equal(t, plan, done)
+
+	// Once applied, nothing remains to do
+	plan, xerr = handler.Plan(m, false, false)
+	require.Nil(t, xerr)
+	assert.True(t, plan.IsEmpty())
+
+	plan, xerr = handler.Plan(m, true, false)
+	require.Nil(t, xerr)
+	require.Len(t, plan, 3)
+	assert.Equal(t, manifest.Step{Kind: manifest.KindBucket, Name: "bucket-manifest", Action: manifest.ActionDelete}, plan[0])
+	assert.Equal(t, manifest.Step{Kind: manifest.KindNetwork, Name: "net-manifest", Action: manifest.ActionDelete}, plan[2])
+
+	done, xerr = handler.Destroy(m)
+	require.Nil(t, xerr)
+	assert.Equal(t, plan, done)
+
+	plan, xerr = handler.Plan(m, true, false)
+	require.Nil(t, xerr)
+	assert.True(t, plan.IsEmpty(), plan)
+}
+
+func TestManifestHandler_Drift(t *testing.T) {
+	applied := `
+name: drift
+networks:
+  - name: net-drift
+    cidr: 192.168.20.0/24
+    labels:
+      Env: test
+volumes:
+  - name: vol-kept
+    size: 10
+  - name: vol-removed
+buckets:
+  - name: bucket-removed
+`
+	changed := `
+name: drift
+networks:
+  - name: net-drift
+    cidr: 192.168.20.0/24
+    labels:
+      Env: prod
+volumes:
+  - name: vol-kept
+    size: 20
+`
+	m, xerr := manifest.Decode([]byte(applied), "yaml")
+	require.Nil(t, xerr)
+
+	handler := NewManifestHandler(getManifestJob(t))
+	_, xerr = handler.Apply(m, false)
+	require.Nil(t, xerr)
+
+	m, xerr = manifest.Decode([]byte(changed), "yaml")
+	require.Nil(t, xerr)
+
+	expected := manifest.Plan{
+		{Kind: manifest.KindNetwork, Name: "net-drift", Action: manifest.ActionUpdate, Detail: "labels: {Env=test} -> {Env=prod}", Drift: true},
+		{Kind: manifest.KindVolume, Name: "vol-kept", Action: manifest.ActionUpdate, Detail: "size: 10 -> 20", Drift: true},
+		{Kind: manifest.KindBucket, Name: "bucket-removed", Action: manifest.ActionDelete, Drift: true},
+		{Kind: manifest.KindVolume, Name: "vol-removed", Action: manifest.ActionDelete, Drift: true},
+	}
+	plan, xerr := handler.Plan(m, false, false)
+	require.Nil(t, xerr)
+	assert.Equal(t, expected, plan)
+
+	// Without prune, nothing is done and the removed resources are still reported
+	done, xerr := handler.Apply(m, false)
+	require.Nil(t, xerr)
+	assert.True(t, done.IsEmpty())
+	plan, xerr = handler.Plan(m, false, false)
+	require.Nil(t, xerr)
+	assert.Equal(t, expected, plan)
+
+	plan, xerr = handler.Plan(m, false, true)
+	require.Nil(t, xerr)
+	require.Len(t, plan.Applicable(), 2)
+	done, xerr = handler.Apply(m, true)
+	require.Nil(t, xerr)
+	assert.Equal(t, plan.Applicable(), done)
+
+	plan, xerr = handler.Plan(m, false, false)
+	require.Nil(t, xerr)
+	assert.Equal(t, expected[:2], plan)
+
+	done, xerr = handler.Destroy(m)
+	require.Nil(t, xerr)
+	require.Len(t, done, 2)
+	plan, xerr = handler.Plan(m, true, false)
+	require.Nil(t, xerr)
+	assert.True(t, plan.IsEmpty(), plan)
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumeproperty"
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumespeed"
 	hostfactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/host"
+	snapshotfactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/snapshot"
 	volumefactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/volume"
 	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
 	"github.com/CS-SI/SafeScale/lib/utils/data"
@@ -45,9 +46,15 @@ type VolumeHandler interface {
 	Delete(ref string) fail.Error
 	List(all bool) ([]resources.Volume, fail.Error)
 	Inspect(ref string) (resources.Volume, fail.Error)
-	Create(name string, size int, speed volumespeed.Enum) (resources.Volume, fail.Error)
+	Create(name string, size int, speed volumespeed.Enum, labels map[string]string) (resources.Volume, fail.Error)
 	Attach(volume string, host string, path string, format string, doNotFormat bool) fail.Error
 	Detach(volume string, host string) fail.Error
+	CreateSnapshot(volume string, name string, description string) (resources.Snapshot, fail.Error)
+	ListSnapshots(volume string, all bool) ([]*abstract.Snapshot, fail.Error)
+	DeleteSnapshot(ref string) fail.Error
+	RestoreSnapshot(snapshot string, name string, size int, speed volumespeed.Enum) (resources.Volume, fail.Error)
+	Import(ref string) (resources.Volume, fail.Error)
+	Resize(ref string, size int) (resources.Volume, fail.Error)
 }
 
 // TODO: At service level, ve need to log before returning, because it's the last chance to track the real issue in server side
@@ -183,7 +190,7 @@ func (handler *volumeHandler) Inspect(ref string) (volume resources.Volume, xerr
 }
 
 // Create a volume
-func (handler *volumeHandler) Create(name string, size int, speed volumespeed.Enum) (objv resources.Volume, xerr fail.Error) {
+func (handler *volumeHandler) Create(name string, size int, speed volumespeed.Enum, labels map[string]string) (objv resources.Volume, xerr fail.Error) {
 	defer fail.OnPanic(&xerr)
 
 	if handler == nil {
@@ -205,9 +212,10 @@ func (handler *volumeHandler) Create(name string, size int, speed volumespeed.En
 		return nil, xerr
 	}
 	request := abstract.VolumeRequest{
-		Name:  name,
-		Size:  size,
-		Speed: speed,
+		Name:   name,
+		Size:   size,
+		Speed:  speed,
+		Labels: labels,
 	}
 	if xerr = objv.Create(handler.job.Context(), request); xerr != nil {
 		return nil, xerr
@@ -296,3 +304,224 @@ func (handler *volumeHandler) Detach(volumeRef, hostRef string) (xerr fail.Error
 
 	return rv.Detach(handler.job.Context(), rh)
 }
+
+// CreateSnapshot takes a snapshot of the volume identified by volumeRef, ref can be the name or the id
+func (handler *volumeHandler) CreateSnapshot(volumeRef, name, description string) (_ resources.Snapshot, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if handler.job == nil {
+		return nil, fail.InvalidInstanceContentError("handler.job", "cannot be nil")
+	}
+	if volumeRef == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("volumeRef")
+	}
+	if name == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("name")
+	}
+
+	tracer := debug.NewTracer(handler.job.Task(), tracing.ShouldTrace("handlers.volume"), "('%s', '%s')", volumeRef, name).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&xerr, tracer.TraceMessage())
+
+	svc := handler.job.Service()
+	volumeInstance, xerr := volumefactory.Load(svc, volumeRef)
+	if xerr != nil {
+		if _, ok := xerr.(*fail.ErrNotFound); ok {
+			return nil, abstract.ResourceNotFoundError("volume", volumeRef)
+		}
+		return nil, xerr
+	}
+	defer volumeInstance.Released()
+
+	snapshotInstance, xerr := snapshotfactory.New(svc)
+	if xerr != nil {
+		return nil, xerr
+	}
+	request := abstract.SnapshotRequest{
+		Name:        name,
+		VolumeID:    volumeInstance.GetID(),
+		Description: description,
+	}
+	if xerr = snapshotInstance.Create(handler.job.Context(), request); xerr != nil {
+		return nil, xerr
+	}
+	return snapshotInstance, nil
+}
+
+// ListSnapshots returns the snapshots of the volume identified by volumeRef (all the snapshots if volumeRef is empty)
+// If all is true, lists also the snapshots not managed by SafeScale
+func (handler *volumeHandler) ListSnapshots(volumeRef string, all bool) (_ []*abstract.Snapshot, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if handler.job == nil {
+		return nil, fail.InvalidInstanceContentError("handler.job", "cannot be nil")
+	}
+
+	tracer := debug.NewTracer(handler.job.Task(), tracing.ShouldTrace("handlers.volume"), "('%s', %v)", volumeRef, all).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&xerr, tracer.TraceMessage())
+
+	svc := handler.job.Service()
+	var volumeID string
+	if volumeRef != "" {
+		volumeInstance, xerr := volumefactory.Load(svc, volumeRef)
+		if xerr != nil {
+			if _, ok := xerr.(*fail.ErrNotFound); ok {
+				return nil, abstract.ResourceNotFoundError("volume", volumeRef)
+			}
+			return nil, xerr
+		}
+		volumeID = volumeInstance.GetID()
+		volumeInstance.Released()
+	}
+
+	return snapshotfactory.List(handler.job.Context(), svc, volumeID, all)
+}
+
+// DeleteSnapshot deletes the volume snapshot identified by ref, ref can be the name or the id
+func (handler *volumeHandler) DeleteSnapshot(ref string) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if handler == nil {
+		return fail.InvalidInstanceError()
+	}
+	if handler.job == nil {
+		return fail.InvalidInstanceContentError("handler.job", "cannot be nil")
+	}
+	if ref == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("ref")
+	}
+
+	tracer := debug.NewTracer(handler.job.Task(), tracing.ShouldTrace("handlers.volume"), "('%s')", ref).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&xerr, tracer.TraceMessage())
+
+	snapshotInstance, xerr := snapshotfactory.Load(handler.job.Service(), ref)
+	if xerr != nil {
+		if _, ok := xerr.(*fail.ErrNotFound); ok {
+			return abstract.ResourceNotFoundError("snapshot", ref)
+		}
+		return xerr
+	}
+
+	xerr = snapshotInstance.Delete(handler.job.Context())
+	if xerr != nil {
+		snapshotInstance.Released()
+		return xerr
+	}
+
+	return nil
+}
+
+// RestoreSnapshot creates a new volume named name from the snapshot identified by snapshotRef, ref can be the name or the id
+// If size is 0, the size of the snapshot is used
+func (handler *volumeHandler) RestoreSnapshot(snapshotRef, name string, size int, speed volumespeed.Enum) (_ resources.Volume, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if handler.job == nil {
+		return nil, fail.InvalidInstanceContentError("handler.job", "cannot be nil")
+	}
+	if snapshotRef == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("snapshotRef")
+	}
+	if name == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("name")
+	}
+
+	tracer := debug.NewTracer(handler.job.Task(), tracing.ShouldTrace("handlers.volume"), "('%s', '%s', %d, %s)", snapshotRef, name, size, speed.String()).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&xerr, tracer.TraceMessage())
+
+	snapshotInstance, xerr := snapshotfactory.Load(handler.job.Service(), snapshotRef)
+	if xerr != nil {
+		if _, ok := xerr.(*fail.ErrNotFound); ok {
+			return nil, abstract.ResourceNotFoundError("snapshot", snapshotRef)
+		}
+		return nil, xerr
+	}
+	defer snapshotInstance.Released()
+
+	request := abstract.VolumeRequest{
+		Name:  name,
+		Size:  size,
+		Speed: speed,
+	}
+	return snapshotInstance.Restore(handler.job.Context(), request)
+}
+
+// Import registers in metadata the existing volume of the provider identified by ref
+func (handler *volumeHandler) Import(ref string) (_ resources.Volume, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if handler.job == nil {
+		return nil, fail.InvalidInstanceContentError("handler.job", "cannot be nil")
+	}
+	if ref == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("ref")
+	}
+
+	tracer := debug.NewTracer(handler.job.Task(), tracing.ShouldTrace("handlers.volume"), "('%s')", ref).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&xerr, tracer.TraceMessage())
+
+	volumeInstance, xerr := volumefactory.New(handler.job.Service())
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	xerr = volumeInstance.Import(handler.job.Context(), ref)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return volumeInstance, nil
+}
+
+// Resize grows the volume identified by ref to size GB, including the file system on the hosts where it is attached
+func (handler *volumeHandler) Resize(ref string, size int) (_ resources.Volume, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if handler == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if handler.job == nil {
+		return nil, fail.InvalidInstanceContentError("handler.job", "cannot be nil")
+	}
+	if ref == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("ref")
+	}
+	if size <= 0 {
+		return nil, fail.InvalidParameterError("size", "must be greater than 0")
+	}
+
+	tracer := debug.NewTracer(handler.job.Task(), tracing.ShouldTrace("handlers.volume"), "('%s', %d)", ref, size).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&xerr, tracer.TraceMessage())
+
+	volumeInstance, xerr := volumefactory.Load(handler.job.Service(), ref)
+	if xerr != nil {
+		if _, ok := xerr.(*fail.ErrNotFound); ok {
+			return nil, abstract.ResourceNotFoundError("volume", ref)
+		}
+		return nil, xerr
+	}
+
+	xerr = volumeInstance.Resize(handler.job.Context(), size)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return volumeInstance, nil
+}
@@ -78,15 +78,12 @@ func UseService(tenantName, metadataVersion string) (newService Service, xerr fa
 	}
 
 	var (
-		tenantInCfg    bool
-		found          bool
-		name, provider string
-		svc            Service
-		svcProvider    = "__not_found__"
+		tenantInCfg bool
+		svcProvider = "__not_found__"
 	)
 
 	for _, tenant := range tenants {
-		name, found = tenant["name"].(string)
+		name, found := tenant["name"].(string)
 		if !found {
 			logrus.Error("tenant found without 'name'")
 			continue
@@ -96,187 +93,213 @@ func UseService(tenantName, metadataVersion string) (newService Service, xerr fa
 		}
 
 		tenantInCfg = true
-		provider, found = tenant["provider"].(string)
+		provider, found := tenantProviderName(tenant)
 		if !found {
-			provider, found = tenant["client"].(string)
-			if !found {
-				logrus.Error("Missing field 'provider' in tenant")
-				continue
-			}
+			logrus.Error("Missing field 'provider' in tenant")
+			continue
 		}
 
 		svcProvider = provider
-		svc, found = allProviders[provider]
-		if !found {
+		if _, found = allProviders[provider]; !found {
 			logrus.Errorf("failed to find client '%s' for tenant '%s'", svcProvider, name)
 			continue
 		}
 
-		_, found = tenant["identity"].(map[string]interface{})
-		if !found {
-			logrus.Debugf("No section 'identity' found in tenant '%s', continuing.", name)
-		}
-		_, found = tenant["compute"].(map[string]interface{})
-		if !found {
-			logrus.Debugf("No section 'compute' found in tenant '%s', continuing.", name)
-		}
-		_, found = tenant["network"].(map[string]interface{})
-		if !found {
-			logrus.Debugf("No section 'network' found in tenant '%s', continuing.", name)
-		}
+		return BuildService(tenant, metadataVersion)
+	}
 
-		_, tenantObjectStorageFound := tenant["objectstorage"]
-		_, tenantMetadataFound := tenant["metadata"]
+	if !tenantInCfg {
+		return NullService(), fail.NotFoundError("tenant '%s' not found in configuration", tenantName)
+	}
+	return NullService(), fail.NotFoundError("provider builder for '%s'", svcProvider)
+}
 
-		// Initializes Provider
-		providerInstance, xerr := svc.Build(tenant)
-		if xerr != nil {
-			return NullService(), fail.Wrap(xerr, "error initializing tenant '%s' on provider '%s'", tenantName, provider)
-		}
+// tenantProviderName returns the name of the provider used by the tenant, read from field 'provider' or 'client'
+func tenantProviderName(tenant map[string]interface{}) (string, bool) {
+	provider, found := tenant["provider"].(string)
+	if !found {
+		provider, found = tenant["client"].(string)
+	}
+	return provider, found
+}
+
+// BuildService builds the service of a tenant from its definition (content of a '[[tenants]]' entry of tenants.toml),
+// without reading the configuration file
+func BuildService(tenant map[string]interface{}, metadataVersion string) (newService Service, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
 
-		newS := &service{
-			Provider:   providerInstance,
-			cache:      serviceCache{map[string]*ResourceCache{}},
-			cacheLock:  &sync.Mutex{},
-			tenantName: tenantName,
+	tenantName, found := tenant["name"].(string)
+	if !found || tenantName == "" {
+		return NullService(), fail.SyntaxError("field 'name' not found in tenant definition")
+	}
+	provider, found := tenantProviderName(tenant)
+	if !found {
+		return NullService(), fail.SyntaxError("missing field 'provider' in tenant '%s'", tenantName)
+	}
+	svc, found := allProviders[provider]
+	if !found {
+		return NullService(), fail.NotFoundError("provider builder for '%s'", provider)
+	}
+
+	_, found = tenant["identity"].(map[string]interface{})
+	if !found {
+		logrus.Debugf("No section 'identity' found in tenant '%s', continuing.", tenantName)
+	}
+	_, found = tenant["compute"].(map[string]interface{})
+	if !found {
+		logrus.Debugf("No section 'compute' found in tenant '%s', continuing.", tenantName)
+	}
+	_, found = tenant["network"].(map[string]interface{})
+	if !found {
+		logrus.Debugf("No section 'network' found in tenant '%s', continuing.", tenantName)
+	}
+
+	_, tenantObjectStorageFound := tenant["objectstorage"]
+	_, tenantMetadataFound := tenant["metadata"]
+
+	// Initializes Provider
+	providerInstance, xerr := svc.Build(tenant)
+	if xerr != nil {
+		return NullService(), fail.Wrap(xerr, "error initializing tenant '%s' on provider '%s'", tenantName, provider)
+	}
+
+	newS := &service{
+		Provider:   providerInstance,
+		cache:      serviceCache{map[string]*ResourceCache{}},
+		cacheLock:  &sync.Mutex{},
+		tenantName: tenantName,
+	}
+
+	// allRegions, xerr := newS.ListRegions()
+	// if xerr != nil {
+	// 	switch xerr.(type) {
+	// 	case *fail.ErrNotFound:
+	// 		break
+	// 	default:
+	// 		return NullService(), xerr
+	// 	}
+	// }
+
+	authOpts, xerr := providerInstance.GetAuthenticationOptions()
+	if xerr != nil {
+		return NullService(), xerr
+	}
+
+	// Validate region parameter in compute section
+	// VPL: does not work with Outscale "cloudgouv"...
+	// computeRegion := authOpts.GetString("Region")
+	// xerr = validateRegionName(computeRegion, allRegions)
+	// if xerr != nil {
+	// 	return NullService(), fail.Wrap(xerr, "invalid region in section 'compute'")
+	// }
+
+	// Initializes Object Storage
+	var objectStorageLocation objectstorage.Location
+	if tenantObjectStorageFound {
+		objectStorageConfig, xerr := initObjectStorageLocationConfig(authOpts, tenant)
+		if xerr != nil {
+			return NullService(), xerr
 		}
 
-		// allRegions, xerr := newS.ListRegions()
+		// VPL: disable region validation, may need to update allRegions for objectstorage/metadata)
+		// xerr = validateRegionName(objectStorageConfig.Region, allRegions)
 		// if xerr != nil {
-		// 	switch xerr.(type) {
-		// 	case *fail.ErrNotFound:
-		// 		break
-		// 	default:
-		// 		return NullService(), xerr
-		// 	}
+		// 	return nil, fail.Wrap(xerr, "invalid region in section 'objectstorage")
 		// }
 
-		authOpts, xerr := providerInstance.GetAuthenticationOptions()
+		objectStorageLocation, xerr = objectstorage.NewLocation(objectStorageConfig)
 		if xerr != nil {
-			return NullService(), xerr
+			return NullService(), fail.Wrap(xerr, "error connecting to Object Storage location")
+		}
+	} else {
+		logrus.Warnf("missing section 'objectstorage' in configuration file for tenant '%s'", tenantName)
+	}
+
+	// Initializes Metadata Object Storage (may be different than the Object Storage)
+	var (
+		metadataBucket   abstract.ObjectStorageBucket
+		metadataCryptKey *crypt.Key
+	)
+	if tenantMetadataFound || tenantObjectStorageFound {
+		// FIXME: This requires tuning too
+		metadataLocationConfig, err := initMetadataLocationConfig(authOpts, tenant)
+		if err != nil {
+			return NullService(), err
 		}
 
-		// Validate region parameter in compute section
-		// VPL: does not work with Outscale "cloudgouv"...
-		// computeRegion := authOpts.GetString("Region")
-		// xerr = validateRegionName(computeRegion, allRegions)
+		// VPL: disable region validation, may need to update allRegions for objectstorage/metadata)
+		// xerr = validateRegionName(metadataLocationConfig.Region, allRegions)
 		// if xerr != nil {
-		// 	return NullService(), fail.Wrap(xerr, "invalid region in section 'compute'")
+		// 	return nil, fail.Wrap(xerr, "invalid region in section 'metadata'")
 		// }
 
-		// Initializes Object Storage
-		var objectStorageLocation objectstorage.Location
-		if tenantObjectStorageFound {
-			objectStorageConfig, xerr := initObjectStorageLocationConfig(authOpts, tenant)
+		metadataLocation, err := objectstorage.NewLocation(metadataLocationConfig)
+		if err != nil {
+			return NullService(), fail.Wrap(err, "error connecting to Object Storage location to store metadata")
+		}
+
+		if metadataLocationConfig.BucketName == "" {
+			serviceCfg, xerr := providerInstance.GetConfigurationOptions()
 			if xerr != nil {
 				return NullService(), xerr
 			}
 
-			// VPL: disable region validation, may need to update allRegions for objectstorage/metadata)
-			// xerr = validateRegionName(objectStorageConfig.Region, allRegions)
-			// if xerr != nil {
-			// 	return nil, fail.Wrap(xerr, "invalid region in section 'objectstorage")
-			// }
-
-			objectStorageLocation, xerr = objectstorage.NewLocation(objectStorageConfig)
-			if xerr != nil {
-				return NullService(), fail.Wrap(xerr, "error connecting to Object Storage location")
+			anon, found := serviceCfg.Get("MetadataBucketName")
+			if !found {
+				return NullService(), fail.SyntaxError("missing configuration option 'MetadataBucketName'")
+			}
+			var ok bool
+			metadataLocationConfig.BucketName, ok = anon.(string)
+			if !ok {
+				return NullService(), fail.InvalidRequestError("invalid bucket name, it's not a string")
 			}
-		} else {
-			logrus.Warnf("missing section 'objectstorage' in configuration file for tenant '%s'", tenantName)
+		}
+		found, err = metadataLocation.FindBucket(metadataLocationConfig.BucketName)
+		if err != nil {
+			return NullService(), fail.Wrap(err, "error accessing metadata location: %s")
 		}
 
-		// Initializes Metadata Object Storage (may be different than the Object Storage)
-		var (
-			metadataBucket   abstract.ObjectStorageBucket
-			metadataCryptKey *crypt.Key
-		)
-		if tenantMetadataFound || tenantObjectStorageFound {
-			// FIXME: This requires tuning too
-			metadataLocationConfig, err := initMetadataLocationConfig(authOpts, tenant)
+		if found {
+			metadataBucket, err = metadataLocation.InspectBucket(metadataLocationConfig.BucketName)
 			if err != nil {
 				return NullService(), err
 			}
-
-			// VPL: disable region validation, may need to update allRegions for objectstorage/metadata)
-			// xerr = validateRegionName(metadataLocationConfig.Region, allRegions)
-			// if xerr != nil {
-			// 	return nil, fail.Wrap(xerr, "invalid region in section 'metadata'")
-			// }
-
-			metadataLocation, err := objectstorage.NewLocation(metadataLocationConfig)
+		} else {
+			// create bucket
+			metadataBucket, err = metadataLocation.CreateBucket(metadataLocationConfig.BucketName)
 			if err != nil {
-				return NullService(), fail.Wrap(err, "error connecting to Object Storage location to store metadata")
+				return NullService(), err
 			}
 
-			if metadataLocationConfig.BucketName == "" {
-				serviceCfg, xerr := providerInstance.GetConfigurationOptions()
+			// Creates metadata version file
+			if metadataVersion != "" {
+				content := bytes.NewBuffer([]byte(metadataVersion))
+				_, xerr := metadataLocation.WriteObject(metadataLocationConfig.BucketName, "version", content, int64(content.Len()), nil)
 				if xerr != nil {
-					return NullService(), xerr
-				}
-
-				anon, found := serviceCfg.Get("MetadataBucketName")
-				if !found {
-					return NullService(), fail.SyntaxError("missing configuration option 'MetadataBucketName'")
+					return NullService(), fail.Wrap(xerr, "failed to create version object in metadata Bucket")
 				}
-				var ok bool
-				metadataLocationConfig.BucketName, ok = anon.(string)
-				if !ok {
-					return NullService(), fail.InvalidRequestError("invalid bucket name, it's not a string")
-				}
-			}
-			found, err = metadataLocation.FindBucket(metadataLocationConfig.BucketName)
-			if err != nil {
-				return NullService(), fail.Wrap(err, "error accessing metadata location: %s")
 			}
-
-			if found {
-				metadataBucket, err = metadataLocation.InspectBucket(metadataLocationConfig.BucketName)
-				if err != nil {
-					return NullService(), err
-				}
-			} else {
-				// create bucket
-				metadataBucket, err = metadataLocation.CreateBucket(metadataLocationConfig.BucketName)
+		}
+		if metadataConfig, ok := tenant["metadata"].(map[string]interface{}); ok {
+			if key, ok := metadataConfig["CryptKey"].(string); ok {
+				ek, err := crypt.NewEncryptionKey([]byte(key))
 				if err != nil {
-					return NullService(), err
-				}
-
-				// Creates metadata version file
-				if metadataVersion != "" {
-					content := bytes.NewBuffer([]byte(metadataVersion))
-					_, xerr := metadataLocation.WriteObject(metadataLocationConfig.BucketName, "version", content, int64(content.Len()), nil)
-					if xerr != nil {
-						return NullService(), fail.Wrap(xerr, "failed to create version object in metadata Bucket")
-					}
+					return NullService(), fail.ConvertError(err)
 				}
+				metadataCryptKey = ek
 			}
-			if metadataConfig, ok := tenant["metadata"].(map[string]interface{}); ok {
-				if key, ok := metadataConfig["CryptKey"].(string); ok {
-					ek, err := crypt.NewEncryptionKey([]byte(key))
-					if err != nil {
-						return NullService(), fail.ConvertError(err)
-					}
-					metadataCryptKey = ek
-				}
-			}
-			logrus.Infof("Setting default Tenant to '%s'; storing metadata in bucket '%s'", tenantName, metadataBucket.GetName())
-		} else {
-			return NullService(), fail.SyntaxError("failed to build service: 'metadata' section (and 'objectstorage' as fallback) is missing in configuration file for tenant '%s'", tenantName)
 		}
-
-		// service is ready
-		newS.Location = objectStorageLocation
-		newS.metadataBucket = metadataBucket
-		newS.metadataKey = metadataCryptKey
-
-		return newS, validateRegexps(newS, tenant)
+		logrus.Infof("Setting default Tenant to '%s'; storing metadata in bucket '%s'", tenantName, metadataBucket.GetName())
+	} else {
+		return NullService(), fail.SyntaxError("failed to build service: 'metadata' section (and 'objectstorage' as fallback) is missing in configuration file for tenant '%s'", tenantName)
 	}
 
-	if !tenantInCfg {
-		return NullService(), fail.NotFoundError("tenant '%s' not found in configuration", tenantName)
-	}
-	return NullService(), fail.NotFoundError("provider builder for '%s'", svcProvider)
+	// service is ready
+	newS.Location = objectStorageLocation
+	newS.metadataBucket = metadataBucket
+	newS.metadataKey = metadataCryptKey
+
+	return newS, validateRegexps(newS, tenant)
 }
 
 // validateRegionName validates the availability of the region passed as parameter
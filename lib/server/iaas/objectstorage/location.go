@@ -120,8 +120,13 @@ type location struct {
 }
 
 // NewLocation creates an Object Storage location based on config
-func NewLocation(conf Config) (_ *location, xerr fail.Error) { // nolint
+// If conf.Type is "memory", the location is kept in memory (intended for tests)
+func NewLocation(conf Config) (_ Location, xerr fail.Error) {
 	defer fail.OnPanic(&xerr)
+	if conf.Type == MemoryType {
+		return newMemoryLocation(conf), nil
+	}
+
 	l := &location{
 		config: conf,
 	}
@@ -0,0 +1,402 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectstorage
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// MemoryType is the value of Config.Type selecting the in-memory Object Storage
+const MemoryType = "memory"
+
+// memoryItem is an object stored in memory
+type memoryItem struct {
+	data     []byte
+	metadata abstract.ObjectStorageItemMetadata
+}
+
+// memoryStorage contains the buckets of an in-memory Object Storage
+type memoryStorage struct {
+	lock    sync.RWMutex
+	buckets map[string]map[string]*memoryItem
+}
+
+var (
+	memoryStorages     = map[string]*memoryStorage{}
+	memoryStoragesLock sync.Mutex
+)
+
+// memoryLocation is an implementation of Location keeping everything in memory
+// All the locations created with the same Config.Endpoint share the same content for the life of the process
+type memoryLocation struct {
+	config  Config
+	storage *memoryStorage
+}
+
+// newMemoryLocation creates an in-memory Object Storage location
+func newMemoryLocation(conf Config) *memoryLocation {
+	memoryStoragesLock.Lock()
+	defer memoryStoragesLock.Unlock()
+
+	storage, ok := memoryStorages[conf.Endpoint]
+	if !ok {
+		storage = &memoryStorage{buckets: map[string]map[string]*memoryItem{}}
+		memoryStorages[conf.Endpoint] = storage
+	}
+	return &memoryLocation{config: conf, storage: storage}
+}
+
+// IsNull tells if the instance should be considered as a null value
+func (l *memoryLocation) IsNull() bool {
+	return l == nil || l.storage == nil
+}
+
+// ObjectStorageProtocol returns the type of ObjectStorage
+func (l memoryLocation) ObjectStorageProtocol() string {
+	if l.IsNull() {
+		return ""
+	}
+	return l.config.Type
+}
+
+// ListBuckets lists the buckets whose name starts with prefix
+func (l memoryLocation) ListBuckets(prefix string) ([]string, fail.Error) {
+	if l.IsNull() {
+		return []string{}, fail.InvalidInstanceError()
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("objectstorage.memoryLocation"), "('%s')", prefix).Entering().Exiting()
+
+	l.storage.lock.RLock()
+	defer l.storage.lock.RUnlock()
+
+	var list []string
+	for k := range l.storage.buckets {
+		if strings.HasPrefix(k, prefix) {
+			list = append(list, k)
+		}
+	}
+	sort.Strings(list)
+	return list, nil
+}
+
+// FindBucket returns true if a bucket with the name exists
+func (l memoryLocation) FindBucket(bucketName string) (bool, fail.Error) {
+	if l.IsNull() {
+		return false, fail.InvalidInstanceError()
+	}
+	if bucketName == "" {
+		return false, fail.InvalidParameterCannotBeEmptyStringError("bucketName")
+	}
+
+	l.storage.lock.RLock()
+	defer l.storage.lock.RUnlock()
+
+	_, ok := l.storage.buckets[bucketName]
+	return ok, nil
+}
+
+// InspectBucket ...
+func (l memoryLocation) InspectBucket(bucketName string) (abstract.ObjectStorageBucket, fail.Error) {
+	if l.IsNull() {
+		return abstract.ObjectStorageBucket{}, fail.InvalidInstanceError()
+	}
+	if bucketName == "" {
+		return abstract.ObjectStorageBucket{}, fail.InvalidParameterCannotBeEmptyStringError("bucketName")
+	}
+
+	l.storage.lock.RLock()
+	defer l.storage.lock.RUnlock()
+
+	if _, ok := l.storage.buckets[bucketName]; !ok {
+		return abstract.ObjectStorageBucket{}, fail.NotFoundError("failed to find bucket '%s'", bucketName)
+	}
+	return abstract.ObjectStorageBucket{ID: bucketName, Name: bucketName}, nil
+}
+
+// CreateBucket ...
+func (l memoryLocation) CreateBucket(bucketName string) (abstract.ObjectStorageBucket, fail.Error) {
+	if l.IsNull() {
+		return abstract.ObjectStorageBucket{}, fail.InvalidInstanceError()
+	}
+	if bucketName == "" {
+		return abstract.ObjectStorageBucket{}, fail.InvalidParameterCannotBeEmptyStringError("bucketName")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("objectstorage.memoryLocation"), "('%s')", bucketName).Entering().Exiting()
+
+	l.storage.lock.Lock()
+	defer l.storage.lock.Unlock()
+
+	if _, ok := l.storage.buckets[bucketName]; ok {
+		return abstract.ObjectStorageBucket{}, fail.DuplicateError("bucket '%s' already exists", bucketName)
+	}
+	l.storage.buckets[bucketName] = map[string]*memoryItem{}
+	return abstract.ObjectStorageBucket{ID: bucketName, Name: bucketName}, nil
+}
+
+// DeleteBucket removes a bucket; the bucket has to be empty
+func (l memoryLocation) DeleteBucket(bucketName string) fail.Error {
+	if l.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if bucketName == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("bucketName")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("objectstorage.memoryLocation"), "('%s')", bucketName).Entering().Exiting()
+
+	l.storage.lock.Lock()
+	defer l.storage.lock.Unlock()
+
+	b, ok := l.storage.buckets[bucketName]
+	if !ok {
+		return fail.NotFoundError("failed to find bucket '%s'", bucketName)
+	}
+	if len(b) > 0 {
+		return fail.NotAvailableError("bucket '%s' is not empty", bucketName)
+	}
+	delete(l.storage.buckets, bucketName)
+	return nil
+}
+
+// ClearBucket removes the objects of the bucket matching path and prefix
+func (l memoryLocation) ClearBucket(bucketName string, path, prefix string) fail.Error {
+	if l.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if bucketName == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("bucketName")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("objectstorage.memoryLocation"), "('%s', '%s', '%s')", bucketName, path, prefix).Entering().Exiting()
+
+	l.storage.lock.Lock()
+	defer l.storage.lock.Unlock()
+
+	b, ok := l.storage.buckets[bucketName]
+	if !ok {
+		return fail.NotFoundError("failed to find bucket '%s'", bucketName)
+	}
+	for _, v := range matchingMemoryItems(b, path, prefix) {
+		delete(b, v)
+	}
+	return nil
+}
+
+// ListObjects lists the objects of the bucket matching path and prefix
+func (l memoryLocation) ListObjects(bucketName string, path, prefix string) ([]string, fail.Error) {
+	if l.IsNull() {
+		return []string{}, fail.InvalidInstanceError()
+	}
+	if bucketName == "" {
+		return []string{}, fail.InvalidParameterCannotBeEmptyStringError("bucketName")
+	}
+
+	l.storage.lock.RLock()
+	defer l.storage.lock.RUnlock()
+
+	b, ok := l.storage.buckets[bucketName]
+	if !ok {
+		return nil, fail.NotFoundError("failed to find bucket '%s'", bucketName)
+	}
+	return matchingMemoryItems(b, path, prefix), nil
+}
+
+// InspectObject ...
+func (l memoryLocation) InspectObject(bucketName string, objectName string) (abstract.ObjectStorageItem, fail.Error) {
+	if l.IsNull() {
+		return abstract.ObjectStorageItem{}, fail.InvalidInstanceError()
+	}
+	if bucketName == "" {
+		return abstract.ObjectStorageItem{}, fail.InvalidParameterCannotBeEmptyStringError("bucketName")
+	}
+	if objectName == "" {
+		return abstract.ObjectStorageItem{}, fail.InvalidParameterCannotBeEmptyStringError("objectName")
+	}
+
+	l.storage.lock.RLock()
+	defer l.storage.lock.RUnlock()
+
+	item, xerr := l.storage.find(bucketName, objectName)
+	if xerr != nil {
+		return abstract.ObjectStorageItem{}, xerr
+	}
+	return abstract.ObjectStorageItem{
+		BucketName: bucketName,
+		ItemID:     objectName,
+		ItemName:   objectName,
+		Metadata:   item.metadata.Clone(),
+	}, nil
+}
+
+// ReadObject writes the content of the object, from byte 'from' to byte 'to' (0 meaning end of object), to writer
+func (l memoryLocation) ReadObject(bucketName, objectName string, writer io.Writer, from, to int64) fail.Error {
+	if l.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if bucketName == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("bucketName")
+	}
+	if objectName == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("objectName")
+	}
+	if writer == nil {
+		return fail.InvalidParameterCannotBeNilError("writer")
+	}
+	if from > to {
+		return fail.InvalidParameterError("from", "cannot be greater than 'to'")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("objectstorage.memoryLocation"), "('%s', '%s')", bucketName, objectName).Entering().Exiting()
+
+	l.storage.lock.RLock()
+	defer l.storage.lock.RUnlock()
+
+	item, xerr := l.storage.find(bucketName, strings.Trim(objectName, "/"))
+	if xerr != nil {
+		return xerr
+	}
+
+	size := int64(len(item.data))
+	end := size
+	if to > 0 && to > from && to < size {
+		end = to
+	}
+	if from > size {
+		from = size
+	}
+	if _, err := writer.Write(item.data[from:end]); err != nil {
+		return fail.ConvertError(err)
+	}
+	return nil
+}
+
+// WriteObject writes the content of source in the object
+func (l memoryLocation) WriteObject(bucketName string, objectName string, source io.Reader, size int64, metadata abstract.ObjectStorageItemMetadata) (abstract.ObjectStorageItem, fail.Error) {
+	if l.IsNull() {
+		return abstract.ObjectStorageItem{}, fail.InvalidInstanceError()
+	}
+	if bucketName == "" {
+		return abstract.ObjectStorageItem{}, fail.InvalidParameterCannotBeEmptyStringError("bucketName")
+	}
+	if objectName == "" {
+		return abstract.ObjectStorageItem{}, fail.InvalidParameterCannotBeEmptyStringError("objectName")
+	}
+	if source == nil {
+		return abstract.ObjectStorageItem{}, fail.InvalidParameterCannotBeNilError("source")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("objectstorage.memoryLocation"), "('%s', '%s', %d)", bucketName, objectName, size).Entering().Exiting()
+
+	var buffer bytes.Buffer
+	var err error
+	if size >= 0 {
+		_, err = io.CopyN(&buffer, source, size)
+	} else {
+		_, err = io.Copy(&buffer, source)
+	}
+	if err != nil && err != io.EOF {
+		return abstract.ObjectStorageItem{}, fail.ConvertError(err)
+	}
+
+	l.storage.lock.Lock()
+	defer l.storage.lock.Unlock()
+
+	b, ok := l.storage.buckets[bucketName]
+	if !ok {
+		return abstract.ObjectStorageItem{}, fail.NotFoundError("failed to find bucket '%s'", bucketName)
+	}
+	item := &memoryItem{data: buffer.Bytes(), metadata: abstract.ObjectStorageItemMetadata{}}
+	if metadata != nil {
+		item.metadata = metadata.Clone()
+	}
+	b[objectName] = item
+
+	return abstract.ObjectStorageItem{
+		BucketName: bucketName,
+		ItemID:     objectName,
+		ItemName:   objectName,
+		Metadata:   item.metadata.Clone(),
+	}, nil
+}
+
+// WriteMultiPartObject writes the content of source in the object
+// There is no need to split data in memory, so chunkSize is ignored
+func (l memoryLocation) WriteMultiPartObject(bucketName string, objectName string, source io.Reader, sourceSize int64, chunkSize int, metadata abstract.ObjectStorageItemMetadata) (abstract.ObjectStorageItem, fail.Error) {
+	return l.WriteObject(bucketName, objectName, source, sourceSize, metadata)
+}
+
+// DeleteObject deletes an object from a bucket
+func (l memoryLocation) DeleteObject(bucketName, objectName string) fail.Error {
+	if l.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if bucketName == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("bucketName")
+	}
+	if objectName == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("objectName")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("objectstorage.memoryLocation"), "('%s', '%s')", bucketName, objectName).Entering().Exiting()
+
+	l.storage.lock.Lock()
+	defer l.storage.lock.Unlock()
+
+	if _, xerr := l.storage.find(bucketName, objectName); xerr != nil {
+		return xerr
+	}
+	delete(l.storage.buckets[bucketName], objectName)
+	return nil
+}
+
+// find returns the item named objectName in bucket bucketName
+// Must be called with storage lock held
+func (s *memoryStorage) find(bucketName, objectName string) (*memoryItem, fail.Error) {
+	b, ok := s.buckets[bucketName]
+	if !ok {
+		return nil, fail.NotFoundError("failed to find bucket '%s'", bucketName)
+	}
+	item, ok := b[objectName]
+	if !ok {
+		return nil, fail.NotFoundError("failed to find object '%s' in bucket '%s'", objectName, bucketName)
+	}
+	return item, nil
+}
+
+// matchingMemoryItems returns the sorted names of items starting with path and with the full path built from path and prefix
+func matchingMemoryItems(b map[string]*memoryItem, path, prefix string) []string {
+	fullPath := buildFullPath(path, prefix)
+	var list []string
+	for k := range b {
+		if strings.HasPrefix(k, path) && strings.HasPrefix(k, fullPath) {
+			list = append(list, k)
+		}
+	}
+	sort.Strings(list)
+	return list
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package objectstorage
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+func TestMemoryLocation(t *testing.T) {
+	l, xerr := NewLocation(Config{Type: MemoryType, Endpoint: "TestMemoryLocation"})
+	require.Nil(t, xerr)
+	assert.Equal(t, MemoryType, l.ObjectStorageProtocol())
+
+	_, xerr = l.CreateBucket("bucket")
+	require.Nil(t, xerr)
+	_, xerr = l.CreateBucket("bucket")
+	assert.IsType(t, &fail.ErrDuplicate{}, xerr)
+
+	// A second location with the same endpoint shares the content
+	other, xerr := NewLocation(Config{Type: MemoryType, Endpoint: "TestMemoryLocation"})
+	require.Nil(t, xerr)
+	found, xerr := other.FindBucket("bucket")
+	require.Nil(t, xerr)
+	assert.True(t, found)
+
+	content := []byte("0123456789")
+	for _, v := range []string{"hosts/byID/1", "hosts/byName/one", "networks/byID/2"} {
+		_, xerr = l.WriteObject("bucket", v, bytes.NewReader(content), int64(len(content)), abstract.ObjectStorageItemMetadata{"k": "v"})
+		require.Nil(t, xerr)
+	}
+
+	list, xerr := l.ListObjects("bucket", "hosts", "")
+	require.Nil(t, xerr)
+	assert.Equal(t, []string{"hosts/byID/1", "hosts/byName/one"}, list)
+
+	var buffer bytes.Buffer
+	xerr = l.ReadObject("bucket", "hosts/byID/1", &buffer, 0, 0)
+	require.Nil(t, xerr)
+	assert.Equal(t, content, buffer.Bytes())
+
+	buffer.Reset()
+	xerr = l.ReadObject("bucket", "hosts/byID/1", &buffer, 2, 5)
+	require.Nil(t, xerr)
+	assert.Equal(t, "234", buffer.String())
+
+	item, xerr := l.InspectObject("bucket", "hosts/byID/1")
+	require.Nil(t, xerr)
+	assert.Equal(t, "v", item.Metadata["k"])
+
+	xerr = l.ReadObject("bucket", "missing", &buffer, 0, 0)
+	assert.IsType(t, &fail.ErrNotFound{}, xerr)
+
+	assert.NotNil(t, l.DeleteBucket("bucket"), "non-empty bucket must not be deleted")
+	require.Nil(t, l.ClearBucket("bucket", "hosts", ""))
+	list, xerr = l.ListObjects("bucket", "", "")
+	require.Nil(t, xerr)
+	assert.Equal(t, []string{"networks/byID/2"}, list)
+
+	require.Nil(t, l.DeleteObject("bucket", "networks/byID/2"))
+	require.Nil(t, l.DeleteBucket("bucket"))
+	found, xerr = l.FindBucket("bucket")
+	require.Nil(t, xerr)
+	assert.False(t, found)
+}
@@ -88,6 +88,12 @@ func (provider *provider) ListImages(all bool) ([]abstract.Image, fail.Error) {
 func (provider *provider) InspectImage(id string) (abstract.Image, fail.Error) {
 	return abstract.Image{}, gReport
 }
+func (provider *provider) CreateImage(request abstract.ImageRequest) (*abstract.Image, fail.Error) {
+	return nil, gReport
+}
+func (provider *provider) DeleteImage(id string) fail.Error {
+	return gReport
+}
 
 func (provider *provider) InspectTemplate(id string) (abstract.HostTemplate, fail.Error) {
 	return abstract.HostTemplate{}, gReport
@@ -167,6 +173,25 @@ func (provider *provider) DeleteVIP(vip *abstract.VirtualIP) fail.Error {
 	return gReport
 }
 
+func (provider *provider) CreatePublicIP(request abstract.PublicIPRequest) (*abstract.PublicIP, fail.Error) {
+	return nil, gReport
+}
+func (provider *provider) InspectPublicIP(id string) (*abstract.PublicIP, fail.Error) {
+	return nil, gReport
+}
+func (provider *provider) ListPublicIPs() ([]*abstract.PublicIP, fail.Error) {
+	return nil, gReport
+}
+func (provider *provider) DeletePublicIP(id string) fail.Error {
+	return gReport
+}
+func (provider *provider) BindPublicIPToHost(pip *abstract.PublicIP, hostParam stacks.HostParameter) fail.Error {
+	return gReport
+}
+func (provider *provider) UnbindPublicIPFromHost(pip *abstract.PublicIP, hostParam stacks.HostParameter) fail.Error {
+	return gReport
+}
+
 func (provider *provider) CreateHost(request abstract.HostRequest) (*abstract.HostFull, *userdata.Content, fail.Error) {
 	return nil, nil, gReport
 }
@@ -214,6 +239,25 @@ func (provider *provider) DeleteVolume(id string) fail.Error {
 	return gReport
 }
 
+func (provider *provider) CreateVolumeSnapshot(request abstract.SnapshotRequest) (*abstract.Snapshot, fail.Error) {
+	return nil, gReport
+}
+func (provider *provider) InspectVolumeSnapshot(id string) (*abstract.Snapshot, fail.Error) {
+	return nil, gReport
+}
+func (provider *provider) ListVolumeSnapshots(volumeID string) ([]*abstract.Snapshot, fail.Error) {
+	return nil, gReport
+}
+func (provider *provider) DeleteVolumeSnapshot(id string) fail.Error {
+	return gReport
+}
+func (provider *provider) CreateVolumeFromSnapshot(snapshotID string, request abstract.VolumeRequest) (*abstract.Volume, fail.Error) {
+	return nil, gReport
+}
+func (provider *provider) ResizeVolume(id string, newSize int) (*abstract.Volume, fail.Error) {
+	return nil, gReport
+}
+
 func (provider *provider) CreateVolumeAttachment(request abstract.VolumeAttachmentRequest) (string, fail.Error) {
 	return "", gReport
 }
@@ -0,0 +1,198 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"regexp"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/objectstorage"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/providers"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks/api"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks/memory"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumespeed"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+const (
+	memoryDefaultImage  = "Ubuntu 20.04"
+	memoryDefaultRegion = "local"
+)
+
+// provider is the provider implementation of the memory provider, intended to run SafeScale without cloud account
+// (hermetic tests, demonstrations, ...)
+type provider struct {
+	api.Stack
+
+	tenantParameters map[string]interface{}
+}
+
+// New creates a new instance of memory provider
+func New() providers.Provider {
+	return &provider{}
+}
+
+// IsNull returns true if the instance is considered as a null value
+func (p *provider) IsNull() bool {
+	return p == nil || p.Stack == nil
+}
+
+// Build builds a new provider from configuration parameters
+// Can be called from nil
+// All the providers built for the same tenant name share the same in-memory content
+func (p *provider) Build(params map[string]interface{}) (providers.Provider, fail.Error) {
+	tenantName, _ := params["name"].(string)
+	if tenantName == "" {
+		return &provider{}, fail.SyntaxError("field 'name' not found in tenant definition")
+	}
+
+	computeCfg, ok := params["compute"].(map[string]interface{})
+	if !ok {
+		return &provider{}, fail.SyntaxError("section 'compute' not found in tenants.toml")
+	}
+
+	region, _ := computeCfg["Region"].(string)
+	if region == "" {
+		region = memoryDefaultRegion
+	}
+	zone, _ := computeCfg["AvailabilityZone"].(string)
+	if zone == "" {
+		zone = region + "-a"
+	}
+	defaultImage, _ := computeCfg["DefaultImage"].(string)
+	if defaultImage == "" {
+		defaultImage = memoryDefaultImage
+	}
+
+	operatorUsername := abstract.DefaultUser
+	if operatorUsernameIf, ok := computeCfg["OperatorUsername"]; ok {
+		operatorUsername = operatorUsernameIf.(string)
+	}
+
+	authOptions := stacks.AuthenticationOptions{
+		TenantName:       tenantName,
+		Region:           region,
+		AvailabilityZone: zone,
+	}
+
+	providerName := "memory"
+	metadataBucketName, xerr := objectstorage.BuildMetadataBucketName(providerName, region, "", tenantName)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	cfgOptions := stacks.ConfigurationOptions{
+		DNSList:                   []string{"1.1.1.1"},
+		UseFloatingIP:             true,
+		AutoHostNetworkInterfaces: false,
+		VolumeSpeeds: map[string]volumespeed.Enum{
+			"standard":   volumespeed.Cold,
+			"performant": volumespeed.Ssd,
+		},
+		MetadataBucket:   metadataBucketName,
+		DefaultImage:     defaultImage,
+		OperatorUsername: operatorUsername,
+		ProviderName:     providerName,
+	}
+
+	memoryStack, xerr := memory.New(authOptions, cfgOptions)
+	if xerr != nil {
+		return nil, xerr
+	}
+	newP := &provider{
+		Stack:            memoryStack,
+		tenantParameters: params,
+	}
+	return newP, nil
+}
+
+// GetAuthenticationOptions returns the auth options
+func (p provider) GetAuthenticationOptions() (providers.Config, fail.Error) {
+	cfg := providers.ConfigMap{}
+
+	opts := p.Stack.(api.ReservedForProviderUse).GetAuthenticationOptions()
+	cfg.Set("TenantName", opts.TenantName)
+	cfg.Set("Region", opts.Region)
+	cfg.Set("AvailabilityZone", opts.AvailabilityZone)
+	return cfg, nil
+}
+
+// GetConfigurationOptions return configuration parameters
+func (p provider) GetConfigurationOptions() (providers.Config, fail.Error) {
+	cfg := providers.ConfigMap{}
+
+	opts := p.Stack.(api.ReservedForProviderUse).GetConfigurationOptions()
+	cfg.Set("DNSList", opts.DNSList)
+	cfg.Set("AutoHostNetworkInterfaces", opts.AutoHostNetworkInterfaces)
+	cfg.Set("UseLayer3Networking", opts.UseLayer3Networking)
+	cfg.Set("DefaultImage", opts.DefaultImage)
+	cfg.Set("MetadataBucketName", opts.MetadataBucket)
+	cfg.Set("OperatorUsername", opts.OperatorUsername)
+	cfg.Set("UseNATService", opts.UseNATService)
+	cfg.Set("ProviderName", p.GetName())
+	return cfg, nil
+}
+
+// GetName returns the providerName
+func (p provider) GetName() string {
+	return "memory"
+}
+
+// ListImages ...
+func (p provider) ListImages(all bool) ([]abstract.Image, fail.Error) {
+	if p.IsNull() {
+		return []abstract.Image{}, fail.InvalidInstanceError()
+	}
+	return p.Stack.(api.ReservedForProviderUse).ListImages()
+}
+
+// ListTemplates ...
+func (p provider) ListTemplates(all bool) ([]abstract.HostTemplate, fail.Error) {
+	if p.IsNull() {
+		return []abstract.HostTemplate{}, fail.InvalidInstanceError()
+	}
+	return p.Stack.(api.ReservedForProviderUse).ListTemplates()
+}
+
+// GetTenantParameters returns the tenant parameters as-is
+func (p *provider) GetTenantParameters() map[string]interface{} {
+	return p.tenantParameters
+}
+
+// GetCapabilities returns the capabilities of the provider
+func (p *provider) GetCapabilities() providers.Capabilities {
+	return providers.Capabilities{
+		PublicVirtualIP:         true,
+		PrivateVirtualIP:        true,
+		CanDisableSecurityGroup: true,
+	}
+}
+
+// GetRegexpsOfTemplatesWithGPU returns a slice of regexps corresponding to templates with GPU
+func (p provider) GetRegexpsOfTemplatesWithGPU() []*regexp.Regexp {
+	var emptySlice []*regexp.Regexp
+	if p.IsNull() {
+		return emptySlice
+	}
+	return []*regexp.Regexp{regexp.MustCompile("^gpu")}
+}
+
+func init() {
+	iaas.Register("memory", &provider{})
+}
@@ -0,0 +1,361 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory_test
+
+import (
+	"context"
+	"crypto/md5"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	_ "github.com/CS-SI/SafeScale/lib/server/iaas/providers/memory"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
+	memorystack "github.com/CS-SI/SafeScale/lib/server/iaas/stacks/memory"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/hoststate"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/ipversion"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/securitygroupruledirection"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumestate"
+	"github.com/CS-SI/SafeScale/lib/system"
+	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/outputs"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// getService builds the service of the tenant 'TestMemory'
+func getService(t *testing.T) iaas.Service {
+	tenant := map[string]interface{}{
+		"name":   "TestMemory",
+		"client": "memory",
+		"compute": map[string]interface{}{
+			"Region": "test",
+		},
+		"objectstorage": map[string]interface{}{
+			"Type":     "memory",
+			"Endpoint": "TestMemory",
+		},
+	}
+	svc, xerr := iaas.BuildService(tenant, "v21.05.0")
+	require.Nil(t, xerr)
+	require.NotNil(t, svc)
+	return svc
+}
+
+func resetService(t *testing.T) iaas.Service {
+	memorystack.Forget("TestMemory")
+	return getService(t)
+}
+
+func Test_Service(t *testing.T) {
+	svc := resetService(t)
+
+	assert.Equal(t, "memory", svc.GetProviderName())
+	assert.NotEmpty(t, svc.GetMetadataBucket().Name)
+
+	found, xerr := svc.FindBucket(svc.GetMetadataBucket().Name)
+	require.Nil(t, xerr)
+	assert.True(t, found)
+
+	images, xerr := svc.ListImages(false)
+	require.Nil(t, xerr)
+	assert.NotEmpty(t, images)
+
+	image, xerr := svc.SearchImage("Ubuntu 20.04")
+	require.Nil(t, xerr)
+	assert.Equal(t, "ubuntu-2004", image.ID)
+
+	template, xerr := svc.FindTemplateByName("medium")
+	require.Nil(t, xerr)
+	assert.Equal(t, 4, template.Cores)
+}
+
+func Test_NetworksAndSubnets(t *testing.T) {
+	svc := resetService(t)
+
+	an, xerr := svc.CreateNetwork(abstract.NetworkRequest{Name: "net", CIDR: "192.168.0.0/16"})
+	require.Nil(t, xerr)
+	assert.NotEmpty(t, an.ID)
+
+	_, xerr = svc.CreateNetwork(abstract.NetworkRequest{Name: "net", CIDR: "192.168.0.0/16"})
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrDuplicate{}, xerr)
+
+	as, xerr := svc.CreateSubnet(abstract.SubnetRequest{NetworkID: an.ID, Name: "subnet", CIDR: "192.168.1.0/24", IPVersion: ipversion.IPv4})
+	require.Nil(t, xerr)
+
+	_, xerr = svc.CreateSubnet(abstract.SubnetRequest{NetworkID: an.ID, Name: "other", CIDR: "192.168.1.128/25"})
+	assert.NotNil(t, xerr, "overlapping CIDR must be refused")
+	_, xerr = svc.CreateSubnet(abstract.SubnetRequest{NetworkID: an.ID, Name: "outside", CIDR: "10.0.0.0/24"})
+	assert.NotNil(t, xerr, "CIDR outside Network must be refused")
+
+	found, xerr := svc.InspectSubnetByName("net", "subnet")
+	require.Nil(t, xerr)
+	assert.Equal(t, as.ID, found.ID)
+
+	list, xerr := svc.ListSubnets(an.ID)
+	require.Nil(t, xerr)
+	assert.Len(t, list, 1)
+
+	xerr = svc.DeleteNetwork(an.ID)
+	assert.NotNil(t, xerr, "Network containing Subnet must not be deleted")
+
+	require.Nil(t, svc.DeleteSubnet(as.ID))
+	require.Nil(t, svc.DeleteNetwork(an.ID))
+
+	_, xerr = svc.InspectNetwork(an.ID)
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrNotFound{}, xerr)
+}
+
+func Test_HostsAndVolumes(t *testing.T) {
+	svc := resetService(t)
+
+	an, xerr := svc.CreateNetwork(abstract.NetworkRequest{Name: "net", CIDR: "10.0.0.0/16"})
+	require.Nil(t, xerr)
+	as, xerr := svc.CreateSubnet(abstract.SubnetRequest{NetworkID: an.ID, Name: "subnet", CIDR: "10.0.1.0/24"})
+	require.Nil(t, xerr)
+	asg, xerr := svc.CreateSecurityGroup(an.ID, "sg", "test", nil)
+	require.Nil(t, xerr)
+
+	ahf, udc, xerr := svc.CreateHost(abstract.HostRequest{
+		ResourceName:     "host",
+		Subnets:          []*abstract.Subnet{as},
+		TemplateID:       "small",
+		ImageID:          "ubuntu-2004",
+		PublicIP:         true,
+		SecurityGroupIDs: map[string]struct{}{asg.ID: {}},
+	})
+	require.Nil(t, xerr)
+	require.NotNil(t, udc)
+	assert.Equal(t, hoststate.Started, ahf.CurrentState)
+	assert.Equal(t, "10.0.1.2", ahf.Networking.IPv4Addresses[as.ID])
+	assert.NotEmpty(t, ahf.Networking.PublicIPv4)
+	assert.NotEmpty(t, ahf.Core.PrivateKey)
+	assert.Equal(t, 2, ahf.Sizing.Cores)
+
+	_, _, xerr = svc.CreateHost(abstract.HostRequest{ResourceName: "host", Subnets: []*abstract.Subnet{as}, TemplateID: "small", ImageID: "ubuntu-2004"})
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrDuplicate{}, xerr)
+
+	require.Nil(t, svc.StopHost(ahf.Core.ID, true))
+	state, xerr := svc.GetHostState("host")
+	require.Nil(t, xerr)
+	assert.Equal(t, hoststate.Stopped, state)
+	require.Nil(t, svc.StartHost(ahf.Core.ID))
+	_, xerr = svc.WaitHostReady(ahf.Core.ID, 0)
+	require.Nil(t, xerr)
+
+	av, xerr := svc.CreateVolume(abstract.VolumeRequest{Name: "vol", Size: 10})
+	require.Nil(t, xerr)
+	vaID, xerr := svc.CreateVolumeAttachment(abstract.VolumeAttachmentRequest{Name: "vol-host", VolumeID: av.ID, HostID: ahf.Core.ID})
+	require.Nil(t, xerr)
+	ava, xerr := svc.InspectVolumeAttachment(ahf.Core.ID, vaID)
+	require.Nil(t, xerr)
+	assert.Equal(t, "/dev/vdb", ava.Device)
+	av, xerr = svc.InspectVolume(av.ID)
+	require.Nil(t, xerr)
+	assert.Equal(t, volumestate.Used, av.State)
+	assert.NotNil(t, svc.DeleteVolume(av.ID), "attached volume must not be deleted")
+
+	assert.NotNil(t, svc.DeleteSecurityGroup(asg), "Security Group bound to host must not be deleted")
+
+	require.Nil(t, svc.DeleteHost(ahf.Core.ID))
+	av, xerr = svc.InspectVolume(av.ID)
+	require.Nil(t, xerr)
+	assert.Equal(t, volumestate.Available, av.State)
+	require.Nil(t, svc.DeleteVolume(av.ID))
+	require.Nil(t, svc.DeleteSecurityGroup(asg))
+
+	hosts, xerr := svc.ListHosts(true)
+	require.Nil(t, xerr)
+	assert.Empty(t, hosts)
+}
+
+func Test_SecurityGroupRules(t *testing.T) {
+	svc := resetService(t)
+
+	an, xerr := svc.CreateNetwork(abstract.NetworkRequest{Name: "net", CIDR: "10.0.0.0/16"})
+	require.Nil(t, xerr)
+	asg, xerr := svc.CreateSecurityGroup(an.ID, "sg", "test", abstract.SecurityGroupRules{})
+	require.Nil(t, xerr)
+
+	rule := &abstract.SecurityGroupRule{
+		EtherType: ipversion.IPv4,
+		Direction: securitygroupruledirection.Ingress,
+		Protocol:  "tcp",
+		PortFrom:  22,
+		PortTo:    22,
+		Sources:   []string{"0.0.0.0/0"},
+	}
+	asg, xerr = svc.AddRuleToSecurityGroup(asg, rule)
+	require.Nil(t, xerr)
+	require.Len(t, asg.Rules, 1)
+	assert.NotEmpty(t, asg.Rules[0].IDs)
+
+	_, xerr = svc.AddRuleToSecurityGroup(asg, rule)
+	assert.NotNil(t, xerr, "duplicate rule must be refused")
+
+	asg, xerr = svc.DeleteRuleFromSecurityGroup(asg, rule)
+	require.Nil(t, xerr)
+	assert.Empty(t, asg.Rules)
+
+	asg, xerr = svc.InspectSecurityGroup(stacks.SecurityGroupParameter(asg.ID))
+	require.Nil(t, xerr)
+	assert.Equal(t, "sg", asg.Name)
+}
+
+func Test_StateIsSharedBetweenServices(t *testing.T) {
+	svc := resetService(t)
+
+	an, xerr := svc.CreateNetwork(abstract.NetworkRequest{Name: "net", CIDR: "10.0.0.0/16"})
+	require.Nil(t, xerr)
+
+	other := getService(t)
+	found, xerr := other.InspectNetworkByName("net")
+	require.Nil(t, xerr)
+	assert.Equal(t, an.ID, found.ID)
+
+	assert.Equal(t, svc.GetMetadataBucket().Name, other.GetMetadataBucket().Name)
+}
+
+func Test_PublicIPs(t *testing.T) {
+	svc := resetService(t)
+
+	an, xerr := svc.CreateNetwork(abstract.NetworkRequest{Name: "net", CIDR: "10.0.0.0/16"})
+	require.Nil(t, xerr)
+	as, xerr := svc.CreateSubnet(abstract.SubnetRequest{NetworkID: an.ID, Name: "subnet", CIDR: "10.0.1.0/24"})
+	require.Nil(t, xerr)
+	blue, _, xerr := svc.CreateHost(abstract.HostRequest{ResourceName: "blue", Subnets: []*abstract.Subnet{as}, TemplateID: "small", ImageID: "ubuntu-2004"})
+	require.Nil(t, xerr)
+	green, _, xerr := svc.CreateHost(abstract.HostRequest{ResourceName: "green", Subnets: []*abstract.Subnet{as}, TemplateID: "small", ImageID: "ubuntu-2004"})
+	require.Nil(t, xerr)
+
+	apip, xerr := svc.CreatePublicIP(abstract.PublicIPRequest{Name: "front"})
+	require.Nil(t, xerr)
+	assert.NotEmpty(t, apip.ID)
+	assert.NotEmpty(t, apip.IPAddress)
+	assert.Empty(t, apip.HostID)
+
+	_, xerr = svc.CreatePublicIP(abstract.PublicIPRequest{Name: "front"})
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrDuplicate{}, xerr)
+
+	require.Nil(t, svc.BindPublicIPToHost(apip, blue.Core.ID))
+	apip, xerr = svc.InspectPublicIP(apip.ID)
+	require.Nil(t, xerr)
+	assert.Equal(t, blue.Core.ID, apip.HostID)
+	assert.NotNil(t, svc.BindPublicIPToHost(apip, green.Core.ID), "public IP bound to another host must be unbound first")
+	assert.NotNil(t, svc.DeletePublicIP(apip.ID), "bound public IP must not be released")
+
+	// blue/green swap
+	require.Nil(t, svc.UnbindPublicIPFromHost(apip, blue.Core.ID))
+	require.Nil(t, svc.BindPublicIPToHost(apip, green.Core.ID))
+	ahf, xerr := svc.InspectHost(green.Core.ID)
+	require.Nil(t, xerr)
+	assert.Equal(t, apip.IPAddress, ahf.Networking.PublicIPv4)
+	ahf, xerr = svc.InspectHost(blue.Core.ID)
+	require.Nil(t, xerr)
+	assert.Empty(t, ahf.Networking.PublicIPv4)
+
+	xerr = svc.UnbindPublicIPFromHost(apip, blue.Core.ID)
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrNotFound{}, xerr)
+
+	// deleting the host keeps the public IP allocated
+	require.Nil(t, svc.DeleteHost(green.Core.ID))
+	apip, xerr = svc.InspectPublicIP(apip.ID)
+	require.Nil(t, xerr)
+	assert.Empty(t, apip.HostID)
+
+	list, xerr := svc.ListPublicIPs()
+	require.Nil(t, xerr)
+	assert.Len(t, list, 1)
+
+	require.Nil(t, svc.DeletePublicIP(apip.ID))
+	_, xerr = svc.InspectPublicIP(apip.ID)
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrNotFound{}, xerr)
+}
+
+// runSSH runs command on the host described by sconf
+func runSSH(ctx context.Context, sconf *system.SSHConfig, command string) (int, string, string, fail.Error) {
+	cmd, xerr := sconf.NewCommand(ctx, command)
+	if xerr != nil {
+		return -1, "", "", xerr
+	}
+	defer func() { _ = cmd.Close() }()
+
+	return cmd.RunWithTimeout(ctx, outputs.COLLECT, 10*time.Second)
+}
+
+func Test_HostsAnswerSSH(t *testing.T) {
+	svc := resetService(t)
+	ctx := context.Background()
+
+	an, xerr := svc.CreateNetwork(abstract.NetworkRequest{Name: "net", CIDR: "10.0.0.0/16"})
+	require.Nil(t, xerr)
+	as, xerr := svc.CreateSubnet(abstract.SubnetRequest{NetworkID: an.ID, Name: "subnet", CIDR: "10.0.1.0/24"})
+	require.Nil(t, xerr)
+	ahf, _, xerr := svc.CreateHost(abstract.HostRequest{ResourceName: "host", Subnets: []*abstract.Subnet{as}, TemplateID: "small", ImageID: "ubuntu-2004"})
+	require.Nil(t, xerr)
+
+	sconf := &system.SSHConfig{
+		Hostname:   ahf.Core.Name,
+		IPAddress:  ahf.Networking.IPv4Addresses[as.ID],
+		Port:       22,
+		User:       abstract.DefaultUser,
+		PrivateKey: ahf.Core.PrivateKey,
+	}
+
+	// commands succeed without doing anything, except the reading of the state of the install phases
+	retcode, stdout, _, xerr := runSSH(ctx, sconf, "sudo apt-get install -y docker-ce")
+	require.Nil(t, xerr)
+	assert.Equal(t, 0, retcode)
+	assert.Empty(t, stdout)
+	retcode, stdout, _, xerr = runSSH(ctx, sconf, "sudo cat /opt/safescale/var/state/user_data.final.done")
+	require.Nil(t, xerr)
+	assert.Equal(t, 0, retcode)
+	assert.True(t, strings.HasPrefix(stdout, "0,linux,ubuntu,20.04,host,"), stdout)
+
+	// copied files are kept with the host
+	dir, err := ioutil.TempDir("", "safescale-memory")
+	require.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	local := filepath.Join(dir, "local")
+	require.Nil(t, ioutil.WriteFile(local, []byte("content to copy"), 0640))
+	retcode, _, stderr, xerr := sconf.CopyWithTimeout(ctx, "/opt/safescale/var/tmp/remote", local, true, 10*time.Second)
+	require.Nil(t, xerr)
+	require.Equal(t, 0, retcode, stderr)
+	retcode, stdout, _, xerr = runSSH(ctx, sconf, "/usr/bin/md5sum /opt/safescale/var/tmp/remote")
+	require.Nil(t, xerr)
+	assert.Equal(t, 0, retcode)
+	assert.Equal(t, fmt.Sprintf("%x  /opt/safescale/var/tmp/remote\n", md5.Sum([]byte("content to copy"))), stdout)
+
+	// a stopped host does not answer
+	require.Nil(t, svc.StopHost(ahf.Core.ID, true))
+	retcode, _, _, xerr = runSSH(ctx, sconf, "true")
+	require.Nil(t, xerr)
+	assert.Equal(t, 255, retcode)
+}
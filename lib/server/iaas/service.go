@@ -17,6 +17,7 @@
 package iaas
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -47,6 +48,9 @@ import (
 	"github.com/CS-SI/SafeScale/lib/utils/strprocess"
 )
 
+// ImagesMetadataFolder is the folder of the metadata bucket where custom images are recorded
+const ImagesMetadataFolder = "images"
+
 //go:generate minimock -o mocks/mock_serviceapi.go -i github.com/CS-SI/SafeScale/lib/server/iaas.Service
 
 // Service consolidates Provider and ObjectStorage.Location interfaces in a single interface
@@ -601,11 +605,10 @@ func (svc service) FilterImages(filter string) ([]abstract.Image, fail.Error) {
 		return nil, fail.InvalidInstanceError()
 	}
 
-	imgs, err := svc.ListImages(false)
+	imgs, err := svc.ListImages(false) // already reduced by white/black lists
 	if err != nil {
 		return nil, err
 	}
-	imgs = svc.reduceImages(imgs)
 
 	if len(filter) == 0 {
 		return imgs, nil
@@ -677,7 +680,67 @@ func (svc service) ListImages(all bool) ([]abstract.Image, fail.Error) {
 	if err != nil {
 		return nil, err
 	}
-	return svc.reduceImages(imgs), nil
+	imgs = svc.reduceImages(imgs)
+
+	// custom images are not subject to white/black lists, they have been explicitly created by the user
+	customs, xerr := svc.listCustomImages()
+	if xerr != nil {
+		logrus.Warnf("failed to list custom images: %v", xerr)
+		return imgs, nil
+	}
+	for _, c := range customs {
+		found := false
+		for _, v := range imgs {
+			if v.ID == c.ID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			imgs = append(imgs, c)
+		}
+	}
+	return imgs, nil
+}
+
+// listCustomImages returns the custom images recorded in metadata
+func (svc service) listCustomImages() ([]abstract.Image, fail.Error) {
+	bucketName := svc.metadataBucket.Name
+	if bucketName == "" {
+		return []abstract.Image{}, nil
+	}
+
+	path := ImagesMetadataFolder + "/byID"
+	list, xerr := svc.ListObjects(bucketName, path, objectstorage.NoPrefix)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	out := make([]abstract.Image, 0, len(list))
+	for _, v := range list {
+		v = strings.Trim(v, "/")
+		if v == path {
+			continue
+		}
+
+		var buffer bytes.Buffer
+		if xerr = svc.ReadObject(bucketName, v, &buffer, 0, 0); xerr != nil {
+			return nil, xerr
+		}
+		content := buffer.Bytes()
+		if svc.metadataKey != nil {
+			var err error
+			if content, err = crypt.Decrypt(content, svc.metadataKey); err != nil {
+				return nil, fail.ConvertError(err)
+			}
+		}
+		img := abstract.NewImage()
+		if xerr = img.Deserialize(content); xerr != nil {
+			return nil, xerr
+		}
+		out = append(out, *img)
+	}
+	return out, nil
 }
 
 // SearchImage search an image corresponding to OS Name
@@ -697,6 +760,13 @@ func (svc service) SearchImage(osname string) (*abstract.Image, fail.Error) {
 		return nil, fail.NotFoundError("unable to find an image matching '%s'", osname)
 	}
 
+	// an exact match on name or ID wins (this is always the case when a custom image is requested)
+	for i, v := range imgs {
+		if v.Name == osname || v.ID == osname {
+			return &imgs[i], nil
+		}
+	}
+
 	// reg, err := regexp.Compile("[^A-Z0-9.]")
 	reg, err := regexp.Compile("[^A-Z0-9]")
 	if err != nil {
@@ -802,6 +872,46 @@ func (svc service) CreateHostWithKeyPair(request abstract.HostRequest) (*abstrac
 	return host, userData, kp, nil
 }
 
+// CreateHost creates a host on provider side, tagged as created by SafeScale for the tenant
+func (svc service) CreateHost(request abstract.HostRequest) (*abstract.HostFull, *userdata.Content, fail.Error) {
+	if svc.IsNull() {
+		return nil, nil, fail.InvalidInstanceError()
+	}
+
+	request.Labels = abstract.ManagementTags(request.Labels, svc.tenantName, svc.metadataBucket.GetName())
+	return svc.Provider.CreateHost(request)
+}
+
+// CreateNetwork creates a Network on provider side, tagged as created by SafeScale for the tenant
+func (svc service) CreateNetwork(request abstract.NetworkRequest) (*abstract.Network, fail.Error) {
+	if svc.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	request.Labels = abstract.ManagementTags(request.Labels, svc.tenantName, svc.metadataBucket.GetName())
+	return svc.Provider.CreateNetwork(request)
+}
+
+// CreateVolume creates a volume on provider side, tagged as created by SafeScale for the tenant
+func (svc service) CreateVolume(request abstract.VolumeRequest) (*abstract.Volume, fail.Error) {
+	if svc.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	request.Labels = abstract.ManagementTags(request.Labels, svc.tenantName, svc.metadataBucket.GetName())
+	return svc.Provider.CreateVolume(request)
+}
+
+// CreateVolumeFromSnapshot creates a volume from a snapshot on provider side, tagged as created by SafeScale for the tenant
+func (svc service) CreateVolumeFromSnapshot(snapshotID string, request abstract.VolumeRequest) (*abstract.Volume, fail.Error) {
+	if svc.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	request.Labels = abstract.ManagementTags(request.Labels, svc.tenantName, svc.metadataBucket.GetName())
+	return svc.Provider.CreateVolumeFromSnapshot(snapshotID, request)
+}
+
 // ListHostsByName list hosts by name
 func (svc service) ListHostsByName(details bool) (map[string]*abstract.HostFull, fail.Error) {
 	if svc.IsNull() {
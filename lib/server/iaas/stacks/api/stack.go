@@ -38,6 +38,10 @@ type Stack interface {
 
 	// InspectImage returns the Image referenced by id
 	InspectImage(id string) (abstract.Image, fail.Error)
+	// CreateImage creates a custom image from the host identified by request.HostID; returns when the image is usable
+	CreateImage(request abstract.ImageRequest) (*abstract.Image, fail.Error)
+	// DeleteImage deletes the custom image identified by id
+	DeleteImage(id string) fail.Error
 
 	// InspectTemplate returns the Template referenced by id
 	InspectTemplate(id string) (abstract.HostTemplate, fail.Error)
@@ -113,6 +117,19 @@ type Stack interface {
 	// DeleteVIP deletes the port corresponding to the VIP
 	DeleteVIP(*abstract.VirtualIP) fail.Error
 
+	// CreatePublicIP allocates a public IP not bound to any host
+	CreatePublicIP(request abstract.PublicIPRequest) (*abstract.PublicIP, fail.Error)
+	// InspectPublicIP returns the public IP identified by id
+	InspectPublicIP(id string) (*abstract.PublicIP, fail.Error)
+	// ListPublicIPs lists the public IPs allocated in the tenant
+	ListPublicIPs() ([]*abstract.PublicIP, fail.Error)
+	// DeletePublicIP releases the public IP identified by id
+	DeletePublicIP(id string) fail.Error
+	// BindPublicIPToHost associates the public IP to a host
+	BindPublicIPToHost(pip *abstract.PublicIP, hostParam stacks.HostParameter) fail.Error
+	// UnbindPublicIPFromHost dissociates the public IP from a host
+	UnbindPublicIPFromHost(pip *abstract.PublicIP, hostParam stacks.HostParameter) fail.Error
+
 	// CreateHost creates an host that fulfils the request
 	CreateHost(request abstract.HostRequest) (*abstract.HostFull, *userdata.Content, fail.Error)
 	// ClearHostStartupScript clears the Startup Script of the Host (if the stack can do it)
@@ -148,6 +165,19 @@ type Stack interface {
 	ListVolumes() ([]abstract.Volume, fail.Error)
 	// DeleteVolume deletes the volume identified by id
 	DeleteVolume(id string) fail.Error
+	// ResizeVolume grows the volume identified by id to newSize GB, and returns the volume updated
+	ResizeVolume(id string, newSize int) (*abstract.Volume, fail.Error)
+
+	// CreateVolumeSnapshot takes a snapshot of a volume
+	CreateVolumeSnapshot(request abstract.SnapshotRequest) (*abstract.Snapshot, fail.Error)
+	// InspectVolumeSnapshot returns the volume snapshot identified by id
+	InspectVolumeSnapshot(id string) (*abstract.Snapshot, fail.Error)
+	// ListVolumeSnapshots lists the snapshots of the volume identified by volumeID (all the snapshots if volumeID is empty)
+	ListVolumeSnapshots(volumeID string) ([]*abstract.Snapshot, fail.Error)
+	// DeleteVolumeSnapshot deletes the volume snapshot identified by id
+	DeleteVolumeSnapshot(id string) fail.Error
+	// CreateVolumeFromSnapshot creates a new volume filled with the content of the snapshot identified by snapshotID
+	CreateVolumeFromSnapshot(snapshotID string, request abstract.VolumeRequest) (*abstract.Volume, fail.Error)
 
 	// CreateVolumeAttachment attaches a volume to an host
 	CreateVolumeAttachment(request abstract.VolumeAttachmentRequest) (string, fail.Error)
@@ -621,6 +621,10 @@ func (s stack) CreateHost(request abstract.HostRequest) (ahf *abstract.HostFull,
 		}
 	}()
 
+	if xerr = s.rpcCreateTags([]*string{aws.String(ahf.Core.ID)}, fromAbstractLabels(request.Labels)); xerr != nil {
+		return nullAHF, nullUDC, xerr
+	}
+
 	if !ahf.OK() {
 		logrus.Warnf("Missing data in ahf: %v", ahf)
 	}
@@ -899,6 +903,7 @@ func (s stack) ListHosts(details bool) (hosts abstract.HostList, xerr fail.Error
 					return nullList, xerr
 				}
 			}
+			ahf.Core.Tags = toAbstractTags(instance.Tags)
 			hosts = append(hosts, ahf)
 		}
 	}
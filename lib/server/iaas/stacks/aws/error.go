@@ -48,6 +48,12 @@ func normalizeError(err error) fail.Error {
 			return fail.DuplicateError("a Security Group already exists with that name")
 		case "InvalidVolume.NotFound":
 			return fail.NotFoundError("failed to find Volume")
+		case "InvalidSnapshot.NotFound":
+			return fail.NotFoundError("failed to find Snapshot")
+		case "InvalidAMIID.NotFound":
+			return fail.NotFoundError("failed to find Image")
+		case "InvalidAMIName.Duplicate":
+			return fail.DuplicateError("an Image already exists with that name")
 		case "InvalidSubnetID.NotFound":
 			return fail.NotFoundError("failed to find Subnet")
 		case "InvalidParameterValue":
@@ -0,0 +1,151 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aws
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/retry"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+// CreateImage creates an AMI from the instance identified by request.HostID
+// Note: AWS reboots the instance to ensure file system consistency of the image
+func (s stack) CreateImage(request abstract.ImageRequest) (_ *abstract.Image, ferr fail.Error) {
+	nullAI := abstract.NewImage()
+	if s.IsNull() {
+		return nullAI, fail.InvalidInstanceError()
+	}
+	if request.Name == "" {
+		return nullAI, fail.InvalidParameterCannotBeEmptyStringError("request.Name")
+	}
+	if request.HostID == "" {
+		return nullAI, fail.InvalidParameterCannotBeEmptyStringError("request.HostID")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.aws") || tracing.ShouldTrace("stacks.compute"), "(%s, %s)", request.HostID, request.Name).WithStopwatch().Entering().Exiting()
+	defer fail.OnExitTraceError(&ferr)
+
+	imageID, xerr := s.rpcCreateImage(aws.String(request.Name), aws.String(request.HostID), aws.String(request.Description))
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			return nullAI, abstract.ResourceNotFoundError("host", request.HostID)
+		case *fail.ErrDuplicate:
+			return nullAI, abstract.ResourceDuplicateError("image", request.Name)
+		default:
+			return nullAI, xerr
+		}
+	}
+
+	defer func() {
+		if ferr != nil {
+			if derr := s.DeleteImage(aws.StringValue(imageID)); derr != nil {
+				logrus.Errorf("cleaning up on failure, failed to delete Image '%s': %v", request.Name, derr)
+				_ = ferr.AddConsequence(derr)
+			}
+		}
+	}()
+
+	var resp *ec2.Image
+	xerr = retry.WhileUnsuccessful(
+		func() error {
+			var innerXErr fail.Error
+			resp, innerXErr = s.rpcDescribeImageByID(imageID)
+			if innerXErr != nil {
+				return innerXErr
+			}
+
+			switch aws.StringValue(resp.State) {
+			case ec2.ImageStateAvailable:
+				return nil
+			case ec2.ImageStateFailed, ec2.ImageStateError, ec2.ImageStateInvalid, ec2.ImageStateDeregistered:
+				reason := ""
+				if resp.StateReason != nil {
+					reason = aws.StringValue(resp.StateReason.Message)
+				}
+				return retry.StopRetryError(fail.NewError("Image '%s' is in state '%s': %s", request.Name, aws.StringValue(resp.State), reason))
+			default:
+				return fail.NotAvailableError("Image '%s' not ready yet (state '%s')", request.Name, aws.StringValue(resp.State))
+			}
+		},
+		temporal.GetDefaultDelay(),
+		temporal.GetLongOperationTimeout(),
+	)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *retry.ErrStopRetry, *retry.ErrTimeout:
+			return nullAI, fail.Wrap(fail.Cause(xerr), "failed to create Image '%s'", request.Name)
+		default:
+			return nullAI, xerr
+		}
+	}
+
+	ai := abstract.NewImage()
+	*ai = toAbstractImage(*resp)
+	ai.HostID = request.HostID
+	for _, v := range resp.BlockDeviceMappings {
+		if aws.StringValue(v.DeviceName) == aws.StringValue(resp.RootDeviceName) && v.Ebs != nil {
+			ai.DiskSize = aws.Int64Value(v.Ebs.VolumeSize)
+		}
+	}
+	return ai, nil
+}
+
+// DeleteImage deregisters the AMI identified by id and deletes the EBS snapshots backing it
+func (s stack) DeleteImage(id string) (ferr fail.Error) {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.aws") || tracing.ShouldTrace("stacks.compute"), "(%s)", id).WithStopwatch().Entering().Exiting()
+	defer fail.OnExitTraceError(&ferr)
+
+	resp, xerr := s.rpcDescribeImageByID(aws.String(id))
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			return abstract.ResourceNotFoundError("image", id)
+		default:
+			return xerr
+		}
+	}
+
+	if xerr = s.rpcDeregisterImage(aws.String(id)); xerr != nil {
+		return xerr
+	}
+
+	// the snapshots are not deleted with the AMI, and would be charged for nothing
+	for _, v := range resp.BlockDeviceMappings {
+		if v.Ebs == nil || aws.StringValue(v.Ebs.SnapshotId) == "" {
+			continue
+		}
+		if xerr = s.rpcDeleteSnapshot(v.Ebs.SnapshotId); xerr != nil {
+			logrus.Warnf("failed to delete Snapshot '%s' of Image '%s': %v", aws.StringValue(v.Ebs.SnapshotId), id, xerr)
+		}
+	}
+	return nil
+}
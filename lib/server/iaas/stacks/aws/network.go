@@ -163,6 +163,10 @@ func (s stack) CreateNetwork(req abstract.NetworkRequest) (res *abstract.Network
 		}
 	}()
 
+	if xerr = s.rpcCreateTags([]*string{theVpc.VpcId}, fromAbstractLabels(req.Labels)); xerr != nil {
+		return nullAN, fail.Wrap(xerr, "failed to set labels of Network/VPC")
+	}
+
 	anet := abstract.NewNetwork()
 	anet.ID = aws.StringValue(theVpc.VpcId)
 	anet.Name = req.Name
@@ -209,6 +213,7 @@ func toAbstractNetwork(in *ec2.Vpc) (*abstract.Network, fail.Error) {
 	out := abstract.NewNetwork()
 	out.ID = aws.StringValue(in.VpcId)
 	out.CIDR = aws.StringValue(in.CidrBlock)
+	out.Tags = toAbstractTags(in.Tags)
 	for _, v := range in.Tags {
 		if *v.Key == *awsTagNameLabel {
 			out.Name = aws.StringValue(v.Value)
@@ -263,6 +268,7 @@ func (s stack) ListNetworks() (_ []*abstract.Network, xerr fail.Error) {
 		n := abstract.NewNetwork()
 		n.ID = aws.StringValue(vpc.VpcId)
 		n.CIDR = aws.StringValue(vpc.CidrBlock)
+		n.Tags = toAbstractTags(vpc.Tags)
 		for _, tag := range vpc.Tags {
 			if *tag.Key == *awsTagNameLabel && aws.StringValue(tag.Value) != "" {
 				n.Name = aws.StringValue(tag.Value)
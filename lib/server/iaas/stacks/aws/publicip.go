@@ -0,0 +1,172 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aws
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// toAbstractPublicIP converts an Elastic IP to *abstract.PublicIP
+func toAbstractPublicIP(address *ec2.Address) *abstract.PublicIP {
+	pip := abstract.NewPublicIP()
+	pip.ID = aws.StringValue(address.AllocationId)
+	pip.Type = aws.StringValue(address.Domain)
+	pip.IPAddress = aws.StringValue(address.PublicIp)
+	pip.HostID = aws.StringValue(address.InstanceId)
+	for _, v := range address.Tags {
+		if aws.StringValue(v.Key) == tagNameLabel {
+			pip.Name = aws.StringValue(v.Value)
+		}
+	}
+	return pip
+}
+
+// CreatePublicIP allocates an Elastic IP not bound to any host
+func (s stack) CreatePublicIP(request abstract.PublicIPRequest) (*abstract.PublicIP, fail.Error) {
+	nullPIP := abstract.NewPublicIP()
+	if s.IsNull() {
+		return nullPIP, fail.InvalidInstanceError()
+	}
+	if request.Name == "" {
+		return nullPIP, fail.InvalidParameterCannotBeEmptyStringError("request.Name")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.aws") || tracing.ShouldTrace("stacks.network"), "(%s)", request.Name).WithStopwatch().Entering().Exiting()
+
+	allocID, _, xerr := s.rpcAllocateAddress(request.Name)
+	if xerr != nil {
+		return nullPIP, xerr
+	}
+
+	address, xerr := s.rpcDescribeAddressByID(allocID)
+	if xerr != nil {
+		return nullPIP, xerr
+	}
+
+	pip := toAbstractPublicIP(address)
+	pip.Name = request.Name
+	pip.Description = request.Description
+	return pip, nil
+}
+
+// InspectPublicIP returns the Elastic IP identified by id (the allocation ID)
+func (s stack) InspectPublicIP(id string) (*abstract.PublicIP, fail.Error) {
+	nullPIP := abstract.NewPublicIP()
+	if s.IsNull() {
+		return nullPIP, fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return nullPIP, fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.aws") || tracing.ShouldTrace("stacks.network"), "(%s)", id).WithStopwatch().Entering().Exiting()
+
+	address, xerr := s.rpcDescribeAddressByID(aws.String(id))
+	if xerr != nil {
+		return nullPIP, xerr
+	}
+	return toAbstractPublicIP(address), nil
+}
+
+// ListPublicIPs lists the Elastic IPs of the region
+func (s stack) ListPublicIPs() ([]*abstract.PublicIP, fail.Error) {
+	var emptySlice []*abstract.PublicIP
+	if s.IsNull() {
+		return emptySlice, fail.InvalidInstanceError()
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.aws") || tracing.ShouldTrace("stacks.network")).WithStopwatch().Entering().Exiting()
+
+	list, xerr := s.rpcDescribeAddressesByAllocationID(nil)
+	if xerr != nil {
+		return emptySlice, xerr
+	}
+
+	out := make([]*abstract.PublicIP, 0, len(list))
+	for _, v := range list {
+		out = append(out, toAbstractPublicIP(v))
+	}
+	return out, nil
+}
+
+// DeletePublicIP releases the Elastic IP identified by id
+func (s stack) DeletePublicIP(id string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.aws") || tracing.ShouldTrace("stacks.network"), "(%s)", id).WithStopwatch().Entering().Exiting()
+
+	return s.rpcReleaseAddress(aws.String(id))
+}
+
+// BindPublicIPToHost associates the Elastic IP to a host
+func (s stack) BindPublicIPToHost(pip *abstract.PublicIP, hostParam stacks.HostParameter) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if pip == nil {
+		return fail.InvalidParameterCannotBeNilError("pip")
+	}
+	ahf, hostLabel, xerr := stacks.ValidateHostParameter(hostParam)
+	if xerr != nil {
+		return xerr
+	}
+	if ahf.Core.ID == "" {
+		return fail.InvalidParameterError("hostParam", "must contain the ID of the host")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.aws") || tracing.ShouldTrace("stacks.network"), "(%s, %s)", pip.ID, hostLabel).WithStopwatch().Entering().Exiting()
+
+	_, xerr = s.rpcAssociateAddressToInstance(aws.String(ahf.Core.ID), aws.String(pip.ID))
+	return xerr
+}
+
+// UnbindPublicIPFromHost dissociates the Elastic IP from a host
+func (s stack) UnbindPublicIPFromHost(pip *abstract.PublicIP, hostParam stacks.HostParameter) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if pip == nil {
+		return fail.InvalidParameterCannotBeNilError("pip")
+	}
+	ahf, hostLabel, xerr := stacks.ValidateHostParameter(hostParam)
+	if xerr != nil {
+		return xerr
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.aws") || tracing.ShouldTrace("stacks.network"), "(%s, %s)", pip.ID, hostLabel).WithStopwatch().Entering().Exiting()
+
+	address, xerr := s.rpcDescribeAddressByID(aws.String(pip.ID))
+	if xerr != nil {
+		return xerr
+	}
+	if aws.StringValue(address.AssociationId) == "" || (ahf.Core.ID != "" && aws.StringValue(address.InstanceId) != ahf.Core.ID) {
+		return fail.NotFoundError("Elastic IP '%s' is not associated with host %s", pip.ID, hostLabel)
+	}
+	return s.rpcDisassociateAddress(address.AssociationId)
+}
@@ -880,6 +880,74 @@ func (s stack) rpcDescribeAddressByIP(ip *string) (*ec2.Address, fail.Error) {
 	return resp.Addresses[0], nil
 }
 
+func (s stack) rpcDescribeAddressesByAllocationID(ids []*string) ([]*ec2.Address, fail.Error) {
+	request := ec2.DescribeAddressesInput{}
+	if len(ids) > 0 {
+		request.AllocationIds = ids
+	}
+	var resp *ec2.DescribeAddressesOutput
+	xerr := stacks.RetryableRemoteCall(
+		func() (err error) {
+			resp, err = s.EC2Service.DescribeAddresses(&request)
+			return err
+		},
+		normalizeError,
+	)
+	if xerr != nil {
+		return []*ec2.Address{}, xerr
+	}
+	if resp == nil {
+		return []*ec2.Address{}, nil
+	}
+	return resp.Addresses, nil
+}
+
+func (s stack) rpcDescribeAddressByID(id *string) (*ec2.Address, fail.Error) {
+	if xerr := validateAWSString(id, "id", true); xerr != nil {
+		return nil, xerr
+	}
+
+	resp, xerr := s.rpcDescribeAddressesByAllocationID([]*string{id})
+	if xerr != nil {
+		return nil, xerr
+	}
+	if len(resp) == 0 {
+		return nil, fail.NotFoundError("failed to find Elastic IP with ID %s", aws.StringValue(id))
+	}
+	if len(resp) > 1 {
+		return nil, fail.InconsistentError("more than one Elastic IP with ID %s returned by the Cloud Provider", aws.StringValue(id))
+	}
+	return resp[0], nil
+}
+
+func (s stack) rpcAssociateAddressToInstance(instanceID, addressID *string) (*string, fail.Error) {
+	if xerr := validateAWSString(instanceID, "instanceID", true); xerr != nil {
+		return nil, xerr
+	}
+	if xerr := validateAWSString(addressID, "addressID", true); xerr != nil {
+		return nil, xerr
+	}
+
+	request := ec2.AssociateAddressInput{
+		AllocationId: addressID,
+		InstanceId:   instanceID,
+	}
+	var resp *ec2.AssociateAddressOutput
+	xerr := stacks.RetryableRemoteCall(
+		func() (err error) {
+			resp, err = s.EC2Service.AssociateAddress(&request)
+			return err
+		}, normalizeError,
+	)
+	if xerr != nil {
+		return nil, xerr
+	}
+	if resp == nil || resp.AssociationId == nil || aws.StringValue(resp.AssociationId) == "" {
+		return nil, fail.InconsistentError("invalid empty response from Cloud Provider")
+	}
+	return resp.AssociationId, nil
+}
+
 func (s stack) rpcDescribeInstanceByID(id *string) (*ec2.Instance, fail.Error) {
 	if xerr := validateAWSString(id, "id", true); xerr != nil {
 		return &ec2.Instance{}, xerr
@@ -2117,6 +2185,61 @@ func (s stack) rpcCreateVolume(name *string, size int64, speed string) (_ *ec2.V
 	return resp, nil
 }
 
+func (s stack) rpcCreateVolumeFromSnapshot(name, snapshotID *string, size int64, speed string) (_ *ec2.Volume, ferr fail.Error) {
+	if name == nil {
+		return &ec2.Volume{}, fail.InvalidParameterCannotBeNilError("name")
+	}
+	if aws.StringValue(name) == "" {
+		return &ec2.Volume{}, fail.InvalidParameterError("name", "cannot be empty AWS String")
+	}
+	if snapshotID == nil {
+		return &ec2.Volume{}, fail.InvalidParameterCannotBeNilError("snapshotID")
+	}
+	if aws.StringValue(snapshotID) == "" {
+		return &ec2.Volume{}, fail.InvalidParameterError("snapshotID", "cannot be empty AWS String")
+	}
+
+	request := ec2.CreateVolumeInput{
+		SnapshotId:       snapshotID,
+		VolumeType:       aws.String(speed),
+		AvailabilityZone: aws.String(s.AwsConfig.Zone),
+	}
+	if size > 0 {
+		request.Size = aws.Int64(size)
+	}
+	var resp *ec2.Volume
+	xerr := stacks.RetryableRemoteCall(
+		func() (err error) {
+			resp, err = s.EC2Service.CreateVolume(&request)
+			return err
+		},
+		normalizeError,
+	)
+	if xerr != nil {
+		return &ec2.Volume{}, xerr
+	}
+
+	defer func() {
+		if ferr != nil {
+			if derr := s.rpcDeleteVolume(resp.VolumeId); derr != nil {
+				_ = ferr.AddConsequence(fail.Wrap(derr, "cleaning up on failure, failed to delete Volume '%s'", aws.StringValue(name)))
+			}
+		}
+	}()
+
+	tags := []*ec2.Tag{
+		{
+			Key:   awsTagNameLabel,
+			Value: name,
+		},
+	}
+	if xerr := s.rpcCreateTags([]*string{resp.VolumeId}, tags); xerr != nil {
+		return nil, xerr
+	}
+
+	return resp, nil
+}
+
 func (s stack) rpcDeleteVolume(id *string) fail.Error {
 	if id == nil {
 		return fail.InvalidParameterCannotBeNilError("id")
@@ -2136,3 +2259,227 @@ func (s stack) rpcDeleteVolume(id *string) fail.Error {
 		normalizeError,
 	)
 }
+
+func (s stack) rpcModifyVolumeSize(id *string, size int64) (*ec2.VolumeModification, fail.Error) {
+	if id == nil {
+		return &ec2.VolumeModification{}, fail.InvalidParameterCannotBeNilError("id")
+	}
+	if aws.StringValue(id) == "" {
+		return &ec2.VolumeModification{}, fail.InvalidParameterError("id", "cannot be empty AWS String")
+	}
+
+	request := ec2.ModifyVolumeInput{
+		VolumeId: id,
+		Size:     aws.Int64(size),
+	}
+	var resp *ec2.ModifyVolumeOutput
+	xerr := stacks.RetryableRemoteCall(
+		func() (err error) {
+			resp, err = s.EC2Service.ModifyVolume(&request)
+			return err
+		},
+		normalizeError,
+	)
+	if xerr != nil {
+		return &ec2.VolumeModification{}, xerr
+	}
+	return resp.VolumeModification, nil
+}
+
+func (s stack) rpcDescribeVolumeModification(id *string) (*ec2.VolumeModification, fail.Error) {
+	if id == nil {
+		return &ec2.VolumeModification{}, fail.InvalidParameterCannotBeNilError("id")
+	}
+	if aws.StringValue(id) == "" {
+		return &ec2.VolumeModification{}, fail.InvalidParameterError("id", "cannot be empty AWS String")
+	}
+
+	request := ec2.DescribeVolumesModificationsInput{
+		VolumeIds: []*string{id},
+	}
+	var resp *ec2.DescribeVolumesModificationsOutput
+	xerr := stacks.RetryableRemoteCall(
+		func() (err error) {
+			resp, err = s.EC2Service.DescribeVolumesModifications(&request)
+			return err
+		},
+		normalizeError,
+	)
+	if xerr != nil {
+		return &ec2.VolumeModification{}, xerr
+	}
+	if len(resp.VolumesModifications) == 0 {
+		return &ec2.VolumeModification{}, fail.NotFoundError("failed to find a modification of Volume with ID %s", aws.StringValue(id))
+	}
+
+	// the most recent modification is the one started last
+	last := resp.VolumesModifications[0]
+	for _, v := range resp.VolumesModifications[1:] {
+		if aws.TimeValue(v.StartTime).After(aws.TimeValue(last.StartTime)) {
+			last = v
+		}
+	}
+	return last, nil
+}
+
+func (s stack) rpcCreateSnapshot(name, volumeID, description *string) (_ *ec2.Snapshot, ferr fail.Error) {
+	if name == nil {
+		return &ec2.Snapshot{}, fail.InvalidParameterCannotBeNilError("name")
+	}
+	if aws.StringValue(name) == "" {
+		return &ec2.Snapshot{}, fail.InvalidParameterError("name", "cannot be empty AWS String")
+	}
+	if volumeID == nil {
+		return &ec2.Snapshot{}, fail.InvalidParameterCannotBeNilError("volumeID")
+	}
+	if aws.StringValue(volumeID) == "" {
+		return &ec2.Snapshot{}, fail.InvalidParameterError("volumeID", "cannot be empty AWS String")
+	}
+
+	request := ec2.CreateSnapshotInput{
+		VolumeId:    volumeID,
+		Description: description,
+		TagSpecifications: []*ec2.TagSpecification{
+			{
+				ResourceType: aws.String(ec2.ResourceTypeSnapshot),
+				Tags: []*ec2.Tag{
+					{
+						Key:   awsTagNameLabel,
+						Value: name,
+					},
+				},
+			},
+		},
+	}
+	var resp *ec2.Snapshot
+	xerr := stacks.RetryableRemoteCall(
+		func() (err error) {
+			resp, err = s.EC2Service.CreateSnapshot(&request)
+			return err
+		},
+		normalizeError,
+	)
+	if xerr != nil {
+		return &ec2.Snapshot{}, xerr
+	}
+	return resp, nil
+}
+
+// rpcDescribeSnapshots returns the snapshots owned by the account; if volumeID is not nil, only the snapshots of this volume are returned
+func (s stack) rpcDescribeSnapshots(ids []*string, volumeID *string) ([]*ec2.Snapshot, fail.Error) {
+	request := ec2.DescribeSnapshotsInput{
+		OwnerIds: []*string{aws.String("self")},
+	}
+	if len(ids) > 0 {
+		request.SnapshotIds = ids
+	}
+	if aws.StringValue(volumeID) != "" {
+		request.Filters = []*ec2.Filter{
+			{
+				Name:   aws.String("volume-id"),
+				Values: []*string{volumeID},
+			},
+		}
+	}
+	var resp *ec2.DescribeSnapshotsOutput
+	xerr := stacks.RetryableRemoteCall(
+		func() (err error) {
+			resp, err = s.EC2Service.DescribeSnapshots(&request)
+			return err
+		},
+		normalizeError,
+	)
+	if xerr != nil {
+		return []*ec2.Snapshot{}, xerr
+	}
+	if len(resp.Snapshots) == 0 {
+		return []*ec2.Snapshot{}, nil
+	}
+	return resp.Snapshots, nil
+}
+
+func (s stack) rpcDescribeSnapshotByID(id *string) (*ec2.Snapshot, fail.Error) {
+	if id == nil {
+		return &ec2.Snapshot{}, fail.InvalidParameterCannotBeNilError("id")
+	}
+	if aws.StringValue(id) == "" {
+		return &ec2.Snapshot{}, fail.InvalidParameterError("id", "cannot be empty AWS String")
+	}
+
+	resp, xerr := s.rpcDescribeSnapshots([]*string{id}, nil)
+	if xerr != nil {
+		return &ec2.Snapshot{}, xerr
+	}
+	if len(resp) == 0 {
+		return &ec2.Snapshot{}, fail.NotFoundError("failed to find a Snapshot with ID %s", aws.StringValue(id))
+	}
+	if len(resp) > 1 {
+		return &ec2.Snapshot{}, fail.InconsistentError("found more than one Snapshot with ID %s", aws.StringValue(id))
+	}
+
+	return resp[0], nil
+}
+
+func (s stack) rpcDeleteSnapshot(id *string) fail.Error {
+	if id == nil {
+		return fail.InvalidParameterCannotBeNilError("id")
+	}
+	if aws.StringValue(id) == "" {
+		return fail.InvalidParameterError("id", "cannot be empty AWS String")
+	}
+
+	request := ec2.DeleteSnapshotInput{
+		SnapshotId: id,
+	}
+	return stacks.RetryableRemoteCall(
+		func() error {
+			_, err := s.EC2Service.DeleteSnapshot(&request)
+			return err
+		},
+		normalizeError,
+	)
+}
+
+func (s stack) rpcCreateImage(name, instanceID, description *string) (_ *string, ferr fail.Error) {
+	if xerr := validateAWSString(name, "name", true); xerr != nil {
+		return nil, xerr
+	}
+	if xerr := validateAWSString(instanceID, "instanceID", true); xerr != nil {
+		return nil, xerr
+	}
+
+	request := ec2.CreateImageInput{
+		InstanceId:  instanceID,
+		Name:        name,
+		Description: description,
+	}
+	var resp *ec2.CreateImageOutput
+	xerr := stacks.RetryableRemoteCall(
+		func() (err error) {
+			resp, err = s.EC2Service.CreateImage(&request)
+			return err
+		},
+		normalizeError,
+	)
+	if xerr != nil {
+		return nil, xerr
+	}
+	return resp.ImageId, nil
+}
+
+func (s stack) rpcDeregisterImage(id *string) fail.Error {
+	if xerr := validateAWSString(id, "id", true); xerr != nil {
+		return xerr
+	}
+
+	request := ec2.DeregisterImageInput{
+		ImageId: id,
+	}
+	return stacks.RetryableRemoteCall(
+		func() error {
+			_, err := s.EC2Service.DeregisterImage(&request)
+			return err
+		},
+		normalizeError,
+	)
+}
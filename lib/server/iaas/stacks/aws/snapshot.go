@@ -0,0 +1,201 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aws
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumestate"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// CreateVolumeSnapshot takes an EBS snapshot of a volume
+func (s stack) CreateVolumeSnapshot(request abstract.SnapshotRequest) (_ *abstract.Snapshot, ferr fail.Error) {
+	nullAS := abstract.NewSnapshot()
+	if s.IsNull() {
+		return nullAS, fail.InvalidInstanceError()
+	}
+	if request.Name == "" {
+		return nullAS, fail.InvalidParameterCannotBeEmptyStringError("request.Name")
+	}
+	if request.VolumeID == "" {
+		return nullAS, fail.InvalidParameterCannotBeEmptyStringError("request.VolumeID")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.aws") || tracing.ShouldTrace("stacks.volume"), "(%v)", request).WithStopwatch().Entering().Exiting()
+	defer fail.OnExitLogError(&ferr)
+
+	resp, xerr := s.rpcCreateSnapshot(aws.String(request.Name), aws.String(request.VolumeID), aws.String(request.Description))
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			return nullAS, abstract.ResourceNotFoundError("volume", request.VolumeID)
+		default:
+			return nullAS, xerr
+		}
+	}
+
+	return toAbstractSnapshot(resp), nil
+}
+
+// InspectVolumeSnapshot returns the EBS snapshot identified by id
+func (s stack) InspectVolumeSnapshot(id string) (*abstract.Snapshot, fail.Error) {
+	nullAS := abstract.NewSnapshot()
+	if s.IsNull() {
+		return nullAS, fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return nullAS, fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.aws") || tracing.ShouldTrace("stacks.volume"), "(%s)", id).WithStopwatch().Entering().Exiting()
+
+	resp, xerr := s.rpcDescribeSnapshotByID(aws.String(id))
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound, *fail.ErrInvalidRequest:
+			return nullAS, abstract.ResourceNotFoundError("snapshot", id)
+		default:
+			return nullAS, xerr
+		}
+	}
+
+	return toAbstractSnapshot(resp), nil
+}
+
+// ListVolumeSnapshots lists the EBS snapshots of the volume identified by volumeID (all the snapshots owned by the account if volumeID is empty)
+func (s stack) ListVolumeSnapshots(volumeID string) ([]*abstract.Snapshot, fail.Error) {
+	var emptySlice []*abstract.Snapshot
+	if s.IsNull() {
+		return emptySlice, fail.InvalidInstanceError()
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.aws") || tracing.ShouldTrace("stacks.volume"), "(%s)", volumeID).WithStopwatch().Entering().Exiting()
+
+	resp, xerr := s.rpcDescribeSnapshots(nil, aws.String(volumeID))
+	if xerr != nil {
+		return emptySlice, xerr
+	}
+
+	out := make([]*abstract.Snapshot, 0, len(resp))
+	for _, v := range resp {
+		out = append(out, toAbstractSnapshot(v))
+	}
+	return out, nil
+}
+
+// DeleteVolumeSnapshot deletes the EBS snapshot identified by id
+func (s stack) DeleteVolumeSnapshot(id string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.aws") || tracing.ShouldTrace("stacks.volume"), "(%s)", id).WithStopwatch().Entering().Exiting()
+
+	xerr := s.rpcDeleteSnapshot(aws.String(id))
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			return abstract.ResourceNotFoundError("snapshot", id)
+		default:
+			return xerr
+		}
+	}
+	return nil
+}
+
+// CreateVolumeFromSnapshot creates a new volume filled with the content of the EBS snapshot identified by snapshotID
+// If request.Size is 0, the size of the snapshot is used
+func (s stack) CreateVolumeFromSnapshot(snapshotID string, request abstract.VolumeRequest) (_ *abstract.Volume, ferr fail.Error) {
+	nullAV := abstract.NewVolume()
+	if s.IsNull() {
+		return nullAV, fail.InvalidInstanceError()
+	}
+	if snapshotID == "" {
+		return nullAV, fail.InvalidParameterCannotBeEmptyStringError("snapshotID")
+	}
+	if request.Name == "" {
+		return nullAV, fail.InvalidParameterCannotBeEmptyStringError("request.Name")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.aws") || tracing.ShouldTrace("stacks.volume"), "(%s, %v)", snapshotID, request).WithStopwatch().Entering().Exiting()
+	defer fail.OnExitLogError(&ferr)
+
+	snap, xerr := s.InspectVolumeSnapshot(snapshotID)
+	if xerr != nil {
+		return nullAV, xerr
+	}
+	if request.Size != 0 && request.Size < snap.Size {
+		return nullAV, fail.InvalidRequestError("cannot restore snapshot '%s' of %d GB in a volume of %d GB", snap.Name, snap.Size, request.Size)
+	}
+
+	volumeType, _ := fromAbstractVolumeSpeed(request.Speed)
+	resp, xerr := s.rpcCreateVolumeFromSnapshot(aws.String(request.Name), aws.String(snapshotID), int64(request.Size), volumeType)
+	if xerr != nil {
+		return nullAV, xerr
+	}
+
+	volume := abstract.Volume{
+		ID:    aws.StringValue(resp.VolumeId),
+		Name:  request.Name,
+		Size:  int(aws.Int64Value(resp.Size)),
+		Speed: toAbstractVolumeSpeed(resp.VolumeType),
+		State: toAbstractVolumeState(resp.State),
+	}
+	return &volume, nil
+}
+
+// toAbstractSnapshot converts an EBS snapshot to *abstract.Snapshot
+func toAbstractSnapshot(in *ec2.Snapshot) *abstract.Snapshot {
+	out := abstract.NewSnapshot()
+	out.ID = aws.StringValue(in.SnapshotId)
+	out.Description = aws.StringValue(in.Description)
+	out.VolumeID = aws.StringValue(in.VolumeId)
+	out.Size = int(aws.Int64Value(in.VolumeSize))
+	out.State = toAbstractSnapshotState(in.State)
+	out.CreatedAt = aws.TimeValue(in.StartTime)
+	for _, v := range in.Tags {
+		if aws.StringValue(v.Key) == tagNameLabel {
+			out.Name = aws.StringValue(v.Value)
+			break
+		}
+	}
+	if out.Name == "" {
+		out.Name = out.ID
+	}
+	return out
+}
+
+func toAbstractSnapshotState(s *string) volumestate.Enum {
+	switch aws.StringValue(s) {
+	case ec2.SnapshotStatePending:
+		return volumestate.Creating
+	case ec2.SnapshotStateCompleted:
+		return volumestate.Available
+	case ec2.SnapshotStateError:
+		return volumestate.Error
+	default:
+		return volumestate.Unknown
+	}
+}
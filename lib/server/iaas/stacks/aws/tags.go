@@ -0,0 +1,57 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aws
+
+import (
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// fromAbstractLabels converts user-defined labels to AWS tags, sorted by key
+func fromAbstractLabels(labels map[string]string) []*ec2.Tag {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	tags := make([]*ec2.Tag, 0, len(keys))
+	for _, k := range keys {
+		tags = append(tags, &ec2.Tag{
+			Key:   aws.String(k),
+			Value: aws.String(labels[k]),
+		})
+	}
+	return tags
+}
+
+// toAbstractTags converts AWS tags to a map indexed by key
+func toAbstractTags(tags []*ec2.Tag) map[string]string {
+	out := make(map[string]string, len(tags))
+	for _, v := range tags {
+		if v != nil {
+			out[aws.StringValue(v.Key)] = aws.StringValue(v.Value)
+		}
+	}
+	return out
+}
@@ -32,6 +32,8 @@ import (
 	"github.com/CS-SI/SafeScale/lib/utils/debug"
 	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/retry"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
 )
 
 // CreateVolume ...
@@ -63,6 +65,10 @@ func (s stack) CreateVolume(request abstract.VolumeRequest) (_ *abstract.Volume,
 		}
 	}()
 
+	if xerr = s.rpcCreateTags([]*string{resp.VolumeId}, fromAbstractLabels(request.Labels)); xerr != nil {
+		return nil, xerr
+	}
+
 	volume := abstract.Volume{
 		ID:    aws.StringValue(resp.VolumeId),
 		Name:  request.Name,
@@ -226,6 +232,7 @@ func (s stack) ListVolumes() (_ []abstract.Volume, xerr fail.Error) {
 			Size:  int(aws.Int64Value(v.Size)),
 			Speed: toAbstractVolumeSpeed(v.VolumeType),
 			State: toAbstractVolumeState(v.State),
+			Tags:  toAbstractTags(v.Tags),
 		}
 		volumes = append(volumes, volume)
 	}
@@ -257,6 +264,63 @@ func (s stack) DeleteVolume(id string) (xerr fail.Error) {
 	)
 }
 
+// ResizeVolume grows the volume identified by id to newSize GB
+// Returns when the modification is optimizing, which is enough to grow the file system of the volume
+func (s stack) ResizeVolume(id string, newSize int) (_ *abstract.Volume, xerr fail.Error) {
+	nullAV := abstract.NewVolume()
+	if s.IsNull() {
+		return nullAV, fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return nullAV, fail.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.aws") || tracing.ShouldTrace("stacks.volume"), "(%s, %d)", id, newSize).WithStopwatch().Entering().Exiting()
+	defer fail.OnExitLogError(&xerr)
+
+	av, xerr := s.InspectVolume(id)
+	if xerr != nil {
+		return nullAV, xerr
+	}
+	if newSize <= av.Size {
+		return nullAV, fail.InvalidParameterError("newSize", "must be greater than the current size of the volume (%d GB)", av.Size)
+	}
+
+	if _, xerr = s.rpcModifyVolumeSize(aws.String(id), int64(newSize)); xerr != nil {
+		return nullAV, fail.Wrap(xerr, "failed to modify the size of volume '%s'", av.Name)
+	}
+
+	xerr = retry.WhileUnsuccessful(
+		func() error {
+			modification, innerXErr := s.rpcDescribeVolumeModification(aws.String(id))
+			if innerXErr != nil {
+				return innerXErr
+			}
+
+			switch aws.StringValue(modification.ModificationState) {
+			case ec2.VolumeModificationStateOptimizing, ec2.VolumeModificationStateCompleted:
+				return nil
+			case ec2.VolumeModificationStateFailed:
+				return retry.StopRetryError(fail.NewError("modification of volume '%s' failed: %s", av.Name, aws.StringValue(modification.StatusMessage)))
+			default:
+				return fail.NotAvailableError("volume '%s' is still being modified", av.Name)
+			}
+		},
+		temporal.GetDefaultDelay(),
+		temporal.GetOperationTimeout(),
+	)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *retry.ErrStopRetry:
+			return nullAV, fail.Wrap(fail.Cause(xerr), "stopping retries")
+		default:
+			return nullAV, xerr
+		}
+	}
+
+	return s.InspectVolume(id)
+}
+
 // CreateVolumeAttachment ...
 func (s stack) CreateVolumeAttachment(request abstract.VolumeAttachmentRequest) (_ string, xerr fail.Error) {
 	if s.IsNull() {
@@ -0,0 +1,98 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gcp
+
+import (
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// CreateImage creates a custom image from the boot disk of the instance identified by request.HostID
+// Note: the image is forced if the instance is running; stop the host first to ensure file system consistency
+func (s stack) CreateImage(request abstract.ImageRequest) (_ *abstract.Image, xerr fail.Error) {
+	nullAI := abstract.NewImage()
+	if s.IsNull() {
+		return nullAI, fail.InvalidInstanceError()
+	}
+	if request.Name == "" {
+		return nullAI, fail.InvalidParameterCannotBeEmptyStringError("request.Name")
+	}
+	if request.HostID == "" {
+		return nullAI, fail.InvalidParameterCannotBeEmptyStringError("request.HostID")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.gcp") || tracing.ShouldTrace("stacks.compute"), "(%s, %s)", request.HostID, request.Name).WithStopwatch().Entering().Exiting()
+	defer fail.OnExitLogError(&xerr)
+
+	instance, xerr := s.rpcGetInstance(request.HostID)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			return nullAI, abstract.ResourceNotFoundError("host", request.HostID)
+		default:
+			return nullAI, xerr
+		}
+	}
+
+	var sourceDisk string
+	for _, v := range instance.Disks {
+		if v.Boot {
+			sourceDisk = v.Source
+			break
+		}
+	}
+	if sourceDisk == "" {
+		return nullAI, fail.InconsistentError("failed to find boot disk of Host '%s'", request.HostID)
+	}
+
+	resp, xerr := s.rpcCreateImage(request.Name, request.Description, sourceDisk)
+	if xerr != nil {
+		return nullAI, xerr
+	}
+
+	ai := abstract.NewImage()
+	*ai = toAbstractImage(*resp)
+	ai.HostID = request.HostID
+	return ai, nil
+}
+
+// DeleteImage deletes the custom image identified by id
+func (s stack) DeleteImage(id string) (xerr fail.Error) {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.gcp") || tracing.ShouldTrace("stacks.compute"), "(%s)", id).WithStopwatch().Entering().Exiting()
+	defer fail.OnExitLogError(&xerr)
+
+	resp, xerr := s.rpcGetImageByID(id)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			return abstract.ResourceNotFoundError("image", id)
+		default:
+			return xerr
+		}
+	}
+
+	return s.rpcDeleteImage(resp.Name)
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gcp
+
+import (
+	"strings"
+)
+
+const maxGCPLabelLength = 63
+
+// fromAbstractLabels converts user-defined labels to GCP labels
+// GCP only accepts lowercase letters, digits, '_' and '-' (keys must start with a letter), so
+// invalid characters are replaced by '_'; the original labels are kept in SafeScale metadata.
+func fromAbstractLabels(labels map[string]string) map[string]string {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	out := make(map[string]string, len(labels))
+	for k, v := range labels {
+		key := sanitizeGCPLabel(k)
+		if key == "" {
+			continue
+		}
+		if key[0] < 'a' || key[0] > 'z' {
+			key = "l" + key
+			if len(key) > maxGCPLabelLength {
+				key = key[:maxGCPLabelLength]
+			}
+		}
+		out[key] = sanitizeGCPLabel(v)
+	}
+	return out
+}
+
+func sanitizeGCPLabel(in string) string {
+	in = strings.ToLower(in)
+	out := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' || r == '-' {
+			return r
+		}
+		return '_'
+	}, in)
+	if len(out) > maxGCPLabelLength {
+		out = out[:maxGCPLabelLength]
+	}
+	return out
+}
@@ -0,0 +1,210 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gcp
+
+import (
+	"fmt"
+	"path"
+
+	"google.golang.org/api/compute/v1"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// toAbstractPublicIP converts a GCP external address to *abstract.PublicIP
+// GCP identifies addresses by name, so ID and Name are the same
+func (s stack) toAbstractPublicIP(address *compute.Address) (*abstract.PublicIP, fail.Error) {
+	pip := abstract.NewPublicIP()
+	pip.ID = address.Name
+	pip.Name = address.Name
+	pip.Type = address.AddressType
+	pip.Description = address.Description
+	pip.IPAddress = address.Address
+	if len(address.Users) > 0 {
+		// Users contains the self-link of the instance using the address; its last element is the instance name
+		instance, xerr := s.rpcGetInstance(path.Base(address.Users[0]))
+		if xerr != nil {
+			return nil, xerr
+		}
+		pip.HostID = fmt.Sprintf("%d", instance.Id)
+	}
+	return pip, nil
+}
+
+// CreatePublicIP allocates a regional external address not bound to any host
+func (s stack) CreatePublicIP(request abstract.PublicIPRequest) (*abstract.PublicIP, fail.Error) {
+	nullPIP := abstract.NewPublicIP()
+	if s.IsNull() {
+		return nullPIP, fail.InvalidInstanceError()
+	}
+	if request.Name == "" {
+		return nullPIP, fail.InvalidParameterCannotBeEmptyStringError("request.Name")
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("stacks.network") || tracing.ShouldTrace("stack.gcp"), "('%s')", request.Name).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	address, xerr := s.rpcCreateExternalAddress(request.Name, false)
+	if xerr != nil {
+		return nullPIP, xerr
+	}
+
+	pip, xerr := s.toAbstractPublicIP(address)
+	if xerr != nil {
+		return nullPIP, xerr
+	}
+	pip.Description = request.Description
+	return pip, nil
+}
+
+// InspectPublicIP returns the external address identified by id
+func (s stack) InspectPublicIP(id string) (*abstract.PublicIP, fail.Error) {
+	nullPIP := abstract.NewPublicIP()
+	if s.IsNull() {
+		return nullPIP, fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return nullPIP, fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("stacks.network") || tracing.ShouldTrace("stack.gcp"), "('%s')", id).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	address, xerr := s.rpcGetExternalAddress(id, false)
+	if xerr != nil {
+		return nullPIP, xerr
+	}
+	return s.toAbstractPublicIP(address)
+}
+
+// ListPublicIPs lists the external addresses of the region
+func (s stack) ListPublicIPs() ([]*abstract.PublicIP, fail.Error) {
+	var emptySlice []*abstract.PublicIP
+	if s.IsNull() {
+		return emptySlice, fail.InvalidInstanceError()
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("stacks.network") || tracing.ShouldTrace("stack.gcp")).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	list, xerr := s.rpcListExternalAddresses()
+	if xerr != nil {
+		return emptySlice, xerr
+	}
+
+	out := make([]*abstract.PublicIP, 0, len(list))
+	for _, v := range list {
+		pip, xerr := s.toAbstractPublicIP(v)
+		if xerr != nil {
+			return emptySlice, xerr
+		}
+		out = append(out, pip)
+	}
+	return out, nil
+}
+
+// DeletePublicIP releases the external address identified by id
+func (s stack) DeletePublicIP(id string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("stacks.network") || tracing.ShouldTrace("stack.gcp"), "('%s')", id).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	return s.rpcDeleteExternalAddress(id, false)
+}
+
+// BindPublicIPToHost associates the external address to a host
+// The access config of the first network interface of the instance is replaced by one using the external address
+func (s stack) BindPublicIPToHost(pip *abstract.PublicIP, hostParam stacks.HostParameter) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if pip == nil {
+		return fail.InvalidParameterCannotBeNilError("pip")
+	}
+	ahf, hostLabel, xerr := stacks.ValidateHostParameter(hostParam)
+	if xerr != nil {
+		return xerr
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("stacks.network") || tracing.ShouldTrace("stack.gcp"), "(%s, %s)", pip.ID, hostLabel).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	ref := ahf.Core.ID
+	if ref == "" {
+		ref = ahf.Core.Name
+	}
+	instance, xerr := s.rpcGetInstance(ref)
+	if xerr != nil {
+		return xerr
+	}
+	if len(instance.NetworkInterfaces) == 0 {
+		return fail.InconsistentError("host %s has no network interface", hostLabel)
+	}
+
+	nic := instance.NetworkInterfaces[0]
+	for _, v := range nic.AccessConfigs {
+		if xerr = s.rpcDeleteAccessConfig(instance.Name, nic.Name, v.Name); xerr != nil {
+			return fail.Wrap(xerr, "failed to remove current access config of host %s", hostLabel)
+		}
+	}
+	return s.rpcAddAccessConfig(instance.Name, nic.Name, pip.IPAddress)
+}
+
+// UnbindPublicIPFromHost dissociates the external address from a host
+func (s stack) UnbindPublicIPFromHost(pip *abstract.PublicIP, hostParam stacks.HostParameter) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if pip == nil {
+		return fail.InvalidParameterCannotBeNilError("pip")
+	}
+	ahf, hostLabel, xerr := stacks.ValidateHostParameter(hostParam)
+	if xerr != nil {
+		return xerr
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("stacks.network") || tracing.ShouldTrace("stack.gcp"), "(%s, %s)", pip.ID, hostLabel).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	ref := ahf.Core.ID
+	if ref == "" {
+		ref = ahf.Core.Name
+	}
+	instance, xerr := s.rpcGetInstance(ref)
+	if xerr != nil {
+		return xerr
+	}
+
+	for _, nic := range instance.NetworkInterfaces {
+		for _, v := range nic.AccessConfigs {
+			if v.NatIP == pip.IPAddress {
+				return s.rpcDeleteAccessConfig(instance.Name, nic.Name, v.Name)
+			}
+		}
+	}
+	return fail.NotFoundError("external address '%s' is not bound to host %s", pip.ID, hostLabel)
+}
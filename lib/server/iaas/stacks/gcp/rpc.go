@@ -979,7 +979,8 @@ func (s stack) rpcGetImageByID(id string) (*compute.Image, fail.Error) {
 		resp *compute.ImageList
 	)
 	filter := `id eq "` + id + `"`
-	for _, f := range imageFamilies {
+	// custom images are stored in the project itself
+	for _, f := range append([]string{s.GcpConfig.ProjectID}, imageFamilies...) {
 		for token := ""; ; {
 			xerr := stacks.RetryableRemoteCall(
 				func() (err error) {
@@ -1138,7 +1139,7 @@ func (s stack) rpcListInstances() ([]*compute.Instance, fail.Error) {
 	return out, nil
 }
 
-func (s stack) rpcCreateInstance(name, networkName, subnetID, subnetName, templateName, imageURL string, diskSize int64, userdata string, hasPublicIP bool, sgs map[string]struct{}) (_ *compute.Instance, ferr fail.Error) {
+func (s stack) rpcCreateInstance(name, networkName, subnetID, subnetName, templateName, imageURL string, diskSize int64, userdata string, hasPublicIP bool, sgs map[string]struct{}, labels map[string]string) (_ *compute.Instance, ferr fail.Error) {
 	var xerr fail.Error
 	var tags []string
 	for k := range sgs {
@@ -1164,6 +1165,7 @@ func (s stack) rpcCreateInstance(name, networkName, subnetID, subnetName, templa
 		Description:  name,
 		MachineType:  s.selfLinkPrefix + "/zones/" + s.GcpConfig.Zone + "/machineTypes/" + templateName,
 		CanIpForward: hasPublicIP,
+		Labels:       fromAbstractLabels(labels),
 		Tags: &compute.Tags{
 			Items: tags,
 		},
@@ -1588,6 +1590,137 @@ func (s stack) rpcGetExternalAddress(name string, global bool) (_ *compute.Addre
 	return resp, nil
 }
 
+func (s stack) rpcListExternalAddresses() ([]*compute.Address, fail.Error) {
+	var (
+		out  []*compute.Address
+		resp *compute.AddressList
+	)
+	for token := ""; ; {
+		zero := []*compute.Address{}
+		xerr := stacks.RetryableRemoteCall(
+			func() (err error) {
+				resp, err = s.ComputeService.Addresses.List(s.GcpConfig.ProjectID, s.GcpConfig.Region).PageToken(token).Do()
+				if err != nil {
+					return err
+				}
+				if resp != nil {
+					if resp.HTTPStatusCode != 200 {
+						logrus.Tracef("received http error code %d", resp.HTTPStatusCode)
+					}
+				}
+				return err
+			},
+			normalizeError,
+		)
+		if xerr != nil {
+			switch xerr.(type) {
+			case *retry.ErrStopRetry: // On StopRetry, the real error is the cause
+				return zero, fail.Wrap(fail.Cause(xerr), "stopping retries")
+			case *retry.ErrTimeout: // On timeout, we keep the last error as cause
+				return zero, fail.Wrap(fail.Cause(xerr), "timeout")
+			default:
+				return zero, xerr
+			}
+		}
+		if len(resp.Items) > 0 {
+			out = append(out, resp.Items...)
+		}
+		if token = resp.NextPageToken; token == "" {
+			break
+		}
+	}
+	return out, nil
+}
+
+// rpcAddAccessConfig adds a ONE_TO_ONE_NAT access config using the external address 'natIP' to the network interface 'nicName' of instance 'instanceName'
+func (s stack) rpcAddAccessConfig(instanceName, nicName, natIP string) fail.Error {
+	if instanceName == "" {
+		return fail.InvalidParameterError("instanceName", "cannot be empty string")
+	}
+	if nicName == "" {
+		return fail.InvalidParameterError("nicName", "cannot be empty string")
+	}
+	if natIP == "" {
+		return fail.InvalidParameterError("natIP", "cannot be empty string")
+	}
+
+	request := compute.AccessConfig{
+		Type:  "ONE_TO_ONE_NAT",
+		Name:  "External NAT",
+		NatIP: natIP,
+	}
+	var op *compute.Operation
+	xerr := stacks.RetryableRemoteCall(
+		func() (err error) {
+			op, err = s.ComputeService.Instances.AddAccessConfig(s.GcpConfig.ProjectID, s.GcpConfig.Zone, instanceName, nicName, &request).Do()
+			if err != nil {
+				return err
+			}
+			if op != nil {
+				if op.HTTPStatusCode != 200 {
+					logrus.Tracef("received http error code %d", op.HTTPStatusCode)
+				}
+			}
+			return err
+		},
+		normalizeError,
+	)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *retry.ErrStopRetry: // On StopRetry, the real error is the cause
+			return fail.Wrap(fail.Cause(xerr), "stopping retries")
+		case *retry.ErrTimeout: // On timeout, we keep the last error as cause
+			return fail.Wrap(fail.Cause(xerr), "timeout")
+		default:
+			return xerr
+		}
+	}
+
+	return s.rpcWaitUntilOperationIsSuccessfulOrTimeout(op, temporal.GetMinDelay(), temporal.GetHostTimeout())
+}
+
+// rpcDeleteAccessConfig removes the access config 'accessConfigName' from the network interface 'nicName' of instance 'instanceName'
+func (s stack) rpcDeleteAccessConfig(instanceName, nicName, accessConfigName string) fail.Error {
+	if instanceName == "" {
+		return fail.InvalidParameterError("instanceName", "cannot be empty string")
+	}
+	if nicName == "" {
+		return fail.InvalidParameterError("nicName", "cannot be empty string")
+	}
+	if accessConfigName == "" {
+		return fail.InvalidParameterError("accessConfigName", "cannot be empty string")
+	}
+
+	var op *compute.Operation
+	xerr := stacks.RetryableRemoteCall(
+		func() (err error) {
+			op, err = s.ComputeService.Instances.DeleteAccessConfig(s.GcpConfig.ProjectID, s.GcpConfig.Zone, instanceName, accessConfigName, nicName).Do()
+			if err != nil {
+				return err
+			}
+			if op != nil {
+				if op.HTTPStatusCode != 200 {
+					logrus.Tracef("received http error code %d", op.HTTPStatusCode)
+				}
+			}
+			return err
+		},
+		normalizeError,
+	)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *retry.ErrStopRetry: // On StopRetry, the real error is the cause
+			return fail.Wrap(fail.Cause(xerr), "stopping retries")
+		case *retry.ErrTimeout: // On timeout, we keep the last error as cause
+			return fail.Wrap(fail.Cause(xerr), "timeout")
+		default:
+			return xerr
+		}
+	}
+
+	return s.rpcWaitUntilOperationIsSuccessfulOrTimeout(op, temporal.GetMinDelay(), temporal.GetHostTimeout())
+}
+
 func (s stack) rpcDeleteExternalAddress(name string, global bool) fail.Error {
 	if global {
 		xerr := stacks.RetryableRemoteCall(
@@ -2024,13 +2157,14 @@ func (s stack) rpcDeleteNetworkByID(id string) (xerr fail.Error) {
 	return s.rpcWaitUntilOperationIsSuccessfulOrTimeout(resp, temporal.GetMinDelay(), 2*temporal.GetContextTimeout())
 }
 
-func (s stack) rpcCreateDisk(name, kind string, size int64) (*compute.Disk, fail.Error) {
+func (s stack) rpcCreateDisk(name, kind string, size int64, labels map[string]string) (*compute.Disk, fail.Error) {
 	request := compute.Disk{
 		Name:   name,
 		Region: s.GcpConfig.Region,
 		SizeGb: size,
 		Type:   kind,
 		Zone:   s.GcpConfig.Zone,
+		Labels: fromAbstractLabels(labels),
 	}
 	var op *compute.Operation
 	zero := &compute.Disk{}
@@ -2139,6 +2273,44 @@ func (s stack) rpcDeleteDisk(ref string) fail.Error {
 	return s.rpcWaitUntilOperationIsSuccessfulOrTimeout(op, temporal.GetMinDelay(), temporal.GetHostTimeout())
 }
 
+func (s stack) rpcResizeDisk(ref string, size int64) fail.Error {
+	if ref == "" {
+		return fail.InvalidParameterError("ref", "cannot be empty string")
+	}
+
+	request := compute.DisksResizeRequest{
+		SizeGb: size,
+	}
+	var op *compute.Operation
+	xerr := stacks.RetryableRemoteCall(
+		func() (err error) {
+			op, err = s.ComputeService.Disks.Resize(s.GcpConfig.ProjectID, s.GcpConfig.Zone, ref, &request).Do()
+			if err != nil {
+				return err
+			}
+			if op != nil {
+				if op.HTTPStatusCode != 200 {
+					logrus.Tracef("received http error code %d", op.HTTPStatusCode)
+				}
+			}
+			return err
+		},
+		normalizeError,
+	)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *retry.ErrStopRetry: // On StopRetry, the real error is the cause
+			return fail.Wrap(fail.Cause(xerr), "stopping retries")
+		case *retry.ErrTimeout: // On timeout, we keep the last error as cause
+			return fail.Wrap(fail.Cause(xerr), "timeout")
+		default:
+			return xerr
+		}
+	}
+
+	return s.rpcWaitUntilOperationIsSuccessfulOrTimeout(op, temporal.GetMinDelay(), temporal.GetOperationTimeout())
+}
+
 func (s stack) rpcCreateDiskAttachment(diskRef, hostRef string) (string, fail.Error) {
 	if diskRef == "" {
 		return "", fail.InvalidParameterError("diskRef", "cannot be empty string")
@@ -2233,3 +2405,319 @@ func (s stack) rpcDeleteDiskAttachment(vaID string) fail.Error {
 
 	return s.rpcWaitUntilOperationIsSuccessfulOrTimeout(op, temporal.GetMinDelay(), temporal.GetHostTimeout())
 }
+
+func (s stack) rpcCreateDiskFromSnapshot(name, kind string, size int64, snapshotLink string) (*compute.Disk, fail.Error) {
+	if snapshotLink == "" {
+		return &compute.Disk{}, fail.InvalidParameterError("snapshotLink", "cannot be empty string")
+	}
+
+	request := compute.Disk{
+		Name:           name,
+		Region:         s.GcpConfig.Region,
+		SizeGb:         size,
+		Type:           kind,
+		Zone:           s.GcpConfig.Zone,
+		SourceSnapshot: snapshotLink,
+	}
+	var op *compute.Operation
+	zero := &compute.Disk{}
+	xerr := stacks.RetryableRemoteCall(
+		func() (err error) {
+			op, err = s.ComputeService.Disks.Insert(s.GcpConfig.ProjectID, s.GcpConfig.Zone, &request).Do()
+			if err != nil {
+				return err
+			}
+			if op != nil {
+				if op.HTTPStatusCode != 200 {
+					logrus.Tracef("received http error code %d", op.HTTPStatusCode)
+				}
+			}
+			return err
+		},
+		normalizeError,
+	)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *retry.ErrStopRetry: // On StopRetry, the real error is the cause
+			return zero, fail.Wrap(fail.Cause(xerr), "stopping retries")
+		case *retry.ErrTimeout: // On timeout, we keep the last error as cause
+			return zero, fail.Wrap(fail.Cause(xerr), "timeout")
+		default:
+			return zero, xerr
+		}
+	}
+
+	if xerr = s.rpcWaitUntilOperationIsSuccessfulOrTimeout(op, temporal.GetMinDelay(), temporal.GetHostTimeout()); xerr != nil {
+		return &compute.Disk{}, xerr
+	}
+
+	return s.rpcGetDisk(name)
+}
+
+func (s stack) rpcCreateSnapshot(diskRef, name, description string) (*compute.Snapshot, fail.Error) {
+	if diskRef == "" {
+		return &compute.Snapshot{}, fail.InvalidParameterError("diskRef", "cannot be empty string")
+	}
+	if name == "" {
+		return &compute.Snapshot{}, fail.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	request := compute.Snapshot{
+		Name:        name,
+		Description: description,
+	}
+	var op *compute.Operation
+	zero := &compute.Snapshot{}
+	xerr := stacks.RetryableRemoteCall(
+		func() (err error) {
+			op, err = s.ComputeService.Disks.CreateSnapshot(s.GcpConfig.ProjectID, s.GcpConfig.Zone, diskRef, &request).Do()
+			if err != nil {
+				return err
+			}
+			if op != nil {
+				if op.HTTPStatusCode != 200 {
+					logrus.Tracef("received http error code %d", op.HTTPStatusCode)
+				}
+			}
+			return err
+		},
+		normalizeError,
+	)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *retry.ErrStopRetry: // On StopRetry, the real error is the cause
+			return zero, fail.Wrap(fail.Cause(xerr), "stopping retries")
+		case *retry.ErrTimeout: // On timeout, we keep the last error as cause
+			return zero, fail.Wrap(fail.Cause(xerr), "timeout")
+		default:
+			return zero, xerr
+		}
+	}
+
+	if xerr = s.rpcWaitUntilOperationIsSuccessfulOrTimeout(op, temporal.GetMinDelay(), temporal.GetLongOperationTimeout()); xerr != nil {
+		return &compute.Snapshot{}, xerr
+	}
+
+	return s.rpcGetSnapshot(name)
+}
+
+func (s stack) rpcGetSnapshot(ref string) (*compute.Snapshot, fail.Error) {
+	if ref == "" {
+		return &compute.Snapshot{}, fail.InvalidParameterError("ref", "cannot be empty string")
+	}
+
+	var resp *compute.Snapshot
+	zero := &compute.Snapshot{}
+	xerr := stacks.RetryableRemoteCall(
+		func() (err error) {
+			resp, err = s.ComputeService.Snapshots.Get(s.GcpConfig.ProjectID, ref).Do()
+			if err != nil {
+				return err
+			}
+			if resp != nil {
+				if resp.HTTPStatusCode != 200 {
+					logrus.Tracef("received http error code %d", resp.HTTPStatusCode)
+				}
+			}
+			return err
+		},
+		normalizeError,
+	)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *retry.ErrStopRetry: // On StopRetry, the real error is the cause
+			return zero, fail.Wrap(fail.Cause(xerr), "stopping retries")
+		case *retry.ErrTimeout: // On timeout, we keep the last error as cause
+			return zero, fail.Wrap(fail.Cause(xerr), "timeout")
+		default:
+			return zero, xerr
+		}
+	}
+	if resp == nil {
+		return &compute.Snapshot{}, fail.NotFoundError("failed to find Snapshot '%s'", ref)
+	}
+	return resp, nil
+}
+
+func (s stack) rpcListSnapshots() ([]*compute.Snapshot, fail.Error) {
+	var (
+		out  []*compute.Snapshot
+		resp *compute.SnapshotList
+	)
+	for token := ""; ; {
+		zero := []*compute.Snapshot{}
+		xerr := stacks.RetryableRemoteCall(
+			func() (err error) {
+				resp, err = s.ComputeService.Snapshots.List(s.GcpConfig.ProjectID).PageToken(token).Do()
+				if err != nil {
+					return err
+				}
+				if resp != nil {
+					if resp.HTTPStatusCode != 200 {
+						logrus.Tracef("received http error code %d", resp.HTTPStatusCode)
+					}
+				}
+				return err
+			},
+			normalizeError,
+		)
+		if xerr != nil {
+			switch xerr.(type) {
+			case *retry.ErrStopRetry: // On StopRetry, the real error is the cause
+				return zero, fail.Wrap(fail.Cause(xerr), "stopping retries")
+			case *retry.ErrTimeout: // On timeout, we keep the last error as cause
+				return zero, fail.Wrap(fail.Cause(xerr), "timeout")
+			default:
+				return zero, xerr
+			}
+		}
+		if resp != nil && len(resp.Items) > 0 {
+			out = append(out, resp.Items...)
+		}
+		if token = resp.NextPageToken; token == "" {
+			break
+		}
+	}
+	return out, nil
+}
+
+func (s stack) rpcDeleteSnapshot(ref string) fail.Error {
+	if ref == "" {
+		return fail.InvalidParameterError("ref", "cannot be empty string")
+	}
+
+	var op *compute.Operation
+	xerr := stacks.RetryableRemoteCall(
+		func() (err error) {
+			op, err = s.ComputeService.Snapshots.Delete(s.GcpConfig.ProjectID, ref).Do()
+			if err != nil {
+				return err
+			}
+			if op != nil {
+				if op.HTTPStatusCode != 200 {
+					logrus.Tracef("received http error code %d", op.HTTPStatusCode)
+				}
+			}
+			return err
+		},
+		normalizeError,
+	)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *retry.ErrStopRetry: // On StopRetry, the real error is the cause
+			return fail.Wrap(fail.Cause(xerr), "stopping retries")
+		case *retry.ErrTimeout: // On timeout, we keep the last error as cause
+			return fail.Wrap(fail.Cause(xerr), "timeout")
+		default:
+			return xerr
+		}
+	}
+
+	return s.rpcWaitUntilOperationIsSuccessfulOrTimeout(op, temporal.GetMinDelay(), temporal.GetHostTimeout())
+}
+
+// rpcCreateImage creates an image from the disk sourceDisk; the disk may be in use by a running instance
+func (s stack) rpcCreateImage(name, description, sourceDisk string) (*compute.Image, fail.Error) {
+	if name == "" {
+		return &compute.Image{}, fail.InvalidParameterError("name", "cannot be empty string")
+	}
+	if sourceDisk == "" {
+		return &compute.Image{}, fail.InvalidParameterError("sourceDisk", "cannot be empty string")
+	}
+
+	request := compute.Image{
+		Name:        name,
+		Description: description,
+		SourceDisk:  sourceDisk,
+	}
+	var op *compute.Operation
+	zero := &compute.Image{}
+	xerr := stacks.RetryableRemoteCall(
+		func() (err error) {
+			op, err = s.ComputeService.Images.Insert(s.GcpConfig.ProjectID, &request).ForceCreate(true).Do()
+			if err != nil {
+				return err
+			}
+			if op != nil {
+				if op.HTTPStatusCode != 200 {
+					logrus.Tracef("received http error code %d", op.HTTPStatusCode)
+				}
+			}
+			return err
+		},
+		normalizeError,
+	)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *retry.ErrStopRetry: // On StopRetry, the real error is the cause
+			return zero, fail.Wrap(fail.Cause(xerr), "stopping retries")
+		case *retry.ErrTimeout: // On timeout, we keep the last error as cause
+			return zero, fail.Wrap(fail.Cause(xerr), "timeout")
+		default:
+			return zero, xerr
+		}
+	}
+
+	if xerr = s.rpcWaitUntilOperationIsSuccessfulOrTimeout(op, temporal.GetMinDelay(), temporal.GetLongOperationTimeout()); xerr != nil {
+		return zero, xerr
+	}
+
+	return s.rpcGetImage(name)
+}
+
+// rpcGetImage returns the image named name stored in the project
+func (s stack) rpcGetImage(name string) (*compute.Image, fail.Error) {
+	if name == "" {
+		return &compute.Image{}, fail.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	var resp *compute.Image
+	zero := &compute.Image{}
+	xerr := stacks.RetryableRemoteCall(
+		func() (err error) {
+			resp, err = s.ComputeService.Images.Get(s.GcpConfig.ProjectID, name).Do()
+			if err != nil {
+				return err
+			}
+			if resp != nil {
+				if resp.HTTPStatusCode != 200 {
+					logrus.Tracef("received http error code %d", resp.HTTPStatusCode)
+				}
+			}
+			return err
+		},
+		normalizeError,
+	)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *retry.ErrStopRetry: // On StopRetry, the real error is the cause
+			return zero, fail.Wrap(fail.Cause(xerr), "stopping retries")
+		case *retry.ErrTimeout: // On timeout, we keep the last error as cause
+			return zero, fail.Wrap(fail.Cause(xerr), "timeout")
+		default:
+			return zero, xerr
+		}
+	}
+	return resp, nil
+}
+
+// rpcDeleteImage deletes the image named name stored in the project
+func (s stack) rpcDeleteImage(name string) fail.Error {
+	if name == "" {
+		return fail.InvalidParameterError("name", "cannot be empty string")
+	}
+
+	var op *compute.Operation
+	xerr := stacks.RetryableRemoteCall(
+		func() (err error) {
+			op, err = s.ComputeService.Images.Delete(s.GcpConfig.ProjectID, name).Do()
+			return err
+		},
+		normalizeError,
+	)
+	if xerr != nil {
+		return xerr
+	}
+
+	return s.rpcWaitUntilOperationIsSuccessfulOrTimeout(op, temporal.GetMinDelay(), temporal.GetLongOperationTimeout())
+}
@@ -0,0 +1,217 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gcp
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"google.golang.org/api/compute/v1"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumespeed"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumestate"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// -------------Volume Snapshots Management------------------------------------------------------------------------------
+
+// CreateVolumeSnapshot takes a snapshot of a persistent disk
+func (s stack) CreateVolumeSnapshot(request abstract.SnapshotRequest) (_ *abstract.Snapshot, xerr fail.Error) {
+	nullAS := abstract.NewSnapshot()
+	if s.IsNull() {
+		return nullAS, fail.InvalidInstanceError()
+	}
+	if request.Name == "" {
+		return nullAS, fail.InvalidParameterCannotBeEmptyStringError("request.Name")
+	}
+	if request.VolumeID == "" {
+		return nullAS, fail.InvalidParameterCannotBeEmptyStringError("request.VolumeID")
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("stacks.volume") || tracing.ShouldTrace("stack.gcp"), "(%s, %s)", request.VolumeID, request.Name).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	resp, xerr := s.rpcCreateSnapshot(request.VolumeID, request.Name, request.Description)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			return nullAS, abstract.ResourceNotFoundError("volume", request.VolumeID)
+		default:
+			return nullAS, xerr
+		}
+	}
+
+	return toAbstractSnapshot(*resp), nil
+}
+
+// InspectVolumeSnapshot returns the snapshot identified by ref (name or id)
+func (s stack) InspectVolumeSnapshot(ref string) (_ *abstract.Snapshot, xerr fail.Error) {
+	nullAS := abstract.NewSnapshot()
+	if s.IsNull() {
+		return nullAS, fail.InvalidInstanceError()
+	}
+	if ref == "" {
+		return nullAS, fail.InvalidParameterCannotBeEmptyStringError("ref")
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("stacks.volume") || tracing.ShouldTrace("stack.gcp"), "(%s)", ref).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	resp, xerr := s.rpcGetSnapshot(ref)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			return nullAS, abstract.ResourceNotFoundError("snapshot", ref)
+		default:
+			return nullAS, xerr
+		}
+	}
+
+	return toAbstractSnapshot(*resp), nil
+}
+
+// ListVolumeSnapshots lists the snapshots of the volume identified by volumeID (all the snapshots of the project if volumeID is empty)
+func (s stack) ListVolumeSnapshots(volumeID string) ([]*abstract.Snapshot, fail.Error) {
+	var emptySlice []*abstract.Snapshot
+	if s.IsNull() {
+		return emptySlice, fail.InvalidInstanceError()
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("stacks.volume") || tracing.ShouldTrace("stack.gcp"), "(%s)", volumeID).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	resp, xerr := s.rpcListSnapshots()
+	if xerr != nil {
+		return emptySlice, xerr
+	}
+
+	out := make([]*abstract.Snapshot, 0, len(resp))
+	for _, v := range resp {
+		item := toAbstractSnapshot(*v)
+		if volumeID == "" || item.VolumeID == volumeID {
+			out = append(out, item)
+		}
+	}
+	return out, nil
+}
+
+// DeleteVolumeSnapshot deletes the snapshot identified by ref (name or id)
+func (s stack) DeleteVolumeSnapshot(ref string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if ref == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("ref")
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("stacks.volume") || tracing.ShouldTrace("stack.gcp"), "(%s)", ref).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	xerr := s.rpcDeleteSnapshot(ref)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			return abstract.ResourceNotFoundError("snapshot", ref)
+		default:
+			return xerr
+		}
+	}
+	return nil
+}
+
+// CreateVolumeFromSnapshot creates a new persistent disk filled with the content of the snapshot identified by snapshotID
+// If request.Size is 0, the size of the snapshot is used
+func (s stack) CreateVolumeFromSnapshot(snapshotID string, request abstract.VolumeRequest) (_ *abstract.Volume, xerr fail.Error) {
+	nullAV := abstract.NewVolume()
+	if s.IsNull() {
+		return nullAV, fail.InvalidInstanceError()
+	}
+	if snapshotID == "" {
+		return nullAV, fail.InvalidParameterCannotBeEmptyStringError("snapshotID")
+	}
+	if request.Name == "" {
+		return nullAV, fail.InvalidParameterCannotBeEmptyStringError("request.Name")
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("stacks.volume") || tracing.ShouldTrace("stack.gcp"), "(%s, %s)", snapshotID, request.Name).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	snap, xerr := s.rpcGetSnapshot(snapshotID)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			return nullAV, abstract.ResourceNotFoundError("snapshot", snapshotID)
+		default:
+			return nullAV, xerr
+		}
+	}
+	if request.Size == 0 {
+		request.Size = int(snap.DiskSizeGb)
+	}
+	if int64(request.Size) < snap.DiskSizeGb {
+		return nullAV, fail.InvalidRequestError("cannot restore snapshot '%s' of %d GB in a volume of %d GB", snap.Name, snap.DiskSizeGb, request.Size)
+	}
+
+	selectedType := fmt.Sprintf("projects/%s/zones/%s/diskTypes/pd-standard", s.GcpConfig.ProjectID, s.GcpConfig.Zone)
+	if request.Speed == volumespeed.Ssd {
+		selectedType = fmt.Sprintf("projects/%s/zones/%s/diskTypes/pd-ssd", s.GcpConfig.ProjectID, s.GcpConfig.Zone)
+	}
+
+	resp, xerr := s.rpcCreateDiskFromSnapshot(request.Name, selectedType, int64(request.Size), snap.SelfLink)
+	if xerr != nil {
+		return nullAV, xerr
+	}
+
+	out, xerr := toAbstractVolume(*resp)
+	if xerr != nil {
+		return nullAV, xerr
+	}
+	return out, nil
+}
+
+func toAbstractSnapshot(in compute.Snapshot) *abstract.Snapshot {
+	out := abstract.NewSnapshot()
+	out.ID = strconv.FormatUint(in.Id, 10)
+	out.Name = in.Name
+	out.Description = in.Description
+	out.VolumeID = in.SourceDiskId
+	out.Size = int(in.DiskSizeGb)
+	out.State = toAbstractSnapshotState(in.Status)
+	if created, err := time.Parse(time.RFC3339, in.CreationTimestamp); err == nil {
+		out.CreatedAt = created
+	}
+	return out
+}
+
+func toAbstractSnapshotState(in string) volumestate.Enum {
+	switch in {
+	case "CREATING", "UPLOADING":
+		return volumestate.Creating
+	case "DELETING":
+		return volumestate.Deleting
+	case "FAILED":
+		return volumestate.Error
+	case "READY":
+		return volumestate.Available
+	default:
+		return volumestate.Unknown
+	}
+}
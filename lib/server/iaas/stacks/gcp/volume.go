@@ -61,7 +61,7 @@ func (s stack) CreateVolume(request abstract.VolumeRequest) (_ *abstract.Volume,
 		selectedType = fmt.Sprintf("projects/%s/zones/%s/diskTypes/pd-ssd", s.GcpConfig.ProjectID, s.GcpConfig.Zone)
 	}
 
-	resp, xerr := s.rpcCreateDisk(request.Name, selectedType, int64(request.Size))
+	resp, xerr := s.rpcCreateDisk(request.Name, selectedType, int64(request.Size), request.Labels)
 	if xerr != nil {
 		return nullAV, xerr
 	}
@@ -83,6 +83,7 @@ func toAbstractVolume(in compute.Disk) (out *abstract.Volume, xerr fail.Error) {
 	}
 	out.Size = int(in.SizeGb)
 	out.ID = strconv.FormatUint(in.Id, 10)
+	out.Tags = in.Labels
 	if out.State, xerr = toAbstractVolumeState(in.Status); xerr != nil {
 		return abstract.NewVolume(), xerr
 	}
@@ -197,6 +198,35 @@ func (s stack) DeleteVolume(ref string) fail.Error {
 	return s.rpcDeleteDisk(ref)
 }
 
+// ResizeVolume grows the volume identified by ref to newSize GB
+// GCP supports the resize of attached disks
+func (s stack) ResizeVolume(ref string, newSize int) (_ *abstract.Volume, xerr fail.Error) {
+	nullAV := abstract.NewVolume()
+	if s.IsNull() {
+		return nullAV, fail.InvalidInstanceError()
+	}
+	if ref == "" {
+		return nullAV, fail.InvalidParameterCannotBeEmptyStringError("ref")
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("stacks.volume") || tracing.ShouldTrace("stack.gcp"), "(%s, %d)", ref, newSize).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	av, xerr := s.InspectVolume(ref)
+	if xerr != nil {
+		return nullAV, xerr
+	}
+	if newSize <= av.Size {
+		return nullAV, fail.InvalidParameterError("newSize", "must be greater than the current size of the volume (%d GB)", av.Size)
+	}
+
+	if xerr = s.rpcResizeDisk(ref, int64(newSize)); xerr != nil {
+		return nullAV, fail.Wrap(xerr, "failed to resize volume '%s'", av.Name)
+	}
+
+	return s.InspectVolume(ref)
+}
+
 // CreateVolumeAttachment attaches a volume to an host
 func (s stack) CreateVolumeAttachment(request abstract.VolumeAttachmentRequest) (string, fail.Error) {
 	if s.IsNull() {
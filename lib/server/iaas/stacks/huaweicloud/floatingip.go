@@ -26,6 +26,9 @@ import (
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
 )
 
+// defaultFloatingIPType is the type of floating IP used when none is requested
+const defaultFloatingIPType = "5_bgp"
+
 // ListOpts to define parameter of list
 type ListOpts struct {
 	Marker string `json:"marker,omitempty"`
@@ -216,8 +219,13 @@ func (s stack) CreateFloatingIP(host *abstract.HostFull) (*FloatingIP, fail.Erro
 		return &FloatingIP{}, fail.InvalidParameterCannotBeNilError("host")
 	}
 
+	return s.createFloatingIP(defaultFloatingIPType, "bandwidth-"+host.Networking.SubnetsByID[host.Networking.DefaultSubnetID])
+}
+
+// createFloatingIP creates a floating IP of type 'ipType' with a dedicated bandwidth named 'bandwidthName'
+func (s stack) createFloatingIP(ipType, bandwidthName string) (*FloatingIP, fail.Error) {
 	ipOpts := ipCreateOpts{
-		Type: "5_bgp",
+		Type: ipType,
 	}
 	bi, err := ipOpts.toFloatingIPCreateMap()
 	if err != nil {
@@ -225,7 +233,7 @@ func (s stack) CreateFloatingIP(host *abstract.HostFull) (*FloatingIP, fail.Erro
 	}
 
 	bandwidthOpts := bandwidthCreateOpts{
-		Name:      "bandwidth-" + host.Networking.SubnetsByID[host.Networking.DefaultSubnetID],
+		Name:      bandwidthName,
 		Size:      1000,
 		ShareType: "PER",
 	}
@@ -0,0 +1,168 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package huaweicloud
+
+import (
+	"github.com/gophercloud/gophercloud/pagination"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// toAbstractPublicIP converts a FloatingIP to *abstract.PublicIP
+func toAbstractPublicIP(fip FloatingIP) *abstract.PublicIP {
+	pip := abstract.NewPublicIP()
+	pip.ID = fip.ID
+	pip.Type = fip.Type
+	pip.IPAddress = fip.PublicIPAddress
+	return pip
+}
+
+// CreatePublicIP allocates a floating IP not bound to any host
+// request.Type, if set, is the type of floating IP to allocate (default: "5_bgp")
+func (s stack) CreatePublicIP(request abstract.PublicIPRequest) (*abstract.PublicIP, fail.Error) {
+	nullPIP := abstract.NewPublicIP()
+	if s.IsNull() {
+		return nullPIP, fail.InvalidInstanceError()
+	}
+	if request.Name == "" {
+		return nullPIP, fail.InvalidParameterCannotBeEmptyStringError("request.Name")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.network"), "(%s)", request.Name).WithStopwatch().Entering().Exiting()
+
+	ipType := request.Type
+	if ipType == "" {
+		ipType = defaultFloatingIPType
+	}
+	fip, xerr := s.createFloatingIP(ipType, "bandwidth-"+request.Name)
+	if xerr != nil {
+		return nullPIP, xerr
+	}
+
+	pip := toAbstractPublicIP(*fip)
+	pip.Name = request.Name
+	pip.Description = request.Description
+	return pip, nil
+}
+
+// InspectPublicIP returns the floating IP identified by id
+func (s stack) InspectPublicIP(id string) (*abstract.PublicIP, fail.Error) {
+	nullPIP := abstract.NewPublicIP()
+	if s.IsNull() {
+		return nullPIP, fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return nullPIP, fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	fip, xerr := s.GetFloatingIP(id)
+	if xerr != nil {
+		return nullPIP, xerr
+	}
+	return toAbstractPublicIP(*fip), nil
+}
+
+// ListPublicIPs lists the floating IPs of the VPC
+func (s stack) ListPublicIPs() ([]*abstract.PublicIP, fail.Error) {
+	var emptySlice []*abstract.PublicIP
+	if s.IsNull() {
+		return emptySlice, fail.InvalidInstanceError()
+	}
+
+	var out []*abstract.PublicIP
+	xerr := stacks.RetryableRemoteCall(
+		func() error {
+			out = []*abstract.PublicIP{}
+			innerErr := s.ListFloatingIPs().EachPage(func(page pagination.Page) (bool, error) {
+				list, err := extractFloatingIPs(page)
+				if err != nil {
+					return false, err
+				}
+				for _, v := range list {
+					out = append(out, toAbstractPublicIP(v))
+				}
+				return true, nil
+			})
+			return normalizeError(innerErr)
+		},
+		normalizeError,
+	)
+	if xerr != nil {
+		return emptySlice, xerr
+	}
+	return out, nil
+}
+
+// DeletePublicIP releases the floating IP identified by id
+func (s stack) DeletePublicIP(id string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.network"), "(%s)", id).WithStopwatch().Entering().Exiting()
+
+	return s.DeleteFloatingIP(id)
+}
+
+// BindPublicIPToHost associates the floating IP to a host
+func (s stack) BindPublicIPToHost(pip *abstract.PublicIP, hostParam stacks.HostParameter) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if pip == nil {
+		return fail.InvalidParameterCannotBeNilError("pip")
+	}
+	ahf, hostLabel, xerr := stacks.ValidateHostParameter(hostParam)
+	if xerr != nil {
+		return xerr
+	}
+	if ahf.Core.ID == "" {
+		return fail.InvalidParameterError("hostParam", "must contain the ID of the host")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.network"), "(%s, %s)", pip.ID, hostLabel).WithStopwatch().Entering().Exiting()
+
+	return s.AssociateFloatingIP(ahf.Core, pip.ID)
+}
+
+// UnbindPublicIPFromHost dissociates the floating IP from a host
+func (s stack) UnbindPublicIPFromHost(pip *abstract.PublicIP, hostParam stacks.HostParameter) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if pip == nil {
+		return fail.InvalidParameterCannotBeNilError("pip")
+	}
+	ahf, hostLabel, xerr := stacks.ValidateHostParameter(hostParam)
+	if xerr != nil {
+		return xerr
+	}
+	if ahf.Core.ID == "" {
+		return fail.InvalidParameterError("hostParam", "must contain the ID of the host")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.network"), "(%s, %s)", pip.ID, hostLabel).WithStopwatch().Entering().Exiting()
+
+	return s.DissociateFloatingIP(ahf.Core, pip.ID)
+}
@@ -102,6 +102,16 @@ var defaultNetworkCIDR = "192.168.122.0/24"
 
 // -------------IMAGES---------------------------------------------------------------------------------------------------
 
+// CreateImage creates a custom image from an existing host
+func (s stack) CreateImage(request abstract.ImageRequest) (*abstract.Image, fail.Error) {
+	return nil, fail.NotImplementedError("CreateImage() not implemented yet") // FIXME: Technical debt
+}
+
+// DeleteImage deletes a custom image
+func (s stack) DeleteImage(id string) fail.Error {
+	return fail.NotImplementedError("DeleteImage() not implemented yet") // FIXME: Technical debt
+}
+
 // ListImages lists available OS images
 func (s stack) ListImages() (images []abstract.Image, xerr fail.Error) {
 	if s.IsNull() {
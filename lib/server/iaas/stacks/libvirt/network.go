@@ -32,6 +32,7 @@ import (
 	libvirtxml "github.com/libvirt/libvirt-go-xml"
 	"github.com/sirupsen/logrus"
 
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
 	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/ipversion"
 	"github.com/CS-SI/SafeScale/lib/utils/debug"
@@ -397,3 +398,33 @@ func (s stack) UnbindHostFromVIP(vip *abstract.VirtualIP, host *abstract.Host) f
 func (s stack) DeleteVIP(vip *abstract.VirtualIP) fail.Error {
 	return fail.NotImplementedError("DeleteVIP() not implemented yet") // FIXME: Technical debt
 }
+
+// CreatePublicIP allocates a public IP not bound to any host
+func (s stack) CreatePublicIP(request abstract.PublicIPRequest) (*abstract.PublicIP, fail.Error) {
+	return nil, fail.NotImplementedError("CreatePublicIP() not implemented yet") // FIXME: Technical debt
+}
+
+// InspectPublicIP returns the public IP identified by id
+func (s stack) InspectPublicIP(id string) (*abstract.PublicIP, fail.Error) {
+	return nil, fail.NotImplementedError("InspectPublicIP() not implemented yet") // FIXME: Technical debt
+}
+
+// ListPublicIPs lists the public IPs allocated in the tenant
+func (s stack) ListPublicIPs() ([]*abstract.PublicIP, fail.Error) {
+	return nil, fail.NotImplementedError("ListPublicIPs() not implemented yet") // FIXME: Technical debt
+}
+
+// DeletePublicIP releases the public IP identified by id
+func (s stack) DeletePublicIP(id string) fail.Error {
+	return fail.NotImplementedError("DeletePublicIP() not implemented yet") // FIXME: Technical debt
+}
+
+// BindPublicIPToHost associates the public IP to a host
+func (s stack) BindPublicIPToHost(pip *abstract.PublicIP, hostParam stacks.HostParameter) fail.Error {
+	return fail.NotImplementedError("BindPublicIPToHost() not implemented yet") // FIXME: Technical debt
+}
+
+// UnbindPublicIPFromHost dissociates the public IP from a host
+func (s stack) UnbindPublicIPFromHost(pip *abstract.PublicIP, hostParam stacks.HostParameter) fail.Error {
+	return fail.NotImplementedError("UnbindPublicIPFromHost() not implemented yet") // FIXME: Technical debt
+}
@@ -83,6 +83,16 @@ func (s stack) InspectImage(id string) (abstract.Image, fail.Error) {
 	return abstract.Image{}, gError
 }
 
+// CreateImage stub
+func (s stack) CreateImage(request abstract.ImageRequest) (*abstract.Image, fail.Error) {
+	return abstract.NewImage(), gError
+}
+
+// DeleteImage stub
+func (s stack) DeleteImage(id string) fail.Error {
+	return gError
+}
+
 // InspectTemplate stub
 func (s stack) InspectTemplate(id string) (abstract.HostTemplate, fail.Error) {
 	return abstract.HostTemplate{}, gError
@@ -188,6 +198,36 @@ func (s stack) DeleteVIP(vip *abstract.VirtualIP) fail.Error {
 	return gError
 }
 
+// CreatePublicIP stub
+func (s stack) CreatePublicIP(request abstract.PublicIPRequest) (*abstract.PublicIP, fail.Error) {
+	return abstract.NewPublicIP(), gError
+}
+
+// InspectPublicIP stub
+func (s stack) InspectPublicIP(id string) (*abstract.PublicIP, fail.Error) {
+	return abstract.NewPublicIP(), gError
+}
+
+// ListPublicIPs stub
+func (s stack) ListPublicIPs() ([]*abstract.PublicIP, fail.Error) {
+	return []*abstract.PublicIP{}, gError
+}
+
+// DeletePublicIP stub
+func (s stack) DeletePublicIP(id string) fail.Error {
+	return gError
+}
+
+// BindPublicIPToHost stub
+func (s stack) BindPublicIPToHost(pip *abstract.PublicIP, hostParam stacks.HostParameter) fail.Error {
+	return gError
+}
+
+// UnbindPublicIPFromHost stub
+func (s stack) UnbindPublicIPFromHost(pip *abstract.PublicIP, hostParam stacks.HostParameter) fail.Error {
+	return gError
+}
+
 // CreateHost stub
 func (s stack) CreateHost(request abstract.HostRequest) (*abstract.HostFull, *userdata.Content, fail.Error) {
 	return abstract.NewHostFull(), userdata.NewContent(), gError
@@ -263,6 +303,36 @@ func (s stack) DeleteVolume(id string) fail.Error {
 	return gError
 }
 
+// CreateVolumeSnapshot stub
+func (s stack) CreateVolumeSnapshot(request abstract.SnapshotRequest) (*abstract.Snapshot, fail.Error) {
+	return abstract.NewSnapshot(), gError
+}
+
+// InspectVolumeSnapshot stub
+func (s stack) InspectVolumeSnapshot(id string) (*abstract.Snapshot, fail.Error) {
+	return abstract.NewSnapshot(), gError
+}
+
+// ListVolumeSnapshots stub
+func (s stack) ListVolumeSnapshots(volumeID string) ([]*abstract.Snapshot, fail.Error) {
+	return []*abstract.Snapshot{}, gError
+}
+
+// DeleteVolumeSnapshot stub
+func (s stack) DeleteVolumeSnapshot(id string) fail.Error {
+	return gError
+}
+
+// CreateVolumeFromSnapshot stub
+func (s stack) CreateVolumeFromSnapshot(snapshotID string, request abstract.VolumeRequest) (*abstract.Volume, fail.Error) {
+	return &abstract.Volume{}, gError
+}
+
+// ResizeVolume stub
+func (s stack) ResizeVolume(id string, newSize int) (*abstract.Volume, fail.Error) {
+	return &abstract.Volume{}, gError
+}
+
 // CreateVolumeAttachment stub
 func (s stack) CreateVolumeAttachment(request abstract.VolumeAttachmentRequest) (string, fail.Error) {
 	return "", gError
@@ -359,6 +359,36 @@ func (s stack) DeleteVolume(ref string) fail.Error {
 	return nil
 }
 
+// CreateVolumeSnapshot takes a snapshot of a volume
+func (s stack) CreateVolumeSnapshot(request abstract.SnapshotRequest) (*abstract.Snapshot, fail.Error) {
+	return nil, fail.NotImplementedError("CreateVolumeSnapshot() not implemented yet") // FIXME: Technical debt
+}
+
+// InspectVolumeSnapshot returns the volume snapshot identified by id
+func (s stack) InspectVolumeSnapshot(id string) (*abstract.Snapshot, fail.Error) {
+	return nil, fail.NotImplementedError("InspectVolumeSnapshot() not implemented yet") // FIXME: Technical debt
+}
+
+// ListVolumeSnapshots lists the snapshots of a volume
+func (s stack) ListVolumeSnapshots(volumeID string) ([]*abstract.Snapshot, fail.Error) {
+	return nil, fail.NotImplementedError("ListVolumeSnapshots() not implemented yet") // FIXME: Technical debt
+}
+
+// DeleteVolumeSnapshot deletes the volume snapshot identified by id
+func (s stack) DeleteVolumeSnapshot(id string) fail.Error {
+	return fail.NotImplementedError("DeleteVolumeSnapshot() not implemented yet") // FIXME: Technical debt
+}
+
+// CreateVolumeFromSnapshot creates a new volume from a snapshot
+func (s stack) CreateVolumeFromSnapshot(snapshotID string, request abstract.VolumeRequest) (*abstract.Volume, fail.Error) {
+	return nil, fail.NotImplementedError("CreateVolumeFromSnapshot() not implemented yet") // FIXME: Technical debt
+}
+
+// ResizeVolume grows a volume
+func (s stack) ResizeVolume(id string, newSize int) (*abstract.Volume, fail.Error) {
+	return nil, fail.NotImplementedError("ResizeVolume() not implemented yet") // FIXME: Technical debt
+}
+
 // CreateVolumeAttachment attaches a volume to an host
 // - 'name' of the volume attachment
 // - 'volume' to attach
@@ -0,0 +1,393 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
+	"github.com/CS-SI/SafeScale/lib/server/iaas/userdata"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/hoststate"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumestate"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/strprocess"
+)
+
+// CreateHost creates a host meeting the requirements specified by request
+// The userdata content is prepared as for any other stack, but is never executed
+func (s stack) CreateHost(request abstract.HostRequest) (*abstract.HostFull, *userdata.Content, fail.Error) {
+	nullAHF := abstract.NewHostFull()
+	nullUDC := userdata.NewContent()
+	if s.IsNull() {
+		return nullAHF, nullUDC, fail.InvalidInstanceError()
+	}
+	if request.ResourceName == "" {
+		return nullAHF, nullUDC, fail.InvalidParameterCannotBeEmptyStringError("request.ResourceName")
+	}
+	if len(request.Subnets) == 0 && !request.PublicIP {
+		return nullAHF, nullUDC, abstract.ResourceInvalidRequestError("host creation", "cannot create a host without public IP or without attached subnet")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.memory") || tracing.ShouldTrace("stacks.compute"), "(%s)", request.ResourceName).WithStopwatch().Entering().Exiting()
+
+	template, xerr := s.InspectTemplate(request.TemplateID)
+	if xerr != nil {
+		return nullAHF, nullUDC, fail.Wrap(xerr, "failed to get template")
+	}
+	image, xerr := s.InspectImage(request.ImageID)
+	if xerr != nil {
+		return nullAHF, nullUDC, fail.Wrap(xerr, "failed to get image")
+	}
+
+	if xerr = stacks.ProvideCredentialsIfNeeded(&request); xerr != nil {
+		return nullAHF, nullUDC, fail.Wrap(xerr, "failed to provide credentials for the host")
+	}
+
+	// Configure userdata content
+	udc := userdata.NewContent()
+	cidr := func() string {
+		if len(request.Subnets) == 0 {
+			return ""
+		}
+		return request.Subnets[0].CIDR
+	}()
+	if xerr = udc.Prepare(*s.Config, request, cidr, ""); xerr != nil {
+		msg := "failed to prepare user data content"
+		logrus.Debugf(strprocess.Capitalize(msg + ": " + xerr.Error()))
+		return nullAHF, nullUDC, fail.Wrap(xerr, msg)
+	}
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	for _, v := range s.store.hosts {
+		if v.Core.Name == request.ResourceName {
+			return nullAHF, nullUDC, abstract.ResourceDuplicateError("host", request.ResourceName)
+		}
+	}
+	for k := range request.SecurityGroupIDs {
+		if _, ok := s.store.securityGroups[k]; !ok {
+			return nullAHF, nullUDC, abstract.ResourceNotFoundError("security group", k)
+		}
+	}
+
+	id, xerr := newID()
+	if xerr != nil {
+		return nullAHF, nullUDC, xerr
+	}
+
+	ahf := abstract.NewHostFull()
+	ahf.Core.ID = id
+	ahf.Core.Name = request.ResourceName
+	ahf.Core.PrivateKey = udc.FirstPrivateKey
+	ahf.Core.Password = request.Password
+	if request.SSHPort != 0 {
+		ahf.Core.SSHPort = request.SSHPort
+	}
+	ahf.Core.LastState = hoststate.Started
+	ahf.Core.Tags = cloneStringMap(request.Labels)
+	ahf.CurrentState = hoststate.Started
+
+	ahf.Sizing.Cores = template.Cores
+	ahf.Sizing.CPUFreq = template.CPUFreq
+	ahf.Sizing.RAMSize = template.RAMSize
+	ahf.Sizing.DiskSize = template.DiskSize
+	if request.DiskSize > template.DiskSize {
+		ahf.Sizing.DiskSize = request.DiskSize
+	}
+	ahf.Sizing.GPUNumber = template.GPUNumber
+	ahf.Sizing.GPUType = template.GPUType
+	ahf.Sizing.ImageID = image.ID
+	ahf.Sizing.Replaceable = request.Preemptible
+
+	ahf.Networking.IsGateway = request.IsGateway
+	for k, v := range request.Subnets {
+		as, ok := s.store.subnets[v.ID]
+		if !ok {
+			return nullAHF, nullUDC, abstract.ResourceNotFoundError("subnet", v.ID)
+		}
+		if k == 0 {
+			ahf.Networking.DefaultSubnetID = as.ID
+		}
+		ip, xerr := s.store.allocatePrivateIP(as)
+		if xerr != nil {
+			return nullAHF, nullUDC, xerr
+		}
+		ahf.Networking.SubnetsByID[as.ID] = as.Name
+		ahf.Networking.SubnetsByName[as.Name] = as.ID
+		ahf.Networking.IPv4Addresses[as.ID] = ip
+	}
+	if request.PublicIP {
+		ip, xerr := s.store.allocatePublicIP()
+		if xerr != nil {
+			return nullAHF, nullUDC, xerr
+		}
+		ahf.Networking.PublicIPv4 = ip
+	}
+
+	ahf.Description.Created = time.Now()
+	ahf.Description.Updated = ahf.Description.Created
+	ahf.Description.Tenant = s.AuthOptions.TenantName
+
+	s.store.hosts[id] = ahf
+	if len(request.SecurityGroupIDs) > 0 {
+		s.store.hostSGs[id] = map[string]struct{}{}
+		for k := range request.SecurityGroupIDs {
+			s.store.hostSGs[id][k] = struct{}{}
+		}
+	}
+
+	logrus.Infof("Host '%s' created successfully", request.ResourceName)
+	return cloneHost(ahf), udc, nil
+}
+
+// ClearHostStartupScript clears the userdata startup script for Host instance (metadata service)
+// Does actually nothing for memory stack
+func (s stack) ClearHostStartupScript(hostParam stacks.HostParameter) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if _, _, xerr := stacks.ValidateHostParameter(hostParam); xerr != nil {
+		return xerr
+	}
+	return nil
+}
+
+// InspectHost returns the host identified by ref (name or id) or by a *abstract.HostFull containing an id
+func (s stack) InspectHost(hostParam stacks.HostParameter) (*abstract.HostFull, fail.Error) {
+	nullAHF := abstract.NewHostFull()
+	if s.IsNull() {
+		return nullAHF, fail.InvalidInstanceError()
+	}
+	ahf, _, xerr := stacks.ValidateHostParameter(hostParam)
+	if xerr != nil {
+		return nullAHF, xerr
+	}
+
+	s.store.lock.RLock()
+	defer s.store.lock.RUnlock()
+
+	stored, xerr := s.store.findHost(ahf)
+	if xerr != nil {
+		return nullAHF, xerr
+	}
+	return cloneHost(stored), nil
+}
+
+// GetHostState returns the current state of the host
+func (s stack) GetHostState(hostParam stacks.HostParameter) (hoststate.Enum, fail.Error) {
+	if s.IsNull() {
+		return hoststate.Error, fail.InvalidInstanceError()
+	}
+
+	host, xerr := s.InspectHost(hostParam)
+	if xerr != nil {
+		return hoststate.Error, xerr
+	}
+	return host.CurrentState, nil
+}
+
+// ListHosts lists all hosts
+func (s stack) ListHosts(details bool) (abstract.HostList, fail.Error) {
+	var emptyList abstract.HostList
+	if s.IsNull() {
+		return emptyList, fail.InvalidInstanceError()
+	}
+
+	s.store.lock.RLock()
+	defer s.store.lock.RUnlock()
+
+	out := make(abstract.HostList, 0, len(s.store.hosts))
+	for _, v := range s.store.hosts {
+		if details {
+			out = append(out, cloneHost(v))
+		} else {
+			ahf := abstract.NewHostFull()
+			ahf.Core = v.Core.Clone().(*abstract.HostCore)
+			ahf.CurrentState = v.CurrentState
+			out = append(out, ahf)
+		}
+	}
+	return out, nil
+}
+
+// DeleteHost deletes the host, detaching its volumes and releasing its Security Groups
+func (s stack) DeleteHost(hostParam stacks.HostParameter) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	ahf, hostLabel, xerr := stacks.ValidateHostParameter(hostParam)
+	if xerr != nil {
+		return xerr
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.memory") || tracing.ShouldTrace("stacks.compute"), "(%s)", hostLabel).WithStopwatch().Entering().Exiting()
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	stored, xerr := s.store.findHost(ahf)
+	if xerr != nil {
+		return xerr
+	}
+
+	hostID := stored.Core.ID
+	for _, v := range s.store.attachments[hostID] {
+		if vol, ok := s.store.volumes[v.VolumeID]; ok {
+			vol.State = volumestate.Available
+		}
+	}
+	delete(s.store.attachments, hostID)
+	delete(s.store.hostSGs, hostID)
+	delete(s.store.hostFiles, hostID)
+	for _, vip := range s.store.vips {
+		for k, v := range vip.Hosts {
+			if v.ID == hostID {
+				vip.Hosts = append(vip.Hosts[:k], vip.Hosts[k+1:]...)
+				break
+			}
+		}
+	}
+	for _, v := range s.store.publicIPs {
+		if v.HostID == hostID {
+			v.HostID = ""
+		}
+	}
+	delete(s.store.hosts, hostID)
+	return nil
+}
+
+// StopHost stops the host
+func (s stack) StopHost(hostParam stacks.HostParameter, gracefully bool) fail.Error {
+	return s.changeHostState(hostParam, hoststate.Stopped)
+}
+
+// StartHost starts the host
+func (s stack) StartHost(hostParam stacks.HostParameter) fail.Error {
+	return s.changeHostState(hostParam, hoststate.Started)
+}
+
+// RebootHost reboots the host; as it is instantaneous, the host ends up started
+func (s stack) RebootHost(hostParam stacks.HostParameter) fail.Error {
+	return s.changeHostState(hostParam, hoststate.Started)
+}
+
+// changeHostState sets the state of the host
+func (s stack) changeHostState(hostParam stacks.HostParameter, state hoststate.Enum) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	ahf, hostLabel, xerr := stacks.ValidateHostParameter(hostParam)
+	if xerr != nil {
+		return xerr
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.memory") || tracing.ShouldTrace("stacks.compute"), "(%s, %s)", hostLabel, state.String()).WithStopwatch().Entering().Exiting()
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	stored, xerr := s.store.findHost(ahf)
+	if xerr != nil {
+		return xerr
+	}
+	stored.CurrentState = state
+	stored.Core.LastState = state
+	stored.Description.Updated = time.Now()
+	return nil
+}
+
+// ResizeHost changes the template used by a host
+func (s stack) ResizeHost(hostParam stacks.HostParameter, request abstract.HostSizingRequirements) (*abstract.HostFull, fail.Error) {
+	nullAHF := abstract.NewHostFull()
+	if s.IsNull() {
+		return nullAHF, fail.InvalidInstanceError()
+	}
+	if _, _, xerr := stacks.ValidateHostParameter(hostParam); xerr != nil {
+		return nullAHF, xerr
+	}
+
+	return nullAHF, fail.NotImplementedError("ResizeHost() not implemented yet") // FIXME: Technical debt
+}
+
+// WaitHostReady waits until the host is in state Started
+// As there is no provisioning delay in memory stack, the state is checked only once
+func (s stack) WaitHostReady(hostParam stacks.HostParameter, timeout time.Duration) (*abstract.HostCore, fail.Error) {
+	nullAHC := abstract.NewHostCore()
+	if s.IsNull() {
+		return nullAHC, fail.InvalidInstanceError()
+	}
+
+	host, xerr := s.InspectHost(hostParam)
+	if xerr != nil {
+		return nullAHC, xerr
+	}
+	if host.CurrentState != hoststate.Started {
+		return host.Core, fail.NotAvailableError("not in ready state (current state: %s)", host.CurrentState.String())
+	}
+	return host.Core, nil
+}
+
+// findHost returns the stored host corresponding to ahf (by ID, then by name)
+// Must be called with store lock held
+func (st *store) findHost(ahf *abstract.HostFull) (*abstract.HostFull, fail.Error) {
+	if id := ahf.Core.ID; id != "" {
+		if stored, ok := st.hosts[id]; ok {
+			return stored, nil
+		}
+	}
+	ref := ahf.Core.Name
+	if ref == "" {
+		ref = ahf.Core.ID
+	}
+	for _, v := range st.hosts {
+		if v.Core.Name == ref {
+			return v, nil
+		}
+	}
+	return nil, abstract.ResourceNotFoundError("host", ref)
+}
+
+// cloneHost does a deep copy of an *abstract.HostFull
+func cloneHost(in *abstract.HostFull) *abstract.HostFull {
+	out := abstract.NewHostFull()
+	out.Core = in.Core.Clone().(*abstract.HostCore)
+	*out.Sizing = *in.Sizing
+	*out.Description = *in.Description
+	out.CurrentState = in.CurrentState
+
+	networking := *in.Networking
+	networking.SubnetsByID = cloneStringMap(in.Networking.SubnetsByID)
+	networking.SubnetsByName = cloneStringMap(in.Networking.SubnetsByName)
+	networking.IPv4Addresses = cloneStringMap(in.Networking.IPv4Addresses)
+	networking.IPv6Addresses = cloneStringMap(in.Networking.IPv6Addresses)
+	out.Networking = &networking
+	return out
+}
+
+func cloneStringMap(in map[string]string) map[string]string {
+	out := make(map[string]string, len(in))
+	for k, v := range in {
+		out[k] = v
+	}
+	return out
+}
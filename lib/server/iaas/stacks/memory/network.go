@@ -0,0 +1,562 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"net"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/ipversion"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/subnetstate"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	netutils "github.com/CS-SI/SafeScale/lib/utils/net"
+)
+
+// HasDefaultNetwork returns true if the stack as a default network set (coming from tenants file)
+// No default network for memory stack
+func (s stack) HasDefaultNetwork() bool {
+	return false
+}
+
+// GetDefaultNetwork returns the *abstract.Network corresponding to the default network
+func (s stack) GetDefaultNetwork() (*abstract.Network, fail.Error) {
+	if s.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	return nil, fail.NotFoundError("no default Network in stack")
+}
+
+// CreateNetwork creates a Network
+func (s stack) CreateNetwork(req abstract.NetworkRequest) (*abstract.Network, fail.Error) {
+	nullAN := abstract.NewNetwork()
+	if s.IsNull() {
+		return nullAN, fail.InvalidInstanceError()
+	}
+	if req.Name == "" {
+		return nullAN, fail.InvalidParameterCannotBeEmptyStringError("req.Name")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.memory") || tracing.ShouldTrace("stacks.network"), "(%v)", req).WithStopwatch().Entering().Exiting()
+
+	if _, _, err := net.ParseCIDR(req.CIDR); err != nil {
+		return nullAN, fail.Wrap(err, "failed to parse CIDR '%s'", req.CIDR)
+	}
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	for _, v := range s.store.networks {
+		if v.Name == req.Name {
+			return nullAN, abstract.ResourceDuplicateError("network", req.Name)
+		}
+	}
+
+	id, xerr := newID()
+	if xerr != nil {
+		return nullAN, xerr
+	}
+
+	an := abstract.NewNetwork()
+	an.ID = id
+	an.Name = req.Name
+	an.CIDR = req.CIDR
+	an.DNSServers = append([]string{}, req.DNSServers...)
+	an.Tags = cloneStringMap(req.Labels)
+	s.store.networks[id] = an
+	return an.Clone().(*abstract.Network), nil
+}
+
+// InspectNetwork returns the Network identified by id
+func (s stack) InspectNetwork(id string) (*abstract.Network, fail.Error) {
+	nullAN := abstract.NewNetwork()
+	if s.IsNull() {
+		return nullAN, fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return nullAN, fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	s.store.lock.RLock()
+	defer s.store.lock.RUnlock()
+
+	an, ok := s.store.networks[id]
+	if !ok {
+		return nullAN, abstract.ResourceNotFoundError("network", id)
+	}
+	return an.Clone().(*abstract.Network), nil
+}
+
+// InspectNetworkByName returns the Network identified by name
+func (s stack) InspectNetworkByName(name string) (*abstract.Network, fail.Error) {
+	nullAN := abstract.NewNetwork()
+	if s.IsNull() {
+		return nullAN, fail.InvalidInstanceError()
+	}
+	if name == "" {
+		return nullAN, fail.InvalidParameterCannotBeEmptyStringError("name")
+	}
+
+	s.store.lock.RLock()
+	defer s.store.lock.RUnlock()
+
+	for _, v := range s.store.networks {
+		if v.Name == name {
+			return v.Clone().(*abstract.Network), nil
+		}
+	}
+	return nullAN, abstract.ResourceNotFoundError("network", name)
+}
+
+// ListNetworks lists all Networks
+func (s stack) ListNetworks() ([]*abstract.Network, fail.Error) {
+	var emptySlice []*abstract.Network
+	if s.IsNull() {
+		return emptySlice, fail.InvalidInstanceError()
+	}
+
+	s.store.lock.RLock()
+	defer s.store.lock.RUnlock()
+
+	out := make([]*abstract.Network, 0, len(s.store.networks))
+	for _, v := range s.store.networks {
+		out = append(out, v.Clone().(*abstract.Network))
+	}
+	return out, nil
+}
+
+// DeleteNetwork deletes the Network identified by id
+func (s stack) DeleteNetwork(id string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.memory") || tracing.ShouldTrace("stacks.network"), "(%s)", id).WithStopwatch().Entering().Exiting()
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	if _, ok := s.store.networks[id]; !ok {
+		return abstract.ResourceNotFoundError("network", id)
+	}
+	for _, v := range s.store.subnets {
+		if v.Network == id {
+			return fail.NotAvailableError("cannot delete Network '%s': still contains Subnet '%s'", id, v.Name)
+		}
+	}
+	for _, v := range s.store.securityGroups {
+		if v.Network == id {
+			return fail.NotAvailableError("cannot delete Network '%s': still contains Security Group '%s'", id, v.Name)
+		}
+	}
+	delete(s.store.networks, id)
+	return nil
+}
+
+// CreateSubnet creates a Subnet inside a Network
+func (s stack) CreateSubnet(req abstract.SubnetRequest) (*abstract.Subnet, fail.Error) {
+	nullAS := abstract.NewSubnet()
+	if s.IsNull() {
+		return nullAS, fail.InvalidInstanceError()
+	}
+	if req.NetworkID == "" {
+		return nullAS, fail.InvalidParameterCannotBeEmptyStringError("req.NetworkID")
+	}
+	if req.Name == "" {
+		return nullAS, fail.InvalidParameterCannotBeEmptyStringError("req.Name")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.memory") || tracing.ShouldTrace("stacks.network"), "(%v)", req).WithStopwatch().Entering().Exiting()
+
+	_, subnetNet, err := net.ParseCIDR(req.CIDR)
+	if err != nil {
+		return nullAS, fail.Wrap(err, "failed to parse CIDR '%s'", req.CIDR)
+	}
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	an, ok := s.store.networks[req.NetworkID]
+	if !ok {
+		return nullAS, abstract.ResourceNotFoundError("network", req.NetworkID)
+	}
+	_, networkNet, err := net.ParseCIDR(an.CIDR)
+	if err != nil {
+		return nullAS, fail.Wrap(err, "failed to parse CIDR '%s' of Network '%s'", an.CIDR, an.Name)
+	}
+	ones, _ := subnetNet.Mask.Size()
+	networkOnes, _ := networkNet.Mask.Size()
+	if !networkNet.Contains(subnetNet.IP) || ones < networkOnes {
+		return nullAS, fail.InvalidRequestError("CIDR '%s' of Subnet is not included in CIDR '%s' of Network '%s'", req.CIDR, an.CIDR, an.Name)
+	}
+
+	for _, v := range s.store.subnets {
+		if v.Network != req.NetworkID {
+			continue
+		}
+		if v.Name == req.Name {
+			return nullAS, abstract.ResourceDuplicateError("subnet", req.Name)
+		}
+		_, otherNet, err := net.ParseCIDR(v.CIDR)
+		if err == nil && netutils.CIDROverlap(*subnetNet, *otherNet) {
+			return nullAS, fail.InvalidRequestError("CIDR '%s' overlaps with CIDR '%s' of Subnet '%s'", req.CIDR, v.CIDR, v.Name)
+		}
+	}
+
+	firstIP, _, xerr := netutils.CIDRToUInt32Range(req.CIDR)
+	if xerr != nil {
+		return nullAS, xerr
+	}
+
+	id, xerr := newID()
+	if xerr != nil {
+		return nullAS, xerr
+	}
+
+	as := abstract.NewSubnet()
+	as.ID = id
+	as.Name = req.Name
+	as.Network = req.NetworkID
+	as.CIDR = req.CIDR
+	as.Domain = req.Domain
+	as.DNSServers = append([]string{}, req.DNSServers...)
+	as.IPVersion = req.IPVersion
+	if as.IPVersion != ipversion.IPv6 {
+		as.IPVersion = ipversion.IPv4
+	}
+	as.State = subnetstate.Ready
+	if req.DefaultSSHPort != 0 {
+		as.DefaultSSHPort = req.DefaultSSHPort
+	}
+	s.store.subnets[id] = as
+	// first address of the range is the network address, the second one is reserved as router of the Subnet
+	s.store.lastIPs[id] = firstIP + 1
+	return as.Clone().(*abstract.Subnet), nil
+}
+
+// InspectSubnet returns the Subnet identified by id
+func (s stack) InspectSubnet(id string) (*abstract.Subnet, fail.Error) {
+	nullAS := abstract.NewSubnet()
+	if s.IsNull() {
+		return nullAS, fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return nullAS, fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	s.store.lock.RLock()
+	defer s.store.lock.RUnlock()
+
+	as, ok := s.store.subnets[id]
+	if !ok {
+		return nullAS, abstract.ResourceNotFoundError("subnet", id)
+	}
+	return as.Clone().(*abstract.Subnet), nil
+}
+
+// InspectSubnetByName returns the Subnet identified by name inside the Network networkRef (if networkRef is not empty)
+func (s stack) InspectSubnetByName(networkRef, name string) (*abstract.Subnet, fail.Error) {
+	nullAS := abstract.NewSubnet()
+	if s.IsNull() {
+		return nullAS, fail.InvalidInstanceError()
+	}
+	if name == "" {
+		return nullAS, fail.InvalidParameterCannotBeEmptyStringError("name")
+	}
+
+	s.store.lock.RLock()
+	defer s.store.lock.RUnlock()
+
+	networkID := s.store.networkIDFromRef(networkRef)
+	var found *abstract.Subnet
+	for _, v := range s.store.subnets {
+		if v.Name != name || (networkID != "" && v.Network != networkID) {
+			continue
+		}
+		if found != nil {
+			return nullAS, fail.DuplicateError("more than one Subnet named '%s' found", name)
+		}
+		found = v
+	}
+	if found == nil {
+		return nullAS, abstract.ResourceNotFoundError("subnet", name)
+	}
+	return found.Clone().(*abstract.Subnet), nil
+}
+
+// ListSubnets lists the Subnets of the Network networkRef (or all Subnets if networkRef is empty)
+func (s stack) ListSubnets(networkRef string) ([]*abstract.Subnet, fail.Error) {
+	var emptySlice []*abstract.Subnet
+	if s.IsNull() {
+		return emptySlice, fail.InvalidInstanceError()
+	}
+
+	s.store.lock.RLock()
+	defer s.store.lock.RUnlock()
+
+	networkID := s.store.networkIDFromRef(networkRef)
+	out := make([]*abstract.Subnet, 0, len(s.store.subnets))
+	for _, v := range s.store.subnets {
+		if networkID == "" || v.Network == networkID {
+			out = append(out, v.Clone().(*abstract.Subnet))
+		}
+	}
+	return out, nil
+}
+
+// DeleteSubnet deletes the Subnet identified by id
+func (s stack) DeleteSubnet(id string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.memory") || tracing.ShouldTrace("stacks.network"), "(%s)", id).WithStopwatch().Entering().Exiting()
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	if _, ok := s.store.subnets[id]; !ok {
+		return abstract.ResourceNotFoundError("subnet", id)
+	}
+	for _, v := range s.store.hosts {
+		if _, ok := v.Networking.SubnetsByID[id]; ok {
+			return fail.NotAvailableError("cannot delete Subnet '%s': Host '%s' is still attached to it", id, v.Core.Name)
+		}
+	}
+	for _, v := range s.store.vips {
+		if v.SubnetID == id {
+			return fail.NotAvailableError("cannot delete Subnet '%s': VIP '%s' still uses it", id, v.Name)
+		}
+	}
+
+	delete(s.store.subnets, id)
+	delete(s.store.subnetSGs, id)
+	delete(s.store.lastIPs, id)
+	return nil
+}
+
+// CreateVIP creates a private virtual IP in a Subnet
+func (s stack) CreateVIP(networkID, subnetID, name string, securityGroups []string) (*abstract.VirtualIP, fail.Error) {
+	if s.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if subnetID == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("subnetID")
+	}
+	if name == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("name")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.memory") || tracing.ShouldTrace("stacks.network"), "(%s, '%s')", subnetID, name).WithStopwatch().Entering().Exiting()
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	as, ok := s.store.subnets[subnetID]
+	if !ok {
+		return nil, abstract.ResourceNotFoundError("subnet", subnetID)
+	}
+	for _, v := range securityGroups {
+		if _, ok := s.store.securityGroups[v]; !ok {
+			return nil, abstract.ResourceNotFoundError("security group", v)
+		}
+	}
+
+	ip, xerr := s.store.allocatePrivateIP(as)
+	if xerr != nil {
+		return nil, xerr
+	}
+	id, xerr := newID()
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	vip := abstract.NewVirtualIP()
+	vip.ID = id
+	vip.Name = name
+	vip.NetworkID = as.Network
+	vip.SubnetID = subnetID
+	vip.PrivateIP = ip
+	s.store.vips[id] = vip
+	return vip.Clone().(*abstract.VirtualIP), nil
+}
+
+// AddPublicIPToVIP allocates a public IP address to the VIP
+func (s stack) AddPublicIPToVIP(vip *abstract.VirtualIP) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if vip == nil {
+		return fail.InvalidParameterCannotBeNilError("vip")
+	}
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	stored, ok := s.store.vips[vip.ID]
+	if !ok {
+		return abstract.ResourceNotFoundError("vip", vip.ID)
+	}
+	if stored.PublicIP == "" {
+		ip, xerr := s.store.allocatePublicIP()
+		if xerr != nil {
+			return xerr
+		}
+		stored.PublicIP = ip
+	}
+	vip.PublicIP = stored.PublicIP
+	return nil
+}
+
+// BindHostToVIP makes the host hostID answer to the VIP
+func (s stack) BindHostToVIP(vip *abstract.VirtualIP, hostID string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if vip == nil {
+		return fail.InvalidParameterCannotBeNilError("vip")
+	}
+	if hostID == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("hostID")
+	}
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	stored, ok := s.store.vips[vip.ID]
+	if !ok {
+		return abstract.ResourceNotFoundError("vip", vip.ID)
+	}
+	host, ok := s.store.hosts[hostID]
+	if !ok {
+		return abstract.ResourceNotFoundError("host", hostID)
+	}
+	for _, v := range stored.Hosts {
+		if v.ID == hostID {
+			return nil
+		}
+	}
+	stored.Hosts = append(stored.Hosts, host.Core.Clone().(*abstract.HostCore))
+	return nil
+}
+
+// UnbindHostFromVIP removes the host hostID from the VIP
+func (s stack) UnbindHostFromVIP(vip *abstract.VirtualIP, hostID string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if vip == nil {
+		return fail.InvalidParameterCannotBeNilError("vip")
+	}
+	if hostID == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("hostID")
+	}
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	stored, ok := s.store.vips[vip.ID]
+	if !ok {
+		return abstract.ResourceNotFoundError("vip", vip.ID)
+	}
+	for k, v := range stored.Hosts {
+		if v.ID == hostID {
+			stored.Hosts = append(stored.Hosts[:k], stored.Hosts[k+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// DeleteVIP deletes the VIP
+func (s stack) DeleteVIP(vip *abstract.VirtualIP) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if vip == nil {
+		return fail.InvalidParameterCannotBeNilError("vip")
+	}
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	if _, ok := s.store.vips[vip.ID]; !ok {
+		return abstract.ResourceNotFoundError("vip", vip.ID)
+	}
+	delete(s.store.vips, vip.ID)
+	return nil
+}
+
+// networkIDFromRef returns the ID of the Network referenced by ID or name, or "" if not found
+// Must be called with store lock held
+func (st *store) networkIDFromRef(ref string) string {
+	if ref == "" {
+		return ""
+	}
+	if _, ok := st.networks[ref]; ok {
+		return ref
+	}
+	for k, v := range st.networks {
+		if v.Name == ref {
+			return k
+		}
+	}
+	return ref
+}
+
+// allocatePrivateIP returns the next free IPv4 address in the Subnet
+// Must be called with store lock held
+func (st *store) allocatePrivateIP(as *abstract.Subnet) (string, fail.Error) {
+	_, lastIP, xerr := netutils.CIDRToUInt32Range(as.CIDR)
+	if xerr != nil {
+		return "", xerr
+	}
+
+	next := st.lastIPs[as.ID] + 1
+	// last address of the range is the broadcast address
+	if next >= lastIP {
+		return "", fail.OverloadError("no more IP address available in Subnet '%s'", as.Name)
+	}
+	st.lastIPs[as.ID] = next
+	return netutils.UInt32ToIPv4String(next), nil
+}
+
+// allocatePublicIP returns the next free public IPv4 address
+// Must be called with store lock held
+func (st *store) allocatePublicIP() (string, fail.Error) {
+	firstIP, lastIP, xerr := netutils.CIDRToUInt32Range(PublicIPCIDR)
+	if xerr != nil {
+		return "", xerr
+	}
+
+	if st.lastPublicIP < firstIP {
+		st.lastPublicIP = firstIP
+	}
+	next := st.lastPublicIP + 1
+	if next >= lastIP {
+		return "", fail.OverloadError("no more public IP address available")
+	}
+	st.lastPublicIP = next
+	return netutils.UInt32ToIPv4String(next), nil
+}
@@ -0,0 +1,200 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// CreatePublicIP allocates a public IP not bound to any host
+func (s stack) CreatePublicIP(request abstract.PublicIPRequest) (*abstract.PublicIP, fail.Error) {
+	nullPIP := abstract.NewPublicIP()
+	if s.IsNull() {
+		return nullPIP, fail.InvalidInstanceError()
+	}
+	if request.Name == "" {
+		return nullPIP, fail.InvalidParameterCannotBeEmptyStringError("request.Name")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.memory") || tracing.ShouldTrace("stacks.network"), "(%s)", request.Name).WithStopwatch().Entering().Exiting()
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	for _, v := range s.store.publicIPs {
+		if v.Name == request.Name {
+			return nullPIP, abstract.ResourceDuplicateError("public ip", request.Name)
+		}
+	}
+
+	id, xerr := newID()
+	if xerr != nil {
+		return nullPIP, xerr
+	}
+	ip, xerr := s.store.allocatePublicIP()
+	if xerr != nil {
+		return nullPIP, xerr
+	}
+
+	pip := abstract.NewPublicIP()
+	pip.ID = id
+	pip.Name = request.Name
+	pip.Type = request.Type
+	pip.Description = request.Description
+	pip.IPAddress = ip
+	s.store.publicIPs[id] = pip
+	return pip.Clone().(*abstract.PublicIP), nil
+}
+
+// InspectPublicIP returns the public IP identified by id
+func (s stack) InspectPublicIP(id string) (*abstract.PublicIP, fail.Error) {
+	nullPIP := abstract.NewPublicIP()
+	if s.IsNull() {
+		return nullPIP, fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return nullPIP, fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	s.store.lock.RLock()
+	defer s.store.lock.RUnlock()
+
+	pip, ok := s.store.publicIPs[id]
+	if !ok {
+		return nullPIP, abstract.ResourceNotFoundError("public ip", id)
+	}
+	return pip.Clone().(*abstract.PublicIP), nil
+}
+
+// ListPublicIPs lists the public IPs allocated in the tenant
+func (s stack) ListPublicIPs() ([]*abstract.PublicIP, fail.Error) {
+	var emptySlice []*abstract.PublicIP
+	if s.IsNull() {
+		return emptySlice, fail.InvalidInstanceError()
+	}
+
+	s.store.lock.RLock()
+	defer s.store.lock.RUnlock()
+
+	out := make([]*abstract.PublicIP, 0, len(s.store.publicIPs))
+	for _, v := range s.store.publicIPs {
+		out = append(out, v.Clone().(*abstract.PublicIP))
+	}
+	return out, nil
+}
+
+// DeletePublicIP releases the public IP identified by id
+func (s stack) DeletePublicIP(id string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.memory") || tracing.ShouldTrace("stacks.network"), "(%s)", id).WithStopwatch().Entering().Exiting()
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	pip, ok := s.store.publicIPs[id]
+	if !ok {
+		return abstract.ResourceNotFoundError("public ip", id)
+	}
+	if pip.HostID != "" {
+		return fail.NotAvailableError("cannot delete public ip '%s': still bound to a host", pip.Name)
+	}
+	delete(s.store.publicIPs, id)
+	return nil
+}
+
+// BindPublicIPToHost associates the public IP to a host
+// The public IP replaces the public IP the host may already have
+func (s stack) BindPublicIPToHost(pip *abstract.PublicIP, hostParam stacks.HostParameter) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if pip == nil {
+		return fail.InvalidParameterCannotBeNilError("pip")
+	}
+	ahf, hostLabel, xerr := stacks.ValidateHostParameter(hostParam)
+	if xerr != nil {
+		return xerr
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.memory") || tracing.ShouldTrace("stacks.network"), "(%s, %s)", pip.ID, hostLabel).WithStopwatch().Entering().Exiting()
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	stored, ok := s.store.publicIPs[pip.ID]
+	if !ok {
+		return abstract.ResourceNotFoundError("public ip", pip.ID)
+	}
+	host, xerr := s.store.findHost(ahf)
+	if xerr != nil {
+		return xerr
+	}
+	if stored.HostID != "" && stored.HostID != host.Core.ID {
+		return fail.NotAvailableError("public ip '%s' is already bound to another host", stored.Name)
+	}
+
+	stored.HostID = host.Core.ID
+	host.Networking.PublicIPv4 = stored.IPAddress
+	return nil
+}
+
+// UnbindPublicIPFromHost dissociates the public IP from a host
+func (s stack) UnbindPublicIPFromHost(pip *abstract.PublicIP, hostParam stacks.HostParameter) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if pip == nil {
+		return fail.InvalidParameterCannotBeNilError("pip")
+	}
+	ahf, hostLabel, xerr := stacks.ValidateHostParameter(hostParam)
+	if xerr != nil {
+		return xerr
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.memory") || tracing.ShouldTrace("stacks.network"), "(%s, %s)", pip.ID, hostLabel).WithStopwatch().Entering().Exiting()
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	stored, ok := s.store.publicIPs[pip.ID]
+	if !ok {
+		return abstract.ResourceNotFoundError("public ip", pip.ID)
+	}
+	host, xerr := s.store.findHost(ahf)
+	if xerr != nil {
+		return xerr
+	}
+	if stored.HostID != host.Core.ID {
+		return fail.NotFoundError("public ip '%s' is not bound to host %s", stored.Name, hostLabel)
+	}
+
+	stored.HostID = ""
+	if host.Networking.PublicIPv4 == stored.IPAddress {
+		host.Networking.PublicIPv4 = ""
+	}
+	return nil
+}
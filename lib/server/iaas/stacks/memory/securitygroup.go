@@ -0,0 +1,416 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// ListSecurityGroups lists existing Security Groups of the Network networkRef (or all if networkRef is empty)
+func (s stack) ListSecurityGroups(networkRef string) ([]*abstract.SecurityGroup, fail.Error) {
+	var emptySlice []*abstract.SecurityGroup
+	if s.IsNull() {
+		return emptySlice, fail.InvalidInstanceError()
+	}
+
+	s.store.lock.RLock()
+	defer s.store.lock.RUnlock()
+
+	networkID := s.store.networkIDFromRef(networkRef)
+	out := make([]*abstract.SecurityGroup, 0, len(s.store.securityGroups))
+	for _, v := range s.store.securityGroups {
+		if networkID == "" || v.Network == networkID {
+			out = append(out, v.Clone().(*abstract.SecurityGroup))
+		}
+	}
+	return out, nil
+}
+
+// CreateSecurityGroup creates a Security Group in the Network networkRef
+func (s stack) CreateSecurityGroup(networkRef, name, description string, rules abstract.SecurityGroupRules) (*abstract.SecurityGroup, fail.Error) {
+	nullASG := abstract.NewSecurityGroup()
+	if s.IsNull() {
+		return nullASG, fail.InvalidInstanceError()
+	}
+	if name == "" {
+		return nullASG, fail.InvalidParameterCannotBeEmptyStringError("name")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.memory") || tracing.ShouldTrace("stacks.network"), "('%s', '%s')", networkRef, name).WithStopwatch().Entering().Exiting()
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	networkID := s.store.networkIDFromRef(networkRef)
+	if networkID != "" {
+		if _, ok := s.store.networks[networkID]; !ok {
+			return nullASG, abstract.ResourceNotFoundError("network", networkRef)
+		}
+	}
+	// by design, Security Group names are unique tenant-wide
+	for _, v := range s.store.securityGroups {
+		if v.Name == name {
+			return nullASG, abstract.ResourceDuplicateError("security group", name)
+		}
+	}
+
+	id, xerr := newID()
+	if xerr != nil {
+		return nullASG, xerr
+	}
+
+	asg := abstract.NewSecurityGroup()
+	asg.ID = id
+	asg.Name = name
+	asg.Network = networkID
+	asg.Description = description
+	for _, v := range rules {
+		rule := v.Clone().(*abstract.SecurityGroupRule)
+		if xerr = addRule(asg, rule); xerr != nil {
+			return nullASG, xerr
+		}
+	}
+	s.store.securityGroups[id] = asg
+	return asg.Clone().(*abstract.SecurityGroup), nil
+}
+
+// InspectSecurityGroup returns the Security Group referenced by sgParam
+func (s stack) InspectSecurityGroup(sgParam stacks.SecurityGroupParameter) (*abstract.SecurityGroup, fail.Error) {
+	nullASG := abstract.NewSecurityGroup()
+	if s.IsNull() {
+		return nullASG, fail.InvalidInstanceError()
+	}
+	asg, _, xerr := stacks.ValidateSecurityGroupParameter(sgParam)
+	if xerr != nil {
+		return nullASG, xerr
+	}
+
+	s.store.lock.RLock()
+	defer s.store.lock.RUnlock()
+
+	stored, xerr := s.store.findSecurityGroup(asg)
+	if xerr != nil {
+		return nullASG, xerr
+	}
+	return stored.Clone().(*abstract.SecurityGroup), nil
+}
+
+// ClearSecurityGroup removes all the rules of a Security Group
+func (s stack) ClearSecurityGroup(sgParam stacks.SecurityGroupParameter) (*abstract.SecurityGroup, fail.Error) {
+	nullASG := abstract.NewSecurityGroup()
+	if s.IsNull() {
+		return nullASG, fail.InvalidInstanceError()
+	}
+	asg, sgLabel, xerr := stacks.ValidateSecurityGroupParameter(sgParam)
+	if xerr != nil {
+		return nullASG, xerr
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.memory") || tracing.ShouldTrace("stacks.network"), "(%s)", sgLabel).WithStopwatch().Entering().Exiting()
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	stored, xerr := s.store.findSecurityGroup(asg)
+	if xerr != nil {
+		return nullASG, xerr
+	}
+	stored.Rules = abstract.SecurityGroupRules{}
+	return stored.Clone().(*abstract.SecurityGroup), nil
+}
+
+// DeleteSecurityGroup deletes a Security Group
+func (s stack) DeleteSecurityGroup(asg *abstract.SecurityGroup) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if asg.IsNull() {
+		return fail.InvalidParameterError("asg", "cannot be null value of '*abstract.SecurityGroup'")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.memory") || tracing.ShouldTrace("stacks.network"), "(%s)", asg.ID).WithStopwatch().Entering().Exiting()
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	stored, xerr := s.store.findSecurityGroup(asg)
+	if xerr != nil {
+		return xerr
+	}
+	for hostID, sgs := range s.store.hostSGs {
+		if _, ok := sgs[stored.ID]; ok {
+			return fail.NotAvailableError("cannot delete Security Group '%s': still bound to Host '%s'", stored.Name, hostID)
+		}
+	}
+	for subnetID, sgs := range s.store.subnetSGs {
+		if _, ok := sgs[stored.ID]; ok {
+			return fail.NotAvailableError("cannot delete Security Group '%s': still bound to Subnet '%s'", stored.Name, subnetID)
+		}
+	}
+	delete(s.store.securityGroups, stored.ID)
+	return nil
+}
+
+// AddRuleToSecurityGroup adds a rule to a Security Group
+func (s stack) AddRuleToSecurityGroup(sgParam stacks.SecurityGroupParameter, rule *abstract.SecurityGroupRule) (*abstract.SecurityGroup, fail.Error) {
+	nullASG := abstract.NewSecurityGroup()
+	if s.IsNull() {
+		return nullASG, fail.InvalidInstanceError()
+	}
+	asg, sgLabel, xerr := stacks.ValidateSecurityGroupParameter(sgParam)
+	if xerr != nil {
+		return nullASG, xerr
+	}
+	if rule == nil {
+		return nullASG, fail.InvalidParameterCannotBeNilError("rule")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.memory") || tracing.ShouldTrace("stacks.network"), "(%s)", sgLabel).WithStopwatch().Entering().Exiting()
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	stored, xerr := s.store.findSecurityGroup(asg)
+	if xerr != nil {
+		return nullASG, xerr
+	}
+	if xerr = addRule(stored, rule.Clone().(*abstract.SecurityGroupRule)); xerr != nil {
+		return nullASG, xerr
+	}
+	return stored.Clone().(*abstract.SecurityGroup), nil
+}
+
+// DeleteRuleFromSecurityGroup deletes a rule from a Security Group
+func (s stack) DeleteRuleFromSecurityGroup(sgParam stacks.SecurityGroupParameter, rule *abstract.SecurityGroupRule) (*abstract.SecurityGroup, fail.Error) {
+	nullASG := abstract.NewSecurityGroup()
+	if s.IsNull() {
+		return nullASG, fail.InvalidInstanceError()
+	}
+	asg, sgLabel, xerr := stacks.ValidateSecurityGroupParameter(sgParam)
+	if xerr != nil {
+		return nullASG, xerr
+	}
+	if rule == nil {
+		return nullASG, fail.InvalidParameterCannotBeNilError("rule")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.memory") || tracing.ShouldTrace("stacks.network"), "(%s)", sgLabel).WithStopwatch().Entering().Exiting()
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	stored, xerr := s.store.findSecurityGroup(asg)
+	if xerr != nil {
+		return nullASG, xerr
+	}
+	index, xerr := stored.Rules.IndexOfEquivalentRule(rule)
+	if xerr != nil {
+		return nullASG, xerr
+	}
+	if stored.Rules, xerr = stored.Rules.RemoveRuleByIndex(index); xerr != nil {
+		return nullASG, xerr
+	}
+	return stored.Clone().(*abstract.SecurityGroup), nil
+}
+
+// GetDefaultSecurityGroupName returns the name of the default Security Group
+func (s stack) GetDefaultSecurityGroupName() string {
+	if s.IsNull() {
+		return ""
+	}
+	return s.GetConfigurationOptions().DefaultSecurityGroupName
+}
+
+// EnableSecurityGroup enables a Security Group
+// Does actually nothing for memory stack
+func (s stack) EnableSecurityGroup(*abstract.SecurityGroup) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	return nil
+}
+
+// DisableSecurityGroup disables a Security Group
+// Does actually nothing for memory stack
+func (s stack) DisableSecurityGroup(*abstract.SecurityGroup) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	return nil
+}
+
+// BindSecurityGroupToSubnet binds a Security Group to a Subnet
+func (s stack) BindSecurityGroupToSubnet(sgParam stacks.SecurityGroupParameter, subnetID string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if subnetID == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("subnetID")
+	}
+	asg, _, xerr := stacks.ValidateSecurityGroupParameter(sgParam)
+	if xerr != nil {
+		return xerr
+	}
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	stored, xerr := s.store.findSecurityGroup(asg)
+	if xerr != nil {
+		return xerr
+	}
+	if _, ok := s.store.subnets[subnetID]; !ok {
+		return abstract.ResourceNotFoundError("subnet", subnetID)
+	}
+	if _, ok := s.store.subnetSGs[subnetID]; !ok {
+		s.store.subnetSGs[subnetID] = map[string]struct{}{}
+	}
+	s.store.subnetSGs[subnetID][stored.ID] = struct{}{}
+	return nil
+}
+
+// UnbindSecurityGroupFromSubnet unbinds a Security Group from a Subnet
+func (s stack) UnbindSecurityGroupFromSubnet(sgParam stacks.SecurityGroupParameter, subnetID string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if subnetID == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("subnetID")
+	}
+	asg, _, xerr := stacks.ValidateSecurityGroupParameter(sgParam)
+	if xerr != nil {
+		return xerr
+	}
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	stored, xerr := s.store.findSecurityGroup(asg)
+	if xerr != nil {
+		return xerr
+	}
+	if sgs, ok := s.store.subnetSGs[subnetID]; ok {
+		delete(sgs, stored.ID)
+	}
+	return nil
+}
+
+// BindSecurityGroupToHost binds a Security Group to a Host
+func (s stack) BindSecurityGroupToHost(sgParam stacks.SecurityGroupParameter, hostParam stacks.HostParameter) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	asg, _, xerr := stacks.ValidateSecurityGroupParameter(sgParam)
+	if xerr != nil {
+		return xerr
+	}
+	ahf, _, xerr := stacks.ValidateHostParameter(hostParam)
+	if xerr != nil {
+		return xerr
+	}
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	stored, xerr := s.store.findSecurityGroup(asg)
+	if xerr != nil {
+		return xerr
+	}
+	host, xerr := s.store.findHost(ahf)
+	if xerr != nil {
+		return xerr
+	}
+	if _, ok := s.store.hostSGs[host.Core.ID]; !ok {
+		s.store.hostSGs[host.Core.ID] = map[string]struct{}{}
+	}
+	s.store.hostSGs[host.Core.ID][stored.ID] = struct{}{}
+	return nil
+}
+
+// UnbindSecurityGroupFromHost unbinds a Security Group from a Host
+func (s stack) UnbindSecurityGroupFromHost(sgParam stacks.SecurityGroupParameter, hostParam stacks.HostParameter) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	asg, _, xerr := stacks.ValidateSecurityGroupParameter(sgParam)
+	if xerr != nil {
+		return xerr
+	}
+	ahf, _, xerr := stacks.ValidateHostParameter(hostParam)
+	if xerr != nil {
+		return xerr
+	}
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	stored, xerr := s.store.findSecurityGroup(asg)
+	if xerr != nil {
+		return xerr
+	}
+	host, xerr := s.store.findHost(ahf)
+	if xerr != nil {
+		return xerr
+	}
+	if sgs, ok := s.store.hostSGs[host.Core.ID]; ok {
+		delete(sgs, stored.ID)
+	}
+	return nil
+}
+
+// findSecurityGroup returns the stored Security Group corresponding to asg (by ID, then by name)
+// Must be called with store lock held
+func (st *store) findSecurityGroup(asg *abstract.SecurityGroup) (*abstract.SecurityGroup, fail.Error) {
+	if asg.ID != "" {
+		if stored, ok := st.securityGroups[asg.ID]; ok {
+			return stored, nil
+		}
+	}
+	ref := asg.Name
+	if ref == "" {
+		ref = asg.ID
+	}
+	for _, v := range st.securityGroups {
+		if v.Name == ref {
+			return v, nil
+		}
+	}
+	return nil, abstract.ResourceNotFoundError("security group", ref)
+}
+
+// addRule validates and adds the rule to the Security Group, rejecting duplicates
+func addRule(asg *abstract.SecurityGroup, rule *abstract.SecurityGroupRule) fail.Error {
+	if xerr := rule.Validate(); xerr != nil {
+		return xerr
+	}
+	for _, v := range asg.Rules {
+		if v.EquivalentTo(rule) {
+			return fail.DuplicateError("rule already exists in Security Group '%s'", asg.Name)
+		}
+	}
+	id, xerr := newID()
+	if xerr != nil {
+		return xerr
+	}
+	rule.IDs = []string{id}
+	asg.Rules = append(asg.Rules, rule)
+	return nil
+}
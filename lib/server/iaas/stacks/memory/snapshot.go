@@ -0,0 +1,182 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"time"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumestate"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// CreateVolumeSnapshot takes a snapshot of a volume
+func (s stack) CreateVolumeSnapshot(request abstract.SnapshotRequest) (*abstract.Snapshot, fail.Error) {
+	nullAS := abstract.NewSnapshot()
+	if s.IsNull() {
+		return nullAS, fail.InvalidInstanceError()
+	}
+	if request.Name == "" {
+		return nullAS, fail.InvalidParameterCannotBeEmptyStringError("request.Name")
+	}
+	if request.VolumeID == "" {
+		return nullAS, fail.InvalidParameterCannotBeEmptyStringError("request.VolumeID")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.memory") || tracing.ShouldTrace("stacks.volume"), "(%s, %s)", request.VolumeID, request.Name).WithStopwatch().Entering().Exiting()
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	av, ok := s.store.volumes[request.VolumeID]
+	if !ok {
+		return nullAS, abstract.ResourceNotFoundError("volume", request.VolumeID)
+	}
+	for _, v := range s.store.snapshots {
+		if v.Name == request.Name {
+			return nullAS, abstract.ResourceDuplicateError("snapshot", request.Name)
+		}
+	}
+
+	id, xerr := newID()
+	if xerr != nil {
+		return nullAS, xerr
+	}
+
+	as := abstract.NewSnapshot()
+	as.ID = id
+	as.Name = request.Name
+	as.Description = request.Description
+	as.VolumeID = av.ID
+	as.Size = av.Size
+	as.State = volumestate.Available
+	as.CreatedAt = time.Now().UTC()
+	s.store.snapshots[id] = as
+	return as.Clone().(*abstract.Snapshot), nil
+}
+
+// InspectVolumeSnapshot returns the volume snapshot identified by id
+func (s stack) InspectVolumeSnapshot(id string) (*abstract.Snapshot, fail.Error) {
+	nullAS := abstract.NewSnapshot()
+	if s.IsNull() {
+		return nullAS, fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return nullAS, fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	s.store.lock.RLock()
+	defer s.store.lock.RUnlock()
+
+	as, ok := s.store.snapshots[id]
+	if !ok {
+		return nullAS, abstract.ResourceNotFoundError("snapshot", id)
+	}
+	return as.Clone().(*abstract.Snapshot), nil
+}
+
+// ListVolumeSnapshots lists the snapshots of the volume identified by volumeID (all the snapshots if volumeID is empty)
+func (s stack) ListVolumeSnapshots(volumeID string) ([]*abstract.Snapshot, fail.Error) {
+	var emptySlice []*abstract.Snapshot
+	if s.IsNull() {
+		return emptySlice, fail.InvalidInstanceError()
+	}
+
+	s.store.lock.RLock()
+	defer s.store.lock.RUnlock()
+
+	out := make([]*abstract.Snapshot, 0, len(s.store.snapshots))
+	for _, v := range s.store.snapshots {
+		if volumeID == "" || v.VolumeID == volumeID {
+			out = append(out, v.Clone().(*abstract.Snapshot))
+		}
+	}
+	return out, nil
+}
+
+// DeleteVolumeSnapshot deletes the volume snapshot identified by id
+func (s stack) DeleteVolumeSnapshot(id string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.memory") || tracing.ShouldTrace("stacks.volume"), "(%s)", id).WithStopwatch().Entering().Exiting()
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	if _, ok := s.store.snapshots[id]; !ok {
+		return abstract.ResourceNotFoundError("snapshot", id)
+	}
+	delete(s.store.snapshots, id)
+	return nil
+}
+
+// CreateVolumeFromSnapshot creates a new volume filled with the content of the snapshot identified by snapshotID
+func (s stack) CreateVolumeFromSnapshot(snapshotID string, request abstract.VolumeRequest) (*abstract.Volume, fail.Error) {
+	nullAV := abstract.NewVolume()
+	if s.IsNull() {
+		return nullAV, fail.InvalidInstanceError()
+	}
+	if snapshotID == "" {
+		return nullAV, fail.InvalidParameterCannotBeEmptyStringError("snapshotID")
+	}
+	if request.Name == "" {
+		return nullAV, fail.InvalidParameterCannotBeEmptyStringError("request.Name")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.memory") || tracing.ShouldTrace("stacks.volume"), "(%s, %s)", snapshotID, request.Name).WithStopwatch().Entering().Exiting()
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	as, ok := s.store.snapshots[snapshotID]
+	if !ok {
+		return nullAV, abstract.ResourceNotFoundError("snapshot", snapshotID)
+	}
+	if request.Size == 0 {
+		request.Size = as.Size
+	}
+	if request.Size < as.Size {
+		return nullAV, fail.InvalidRequestError("cannot restore snapshot '%s' of %d GB in a volume of %d GB", as.Name, as.Size, request.Size)
+	}
+	for _, v := range s.store.volumes {
+		if v.Name == request.Name {
+			return nullAV, abstract.ResourceDuplicateError("volume", request.Name)
+		}
+	}
+
+	id, xerr := newID()
+	if xerr != nil {
+		return nullAV, xerr
+	}
+
+	av := abstract.NewVolume()
+	av.ID = id
+	av.Name = request.Name
+	av.Size = request.Size
+	av.Speed = request.Speed
+	av.State = volumestate.Available
+	av.Tags = cloneStringMap(request.Labels)
+	s.store.volumes[id] = av
+	return av.Clone().(*abstract.Volume), nil
+}
@@ -0,0 +1,293 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net"
+	"path"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/pkg/sftp"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/hoststate"
+	"github.com/CS-SI/SafeScale/lib/system"
+)
+
+// The hosts of the memory stack are never really started; their SSH server is simulated in process, reached through
+// system.RegisterSSHDialer: commands succeed without doing anything, except the reading of the state files of the
+// install phases (user_data.<phase>.done), answered as a host having completed the phase, and the md5sum of files
+// copied with SFTP, which are kept in memory with the host.
+
+func init() {
+	system.RegisterSSHDialer(sshDialer{})
+}
+
+// sshFxfRead is the SFTP flag opening a file for reading (SSH_FXF_READ)
+const sshFxfRead = 0x00000001
+
+var (
+	// phaseDoneRegexp matches the commands reading the state file of an install phase
+	phaseDoneRegexp = regexp.MustCompile(`cat [^ ]*/state/user_data\.([a-z0-9_]+)\.done`)
+	// md5sumRegexp matches the commands checking the content of a file copied on the host
+	md5sumRegexp = regexp.MustCompile(`md5sum ([^ ;|&\n]+)`)
+)
+
+var (
+	sshServerConfig     *ssh.ServerConfig
+	sshServerConfigOnce sync.Once
+)
+
+// getSSHServerConfig returns the configuration of the simulated SSH servers, accepting any client
+func getSSHServerConfig() *ssh.ServerConfig {
+	sshServerConfigOnce.Do(func() {
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			panic(fmt.Sprintf("failed to generate host key of simulated SSH servers: %v", err))
+		}
+		signer, err := ssh.NewSignerFromKey(key)
+		if err != nil {
+			panic(fmt.Sprintf("failed to generate host key of simulated SSH servers: %v", err))
+		}
+
+		sshServerConfig = &ssh.ServerConfig{NoClientAuth: true}
+		sshServerConfig.AddHostKey(signer)
+	})
+	return sshServerConfig
+}
+
+// sshDialer reaches the simulated SSH servers of the hosts of all the memory stacks
+type sshDialer struct{}
+
+// Serves tells if address is the one of a host of a memory stack
+func (sshDialer) Serves(address string) bool {
+	_, ok := findHostByAddress(address)
+	return ok
+}
+
+// DialSSH connects to the simulated SSH server of the host reached with address; the connection is refused if the host is not started
+func (sshDialer) DialSSH(ctx context.Context, address string) (net.Conn, error) {
+	host, ok := findHostByAddress(address)
+	if !ok {
+		return nil, fmt.Errorf("no route to host '%s'", address)
+	}
+	if host.state != hoststate.Started {
+		return nil, fmt.Errorf("connection to '%s' refused: host '%s' is in state '%s'", address, host.name, host.state.String())
+	}
+
+	// both ends of the SSH connection write before reading, so the unbuffered net.Pipe() cannot be used: the server
+	// listens on a loopback port, for this connection only
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		defer func() { _ = listener.Close() }()
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		serveSSH(conn, host)
+	}()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", listener.Addr().String())
+	if err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// simulatedHost contains what the simulated SSH server needs to know about a host
+type simulatedHost struct {
+	name  string
+	state hoststate.Enum
+	files sftp.Handlers
+}
+
+// findHostByAddress returns the host of a memory stack having the IP address of address
+func findHostByAddress(address string) (simulatedHost, bool) {
+	ip, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return simulatedHost{}, false
+	}
+
+	storesLock.Lock()
+	list := make([]*store, 0, len(stores))
+	for _, v := range stores {
+		list = append(list, v)
+	}
+	storesLock.Unlock()
+
+	for _, st := range list {
+		if host, ok := st.findHostByIP(ip); ok {
+			return host, true
+		}
+	}
+	return simulatedHost{}, false
+}
+
+// findHostByIP returns the host having the private or public IP address ip
+func (st *store) findHostByIP(ip string) (simulatedHost, bool) {
+	st.lock.Lock()
+	defer st.lock.Unlock()
+
+	for id, ahf := range st.hosts {
+		if !hostHasIP(ahf, ip) {
+			continue
+		}
+
+		files, ok := st.hostFiles[id]
+		if !ok {
+			files = sftp.InMemHandler()
+			files.FilePut = parentsCreatingWriter{files}
+			st.hostFiles[id] = files
+		}
+		return simulatedHost{name: ahf.Core.Name, state: ahf.Core.LastState, files: files}, true
+	}
+	return simulatedHost{}, false
+}
+
+// parentsCreatingWriter creates the missing parent folders of the files written, like they are created by the userdata on real hosts
+type parentsCreatingWriter struct {
+	files sftp.Handlers
+}
+
+// Filewrite creates the parent folders of the file requested then opens it for writing
+func (w parentsCreatingWriter) Filewrite(req *sftp.Request) (io.WriterAt, error) {
+	var parents []string
+	for dir := path.Dir(req.Filepath); dir != "/" && dir != "."; dir = path.Dir(dir) {
+		parents = append(parents, dir)
+	}
+	for i := len(parents) - 1; i >= 0; i-- {
+		// an error means the folder already exists
+		_ = w.files.FileCmd.Filecmd(sftp.NewRequest("Mkdir", parents[i]))
+	}
+	return w.files.FilePut.Filewrite(req)
+}
+
+// hostHasIP tells if ip is one of the IP addresses of the host
+func hostHasIP(ahf *abstract.HostFull, ip string) bool {
+	if ahf.Networking == nil {
+		return false
+	}
+	if ahf.Networking.PublicIPv4 == ip {
+		return true
+	}
+	for _, v := range ahf.Networking.IPv4Addresses {
+		if v == ip {
+			return true
+		}
+	}
+	return false
+}
+
+// serveSSH serves the SSH connection conn of host
+func serveSSH(conn net.Conn, host simulatedHost) {
+	defer func() { _ = conn.Close() }()
+
+	_, chans, reqs, err := ssh.NewServerConn(conn, getSSHServerConfig())
+	if err != nil {
+		logrus.Debugf("simulated SSH server of '%s': handshake failed: %v", host.name, err)
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	for newChannel := range chans {
+		if newChannel.ChannelType() != "session" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "only sessions are supported")
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+		go serveSSHSession(channel, requests, host)
+	}
+}
+
+// serveSSHSession serves a session: exec and shell requests succeed after reading the command on stdin, SFTP uses the
+// in-memory files of the host
+func serveSSHSession(channel ssh.Channel, requests <-chan *ssh.Request, host simulatedHost) {
+	defer func() { _ = channel.Close() }()
+
+	for req := range requests {
+		switch req.Type {
+		case "env", "pty-req", "window-change":
+			_ = req.Reply(req.WantReply, nil)
+		case "exec", "shell":
+			_ = req.Reply(true, nil)
+			// the command is given as argument of exec, or streamed to the stdin of the shell
+			command, _ := ioutil.ReadAll(channel)
+			if req.Type == "exec" && len(req.Payload) > 4 {
+				command = append(append(req.Payload[4:], '\n'), command...)
+			}
+			_, _ = io.WriteString(channel, simulatedOutput(string(command), host))
+			status := make([]byte, 4)
+			binary.BigEndian.PutUint32(status, 0)
+			_, _ = channel.SendRequest("exit-status", false, status)
+			return
+		case "subsystem":
+			if len(req.Payload) < 4 || string(req.Payload[4:]) != "sftp" {
+				_ = req.Reply(false, nil)
+				continue
+			}
+			_ = req.Reply(true, nil)
+			server := sftp.NewRequestServer(channel, host.files)
+			_ = server.Serve()
+			_ = server.Close()
+			return
+		default:
+			_ = req.Reply(false, nil)
+		}
+	}
+}
+
+// simulatedOutput returns the output of command on host
+func simulatedOutput(command string, host simulatedHost) string {
+	if match := phaseDoneRegexp.FindStringSubmatch(command); match != nil {
+		return fmt.Sprintf("0,linux,ubuntu,20.04,%s,%s", host.name, time.Now().Format("2006/01/02-15:04:05"))
+	}
+	if match := md5sumRegexp.FindStringSubmatch(command); match != nil {
+		req := sftp.NewRequest("Get", match[1])
+		req.Flags = sshFxfRead
+		reader, err := host.files.FileGet.Fileread(req)
+		if err != nil {
+			return ""
+		}
+		content, err := ioutil.ReadAll(io.NewSectionReader(reader, 0, math.MaxInt64))
+		if err != nil {
+			return ""
+		}
+		return fmt.Sprintf("%x  %s\n", md5.Sum(content), match[1])
+	}
+	return ""
+}
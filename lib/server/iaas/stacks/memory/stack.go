@@ -0,0 +1,430 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"sync"
+
+	"github.com/pkg/sftp"
+	uuid "github.com/satori/go.uuid"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+const (
+	// PublicIPCIDR is the CIDR of the (documentation) network used to allocate fake public IP addresses
+	PublicIPCIDR = "203.0.113.0/24"
+)
+
+// store contains the state of the in-memory "cloud" of a tenant
+// All stack instances built for the same tenant share the same store, so the content survives
+// successive calls to iaas.UseService()
+type store struct {
+	lock sync.RWMutex
+
+	keypairs       map[string]*abstract.KeyPair                     // indexed by name
+	networks       map[string]*abstract.Network                     // indexed by ID
+	subnets        map[string]*abstract.Subnet                      // indexed by ID
+	vips           map[string]*abstract.VirtualIP                   // indexed by ID
+	publicIPs      map[string]*abstract.PublicIP                    // indexed by ID
+	securityGroups map[string]*abstract.SecurityGroup               // indexed by ID
+	hosts          map[string]*abstract.HostFull                    // indexed by ID
+	images         map[string]*abstract.Image                       // custom images, indexed by ID
+	volumes        map[string]*abstract.Volume                      // indexed by ID
+	snapshots      map[string]*abstract.Snapshot                    // indexed by ID
+	attachments    map[string]map[string]*abstract.VolumeAttachment // indexed by host ID, then by attachment ID
+	hostSGs        map[string]map[string]struct{}                   // IDs of Security Groups bound to host, indexed by host ID
+	subnetSGs      map[string]map[string]struct{}                   // IDs of Security Groups bound to subnet, indexed by subnet ID
+	lastIPs        map[string]uint32                                // last private IP address allocated, indexed by subnet ID
+	hostFiles      map[string]sftp.Handlers                         // files copied on host with SFTP, indexed by host ID
+	lastPublicIP   uint32
+}
+
+var (
+	stores     = map[string]*store{}
+	storesLock sync.Mutex
+)
+
+// getStore returns the store corresponding to the tenant, creating it if needed
+func getStore(tenant string) *store {
+	storesLock.Lock()
+	defer storesLock.Unlock()
+
+	if s, ok := stores[tenant]; ok {
+		return s
+	}
+
+	s := &store{
+		keypairs:       map[string]*abstract.KeyPair{},
+		networks:       map[string]*abstract.Network{},
+		subnets:        map[string]*abstract.Subnet{},
+		vips:           map[string]*abstract.VirtualIP{},
+		publicIPs:      map[string]*abstract.PublicIP{},
+		securityGroups: map[string]*abstract.SecurityGroup{},
+		hosts:          map[string]*abstract.HostFull{},
+		images:         map[string]*abstract.Image{},
+		volumes:        map[string]*abstract.Volume{},
+		snapshots:      map[string]*abstract.Snapshot{},
+		attachments:    map[string]map[string]*abstract.VolumeAttachment{},
+		hostSGs:        map[string]map[string]struct{}{},
+		subnetSGs:      map[string]map[string]struct{}{},
+		lastIPs:        map[string]uint32{},
+		hostFiles:      map[string]sftp.Handlers{},
+	}
+	stores[tenant] = s
+	return s
+}
+
+// Forget drops the in-memory content of the tenant
+// Intended to be used by tests to start from a clean state
+func Forget(tenant string) {
+	storesLock.Lock()
+	defer storesLock.Unlock()
+
+	delete(stores, tenant)
+}
+
+// stack is the implementation of an in-memory stack, usable to run SafeScale without cloud account
+type stack struct {
+	Config      *stacks.ConfigurationOptions
+	AuthOptions *stacks.AuthenticationOptions
+
+	images    []abstract.Image
+	templates []abstract.HostTemplate
+
+	store *store
+}
+
+// NullStack is not exposed through API, is needed essentially by tests
+func NullStack() *stack { //nolint
+	return &stack{}
+}
+
+// New creates a new in-memory stack; the content is shared between all the stacks of the same tenant
+// (identified by auth.TenantName)
+func New(auth stacks.AuthenticationOptions, cfg stacks.ConfigurationOptions) (*stack, fail.Error) { //nolint
+	if auth.TenantName == "" {
+		return NullStack(), fail.InvalidParameterError("auth.TenantName", "cannot be empty string")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.memory"), "('%s')", auth.TenantName).Entering().Exiting()
+
+	if cfg.DefaultSecurityGroupName == "" {
+		cfg.DefaultSecurityGroupName = "safescale-default-sg"
+	}
+	return &stack{
+		Config:      &cfg,
+		AuthOptions: &auth,
+		images:      defaultImages(),
+		templates:   defaultTemplates(),
+		store:       getStore(auth.TenantName),
+	}, nil
+}
+
+// IsNull tells if the instance is a null value
+func (s *stack) IsNull() bool {
+	return s == nil || s.store == nil
+}
+
+// GetConfigurationOptions ...
+func (s stack) GetConfigurationOptions() stacks.ConfigurationOptions {
+	if s.IsNull() || s.Config == nil {
+		return stacks.ConfigurationOptions{}
+	}
+	return *s.Config
+}
+
+// GetAuthenticationOptions ...
+func (s stack) GetAuthenticationOptions() stacks.AuthenticationOptions {
+	if s.IsNull() || s.AuthOptions == nil {
+		return stacks.AuthenticationOptions{}
+	}
+	return *s.AuthOptions
+}
+
+// ListRegions returns the only region of the stack
+func (s stack) ListRegions() ([]string, fail.Error) {
+	if s.IsNull() {
+		return []string{}, fail.InvalidInstanceError()
+	}
+	return []string{s.AuthOptions.Region}, nil
+}
+
+// ListAvailabilityZones returns the only availability zone of the stack
+func (s stack) ListAvailabilityZones() (map[string]bool, fail.Error) {
+	if s.IsNull() {
+		return map[string]bool{}, fail.InvalidInstanceError()
+	}
+	return map[string]bool{s.AuthOptions.AvailabilityZone: true}, nil
+}
+
+// ListImages lists available OS images
+func (s stack) ListImages() ([]abstract.Image, fail.Error) {
+	if s.IsNull() {
+		return []abstract.Image{}, fail.InvalidInstanceError()
+	}
+
+	s.store.lock.RLock()
+	defer s.store.lock.RUnlock()
+
+	out := make([]abstract.Image, len(s.images), len(s.images)+len(s.store.images))
+	copy(out, s.images)
+	for _, v := range s.store.images {
+		out = append(out, *v)
+	}
+	return out, nil
+}
+
+// InspectImage returns the Image referenced by id
+func (s stack) InspectImage(id string) (abstract.Image, fail.Error) {
+	if s.IsNull() {
+		return abstract.Image{}, fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return abstract.Image{}, fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	for _, v := range s.images {
+		if v.ID == id {
+			return v, nil
+		}
+	}
+
+	s.store.lock.RLock()
+	defer s.store.lock.RUnlock()
+
+	if v, ok := s.store.images[id]; ok {
+		return *v, nil
+	}
+	return abstract.Image{}, abstract.ResourceNotFoundError("image", id)
+}
+
+// CreateImage creates a custom image from the host identified by request.HostID
+func (s stack) CreateImage(request abstract.ImageRequest) (*abstract.Image, fail.Error) {
+	nullAI := abstract.NewImage()
+	if s.IsNull() {
+		return nullAI, fail.InvalidInstanceError()
+	}
+	if request.Name == "" {
+		return nullAI, fail.InvalidParameterCannotBeEmptyStringError("request.Name")
+	}
+	if request.HostID == "" {
+		return nullAI, fail.InvalidParameterCannotBeEmptyStringError("request.HostID")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.memory") || tracing.ShouldTrace("stacks.compute"), "(%s, %s)", request.HostID, request.Name).WithStopwatch().Entering().Exiting()
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	ahf, ok := s.store.hosts[request.HostID]
+	if !ok {
+		return nullAI, abstract.ResourceNotFoundError("host", request.HostID)
+	}
+	for _, v := range s.images {
+		if v.Name == request.Name {
+			return nullAI, abstract.ResourceDuplicateError("image", request.Name)
+		}
+	}
+	for _, v := range s.store.images {
+		if v.Name == request.Name {
+			return nullAI, abstract.ResourceDuplicateError("image", request.Name)
+		}
+	}
+
+	id, xerr := newID()
+	if xerr != nil {
+		return nullAI, xerr
+	}
+
+	ai := abstract.NewImage()
+	ai.ID = id
+	ai.Name = request.Name
+	ai.Description = request.Description
+	ai.HostID = ahf.Core.ID
+	if ahf.Sizing != nil {
+		ai.DiskSize = int64(ahf.Sizing.DiskSize)
+	}
+	s.store.images[id] = ai
+	return ai.Clone().(*abstract.Image), nil
+}
+
+// DeleteImage deletes the custom image identified by id
+func (s stack) DeleteImage(id string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.memory") || tracing.ShouldTrace("stacks.compute"), "(%s)", id).WithStopwatch().Entering().Exiting()
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	if _, ok := s.store.images[id]; !ok {
+		return abstract.ResourceNotFoundError("image", id)
+	}
+	delete(s.store.images, id)
+	return nil
+}
+
+// ListTemplates lists available host templates
+func (s stack) ListTemplates() ([]abstract.HostTemplate, fail.Error) {
+	if s.IsNull() {
+		return []abstract.HostTemplate{}, fail.InvalidInstanceError()
+	}
+
+	out := make([]abstract.HostTemplate, len(s.templates))
+	copy(out, s.templates)
+	return out, nil
+}
+
+// InspectTemplate returns the Template referenced by id
+func (s stack) InspectTemplate(id string) (abstract.HostTemplate, fail.Error) {
+	if s.IsNull() {
+		return abstract.HostTemplate{}, fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return abstract.HostTemplate{}, fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	for _, v := range s.templates {
+		if v.ID == id {
+			return v, nil
+		}
+	}
+	return abstract.HostTemplate{}, abstract.ResourceNotFoundError("template", id)
+}
+
+// CreateKeyPair creates a key pair
+func (s stack) CreateKeyPair(name string) (*abstract.KeyPair, fail.Error) {
+	if s.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if name == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("name")
+	}
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	if _, ok := s.store.keypairs[name]; ok {
+		return nil, abstract.ResourceDuplicateError("keypair", name)
+	}
+
+	kp, xerr := abstract.NewKeyPair(name)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	stored := *kp
+	stored.PrivateKey = ""
+	s.store.keypairs[name] = &stored
+	return kp, nil
+}
+
+// InspectKeyPair returns the key pair identified by id (private key is not returned)
+func (s stack) InspectKeyPair(id string) (*abstract.KeyPair, fail.Error) {
+	if s.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	s.store.lock.RLock()
+	defer s.store.lock.RUnlock()
+
+	kp, ok := s.store.keypairs[id]
+	if !ok {
+		return nil, abstract.ResourceNotFoundError("keypair", id)
+	}
+	out := *kp
+	return &out, nil
+}
+
+// ListKeyPairs lists available key pairs (private keys are not returned)
+func (s stack) ListKeyPairs() ([]abstract.KeyPair, fail.Error) {
+	if s.IsNull() {
+		return []abstract.KeyPair{}, fail.InvalidInstanceError()
+	}
+
+	s.store.lock.RLock()
+	defer s.store.lock.RUnlock()
+
+	out := make([]abstract.KeyPair, 0, len(s.store.keypairs))
+	for _, v := range s.store.keypairs {
+		out = append(out, *v)
+	}
+	return out, nil
+}
+
+// DeleteKeyPair deletes the key pair identified by id
+func (s stack) DeleteKeyPair(id string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	if _, ok := s.store.keypairs[id]; !ok {
+		return abstract.ResourceNotFoundError("keypair", id)
+	}
+	delete(s.store.keypairs, id)
+	return nil
+}
+
+// newID generates a new unique ID for a resource
+func newID() (string, fail.Error) {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return "", fail.Wrap(err, "failed to generate ID")
+	}
+	return id.String(), nil
+}
+
+// defaultImages returns the images proposed by the stack
+func defaultImages() []abstract.Image {
+	return []abstract.Image{
+		{ID: "ubuntu-2004", Name: "Ubuntu 20.04", URL: "memory://images/ubuntu-2004", DiskSize: 10},
+		{ID: "ubuntu-1804", Name: "Ubuntu 18.04", URL: "memory://images/ubuntu-1804", DiskSize: 10},
+		{ID: "debian-10", Name: "Debian 10", URL: "memory://images/debian-10", DiskSize: 10},
+		{ID: "centos-7", Name: "CentOS 7.9", URL: "memory://images/centos-7", DiskSize: 10},
+	}
+}
+
+// defaultTemplates returns the host templates proposed by the stack
+func defaultTemplates() []abstract.HostTemplate {
+	return []abstract.HostTemplate{
+		{ID: "tiny", Name: "tiny", Cores: 1, RAMSize: 1, DiskSize: 10, CPUFreq: 2.4},
+		{ID: "small", Name: "small", Cores: 2, RAMSize: 4, DiskSize: 20, CPUFreq: 2.4},
+		{ID: "medium", Name: "medium", Cores: 4, RAMSize: 8, DiskSize: 40, CPUFreq: 2.4},
+		{ID: "large", Name: "large", Cores: 8, RAMSize: 16, DiskSize: 80, CPUFreq: 2.4},
+		{ID: "xlarge", Name: "xlarge", Cores: 16, RAMSize: 64, DiskSize: 160, CPUFreq: 2.4},
+		{ID: "gpu", Name: "gpu", Cores: 8, RAMSize: 32, DiskSize: 100, CPUFreq: 2.4, GPUNumber: 1, GPUType: "memory-gpu"},
+	}
+}
@@ -0,0 +1,274 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"fmt"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumestate"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// CreateVolume creates a block volume
+func (s stack) CreateVolume(request abstract.VolumeRequest) (*abstract.Volume, fail.Error) {
+	nullAV := abstract.NewVolume()
+	if s.IsNull() {
+		return nullAV, fail.InvalidInstanceError()
+	}
+	if request.Name == "" {
+		return nullAV, fail.InvalidParameterCannotBeEmptyStringError("request.Name")
+	}
+	if request.Size <= 0 {
+		return nullAV, fail.InvalidParameterError("request.Size", "must be a positive integer")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.memory") || tracing.ShouldTrace("stacks.volume"), "(%s)", request.Name).WithStopwatch().Entering().Exiting()
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	for _, v := range s.store.volumes {
+		if v.Name == request.Name {
+			return nullAV, abstract.ResourceDuplicateError("volume", request.Name)
+		}
+	}
+
+	id, xerr := newID()
+	if xerr != nil {
+		return nullAV, xerr
+	}
+
+	av := abstract.NewVolume()
+	av.ID = id
+	av.Name = request.Name
+	av.Size = request.Size
+	av.Speed = request.Speed
+	av.State = volumestate.Available
+	av.Tags = cloneStringMap(request.Labels)
+	s.store.volumes[id] = av
+	return av.Clone().(*abstract.Volume), nil
+}
+
+// InspectVolume returns the volume identified by id
+func (s stack) InspectVolume(id string) (*abstract.Volume, fail.Error) {
+	nullAV := abstract.NewVolume()
+	if s.IsNull() {
+		return nullAV, fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return nullAV, fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	s.store.lock.RLock()
+	defer s.store.lock.RUnlock()
+
+	av, ok := s.store.volumes[id]
+	if !ok {
+		return nullAV, abstract.ResourceNotFoundError("volume", id)
+	}
+	return av.Clone().(*abstract.Volume), nil
+}
+
+// ListVolumes returns the list of all volumes
+func (s stack) ListVolumes() ([]abstract.Volume, fail.Error) {
+	var emptySlice []abstract.Volume
+	if s.IsNull() {
+		return emptySlice, fail.InvalidInstanceError()
+	}
+
+	s.store.lock.RLock()
+	defer s.store.lock.RUnlock()
+
+	out := make([]abstract.Volume, 0, len(s.store.volumes))
+	for _, v := range s.store.volumes {
+		out = append(out, *v)
+	}
+	return out, nil
+}
+
+// DeleteVolume deletes the volume identified by id
+func (s stack) DeleteVolume(id string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.memory") || tracing.ShouldTrace("stacks.volume"), "(%s)", id).WithStopwatch().Entering().Exiting()
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	av, ok := s.store.volumes[id]
+	if !ok {
+		return abstract.ResourceNotFoundError("volume", id)
+	}
+	if av.State == volumestate.Used {
+		return fail.NotAvailableError("cannot delete volume '%s': still attached to a host", av.Name)
+	}
+	delete(s.store.volumes, id)
+	return nil
+}
+
+// ResizeVolume grows the volume identified by id to newSize GB
+func (s stack) ResizeVolume(id string, newSize int) (*abstract.Volume, fail.Error) {
+	nullAV := abstract.NewVolume()
+	if s.IsNull() {
+		return nullAV, fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return nullAV, fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.memory") || tracing.ShouldTrace("stacks.volume"), "(%s, %d)", id, newSize).WithStopwatch().Entering().Exiting()
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	av, ok := s.store.volumes[id]
+	if !ok {
+		return nullAV, abstract.ResourceNotFoundError("volume", id)
+	}
+	if newSize <= av.Size {
+		return nullAV, fail.InvalidParameterError("newSize", "must be greater than the current size of the volume (%d GB)", av.Size)
+	}
+	av.Size = newSize
+	return av.Clone().(*abstract.Volume), nil
+}
+
+// CreateVolumeAttachment attaches a volume to a host
+// The ID of the attachment is the ID of the volume
+func (s stack) CreateVolumeAttachment(request abstract.VolumeAttachmentRequest) (string, fail.Error) {
+	if s.IsNull() {
+		return "", fail.InvalidInstanceError()
+	}
+	if request.VolumeID == "" {
+		return "", fail.InvalidParameterCannotBeEmptyStringError("request.VolumeID")
+	}
+	if request.HostID == "" {
+		return "", fail.InvalidParameterCannotBeEmptyStringError("request.HostID")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.memory") || tracing.ShouldTrace("stacks.volume"), "('%s')", request.Name).WithStopwatch().Entering().Exiting()
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	av, ok := s.store.volumes[request.VolumeID]
+	if !ok {
+		return "", abstract.ResourceNotFoundError("volume", request.VolumeID)
+	}
+	if _, ok := s.store.hosts[request.HostID]; !ok {
+		return "", abstract.ResourceNotFoundError("host", request.HostID)
+	}
+	if av.State == volumestate.Used {
+		return "", fail.NotAvailableError("volume '%s' is already attached", av.Name)
+	}
+
+	attachments, ok := s.store.attachments[request.HostID]
+	if !ok {
+		attachments = map[string]*abstract.VolumeAttachment{}
+		s.store.attachments[request.HostID] = attachments
+	}
+
+	ava := abstract.NewVolumeAttachment()
+	ava.ID = av.ID
+	ava.Name = request.Name
+	ava.VolumeID = av.ID
+	ava.ServerID = request.HostID
+	// system disk is /dev/vda, attached volumes start at /dev/vdb
+	ava.Device = fmt.Sprintf("/dev/vd%c", 'b'+len(attachments))
+	attachments[ava.ID] = ava
+	av.State = volumestate.Used
+	return ava.ID, nil
+}
+
+// InspectVolumeAttachment returns the volume attachment identified by id
+func (s stack) InspectVolumeAttachment(serverID, id string) (*abstract.VolumeAttachment, fail.Error) {
+	nullAVA := abstract.NewVolumeAttachment()
+	if s.IsNull() {
+		return nullAVA, fail.InvalidInstanceError()
+	}
+	if serverID == "" {
+		return nullAVA, fail.InvalidParameterCannotBeEmptyStringError("serverID")
+	}
+	if id == "" {
+		return nullAVA, fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	s.store.lock.RLock()
+	defer s.store.lock.RUnlock()
+
+	ava, ok := s.store.attachments[serverID][id]
+	if !ok {
+		return nullAVA, abstract.ResourceNotFoundError("volume attachment", id)
+	}
+	out := *ava
+	return &out, nil
+}
+
+// ListVolumeAttachments lists available volume attachments of a host
+func (s stack) ListVolumeAttachments(serverID string) ([]abstract.VolumeAttachment, fail.Error) {
+	var emptySlice []abstract.VolumeAttachment
+	if s.IsNull() {
+		return emptySlice, fail.InvalidInstanceError()
+	}
+	if serverID == "" {
+		return emptySlice, fail.InvalidParameterCannotBeEmptyStringError("serverID")
+	}
+
+	s.store.lock.RLock()
+	defer s.store.lock.RUnlock()
+
+	out := make([]abstract.VolumeAttachment, 0, len(s.store.attachments[serverID]))
+	for _, v := range s.store.attachments[serverID] {
+		out = append(out, *v)
+	}
+	return out, nil
+}
+
+// DeleteVolumeAttachment detaches the volume attachment identified by id from the host serverID
+func (s stack) DeleteVolumeAttachment(serverID, id string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if serverID == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("serverID")
+	}
+	if id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.memory") || tracing.ShouldTrace("stacks.volume"), "(%s, %s)", serverID, id).WithStopwatch().Entering().Exiting()
+
+	s.store.lock.Lock()
+	defer s.store.lock.Unlock()
+
+	ava, ok := s.store.attachments[serverID][id]
+	if !ok {
+		return abstract.ResourceNotFoundError("volume attachment", id)
+	}
+	if av, ok := s.store.volumes[ava.VolumeID]; ok {
+		av.State = volumestate.Available
+	}
+	delete(s.store.attachments[serverID], id)
+	return nil
+}
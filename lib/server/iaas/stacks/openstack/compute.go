@@ -690,7 +690,7 @@ func (s Stack) CreateHost(request abstract.HostRequest) (host *abstract.HostFull
 				}
 			}()
 
-			server, innerXErr = s.rpcCreateServer(request.ResourceName, hostNets, request.TemplateID, request.ImageID, userDataPhase1, azone)
+			server, innerXErr = s.rpcCreateServer(request.ResourceName, hostNets, request.TemplateID, request.ImageID, userDataPhase1, azone, request.Labels)
 			if innerXErr != nil {
 				switch innerXErr.(type) {
 				case *retry.ErrStopRetry:
@@ -1141,6 +1141,7 @@ func (s Stack) ListHosts(details bool) (abstract.HostList, fail.Error) {
 				for _, srv := range list {
 					ahc := abstract.NewHostCore()
 					ahc.ID = srv.ID
+					ahc.Tags = srv.Metadata
 					var ahf *abstract.HostFull
 					if details {
 						ahf, err = s.complementHost(ahc, srv, nil, nil)
@@ -0,0 +1,152 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package openstack
+
+import (
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/retry"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+// CreateImage creates a custom image from the host identified by request.HostID
+// Returns when the image is active (usable to create hosts)
+func (s Stack) CreateImage(request abstract.ImageRequest) (_ *abstract.Image, xerr fail.Error) {
+	nullAI := abstract.NewImage()
+	if s.IsNull() {
+		return nullAI, fail.InvalidInstanceError()
+	}
+	if request.Name == "" {
+		return nullAI, fail.InvalidParameterCannotBeEmptyStringError("request.Name")
+	}
+	if request.HostID == "" {
+		return nullAI, fail.InvalidParameterCannotBeEmptyStringError("request.HostID")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("Stack.openstack") || tracing.ShouldTrace("stacks.compute"), "(%s, %s)", request.HostID, request.Name).WithStopwatch().Entering().Exiting()
+
+	opts := servers.CreateImageOpts{
+		Name: request.Name,
+		Metadata: map[string]string{
+			"ManagedBy":   "safescale",
+			"Description": request.Description,
+		},
+	}
+	var imageID string
+	xerr = stacks.RetryableRemoteCall(
+		func() (innerErr error) {
+			imageID, innerErr = servers.CreateImage(s.ComputeClient, request.HostID, opts).ExtractImageID()
+			return innerErr
+		},
+		NormalizeError,
+	)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			return nullAI, abstract.ResourceNotFoundError("host", request.HostID)
+		default:
+			return nullAI, xerr
+		}
+	}
+
+	defer func() {
+		if xerr != nil {
+			if derr := s.DeleteImage(imageID); derr != nil {
+				logrus.Errorf("cleaning up on failure, failed to delete image '%s': %v", request.Name, derr)
+				_ = xerr.AddConsequence(derr)
+			}
+		}
+	}()
+
+	var img *images.Image
+	xerr = retry.WhileUnsuccessful(
+		func() error {
+			innerXErr := stacks.RetryableRemoteCall(
+				func() (innerErr error) {
+					img, innerErr = images.Get(s.ComputeClient, imageID).Extract()
+					return innerErr
+				},
+				NormalizeError,
+			)
+			if innerXErr != nil {
+				return innerXErr
+			}
+
+			switch img.Status {
+			case images.ImageStatusActive:
+				return nil
+			case images.ImageStatusKilled, images.ImageStatusDeleted, images.ImageStatusPendingDelete, images.ImageStatusDeactivated:
+				return retry.StopRetryError(fail.NewError("image '%s' is in state '%s'", request.Name, img.Status))
+			default:
+				return fail.NotAvailableError("image '%s' not ready yet (state '%s')", request.Name, img.Status)
+			}
+		},
+		temporal.GetDefaultDelay(),
+		temporal.GetLongOperationTimeout(),
+	)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *retry.ErrStopRetry, *retry.ErrTimeout:
+			return nullAI, fail.Wrap(fail.Cause(xerr), "failed to create image '%s'", request.Name)
+		default:
+			return nullAI, xerr
+		}
+	}
+
+	ai := abstract.NewImage()
+	ai.ID = img.ID
+	ai.Name = img.Name
+	ai.Description = request.Description
+	ai.HostID = request.HostID
+	ai.DiskSize = int64(img.MinDiskGigabytes)
+	return ai, nil
+}
+
+// DeleteImage deletes the custom image identified by id
+func (s Stack) DeleteImage(id string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("Stack.openstack") || tracing.ShouldTrace("stacks.compute"), "(%s)", id).WithStopwatch().Entering().Exiting()
+
+	xerr := stacks.RetryableRemoteCall(
+		func() error {
+			return images.Delete(s.ComputeClient, id).ExtractErr()
+		},
+		NormalizeError,
+	)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			return abstract.ResourceNotFoundError("image", id)
+		default:
+			return xerr
+		}
+	}
+	return nil
+}
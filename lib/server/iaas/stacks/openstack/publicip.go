@@ -0,0 +1,162 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package openstack
+
+import (
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/floatingips"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// toAbstractPublicIP converts a floating IP to *abstract.PublicIP
+func toAbstractPublicIP(fip floatingips.FloatingIP) *abstract.PublicIP {
+	pip := abstract.NewPublicIP()
+	pip.ID = fip.ID
+	pip.Type = fip.Pool
+	pip.IPAddress = fip.IP
+	pip.HostID = fip.InstanceID
+	return pip
+}
+
+// CreatePublicIP allocates a floating IP not bound to any host
+// request.Type, if set, designates the pool of floating IPs to use; tenant setting FloatingIPPool is used otherwise
+// OpenStack floating IPs have no name, so request.Name and request.Description are only kept in SafeScale metadata
+func (s Stack) CreatePublicIP(request abstract.PublicIPRequest) (*abstract.PublicIP, fail.Error) {
+	nullPIP := abstract.NewPublicIP()
+	if s.IsNull() {
+		return nullPIP, fail.InvalidInstanceError()
+	}
+	if request.Name == "" {
+		return nullPIP, fail.InvalidParameterCannotBeEmptyStringError("request.Name")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.openstack") || tracing.ShouldTrace("stacks.network"), "(%s)", request.Name).WithStopwatch().Entering().Exiting()
+
+	pool := request.Type
+	if pool == "" {
+		pool = s.authOpts.FloatingIPPool
+	}
+	fip, xerr := s.rpcCreateFloatingIPFromPool(pool)
+	if xerr != nil {
+		return nullPIP, xerr
+	}
+
+	pip := toAbstractPublicIP(*fip)
+	pip.Name = request.Name
+	pip.Description = request.Description
+	return pip, nil
+}
+
+// InspectPublicIP returns the floating IP identified by id
+func (s Stack) InspectPublicIP(id string) (*abstract.PublicIP, fail.Error) {
+	nullPIP := abstract.NewPublicIP()
+	if s.IsNull() {
+		return nullPIP, fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return nullPIP, fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.openstack") || tracing.ShouldTrace("stacks.network"), "(%s)", id).WithStopwatch().Entering().Exiting()
+
+	fip, xerr := s.rpcGetFloatingIP(id)
+	if xerr != nil {
+		return nullPIP, xerr
+	}
+	return toAbstractPublicIP(*fip), nil
+}
+
+// ListPublicIPs lists the floating IPs of the project
+func (s Stack) ListPublicIPs() ([]*abstract.PublicIP, fail.Error) {
+	var emptySlice []*abstract.PublicIP
+	if s.IsNull() {
+		return emptySlice, fail.InvalidInstanceError()
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.openstack") || tracing.ShouldTrace("stacks.network")).WithStopwatch().Entering().Exiting()
+
+	list, xerr := s.rpcListFloatingIPs()
+	if xerr != nil {
+		return emptySlice, xerr
+	}
+
+	out := make([]*abstract.PublicIP, 0, len(list))
+	for _, v := range list {
+		out = append(out, toAbstractPublicIP(v))
+	}
+	return out, nil
+}
+
+// DeletePublicIP releases the floating IP identified by id
+func (s Stack) DeletePublicIP(id string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.openstack") || tracing.ShouldTrace("stacks.network"), "(%s)", id).WithStopwatch().Entering().Exiting()
+
+	return s.rpcDeleteFloatingIP(id)
+}
+
+// BindPublicIPToHost associates the floating IP to a host
+func (s Stack) BindPublicIPToHost(pip *abstract.PublicIP, hostParam stacks.HostParameter) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if pip == nil {
+		return fail.InvalidParameterCannotBeNilError("pip")
+	}
+	ahf, hostLabel, xerr := stacks.ValidateHostParameter(hostParam)
+	if xerr != nil {
+		return xerr
+	}
+	if ahf.Core.ID == "" {
+		return fail.InvalidParameterError("hostParam", "must contain the ID of the host")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.openstack") || tracing.ShouldTrace("stacks.network"), "(%s, %s)", pip.IPAddress, hostLabel).WithStopwatch().Entering().Exiting()
+
+	return s.rpcAssociateFloatingIP(ahf.Core.ID, pip.IPAddress)
+}
+
+// UnbindPublicIPFromHost dissociates the floating IP from a host
+func (s Stack) UnbindPublicIPFromHost(pip *abstract.PublicIP, hostParam stacks.HostParameter) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if pip == nil {
+		return fail.InvalidParameterCannotBeNilError("pip")
+	}
+	ahf, hostLabel, xerr := stacks.ValidateHostParameter(hostParam)
+	if xerr != nil {
+		return xerr
+	}
+	if ahf.Core.ID == "" {
+		return fail.InvalidParameterError("hostParam", "must contain the ID of the host")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stack.openstack") || tracing.ShouldTrace("stacks.network"), "(%s, %s)", pip.IPAddress, hostLabel).WithStopwatch().Entering().Exiting()
+
+	return s.rpcDissociateFloatingIP(ahf.Core.ID, pip.IPAddress)
+}
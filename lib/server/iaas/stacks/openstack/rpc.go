@@ -160,7 +160,7 @@ func (s Stack) rpcListServers() ([]*servers.Server, fail.Error) {
 }
 
 // rpcCreateServer calls openstack to create a server
-func (s Stack) rpcCreateServer(name string, networks []servers.Network, templateID, imageID string, userdata []byte, az string) (*servers.Server, fail.Error) {
+func (s Stack) rpcCreateServer(name string, networks []servers.Network, templateID, imageID string, userdata []byte, az string, metadata map[string]string) (*servers.Server, fail.Error) {
 	nullServer := &servers.Server{}
 	if name = strings.TrimSpace(name); name == "" {
 		return nullServer, fail.InvalidParameterCannotBeEmptyStringError("name")
@@ -182,6 +182,7 @@ func (s Stack) rpcCreateServer(name string, networks []servers.Network, template
 		ImageRef:         imageID,
 		UserData:         userdata,
 		AvailabilityZone: az,
+		Metadata:         metadata,
 	}
 
 	var server *servers.Server
@@ -306,11 +307,16 @@ func (s Stack) rpcGetPort(id string) (port *ports.Port, xerr fail.Error) {
 
 // rpcCreateFloatingIP creates a floating IP
 func (s Stack) rpcCreateFloatingIP() (*floatingips.FloatingIP, fail.Error) {
+	return s.rpcCreateFloatingIPFromPool(s.authOpts.FloatingIPPool)
+}
+
+// rpcCreateFloatingIPFromPool creates a floating IP taken from the pool 'pool'
+func (s Stack) rpcCreateFloatingIPFromPool(pool string) (*floatingips.FloatingIP, fail.Error) {
 	var resp *floatingips.FloatingIP
 	xerr := stacks.RetryableRemoteCall(
 		func() (innerErr error) {
 			resp, innerErr = floatingips.Create(s.ComputeClient, floatingips.CreateOpts{
-				Pool: s.authOpts.FloatingIPPool,
+				Pool: pool,
 			}).Extract()
 			return innerErr
 		},
@@ -322,6 +328,87 @@ func (s Stack) rpcCreateFloatingIP() (*floatingips.FloatingIP, fail.Error) {
 	return resp, nil
 }
 
+// rpcGetFloatingIP returns the floating IP identified by id
+func (s Stack) rpcGetFloatingIP(id string) (*floatingips.FloatingIP, fail.Error) {
+	if id == "" {
+		return &floatingips.FloatingIP{}, fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	var resp *floatingips.FloatingIP
+	xerr := stacks.RetryableRemoteCall(
+		func() (innerErr error) {
+			resp, innerErr = floatingips.Get(s.ComputeClient, id).Extract()
+			return innerErr
+		},
+		NormalizeError,
+	)
+	if xerr != nil {
+		return &floatingips.FloatingIP{}, xerr
+	}
+	return resp, nil
+}
+
+// rpcListFloatingIPs returns all the floating IPs of the project
+func (s Stack) rpcListFloatingIPs() ([]floatingips.FloatingIP, fail.Error) {
+	var out []floatingips.FloatingIP
+	xerr := stacks.RetryableRemoteCall(
+		func() error {
+			out = []floatingips.FloatingIP{}
+			return floatingips.List(s.ComputeClient).EachPage(func(page pagination.Page) (bool, error) {
+				list, err := floatingips.ExtractFloatingIPs(page)
+				if err != nil {
+					return false, err
+				}
+				out = append(out, list...)
+				return true, nil
+			})
+		},
+		NormalizeError,
+	)
+	if xerr != nil {
+		return []floatingips.FloatingIP{}, xerr
+	}
+	return out, nil
+}
+
+// rpcAssociateFloatingIP associates the floating IP address 'ip' to the server identified by 'serverID'
+func (s Stack) rpcAssociateFloatingIP(serverID, ip string) fail.Error {
+	if serverID == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("serverID")
+	}
+	if ip == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("ip")
+	}
+
+	return stacks.RetryableRemoteCall(
+		func() error {
+			return floatingips.AssociateInstance(s.ComputeClient, serverID, floatingips.AssociateOpts{
+				FloatingIP: ip,
+			}).ExtractErr()
+		},
+		NormalizeError,
+	)
+}
+
+// rpcDissociateFloatingIP dissociates the floating IP address 'ip' from the server identified by 'serverID'
+func (s Stack) rpcDissociateFloatingIP(serverID, ip string) fail.Error {
+	if serverID == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("serverID")
+	}
+	if ip == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("ip")
+	}
+
+	return stacks.RetryableRemoteCall(
+		func() error {
+			return floatingips.DisassociateInstance(s.ComputeClient, serverID, floatingips.DisassociateOpts{
+				FloatingIP: ip,
+			}).ExtractErr()
+		},
+		NormalizeError,
+	)
+}
+
 // rpcDeleteFloatingIP deletes a floating IP
 func (s Stack) rpcDeleteFloatingIP(id string) fail.Error {
 	if id == "" {
@@ -0,0 +1,292 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package openstack
+
+import (
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	volumesv1 "github.com/gophercloud/gophercloud/openstack/blockstorage/v1/volumes"
+	snapshotsv2 "github.com/gophercloud/gophercloud/openstack/blockstorage/v2/snapshots"
+	volumesv2 "github.com/gophercloud/gophercloud/openstack/blockstorage/v2/volumes"
+	"github.com/gophercloud/gophercloud/pagination"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// toAbstractSnapshot converts an OpenStack snapshot to *abstract.Snapshot
+func toAbstractSnapshot(snap snapshotsv2.Snapshot) *abstract.Snapshot {
+	as := abstract.NewSnapshot()
+	as.ID = snap.ID
+	as.Name = snap.Name
+	as.Description = snap.Description
+	as.VolumeID = snap.VolumeID
+	as.Size = snap.Size
+	as.State = toVolumeState(snap.Status)
+	as.CreatedAt = snap.CreatedAt
+	return as
+}
+
+// CreateVolumeSnapshot takes a snapshot of a volume
+// Note: the snapshot is forced, so a volume attached to a host can be snapshotted; it is up to the caller to ensure data consistency
+func (s Stack) CreateVolumeSnapshot(request abstract.SnapshotRequest) (*abstract.Snapshot, fail.Error) {
+	nullAS := abstract.NewSnapshot()
+	if s.IsNull() {
+		return nullAS, fail.InvalidInstanceError()
+	}
+	if request.Name == "" {
+		return nullAS, fail.InvalidParameterCannotBeEmptyStringError("request.Name")
+	}
+	if request.VolumeID == "" {
+		return nullAS, fail.InvalidParameterCannotBeEmptyStringError("request.VolumeID")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("Stack.volume"), "(%s, %s)", request.VolumeID, request.Name).WithStopwatch().Entering().Exiting()
+
+	opts := snapshotsv2.CreateOpts{
+		VolumeID:    request.VolumeID,
+		Force:       true,
+		Name:        request.Name,
+		Description: request.Description,
+	}
+	var snap *snapshotsv2.Snapshot
+	xerr := stacks.RetryableRemoteCall(
+		func() (innerErr error) {
+			snap, innerErr = snapshotsv2.Create(s.VolumeClient, opts).Extract()
+			return innerErr
+		},
+		NormalizeError,
+	)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			return nullAS, abstract.ResourceNotFoundError("volume", request.VolumeID)
+		default:
+			return nullAS, xerr
+		}
+	}
+	if snap == nil {
+		return nullAS, fail.InconsistentError("snapshot creation seems to have succeeded, but returned nil value is unexpected")
+	}
+
+	return toAbstractSnapshot(*snap), nil
+}
+
+// InspectVolumeSnapshot returns the volume snapshot identified by id
+func (s Stack) InspectVolumeSnapshot(id string) (*abstract.Snapshot, fail.Error) {
+	nullAS := abstract.NewSnapshot()
+	if s.IsNull() {
+		return nullAS, fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return nullAS, fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("Stack.volume"), "(%s)", id).WithStopwatch().Entering().Exiting()
+
+	var snap *snapshotsv2.Snapshot
+	xerr := stacks.RetryableRemoteCall(
+		func() (innerErr error) {
+			snap, innerErr = snapshotsv2.Get(s.VolumeClient, id).Extract()
+			return innerErr
+		},
+		NormalizeError,
+	)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			return nullAS, abstract.ResourceNotFoundError("snapshot", id)
+		default:
+			return nullAS, xerr
+		}
+	}
+
+	return toAbstractSnapshot(*snap), nil
+}
+
+// ListVolumeSnapshots lists the snapshots of the volume identified by volumeID (all the snapshots if volumeID is empty)
+func (s Stack) ListVolumeSnapshots(volumeID string) ([]*abstract.Snapshot, fail.Error) {
+	var emptySlice []*abstract.Snapshot
+	if s.IsNull() {
+		return emptySlice, fail.InvalidInstanceError()
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("Stack.volume"), "(%s)", volumeID).WithStopwatch().Entering().Exiting()
+
+	var out []*abstract.Snapshot
+	xerr := stacks.RetryableRemoteCall(
+		func() error {
+			out = []*abstract.Snapshot{} // If call fails, need to restart list from 0...
+			return snapshotsv2.List(s.VolumeClient, snapshotsv2.ListOpts{VolumeID: volumeID}).EachPage(func(page pagination.Page) (bool, error) {
+				list, err := snapshotsv2.ExtractSnapshots(page)
+				if err != nil {
+					logrus.Errorf("Error listing volume snapshots: snapshot extraction: %+v", err)
+					return false, err
+				}
+				for _, snap := range list {
+					out = append(out, toAbstractSnapshot(snap))
+				}
+				return true, nil
+			})
+		},
+		NormalizeError,
+	)
+	if xerr != nil {
+		return emptySlice, xerr
+	}
+
+	return out, nil
+}
+
+// DeleteVolumeSnapshot deletes the volume snapshot identified by id
+func (s Stack) DeleteVolumeSnapshot(id string) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if id = strings.TrimSpace(id); id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("Stack.volume"), "(%s)", id).WithStopwatch().Entering().Exiting()
+
+	xerr = stacks.RetryableRemoteCall(
+		func() error {
+			return snapshotsv2.Delete(s.VolumeClient, id).ExtractErr()
+		},
+		NormalizeError,
+	)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			return abstract.ResourceNotFoundError("snapshot", id)
+		default:
+			return xerr
+		}
+	}
+	return nil
+}
+
+// CreateVolumeFromSnapshot creates a new volume filled with the content of the snapshot identified by snapshotID
+// If request.Size is 0, the size of the snapshot is used
+func (s Stack) CreateVolumeFromSnapshot(snapshotID string, request abstract.VolumeRequest) (*abstract.Volume, fail.Error) {
+	nullAV := abstract.NewVolume()
+	if s.IsNull() {
+		return nullAV, fail.InvalidInstanceError()
+	}
+	if snapshotID == "" {
+		return nullAV, fail.InvalidParameterCannotBeEmptyStringError("snapshotID")
+	}
+	if request.Name == "" {
+		return nullAV, fail.InvalidParameterCannotBeEmptyStringError("request.Name")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("Stack.volume"), "(%s, %s)", snapshotID, request.Name).WithStopwatch().Entering().Exiting()
+
+	snap, xerr := s.InspectVolumeSnapshot(snapshotID)
+	if xerr != nil {
+		return nullAV, xerr
+	}
+	if request.Size == 0 {
+		request.Size = snap.Size
+	}
+	if request.Size < snap.Size {
+		return nullAV, fail.InvalidRequestError("cannot restore snapshot '%s' of %d GB in a volume of %d GB", snap.Name, snap.Size, request.Size)
+	}
+
+	az, xerr := s.SelectedAvailabilityZone()
+	if xerr != nil {
+		return nullAV, xerr
+	}
+
+	var v abstract.Volume
+	switch s.versions["volume"] {
+	case "v1":
+		var vol *volumesv1.Volume
+		opts := volumesv1.CreateOpts{
+			AvailabilityZone: az,
+			Name:             request.Name,
+			Size:             request.Size,
+			VolumeType:       s.getVolumeType(request.Speed),
+			SnapshotID:       snapshotID,
+		}
+		xerr = stacks.RetryableRemoteCall(
+			func() (innerErr error) {
+				vol, innerErr = volumesv1.Create(s.VolumeClient, opts).Extract()
+				return innerErr
+			},
+			NormalizeError,
+		)
+		if xerr != nil {
+			break
+		}
+		if vol == nil {
+			xerr = fail.InconsistentError("volume creation seems to have succeeded, but returned nil value is unexpected")
+			break
+		}
+		v = abstract.Volume{
+			ID:    vol.ID,
+			Name:  vol.Name,
+			Size:  vol.Size,
+			Speed: s.getVolumeSpeed(vol.VolumeType),
+			State: toVolumeState(vol.Status),
+		}
+	case "v2":
+		var vol *volumesv2.Volume
+		opts := volumesv2.CreateOpts{
+			AvailabilityZone: az,
+			Name:             request.Name,
+			Size:             request.Size,
+			VolumeType:       s.getVolumeType(request.Speed),
+			SnapshotID:       snapshotID,
+		}
+		xerr = stacks.RetryableRemoteCall(
+			func() (innerErr error) {
+				vol, innerErr = volumesv2.Create(s.VolumeClient, opts).Extract()
+				return innerErr
+			},
+			NormalizeError,
+		)
+		if xerr != nil {
+			break
+		}
+		if vol == nil {
+			xerr = fail.InconsistentError("volume creation seems to have succeeded, but returned nil value is unexpected")
+			break
+		}
+		v = abstract.Volume{
+			ID:    vol.ID,
+			Name:  vol.Name,
+			Size:  vol.Size,
+			Speed: s.getVolumeSpeed(vol.VolumeType),
+			State: toVolumeState(vol.Status),
+		}
+	default:
+		xerr = fail.NotImplementedError("unmanaged service 'volume' version '%s'", s.versions["volume"])
+	}
+	if xerr != nil {
+		return nullAV, xerr
+	}
+
+	return &v, nil
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/extensions/volumeactions"
 	volumesv1 "github.com/gophercloud/gophercloud/openstack/blockstorage/v1/volumes"
 	volumesv2 "github.com/gophercloud/gophercloud/openstack/blockstorage/v2/volumes"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/extensions/volumeattach"
@@ -113,6 +114,7 @@ func (s Stack) CreateVolume(request abstract.VolumeRequest) (volume *abstract.Vo
 			Name:             request.Name,
 			Size:             request.Size,
 			VolumeType:       s.getVolumeType(request.Speed),
+			Metadata:         request.Labels,
 		}
 		xerr = stacks.RetryableRemoteCall(
 			func() (innerErr error) {
@@ -141,6 +143,7 @@ func (s Stack) CreateVolume(request abstract.VolumeRequest) (volume *abstract.Vo
 			Name:             request.Name,
 			Size:             request.Size,
 			VolumeType:       s.getVolumeType(request.Speed),
+			Metadata:         request.Labels,
 		}
 		var vol *volumesv2.Volume
 		xerr = stacks.RetryableRemoteCall(
@@ -239,6 +242,7 @@ func (s Stack) ListVolumes() ([]abstract.Volume, fail.Error) {
 						Size:  vol.Size,
 						Speed: s.getVolumeSpeed(vol.VolumeType),
 						State: toVolumeState(vol.Status),
+						Tags:  vol.Metadata,
 					}
 					vs = append(vs, av)
 				}
@@ -301,6 +305,80 @@ func (s Stack) DeleteVolume(id string) (xerr fail.Error) {
 	return nil
 }
 
+// ResizeVolume grows the volume identified by id to newSize GB, and waits for the end of the extension
+// Extension of an attached volume needs the microversion 3.42 of the Block Storage API; if the cloud does not support it,
+// the volume has to be detached first
+func (s Stack) ResizeVolume(id string, newSize int) (_ *abstract.Volume, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	nullAV := abstract.NewVolume()
+	if s.IsNull() {
+		return nullAV, fail.InvalidInstanceError()
+	}
+	if id = strings.TrimSpace(id); id == "" {
+		return nullAV, fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("Stack.volume"), "(%s, %d)", id, newSize).WithStopwatch().Entering().Exiting()
+
+	av, xerr := s.InspectVolume(id)
+	if xerr != nil {
+		return nullAV, xerr
+	}
+	if newSize <= av.Size {
+		return nullAV, fail.InvalidParameterError("newSize", "must be greater than the current size of the volume (%d GB)", av.Size)
+	}
+
+	client := s.VolumeClient
+	if av.State == volumestate.Used {
+		inUseClient := *s.VolumeClient
+		inUseClient.Microversion = "3.42"
+		client = &inUseClient
+	}
+	xerr = stacks.RetryableRemoteCall(
+		func() error {
+			return volumeactions.ExtendSize(client, id, volumeactions.ExtendSizeOpts{NewSize: newSize}).ExtractErr()
+		},
+		NormalizeError,
+	)
+	if xerr != nil {
+		return nullAV, fail.Wrap(xerr, "failed to extend volume '%s'", av.Name)
+	}
+
+	xerr = retry.WhileUnsuccessful(
+		func() error {
+			var innerXErr fail.Error
+			av, innerXErr = s.InspectVolume(id)
+			if innerXErr != nil {
+				return innerXErr
+			}
+
+			switch av.State {
+			case volumestate.Error:
+				return retry.StopRetryError(fail.NewError("volume '%s' is in error after extension", av.Name))
+			case volumestate.Available, volumestate.Used:
+				if av.Size >= newSize {
+					return nil
+				}
+			}
+			return fail.NotAvailableError("volume '%s' is still being extended", av.Name)
+		},
+		temporal.GetDefaultDelay(),
+		temporal.GetOperationTimeout(),
+	)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrTimeout:
+			return nullAV, fail.Wrap(fail.Cause(xerr), "timeout")
+		case *retry.ErrStopRetry:
+			return nullAV, fail.Wrap(fail.Cause(xerr), "stopping retries")
+		default:
+			return nullAV, xerr
+		}
+	}
+	return av, nil
+}
+
 // CreateVolumeAttachment attaches a volume to an host
 // - 'name' of the volume attachment
 // - 'volume' to attach
@@ -962,9 +962,13 @@ func (s stack) CreateHost(request abstract.HostRequest) (ahf *abstract.HostFull,
 	if xerr = s.addGPUs(&request, tpl, vm.VmId); xerr != nil {
 		return nullAHF, nullUDC, xerr
 	}
-	_, xerr = s.rpcCreateTags(vm.VmId, map[string]string{
+	tags := map[string]string{
 		"name": request.ResourceName,
-	})
+	}
+	for k, v := range request.Labels {
+		tags[k] = v
+	}
+	_, xerr = s.rpcCreateTags(vm.VmId, tags)
 	if xerr != nil {
 		return nullAHF, nullUDC, xerr
 	}
@@ -1165,6 +1169,7 @@ func (s stack) ListHosts(details bool) (_ abstract.HostList, xerr fail.Error) {
 				ahf.Core.Name = tag
 			}
 		}
+		ahf.Core.Tags = unwrapTags(vm.Tags)
 		hosts = append(hosts, ahf)
 	}
 	return hosts, nil
@@ -0,0 +1,142 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package outscale
+
+import (
+	"github.com/outscale/osc-sdk-go/osc"
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/retry"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+// CreateImage creates an OMI from the VM identified by request.HostID
+// Note: the VM is shut down during the creation of the OMI, then rebooted, to ensure file system consistency
+func (s stack) CreateImage(request abstract.ImageRequest) (_ *abstract.Image, ferr fail.Error) {
+	nullAI := abstract.NewImage()
+	if s.IsNull() {
+		return nullAI, fail.InvalidInstanceError()
+	}
+	if request.Name == "" {
+		return nullAI, fail.InvalidParameterCannotBeEmptyStringError("request.Name")
+	}
+	if request.HostID == "" {
+		return nullAI, fail.InvalidParameterCannotBeEmptyStringError("request.HostID")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stacks.compute") || tracing.ShouldTrace("stack.outscale"), "(%s, %s)", request.HostID, request.Name).WithStopwatch().Entering().Exiting()
+
+	resp, xerr := s.rpcCreateImage(request.Name, request.HostID, request.Description)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			return nullAI, abstract.ResourceNotFoundError("host", request.HostID)
+		default:
+			return nullAI, xerr
+		}
+	}
+
+	defer func() {
+		if ferr != nil {
+			if derr := s.DeleteImage(resp.ImageId); derr != nil {
+				logrus.Errorf("cleaning up on failure, failed to delete Image '%s': %v", request.Name, derr)
+				_ = ferr.AddConsequence(derr)
+			}
+		}
+	}()
+
+	var img osc.Image
+	xerr = retry.WhileUnsuccessful(
+		func() error {
+			var innerXErr fail.Error
+			img, innerXErr = s.rpcReadImageByID(resp.ImageId)
+			if innerXErr != nil {
+				return innerXErr
+			}
+
+			switch img.State {
+			case "available":
+				return nil
+			case "failed":
+				return retry.StopRetryError(fail.NewError("Image '%s' is in state 'failed': %s", request.Name, img.StateComment.StateMessage))
+			default:
+				return fail.NotAvailableError("Image '%s' not ready yet (state '%s')", request.Name, img.State)
+			}
+		},
+		temporal.GetDefaultDelay(),
+		temporal.GetLongOperationTimeout(),
+	)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *retry.ErrStopRetry, *retry.ErrTimeout:
+			return nullAI, fail.Wrap(fail.Cause(xerr), "failed to create Image '%s'", request.Name)
+		default:
+			return nullAI, xerr
+		}
+	}
+
+	ai := abstract.NewImage()
+	*ai = toAbstractImage(img)
+	ai.HostID = request.HostID
+	for _, v := range img.BlockDeviceMappings {
+		if v.DeviceName == img.RootDeviceName {
+			ai.DiskSize = int64(v.Bsu.VolumeSize)
+		}
+	}
+	return ai, nil
+}
+
+// DeleteImage deletes the OMI identified by id and the snapshots backing it
+func (s stack) DeleteImage(id string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	defer debug.NewTracer(nil, tracing.ShouldTrace("stacks.compute") || tracing.ShouldTrace("stack.outscale"), "(%s)", id).WithStopwatch().Entering().Exiting()
+
+	img, xerr := s.rpcReadImageByID(id)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			return abstract.ResourceNotFoundError("image", id)
+		default:
+			return xerr
+		}
+	}
+
+	if xerr = s.rpcDeleteImage(id); xerr != nil {
+		return xerr
+	}
+
+	// the snapshots are not deleted with the OMI, and would be charged for nothing
+	for _, v := range img.BlockDeviceMappings {
+		if v.Bsu.SnapshotId == "" {
+			continue
+		}
+		if xerr = s.rpcDeleteSnapshot(v.Bsu.SnapshotId); xerr != nil {
+			logrus.Warnf("failed to delete Snapshot '%s' of Image '%s': %v", v.Bsu.SnapshotId, id, xerr)
+		}
+	}
+	return nil
+}
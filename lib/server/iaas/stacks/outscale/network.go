@@ -95,6 +95,12 @@ func (s stack) CreateNetwork(req abstract.NetworkRequest) (an *abstract.Network,
 		return nullAN, fail.Wrap(xerr, "failed to create Internet Service of Network/VPC")
 	}
 
+	if len(req.Labels) > 0 {
+		if _, xerr = s.rpcCreateTags(resp.NetId, req.Labels); xerr != nil {
+			return nullAN, fail.Wrap(xerr, "failed to set labels of Network/VPC")
+		}
+	}
+
 	return toAbstractNetwork(resp), nil
 }
 
@@ -241,6 +247,7 @@ func toAbstractNetwork(in osc.Net) *abstract.Network {
 	if name, ok := tags[tagNameLabel]; ok {
 		out.Name = name
 	}
+	out.Tags = tags
 	return out
 }
 
@@ -0,0 +1,180 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package outscale
+
+import (
+	"github.com/outscale/osc-sdk-go/osc"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// toAbstractPublicIP converts an osc.PublicIp to *abstract.PublicIP
+func toAbstractPublicIP(ip osc.PublicIp) *abstract.PublicIP {
+	pip := abstract.NewPublicIP()
+	pip.ID = ip.PublicIpId
+	pip.Name = getResourceTag(ip.Tags, tagNameLabel, "")
+	pip.Description = getResourceTag(ip.Tags, "description", "")
+	pip.IPAddress = ip.PublicIp
+	pip.HostID = ip.VmId
+	return pip
+}
+
+// CreatePublicIP allocates a public IP not bound to any host
+func (s stack) CreatePublicIP(request abstract.PublicIPRequest) (_ *abstract.PublicIP, xerr fail.Error) {
+	nullPIP := abstract.NewPublicIP()
+	if s.IsNull() {
+		return nullPIP, fail.InvalidInstanceError()
+	}
+	if request.Name == "" {
+		return nullPIP, fail.InvalidParameterCannotBeEmptyStringError("request.Name")
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("stacks.outscale"), "(%s)", request.Name).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	ip, xerr := s.rpcCreatePublicIP()
+	if xerr != nil {
+		return nullPIP, xerr
+	}
+
+	defer func() {
+		if xerr != nil {
+			if derr := s.rpcDeletePublicIPByID(ip.PublicIpId); derr != nil {
+				_ = xerr.AddConsequence(fail.Wrap(derr, "cleaning up on failure, failed to delete Public IP %s", ip.PublicIpId))
+			}
+		}
+	}()
+
+	tags := map[string]string{tagNameLabel: request.Name}
+	if request.Description != "" {
+		tags["description"] = request.Description
+	}
+	if ip.Tags, xerr = s.rpcCreateTags(ip.PublicIpId, tags); xerr != nil {
+		return nullPIP, xerr
+	}
+
+	return toAbstractPublicIP(ip), nil
+}
+
+// InspectPublicIP returns the public IP identified by id
+func (s stack) InspectPublicIP(id string) (*abstract.PublicIP, fail.Error) {
+	nullPIP := abstract.NewPublicIP()
+	if s.IsNull() {
+		return nullPIP, fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return nullPIP, fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("stacks.outscale"), "(%s)", id).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	ip, xerr := s.rpcReadPublicIPByID(id)
+	if xerr != nil {
+		return nullPIP, xerr
+	}
+	return toAbstractPublicIP(ip), nil
+}
+
+// ListPublicIPs lists the public IPs of the account
+func (s stack) ListPublicIPs() ([]*abstract.PublicIP, fail.Error) {
+	var emptySlice []*abstract.PublicIP
+	if s.IsNull() {
+		return emptySlice, fail.InvalidInstanceError()
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("stacks.outscale")).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	list, xerr := s.rpcReadPublicIPs(nil)
+	if xerr != nil {
+		return emptySlice, xerr
+	}
+
+	out := make([]*abstract.PublicIP, 0, len(list))
+	for _, v := range list {
+		out = append(out, toAbstractPublicIP(v))
+	}
+	return out, nil
+}
+
+// DeletePublicIP releases the public IP identified by id
+func (s stack) DeletePublicIP(id string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("stacks.outscale"), "(%s)", id).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	return s.rpcDeletePublicIPByID(id)
+}
+
+// BindPublicIPToHost associates the public IP to a host
+func (s stack) BindPublicIPToHost(pip *abstract.PublicIP, hostParam stacks.HostParameter) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if pip == nil {
+		return fail.InvalidParameterCannotBeNilError("pip")
+	}
+	ahf, hostLabel, xerr := stacks.ValidateHostParameter(hostParam)
+	if xerr != nil {
+		return xerr
+	}
+	if ahf.Core.ID == "" {
+		return fail.InvalidParameterError("hostParam", "must contain the ID of the host")
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("stacks.outscale"), "(%s, %s)", pip.ID, hostLabel).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	return s.rpcLinkPublicIPToVM(pip.ID, ahf.Core.ID)
+}
+
+// UnbindPublicIPFromHost dissociates the public IP from a host
+func (s stack) UnbindPublicIPFromHost(pip *abstract.PublicIP, hostParam stacks.HostParameter) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if pip == nil {
+		return fail.InvalidParameterCannotBeNilError("pip")
+	}
+	ahf, hostLabel, xerr := stacks.ValidateHostParameter(hostParam)
+	if xerr != nil {
+		return xerr
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("stacks.outscale"), "(%s, %s)", pip.ID, hostLabel).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	ip, xerr := s.rpcReadPublicIPByID(pip.ID)
+	if xerr != nil {
+		return xerr
+	}
+	if ip.LinkPublicIpId == "" || (ahf.Core.ID != "" && ip.VmId != ahf.Core.ID) {
+		return fail.NotFoundError("Public IP '%s' is not linked to host %s", pip.ID, hostLabel)
+	}
+	return s.rpcUnlinkPublicIP(ip.LinkPublicIpId)
+}
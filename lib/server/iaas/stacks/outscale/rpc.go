@@ -17,7 +17,15 @@
 package outscale
 
 import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+
 	"github.com/antihax/optional"
+	awscredentials "github.com/aws/aws-sdk-go/aws/credentials"
+	awsv4 "github.com/aws/aws-sdk-go/aws/signer/v4"
 	"github.com/outscale/osc-sdk-go/osc"
 
 	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
@@ -912,12 +920,13 @@ func (s stack) rpcUpdateVMSecurityGroups(id string, sgs []string) fail.Error {
 	)
 }
 
-func (s stack) rpcCreateVolume(name string, size int32, iops int32, speed string) (_ osc.Volume, ferr fail.Error) {
+// rpcCreateVolume creates a volume; if snapshotID is not empty, the volume is filled with the content of the snapshot
+func (s stack) rpcCreateVolume(name string, size int32, iops int32, speed string, snapshotID string) (_ osc.Volume, ferr fail.Error) {
 	createVolumeOpts := osc.CreateVolumeOpts{
 		CreateVolumeRequest: optional.NewInterface(osc.CreateVolumeRequest{
 			Iops:          iops,
 			Size:          size,
-			SnapshotId:    "",
+			SnapshotId:    snapshotID,
 			SubregionName: s.Options.Compute.Subregion,
 			VolumeType:    speed,
 		}),
@@ -1072,6 +1081,66 @@ func (s stack) rpcDeleteVolume(id string) fail.Error {
 	)
 }
 
+// rpcUpdateVolumeSize changes the size of a volume
+// The version of osc-sdk-go in use does not provide UpdateVolume, so the OAPI call is built here the same way the SDK does
+func (s stack) rpcUpdateVolumeSize(id string, size int32) fail.Error {
+	if id == "" {
+		return fail.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	body, err := json.Marshal(struct {
+		VolumeId string `json:"VolumeId"` //nolint
+		Size     int32  `json:"Size"`
+	}{VolumeId: id, Size: size})
+	if err != nil {
+		return fail.ConvertError(err)
+	}
+
+	cfg := s.client.GetConfig()
+	endpoint, err := url.Parse(cfg.BasePath + "/UpdateVolume")
+	if err != nil {
+		return fail.ConvertError(err)
+	}
+	if cfg.Scheme != "" {
+		endpoint.Scheme = cfg.Scheme
+	}
+	auth, ok := s.auth.Value(osc.ContextAWSv4).(osc.AWSv4)
+	if !ok {
+		return fail.InconsistentError("missing AWSv4 credentials in Outscale stack")
+	}
+
+	return stacks.RetryableRemoteCall(
+		func() error {
+			request, err := http.NewRequest(http.MethodPost, endpoint.String(), bytes.NewReader(body))
+			if err != nil {
+				return err
+			}
+			request.Header.Set("Content-Type", "application/json")
+			request.Header.Set("Accept", "application/json")
+			request.Header.Set("User-Agent", cfg.UserAgent)
+
+			signer := awsv4.NewSigner(awscredentials.NewStaticCredentials(auth.AccessKey, auth.SecretKey, ""))
+			if _, err = signer.Sign(request, bytes.NewReader(body), "oapi", s.Options.Compute.Region, time.Now()); err != nil {
+				return err
+			}
+
+			resp, err := cfg.HTTPClient.Do(request)
+			if err != nil {
+				return err
+			}
+			if resp.StatusCode != http.StatusOK {
+				normalized, xerr := normalizeFromHTTPReturnCode(resp)
+				if xerr != nil {
+					return xerr
+				}
+				return normalized
+			}
+			return resp.Body.Close()
+		},
+		normalizeError,
+	)
+}
+
 func (s stack) rpcLinkVolume(volumeID, hostID, deviceName string) fail.Error {
 	if volumeID == "" {
 		return fail.InvalidParameterError("volumeID", "cannot be empty string")
@@ -1826,6 +1895,101 @@ func (s stack) rpcCreateVMs(request osc.CreateVmsRequest) ([]osc.Vm, fail.Error)
 	return resp.Vms, nil
 }
 
+func (s stack) rpcLinkPublicIPToVM(ipID, vmID string) fail.Error {
+	if ipID == "" {
+		return fail.InvalidParameterError("ipID", "cannot be empty string")
+	}
+	if vmID == "" {
+		return fail.InvalidParameterError("vmID", "cannot be empty string")
+	}
+
+	opts := osc.LinkPublicIpOpts{
+		LinkPublicIpRequest: optional.NewInterface(osc.LinkPublicIpRequest{
+			VmId:       vmID,
+			PublicIpId: ipID,
+		}),
+	}
+	return stacks.RetryableRemoteCall(
+		func() error {
+			_, hr, err := s.client.PublicIpApi.LinkPublicIp(s.auth, &opts)
+			if err != nil {
+				return newOutscaleError(hr, err)
+			}
+			return nil
+		},
+		normalizeError,
+	)
+}
+
+func (s stack) rpcUnlinkPublicIP(linkID string) fail.Error {
+	if linkID == "" {
+		return fail.InvalidParameterError("linkID", "cannot be empty string")
+	}
+
+	opts := osc.UnlinkPublicIpOpts{
+		UnlinkPublicIpRequest: optional.NewInterface(osc.UnlinkPublicIpRequest{
+			LinkPublicIpId: linkID,
+		}),
+	}
+	return stacks.RetryableRemoteCall(
+		func() error {
+			_, hr, err := s.client.PublicIpApi.UnlinkPublicIp(s.auth, &opts)
+			if err != nil {
+				return newOutscaleError(hr, err)
+			}
+			return nil
+		},
+		normalizeError,
+	)
+}
+
+func (s stack) rpcReadPublicIPs(ids []string) ([]osc.PublicIp, fail.Error) {
+	request := osc.ReadPublicIpsRequest{}
+	if len(ids) > 0 {
+		request.Filters = osc.FiltersPublicIp{PublicIpIds: ids}
+	}
+	opts := osc.ReadPublicIpsOpts{
+		ReadPublicIpsRequest: optional.NewInterface(request),
+	}
+	var resp osc.ReadPublicIpsResponse
+	xerr := stacks.RetryableRemoteCall(
+		func() error {
+			dr, hr, err := s.client.PublicIpApi.ReadPublicIps(s.auth, &opts)
+			if err != nil {
+				return newOutscaleError(hr, err)
+			}
+			resp = dr
+			return nil
+		},
+		normalizeError,
+	)
+	if xerr != nil {
+		return []osc.PublicIp{}, xerr
+	}
+	if len(resp.PublicIps) == 0 {
+		return []osc.PublicIp{}, nil
+	}
+	return resp.PublicIps, nil
+}
+
+func (s stack) rpcReadPublicIPByID(id string) (osc.PublicIp, fail.Error) {
+	if id == "" {
+		return osc.PublicIp{}, fail.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	resp, xerr := s.rpcReadPublicIPs([]string{id})
+	if xerr != nil {
+		return osc.PublicIp{}, xerr
+	}
+	if len(resp) == 0 {
+		return osc.PublicIp{}, fail.NotFoundError("failed to find Public IP with ID %s", id)
+	}
+	if len(resp) > 1 {
+		return osc.PublicIp{}, fail.InconsistentError("found more than one Public IP with ID %s", id)
+	}
+	return resp[0], nil
+}
+
 func (s stack) rpcReadPublicIPsOfVM(id string) ([]osc.PublicIp, fail.Error) {
 	if id == "" {
 		return []osc.PublicIp{}, fail.InvalidParameterError("id", "cannot be empty string")
@@ -1979,3 +2143,188 @@ func (s stack) rpcReadNicsOfVM(id string) ([]osc.Nic, fail.Error) {
 	}
 	return resp.Nics, nil
 }
+
+func (s stack) rpcCreateSnapshot(name, volumeID, description string) (_ osc.Snapshot, ferr fail.Error) {
+	if name == "" {
+		return osc.Snapshot{}, fail.InvalidParameterError("name", "cannot be empty string")
+	}
+	if volumeID == "" {
+		return osc.Snapshot{}, fail.InvalidParameterError("volumeID", "cannot be empty string")
+	}
+
+	opts := osc.CreateSnapshotOpts{
+		CreateSnapshotRequest: optional.NewInterface(osc.CreateSnapshotRequest{
+			VolumeId:    volumeID,
+			Description: description,
+		}),
+	}
+	var resp osc.CreateSnapshotResponse
+	xerr := stacks.RetryableRemoteCall(
+		func() error {
+			dr, hr, err := s.client.SnapshotApi.CreateSnapshot(s.auth, &opts)
+			if err != nil {
+				return newOutscaleError(hr, err)
+			}
+			resp = dr
+			return nil
+		},
+		normalizeError,
+	)
+	if xerr != nil {
+		return osc.Snapshot{}, xerr
+	}
+
+	defer func() {
+		if ferr != nil {
+			if derr := s.rpcDeleteSnapshot(resp.Snapshot.SnapshotId); derr != nil {
+				_ = ferr.AddConsequence(fail.Wrap(derr, "cleaning up on failure, failed to delete Snapshot '%s'", name))
+			}
+		}
+	}()
+
+	tags, xerr := s.rpcCreateTags(resp.Snapshot.SnapshotId, map[string]string{
+		tagNameLabel: name,
+	})
+	if xerr != nil {
+		return osc.Snapshot{}, xerr
+	}
+
+	resp.Snapshot.Tags = tags
+	return resp.Snapshot, nil
+}
+
+// rpcReadSnapshots returns the snapshots identified by ids; if volumeID is not empty, only the snapshots of this volume are returned
+func (s stack) rpcReadSnapshots(ids []string, volumeID string) ([]osc.Snapshot, fail.Error) {
+	var filters osc.FiltersSnapshot
+	if len(ids) > 0 {
+		filters.SnapshotIds = ids
+	}
+	if volumeID != "" {
+		filters.VolumeIds = []string{volumeID}
+	}
+	opts := osc.ReadSnapshotsOpts{
+		ReadSnapshotsRequest: optional.NewInterface(osc.ReadSnapshotsRequest{
+			Filters: filters,
+		}),
+	}
+	var resp osc.ReadSnapshotsResponse
+	xerr := stacks.RetryableRemoteCall(
+		func() error {
+			dr, hr, err := s.client.SnapshotApi.ReadSnapshots(s.auth, &opts)
+			if err != nil {
+				return newOutscaleError(hr, err)
+			}
+			resp = dr
+			return nil
+		},
+		normalizeError,
+	)
+	if xerr != nil {
+		return []osc.Snapshot{}, xerr
+	}
+	if len(resp.Snapshots) == 0 {
+		if len(ids) > 0 {
+			return []osc.Snapshot{}, fail.NotFoundError("failed to find Snapshots")
+		}
+		return []osc.Snapshot{}, nil
+	}
+
+	return resp.Snapshots, nil
+}
+
+func (s stack) rpcReadSnapshotByID(id string) (osc.Snapshot, fail.Error) {
+	if id == "" {
+		return osc.Snapshot{}, fail.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	resp, xerr := s.rpcReadSnapshots([]string{id}, "")
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			return osc.Snapshot{}, fail.NotFoundError("failed to find Snapshot with ID %s", id)
+		default:
+			return osc.Snapshot{}, xerr
+		}
+	}
+	if len(resp) > 1 {
+		return osc.Snapshot{}, fail.InconsistentError("found more than one Snapshot with ID %s", id)
+	}
+	return resp[0], nil
+}
+
+func (s stack) rpcDeleteSnapshot(id string) fail.Error {
+	if id == "" {
+		return fail.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	opts := osc.DeleteSnapshotOpts{
+		DeleteSnapshotRequest: optional.NewInterface(osc.DeleteSnapshotRequest{
+			SnapshotId: id,
+		}),
+	}
+	return stacks.RetryableRemoteCall(
+		func() error {
+			_, hr, err := s.client.SnapshotApi.DeleteSnapshot(s.auth, &opts)
+			if err != nil {
+				return newOutscaleError(hr, err)
+			}
+			return nil
+		},
+		normalizeError,
+	)
+}
+
+func (s stack) rpcCreateImage(name, vmID, description string) (osc.Image, fail.Error) {
+	if name == "" {
+		return osc.Image{}, fail.InvalidParameterError("name", "cannot be empty string")
+	}
+	if vmID == "" {
+		return osc.Image{}, fail.InvalidParameterError("vmID", "cannot be empty string")
+	}
+
+	opts := osc.CreateImageOpts{
+		CreateImageRequest: optional.NewInterface(osc.CreateImageRequest{
+			ImageName:   name,
+			VmId:        vmID,
+			Description: description,
+		}),
+	}
+	var resp osc.CreateImageResponse
+	xerr := stacks.RetryableRemoteCall(
+		func() error {
+			dr, hr, err := s.client.ImageApi.CreateImage(s.auth, &opts)
+			if err != nil {
+				return newOutscaleError(hr, err)
+			}
+			resp = dr
+			return nil
+		},
+		normalizeError,
+	)
+	if xerr != nil {
+		return osc.Image{}, xerr
+	}
+	return resp.Image, nil
+}
+
+func (s stack) rpcDeleteImage(id string) fail.Error {
+	if id == "" {
+		return fail.InvalidParameterError("id", "cannot be empty string")
+	}
+
+	opts := osc.DeleteImageOpts{
+		DeleteImageRequest: optional.NewInterface(osc.DeleteImageRequest{
+			ImageId: id,
+		}),
+	}
+	return stacks.RetryableRemoteCall(
+		func() error {
+			_, hr, err := s.client.ImageApi.DeleteImage(s.auth, &opts)
+			if err != nil {
+				return newOutscaleError(hr, err)
+			}
+			return nil
+		},
+		normalizeError,
+	)
+}
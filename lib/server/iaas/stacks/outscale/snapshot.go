@@ -0,0 +1,229 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package outscale
+
+import (
+	"github.com/outscale/osc-sdk-go/osc"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumespeed"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumestate"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// CreateVolumeSnapshot takes a snapshot of a volume
+func (s stack) CreateVolumeSnapshot(request abstract.SnapshotRequest) (_ *abstract.Snapshot, ferr fail.Error) {
+	nullAS := abstract.NewSnapshot()
+	if s.IsNull() {
+		return nullAS, fail.InvalidInstanceError()
+	}
+	if request.Name == "" {
+		return nullAS, fail.InvalidParameterCannotBeEmptyStringError("request.Name")
+	}
+	if request.VolumeID == "" {
+		return nullAS, fail.InvalidParameterCannotBeEmptyStringError("request.VolumeID")
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("stacks.outscale"), "(%v)", request).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	resp, xerr := s.rpcCreateSnapshot(request.Name, request.VolumeID, request.Description)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			return nullAS, abstract.ResourceNotFoundError("volume", request.VolumeID)
+		default:
+			return nullAS, xerr
+		}
+	}
+
+	return toAbstractSnapshot(resp), nil
+}
+
+// InspectVolumeSnapshot returns the snapshot identified by id
+func (s stack) InspectVolumeSnapshot(id string) (*abstract.Snapshot, fail.Error) {
+	nullAS := abstract.NewSnapshot()
+	if s.IsNull() {
+		return nullAS, fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return nullAS, fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("stacks.outscale"), "(%s)", id).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	resp, xerr := s.rpcReadSnapshotByID(id)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			return nullAS, abstract.ResourceNotFoundError("snapshot", id)
+		default:
+			return nullAS, xerr
+		}
+	}
+
+	return toAbstractSnapshot(resp), nil
+}
+
+// ListVolumeSnapshots lists the snapshots of the volume identified by volumeID (all the snapshots if volumeID is empty)
+func (s stack) ListVolumeSnapshots(volumeID string) ([]*abstract.Snapshot, fail.Error) {
+	var emptySlice []*abstract.Snapshot
+	if s.IsNull() {
+		return emptySlice, fail.InvalidInstanceError()
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("stacks.outscale"), "(%s)", volumeID).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	resp, xerr := s.rpcReadSnapshots(nil, volumeID)
+	if xerr != nil {
+		return emptySlice, xerr
+	}
+
+	out := make([]*abstract.Snapshot, 0, len(resp))
+	for _, v := range resp {
+		out = append(out, toAbstractSnapshot(v))
+	}
+	return out, nil
+}
+
+// DeleteVolumeSnapshot deletes the snapshot identified by id
+func (s stack) DeleteVolumeSnapshot(id string) fail.Error {
+	if s.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("stacks.outscale"), "(%s)", id).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	xerr := s.rpcDeleteSnapshot(id)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			return abstract.ResourceNotFoundError("snapshot", id)
+		default:
+			return xerr
+		}
+	}
+	return nil
+}
+
+// CreateVolumeFromSnapshot creates a new volume filled with the content of the snapshot identified by snapshotID
+// If request.Size is 0, the size of the snapshot is used
+func (s stack) CreateVolumeFromSnapshot(snapshotID string, request abstract.VolumeRequest) (_ *abstract.Volume, ferr fail.Error) {
+	nullAV := abstract.NewVolume()
+	if s.IsNull() {
+		return nullAV, fail.InvalidInstanceError()
+	}
+	if snapshotID == "" {
+		return nullAV, fail.InvalidParameterCannotBeEmptyStringError("snapshotID")
+	}
+	if request.Name == "" {
+		return nullAV, fail.InvalidParameterCannotBeEmptyStringError("request.Name")
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("stacks.outscale"), "(%s, %v)", snapshotID, request).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	snap, xerr := s.InspectVolumeSnapshot(snapshotID)
+	if xerr != nil {
+		return nullAV, xerr
+	}
+	if request.Size == 0 {
+		request.Size = snap.Size
+	}
+	if request.Size < snap.Size {
+		return nullAV, fail.InvalidRequestError("cannot restore snapshot '%s' of %d GB in a volume of %d GB", snap.Name, snap.Size, request.Size)
+	}
+
+	v, xerr := s.InspectVolumeByName(request.Name)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			debug.IgnoreError(xerr)
+		default:
+			return nullAV, xerr
+		}
+	} else if v != nil {
+		return nullAV, abstract.ResourceDuplicateError("volume", request.Name)
+	}
+
+	IOPS := 0
+	if request.Speed == volumespeed.Ssd {
+		IOPS = request.Size * 300
+		if IOPS > 13000 {
+			IOPS = 13000
+		}
+	}
+	resp, xerr := s.rpcCreateVolume(request.Name, int32(request.Size), int32(IOPS), s.fromAbstractVolumeSpeed(request.Speed), snapshotID)
+	if xerr != nil {
+		return nullAV, xerr
+	}
+
+	defer func() {
+		if ferr != nil {
+			if derr := s.rpcDeleteVolume(resp.VolumeId); derr != nil {
+				_ = ferr.AddConsequence(fail.Wrap(derr, "cleaning up on failure, failed to delete Volume"))
+			}
+		}
+	}()
+
+	xerr = s.WaitForVolumeState(resp.VolumeId, volumestate.Available)
+	if xerr != nil {
+		return nullAV, xerr
+	}
+
+	volume := abstract.NewVolume()
+	volume.ID = resp.VolumeId
+	volume.Speed = s.toAbstractVolumeSpeed(resp.VolumeType)
+	volume.Size = int(resp.Size)
+	volume.State = volumestate.Available
+	volume.Name = request.Name
+	return volume, nil
+}
+
+func toAbstractSnapshot(in osc.Snapshot) *abstract.Snapshot {
+	out := abstract.NewSnapshot()
+	out.ID = in.SnapshotId
+	out.Name = getResourceTag(in.Tags, tagNameLabel, in.SnapshotId)
+	out.Description = in.Description
+	out.VolumeID = in.VolumeId
+	out.Size = int(in.VolumeSize)
+	out.State = toAbstractSnapshotState(in.State)
+	return out
+}
+
+func toAbstractSnapshotState(state string) volumestate.Enum {
+	switch state {
+	case "in-queue", "pending":
+		return volumestate.Creating
+	case "completed":
+		return volumestate.Available
+	case "deleting":
+		return volumestate.Deleting
+	case "error":
+		return volumestate.Error
+	default:
+		return volumestate.Unknown
+	}
+}
@@ -60,7 +60,7 @@ func (s stack) CreateVolume(request abstract.VolumeRequest) (_ *abstract.Volume,
 			IOPS = 13000
 		}
 	}
-	resp, xerr := s.rpcCreateVolume(request.Name, int32(request.Size), int32(IOPS), s.fromAbstractVolumeSpeed(request.Speed))
+	resp, xerr := s.rpcCreateVolume(request.Name, int32(request.Size), int32(IOPS), s.fromAbstractVolumeSpeed(request.Speed), "")
 	if xerr != nil {
 		return nullAV, xerr
 	}
@@ -78,6 +78,12 @@ func (s stack) CreateVolume(request abstract.VolumeRequest) (_ *abstract.Volume,
 		return nullAV, xerr
 	}
 
+	if len(request.Labels) > 0 {
+		if _, xerr = s.rpcCreateTags(resp.VolumeId, request.Labels); xerr != nil {
+			return nullAV, xerr
+		}
+	}
+
 	volume := abstract.NewVolume()
 	volume.ID = resp.VolumeId
 	volume.Speed = s.toAbstractVolumeSpeed(resp.VolumeType)
@@ -224,6 +230,7 @@ func (s stack) ListVolumes() (_ []abstract.Volume, xerr fail.Error) {
 		volume.Size = int(ov.Size)
 		volume.State = toAbstractVolumeState(ov.State)
 		volume.Name = getResourceTag(ov.Tags, "name", "")
+		volume.Tags = unwrapTags(ov.Tags)
 		volumes = append(volumes, *volume)
 	}
 	return volumes, nil
@@ -244,6 +251,55 @@ func (s stack) DeleteVolume(id string) (xerr fail.Error) {
 	return s.rpcDeleteVolume(id)
 }
 
+// ResizeVolume grows the volume identified by id to newSize GB, and waits for the volume to have its new size
+func (s stack) ResizeVolume(id string, newSize int) (_ *abstract.Volume, xerr fail.Error) {
+	nullAV := abstract.NewVolume()
+	if s.IsNull() {
+		return nullAV, fail.InvalidInstanceError()
+	}
+	if id == "" {
+		return nullAV, fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("stacks.outscale"), "(%s, %d)", id, newSize).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	av, xerr := s.InspectVolume(id)
+	if xerr != nil {
+		return nullAV, xerr
+	}
+	if newSize <= av.Size {
+		return nullAV, fail.InvalidParameterError("newSize", "must be greater than the current size of the volume (%d GB)", av.Size)
+	}
+
+	if xerr = s.rpcUpdateVolumeSize(id, int32(newSize)); xerr != nil {
+		return nullAV, fail.Wrap(xerr, "failed to update the size of volume '%s'", av.Name)
+	}
+
+	xerr = retry.WhileUnsuccessfulWithHardTimeout(
+		func() error {
+			var innerXErr fail.Error
+			av, innerXErr = s.InspectVolume(id)
+			if innerXErr != nil {
+				return innerXErr
+			}
+			if av.State == volumestate.Error {
+				return retry.StopRetryError(fail.NewError("volume '%s' is in error after update", av.Name))
+			}
+			if av.Size < newSize {
+				return fail.NotAvailableError("volume '%s' is still being updated", av.Name)
+			}
+			return nil
+		},
+		temporal.GetDefaultDelay(),
+		temporal.GetOperationTimeout(),
+	)
+	if xerr != nil {
+		return nullAV, xerr
+	}
+	return av, nil
+}
+
 func freeDevice(usedDevices []string, device string) bool {
 	for _, usedDevice := range usedDevices {
 		if device == usedDevice {
@@ -90,6 +90,16 @@ func (s *stack) ListImages(all bool) (list []abstract.Image, xerr fail.Error) {
 	return list, nil
 }
 
+// CreateImage creates a custom image from an existing host
+func (s *stack) CreateImage(request abstract.ImageRequest) (*abstract.Image, fail.Error) {
+	return nil, fail.NotImplementedError("CreateImage() not implemented yet") // FIXME: Technical debt
+}
+
+// DeleteImage deletes a custom image
+func (s *stack) DeleteImage(id string) fail.Error {
+	return fail.NotImplementedError("DeleteImage() not implemented yet") // FIXME: Technical debt
+}
+
 // GetImage returns the Image referenced by id
 func (s *stack) GetImage(id string) (*abstract.Image, fail.Error) {
 	if s == nil {
@@ -27,6 +27,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/vmware/go-vcloud-director/types/v56"
 
+	"github.com/CS-SI/SafeScale/lib/server/iaas/stacks"
 	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
 	"github.com/CS-SI/SafeScale/lib/utils"
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
@@ -828,3 +829,27 @@ func (s *stack) UnbindHostFromVIP(ip *abstract.VirtualIP, s2 string) fail.Error
 func (s *stack) DeleteVIP(ip *abstract.VirtualIP) fail.Error {
 	return fail.NotImplementedError("DeleteVIP() not implemented yet") // FIXME: Technical debt
 }
+
+func (s *stack) CreatePublicIP(abstract.PublicIPRequest) (*abstract.PublicIP, fail.Error) {
+	return nil, fail.NotImplementedError("CreatePublicIP() not implemented yet") // FIXME: Technical debt
+}
+
+func (s *stack) InspectPublicIP(string) (*abstract.PublicIP, fail.Error) {
+	return nil, fail.NotImplementedError("InspectPublicIP() not implemented yet") // FIXME: Technical debt
+}
+
+func (s *stack) ListPublicIPs() ([]*abstract.PublicIP, fail.Error) {
+	return nil, fail.NotImplementedError("ListPublicIPs() not implemented yet") // FIXME: Technical debt
+}
+
+func (s *stack) DeletePublicIP(string) fail.Error {
+	return fail.NotImplementedError("DeletePublicIP() not implemented yet") // FIXME: Technical debt
+}
+
+func (s *stack) BindPublicIPToHost(*abstract.PublicIP, stacks.HostParameter) fail.Error {
+	return fail.NotImplementedError("BindPublicIPToHost() not implemented yet") // FIXME: Technical debt
+}
+
+func (s *stack) UnbindPublicIPFromHost(*abstract.PublicIP, stacks.HostParameter) fail.Error {
+	return fail.NotImplementedError("UnbindPublicIPFromHost() not implemented yet") // FIXME: Technical debt
+}
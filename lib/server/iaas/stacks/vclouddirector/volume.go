@@ -197,6 +197,30 @@ func (s *stack) DeleteVolume(ref string) fail.Error {
 	return normalizeError(err)
 }
 
+func (s *stack) CreateVolumeSnapshot(abstract.SnapshotRequest) (*abstract.Snapshot, fail.Error) {
+	return nil, fail.NotImplementedError("CreateVolumeSnapshot() not implemented yet") // FIXME: Technical debt
+}
+
+func (s *stack) InspectVolumeSnapshot(string) (*abstract.Snapshot, fail.Error) {
+	return nil, fail.NotImplementedError("InspectVolumeSnapshot() not implemented yet") // FIXME: Technical debt
+}
+
+func (s *stack) ListVolumeSnapshots(string) ([]*abstract.Snapshot, fail.Error) {
+	return nil, fail.NotImplementedError("ListVolumeSnapshots() not implemented yet") // FIXME: Technical debt
+}
+
+func (s *stack) DeleteVolumeSnapshot(string) fail.Error {
+	return fail.NotImplementedError("DeleteVolumeSnapshot() not implemented yet") // FIXME: Technical debt
+}
+
+func (s *stack) CreateVolumeFromSnapshot(string, abstract.VolumeRequest) (*abstract.Volume, fail.Error) {
+	return nil, fail.NotImplementedError("CreateVolumeFromSnapshot() not implemented yet") // FIXME: Technical debt
+}
+
+func (s *stack) ResizeVolume(string, int) (*abstract.Volume, fail.Error) {
+	return nil, fail.NotImplementedError("ResizeVolume() not implemented yet") // FIXME: Technical debt
+}
+
 func hash(s string) string {
 	h := fnv.New32a()
 	_, _ = h.Write([]byte(s))
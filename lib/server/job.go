@@ -22,6 +22,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/CS-SI/SafeScale/lib/server/audit"
 	"github.com/CS-SI/SafeScale/lib/server/iaas"
 	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
@@ -43,6 +44,13 @@ type Job interface {
 
 	Abort() fail.Error
 	Aborted() bool
+	Async() bool
+	Detach()
+	ReportProgress(ProgressEvent)
+	Status() JobStatus
+	Watch(from int) ([]ProgressEvent, <-chan struct{}, bool)
+	Done() <-chan struct{}
+	Finish(error)
 	Close()
 }
 
@@ -56,10 +64,17 @@ type job struct {
 	cancel      context.CancelFunc
 	service     iaas.Service
 	startTime   time.Time
+	async       bool
+	action      string
+	resource    string
+	references  []string    // references of the resources targeted by the request, recorded at job creation
+	history     *JobHistory // set if the job has to be kept in job history
+	progress    *jobProgress
 }
 
 var (
 	jobMap          = map[string]Job{}
+	finishedJobMap  = map[string]Job{}
 	mutexJobManager sync.Mutex
 )
 
@@ -100,6 +115,10 @@ func NewJob(ctx context.Context, cancel context.CancelFunc, svc iaas.Service, de
 		}
 	}
 
+	// attach job instance to the context before creating the task, to be reachable from the task and its subtasks
+	nj := &job{progress: newJobProgress()}
+	ctx = context.WithValue(ctx, keyForJobInContext, nj)
+
 	task, xerr := concurrency.NewTaskWithContext(ctx)
 	if xerr != nil {
 		return nil, xerr
@@ -112,23 +131,33 @@ func NewJob(ctx context.Context, cancel context.CancelFunc, svc iaas.Service, de
 	// attach task instance to the context
 	ctx = context.WithValue(ctx, concurrency.KeyForTaskInContext, task) // FIXME don't use string as key
 
-	nj := job{
-		description: description,
-		uuid:        id,
-		ctx:         ctx,
-		task:        task,
-		cancel:      cancel,
-		service:     svc,
-		startTime:   time.Now(),
-	}
+	nj.description = description
+	nj.uuid = id
+	nj.ctx = ctx
+	nj.task = task
+	nj.cancel = cancel
+	nj.service = svc
+	nj.startTime = time.Now()
+	nj.async = IsAsync(ctx)
 	if svc != nil {
 		nj.tenant = svc.GetName()
 	}
-	if xerr = register(&nj); xerr != nil {
+	// the jobs of calls doing changes (the ones audited) are kept in job history
+	if record := audit.FromContext(ctx); record != nil {
+		nj.action = record.Action
+		nj.resource = record.Resource
+		nj.references = record.References
+		nj.history = CurrentJobHistory()
+	}
+	if xerr = register(nj); xerr != nil {
 		return nil, xerr
 	}
 
-	return &nj, nil
+	// completes the audit record of the call, if there is one
+	audit.FromContext(ctx).BindJob(id, svc)
+	nj.persist()
+
+	return nj, nil
 }
 
 // isNull tells if the instance represents a null value
@@ -225,13 +254,53 @@ func (j job) Aborted() bool {
 	return status == concurrency.ABORTED
 }
 
+// Finish records the outcome of the operation of the job, then closes the job
+func (j *job) Finish(err error) {
+	if j.isNull() {
+		return
+	}
+
+	j.close(err, true)
+}
+
 // Close tells the job to wait for end of operation; this ensure everything is cleaned up correctly
+// The outcome of the operation is unknown, the job ends in state JobEnded (unless aborted); use Finish() to report it.
+// Does nothing if the job has been detached.
 func (j *job) Close() {
-	if j.isNull() {
+	if j.isNull() || j.isDetached() {
+		return
+	}
+
+	j.close(nil, false)
+}
+
+// close ends the job; does nothing if the job is already ended
+func (j *job) close(err error, withOutcome bool) {
+	// j.cancel is reset by Abort()
+	aborted := j.cancel == nil || j.Aborted()
+
+	var state JobState
+	switch {
+	case aborted:
+		state = JobAborted
+	case !withOutcome:
+		state = JobEnded
+	case err != nil:
+		state = JobFailed
+	default:
+		state = JobSucceeded
+	}
+	if !j.progress.end(state, err) {
 		return
 	}
 
 	_ = deregister(j)
+	j.persist()
+	record := audit.FromContext(j.ctx)
+	if aborted {
+		record.MarkAborted()
+	}
+	record.Complete(err)
 	if j.cancel != nil {
 		j.cancel()
 	}
@@ -250,6 +319,7 @@ func register(job Job) fail.Error {
 	mutexJobManager.Lock()
 	defer mutexJobManager.Unlock()
 
+	purgeFinishedJobs()
 	jobMap[job.ID()] = job
 	return nil
 }
@@ -269,6 +339,8 @@ func deregister(job Job) fail.Error {
 			return fail.NotFoundError("failed to find a job identified by id '%s'", uuid)
 		}
 		delete(jobMap, uuid)
+		// keeps the job a while, to be able to inspect it after its end
+		finishedJobMap[uuid] = job
 		return nil
 	}
 
@@ -283,7 +355,10 @@ func AbortJobByID(id string) (xerr fail.Error) {
 		return fail.InvalidParameterCannotBeEmptyStringError("id")
 	}
 
-	if job, ok := jobMap[id]; ok {
+	mutexJobManager.Lock()
+	job, ok := jobMap[id]
+	mutexJobManager.Unlock()
+	if ok {
 		if xerr := job.Abort(); xerr != nil {
 			return fail.Wrap(xerr, "failed to stop job '%s'", id)
 		}
@@ -294,9 +369,29 @@ func AbortJobByID(id string) (xerr fail.Error) {
 
 // ListJobs ...
 func ListJobs() map[string]string {
+	mutexJobManager.Lock()
+	defer mutexJobManager.Unlock()
+
 	listMap := map[string]string{}
 	for uuid, job := range jobMap {
 		listMap[uuid] = job.Name()
 	}
 	return listMap
 }
+
+// LongestJobDuration returns the duration of the oldest job running on the tenant, 0 if none
+func LongestJobDuration(tenant string) time.Duration {
+	mutexJobManager.Lock()
+	defer mutexJobManager.Unlock()
+
+	var out time.Duration
+	for _, job := range jobMap {
+		if job.Tenant() != tenant {
+			continue
+		}
+		if d := job.Duration(); d > out {
+			out = d
+		}
+	}
+	return out
+}
@@ -0,0 +1,265 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+const (
+	jobHistoryExtension = ".json"
+	// interruptedJobError is the error set on the jobs found running in history at startup
+	interruptedJobError = "interrupted by the stop of safescaled; the resources of the job may be in an inconsistent state"
+)
+
+// JobHistory keeps on disk the status of the jobs doing changes (the ones recorded by audit), one JSON file per job,
+// to be able to inspect them after their end and after a restart of safescaled
+type JobHistory struct {
+	dir  string
+	lock sync.Mutex
+}
+
+var (
+	currentJobHistory *JobHistory
+	mutexJobHistory   sync.RWMutex
+)
+
+// NewJobHistory returns a job history stored in folder 'dir', created if needed
+func NewJobHistory(dir string) (*JobHistory, fail.Error) {
+	if dir == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("dir")
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fail.Wrap(err, "failed to create job history folder '%s'", dir)
+	}
+	return &JobHistory{dir: dir}, nil
+}
+
+// UseJobHistory defines the job history where the jobs are kept; nil disables job history
+func UseJobHistory(history *JobHistory) {
+	mutexJobHistory.Lock()
+	defer mutexJobHistory.Unlock()
+
+	currentJobHistory = history
+}
+
+// CurrentJobHistory returns the job history in use, nil if there is none
+func CurrentJobHistory() *JobHistory {
+	mutexJobHistory.RLock()
+	defer mutexJobHistory.RUnlock()
+
+	return currentJobHistory
+}
+
+// Dir returns the folder containing job history
+func (h *JobHistory) Dir() string {
+	if h == nil {
+		return ""
+	}
+	return h.dir
+}
+
+// path returns the path of the file of the job identified by id
+func (h *JobHistory) path(id string) (string, fail.Error) {
+	// the id comes from the client: it must not be able to designate a file outside of the folder
+	if id == "" || id != filepath.Base(id) || strings.HasPrefix(id, ".") {
+		return "", fail.InvalidParameterError("id", "'%s' is not a valid job id", id)
+	}
+	return filepath.Join(h.dir, id+jobHistoryExtension), nil
+}
+
+// Save writes the status of a job, replacing the previous one
+func (h *JobHistory) Save(status JobStatus) fail.Error {
+	if h == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	path, xerr := h.path(status.ID)
+	if xerr != nil {
+		return xerr
+	}
+
+	content, err := json.Marshal(status)
+	if err != nil {
+		return fail.ConvertError(err)
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	// writes in a temporary file then renames it, to never leave a truncated file
+	tmp := path + ".tmp"
+	if err = ioutil.WriteFile(tmp, content, 0600); err != nil {
+		return fail.Wrap(err, "failed to write job history file '%s'", tmp)
+	}
+	if err = os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fail.Wrap(err, "failed to write job history file '%s'", path)
+	}
+	return nil
+}
+
+// Load returns the status of the job identified by id
+func (h *JobHistory) Load(id string) (JobStatus, fail.Error) {
+	if h == nil {
+		return JobStatus{}, fail.InvalidInstanceError()
+	}
+
+	path, xerr := h.path(id)
+	if xerr != nil {
+		return JobStatus{}, xerr
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	return h.read(path)
+}
+
+// read decodes a job history file; must be called with lock held
+func (h *JobHistory) read(path string) (JobStatus, fail.Error) {
+	var status JobStatus
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return status, fail.NotFoundError("no job identified by '%s' found", strings.TrimSuffix(filepath.Base(path), jobHistoryExtension))
+		}
+		return status, fail.Wrap(err, "failed to read job history file '%s'", path)
+	}
+	if err = json.Unmarshal(content, &status); err != nil {
+		return status, fail.SyntaxError("invalid content in job history file '%s': %v", path, err)
+	}
+	return status, nil
+}
+
+// List returns the status of all the jobs in history, oldest first; unreadable files are ignored
+func (h *JobHistory) List() ([]JobStatus, fail.Error) {
+	if h == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	return h.list()
+}
+
+// list returns the status of all the jobs in history, oldest first; must be called with lock held
+func (h *JobHistory) list() ([]JobStatus, fail.Error) {
+	files, err := filepath.Glob(filepath.Join(h.dir, "*"+jobHistoryExtension))
+	if err != nil {
+		return nil, fail.ConvertError(err)
+	}
+
+	out := make([]JobStatus, 0, len(files))
+	for _, v := range files {
+		status, xerr := h.read(v)
+		if xerr != nil {
+			logrus.Warnf("ignoring job history file: %v", xerr)
+			continue
+		}
+		out = append(out, status)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].StartTime.Before(out[j].StartTime) })
+	return out, nil
+}
+
+// Recover marks as interrupted, with resources to review, the jobs still running in history; to be called at
+// startup, before any job is started. Returns the jobs marked.
+func (h *JobHistory) Recover() ([]JobStatus, fail.Error) {
+	if h == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	h.lock.Lock()
+	list, xerr := h.list()
+	h.lock.Unlock()
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	var out []JobStatus
+	for _, v := range list {
+		if v.State != JobRunning {
+			continue
+		}
+
+		v.State = JobInterrupted
+		v.Error = interruptedJobError
+		v.Review = true
+		if xerr := h.Save(v); xerr != nil {
+			return out, xerr
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+// Purge removes the jobs ended before 'limit'; jobs with resources to review are kept
+func (h *JobHistory) Purge(limit time.Time) fail.Error {
+	if h == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	list, xerr := h.List()
+	if xerr != nil {
+		return xerr
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	for _, v := range list {
+		if v.Review || v.EndTime.IsZero() || !v.EndTime.Before(limit) {
+			continue
+		}
+		path, xerr := h.path(v.ID)
+		if xerr != nil {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fail.Wrap(err, "failed to remove job history file '%s'", path)
+		}
+	}
+	return nil
+}
+
+// persist writes the status of the job in job history, if the job has to be kept
+func (j *job) persist() {
+	if j.isNull() || j.history == nil {
+		return
+	}
+
+	j.progress.saveLock.Lock()
+	defer j.progress.saveLock.Unlock()
+
+	if xerr := j.history.Save(j.Status()); xerr != nil {
+		logrus.Warnf("failed to keep job '%s' in history: %v", j.uuid, xerr)
+	}
+}
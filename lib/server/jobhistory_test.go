@@ -0,0 +1,127 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/CS-SI/SafeScale/lib/server/audit"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+func newTestJobHistory(t *testing.T) (*JobHistory, func()) {
+	dir, err := ioutil.TempDir("", "safescale-jobs")
+	require.Nil(t, err)
+
+	history, xerr := NewJobHistory(dir)
+	require.Nil(t, xerr)
+	return history, func() { _ = os.RemoveAll(dir) }
+}
+
+func TestJobHistory(t *testing.T) {
+	history, cleanup := newTestJobHistory(t)
+	defer cleanup()
+
+	now := time.Now()
+	require.Nil(t, history.Save(JobStatus{ID: "old", State: JobSucceeded, StartTime: now.Add(-50 * time.Hour), EndTime: now.Add(-49 * time.Hour)}))
+	require.Nil(t, history.Save(JobStatus{ID: "crashed", State: JobRunning, StartTime: now.Add(-2 * time.Hour), Resource: "k8s", References: []string{"cluster:k8s"}, Hosts: []string{"k8s-master-1"}}))
+	require.Nil(t, history.Save(JobStatus{ID: "recent", State: JobFailed, Error: "boom", StartTime: now.Add(-time.Hour), EndTime: now}))
+
+	xerr := history.Save(JobStatus{ID: "../escape"})
+	assert.NotNil(t, xerr)
+	_, xerr = history.Load("unknown")
+	assert.IsType(t, &fail.ErrNotFound{}, xerr)
+
+	list, xerr := history.List()
+	require.Nil(t, xerr)
+	require.Len(t, list, 3)
+	assert.Equal(t, "old", list[0].ID)
+	assert.Equal(t, "boom", list[2].Error)
+
+	interrupted, xerr := history.Recover()
+	require.Nil(t, xerr)
+	require.Len(t, interrupted, 1)
+	assert.Equal(t, "crashed", interrupted[0].ID)
+
+	status, xerr := history.Load("crashed")
+	require.Nil(t, xerr)
+	assert.Equal(t, JobInterrupted, status.State)
+	assert.True(t, status.Review)
+	assert.Equal(t, []string{"cluster:k8s"}, status.References)
+	assert.Equal(t, []string{"k8s-master-1"}, status.Hosts)
+
+	// interrupted jobs are never purged
+	require.Nil(t, history.Purge(now.Add(time.Minute)))
+	list, xerr = history.List()
+	require.Nil(t, xerr)
+	require.Len(t, list, 1)
+	assert.Equal(t, "crashed", list[0].ID)
+}
+
+func TestJob_History(t *testing.T) {
+	history, cleanup := newTestJobHistory(t)
+	defer cleanup()
+	UseJobHistory(history)
+	defer UseJobHistory(nil)
+
+	// jobs not audited are not kept
+	j := newTestJob(t, "not-kept", false)
+	j.Finish(nil)
+	_, xerr := history.Load("not-kept")
+	assert.IsType(t, &fail.ErrNotFound{}, xerr)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("uuid", "kept"))
+	ctx = audit.WithRecord(ctx, &audit.Record{Action: "cluster.create", Resource: "k8s", References: []string{"cluster:k8s"}})
+	ctx, cancel := context.WithCancel(ctx)
+	j, xerr = NewJob(ctx, cancel, nil, "/cluster/k8s/create")
+	require.Nil(t, xerr)
+
+	status, xerr := history.Load("kept")
+	require.Nil(t, xerr)
+	assert.Equal(t, JobRunning, status.State)
+	assert.Equal(t, "cluster.create", status.Action)
+	assert.Equal(t, []string{"cluster:k8s"}, status.References)
+
+	j.ReportProgress(ProgressEvent{Phase: "hosts", Step: "creating master", Host: "k8s-master-1", Percent: 30})
+	status, xerr = history.Load("kept")
+	require.Nil(t, xerr)
+	assert.Equal(t, []string{"k8s-master-1"}, status.Hosts)
+	require.NotNil(t, status.Progress)
+	assert.Equal(t, "hosts", status.Progress.Phase)
+
+	j.Finish(nil)
+	status, xerr = history.Load("kept")
+	require.Nil(t, xerr)
+	assert.Equal(t, JobSucceeded, status.State)
+	assert.False(t, status.EndTime.IsZero())
+
+	// forgotten from memory, the job is still available from history
+	mutexJobManager.Lock()
+	delete(finishedJobMap, "kept")
+	mutexJobManager.Unlock()
+	status, xerr = InspectJob("kept")
+	require.Nil(t, xerr)
+	assert.Equal(t, "k8s", status.Resource)
+}
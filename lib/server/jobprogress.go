@@ -0,0 +1,342 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/CS-SI/SafeScale/lib/server/audit"
+	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// JobState is the state of a job
+type JobState string
+
+const (
+	JobRunning     JobState = "running"
+	JobSucceeded   JobState = "succeeded"
+	JobFailed      JobState = "failed"
+	JobAborted     JobState = "aborted"
+	JobEnded       JobState = "ended"       // the job ended, but its outcome has not been reported
+	JobInterrupted JobState = "interrupted" // the job was running when safescaled stopped
+)
+
+// jobRetention is the time a job is kept after its end, to be inspected
+const jobRetention = time.Hour
+
+type jobContextKey struct{}
+
+var keyForJobInContext = jobContextKey{}
+
+// ProgressEvent describes a step of the progress of a job
+type ProgressEvent struct {
+	Time    time.Time `json:"time"`
+	Phase   string    `json:"phase,omitempty"` // phase of the operation (ex: "network", "masters", "nodes", "configuration")
+	Step    string    `json:"step,omitempty"`  // step inside the phase
+	Host    string    `json:"host,omitempty"`  // host concerned by the step, if any
+	Percent uint32    `json:"percent"`         // estimated overall progress of the operation, from 0 to 100
+	Message string    `json:"message,omitempty"`
+}
+
+// JobStatus is a snapshot of the state of a job; it is also the record kept in job history
+type JobStatus struct {
+	ID          string         `json:"id"`
+	Description string         `json:"description"`
+	Tenant      string         `json:"tenant,omitempty"`
+	Action      string         `json:"action,omitempty"`   // action of the call, as recorded by audit (ex: "cluster.create")
+	Resource    string         `json:"resource,omitempty"` // name of the resource targeted by the call
+	Async       bool           `json:"async,omitempty"`
+	State       JobState       `json:"state"`
+	Error       string         `json:"error,omitempty"`
+	StartTime   time.Time      `json:"start_time"`
+	EndTime     time.Time      `json:"end_time,omitempty"`
+	Progress    *ProgressEvent `json:"progress,omitempty"`   // last progress event, if any
+	References  []string       `json:"references,omitempty"` // resources targeted by the request ('<kind>:<name or id>'), recorded when the job is created
+	Hosts       []string       `json:"hosts,omitempty"`      // hosts named by the progress events, in order of appearance
+	Review      bool           `json:"review,omitempty"`     // the resources of the job have to be reviewed (see JobHistory.Recover())
+}
+
+// jobProgress contains what changes during the life of a job
+type jobProgress struct {
+	lock     sync.Mutex
+	detached bool // set when the operation runs in background; then only Finish() ends the job
+	state    JobState
+	err      error
+	endTime  time.Time
+	events   []ProgressEvent
+	hosts    []string
+	saveLock sync.Mutex    // serializes the writes of the job in history
+	changed  chan struct{} // closed (then replaced) each time an event is added, or when the job ends
+	done     chan struct{} // closed when the job ends
+}
+
+func newJobProgress() *jobProgress {
+	return &jobProgress{
+		state:   JobRunning,
+		changed: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// signal wakes up the watchers; must be called with lock held
+func (p *jobProgress) signal() {
+	close(p.changed)
+	p.changed = make(chan struct{})
+}
+
+// add records an event
+func (p *jobProgress) add(event ProgressEvent) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.state != JobRunning {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	if event.Percent > 100 {
+		event.Percent = 100
+	}
+	// progress never goes backward; events without percentage keep the last known one
+	if count := len(p.events); count > 0 && event.Percent < p.events[count-1].Percent {
+		event.Percent = p.events[count-1].Percent
+	}
+	p.events = append(p.events, event)
+	if event.Host != "" {
+		found := false
+		for _, v := range p.hosts {
+			if v == event.Host {
+				found = true
+				break
+			}
+		}
+		if !found {
+			p.hosts = append(p.hosts, event.Host)
+		}
+	}
+	p.signal()
+}
+
+// end sets the final state; returns false if the job has already ended
+func (p *jobProgress) end(state JobState, err error) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.state != JobRunning {
+		return false
+	}
+	p.state = state
+	p.err = err
+	p.endTime = time.Now()
+	close(p.done)
+	p.signal()
+	return true
+}
+
+// IsAsync tells if the gRPC call carried by ctx asks for an asynchronous execution
+func IsAsync(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+	values := md.Get(srvutils.AsyncMetadataKey)
+	return len(values) > 0 && values[0] == "true"
+}
+
+// DetachedContext returns a context not canceled at the end of the gRPC call carried by ctx, with the same metadata
+// and audit record, to use for an asynchronous execution
+func DetachedContext(ctx context.Context) context.Context {
+	out := context.Background()
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		out = metadata.NewIncomingContext(out, md.Copy())
+	}
+	if record := audit.FromContext(ctx); record != nil {
+		out = audit.WithRecord(out, record)
+	}
+	return out
+}
+
+// ReportProgress records a progress event in the job running in ctx (directly or through a task), if there is one
+func ReportProgress(ctx context.Context, event ProgressEvent) {
+	if ctx == nil {
+		return
+	}
+	if j, ok := ctx.Value(keyForJobInContext).(*job); ok {
+		j.ReportProgress(event)
+	}
+}
+
+// Async tells if the job has been asked to run asynchronously
+func (j job) Async() bool {
+	if j.isNull() {
+		return false
+	}
+
+	return j.async
+}
+
+// Detach tells the operation of the job continues in background after the end of the gRPC call;
+// from now, Close() does nothing and the job ends with Finish()
+func (j *job) Detach() {
+	if j.isNull() {
+		return
+	}
+
+	j.progress.lock.Lock()
+	defer j.progress.lock.Unlock()
+
+	j.progress.detached = true
+}
+
+// isDetached tells if Detach() has been called
+func (j *job) isDetached() bool {
+	j.progress.lock.Lock()
+	defer j.progress.lock.Unlock()
+
+	return j.progress.detached
+}
+
+// ReportProgress records a progress event
+func (j *job) ReportProgress(event ProgressEvent) {
+	if j.isNull() {
+		return
+	}
+
+	j.progress.add(event)
+	j.persist()
+}
+
+// Status returns a snapshot of the state of the job
+func (j job) Status() JobStatus {
+	if j.isNull() {
+		return JobStatus{}
+	}
+
+	j.progress.lock.Lock()
+	defer j.progress.lock.Unlock()
+
+	out := JobStatus{
+		ID:          j.uuid,
+		Description: j.description,
+		Tenant:      j.tenant,
+		Async:       j.async,
+		Action:      j.action,
+		Resource:    j.resource,
+		State:       j.progress.state,
+		StartTime:   j.startTime,
+		EndTime:     j.progress.endTime,
+	}
+	if len(j.references) > 0 {
+		out.References = append([]string{}, j.references...)
+	}
+	if len(j.progress.hosts) > 0 {
+		out.Hosts = append([]string{}, j.progress.hosts...)
+	}
+	if j.progress.err != nil {
+		out.Error = j.progress.err.Error()
+	}
+	if count := len(j.progress.events); count > 0 {
+		last := j.progress.events[count-1]
+		out.Progress = &last
+	}
+	return out
+}
+
+// Watch returns the progress events starting from index 'from', a channel closed when something new happens
+// and a boolean telling if the job has ended
+func (j job) Watch(from int) ([]ProgressEvent, <-chan struct{}, bool) {
+	if j.isNull() {
+		return nil, nil, true
+	}
+
+	j.progress.lock.Lock()
+	defer j.progress.lock.Unlock()
+
+	var events []ProgressEvent
+	if from < 0 {
+		from = 0
+	}
+	if from < len(j.progress.events) {
+		events = make([]ProgressEvent, len(j.progress.events)-from)
+		copy(events, j.progress.events[from:])
+	}
+	return events, j.progress.changed, j.progress.state != JobRunning
+}
+
+// Done returns a channel closed when the job ends
+func (j job) Done() <-chan struct{} {
+	if j.isNull() {
+		closed := make(chan struct{})
+		close(closed)
+		return closed
+	}
+
+	return j.progress.done
+}
+
+// LookupJob returns the job identified by id, running or recently ended
+func LookupJob(id string) (Job, fail.Error) {
+	if id == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("id")
+	}
+
+	mutexJobManager.Lock()
+	defer mutexJobManager.Unlock()
+
+	if j, ok := jobMap[id]; ok {
+		return j, nil
+	}
+	if j, ok := finishedJobMap[id]; ok {
+		return j, nil
+	}
+	return nil, fail.NotFoundError("no job identified by '%s' found", id)
+}
+
+// InspectJob returns the status of the job identified by id, running, recently ended or kept in job history
+func InspectJob(id string) (JobStatus, fail.Error) {
+	j, xerr := LookupJob(id)
+	if xerr == nil {
+		return j.Status(), nil
+	}
+	if _, ok := xerr.(*fail.ErrNotFound); !ok {
+		return JobStatus{}, xerr
+	}
+
+	history := CurrentJobHistory()
+	if history == nil {
+		return JobStatus{}, xerr
+	}
+	return history.Load(id)
+}
+
+// purgeFinishedJobs forgets the jobs ended for more than jobRetention; must be called with mutexJobManager held
+func purgeFinishedJobs() {
+	for k, v := range finishedJobMap {
+		if status := v.Status(); !status.EndTime.IsZero() && time.Since(status.EndTime) > jobRetention {
+			delete(finishedJobMap, k)
+		}
+	}
+}
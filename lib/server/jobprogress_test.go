@@ -0,0 +1,119 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+
+	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+)
+
+func newTestJob(t *testing.T, id string, async bool) *job {
+	md := metadata.Pairs("uuid", id)
+	if async {
+		md.Set(srvutils.AsyncMetadataKey, "true")
+	}
+	ctx, cancel := context.WithCancel(metadata.NewIncomingContext(context.Background(), md))
+	j, xerr := NewJob(ctx, cancel, nil, "/test")
+	require.Nil(t, xerr)
+	return j
+}
+
+func TestJob_ReportProgress(t *testing.T) {
+	j := newTestJob(t, "progress-job", false)
+	defer j.Close()
+
+	assert.False(t, j.Async())
+
+	// events are reachable from the context of subtasks
+	task, xerr := concurrency.NewTaskWithParent(j.Task())
+	require.Nil(t, xerr)
+	ReportProgress(task.Context(), ProgressEvent{Phase: "network", Percent: 10})
+	ReportProgress(j.Context(), ProgressEvent{Phase: "hosts", Host: "node-1"})
+	ReportProgress(j.Context(), ProgressEvent{Phase: "done", Percent: 250})
+
+	events, _, ended := j.Watch(0)
+	require.Len(t, events, 3)
+	assert.False(t, ended)
+	assert.Equal(t, "network", events[0].Phase)
+	assert.False(t, events[0].Time.IsZero())
+	assert.EqualValues(t, 10, events[1].Percent, "progress must not go backward")
+	assert.EqualValues(t, 100, events[2].Percent)
+
+	events, _, _ = j.Watch(2)
+	assert.Len(t, events, 1)
+
+	status := j.Status()
+	assert.Equal(t, JobRunning, status.State)
+	require.NotNil(t, status.Progress)
+	assert.Equal(t, "done", status.Progress.Phase)
+}
+
+func TestJob_Detach(t *testing.T) {
+	j := newTestJob(t, "async-job", true)
+	assert.True(t, j.Async())
+
+	j.Detach()
+	j.Close()
+	select {
+	case <-j.Done():
+		t.Fatal("a detached job must not be ended by Close()")
+	default:
+	}
+
+	_, changed, _ := j.Watch(0)
+	j.Finish(errors.New("boom"))
+	select {
+	case <-j.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Finish() must end the job")
+	}
+	select {
+	case <-changed:
+	default:
+		t.Fatal("watchers must be woken up at the end of the job")
+	}
+
+	status := j.Status()
+	assert.Equal(t, JobFailed, status.State)
+	assert.Equal(t, "boom", status.Error)
+	assert.False(t, status.EndTime.IsZero())
+
+	// ended jobs are still reachable for a while
+	found, xerr := LookupJob("async-job")
+	require.Nil(t, xerr)
+	assert.Equal(t, JobFailed, found.Status().State)
+
+	// events received after the end are ignored
+	j.ReportProgress(ProgressEvent{Phase: "late"})
+	events, _, ended := j.Watch(0)
+	assert.Empty(t, events)
+	assert.True(t, ended)
+}
+
+func TestLookupJob_NotFound(t *testing.T) {
+	_, xerr := LookupJob("unknown-job")
+	assert.NotNil(t, xerr)
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package listeners
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/protocol"
+	"github.com/CS-SI/SafeScale/lib/server/audit"
+	"github.com/CS-SI/SafeScale/lib/server/resources/operations/converters"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// AuditListener audit service server grpc
+type AuditListener struct {
+	protocol.UnimplementedAuditServiceServer
+	Recorder *audit.Recorder
+}
+
+// List returns the audit records selected by the request
+func (s *AuditListener) List(ctx context.Context, in *protocol.AuditListRequest) (_ *protocol.AuditListResponse, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot list audit records")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if s.Recorder == nil {
+		return nil, fail.NotAvailableError("audit is disabled on this safescaled")
+	}
+
+	logrus.Tracef("listing audit records since '%s' on resource '%s'", in.GetSince(), in.GetResource())
+	defer fail.OnExitLogError(&err)
+
+	filter := audit.Filter{Resource: in.GetResource()}
+	if in.GetSince() != "" {
+		since, err := time.Parse(time.RFC3339, in.GetSince())
+		if err != nil {
+			return nil, fail.InvalidParameterError("since", "must be a RFC3339 date")
+		}
+		filter.Since = since
+	}
+
+	list, xerr := s.Recorder.List(filter)
+	if xerr != nil {
+		return nil, xerr
+	}
+	return converters.AuditRecordsFromAbstractToProtocol(list), nil
+}
@@ -28,9 +28,12 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/CS-SI/SafeScale/lib/protocol"
+	"github.com/CS-SI/SafeScale/lib/server"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
 	clusterfactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/cluster"
 	hostfactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/host"
 	"github.com/CS-SI/SafeScale/lib/server/resources/operations/converters"
+	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
 	propertiesv3 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v3"
 	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
 	"github.com/CS-SI/SafeScale/lib/utils/debug"
@@ -44,7 +47,7 @@ type ClusterListener struct {
 }
 
 // List lists clusters
-func (s *ClusterListener) List(ctx context.Context, in *protocol.Reference) (hl *protocol.ClusterListResponse, err error) {
+func (s *ClusterListener) List(ctx context.Context, in *protocol.ClusterListRequest) (hl *protocol.ClusterListResponse, err error) {
 	defer fail.OnExitConvertToGRPCStatus(&err)
 	defer fail.OnExitWrapError(&err, "cannot list clusters")
 
@@ -74,7 +77,30 @@ func (s *ClusterListener) List(ctx context.Context, in *protocol.Reference) (hl
 		return nil, xerr
 	}
 
-	return converters.ClusterListFromAbstractToProtocol(list), nil
+	selector := in.GetLabels()
+	if len(selector) == 0 {
+		return converters.ClusterListFromAbstractToProtocol(list), nil
+	}
+
+	out := &protocol.ClusterListResponse{}
+	for _, v := range list {
+		clusterInstance, xerr := clusterfactory.Load(job.Service(), v.Name)
+		if xerr != nil {
+			return nil, xerr
+		}
+		labels, xerr := clusterInstance.GetLabels()
+		clusterInstance.Released()
+		if xerr != nil {
+			return nil, xerr
+		}
+		if abstract.MatchLabels(labels, selector) {
+			out.Clusters = append(out.Clusters, &protocol.ClusterResponse{
+				Identity: converters.ClusterIdentityFromAbstractToProtocol(v),
+				Labels:   labels,
+			})
+		}
+	}
+	return out, nil
 }
 
 // Create creates a new cluster
@@ -107,29 +133,114 @@ func (s *ClusterListener) Create(ctx context.Context, in *protocol.ClusterCreate
 	defer tracer.Exiting()
 	defer fail.OnExitLogError(&err, tracer.TraceMessage())
 
-	instance, xerr := clusterfactory.New(job.Service())
+	var out *protocol.ClusterResponse
+	async, xerr := runJob(job, func() fail.Error {
+		instance, xerr := clusterfactory.New(job.Service())
+		if xerr != nil {
+			return xerr
+		}
+		defer instance.Released()
+
+		req, xerr := converters.ClusterRequestFromProtocolToAbstract(in)
+		if xerr != nil {
+			return xerr
+		}
+
+		if req.Tenant == "" {
+			req.Tenant = job.Tenant()
+		}
+
+		xerr = instance.Create(job.Context(), req)
+		if xerr != nil {
+			return xerr
+		}
+
+		out, xerr = instance.ToProtocol()
+		return xerr
+	})
 	if xerr != nil {
 		return nil, xerr
 	}
-	defer instance.Released()
+	if async {
+		return &protocol.ClusterResponse{}, nil
+	}
+	return out, nil
+}
+
+// Resume resumes an unfinished creation of a cluster from its last successful step
+func (s *ClusterListener) Resume(ctx context.Context, in *protocol.Reference) (_ *protocol.ClusterResponse, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot resume creation of cluster")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	ref, _ := srvutils.GetReference(in)
+	if ref == "" {
+		return nil, fail.InvalidRequestError("cluster name is missing")
+	}
 
-	req, xerr := converters.ClusterRequestFromProtocolToAbstract(in)
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), fmt.Sprintf("/cluster/%s/resume", ref))
 	if xerr != nil {
 		return nil, xerr
 	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.cluster"), "('%s')", ref).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
 
-	if req.Tenant == "" {
-		req.Tenant = job.Tenant()
+	if id := runningClusterCreation(ref, job.ID()); id != "" {
+		return nil, fail.NotAvailableError("the creation of cluster '%s' is still running in job '%s'", ref, id)
 	}
 
-	xerr = instance.Create(job.Context(), req)
+	var out *protocol.ClusterResponse
+	async, xerr := runJob(job, func() fail.Error {
+		instance, xerr := clusterfactory.Load(job.Service(), ref)
+		if xerr != nil {
+			return xerr
+		}
+		defer instance.Released()
+
+		xerr = instance.Resume(job.Context())
+		if xerr != nil {
+			return xerr
+		}
+
+		out, xerr = instance.ToProtocol()
+		return xerr
+	})
 	if xerr != nil {
 		return nil, xerr
 	}
+	if async {
+		return &protocol.ClusterResponse{}, nil
+	}
+	return out, nil
+}
 
-	defer instance.Released()
-
-	return instance.ToProtocol()
+// runningClusterCreation returns the ID of the job, other than 'self', currently creating or resuming the cluster named 'name'
+func runningClusterCreation(name, self string) string {
+	for id := range server.ListJobs() {
+		if id == self {
+			continue
+		}
+		job, xerr := server.LookupJob(id)
+		if xerr != nil {
+			continue
+		}
+		status := job.Status()
+		if status.State != server.JobRunning {
+			continue
+		}
+		if status.Description == fmt.Sprintf("/cluster/%s/create", name) || status.Description == fmt.Sprintf("/cluster/%s/resume", name) {
+			return id
+		}
+	}
+	return ""
 }
 
 // State returns the status of a cluster
@@ -340,13 +451,16 @@ func (s *ClusterListener) Delete(ctx context.Context, in *protocol.ClusterDelete
 	defer tracer.Exiting()
 	defer fail.OnExitLogError(&err, tracer.TraceMessage())
 
-	instance, xerr := clusterfactory.Load(job.Service(), ref)
-	if xerr != nil {
-		return nil, xerr
-	}
-	// Note: no .Released, the instance will be deleted
+	_, xerr = runJob(job, func() fail.Error {
+		instance, xerr := clusterfactory.Load(job.Service(), ref)
+		if xerr != nil {
+			return xerr
+		}
+		// Note: no .Released, the instance will be deleted
 
-	return empty, instance.Delete(job.Context(), in.GetForce())
+		return instance.Delete(job.Context(), in.GetForce())
+	})
+	return empty, xerr
 }
 
 // Expand adds node(s) to a cluster
@@ -373,46 +487,57 @@ func (s *ClusterListener) Expand(ctx context.Context, in *protocol.ClusterResize
 		return nil, fail.InvalidRequestError("cluster name is missing")
 	}
 
-	job, err := PrepareJob(ctx, in.GetTenantId(), fmt.Sprintf("/cluster/%s/expand", ref))
-	if err != nil {
-		return nil, err
-	}
-	defer job.Close()
-
-	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.host"), "('%s')", ref).WithStopwatch().Entering()
-	defer tracer.Exiting()
-	defer fail.OnExitLogError(&err, tracer.TraceMessage())
-
-	sizing, _, err := converters.HostSizingRequirementsFromStringToAbstract(in.GetNodeSizing())
-	if err != nil {
-		return nil, err
+	sizing, _, xerr := converters.HostSizingRequirementsFromStringToAbstract(in.GetNodeSizing())
+	if xerr != nil {
+		return nil, xerr
 	}
 
 	if sizing.Image == "" {
 		sizing.Image = in.GetImageId()
 	}
 
-	instance, xerr := clusterfactory.Load(job.Service(), in.GetName())
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), fmt.Sprintf("/cluster/%s/expand", ref))
 	if xerr != nil {
 		return nil, xerr
 	}
-	defer instance.Released()
+	defer job.Close()
 
-	resp, xerr := instance.AddNodes(job.Context(), uint(in.Count), *sizing, in.GetKeepOnFailure())
-	if xerr != nil {
-		return nil, xerr
-	}
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.host"), "('%s')", ref).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
 
 	out := &protocol.ClusterNodeListResponse{}
-	out.Nodes = make([]*protocol.Host, 0, len(resp))
-	for _, v := range resp {
-		h, xerr := v.ToProtocol()
+	async, xerr := runJob(job, func() fail.Error {
+		instance, xerr := clusterfactory.Load(job.Service(), in.GetName())
 		if xerr != nil {
-			return nil, xerr
+			return xerr
+		}
+		defer instance.Released()
+
+		resp, xerr := instance.AddNodes(job.Context(), uint(in.Count), *sizing, in.GetKeepOnFailure())
+		if xerr != nil {
+			return xerr
 		}
 
-		out.Nodes = append(out.Nodes, h)
-		v.Released()
+		nodes := make([]*protocol.Host, 0, len(resp))
+		for _, v := range resp {
+			h, xerr := v.ToProtocol()
+			if xerr != nil {
+				return xerr
+			}
+
+			nodes = append(nodes, h)
+			v.Released()
+		}
+		out.Nodes = nodes
+		return nil
+	})
+	if xerr != nil {
+		return nil, xerr
+	}
+	if async {
+		// out is filled by the job running in background
+		return &protocol.ClusterNodeListResponse{}, nil
 	}
 	return out, nil
 }
@@ -441,6 +566,11 @@ func (s *ClusterListener) Shrink(ctx context.Context, in *protocol.ClusterResize
 		return nil, fail.InvalidRequestError("cluster name is missing")
 	}
 
+	count := uint(in.GetCount())
+	if count == 0 {
+		return nil, fail.InvalidParameterError("count", "must be greater than 0")
+	}
+
 	job, xerr := PrepareJob(ctx, in.GetTenantId(), fmt.Sprintf("/cluster/%s/shrink", clusterName))
 	if xerr != nil {
 		return nil, xerr
@@ -451,24 +581,29 @@ func (s *ClusterListener) Shrink(ctx context.Context, in *protocol.ClusterResize
 	defer tracer.Exiting()
 	defer fail.OnExitLogError(&err, tracer.TraceMessage())
 
-	instance, xerr := clusterfactory.Load(job.Service(), in.GetName())
-	if xerr != nil {
-		return nil, xerr
-	}
-	defer instance.Released()
+	out := &protocol.ClusterNodeListResponse{}
+	async, xerr := runJob(job, func() fail.Error {
+		instance, xerr := clusterfactory.Load(job.Service(), in.GetName())
+		if xerr != nil {
+			return xerr
+		}
+		defer instance.Released()
 
-	count := uint(in.GetCount())
-	if count == 0 {
-		return nil, fail.InvalidParameterError("count", "must be greater than 0")
-	}
+		removedNodes, xerr := instance.Shrink(job.Context(), count)
+		if xerr != nil {
+			return xerr
+		}
 
-	removedNodes, xerr := instance.Shrink(job.Context(), count)
+		out.Nodes = fromClusterNodes(removedNodes)
+		return nil
+	})
 	if xerr != nil {
 		return nil, xerr
 	}
-
-	out := &protocol.ClusterNodeListResponse{}
-	out.Nodes = fromClusterNodes(removedNodes)
+	if async {
+		// out is filled by the job running in background
+		return &protocol.ClusterNodeListResponse{}, nil
+	}
 	return out, nil
 }
 
@@ -1237,3 +1372,806 @@ func idOfClusterMember(list resources.IndexedListOfClusterNodes, ref string) str
 	}
 	return id
 }
+
+// AddNodePool creates a node pool in a cluster
+func (s *ClusterListener) AddNodePool(ctx context.Context, in *protocol.ClusterNodePoolCreateRequest) (_ *protocol.ClusterNodePool, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot add node pool to cluster")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+
+	clusterName := in.GetName()
+	if clusterName == "" {
+		return nil, fail.InvalidRequestError("cluster name is missing")
+	}
+	poolName := in.GetPoolName()
+	if poolName == "" {
+		return nil, fail.InvalidRequestError("node pool name is missing")
+	}
+
+	sizing, _, xerr := converters.HostSizingRequirementsFromStringToAbstract(in.GetNodeSizing())
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	pool := propertiesv1.ClusterNodePool{
+		Name:     poolName,
+		Sizing:   *sizing,
+		Image:    in.GetImageId(),
+		MinCount: uint(in.GetMinCount()),
+		MaxCount: uint(in.GetMaxCount()),
+		Labels:   in.GetLabels(),
+		Taints:   in.GetTaints(),
+	}
+	if pool.Image == "" {
+		pool.Image = sizing.Image
+	}
+	pool.Sizing.Image = ""
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), fmt.Sprintf("/cluster/%s/nodepool/%s/add", clusterName, poolName))
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.cluster"), "('%s', '%s')", clusterName, poolName).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	out := &protocol.ClusterNodePool{}
+	async, xerr := runJob(job, func() fail.Error {
+		instance, xerr := clusterfactory.Load(job.Service(), clusterName)
+		if xerr != nil {
+			return xerr
+		}
+		defer instance.Released()
+
+		created, xerr := instance.AddNodePool(job.Context(), pool, uint(in.GetCount()), in.GetKeepOnFailure())
+		if xerr != nil {
+			return xerr
+		}
+
+		return fillNodePool(job.Context(), instance, created, out)
+	})
+	if xerr != nil {
+		return nil, xerr
+	}
+	if async {
+		// out is filled by the job running in background
+		return &protocol.ClusterNodePool{}, nil
+	}
+	return out, nil
+}
+
+// ListNodePools lists the node pools of a cluster
+func (s *ClusterListener) ListNodePools(ctx context.Context, in *protocol.Reference) (_ *protocol.ClusterNodePoolListResponse, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot list node pools of cluster")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+
+	ref, _ := srvutils.GetReference(in)
+	if ref == "" {
+		return nil, fail.InvalidRequestError("cluster name is missing")
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), fmt.Sprintf("/cluster/%s/nodepools/list", ref))
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.cluster"), "('%s')", ref).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	instance, xerr := clusterfactory.Load(job.Service(), ref)
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer instance.Released()
+
+	pools, xerr := instance.ListNodePools(job.Context())
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	nodes, xerr := instance.ListNodes(job.Context())
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	out := &protocol.ClusterNodePoolListResponse{
+		Pools: make([]*protocol.ClusterNodePool, 0, len(pools)),
+	}
+	for _, v := range pools {
+		out.Pools = append(out.Pools, converters.ClusterNodePoolFromPropertyToProtocol(*v, nodes))
+	}
+	return out, nil
+}
+
+// ResizeNodePool adds or removes nodes of a node pool of a cluster
+func (s *ClusterListener) ResizeNodePool(ctx context.Context, in *protocol.ClusterNodePoolRequest) (_ *protocol.ClusterNodePool, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot resize node pool of cluster")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+
+	clusterName := in.GetName()
+	if clusterName == "" {
+		return nil, fail.InvalidRequestError("cluster name is missing")
+	}
+	poolName := in.GetPoolName()
+	if poolName == "" {
+		return nil, fail.InvalidRequestError("node pool name is missing")
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), fmt.Sprintf("/cluster/%s/nodepool/%s/resize", clusterName, poolName))
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.cluster"), "('%s', '%s', %d)", clusterName, poolName, in.GetCount()).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	out := &protocol.ClusterNodePool{}
+	async, xerr := runJob(job, func() fail.Error {
+		instance, xerr := clusterfactory.Load(job.Service(), clusterName)
+		if xerr != nil {
+			return xerr
+		}
+		defer instance.Released()
+
+		resized, xerr := instance.ResizeNodePool(job.Context(), poolName, uint(in.GetCount()), in.GetKeepOnFailure())
+		if xerr != nil {
+			return xerr
+		}
+
+		return fillNodePool(job.Context(), instance, resized, out)
+	})
+	if xerr != nil {
+		return nil, xerr
+	}
+	if async {
+		// out is filled by the job running in background
+		return &protocol.ClusterNodePool{}, nil
+	}
+	return out, nil
+}
+
+// DeleteNodePool deletes a node pool of a cluster, with its nodes
+func (s *ClusterListener) DeleteNodePool(ctx context.Context, in *protocol.ClusterNodePoolRequest) (empty *googleprotobuf.Empty, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot delete node pool of cluster")
+
+	empty = &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return empty, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if in == nil {
+		return empty, fail.InvalidParameterCannotBeNilError("in")
+	}
+
+	clusterName := in.GetName()
+	if clusterName == "" {
+		return empty, fail.InvalidRequestError("cluster name is missing")
+	}
+	poolName := in.GetPoolName()
+	if poolName == "" {
+		return empty, fail.InvalidRequestError("node pool name is missing")
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), fmt.Sprintf("/cluster/%s/nodepool/%s/delete", clusterName, poolName))
+	if xerr != nil {
+		return empty, xerr
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.cluster"), "('%s', '%s')", clusterName, poolName).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	_, xerr = runJob(job, func() fail.Error {
+		instance, xerr := clusterfactory.Load(job.Service(), clusterName)
+		if xerr != nil {
+			return xerr
+		}
+		defer instance.Released()
+
+		return instance.DeleteNodePool(job.Context(), poolName)
+	})
+	return empty, xerr
+}
+
+// fillNodePool fills out with the content of pool, completed with the nodes of the cluster
+func fillNodePool(ctx context.Context, instance resources.Cluster, pool *propertiesv1.ClusterNodePool, out *protocol.ClusterNodePool) fail.Error {
+	nodes, xerr := instance.ListNodes(ctx)
+	if xerr != nil {
+		return xerr
+	}
+
+	converted := converters.ClusterNodePoolFromPropertyToProtocol(*pool, nodes)
+	out.Name = converted.Name
+	out.Sizing = converted.Sizing
+	out.ImageId = converted.ImageId
+	out.MinCount = converted.MinCount
+	out.MaxCount = converted.MaxCount
+	out.Labels = converted.Labels
+	out.Taints = converted.Taints
+	out.Nodes = converted.Nodes
+	return nil
+}
+
+// InspectAutoscaling returns the autoscaling settings of a cluster, with its last scale decisions
+func (s *ClusterListener) InspectAutoscaling(ctx context.Context, in *protocol.Reference) (_ *protocol.ClusterAutoscaling, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot inspect autoscaling of cluster")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+
+	ref, _ := srvutils.GetReference(in)
+	if ref == "" {
+		return nil, fail.InvalidRequestError("cluster name is missing")
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), fmt.Sprintf("/cluster/%s/autoscaling/inspect", ref))
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.cluster"), "('%s')", ref).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	instance, xerr := clusterfactory.Load(job.Service(), ref)
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer instance.Released()
+
+	settings, xerr := instance.GetAutoscaling(job.Context())
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return converters.ClusterAutoscalingFromPropertyToProtocol(instance.GetName(), *settings), nil
+}
+
+// SetAutoscaling replaces the autoscaling settings of a cluster
+func (s *ClusterListener) SetAutoscaling(ctx context.Context, in *protocol.ClusterAutoscaling) (_ *protocol.ClusterAutoscaling, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot set autoscaling of cluster")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+
+	clusterName := in.GetName()
+	if clusterName == "" {
+		return nil, fail.InvalidRequestError("cluster name is missing")
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), fmt.Sprintf("/cluster/%s/autoscaling/set", clusterName))
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.cluster"), "('%s', %v)", clusterName, in.GetEnabled()).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	instance, xerr := clusterfactory.Load(job.Service(), clusterName)
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer instance.Released()
+
+	settings, xerr := instance.SetAutoscaling(job.Context(), converters.ClusterAutoscalingFromProtocolToProperty(in))
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return converters.ClusterAutoscalingFromPropertyToProtocol(instance.GetName(), *settings), nil
+}
+
+// Upgrade upgrades the hosts of a cluster one after the other, or resumes the last upgrade
+func (s *ClusterListener) Upgrade(ctx context.Context, in *protocol.ClusterUpgradeRequest) (_ *protocol.ClusterUpgradeResponse, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot upgrade cluster")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+
+	clusterName := in.GetName()
+	if clusterName == "" {
+		return nil, fail.InvalidRequestError("cluster name is missing")
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), fmt.Sprintf("/cluster/%s/upgrade", clusterName))
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.cluster"), "('%s', '%s', %v)", clusterName, in.GetFeature(), in.GetResume()).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	out := &protocol.ClusterUpgradeResponse{}
+	async, xerr := runJob(job, func() fail.Error {
+		instance, xerr := clusterfactory.Load(job.Service(), clusterName)
+		if xerr != nil {
+			return xerr
+		}
+		defer instance.Released()
+
+		upgrade, xerr := instance.Upgrade(job.Context(), converters.ClusterUpgradeRequestFromProtocolToAbstract(in), in.GetResume())
+		if xerr != nil {
+			return xerr
+		}
+
+		*out = *converters.ClusterUpgradeFromPropertyToProtocol(instance.GetName(), *upgrade)
+		return nil
+	})
+	if xerr != nil {
+		return nil, xerr
+	}
+	if async {
+		// out is filled by the job running in background
+		return &protocol.ClusterUpgradeResponse{}, nil
+	}
+	return out, nil
+}
+
+// InspectUpgrade returns the progress of the last upgrade of a cluster
+func (s *ClusterListener) InspectUpgrade(ctx context.Context, in *protocol.Reference) (_ *protocol.ClusterUpgradeResponse, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot inspect upgrade of cluster")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+
+	ref, _ := srvutils.GetReference(in)
+	if ref == "" {
+		return nil, fail.InvalidRequestError("cluster name is missing")
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), fmt.Sprintf("/cluster/%s/upgrade/inspect", ref))
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.cluster"), "('%s')", ref).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	instance, xerr := clusterfactory.Load(job.Service(), ref)
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer instance.Released()
+
+	upgrade, xerr := instance.GetUpgrade(job.Context())
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return converters.ClusterUpgradeFromPropertyToProtocol(instance.GetName(), *upgrade), nil
+}
+
+// Backup saves the control plane of a cluster in a bucket
+func (s *ClusterListener) Backup(ctx context.Context, in *protocol.ClusterBackupRequest) (_ *protocol.ClusterBackup, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot save cluster")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+
+	clusterName := in.GetName()
+	if clusterName == "" {
+		return nil, fail.InvalidRequestError("cluster name is missing")
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), fmt.Sprintf("/cluster/%s/backup", clusterName))
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.cluster"), "('%s', '%s')", clusterName, in.GetBucket()).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	out := &protocol.ClusterBackup{}
+	async, xerr := runJob(job, func() fail.Error {
+		instance, xerr := clusterfactory.Load(job.Service(), clusterName)
+		if xerr != nil {
+			return xerr
+		}
+		defer instance.Released()
+
+		backup, xerr := instance.Backup(job.Context(), in.GetBucket())
+		if xerr != nil {
+			return xerr
+		}
+
+		*out = *converters.ClusterBackupFromPropertyToProtocol(*backup)
+		return nil
+	})
+	if xerr != nil {
+		return nil, xerr
+	}
+	if async {
+		// out is filled by the job running in background
+		return &protocol.ClusterBackup{}, nil
+	}
+	return out, nil
+}
+
+// ListBackups lists the backups of a cluster
+func (s *ClusterListener) ListBackups(ctx context.Context, in *protocol.Reference) (_ *protocol.ClusterBackupList, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot list backups of cluster")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+
+	ref, _ := srvutils.GetReference(in)
+	if ref == "" {
+		return nil, fail.InvalidRequestError("cluster name is missing")
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), fmt.Sprintf("/cluster/%s/backups/list", ref))
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.cluster"), "('%s')", ref).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	instance, xerr := clusterfactory.Load(job.Service(), ref)
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer instance.Released()
+
+	backups, xerr := instance.ListBackups(job.Context())
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return converters.ClusterBackupsFromPropertyToProtocol(instance.GetName(), *backups), nil
+}
+
+// Restore rebuilds the control plane of a cluster from a backup
+func (s *ClusterListener) Restore(ctx context.Context, in *protocol.ClusterBackupRequest) (_ *protocol.ClusterBackup, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot restore cluster")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+
+	clusterName := in.GetName()
+	if clusterName == "" {
+		return nil, fail.InvalidRequestError("cluster name is missing")
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), fmt.Sprintf("/cluster/%s/restore", clusterName))
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.cluster"), "('%s', '%s', '%s')", clusterName, in.GetBucket(), in.GetId()).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	out := &protocol.ClusterBackup{}
+	async, xerr := runJob(job, func() fail.Error {
+		instance, xerr := clusterfactory.Load(job.Service(), clusterName)
+		if xerr != nil {
+			return xerr
+		}
+		defer instance.Released()
+
+		backup, xerr := instance.Restore(job.Context(), in.GetBucket(), in.GetId())
+		if xerr != nil {
+			return xerr
+		}
+
+		*out = *converters.ClusterBackupFromPropertyToProtocol(*backup)
+		return nil
+	})
+	if xerr != nil {
+		return nil, xerr
+	}
+	if async {
+		// out is filled by the job running in background
+		return &protocol.ClusterBackup{}, nil
+	}
+	return out, nil
+}
+
+// InspectHealth returns the health check settings of a cluster, the health of its masters and nodes and its last repairs
+func (s *ClusterListener) InspectHealth(ctx context.Context, in *protocol.Reference) (_ *protocol.ClusterHealth, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot inspect health of cluster")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+
+	ref, _ := srvutils.GetReference(in)
+	if ref == "" {
+		return nil, fail.InvalidRequestError("cluster name is missing")
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), fmt.Sprintf("/cluster/%s/health/inspect", ref))
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.cluster"), "('%s')", ref).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	instance, xerr := clusterfactory.Load(job.Service(), ref)
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer instance.Released()
+
+	settings, nodes, xerr := instance.GetHealth(job.Context())
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return converters.ClusterHealthFromPropertyToProtocol(instance.GetName(), *settings, *nodes), nil
+}
+
+// SetHealth replaces the health check settings of a cluster
+func (s *ClusterListener) SetHealth(ctx context.Context, in *protocol.ClusterHealth) (_ *protocol.ClusterHealth, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot set health check settings of cluster")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+
+	clusterName := in.GetName()
+	if clusterName == "" {
+		return nil, fail.InvalidRequestError("cluster name is missing")
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), fmt.Sprintf("/cluster/%s/health/set", clusterName))
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.cluster"), "('%s', %v, %d)", clusterName, in.GetAutoRepair(), in.GetFailureThreshold()).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	instance, xerr := clusterfactory.Load(job.Service(), clusterName)
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer instance.Released()
+
+	_, xerr = instance.SetHealth(job.Context(), converters.ClusterHealthFromProtocolToProperty(in))
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	settings, nodes, xerr := instance.GetHealth(job.Context())
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return converters.ClusterHealthFromPropertyToProtocol(instance.GetName(), *settings, *nodes), nil
+}
+
+// CheckHealth checks the masters and nodes of a cluster now, replacing the failed nodes if auto repair is enabled
+func (s *ClusterListener) CheckHealth(ctx context.Context, in *protocol.Reference) (_ *protocol.ClusterHealth, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot check health of cluster")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+
+	ref, _ := srvutils.GetReference(in)
+	if ref == "" {
+		return nil, fail.InvalidRequestError("cluster name is missing")
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), fmt.Sprintf("/cluster/%s/health/check", ref))
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.cluster"), "('%s')", ref).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	out := &protocol.ClusterHealth{}
+	async, xerr := runJob(job, func() fail.Error {
+		instance, xerr := clusterfactory.Load(job.Service(), ref)
+		if xerr != nil {
+			return xerr
+		}
+		defer instance.Released()
+
+		repairs, xerr := instance.CheckHealth(job.Context())
+		if xerr != nil {
+			return xerr
+		}
+		if repairs == nil {
+			return fail.InvalidRequestError("cluster '%s' is not running", instance.GetName())
+		}
+
+		settings, nodes, xerr := instance.GetHealth(job.Context())
+		if xerr != nil {
+			return xerr
+		}
+
+		*out = *converters.ClusterHealthFromPropertyToProtocol(instance.GetName(), *settings, *nodes)
+		return nil
+	})
+	if xerr != nil {
+		return nil, xerr
+	}
+	if async {
+		// out is filled by the job running in background
+		return &protocol.ClusterHealth{}, nil
+	}
+	return out, nil
+}
+
+// Kubeconfig returns the kubeconfig of the admin of a cluster, to use its Kubernetes API server from outside of the cluster
+func (s *ClusterListener) Kubeconfig(ctx context.Context, in *protocol.ClusterKubeconfigRequest) (_ *protocol.ClusterKubeconfig, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot get kubeconfig of cluster")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+
+	clusterName := in.GetName()
+	if clusterName == "" {
+		return nil, fail.InvalidRequestError("cluster name is missing")
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), fmt.Sprintf("/cluster/%s/kubeconfig", clusterName))
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.cluster"), "('%s', '%s')", clusterName, in.GetServer()).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	instance, xerr := clusterfactory.Load(job.Service(), clusterName)
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer instance.Released()
+
+	kc, xerr := instance.GetKubeconfig(job.Context(), in.GetServer())
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return converters.ClusterKubeconfigFromAbstractToProtocol(instance.GetName(), *kc), nil
+}
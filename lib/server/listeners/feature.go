@@ -392,3 +392,83 @@ func (s *FeatureListener) Remove(ctx context.Context, in *protocol.FeatureAction
 	// Should not reach this
 	return empty, fail.Wrap(fail.InconsistentError("reached theoretically unreachable point"), "cannot remove feature")
 }
+
+// Upgrade updates an installed Feature to the version of its specification file
+func (s *FeatureListener) Upgrade(ctx context.Context, in *protocol.FeatureActionRequest) (empty *googleprotobuf.Empty, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnPanic(&err)
+
+	empty = &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return empty, fail.InvalidParameterError("ctx", "cannot be nil")
+	}
+
+	targetType := in.GetTargetType()
+	targetRef, targetRefLabel := srvutils.GetReference(in.GetTargetRef())
+	if targetRef == "" {
+		return empty, fail.InvalidRequestError("target reference is missing")
+	}
+	featureName := in.GetName()
+	featureVariables, xerr := convertVariablesToDataMap(in.GetVariables())
+	if xerr != nil {
+		return empty, fail.Wrap(xerr, "failed to upgrade feature")
+	}
+	featureSettings := converters.FeatureSettingsFromProtocolToResource(in.GetSettings())
+
+	job, err := PrepareJob(ctx, in.GetTenantId(), fmt.Sprintf("/feature/%s/upgrade/%s/%s", featureName, targetType, targetRef))
+	if err != nil {
+		return empty, err
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.Task(), true /*tracing.ShouldTrace("listeners.feature")*/, "(%d, %s, %s)", targetType, targetRefLabel, featureName).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	feat, xerr := featurefactory.New(job.Service(), featureName)
+	if xerr != nil {
+		return empty, xerr
+	}
+
+	switch targetType {
+	case protocol.FeatureTargetType_FT_HOST:
+		hostInstance, xerr := hostfactory.Load(job.Service(), targetRef)
+		if xerr != nil {
+			return empty, xerr
+		}
+
+		defer hostInstance.Released()
+
+		results, xerr := feat.Upgrade(job.Context(), hostInstance, featureVariables, featureSettings)
+		if xerr != nil {
+			return empty, xerr
+		}
+		if results.Successful() {
+			return empty, nil
+		}
+		return empty, fail.ExecutionError(nil, "failed to upgrade feature '%s' on Host '%s' (%s)", featureName, targetRefLabel, results.AllErrorMessages())
+
+	case protocol.FeatureTargetType_FT_CLUSTER:
+		clusterInstance, xerr := clusterfactory.Load(job.Service(), targetRef)
+		if xerr != nil {
+			return empty, xerr
+		}
+
+		defer clusterInstance.Released()
+
+		results, xerr := feat.Upgrade(job.Context(), clusterInstance, featureVariables, featureSettings)
+		if xerr != nil {
+			return empty, xerr
+		}
+		if results.Successful() {
+			return empty, nil
+		}
+		return empty, fail.ExecutionError(nil, "failed to upgrade feature '%s' on Cluster '%s' (%s)", featureName, targetRefLabel, results.AllErrorMessages())
+	}
+
+	// Should not reach this
+	return empty, fail.Wrap(fail.InconsistentError("reached theoretically unreachable point"), "cannot upgrade feature")
+}
@@ -255,9 +255,32 @@ func (s *HostListener) List(ctx context.Context, in *protocol.HostListRequest) (
 	}
 
 	// build response mapping abstract.IPAddress to protocol.IPAddress
-	pbhost := make([]*protocol.Host, len(hosts))
-	for k, host := range hosts {
-		pbhost[k] = converters.HostFullFromAbstractToProtocol(host)
+	selector := in.GetLabels()
+	pbhost := make([]*protocol.Host, 0, len(hosts))
+	for _, host := range hosts {
+		item := converters.HostFullFromAbstractToProtocol(host)
+		if len(selector) > 0 {
+			// Labels are only known from metadata; Hosts not managed by SafeScale cannot match
+			hostInstance, xerr := hostfactory.Load(job.Service(), host.Core.ID)
+			if xerr != nil {
+				switch xerr.(type) {
+				case *fail.ErrNotFound:
+					continue
+				default:
+					return nil, xerr
+				}
+			}
+			labels, xerr := hostInstance.GetLabels()
+			hostInstance.Released()
+			if xerr != nil {
+				return nil, xerr
+			}
+			if !abstract.MatchLabels(labels, selector) {
+				continue
+			}
+			item.Labels = labels
+		}
+		pbhost = append(pbhost, item)
 	}
 	out := &protocol.HostList{Hosts: pbhost}
 	return out, nil
@@ -381,22 +404,101 @@ func (s *HostListener) Create(ctx context.Context, in *protocol.HostDefinition)
 		KeepOnFailure: in.GetKeepOnFailure(),
 		Subnets:       subnets,
 		ImageRef:      in.GetImageId(),
+		Labels:        in.GetLabels(),
 	}
 
-	hostInstance, xerr := hostfactory.New(job.Service())
+	var out *protocol.Host
+	async, xerr := runJob(job, func() fail.Error {
+		hostInstance, xerr := hostfactory.New(job.Service())
+		if xerr != nil {
+			return xerr
+		}
+
+		_, xerr = hostInstance.Create(job.Context(), hostReq, *sizing)
+		if xerr != nil {
+			return xerr
+		}
+
+		defer hostInstance.Released()
+
+		// logrus.Infof("Host '%s' created", name)
+		out, xerr = hostInstance.ToProtocol()
+		return xerr
+	})
 	if xerr != nil {
 		return nil, xerr
 	}
+	if async {
+		return &protocol.Host{}, nil
+	}
+	return out, nil
+}
+
+// Import registers in metadata an existing host of the provider
+func (s *HostListener) Import(ctx context.Context, in *protocol.HostImportRequest) (_ *protocol.Host, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot import host")
+	defer fail.OnPanic(&err)
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	ref := in.GetRef()
+	if ref == "" {
+		return nil, fail.InvalidRequestError("no provider id given as reference")
+	}
 
-	_, xerr = hostInstance.Create(job.Context(), hostReq, *sizing)
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), fmt.Sprintf("/host/%s/import", ref))
 	if xerr != nil {
 		return nil, xerr
 	}
+	defer job.Close()
 
-	defer hostInstance.Released()
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.host"), "('%s')", ref).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
 
-	// logrus.Infof("Host '%s' created", name)
-	return hostInstance.ToProtocol()
+	importReq := abstract.HostImportRequest{
+		Ref:             ref,
+		SSHPort:         in.GetSshPort(),
+		SSHUser:         in.GetSshUser(),
+		SSHPrivateKey:   in.GetSshPrivateKey(),
+		InjectKey:       in.GetInjectKey(),
+		InstallBinaries: in.GetInstallBinaries(),
+		SecurityGroups:  in.GetSecurityGroups(),
+	}
+
+	var out *protocol.Host
+	async, xerr := runJob(job, func() fail.Error {
+		hostInstance, xerr := hostfactory.New(job.Service())
+		if xerr != nil {
+			return xerr
+		}
+
+		xerr = hostInstance.Import(job.Context(), importReq)
+		if xerr != nil {
+			return xerr
+		}
+
+		defer hostInstance.Released()
+
+		out, xerr = hostInstance.ToProtocol()
+		return xerr
+	})
+	if xerr != nil {
+		return nil, xerr
+	}
+	if async {
+		return &protocol.Host{}, nil
+	}
+	return out, nil
 }
 
 // Resize an host
@@ -618,19 +720,22 @@ func (s *HostListener) Delete(ctx context.Context, in *protocol.Reference) (empt
 	defer tracer.Exiting()
 	defer fail.OnExitLogError(&err, tracer.TraceMessage())
 
-	hostInstance, xerr := hostfactory.Load(job.Service(), ref)
-	if xerr != nil {
-		return empty, xerr
-	}
+	_, xerr := runJob(job, func() fail.Error {
+		hostInstance, xerr := hostfactory.Load(job.Service(), ref)
+		if xerr != nil {
+			return xerr
+		}
 
-	xerr = hostInstance.Delete(job.Context())
-	if xerr != nil {
-		hostInstance.Released()
-		return empty, xerr
-	}
+		xerr = hostInstance.Delete(job.Context())
+		if xerr != nil {
+			hostInstance.Released()
+			return xerr
+		}
 
-	tracer.Trace("Host %s successfully deleted.", refLabel)
-	return empty, nil
+		tracer.Trace("Host %s successfully deleted.", refLabel)
+		return nil
+	})
+	return empty, xerr
 }
 
 // SSH returns ssh parameters to access an host
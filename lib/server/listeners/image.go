@@ -18,18 +18,24 @@ package listeners
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/asaskevich/govalidator"
+	googleprotobuf "github.com/golang/protobuf/ptypes/empty"
 	"github.com/sirupsen/logrus"
 
 	"github.com/CS-SI/SafeScale/lib/protocol"
 	"github.com/CS-SI/SafeScale/lib/server/handlers"
 	"github.com/CS-SI/SafeScale/lib/server/resources/operations/converters"
+	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
 	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
 )
 
 // safescale image list --all=false
+// safescale image create --from-host <host> <name>
+// safescale image delete <name>
 
 // ImageListener image service server grpc
 type ImageListener struct {
@@ -80,3 +86,93 @@ func (s *ImageListener) List(ctx context.Context, in *protocol.ImageListRequest)
 	out := &protocol.ImageList{Images: pbImages}
 	return out, nil
 }
+
+// Create creates a custom image from an existing host
+func (s *ImageListener) Create(ctx context.Context, in *protocol.ImageCreateRequest) (_ *protocol.Image, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot create image")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	hostRef, hostRefLabel := srvutils.GetReference(in.GetHost())
+	if hostRef == "" {
+		return nil, fail.InvalidRequestError("neither name nor id given as reference of host")
+	}
+	name := in.GetName()
+	if name == "" {
+		return nil, fail.InvalidRequestError("image name cannot be empty string")
+	}
+
+	if ok, err := govalidator.ValidateStruct(in); err != nil || !ok {
+		logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), fmt.Sprintf("/image/%s/create", name))
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.image"), "('%s', %s)", name, hostRefLabel).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	handler := handlers.NewImageHandler(job)
+	ri, xerr := handler.Create(name, hostRef, in.GetDescription())
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	tracer.Trace("Image '%s' created from host %s", name, hostRefLabel)
+	return ri.ToProtocol()
+}
+
+// Delete deletes a custom image
+func (s *ImageListener) Delete(ctx context.Context, in *protocol.Reference) (empty *googleprotobuf.Empty, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot delete image")
+
+	empty = &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return empty, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return empty, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	ref, refLabel := srvutils.GetReference(in)
+	if ref == "" {
+		return empty, fail.InvalidRequestError("neither name nor id given as reference")
+	}
+
+	if ok, err := govalidator.ValidateStruct(in); err != nil || !ok {
+		logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), fmt.Sprintf("/image/%s/delete", ref))
+	if xerr != nil {
+		return empty, xerr
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.image"), "(%s)", refLabel).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	handler := handlers.NewImageHandler(job)
+	if xerr = handler.Delete(ref); xerr != nil {
+		return empty, xerr
+	}
+
+	logrus.Infof("Image %s successfully deleted.", refLabel)
+	return empty, nil
+}
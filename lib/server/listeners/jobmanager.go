@@ -18,11 +18,13 @@ package listeners
 
 import (
 	"context"
+	"time"
 
 	"github.com/CS-SI/SafeScale/lib/server/resources/operations"
 
 	"github.com/CS-SI/SafeScale/lib/protocol"
 	"github.com/CS-SI/SafeScale/lib/server"
+	"github.com/CS-SI/SafeScale/lib/server/audit"
 	"github.com/CS-SI/SafeScale/lib/server/iaas"
 	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
 	"github.com/CS-SI/SafeScale/lib/utils/debug"
@@ -54,7 +56,12 @@ func PrepareJob(ctx context.Context, tenantID string, jobDescription string) (_
 			return nil, fail.NotFoundError("no tenant set")
 		}
 	}
-	newctx, cancel := context.WithCancel(ctx)
+	// an asynchronous job must survive the end of the gRPC call
+	parentCtx := ctx
+	if server.IsAsync(ctx) {
+		parentCtx = server.DetachedContext(ctx)
+	}
+	newctx, cancel := context.WithCancel(parentCtx)
 	job, xerr := server.NewJob(newctx, cancel, tenant.Service, jobDescription)
 	if xerr != nil {
 		return nil, xerr
@@ -63,6 +70,32 @@ func PrepareJob(ctx context.Context, tenantID string, jobDescription string) (_
 	return job, nil
 }
 
+// runJob runs fn in job, then ends the job with the outcome of fn
+// If the caller asked for an asynchronous execution, the job is detached, fn is run in background and runJob returns
+// immediately with async set to true; the outcome is then available through JobService.
+func runJob(job server.Job, fn func() fail.Error) (async bool, xerr fail.Error) {
+	if !job.Async() {
+		defer func() { job.Finish(xerr) }()
+		defer fail.OnPanic(&xerr)
+
+		return false, fn()
+	}
+
+	job.Detach()
+	audit.FromContext(job.Context()).Detach()
+	go func() {
+		var xerr fail.Error
+		defer func() { job.Finish(xerr) }()
+		defer fail.OnPanic(&xerr)
+
+		xerr = fn()
+		if xerr != nil {
+			logrus.Errorf("asynchronous job '%s' failed: %v", job.ID(), xerr)
+		}
+	}()
+	return true, nil
+}
+
 // PrepareJobWithoutService creates a new job without service instanciation (for example to be used with metadata upgrade)
 func PrepareJobWithoutService(ctx context.Context, jobDescription string) (_ server.Job, xerr fail.Error) {
 	defer fail.OnPanic(&xerr)
@@ -168,3 +201,187 @@ func (s *JobManagerListener) List(ctx context.Context, in *googleprotobuf.Empty)
 	}
 	return &protocol.JobList{List: pbProcessList}, nil
 }
+
+// Inspect returns the status of a job, running, recently ended or kept in job history
+func (s *JobManagerListener) Inspect(ctx context.Context, in *protocol.JobDefinition) (_ *protocol.JobStatus, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot inspect job")
+	defer fail.OnPanic(&err)
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	status, xerr := server.InspectJob(in.GetUuid())
+	if xerr != nil {
+		return nil, xerr
+	}
+	return jobStatusToProtocol(status), nil
+}
+
+// Wait waits for the end of a job, then returns its status
+func (s *JobManagerListener) Wait(ctx context.Context, in *protocol.JobDefinition) (_ *protocol.JobStatus, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot wait job")
+	defer fail.OnPanic(&err)
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	job, xerr := server.LookupJob(in.GetUuid())
+	if xerr != nil {
+		// a job no more in memory has ended; its status may be in job history
+		if _, ok := xerr.(*fail.ErrNotFound); ok {
+			status, xerr := server.InspectJob(in.GetUuid())
+			if xerr != nil {
+				return nil, xerr
+			}
+			return jobStatusToProtocol(status), nil
+		}
+		return nil, xerr
+	}
+
+	select {
+	case <-job.Done():
+	case <-ctx.Done():
+		return nil, fail.AbortedError(ctx.Err(), "stopped waiting for job '%s'", in.GetUuid())
+	}
+	return jobStatusToProtocol(job.Status()), nil
+}
+
+// Watch sends the progress events of a job (starting from the first one) until its end
+func (s *JobManagerListener) Watch(in *protocol.JobDefinition, stream protocol.JobService_WatchServer) (err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot watch job")
+	defer fail.OnPanic(&err)
+
+	if s == nil {
+		return fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return fail.InvalidParameterCannotBeNilError("in")
+	}
+	if stream == nil {
+		return fail.InvalidParameterCannotBeNilError("stream")
+	}
+
+	job, xerr := server.LookupJob(in.GetUuid())
+	if xerr != nil {
+		// a job no more in memory has ended; only its last progress event may be in job history
+		if _, ok := xerr.(*fail.ErrNotFound); ok {
+			status, xerr := server.InspectJob(in.GetUuid())
+			if xerr != nil {
+				return xerr
+			}
+			if status.Progress != nil {
+				if err := stream.Send(jobProgressToProtocol(*status.Progress)); err != nil {
+					return fail.ConvertError(err)
+				}
+			}
+			return nil
+		}
+		return xerr
+	}
+
+	ctx := stream.Context()
+	sent := 0
+	for {
+		events, changed, ended := job.Watch(sent)
+		for _, v := range events {
+			if err := stream.Send(jobProgressToProtocol(v)); err != nil {
+				return fail.ConvertError(err)
+			}
+		}
+		sent += len(events)
+		if ended {
+			return nil
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return fail.AbortedError(ctx.Err(), "stopped watching job '%s'", in.GetUuid())
+		}
+	}
+}
+
+// History lists the jobs kept in job history, oldest first
+func (s *JobManagerListener) History(ctx context.Context, in *googleprotobuf.Empty) (_ *protocol.JobStatusList, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot list job history")
+	defer fail.OnPanic(&err)
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	history := server.CurrentJobHistory()
+	if history == nil {
+		return nil, fail.NotAvailableError("job history is disabled")
+	}
+
+	list, xerr := history.List()
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	out := &protocol.JobStatusList{List: make([]*protocol.JobStatus, 0, len(list))}
+	for _, v := range list {
+		out.List = append(out.List, jobStatusToProtocol(v))
+	}
+	return out, nil
+}
+
+// jobProgressToProtocol converts a server.ProgressEvent to protocol.JobProgress
+func jobProgressToProtocol(in server.ProgressEvent) *protocol.JobProgress {
+	return &protocol.JobProgress{
+		Time:    in.Time.Format(time.RFC3339Nano),
+		Phase:   in.Phase,
+		Step:    in.Step,
+		Host:    in.Host,
+		Percent: in.Percent,
+		Message: in.Message,
+	}
+}
+
+// jobStatusToProtocol converts a server.JobStatus to protocol.JobStatus
+func jobStatusToProtocol(in server.JobStatus) *protocol.JobStatus {
+	out := &protocol.JobStatus{
+		Uuid:       in.ID,
+		Info:       in.Description,
+		Tenant:     in.Tenant,
+		State:      string(in.State),
+		Error:      in.Error,
+		StartTime:  in.StartTime.Format(time.RFC3339Nano),
+		Async:      in.Async,
+		Action:     in.Action,
+		Resource:   in.Resource,
+		Hosts:      in.Hosts,
+		References: in.References,
+		Review:     in.Review,
+	}
+	if !in.EndTime.IsZero() {
+		out.EndTime = in.EndTime.Format(time.RFC3339Nano)
+		out.DurationMs = in.EndTime.Sub(in.StartTime).Milliseconds()
+	}
+	if in.Progress != nil {
+		out.Progress = jobProgressToProtocol(*in.Progress)
+	}
+	return out
+}
@@ -0,0 +1,159 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package listeners
+
+import (
+	"context"
+
+	"github.com/CS-SI/SafeScale/lib/protocol"
+	"github.com/CS-SI/SafeScale/lib/server/handlers"
+	"github.com/CS-SI/SafeScale/lib/server/manifest"
+	"github.com/CS-SI/SafeScale/lib/server/resources/operations/converters"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// ManifestListener manifest service server grpc
+type ManifestListener struct {
+	protocol.UnimplementedManifestServiceServer
+}
+
+// Plan returns the steps needed to converge to (or to destroy) the content of a manifest, without doing anything
+func (s *ManifestListener) Plan(ctx context.Context, in *protocol.ManifestRequest) (_ *protocol.ManifestPlan, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot plan manifest")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	m, xerr := manifest.Decode(in.GetContent(), in.GetFormat())
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), "/manifest/plan")
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.manifest"), "(%v, %v)", in.GetDestroy(), in.GetPrune()).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	plan, xerr := handlers.NewManifestHandler(job).Plan(m, in.GetDestroy(), in.GetPrune())
+	if xerr != nil {
+		return nil, xerr
+	}
+	return converters.ManifestPlanFromAbstractToProtocol(plan), nil
+}
+
+// Apply creates what is missing to converge to the content of a manifest, and deletes what has been removed from it if asked to prune
+func (s *ManifestListener) Apply(ctx context.Context, in *protocol.ManifestRequest) (_ *protocol.ManifestPlan, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot apply manifest")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	m, xerr := manifest.Decode(in.GetContent(), in.GetFormat())
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), "/manifest/apply")
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.manifest"), "(%v)", in.GetPrune()).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	var plan manifest.Plan
+	async, xerr := runJob(job, func() (innerXErr fail.Error) {
+		plan, innerXErr = handlers.NewManifestHandler(job).Apply(m, in.GetPrune())
+		return innerXErr
+	})
+	if xerr != nil {
+		return nil, xerr
+	}
+	if async {
+		return &protocol.ManifestPlan{}, nil
+	}
+	return converters.ManifestPlanFromAbstractToProtocol(plan), nil
+}
+
+// Destroy deletes the resources described in a manifest
+func (s *ManifestListener) Destroy(ctx context.Context, in *protocol.ManifestRequest) (_ *protocol.ManifestPlan, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot destroy manifest")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	m, xerr := manifest.Decode(in.GetContent(), in.GetFormat())
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), "/manifest/destroy")
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.manifest"), "").WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	var plan manifest.Plan
+	async, xerr := runJob(job, func() (innerXErr fail.Error) {
+		plan, innerXErr = handlers.NewManifestHandler(job).Destroy(m)
+		return innerXErr
+	})
+	if xerr != nil {
+		return nil, xerr
+	}
+	if async {
+		return &protocol.ManifestPlan{}, nil
+	}
+	return converters.ManifestPlanFromAbstractToProtocol(plan), nil
+}
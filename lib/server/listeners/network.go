@@ -90,6 +90,7 @@ func (s *NetworkListener) Create(ctx context.Context, in *protocol.NetworkCreate
 		CIDR:          cidr,
 		DNSServers:    in.GetDnsServers(),
 		KeepOnFailure: in.GetKeepOnFailure(),
+		Labels:        in.GetLabels(),
 	}
 	networkInstance, xerr := networkfactory.New(svc)
 	if xerr != nil {
@@ -146,6 +147,7 @@ func (s *NetworkListener) Create(ctx context.Context, in *protocol.NetworkCreate
 			KeepOnFailure:  in.GetKeepOnFailure(),
 			DefaultSSHPort: in.GetGateway().GetSshPort(),
 			ImageRef:       in.GetGateway().GetImageId(),
+			GatewayLabels:  in.GetLabels(),
 		}
 		xerr = subnetInstance.Create(job.Context(), req, in.GetGateway().GetName(), sizing)
 		if xerr != nil {
@@ -206,8 +208,31 @@ func (s *NetworkListener) List(ctx context.Context, in *protocol.NetworkListRequ
 
 	// Build response mapping abstract.Network to protocol.Network
 	var pbnetworks []*protocol.Network
+	selector := in.GetLabels()
 	for _, v := range list {
-		pbnetworks = append(pbnetworks, converters.NetworkFromAbstractToProtocol(v))
+		pbnetwork := converters.NetworkFromAbstractToProtocol(v)
+		if len(selector) > 0 {
+			// Labels are only known from metadata; Networks not managed by SafeScale cannot match
+			networkInstance, xerr := networkfactory.Load(svc, v.ID)
+			if xerr != nil {
+				switch xerr.(type) {
+				case *fail.ErrNotFound:
+					continue
+				default:
+					return nil, xerr
+				}
+			}
+			labels, xerr := networkInstance.GetLabels()
+			networkInstance.Released()
+			if xerr != nil {
+				return nil, xerr
+			}
+			if !abstract.MatchLabels(labels, selector) {
+				continue
+			}
+			pbnetwork.Labels = labels
+		}
+		pbnetworks = append(pbnetworks, pbnetwork)
 	}
 	rv := &protocol.NetworkList{Networks: pbnetworks}
 	return rv, nil
@@ -340,3 +365,45 @@ func (s *NetworkListener) Delete(ctx context.Context, in *protocol.Reference) (e
 	tracer.Trace("Network %s successfully deleted.", refLabel)
 	return empty, nil
 }
+
+// Import registers in metadata an existing Network of the provider, with its Subnets
+func (s *NetworkListener) Import(ctx context.Context, in *protocol.Reference) (_ *protocol.Network, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot import network")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterError("in", "cannot be nil")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterError("ctx", "cannot be nil")
+	}
+
+	ref, refLabel := srvutils.GetReference(in)
+	if ref == "" {
+		return nil, fail.InvalidRequestError("neither name nor id given as reference")
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), fmt.Sprintf("/network/%s/import", ref))
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.Task(), true /*tracing.ShouldTrace("listeners.network")*/, "(%s)", refLabel).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	networkInstance, xerr := networkfactory.Import(job.Context(), job.Service(), ref)
+	if networkInstance != nil {
+		defer networkInstance.Released()
+	}
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	tracer.Trace("Network %s successfully imported.", refLabel)
+	return networkInstance.ToProtocol()
+}
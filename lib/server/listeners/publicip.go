@@ -0,0 +1,358 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package listeners
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/asaskevich/govalidator"
+	googleprotobuf "github.com/golang/protobuf/ptypes/empty"
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/protocol"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	hostfactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/host"
+	publicipfactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/publicip"
+	"github.com/CS-SI/SafeScale/lib/server/resources/operations/converters"
+	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// PublicIPListener public IP service server grpc
+type PublicIPListener struct {
+	protocol.UnimplementedPublicIPServiceServer
+}
+
+// Create allocates a new Public IP
+func (s *PublicIPListener) Create(ctx context.Context, in *protocol.PublicIPCreateRequest) (_ *protocol.PublicIPResponse, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot create Public IP")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	if ok, err := govalidator.ValidateStruct(in); err != nil || !ok {
+		logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+	}
+
+	name := in.GetName()
+	if name == "" {
+		return nil, fail.InvalidRequestError("Public IP name cannot be empty string")
+	}
+
+	job, err := PrepareJob(ctx, in.GetTenantId(), fmt.Sprintf("/publicip/%s/create", name))
+	if err != nil {
+		return nil, err
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.publicip"), "('%s', '%s')", name, in.GetType()).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	pipInstance, xerr := publicipfactory.New(job.Service())
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	req := abstract.PublicIPRequest{
+		Name:        name,
+		Type:        in.GetType(),
+		Description: in.GetDescription(),
+	}
+	xerr = pipInstance.Create(job.Context(), req)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	defer pipInstance.Released()
+
+	tracer.Trace("Public IP '%s' successfully created", name)
+	return pipInstance.ToProtocol()
+}
+
+// List lists Public IPs managed by SafeScale only, or all Public IPs allocated in the tenant
+func (s *PublicIPListener) List(ctx context.Context, in *protocol.PublicIPListRequest) (_ *protocol.PublicIPListResponse, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot list Public IPs")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	if ok, err := govalidator.ValidateStruct(in); err != nil || !ok {
+		logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+	}
+
+	job, err := PrepareJob(ctx, in.GetTenantId(), "/publicips/list")
+	if err != nil {
+		return nil, err
+	}
+	defer job.Close()
+
+	all := in.GetAll()
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.publicip"), "(%v)", all).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	list, xerr := publicipfactory.List(job.Context(), job.Service(), all)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	out := &protocol.PublicIPListResponse{}
+	out.PublicIps = make([]*protocol.PublicIPResponse, 0, len(list))
+	for _, v := range list {
+		out.PublicIps = append(out.PublicIps, converters.PublicIPFromAbstractToProtocol(v))
+	}
+	return out, nil
+}
+
+// Inspect returns information about a Public IP
+func (s *PublicIPListener) Inspect(ctx context.Context, in *protocol.Reference) (_ *protocol.PublicIPResponse, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot inspect Public IP")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	if ok, err := govalidator.ValidateStruct(in); err != nil || !ok {
+		logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+	}
+
+	ref, refLabel := srvutils.GetReference(in)
+	if ref == "" {
+		return nil, fail.InvalidRequestError("neither name nor id given as reference")
+	}
+
+	job, err := PrepareJob(ctx, in.GetTenantId(), fmt.Sprintf("/publicip/%s/inspect", ref))
+	if err != nil {
+		return nil, err
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.publicip"), "(%s)", refLabel).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	pipInstance, xerr := publicipfactory.Load(job.Service(), ref)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	defer pipInstance.Released()
+
+	return pipInstance.ToProtocol()
+}
+
+// Delete releases a Public IP
+func (s *PublicIPListener) Delete(ctx context.Context, in *protocol.PublicIPDeleteRequest) (empty *googleprotobuf.Empty, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot delete Public IP")
+
+	empty = &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return empty, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return empty, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	if ok, err := govalidator.ValidateStruct(in); err != nil || !ok {
+		logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+	}
+
+	ref, refLabel := srvutils.GetReference(in.GetIp())
+	if ref == "" {
+		return empty, fail.InvalidRequestError("neither name nor id given as reference")
+	}
+
+	job, err := PrepareJob(ctx, in.GetTenantId(), fmt.Sprintf("/publicip/%s/delete", ref))
+	if err != nil {
+		return empty, err
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.publicip"), "(%s, %v)", refLabel, in.GetForce()).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	pipInstance, xerr := publicipfactory.Load(job.Service(), ref)
+	if xerr != nil {
+		return empty, xerr
+	}
+
+	xerr = pipInstance.Delete(job.Context(), in.GetForce())
+	if xerr != nil {
+		return empty, xerr
+	}
+
+	tracer.Trace("Public IP %s successfully deleted", refLabel)
+	return empty, nil
+}
+
+// Bind binds a Public IP to a Host, moving it from the Host currently using it if needed
+func (s *PublicIPListener) Bind(ctx context.Context, in *protocol.PublicIPBindRequest) (empty *googleprotobuf.Empty, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot bind Public IP to Host")
+
+	empty = &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return empty, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return empty, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	if ok, err := govalidator.ValidateStruct(in); err != nil || !ok {
+		logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+	}
+
+	pipRef, pipRefLabel := srvutils.GetReference(in.GetIp())
+	if pipRef == "" {
+		return empty, fail.InvalidRequestError("neither name nor id given as reference for Public IP")
+	}
+	hostRef, hostRefLabel := srvutils.GetReference(in.GetHost())
+	if hostRef == "" {
+		return empty, fail.InvalidRequestError("neither name nor id given as reference for Host")
+	}
+
+	job, err := PrepareJob(ctx, in.GetTenantId(), fmt.Sprintf("/publicip/%s/host/%s/bind", pipRef, hostRef))
+	if err != nil {
+		return empty, err
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.publicip"), "(%s, %s)", pipRefLabel, hostRefLabel).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	svc := job.Service()
+	pipInstance, xerr := publicipfactory.Load(svc, pipRef)
+	if xerr != nil {
+		return empty, xerr
+	}
+
+	defer pipInstance.Released()
+
+	hostInstance, xerr := hostfactory.Load(svc, hostRef)
+	if xerr != nil {
+		return empty, xerr
+	}
+
+	defer hostInstance.Released()
+
+	xerr = pipInstance.BindToHost(job.Context(), hostInstance)
+	if xerr != nil {
+		return empty, xerr
+	}
+
+	tracer.Trace("Public IP %s successfully bound to Host %s", pipRefLabel, hostRefLabel)
+	return empty, nil
+}
+
+// Unbind unbinds a Public IP from a Host
+func (s *PublicIPListener) Unbind(ctx context.Context, in *protocol.PublicIPBindRequest) (empty *googleprotobuf.Empty, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot unbind Public IP from Host")
+
+	empty = &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return empty, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return empty, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	if ok, err := govalidator.ValidateStruct(in); err != nil || !ok {
+		logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+	}
+
+	pipRef, pipRefLabel := srvutils.GetReference(in.GetIp())
+	if pipRef == "" {
+		return empty, fail.InvalidRequestError("neither name nor id given as reference for Public IP")
+	}
+	hostRef, hostRefLabel := srvutils.GetReference(in.GetHost())
+	if hostRef == "" {
+		return empty, fail.InvalidRequestError("neither name nor id given as reference for Host")
+	}
+
+	job, err := PrepareJob(ctx, in.GetTenantId(), fmt.Sprintf("/publicip/%s/host/%s/unbind", pipRef, hostRef))
+	if err != nil {
+		return empty, err
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.publicip"), "(%s, %s)", pipRefLabel, hostRefLabel).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	svc := job.Service()
+	pipInstance, xerr := publicipfactory.Load(svc, pipRef)
+	if xerr != nil {
+		return empty, xerr
+	}
+
+	defer pipInstance.Released()
+
+	hostInstance, xerr := hostfactory.Load(svc, hostRef)
+	if xerr != nil {
+		return empty, xerr
+	}
+
+	defer hostInstance.Released()
+
+	xerr = pipInstance.UnbindFromHost(job.Context(), hostInstance)
+	if xerr != nil {
+		return empty, xerr
+	}
+
+	tracer.Trace("Public IP %s successfully unbound from Host %s", pipRefLabel, hostRefLabel)
+	return empty, nil
+}
@@ -30,6 +30,7 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/CS-SI/SafeScale/lib/protocol"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
 	securitygroupfactory "github.com/CS-SI/SafeScale/lib/server/resources/factories/securitygroup"
 	"github.com/CS-SI/SafeScale/lib/server/resources/operations/converters"
 	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
@@ -589,3 +590,101 @@ func (s *SecurityGroupListener) Bonds(ctx context.Context, in *protocol.Security
 
 	return out, nil
 }
+
+// Check compares the rules of a Security Group on provider side with the ones registered in metadata, and fixes the
+// differences if requested
+func (s *SecurityGroupListener) Check(ctx context.Context, in *protocol.SecurityGroupCheckRequest) (_ *protocol.SecurityGroupDrift, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot check Security Group")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+
+	ref, refLabel := srvutils.GetReference(in.GetGroup())
+	if ref == "" {
+		return nil, fail.InvalidRequestError("neither name nor id given as reference")
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetGroup().GetTenantId(), fmt.Sprintf("/securitygroup/%s/check", ref))
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.security-group"), "(%s, fix=%v, adopt=%v)", refLabel, in.GetFix(), in.GetAdopt()).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	sgInstance, xerr := securitygroupfactory.Load(job.Service(), ref)
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer sgInstance.Released()
+
+	var drift *abstract.SecurityGroupDrift
+	if in.GetFix() {
+		drift, xerr = sgInstance.Reconcile(job.Context(), in.GetAdopt())
+	} else {
+		drift, xerr = sgInstance.CheckConsistency(job.Context())
+	}
+	if xerr != nil {
+		return nil, xerr
+	}
+	return converters.SecurityGroupDriftFromAbstractToProtocol(drift), nil
+}
+
+// Import registers in metadata an existing security group of the provider
+func (s *SecurityGroupListener) Import(ctx context.Context, in *protocol.SecurityGroupImportRequest) (_ *protocol.SecurityGroupResponse, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot import security group")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	networkRef, _ := srvutils.GetReference(in.GetNetwork())
+	if networkRef == "" {
+		return nil, fail.InvalidRequestError("neither name nor id given as reference for network")
+	}
+	sgRef, sgRefLabel := srvutils.GetReference(in.GetGroup())
+	if sgRef == "" {
+		return nil, fail.InvalidRequestError("neither name nor id given as reference for security group")
+	}
+
+	job, err := PrepareJob(ctx, in.GetNetwork().GetTenantId(), fmt.Sprintf("/network/%s/securitygroup/%s/import", networkRef, sgRef))
+	if err != nil {
+		return nil, err
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.security-group"), "(%s)", sgRefLabel).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	sgInstance, xerr := securitygroupfactory.New(job.Service())
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	xerr = sgInstance.Import(job.Context(), networkRef, sgRef)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	defer sgInstance.Released()
+
+	return sgInstance.ToProtocol()
+}
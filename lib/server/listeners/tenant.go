@@ -26,9 +26,12 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/CS-SI/SafeScale/lib/protocol"
+	"github.com/CS-SI/SafeScale/lib/server"
 	"github.com/CS-SI/SafeScale/lib/server/handlers"
 	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
 	"github.com/CS-SI/SafeScale/lib/server/resources/operations"
+	"github.com/CS-SI/SafeScale/lib/server/resources/operations/converters"
 	// "github.com/CS-SI/SafeScale/lib/server/resources/operations/metadataupgrade"
 	"github.com/CS-SI/SafeScale/lib/utils/debug"
 	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
@@ -308,3 +311,102 @@ func (s *TenantListener) Upgrade(ctx context.Context, in *protocol.TenantUpgrade
 
 	return &protocol.TenantUpgradeResponse{}, nil
 }
+
+// Drift checks the consistency of the rules of all the Security Groups of a tenant, fixing them if requested
+func (s *TenantListener) Drift(ctx context.Context, in *protocol.TenantDriftRequest) (_ *protocol.TenantDriftResponse, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot check drift of tenant")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+
+	name := in.GetName()
+	job, xerr := PrepareJob(ctx, name, fmt.Sprintf("/tenant/%s/drift", name))
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.tenant"), "('%s', fix=%v, adopt=%v)", name, in.GetFix(), in.GetAdopt()).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	drifts, xerr := operations.CheckSecurityGroupsConsistency(job.Context(), job.Service(), in.GetFix(), in.GetAdopt())
+	out := &protocol.TenantDriftResponse{}
+	if xerr != nil {
+		list, ok := xerr.(*fail.ErrorList)
+		if !ok {
+			return nil, xerr
+		}
+		// failures on some Security Groups do not prevent to report the others
+		for _, v := range list.ToErrorSlice() {
+			out.Errors = append(out.Errors, v.Error())
+		}
+	}
+	for _, v := range drifts {
+		out.SecurityGroups = append(out.SecurityGroups, converters.SecurityGroupDriftFromAbstractToProtocol(v))
+	}
+	return out, nil
+}
+
+// Reconcile compares the metadata of a tenant with the resources on provider side, and fixes the differences on demand
+func (s *TenantListener) Reconcile(ctx context.Context, in *protocol.TenantReconcileRequest) (_ *protocol.TenantReconcileResponse, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot reconcile tenant")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+
+	name := in.GetName()
+	job, xerr := PrepareJob(ctx, name, fmt.Sprintf("/tenant/%s/reconcile", name))
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+
+	opts := abstract.ReconcileOptions{
+		Kinds:      in.GetKinds(),
+		DryRun:     in.GetDryRun(),
+		Import:     in.GetImport(),
+		PurgeCloud: in.GetPurgeCloud(),
+		Purge:      in.GetPurge(),
+		Repair:     in.GetRepair(),
+		// resources without metadata younger than the oldest running job may be registered by it
+		MinAge: server.LongestJobDuration(job.Tenant()),
+		Only:   in.GetOnly(),
+	}
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.tenant"), "('%s', %v)", name, opts).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	entries, xerr := operations.ReconcileTenant(job.Context(), job.Service(), opts)
+	out := &protocol.TenantReconcileResponse{}
+	if xerr != nil {
+		list, ok := xerr.(*fail.ErrorList)
+		if !ok {
+			return nil, xerr
+		}
+		// failures on some kinds of resources do not prevent to report the others
+		for _, v := range list.ToErrorSlice() {
+			out.Errors = append(out.Errors, v.Error())
+		}
+	}
+	for _, v := range entries {
+		out.Entries = append(out.Entries, converters.ReconcileEntryFromAbstractToProtocol(v))
+	}
+	return out, nil
+}
@@ -28,7 +28,9 @@ import (
 
 	"github.com/CS-SI/SafeScale/lib/protocol"
 	"github.com/CS-SI/SafeScale/lib/server/handlers"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumespeed"
+	"github.com/CS-SI/SafeScale/lib/server/resources/operations/converters"
 	srvutils "github.com/CS-SI/SafeScale/lib/server/utils"
 	"github.com/CS-SI/SafeScale/lib/utils/debug"
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
@@ -88,11 +90,15 @@ func (s *VolumeListener) List(ctx context.Context, in *protocol.VolumeListReques
 
 	// Map resources.Volume to protocol.Volume
 	var pbvolumes []*protocol.VolumeInspectResponse
+	selector := in.GetLabels()
 	for _, v := range volumes {
 		pbVolume, xerr := v.ToProtocol()
 		if xerr != nil {
 			return nil, xerr
 		}
+		if !abstract.MatchLabels(pbVolume.GetLabels(), selector) {
+			continue
+		}
 
 		pbvolumes = append(pbvolumes, pbVolume)
 	}
@@ -133,7 +139,7 @@ func (s *VolumeListener) Create(ctx context.Context, in *protocol.VolumeCreateRe
 	defer tracer.Exiting()
 	defer fail.OnExitLogError(&err, tracer.TraceMessage())
 	handler := handlers.NewVolumeHandler(job)
-	rv, xerr := handler.Create(name, int(size), volumespeed.Enum(speed))
+	rv, xerr := handler.Create(name, int(size), volumespeed.Enum(speed), in.GetLabels())
 	if xerr != nil {
 		return nil, xerr
 	}
@@ -336,3 +342,273 @@ func (s *VolumeListener) Inspect(ctx context.Context, in *protocol.Reference) (_
 
 	return rv.ToProtocol()
 }
+
+// SnapshotCreate takes a snapshot of a volume
+func (s *VolumeListener) SnapshotCreate(ctx context.Context, in *protocol.VolumeSnapshotCreateRequest) (_ *protocol.VolumeSnapshotResponse, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot create volume snapshot")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	volumeRef, volumeRefLabel := srvutils.GetReference(in.GetVolume())
+	if volumeRef == "" {
+		return nil, fail.InvalidRequestError("neither name nor id given as reference of volume")
+	}
+	name := in.GetName()
+	if name == "" {
+		return nil, fail.InvalidRequestError("snapshot name cannot be empty string")
+	}
+
+	if ok, err := govalidator.ValidateStruct(in); err != nil || !ok {
+		logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), fmt.Sprintf("/volume/%s/snapshot/%s/create", volumeRef, name))
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.volume"), "(%s, '%s')", volumeRefLabel, name).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	handler := VolumeHandler(job)
+	rs, xerr := handler.CreateSnapshot(volumeRef, name, in.GetDescription())
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	tracer.Trace("Snapshot '%s' of volume %s created", name, volumeRefLabel)
+	return rs.ToProtocol()
+}
+
+// SnapshotList lists the snapshots of a volume (or of all volumes if no volume is given)
+func (s *VolumeListener) SnapshotList(ctx context.Context, in *protocol.VolumeSnapshotListRequest) (_ *protocol.VolumeSnapshotListResponse, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot list volume snapshots")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	if ok, err := govalidator.ValidateStruct(in); err != nil || !ok {
+		logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+	}
+
+	volumeRef, volumeRefLabel := srvutils.GetReference(in.GetVolume())
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), "/volume/snapshots/list")
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+
+	all := in.GetAll()
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.volume"), "(%s, %v)", volumeRefLabel, all).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	handler := VolumeHandler(job)
+	list, xerr := handler.ListSnapshots(volumeRef, all)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	out := &protocol.VolumeSnapshotListResponse{}
+	out.Snapshots = make([]*protocol.VolumeSnapshotResponse, 0, len(list))
+	for _, v := range list {
+		out.Snapshots = append(out.Snapshots, converters.SnapshotFromAbstractToProtocol(v))
+	}
+	return out, nil
+}
+
+// SnapshotDelete deletes a volume snapshot
+func (s *VolumeListener) SnapshotDelete(ctx context.Context, in *protocol.Reference) (empty *googleprotobuf.Empty, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot delete volume snapshot")
+
+	empty = &googleprotobuf.Empty{}
+	if s == nil {
+		return empty, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return empty, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return empty, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	ref, refLabel := srvutils.GetReference(in)
+	if ref == "" {
+		return empty, fail.InvalidRequestError("neither name nor id given as reference")
+	}
+
+	if ok, err := govalidator.ValidateStruct(in); err != nil || !ok {
+		logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), fmt.Sprintf("/volume/snapshot/%s/delete", ref))
+	if xerr != nil {
+		return empty, xerr
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.volume"), "(%s)", refLabel).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	handler := VolumeHandler(job)
+	if xerr = handler.DeleteSnapshot(ref); xerr != nil {
+		return empty, xerr
+	}
+
+	logrus.Infof("Volume snapshot %s successfully deleted.", refLabel)
+	return empty, nil
+}
+
+// SnapshotRestore creates a new volume from a volume snapshot
+func (s *VolumeListener) SnapshotRestore(ctx context.Context, in *protocol.VolumeSnapshotRestoreRequest) (_ *protocol.VolumeInspectResponse, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot restore volume snapshot")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	snapshotRef, snapshotRefLabel := srvutils.GetReference(in.GetSnapshot())
+	if snapshotRef == "" {
+		return nil, fail.InvalidRequestError("neither name nor id given as reference of snapshot")
+	}
+	name := in.GetName()
+	if name == "" {
+		return nil, fail.InvalidRequestError("volume name cannot be empty string")
+	}
+
+	if ok, err := govalidator.ValidateStruct(in); err != nil || !ok {
+		logrus.Warnf("Structure validation failure: %v", in) // FIXME: Generate json tags in protobuf
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), fmt.Sprintf("/volume/snapshot/%s/restore/%s", snapshotRef, name))
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+
+	speed := in.GetSpeed()
+	size := in.GetSize()
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.volume"), "(%s, '%s', %s, %d)", snapshotRefLabel, name, speed.String(), size).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	handler := VolumeHandler(job)
+	rv, xerr := handler.RestoreSnapshot(snapshotRef, name, int(size), volumespeed.Enum(speed))
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	tracer.Trace("Volume '%s' restored from snapshot %s", name, snapshotRefLabel)
+	return rv.ToProtocol()
+}
+
+// Import registers in metadata an existing volume of the provider
+func (s *VolumeListener) Import(ctx context.Context, in *protocol.Reference) (_ *protocol.VolumeInspectResponse, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot import volume")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	ref, refLabel := srvutils.GetReference(in)
+	if ref == "" {
+		return nil, fail.InvalidRequestError("neither name nor id given as reference")
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetTenantId(), fmt.Sprintf("/volume/%s/import", ref))
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.volume"), "(%s)", refLabel).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	handler := VolumeHandler(job)
+	rv, xerr := handler.Import(ref)
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer rv.Released()
+
+	tracer.Trace("Volume %s imported", refLabel)
+	return rv.ToProtocol()
+}
+
+// Resize grows a volume, and its file system on the hosts where it is attached
+func (s *VolumeListener) Resize(ctx context.Context, in *protocol.VolumeResizeRequest) (_ *protocol.VolumeInspectResponse, err error) {
+	defer fail.OnExitConvertToGRPCStatus(&err)
+	defer fail.OnExitWrapError(&err, "cannot resize volume")
+
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	if in == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("in")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	ref, refLabel := srvutils.GetReference(in.GetVolume())
+	if ref == "" {
+		return nil, fail.InvalidRequestError("neither name nor id given as reference")
+	}
+	size := int(in.GetSize())
+	if size <= 0 {
+		return nil, fail.InvalidRequestError("size must be greater than 0")
+	}
+
+	job, xerr := PrepareJob(ctx, in.GetVolume().GetTenantId(), fmt.Sprintf("/volume/%s/resize", ref))
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer job.Close()
+
+	tracer := debug.NewTracer(job.Task(), tracing.ShouldTrace("listeners.volume"), "(%s, %d)", refLabel, size).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&err, tracer.TraceMessage())
+
+	handler := VolumeHandler(job)
+	rv, xerr := handler.Resize(ref, size)
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer rv.Released()
+
+	tracer.Trace("Volume %s resized to %d GB", refLabel, size)
+	return rv.ToProtocol()
+}
@@ -0,0 +1,547 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package manifest describes a whole infrastructure (networks, hosts, volumes, ...) in a declarative way,
+// to be converged by safescaled
+package manifest
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+	"gopkg.in/yaml.v2"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clustercomplexity"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterflavor"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/ipversion"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/securitygroupruledirection"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumespeed"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// nameRegexp restricts the name of a manifest, used to name the object keeping its state in metadata
+var nameRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.\-]*$`)
+
+// Manifest describes the resources that must exist
+// When the manifest is named, the resources it declares are remembered at each apply, allowing to detect the ones
+// removed from it since.
+type Manifest struct {
+	Name           string          `yaml:"name,omitempty" toml:"name,omitempty"`
+	Networks       []Network       `yaml:"networks,omitempty" toml:"networks,omitempty"`
+	SecurityGroups []SecurityGroup `yaml:"security_groups,omitempty" toml:"security_groups,omitempty"`
+	Hosts          []Host          `yaml:"hosts,omitempty" toml:"hosts,omitempty"`
+	Volumes        []Volume        `yaml:"volumes,omitempty" toml:"volumes,omitempty"`
+	Shares         []Share         `yaml:"shares,omitempty" toml:"shares,omitempty"`
+	Buckets        []Bucket        `yaml:"buckets,omitempty" toml:"buckets,omitempty"`
+	Clusters       []Cluster       `yaml:"clusters,omitempty" toml:"clusters,omitempty"`
+}
+
+// Network describes a Network and its Subnets
+type Network struct {
+	Name       string            `yaml:"name" toml:"name"`
+	CIDR       string            `yaml:"cidr,omitempty" toml:"cidr,omitempty"`
+	DNSServers []string          `yaml:"dns_servers,omitempty" toml:"dns_servers,omitempty"`
+	Labels     map[string]string `yaml:"labels,omitempty" toml:"labels,omitempty"`
+	Subnets    []Subnet          `yaml:"subnets,omitempty" toml:"subnets,omitempty"`
+}
+
+// Subnet describes a Subnet inside a Network
+type Subnet struct {
+	Name     string  `yaml:"name" toml:"name"`
+	CIDR     string  `yaml:"cidr" toml:"cidr"`
+	Failover bool    `yaml:"failover,omitempty" toml:"failover,omitempty"`
+	Gateway  Gateway `yaml:"gateway,omitempty" toml:"gateway,omitempty"`
+}
+
+// Gateway describes the gateway(s) of a Subnet
+type Gateway struct {
+	Name    string `yaml:"name,omitempty" toml:"name,omitempty"`
+	OS      string `yaml:"os,omitempty" toml:"os,omitempty"`
+	Sizing  string `yaml:"sizing,omitempty" toml:"sizing,omitempty"`
+	SSHPort uint32 `yaml:"ssh_port,omitempty" toml:"ssh_port,omitempty"`
+}
+
+// SecurityGroup describes a Security Group owned by a Network
+type SecurityGroup struct {
+	Name        string `yaml:"name" toml:"name"`
+	Network     string `yaml:"network" toml:"network"`
+	Description string `yaml:"description,omitempty" toml:"description,omitempty"`
+	Rules       []Rule `yaml:"rules,omitempty" toml:"rules,omitempty"`
+}
+
+// Rule describes a rule of a Security Group
+type Rule struct {
+	Description string   `yaml:"description,omitempty" toml:"description,omitempty"`
+	Direction   string   `yaml:"direction" toml:"direction"`
+	Type        string   `yaml:"type,omitempty" toml:"type,omitempty"`
+	Protocol    string   `yaml:"protocol,omitempty" toml:"protocol,omitempty"`
+	PortFrom    int32    `yaml:"port_from,omitempty" toml:"port_from,omitempty"`
+	PortTo      int32    `yaml:"port_to,omitempty" toml:"port_to,omitempty"`
+	CIDR        []string `yaml:"cidr,omitempty" toml:"cidr,omitempty"`
+}
+
+// Host describes a Host
+type Host struct {
+	Name           string            `yaml:"name" toml:"name"`
+	Network        string            `yaml:"network,omitempty" toml:"network,omitempty"`
+	Subnet         string            `yaml:"subnet,omitempty" toml:"subnet,omitempty"`
+	Single         bool              `yaml:"single,omitempty" toml:"single,omitempty"`
+	OS             string            `yaml:"os,omitempty" toml:"os,omitempty"`
+	Sizing         string            `yaml:"sizing,omitempty" toml:"sizing,omitempty"`
+	Domain         string            `yaml:"domain,omitempty" toml:"domain,omitempty"`
+	SecurityGroups []string          `yaml:"security_groups,omitempty" toml:"security_groups,omitempty"`
+	Labels         map[string]string `yaml:"labels,omitempty" toml:"labels,omitempty"`
+}
+
+// Volume describes a Volume, optionally attached to a Host
+type Volume struct {
+	Name   string            `yaml:"name" toml:"name"`
+	Size   int               `yaml:"size,omitempty" toml:"size,omitempty"`
+	Speed  string            `yaml:"speed,omitempty" toml:"speed,omitempty"`
+	Labels map[string]string `yaml:"labels,omitempty" toml:"labels,omitempty"`
+	Attach *Attachment       `yaml:"attach,omitempty" toml:"attach,omitempty"`
+}
+
+// Attachment describes where a Volume is attached
+type Attachment struct {
+	Host        string `yaml:"host" toml:"host"`
+	Path        string `yaml:"path,omitempty" toml:"path,omitempty"`
+	Format      string `yaml:"format,omitempty" toml:"format,omitempty"`
+	DoNotFormat bool   `yaml:"do_not_format,omitempty" toml:"do_not_format,omitempty"`
+}
+
+// Share describes a Share exported by a Host and the Hosts mounting it
+type Share struct {
+	Name    string  `yaml:"name" toml:"name"`
+	Host    string  `yaml:"host" toml:"host"`
+	Path    string  `yaml:"path" toml:"path"`
+	Options string  `yaml:"options,omitempty" toml:"options,omitempty"`
+	Mounts  []Mount `yaml:"mounts,omitempty" toml:"mounts,omitempty"`
+}
+
+// Mount describes where a Share or a Bucket is mounted
+type Mount struct {
+	Host      string `yaml:"host" toml:"host"`
+	Path      string `yaml:"path,omitempty" toml:"path,omitempty"`
+	WithCache bool   `yaml:"with_cache,omitempty" toml:"with_cache,omitempty"`
+}
+
+// Bucket describes a Bucket, optionally mounted on a Host
+type Bucket struct {
+	Name  string `yaml:"name" toml:"name"`
+	Mount *Mount `yaml:"mount,omitempty" toml:"mount,omitempty"`
+}
+
+// Cluster describes a Cluster
+type Cluster struct {
+	Name          string            `yaml:"name" toml:"name"`
+	Flavor        string            `yaml:"flavor,omitempty" toml:"flavor,omitempty"`
+	Complexity    string            `yaml:"complexity,omitempty" toml:"complexity,omitempty"`
+	CIDR          string            `yaml:"cidr,omitempty" toml:"cidr,omitempty"`
+	Domain        string            `yaml:"domain,omitempty" toml:"domain,omitempty"`
+	OS            string            `yaml:"os,omitempty" toml:"os,omitempty"`
+	Sizing        string            `yaml:"sizing,omitempty" toml:"sizing,omitempty"`
+	GatewaySizing string            `yaml:"gateway_sizing,omitempty" toml:"gateway_sizing,omitempty"`
+	MasterSizing  string            `yaml:"master_sizing,omitempty" toml:"master_sizing,omitempty"`
+	NodeSizing    string            `yaml:"node_sizing,omitempty" toml:"node_sizing,omitempty"`
+	Disabled      []string          `yaml:"disabled,omitempty" toml:"disabled,omitempty"`
+	Labels        map[string]string `yaml:"labels,omitempty" toml:"labels,omitempty"`
+}
+
+// Decode reads a manifest from its content
+// format may be "yaml", "yml", "json" or "toml"; JSON is decoded as YAML, of which it is a subset.
+func Decode(content []byte, format string) (*Manifest, fail.Error) {
+	if len(content) == 0 {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("content")
+	}
+
+	m := &Manifest{}
+	switch strings.ToLower(strings.TrimPrefix(format, ".")) {
+	case "", "yaml", "yml", "json":
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+		decoder.SetStrict(true)
+		if err := decoder.Decode(m); err != nil {
+			return nil, fail.SyntaxError("failed to decode manifest: %s", err.Error())
+		}
+	case "toml":
+		if err := toml.Unmarshal(content, m); err != nil {
+			return nil, fail.SyntaxError("failed to decode manifest: %s", err.Error())
+		}
+	default:
+		return nil, fail.InvalidParameterError("format", "must be 'yaml', 'json' or 'toml'")
+	}
+
+	if xerr := m.Validate(); xerr != nil {
+		return nil, xerr
+	}
+	return m, nil
+}
+
+// IsEmpty tells if the manifest does not describe any resource
+func (m *Manifest) IsEmpty() bool {
+	return m == nil || (len(m.Networks) == 0 && len(m.SecurityGroups) == 0 && len(m.Hosts) == 0 && len(m.Volumes) == 0 &&
+		len(m.Shares) == 0 && len(m.Buckets) == 0 && len(m.Clusters) == 0)
+}
+
+// Validate checks the content of the manifest, without looking at existing resources
+func (m *Manifest) Validate() fail.Error {
+	if m == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	seen := map[string]map[string]struct{}{}
+	unique := func(kind, name string) fail.Error {
+		if name == "" {
+			return fail.InvalidRequestError("a %s without name is declared", kind)
+		}
+		if _, ok := seen[kind]; !ok {
+			seen[kind] = map[string]struct{}{}
+		}
+		if _, ok := seen[kind][name]; ok {
+			return fail.InvalidRequestError("%s '%s' is declared more than once", kind, name)
+		}
+		seen[kind][name] = struct{}{}
+		return nil
+	}
+
+	if m.Name != "" && !nameRegexp.MatchString(m.Name) {
+		return fail.InvalidRequestError("invalid manifest name '%s': must be alphanumeric characters, '-', '_' or '.', starting with an alphanumeric character", m.Name)
+	}
+	for _, n := range m.Networks {
+		if xerr := unique(KindNetwork, n.Name); xerr != nil {
+			return xerr
+		}
+		if xerr := abstract.ValidateLabels(n.Labels); xerr != nil {
+			return fail.Wrap(xerr, "invalid labels of network '%s'", n.Name)
+		}
+		for _, s := range n.Subnets {
+			if xerr := unique(KindSubnet, n.Name+"/"+s.Name); xerr != nil {
+				return xerr
+			}
+			if s.CIDR == "" {
+				return fail.InvalidRequestError("subnet '%s' of network '%s' has no cidr", s.Name, n.Name)
+			}
+		}
+	}
+	for _, sg := range m.SecurityGroups {
+		if xerr := unique(KindSecurityGroup, sg.Name); xerr != nil {
+			return xerr
+		}
+		if sg.Network == "" {
+			return fail.InvalidRequestError("security group '%s' has no network", sg.Name)
+		}
+		if _, xerr := sg.AbstractRules(); xerr != nil {
+			return fail.Wrap(xerr, "invalid rule in security group '%s'", sg.Name)
+		}
+	}
+	for _, h := range m.Hosts {
+		if xerr := unique(KindHost, h.Name); xerr != nil {
+			return xerr
+		}
+		if h.Single == (h.Network != "") {
+			return fail.InvalidRequestError("host '%s' must define either a network or single", h.Name)
+		}
+		if xerr := abstract.ValidateLabels(h.Labels); xerr != nil {
+			return fail.Wrap(xerr, "invalid labels of host '%s'", h.Name)
+		}
+	}
+	for _, v := range m.Volumes {
+		if xerr := unique(KindVolume, v.Name); xerr != nil {
+			return xerr
+		}
+		if v.Size < 0 {
+			return fail.InvalidRequestError("volume '%s' has a negative size", v.Name)
+		}
+		if _, xerr := v.AbstractSpeed(); xerr != nil {
+			return fail.Wrap(xerr, "invalid speed of volume '%s'", v.Name)
+		}
+		if v.Attach != nil && v.Attach.Host == "" {
+			return fail.InvalidRequestError("attachment of volume '%s' has no host", v.Name)
+		}
+		if xerr := abstract.ValidateLabels(v.Labels); xerr != nil {
+			return fail.Wrap(xerr, "invalid labels of volume '%s'", v.Name)
+		}
+	}
+	for _, s := range m.Shares {
+		if xerr := unique(KindShare, s.Name); xerr != nil {
+			return xerr
+		}
+		if s.Host == "" || s.Path == "" {
+			return fail.InvalidRequestError("share '%s' must define host and path", s.Name)
+		}
+		for _, v := range s.Mounts {
+			if v.Host == "" {
+				return fail.InvalidRequestError("a mount of share '%s' has no host", s.Name)
+			}
+		}
+	}
+	for _, b := range m.Buckets {
+		if xerr := unique(KindBucket, b.Name); xerr != nil {
+			return xerr
+		}
+		if b.Mount != nil && b.Mount.Host == "" {
+			return fail.InvalidRequestError("mount of bucket '%s' has no host", b.Name)
+		}
+	}
+	for _, c := range m.Clusters {
+		if xerr := unique(KindCluster, c.Name); xerr != nil {
+			return xerr
+		}
+		if c.Flavor != "" {
+			if _, err := clusterflavor.Parse(c.Flavor); err != nil {
+				return fail.InvalidRequestError("invalid flavor of cluster '%s': %s", c.Name, err.Error())
+			}
+		}
+		if c.Complexity != "" {
+			if _, err := clustercomplexity.Parse(c.Complexity); err != nil {
+				return fail.InvalidRequestError("invalid complexity of cluster '%s': %s", c.Name, err.Error())
+			}
+		}
+		if xerr := abstract.ValidateLabels(c.Labels); xerr != nil {
+			return fail.Wrap(xerr, "invalid labels of cluster '%s'", c.Name)
+		}
+	}
+	return nil
+}
+
+// AbstractRules converts the rules of the Security Group to abstract.SecurityGroupRules
+func (sg SecurityGroup) AbstractRules() (abstract.SecurityGroupRules, fail.Error) {
+	out := make(abstract.SecurityGroupRules, 0, len(sg.Rules))
+	for _, v := range sg.Rules {
+		etherType := ipversion.IPv4
+		if v.Type != "" {
+			var xerr fail.Error
+			if etherType, xerr = ipversion.Parse(v.Type); xerr != nil {
+				return nil, xerr
+			}
+		}
+		direction, xerr := securitygroupruledirection.Parse(v.Direction)
+		if xerr != nil {
+			return nil, xerr
+		}
+
+		rule := &abstract.SecurityGroupRule{
+			Description: v.Description,
+			EtherType:   etherType,
+			Direction:   direction,
+			Protocol:    v.Protocol,
+			PortFrom:    v.PortFrom,
+			PortTo:      v.PortTo,
+		}
+		switch direction {
+		case securitygroupruledirection.Ingress:
+			rule.Sources = v.CIDR
+		case securitygroupruledirection.Egress:
+			rule.Targets = v.CIDR
+		}
+		if xerr = rule.Validate(); xerr != nil {
+			return nil, xerr
+		}
+		out = append(out, rule)
+	}
+	return out, nil
+}
+
+// AbstractSpeed converts the speed of the Volume to volumespeed.Enum (HDD by default)
+func (v Volume) AbstractSpeed() (volumespeed.Enum, fail.Error) {
+	switch strings.ToLower(v.Speed) {
+	case "", "hdd":
+		return volumespeed.Hdd, nil
+	case "ssd":
+		return volumespeed.Ssd, nil
+	case "cold":
+		return volumespeed.Cold, nil
+	default:
+		return volumespeed.Hdd, fail.InvalidRequestError("speed must be 'HDD', 'SSD' or 'COLD'")
+	}
+}
+
+// Declares tells if the manifest declares the resource of kind named name
+// The name of a Subnet is '<network name>/<subnet name>'.
+func (m *Manifest) Declares(kind, name string) bool {
+	if m == nil {
+		return false
+	}
+
+	switch kind {
+	case KindNetwork:
+		for _, v := range m.Networks {
+			if v.Name == name {
+				return true
+			}
+		}
+	case KindSubnet:
+		for _, n := range m.Networks {
+			for _, v := range n.Subnets {
+				if n.Name+"/"+v.Name == name {
+					return true
+				}
+			}
+		}
+	case KindSecurityGroup:
+		for _, v := range m.SecurityGroups {
+			if v.Name == name {
+				return true
+			}
+		}
+	case KindHost:
+		for _, v := range m.Hosts {
+			if v.Name == name {
+				return true
+			}
+		}
+	case KindVolume:
+		for _, v := range m.Volumes {
+			if v.Name == name {
+				return true
+			}
+		}
+	case KindShare:
+		for _, v := range m.Shares {
+			if v.Name == name {
+				return true
+			}
+		}
+	case KindBucket:
+		for _, v := range m.Buckets {
+			if v.Name == name {
+				return true
+			}
+		}
+	case KindCluster:
+		for _, v := range m.Clusters {
+			if v.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Removed returns the resources declared by previous that m does not declare anymore, identified by their names only
+// A Subnet removed from a Network still declared is returned inside this Network, which m still declares.
+func (m *Manifest) Removed(previous *Manifest) *Manifest {
+	out := &Manifest{Name: m.Name}
+	if previous == nil {
+		return out
+	}
+
+	for _, n := range previous.Networks {
+		var subnets []Subnet
+		for _, s := range n.Subnets {
+			if !m.Declares(KindSubnet, n.Name+"/"+s.Name) {
+				subnets = append(subnets, Subnet{Name: s.Name})
+			}
+		}
+		if len(subnets) > 0 || !m.Declares(KindNetwork, n.Name) {
+			out.Networks = append(out.Networks, Network{Name: n.Name, Subnets: subnets})
+		}
+	}
+	for _, v := range previous.SecurityGroups {
+		if !m.Declares(KindSecurityGroup, v.Name) {
+			out.SecurityGroups = append(out.SecurityGroups, SecurityGroup{Name: v.Name, Network: v.Network})
+		}
+	}
+	for _, v := range previous.Hosts {
+		if !m.Declares(KindHost, v.Name) {
+			out.Hosts = append(out.Hosts, Host{Name: v.Name})
+		}
+	}
+	for _, v := range previous.Volumes {
+		if !m.Declares(KindVolume, v.Name) {
+			out.Volumes = append(out.Volumes, Volume{Name: v.Name})
+		}
+	}
+	for _, v := range previous.Shares {
+		if !m.Declares(KindShare, v.Name) {
+			out.Shares = append(out.Shares, Share{Name: v.Name})
+		}
+	}
+	for _, v := range previous.Buckets {
+		if !m.Declares(KindBucket, v.Name) {
+			out.Buckets = append(out.Buckets, Bucket{Name: v.Name})
+		}
+	}
+	for _, v := range previous.Clusters {
+		if !m.Declares(KindCluster, v.Name) {
+			out.Clusters = append(out.Clusters, Cluster{Name: v.Name})
+		}
+	}
+	return out
+}
+
+// Merge returns the resources declared by m or by other, identified by their names only
+func (m *Manifest) Merge(other *Manifest) *Manifest {
+	out := &Manifest{Name: m.Name}
+	for _, list := range []*Manifest{m, other} {
+		if list == nil {
+			continue
+		}
+
+		for _, n := range list.Networks {
+			index := -1
+			for k, v := range out.Networks {
+				if v.Name == n.Name {
+					index = k
+					break
+				}
+			}
+			if index < 0 {
+				out.Networks = append(out.Networks, Network{Name: n.Name})
+				index = len(out.Networks) - 1
+			}
+			for _, s := range n.Subnets {
+				if !out.Declares(KindSubnet, n.Name+"/"+s.Name) {
+					out.Networks[index].Subnets = append(out.Networks[index].Subnets, Subnet{Name: s.Name})
+				}
+			}
+		}
+		for _, v := range list.SecurityGroups {
+			if !out.Declares(KindSecurityGroup, v.Name) {
+				out.SecurityGroups = append(out.SecurityGroups, SecurityGroup{Name: v.Name, Network: v.Network})
+			}
+		}
+		for _, v := range list.Hosts {
+			if !out.Declares(KindHost, v.Name) {
+				out.Hosts = append(out.Hosts, Host{Name: v.Name})
+			}
+		}
+		for _, v := range list.Volumes {
+			if !out.Declares(KindVolume, v.Name) {
+				out.Volumes = append(out.Volumes, Volume{Name: v.Name})
+			}
+		}
+		for _, v := range list.Shares {
+			if !out.Declares(KindShare, v.Name) {
+				out.Shares = append(out.Shares, Share{Name: v.Name})
+			}
+		}
+		for _, v := range list.Buckets {
+			if !out.Declares(KindBucket, v.Name) {
+				out.Buckets = append(out.Buckets, Bucket{Name: v.Name})
+			}
+		}
+		for _, v := range list.Clusters {
+			if !out.Declares(KindCluster, v.Name) {
+				out.Clusters = append(out.Clusters, Cluster{Name: v.Name})
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,174 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package manifest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/securitygroupruledirection"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumespeed"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+const yamlManifest = `
+networks:
+  - name: net-prod
+    cidr: 192.168.0.0/23
+    labels:
+      Cost-Center: CC042
+    subnets:
+      - name: front
+        cidr: 192.168.0.0/24
+        gateway:
+          sizing: "cpu=2,ram>=4"
+security_groups:
+  - name: sg-web
+    network: net-prod
+    rules:
+      - direction: ingress
+        protocol: tcp
+        port_from: 443
+        cidr: [0.0.0.0/0]
+hosts:
+  - name: web1
+    network: net-prod
+    subnet: front
+    security_groups: [sg-web]
+volumes:
+  - name: data
+    size: 50
+    speed: ssd
+    attach:
+      host: web1
+      path: /data
+shares:
+  - name: shared
+    host: web1
+    path: /shared
+buckets:
+  - name: backups
+clusters:
+  - name: k8s
+    flavor: K8S
+    complexity: Small
+`
+
+func TestDecode_YAML(t *testing.T) {
+	m, xerr := Decode([]byte(yamlManifest), "yml")
+	require.Nil(t, xerr)
+	require.Len(t, m.Networks, 1)
+	assert.Equal(t, "CC042", m.Networks[0].Labels["Cost-Center"])
+	require.Len(t, m.Networks[0].Subnets, 1)
+	assert.Equal(t, "cpu=2,ram>=4", m.Networks[0].Subnets[0].Gateway.Sizing)
+	assert.Equal(t, []string{"sg-web"}, m.Hosts[0].SecurityGroups)
+	assert.Equal(t, "/data", m.Volumes[0].Attach.Path)
+	speed, xerr := m.Volumes[0].AbstractSpeed()
+	require.Nil(t, xerr)
+	assert.Equal(t, volumespeed.Ssd, speed)
+
+	rules, xerr := m.SecurityGroups[0].AbstractRules()
+	require.Nil(t, xerr)
+	require.Len(t, rules, 1)
+	assert.Equal(t, securitygroupruledirection.Ingress, rules[0].Direction)
+	assert.Equal(t, []string{"0.0.0.0/0"}, rules[0].Sources)
+}
+
+func TestDecode_TOML(t *testing.T) {
+	content := `
+[[volumes]]
+name = "data"
+size = 20
+
+[[buckets]]
+name = "backups"
+[buckets.mount]
+host = "web1"
+path = "/backups"
+`
+	m, xerr := Decode([]byte(content), "toml")
+	require.Nil(t, xerr)
+	require.Len(t, m.Volumes, 1)
+	assert.Equal(t, 20, m.Volumes[0].Size)
+	require.NotNil(t, m.Buckets[0].Mount)
+	assert.Equal(t, "web1", m.Buckets[0].Mount.Host)
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	cases := map[string]string{
+		"unknown field":       "volumes:\n  - name: data\n    colour: blue\n",
+		"duplicate":           "volumes:\n  - name: data\n  - name: data\n",
+		"no name":             "buckets:\n  - mount:\n      host: h\n",
+		"host without net":    "hosts:\n  - name: h\n",
+		"host with both":      "hosts:\n  - name: h\n    network: n\n    single: true\n",
+		"bad speed":           "volumes:\n  - name: data\n    speed: fast\n",
+		"bad rule":            "security_groups:\n  - name: sg\n    network: n\n    rules:\n      - direction: inside\n",
+		"bad flavor":          "clusters:\n  - name: c\n    flavor: swarm\n",
+		"reserved label":      "hosts:\n  - name: h\n    single: true\n    labels:\n      name: x\n",
+		"subnet without cidr": "networks:\n  - name: n\n    subnets:\n      - name: s\n",
+		"bad name":            "name: ../prod\nbuckets:\n  - name: b\n",
+	}
+	for title, content := range cases {
+		_, xerr := Decode([]byte(content), "yaml")
+		assert.NotNil(t, xerr, title)
+	}
+
+	_, xerr := Decode([]byte("volumes: []"), "xml")
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrInvalidParameter{}, xerr)
+}
+
+func TestStep_String(t *testing.T) {
+	assert.Equal(t, "+ create volume 'data'", Step{Kind: KindVolume, Name: "data", Action: ActionCreate}.String())
+	assert.Equal(t, "~ attach volume 'data' (on host 'web1')", Step{Kind: KindVolume, Name: "data", Action: ActionAttach, Detail: "on host 'web1'"}.String())
+	assert.Equal(t, "- delete host 'web1'", Step{Kind: KindHost, Name: "web1", Action: ActionDelete}.String())
+	assert.Equal(t, "~ update volume 'data' (size: 10 -> 20) [not applied]", Step{Kind: KindVolume, Name: "data", Action: ActionUpdate, Detail: "size: 10 -> 20", Drift: true}.String())
+}
+
+func TestPlan_Applicable(t *testing.T) {
+	plan := Plan{
+		{Kind: KindVolume, Name: "data", Action: ActionCreate},
+		{Kind: KindHost, Name: "web1", Action: ActionDelete, Drift: true},
+	}
+	assert.Equal(t, Plan{{Kind: KindVolume, Name: "data", Action: ActionCreate}}, plan.Applicable())
+}
+
+func TestManifest_RemovedMerge(t *testing.T) {
+	previous, xerr := Decode([]byte(yamlManifest), "yaml")
+	require.Nil(t, xerr)
+
+	current := &Manifest{
+		Networks: []Network{{Name: "net-prod", CIDR: "192.168.0.0/23"}},
+		Volumes:  []Volume{{Name: "data", Size: 50}},
+	}
+	removed := current.Removed(previous)
+	require.Len(t, removed.Networks, 1)
+	assert.Equal(t, Network{Name: "net-prod", Subnets: []Subnet{{Name: "front"}}}, removed.Networks[0])
+	assert.True(t, removed.Declares(KindSubnet, "net-prod/front"))
+	assert.True(t, removed.Declares(KindHost, "web1"))
+	assert.True(t, removed.Declares(KindCluster, "k8s"))
+	assert.False(t, removed.Declares(KindVolume, "data"))
+	assert.Equal(t, &Manifest{}, current.Removed(nil))
+
+	merged := current.Merge(removed)
+	assert.Empty(t, merged.Removed(previous).Hosts)
+	assert.Equal(t, []Network{{Name: "net-prod", Subnets: []Subnet{{Name: "front"}}}}, merged.Networks)
+	assert.Equal(t, []Volume{{Name: "data"}}, merged.Volumes)
+	assert.Equal(t, []Cluster{{Name: "k8s"}}, merged.Clusters)
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package manifest
+
+import (
+	"fmt"
+)
+
+// Kinds of resources handled by a manifest
+const (
+	KindNetwork       = "network"
+	KindSubnet        = "subnet"
+	KindSecurityGroup = "security-group"
+	KindHost          = "host"
+	KindVolume        = "volume"
+	KindShare         = "share"
+	KindBucket        = "bucket"
+	KindCluster       = "cluster"
+)
+
+// Action tells what has to be done on a resource to converge
+type Action string
+
+// Actions that may appear in a Plan
+const (
+	ActionCreate  Action = "create"
+	ActionUpdate  Action = "update"
+	ActionDelete  Action = "delete"
+	ActionBind    Action = "bind"
+	ActionAttach  Action = "attach"
+	ActionDetach  Action = "detach"
+	ActionMount   Action = "mount"
+	ActionUnmount Action = "unmount"
+)
+
+// Step is an elementary action of a Plan
+// A Step marked as Drift reports a difference with the manifest that apply does not converge: an update of attributes
+// SafeScale cannot change on an existing resource, or the deletion of a resource removed from the manifest when
+// apply is not asked to prune.
+type Step struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Action Action `json:"action"`
+	Detail string `json:"detail,omitempty"`
+	Drift  bool   `json:"drift,omitempty"`
+}
+
+// String returns a human readable representation of the Step
+func (s Step) String() string {
+	sign := "~"
+	switch s.Action {
+	case ActionCreate:
+		sign = "+"
+	case ActionDelete:
+		sign = "-"
+	}
+	out := fmt.Sprintf("%s %s %s '%s'", sign, s.Action, s.Kind, s.Name)
+	if s.Detail != "" {
+		out += " (" + s.Detail + ")"
+	}
+	if s.Drift {
+		out += " [not applied]"
+	}
+	return out
+}
+
+// Plan is the ordered list of steps to converge
+type Plan []Step
+
+// IsEmpty tells if there is nothing to do
+func (p Plan) IsEmpty() bool {
+	return len(p) == 0
+}
+
+// Applicable returns the steps that apply will do, leaving out the drifts
+func (p Plan) Applicable() Plan {
+	out := make(Plan, 0, len(p))
+	for _, v := range p {
+		if !v.Drift {
+			out = append(out, v)
+		}
+	}
+	return out
+}
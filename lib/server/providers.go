@@ -25,6 +25,7 @@ import (
 	_ "github.com/CS-SI/SafeScale/lib/server/iaas/providers/flexibleengine" // Imported to initialise tenants
 	_ "github.com/CS-SI/SafeScale/lib/server/iaas/providers/gcp"            // Imported to initialise tenants
 	_ "github.com/CS-SI/SafeScale/lib/server/iaas/providers/local"          // Imported to initialise tenants
+	_ "github.com/CS-SI/SafeScale/lib/server/iaas/providers/memory"         // Imported to initialise tenants
 	_ "github.com/CS-SI/SafeScale/lib/server/iaas/providers/openstack"      // Imported to initialise tenants
 	_ "github.com/CS-SI/SafeScale/lib/server/iaas/providers/opentelekom"    // Imported to initialise tenants
 	_ "github.com/CS-SI/SafeScale/lib/server/iaas/providers/outscale"       // Imported to initialise tenants
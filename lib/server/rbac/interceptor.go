@@ -0,0 +1,299 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbac
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// Resolver returns the name of the resource of kind 'kind' (like 'host' or 'volume') designated by 'ref', its name or
+// its ID, in the tenant 'tenant'; it returns ref if it does not know the kind, and *fail.ErrNotFound if there is no
+// such resource
+type Resolver func(tenant, kind, ref string) (string, fail.Error)
+
+// Identity returns the identity of the caller, ie the common name of its verified client certificate,
+// or AnonymousIdentity
+func Identity(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.AuthInfo == nil {
+		return AnonymousIdentity
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return AnonymousIdentity
+	}
+	if cn := tlsInfo.State.VerifiedChains[0][0].Subject.CommonName; cn != "" {
+		return cn
+	}
+	return AnonymousIdentity
+}
+
+// Action converts the full name of a gRPC method ('/HostService/Create') to an action ('host.create')
+func Action(fullMethod string) string {
+	parts := strings.Split(strings.TrimPrefix(fullMethod, "/"), "/")
+	if len(parts) != 2 {
+		return strings.ToLower(fullMethod)
+	}
+	service := parts[0]
+	if pos := strings.LastIndex(service, "."); pos >= 0 {
+		service = service[pos+1:]
+	}
+	service = strings.TrimSuffix(service, "Service")
+	return strings.ToLower(service) + "." + strings.ToLower(parts[1])
+}
+
+// Resource returns the name of the resource targeted by a gRPC request: the content of field 'name' if present,
+// otherwise the name (or id) of the request if it is a Reference, or of its first Reference field set
+func Resource(req interface{}) string {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return ""
+	}
+
+	m := msg.ProtoReflect()
+	if name := stringField(m, "name"); name != "" {
+		return name
+	}
+	ref := firstReference(m)
+	if m.Descriptor().Name() == "Reference" {
+		ref = m
+	}
+	if ref != nil {
+		for _, field := range []protoreflect.Name{"name", "id"} {
+			if v := stringField(ref, field); v != "" {
+				return v
+			}
+		}
+	}
+	return ""
+}
+
+// References returns the references of all the resources targeted by the gRPC request of method fullMethod, as
+// '<kind>:<name or id>': the request itself (kind of the service) if it has a name or is a Reference, then each of
+// its Reference fields (kind of the name of the field, like 'host' or 'volume')
+func References(fullMethod string, req interface{}) []string {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return nil
+	}
+
+	var out []string
+	add := func(kind string, ref protoreflect.Message) {
+		for _, field := range []protoreflect.Name{"name", "id"} {
+			if v := stringField(ref, field); v != "" {
+				reference := kind + ":" + v
+				for _, r := range out {
+					if r == reference {
+						return
+					}
+				}
+				out = append(out, reference)
+				return
+			}
+		}
+	}
+
+	m := msg.ProtoReflect()
+	kind := Action(fullMethod)
+	if pos := strings.Index(kind, "."); pos >= 0 {
+		kind = kind[:pos]
+	}
+	if m.Descriptor().Name() == "Reference" || stringField(m, "name") != "" {
+		add(kind, m)
+	}
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if fd.Kind() != protoreflect.MessageKind || fd.IsMap() || fd.Message().Name() != "Reference" || !m.Has(fd) {
+			continue
+		}
+		if fd.IsList() {
+			list := m.Get(fd).List()
+			for j := 0; j < list.Len(); j++ {
+				add(string(fd.Name()), list.Get(j).Message())
+			}
+			continue
+		}
+		add(string(fd.Name()), m.Get(fd).Message())
+	}
+	return out
+}
+
+// firstReference returns the first field of type Reference set in the message, or nil
+func firstReference(m protoreflect.Message) protoreflect.Message {
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if fd.Kind() == protoreflect.MessageKind && !fd.IsList() && !fd.IsMap() && fd.Message().Name() == "Reference" && m.Has(fd) {
+			return m.Get(fd).Message()
+		}
+	}
+	return nil
+}
+
+// stringField returns the content of the string field of the message, or "" if there is no such field
+func stringField(m protoreflect.Message, name protoreflect.Name) string {
+	fd := m.Descriptor().Fields().ByName(name)
+	if fd == nil || fd.Kind() != protoreflect.StringKind || fd.IsList() {
+		return ""
+	}
+	return m.Get(fd).String()
+}
+
+// tenantServicePrefix is the prefix of the full names of the methods of TenantService, whose requests designate the
+// tenant with their field 'name'
+const tenantServicePrefix = "TenantService/"
+
+// Tenant returns the tenant targeted by the gRPC request of method fullMethod (field 'name' for the methods of
+// TenantService, field 'tenant_id' of the request or of its first Reference otherwise), or the result of current
+// if the request does not say
+func Tenant(fullMethod string, req interface{}, current func() string) string {
+	if msg, ok := req.(proto.Message); ok {
+		m := msg.ProtoReflect()
+		service := strings.TrimPrefix(fullMethod, "/")
+		if pos := strings.LastIndex(service, "."); pos >= 0 {
+			service = service[pos+1:]
+		}
+		if strings.HasPrefix(service, tenantServicePrefix) {
+			if tenant := stringField(m, "name"); tenant != "" {
+				return tenant
+			}
+		}
+		if tenant := stringField(m, "tenant_id"); tenant != "" {
+			return tenant
+		}
+		if ref := firstReference(m); ref != nil {
+			if tenant := stringField(ref, "tenant_id"); tenant != "" {
+				return tenant
+			}
+		}
+	}
+	if current != nil {
+		return current()
+	}
+	return ""
+}
+
+// UnaryServerInterceptor returns a gRPC interceptor rejecting with PermissionDenied the calls not allowed by the policy
+// currentTenant returns the name of the tenant used when a request does not specify one; resolver gives the name of a
+// resource designated by its ID, so that rules restricted to resource names cannot be bypassed with IDs.
+func UnaryServerInterceptor(policy *Policy, currentTenant func() string, resolver Resolver) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := authorize(ctx, policy, info.FullMethod, req, currentTenant, resolver); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns a gRPC interceptor doing the same as UnaryServerInterceptor for streaming calls;
+// access is checked when the request is received.
+func StreamServerInterceptor(policy *Policy, currentTenant func() string, resolver Resolver) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &authorizedStream{
+			ServerStream:  ss,
+			policy:        policy,
+			fullMethod:    info.FullMethod,
+			currentTenant: currentTenant,
+			resolver:      resolver,
+		})
+	}
+}
+
+// authorizedStream checks access on the first message received
+type authorizedStream struct {
+	grpc.ServerStream
+	policy        *Policy
+	fullMethod    string
+	currentTenant func() string
+	resolver      Resolver
+	granted       bool
+}
+
+// RecvMsg receives a message, then checks access if not done yet
+func (s *authorizedStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if s.granted {
+		return nil
+	}
+	if err := authorize(s.Context(), s.policy, s.fullMethod, m, s.currentTenant, s.resolver); err != nil {
+		return err
+	}
+	s.granted = true
+	return nil
+}
+
+// authorize returns a gRPC PermissionDenied error if the call is not allowed by the policy
+func authorize(ctx context.Context, policy *Policy, fullMethod string, req interface{}, currentTenant func() string, resolver Resolver) error {
+	r := Request{
+		Identity: Identity(ctx),
+		Action:   Action(fullMethod),
+		Tenant:   Tenant(fullMethod, req, currentTenant),
+		Resource: Resource(req),
+	}
+	xerr := resolveResource(policy, &r, fullMethod, req, resolver)
+	if xerr == nil {
+		xerr = policy.Authorize(r)
+	}
+	if xerr != nil {
+		logrus.Warnf("Access denied: %s", xerr.Error())
+		return xerr.ToGRPCStatus()
+	}
+
+	logrus.Debugf("Access granted to '%s' for %s", r.Identity, r.describe())
+	return nil
+}
+
+// resolveResource replaces the resource of the request by its name when the request may designate it by its ID and a
+// rule restricted to resource names applies to the request
+func resolveResource(policy *Policy, r *Request, fullMethod string, req interface{}, resolver Resolver) fail.Error {
+	if resolver == nil || r.Resource == "" || !policy.restrictsResources(*r) {
+		return nil
+	}
+
+	refs := References(fullMethod, req)
+	if len(refs) == 0 {
+		return nil
+	}
+	kind := refs[0][:strings.Index(refs[0], ":")]
+	name, xerr := resolver(r.Tenant, kind, r.Resource)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			// resource to create, or not existing: the reference is its name
+			return nil
+		default:
+			return fail.ForbiddenError("'%s' is not allowed to %s: failed to find the name of %s '%s': %v", r.Identity, r.describe(), kind, r.Resource, xerr)
+		}
+	}
+	if name != "" {
+		r.Resource = name
+	}
+	return nil
+}
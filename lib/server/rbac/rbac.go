@@ -0,0 +1,250 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package rbac implements the role-based access control of safescaled
+//
+// A Policy binds identities (the common name of the client certificate when mutual TLS is used) to roles,
+// each role containing rules allowing or denying actions ('<service>.<method>', like 'host.create'),
+// optionally restricted to tenants and to resource names. All fields support glob patterns ('*', '?', '[...]').
+// A deny rule always wins; an action not allowed by any rule is denied.
+package rbac
+
+import (
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+	"gopkg.in/yaml.v2"
+
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// AnonymousIdentity is the identity of a caller without client certificate
+const AnonymousIdentity = "anonymous"
+
+// Effects of a Rule
+const (
+	EffectAllow = "allow"
+	EffectDeny  = "deny"
+)
+
+// Policy describes who is allowed to do what
+type Policy struct {
+	Roles []Role    `yaml:"roles" toml:"roles"`
+	Users []Binding `yaml:"users" toml:"users"`
+}
+
+// Role is a named set of rules
+type Role struct {
+	Name  string `yaml:"name" toml:"name"`
+	Rules []Rule `yaml:"rules" toml:"rules"`
+}
+
+// Rule allows (or denies) actions on resources of tenants; an empty list matches everything
+type Rule struct {
+	Effect    string   `yaml:"effect,omitempty" toml:"effect,omitempty"`
+	Actions   []string `yaml:"actions" toml:"actions"`
+	Tenants   []string `yaml:"tenants,omitempty" toml:"tenants,omitempty"`
+	Resources []string `yaml:"resources,omitempty" toml:"resources,omitempty"`
+}
+
+// Binding gives roles to identities
+type Binding struct {
+	Identity string   `yaml:"identity" toml:"identity"`
+	Roles    []string `yaml:"roles" toml:"roles"`
+}
+
+// Request describes what a caller wants to do
+type Request struct {
+	Identity string
+	Action   string
+	Tenant   string
+	Resource string // may be empty if the request does not target a named resource
+}
+
+// LoadPolicy reads a Policy from a YAML (default), JSON or TOML file, depending on its extension
+func LoadPolicy(file string) (*Policy, fail.Error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fail.Wrap(err, "failed to read policy file '%s'", file)
+	}
+
+	p := &Policy{}
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".toml":
+		err = toml.Unmarshal(content, p)
+	default:
+		err = yaml.UnmarshalStrict(content, p)
+	}
+	if err != nil {
+		return nil, fail.SyntaxError("failed to decode policy file '%s': %s", file, err.Error())
+	}
+
+	if xerr := p.Validate(); xerr != nil {
+		return nil, fail.Wrap(xerr, "invalid policy file '%s'", file)
+	}
+	return p, nil
+}
+
+// Validate checks the consistency of the Policy
+func (p *Policy) Validate() fail.Error {
+	roles := map[string]bool{}
+	for _, r := range p.Roles {
+		if r.Name == "" {
+			return fail.InvalidRequestError("a role has no name")
+		}
+		if roles[r.Name] {
+			return fail.InvalidRequestError("role '%s' is defined twice", r.Name)
+		}
+		roles[r.Name] = true
+
+		for _, rule := range r.Rules {
+			switch rule.Effect {
+			case "", EffectAllow, EffectDeny:
+			default:
+				return fail.InvalidRequestError("invalid effect '%s' in role '%s', must be '%s' or '%s'", rule.Effect, r.Name, EffectAllow, EffectDeny)
+			}
+			if len(rule.Actions) == 0 {
+				return fail.InvalidRequestError("a rule of role '%s' has no action", r.Name)
+			}
+			for _, list := range [][]string{rule.Actions, rule.Tenants, rule.Resources} {
+				for _, pattern := range list {
+					if _, err := path.Match(pattern, ""); err != nil {
+						return fail.InvalidRequestError("invalid pattern '%s' in role '%s'", pattern, r.Name)
+					}
+				}
+			}
+		}
+	}
+	for _, b := range p.Users {
+		if b.Identity == "" {
+			return fail.InvalidRequestError("a user has no identity")
+		}
+		if _, err := path.Match(b.Identity, ""); err != nil {
+			return fail.InvalidRequestError("invalid identity pattern '%s'", b.Identity)
+		}
+		for _, r := range b.Roles {
+			if !roles[r] {
+				return fail.InvalidRequestError("user '%s' refers to unknown role '%s'", b.Identity, r)
+			}
+		}
+	}
+	return nil
+}
+
+// Authorize returns nil if the request is allowed, *fail.ErrForbidden otherwise
+func (p *Policy) Authorize(req Request) fail.Error {
+	if p == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	allowed := false
+	for _, role := range p.rolesOf(req.Identity) {
+		for _, rule := range role.Rules {
+			if !rule.matches(req) {
+				continue
+			}
+			if rule.Effect == EffectDeny {
+				return fail.ForbiddenError("'%s' is not allowed to %s: denied by role '%s'", req.Identity, req.describe(), role.Name)
+			}
+			allowed = true
+		}
+	}
+	if !allowed {
+		return fail.ForbiddenError("'%s' is not allowed to %s", req.Identity, req.describe())
+	}
+	return nil
+}
+
+// rolesOf returns the roles bound to identity
+func (p *Policy) rolesOf(identity string) []Role {
+	names := map[string]bool{}
+	for _, b := range p.Users {
+		if match(b.Identity, identity) {
+			for _, r := range b.Roles {
+				names[r] = true
+			}
+		}
+	}
+
+	var out []Role
+	for _, r := range p.Roles {
+		if names[r.Name] {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// matches tells if the rule applies to the request
+// A rule restricted to resources never applies to a request without resource.
+func (r Rule) matches(req Request) bool {
+	if !matchAny(r.Actions, req.Action) {
+		return false
+	}
+	if len(r.Tenants) > 0 && !matchAny(r.Tenants, req.Tenant) {
+		return false
+	}
+	if len(r.Resources) > 0 && (req.Resource == "" || !matchAny(r.Resources, req.Resource)) {
+		return false
+	}
+	return true
+}
+
+// restrictsResources tells if a rule restricted to resource names applies to the action of the request in its tenant
+func (p *Policy) restrictsResources(req Request) bool {
+	if p == nil {
+		return false
+	}
+	for _, role := range p.rolesOf(req.Identity) {
+		for _, rule := range role.Rules {
+			if len(rule.Resources) > 0 && matchAny(rule.Actions, req.Action) && (len(rule.Tenants) == 0 || matchAny(rule.Tenants, req.Tenant)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// describe returns a human readable description of the request
+func (req Request) describe() string {
+	out := "'" + req.Action + "'"
+	if req.Resource != "" {
+		out += " on '" + req.Resource + "'"
+	}
+	if req.Tenant != "" {
+		out += " in tenant '" + req.Tenant + "'"
+	}
+	return out
+}
+
+// matchAny tells if value matches one of the patterns
+func matchAny(patterns []string, value string) bool {
+	for _, p := range patterns {
+		if match(p, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// match tells if value matches the glob pattern (patterns have been validated, errors cannot occur)
+func match(pattern, value string) bool {
+	ok, _ := path.Match(pattern, value)
+	return ok
+}
@@ -0,0 +1,246 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rbac
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/CS-SI/SafeScale/lib/protocol"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+const policyContent = `
+roles:
+  - name: admin
+    rules:
+      - actions: ["*"]
+  - name: dev
+    rules:
+      - actions: ["host.*", "volume.*", "*.list", "*.inspect"]
+        tenants: ["dev-*"]
+      - effect: deny
+        actions: ["host.delete"]
+        resources: ["prod-*"]
+      - effect: deny
+        actions: ["cluster.delete"]
+users:
+  - identity: alice
+    roles: [admin]
+  - identity: "dev-*"
+    roles: [dev]
+`
+
+func loadTestPolicy(t *testing.T, content, ext string) (*Policy, fail.Error) {
+	dir, err := ioutil.TempDir("", "safescale-rbac")
+	require.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	file := filepath.Join(dir, "policy"+ext)
+	require.Nil(t, ioutil.WriteFile(file, []byte(content), 0600))
+	return LoadPolicy(file)
+}
+
+func TestPolicy_Authorize(t *testing.T) {
+	p, xerr := loadTestPolicy(t, policyContent, ".yml")
+	require.Nil(t, xerr)
+
+	allowed := []Request{
+		{Identity: "alice", Action: "cluster.delete", Tenant: "prod", Resource: "k8s"},
+		{Identity: "dev-bob", Action: "host.create", Tenant: "dev-eu", Resource: "web1"},
+		{Identity: "dev-bob", Action: "cluster.list", Tenant: "dev-eu"},
+		{Identity: "dev-bob", Action: "host.delete", Tenant: "dev-eu", Resource: "web1"},
+	}
+	for _, v := range allowed {
+		assert.Nil(t, p.Authorize(v), v)
+	}
+
+	denied := []Request{
+		{Identity: "dev-bob", Action: "cluster.delete", Tenant: "dev-eu", Resource: "k8s"},
+		{Identity: "dev-bob", Action: "host.create", Tenant: "prod", Resource: "web1"},
+		{Identity: "dev-bob", Action: "host.delete", Tenant: "dev-eu", Resource: "prod-db"},
+		{Identity: "dev-bob", Action: "network.create", Tenant: "dev-eu", Resource: "net"},
+		{Identity: "mallory", Action: "host.list", Tenant: "dev-eu"},
+		{Identity: AnonymousIdentity, Action: "host.list", Tenant: "dev-eu"},
+	}
+	for _, v := range denied {
+		xerr := p.Authorize(v)
+		require.NotNil(t, xerr, v)
+		assert.IsType(t, &fail.ErrForbidden{}, xerr)
+	}
+}
+
+func TestLoadPolicy_Invalid(t *testing.T) {
+	cases := map[string]string{
+		"unknown role":    "users:\n  - identity: bob\n    roles: [ghost]\n",
+		"bad effect":      "roles:\n  - name: r\n    rules:\n      - effect: maybe\n        actions: ['*']\n",
+		"no action":       "roles:\n  - name: r\n    rules:\n      - tenants: ['*']\n",
+		"bad pattern":     "roles:\n  - name: r\n    rules:\n      - actions: ['[']\n",
+		"duplicated role": "roles:\n  - name: r\n  - name: r\n",
+		"unknown field":   "roles:\n  - name: r\n    color: blue\n",
+	}
+	for title, content := range cases {
+		_, xerr := loadTestPolicy(t, content, ".yaml")
+		assert.NotNil(t, xerr, title)
+	}
+
+	p, xerr := loadTestPolicy(t, "[[roles]]\nname = \"r\"\n[[roles.rules]]\nactions = [\"*.list\"]\n[[users]]\nidentity = \"*\"\nroles = [\"r\"]\n", ".toml")
+	require.Nil(t, xerr)
+	assert.Nil(t, p.Authorize(Request{Identity: "x", Action: "host.list"}))
+}
+
+func TestAction(t *testing.T) {
+	assert.Equal(t, "host.create", Action("/HostService/Create"))
+	assert.Equal(t, "securitygroup.bind", Action("/protocol.SecurityGroupService/Bind"))
+}
+
+func TestResource(t *testing.T) {
+	assert.Equal(t, "web1", Resource(&protocol.HostDefinition{Name: "web1"}))
+	assert.Equal(t, "vol1", Resource(&protocol.VolumeAttachmentRequest{Volume: &protocol.Reference{Name: "vol1"}, Host: &protocol.Reference{Name: "web1"}}))
+	assert.Equal(t, "1234", Resource(&protocol.Reference{Id: "1234"}))
+	assert.Equal(t, "", Resource(&protocol.VolumeListRequest{}))
+}
+
+func TestReferences(t *testing.T) {
+	assert.Equal(t, []string{"host:web1"}, References("/HostService/Create", &protocol.HostDefinition{Name: "web1"}))
+	assert.Equal(t, []string{"volume:vol1", "host:web1"}, References("/VolumeService/Attach", &protocol.VolumeAttachmentRequest{Volume: &protocol.Reference{Name: "vol1"}, Host: &protocol.Reference{Name: "web1"}}))
+	assert.Equal(t, []string{"host:1234"}, References("/HostService/Delete", &protocol.Reference{Id: "1234"}))
+	assert.Empty(t, References("/VolumeService/List", &protocol.VolumeListRequest{}))
+}
+
+func TestTenant(t *testing.T) {
+	current := func() string { return "current" }
+	assert.Equal(t, "prod", Tenant("/TenantService/Set", &protocol.TenantName{Name: "prod"}, current))
+	assert.Equal(t, "prod", Tenant("/protocol.TenantService/Set", &protocol.TenantName{Name: "prod"}, current))
+	assert.Equal(t, "current", Tenant("/TenantService/Get", &protocol.TenantName{}, current))
+	assert.Equal(t, "dev", Tenant("/HostService/Delete", &protocol.Reference{Name: "web1", TenantId: "dev"}, current))
+	assert.Equal(t, "current", Tenant("/HostService/Create", &protocol.HostDefinition{Name: "web1"}, current))
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	p, xerr := loadTestPolicy(t, "roles:\n  - name: r\n    rules:\n      - actions: ['host.list']\nusers:\n  - identity: anonymous\n    roles: [r]\n", ".yml")
+	require.Nil(t, xerr)
+
+	interceptor := UnaryServerInterceptor(p, func() string { return "current" }, nil)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "done", nil }
+
+	out, err := interceptor(context.Background(), &protocol.HostListRequest{}, &grpc.UnaryServerInfo{FullMethod: "/HostService/List"}, handler)
+	require.Nil(t, err)
+	assert.Equal(t, "done", out)
+
+	_, err = interceptor(context.Background(), &protocol.Reference{Name: "web1", TenantId: "prod"}, &grpc.UnaryServerInfo{FullMethod: "/HostService/Delete"}, handler)
+	require.NotNil(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Contains(t, err.Error(), "'host.delete' on 'web1' in tenant 'prod'")
+
+	p, xerr = loadTestPolicy(t, "roles:\n  - name: r\n    rules:\n      - actions: ['tenant.set']\n        tenants: ['dev-*']\nusers:\n  - identity: anonymous\n    roles: [r]\n", ".yml")
+	require.Nil(t, xerr)
+	interceptor = UnaryServerInterceptor(p, func() string { return "dev-1" }, nil)
+	_, err = interceptor(context.Background(), &protocol.TenantName{Name: "prod"}, &grpc.UnaryServerInfo{FullMethod: "/TenantService/Set"}, handler)
+	require.NotNil(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = interceptor(context.Background(), &protocol.TenantName{Name: "dev-2"}, &grpc.UnaryServerInfo{FullMethod: "/TenantService/Set"}, handler)
+	assert.Nil(t, err)
+}
+
+func TestUnaryServerInterceptor_ResourceByID(t *testing.T) {
+	p, xerr := loadTestPolicy(t, policyContent, ".yml")
+	require.Nil(t, xerr)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "done", nil }
+	info := &grpc.UnaryServerInfo{FullMethod: "/HostService/Delete"}
+	// the peer has no client certificate: the caller is anonymous, bound to role dev for this test
+	p.Users = append(p.Users, Binding{Identity: AnonymousIdentity, Roles: []string{"dev"}})
+
+	// the deny rule on 'prod-*' is bypassed when designating the host by its ID, unless the ID is resolved
+	req := &protocol.Reference{Name: "5f0c2c6e-id-of-prod-web", TenantId: "dev-1"}
+	interceptor := UnaryServerInterceptor(p, func() string { return "dev-1" }, nil)
+	_, err := interceptor(context.Background(), req, info, handler)
+	assert.Nil(t, err)
+
+	var resolved []string
+	resolver := func(tenant, kind, ref string) (string, fail.Error) {
+		resolved = append(resolved, tenant+"/"+kind+":"+ref)
+		switch ref {
+		case "5f0c2c6e-id-of-prod-web", "prod-web":
+			return "prod-web", nil
+		case "unreachable":
+			return "", fail.NewError("metadata unreachable")
+		default:
+			return "", fail.NotFoundError("no host '%s'", ref)
+		}
+	}
+	interceptor = UnaryServerInterceptor(p, func() string { return "dev-1" }, resolver)
+	_, err = interceptor(context.Background(), req, info, handler)
+	require.NotNil(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Contains(t, err.Error(), "on 'prod-web'")
+	assert.Equal(t, []string{"dev-1/host:5f0c2c6e-id-of-prod-web"}, resolved)
+
+	// unknown resource: the reference is taken as its name
+	_, err = interceptor(context.Background(), &protocol.Reference{Name: "dev-web"}, info, handler)
+	assert.Nil(t, err)
+
+	// name cannot be resolved: refused rather than risking a bypass
+	_, err = interceptor(context.Background(), &protocol.Reference{Name: "unreachable"}, info, handler)
+	require.NotNil(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// no rule restricted to resources applies: no resolution needed
+	resolved = nil
+	_, err = interceptor(context.Background(), &protocol.Reference{Name: "vol-id"}, &grpc.UnaryServerInfo{FullMethod: "/VolumeService/Delete"}, handler)
+	assert.Nil(t, err)
+	assert.Empty(t, resolved)
+}
+
+type testServerStream struct {
+	grpc.ServerStream
+}
+
+func (s testServerStream) Context() context.Context { return context.Background() }
+
+func (s testServerStream) RecvMsg(m interface{}) error {
+	m.(*protocol.JobDefinition).Uuid = "job-1"
+	return nil
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	p, xerr := loadTestPolicy(t, "roles:\n  - name: r\n    rules:\n      - actions: ['job.inspect']\nusers:\n  - identity: anonymous\n    roles: [r]\n", ".yml")
+	require.Nil(t, xerr)
+
+	interceptor := StreamServerInterceptor(p, func() string { return "current" }, nil)
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		return ss.RecvMsg(&protocol.JobDefinition{})
+	}
+
+	err := interceptor(nil, testServerStream{}, &grpc.StreamServerInfo{FullMethod: "/JobService/Watch"}, handler)
+	require.NotNil(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	p, xerr = loadTestPolicy(t, "roles:\n  - name: r\n    rules:\n      - actions: ['job.*']\nusers:\n  - identity: anonymous\n    roles: [r]\n", ".yml")
+	require.Nil(t, xerr)
+	interceptor = StreamServerInterceptor(p, func() string { return "current" }, nil)
+	assert.Nil(t, interceptor(nil, testServerStream{}, &grpc.StreamServerInfo{FullMethod: "/JobService/Watch"}, handler))
+}
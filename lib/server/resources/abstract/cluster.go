@@ -43,6 +43,23 @@ type ClusterRequest struct {
 	OS                      string                 // contains the name of the linux distribution wanted
 	DisabledDefaultFeatures map[string]struct{}    // contains the list of features that should be installed by default but we don't want actually
 	Force                   bool                   // Force is set to True in order to ignore sizing recommendations
+	Labels                  map[string]string      // contains the user-defined labels of the cluster, propagated to its resources
+}
+
+// ClusterUpgradeRequest defines how the hosts of a Cluster are upgraded
+type ClusterUpgradeRequest struct {
+	Feature     string            `json:"feature,omitempty"`      // feature (re)installed on each host; if empty, the updates of the operating system are applied
+	Params      map[string]string `json:"params,omitempty"`       // parameters of the feature (a new version for example)
+	Parallelism uint              `json:"parallelism"`            // maximum count of nodes upgraded at the same time; masters are always upgraded one by one
+	SkipMasters bool              `json:"skip_masters,omitempty"` // tells to upgrade only the nodes
+}
+
+// ClusterKubeconfig contains what is needed to use the Kubernetes API server of a Cluster from outside of it
+type ClusterKubeconfig struct {
+	Content   string `json:"content"`    // kubeconfig of the Cluster admin, whose server has been replaced by Server
+	Server    string `json:"server"`     // URL of the API server set in Content
+	APIServer string `json:"api_server"` // address (host:port) of the API server inside the network of the Cluster, reachable from the gateways
+	Gateway   string `json:"gateway"`    // name of the primary gateway of the Cluster, to use as SSH jump host to reach APIServer
 }
 
 // ClusterIdentity contains the bare minimum information about a cluster
@@ -151,6 +151,38 @@ type Image struct {
 	Description string `json:"description,omitempty"`
 	StorageType string `json:"storage_type,omitempty"`
 	DiskSize    int64  `json:"disk_size_Gb,omitempty"`
+	HostID      string `json:"host_id,omitempty"` // contains the ID of the host the image has been created from (custom images only)
+}
+
+// NewImage ...
+func NewImage() *Image {
+	return &Image{}
+}
+
+// IsNull tells if the instance is a null value
+func (i *Image) IsNull() bool {
+	return i == nil || (i.ID == "" && i.Name == "")
+}
+
+// Clone ...
+//
+// satisfies interface data.Clonable
+func (i Image) Clone() data.Clonable {
+	return NewImage().Replace(&i)
+}
+
+// Replace ...
+//
+// satisfies interface data.Clonable
+func (i *Image) Replace(p data.Clonable) data.Clonable {
+	// Do not test with isNull(), it's allowed to clone a null value...
+	if i == nil || p == nil {
+		return i
+	}
+
+	src := p.(*Image)
+	*i = *src
+	return i
 }
 
 // OK ...
@@ -162,6 +194,50 @@ func (i Image) OK() bool {
 	return result
 }
 
+// Serialize serializes Image instance into bytes (output json code)
+func (i *Image) Serialize() ([]byte, fail.Error) {
+	if i == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	r, err := json.Marshal(i)
+	return r, fail.ConvertError(err)
+}
+
+// Deserialize reads json code and restores an Image
+func (i *Image) Deserialize(buf []byte) (xerr fail.Error) {
+	if i == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	defer fail.OnPanic(&xerr) // json.Unmarshal may panic
+	return fail.ConvertError(json.Unmarshal(buf, i))
+}
+
+// GetName returns the name of the image
+// Satisfies interface data.Identifiable
+func (i *Image) GetName() string {
+	if i == nil {
+		return ""
+	}
+	return i.Name
+}
+
+// GetID returns the ID of the image
+// Satisfies interface data.Identifiable
+func (i *Image) GetID() string {
+	if i == nil {
+		return ""
+	}
+	return i.ID
+}
+
+// ImageRequest represents a request to create a custom image from an existing host
+type ImageRequest struct {
+	Name        string `json:"name,omitempty"`
+	HostID      string `json:"host_id,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
 // HostRequest represents requirements to create host
 type HostRequest struct {
 	ResourceName     string              // ResourceName contains the name of the compute resource
@@ -182,6 +258,18 @@ type HostRequest struct {
 	KeepOnFailure    bool                // KeepOnFailure tells if resource must be kept on failure
 	Preemptible      bool                // Use spot-like instance
 	SecurityGroupIDs map[string]struct{} // List of Security Groups to attach to IPAddress (using map as dict)
+	Labels           map[string]string   // contains the user-defined labels of the host
+}
+
+// HostImportRequest represents what is needed to import in SafeScale an existing host of the provider
+type HostImportRequest struct {
+	Ref             string   // Ref contains the ID or the name of the host on provider side
+	SSHPort         uint32   // contains the port to use for SSH (22 if 0)
+	SSHUser         string   // SSHUser is the user owning SSHPrivateKey on the host; OperatorUsername if empty
+	SSHPrivateKey   string   // SSHPrivateKey is the private key giving SSH access to the host; SSH will not be available if empty
+	InjectKey       bool     // InjectKey tells to authorize a new key for OperatorUsername, using the access given by SSHUser and SSHPrivateKey
+	InstallBinaries bool     // InstallBinaries tells to add the feature 'safescale-binaries' on the host (needs SSH access)
+	SecurityGroups  []string // SecurityGroups lists the Security Groups (registered in metadata) already bound to the host on provider side
 }
 
 // HostEffectiveSizing ...
@@ -232,6 +320,8 @@ type HostCore struct {
 	SSHPort    uint32         `json:"ssh_port,omitempty"`
 	Password   string         `json:"password,omitempty"`
 	LastState  hoststate.Enum `json:"last_state,omitempty"`
+
+	Tags map[string]string `json:"-"` // tags read on provider side, not kept in metadata
 }
 
 // NewHostCore ...
@@ -0,0 +1,154 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package abstract
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+const (
+	maxLabelKeyLength   = 63
+	maxLabelValueLength = 255
+)
+
+// labelKeyRegexp restricts label keys to the characters accepted by every provider
+var labelKeyRegexp = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9_.\-]*[a-zA-Z0-9])?$`)
+
+// Tags set by SafeScale on the resources it creates on provider side
+const (
+	ManagedByTag      = "ManagedBy"       // tells the resource has been created by SafeScale
+	ManagedByValue    = "safescale"       // value of ManagedByTag
+	CreationDateTag   = "CreationDate"    // creation date of the resource, in seconds since epoch (survives the sanitization of tag values by providers)
+	TenantTag         = "SafeScaleTenant" // name of the tenant that created the resource
+	MetadataBucketTag = "SafeScaleBucket" // name of the metadata bucket of the installation that created the resource
+)
+
+// maxTagValueLength is the shortest length of tag values kept by the providers (GCP truncates labels to 63 characters)
+const maxTagValueLength = 63
+
+// reservedLabelKeys contains the keys used by SafeScale to tag resources on provider side
+var reservedLabelKeys = map[string]struct{}{
+	"name":            {},
+	"managedby":       {},
+	"deletewithvm":    {},
+	"creationdate":    {},
+	"safescaletenant": {},
+	"safescalebucket": {},
+}
+
+// ManagementInfo is what the tags of a resource on provider side tell about its management by SafeScale
+type ManagementInfo struct {
+	Managed        bool      // tells if the resource has been created by SafeScale
+	Created        time.Time // creation date of the resource; zero if unknown
+	Tenant         string    // tenant that created the resource; empty if unknown
+	MetadataBucket string    // metadata bucket of the installation that created the resource; empty if unknown
+}
+
+// ManagementTags returns a copy of labels completed with the tags telling the resource is created now by SafeScale,
+// for the tenant 'tenant' storing its metadata in the bucket 'bucket'
+func ManagementTags(labels map[string]string, tenant, bucket string) map[string]string {
+	out := make(map[string]string, len(labels)+4)
+	for k, v := range labels {
+		out[k] = v
+	}
+	out[ManagedByTag] = ManagedByValue
+	out[CreationDateTag] = strconv.FormatInt(time.Now().Unix(), 10)
+	out[TenantTag] = tenant
+	out[MetadataBucketTag] = bucket
+	return out
+}
+
+// ParseManagementTags tells what tags read on provider side say about the management of a resource by SafeScale
+// Keys are compared case-insensitively, some providers lowering them
+func ParseManagementTags(tags map[string]string) ManagementInfo {
+	var out ManagementInfo
+	for k, v := range tags {
+		switch strings.ToLower(k) {
+		case strings.ToLower(ManagedByTag):
+			out.Managed = strings.EqualFold(v, ManagedByValue)
+		case strings.ToLower(CreationDateTag):
+			if secs, err := strconv.ParseInt(v, 10, 64); err == nil {
+				out.Created = time.Unix(secs, 0)
+			}
+		case strings.ToLower(TenantTag):
+			out.Tenant = v
+		case strings.ToLower(MetadataBucketTag):
+			out.MetadataBucket = v
+		}
+	}
+	return out
+}
+
+// OwnedBy tells if the resource has been created by SafeScale for the tenant 'tenant' storing its metadata in the bucket 'bucket'
+// Values are compared as sanitized by the most restrictive provider, which lowers them, replaces the characters other than
+// letters, digits, '_' and '-' by '_' and truncates them
+func (mi ManagementInfo) OwnedBy(tenant, bucket string) bool {
+	if !mi.Managed || mi.Tenant == "" || mi.MetadataBucket == "" {
+		return false
+	}
+	return normalizeTagValue(mi.Tenant) == normalizeTagValue(tenant) && normalizeTagValue(mi.MetadataBucket) == normalizeTagValue(bucket)
+}
+
+// normalizeTagValue returns the value of a tag as kept by the most restrictive provider
+func normalizeTagValue(in string) string {
+	out := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' || r == '-' {
+			return r
+		}
+		return '_'
+	}, strings.ToLower(in))
+	if len(out) > maxTagValueLength {
+		out = out[:maxTagValueLength]
+	}
+	return out
+}
+
+// ValidateLabels checks that the keys and values of labels are usable on every provider
+func ValidateLabels(labels map[string]string) fail.Error {
+	for k, v := range labels {
+		if len(k) > maxLabelKeyLength || !labelKeyRegexp.MatchString(k) {
+			return fail.InvalidRequestError("invalid label key '%s': must be at most %d alphanumeric characters, '-', '_' or '.', starting and ending with an alphanumeric character", k, maxLabelKeyLength)
+		}
+		if _, ok := reservedLabelKeys[strings.ToLower(k)]; ok {
+			return fail.InvalidRequestError("invalid label key '%s': reserved by SafeScale", k)
+		}
+		if len(v) > maxLabelValueLength {
+			return fail.InvalidRequestError("invalid value of label '%s': must be at most %d characters", k, maxLabelValueLength)
+		}
+	}
+	return nil
+}
+
+// MatchLabels tells if labels satisfies all the key/value pairs of selector
+// An empty value in selector matches any value of the key; an empty selector matches everything
+func MatchLabels(labels, selector map[string]string) bool {
+	for k, v := range selector {
+		current, ok := labels[k]
+		if !ok {
+			return false
+		}
+		if v != "" && current != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package abstract
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateLabels(t *testing.T) {
+	assert.Nil(t, ValidateLabels(nil))
+	assert.Nil(t, ValidateLabels(map[string]string{"cost-center": "R&D 42", "env": ""}))
+	assert.NotNil(t, ValidateLabels(map[string]string{"": "value"}))
+	assert.NotNil(t, ValidateLabels(map[string]string{"-cost": "value"}))
+	assert.NotNil(t, ValidateLabels(map[string]string{"cost center": "value"}))
+	assert.NotNil(t, ValidateLabels(map[string]string{"Name": "value"}))
+	assert.NotNil(t, ValidateLabels(map[string]string{"CreationDate": "0"}))
+	assert.NotNil(t, ValidateLabels(map[string]string{"SafeScaleTenant": "other"}))
+}
+
+func TestMatchLabels(t *testing.T) {
+	labels := map[string]string{"cost-center": "42", "env": "prod"}
+
+	assert.True(t, MatchLabels(labels, nil))
+	assert.True(t, MatchLabels(labels, map[string]string{"env": "prod"}))
+	assert.True(t, MatchLabels(labels, map[string]string{"env": "prod", "cost-center": ""}))
+	assert.False(t, MatchLabels(labels, map[string]string{"env": "dev"}))
+	assert.False(t, MatchLabels(labels, map[string]string{"team": ""}))
+	assert.False(t, MatchLabels(nil, map[string]string{"env": "prod"}))
+}
+
+func TestManagementTags(t *testing.T) {
+	labels := map[string]string{"env": "prod"}
+	tags := ManagementTags(labels, "Prod-OVH", "0.safescale-96d245d7")
+	assert.Equal(t, "prod", tags["env"])
+	assert.Equal(t, 1, len(labels))
+
+	mi := ParseManagementTags(tags)
+	assert.True(t, mi.Managed)
+	assert.WithinDuration(t, time.Now(), mi.Created, time.Minute)
+	assert.True(t, mi.OwnedBy("Prod-OVH", "0.safescale-96d245d7"))
+	assert.False(t, mi.OwnedBy("Dev-OVH", "0.safescale-96d245d7"))
+	assert.False(t, mi.OwnedBy("Prod-OVH", "0.safescale-0a1b2c3d"))
+
+	// some providers lower keys and values and replace unsupported characters
+	mi = ParseManagementTags(map[string]string{
+		"managedby":       "safescale",
+		"creationdate":    "1600000000",
+		"safescaletenant": "prod-ovh",
+		"safescalebucket": "0_safescale-96d245d7",
+	})
+	assert.True(t, mi.Managed)
+	assert.Equal(t, int64(1600000000), mi.Created.Unix())
+	assert.True(t, mi.OwnedBy("Prod-OVH", "0.safescale-96d245d7"))
+
+	// created by SafeScale before the owner was tagged: owner unknown
+	mi = ParseManagementTags(map[string]string{ManagedByTag: ManagedByValue})
+	assert.True(t, mi.Managed)
+	assert.False(t, mi.OwnedBy("Prod-OVH", "0.safescale-96d245d7"))
+
+	mi = ParseManagementTags(map[string]string{"env": "prod"})
+	assert.False(t, mi.Managed)
+	assert.True(t, mi.Created.IsZero())
+	assert.False(t, mi.OwnedBy("", ""))
+}
@@ -29,10 +29,11 @@ import (
 // NetworkRequest represents network requirements to create a network/VPC where CIDR contains a non-routable network
 // like "192.0.2.0/24" or "2001:db8::/32", as defined in RFC 4632 and RFC 4291.
 type NetworkRequest struct {
-	Name          string   // contains name of Network/VPC
-	CIDR          string   // contains the CIDR of the Network/VPC
-	DNSServers    []string // list of dns servers to be used inside the Network/VPC
-	KeepOnFailure bool     // KeepOnFailure tells if resources have to be kept in case of failure (default behavior is to delete them)
+	Name          string            // contains name of Network/VPC
+	CIDR          string            // contains the CIDR of the Network/VPC
+	DNSServers    []string          // list of dns servers to be used inside the Network/VPC
+	KeepOnFailure bool              // KeepOnFailure tells if resources have to be kept in case of failure (default behavior is to delete them)
+	Labels        map[string]string // contains the user-defined labels of the Network/VPC
 }
 
 // SubNetwork --DEPRECATED--
@@ -49,6 +50,8 @@ type Network struct {
 	DNSServers []string `json:"dns_servers,omitempty"` // list of dns servers to be used inside the Network/VPC
 	Imported   bool     `json:"imported,omitempty"`    // tells if the Network has been imported (making it not deleteable by SafeScale)
 
+	Tags map[string]string `json:"-"` // tags read on provider side, not kept in metadata
+
 	Domain             string         `json:"domain,omitempty"`               // DEPRECATED: contains the domain used to define host FQDN
 	GatewayID          string         `json:"gateway_id,omitempty"`           // DEPRECATED: contains the id of the host acting as primary gateway for the network
 	SecondaryGatewayID string         `json:"secondary_gateway_id,omitempty"` // DEPRECATED: contains the id of the host acting as secondary gateway for the network
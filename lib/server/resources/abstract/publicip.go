@@ -0,0 +1,120 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package abstract
+
+import (
+	"encoding/json"
+
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// PublicIPRequest represents a request to allocate a public IP
+type PublicIPRequest struct {
+	Name        string `json:"name,omitempty"`
+	Type        string `json:"type,omitempty"` // provider-specific kind of public IP; empty means provider default
+	Description string `json:"description,omitempty"`
+}
+
+// PublicIP represents a public IP allocated in the tenant, that can be moved from one host to another
+type PublicIP struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+	IPAddress   string `json:"ip_address,omitempty"`
+	MacAddress  string `json:"mac_address,omitempty"`
+	HostID      string `json:"host_id,omitempty"` // contains the ID of the host the public IP is bound to, empty if not bound
+}
+
+// NewPublicIP ...
+func NewPublicIP() *PublicIP {
+	return &PublicIP{}
+}
+
+// IsNull tells if the instance is a null value
+func (pip *PublicIP) IsNull() bool {
+	return pip == nil || (pip.ID == "" && pip.IPAddress == "")
+}
+
+// Clone ...
+//
+// satisfies interface data.Clonable
+func (pip PublicIP) Clone() data.Clonable {
+	return NewPublicIP().Replace(&pip)
+}
+
+// Replace ...
+//
+// satisfies interface data.Clonable
+func (pip *PublicIP) Replace(p data.Clonable) data.Clonable {
+	// Do not test with isNull(), it's allowed to clone a null value...
+	if pip == nil || p == nil {
+		return pip
+	}
+
+	src := p.(*PublicIP)
+	*pip = *src
+	return pip
+}
+
+// OK ...
+func (pip *PublicIP) OK() bool {
+	result := true
+	result = result && pip != nil
+	result = result && pip.ID != ""
+	result = result && pip.Name != ""
+	result = result && pip.IPAddress != ""
+	return result
+}
+
+// Serialize serializes PublicIP instance into bytes (output json code)
+func (pip *PublicIP) Serialize() ([]byte, fail.Error) {
+	if pip == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	r, err := json.Marshal(pip)
+	return r, fail.ConvertError(err)
+}
+
+// Deserialize reads json code and restores a PublicIP
+func (pip *PublicIP) Deserialize(buf []byte) (xerr fail.Error) {
+	if pip == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	defer fail.OnPanic(&xerr) // json.Unmarshal may panic
+	return fail.ConvertError(json.Unmarshal(buf, pip))
+}
+
+// GetName returns the name of the public IP
+// Satisfies interface data.Identifiable
+func (pip *PublicIP) GetName() string {
+	if pip == nil {
+		return ""
+	}
+	return pip.Name
+}
+
+// GetID returns the ID of the public IP
+// Satisfies interface data.Identifiable
+func (pip *PublicIP) GetID() string {
+	if pip == nil {
+		return ""
+	}
+	return pip.ID
+}
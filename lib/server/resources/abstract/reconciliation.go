@@ -0,0 +1,71 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package abstract
+
+import (
+	"time"
+)
+
+// Statuses of a resource compared between metadata and provider side
+const (
+	ReconcileOrphanInCloud    = "orphan-in-cloud"    // resource found on provider side without metadata
+	ReconcileOrphanInMetadata = "orphan-in-metadata" // resource registered in metadata not found on provider side
+	ReconcileMismatched       = "mismatched"         // resource found on both sides with differences
+	ReconcileUnmanaged        = "unmanaged"          // resource found on provider side without metadata nor SafeScale tags; can only be imported
+	ReconcileRecent           = "recent"             // resource found on provider side without metadata, too recent to tell if an operation in progress owns it
+	ReconcileForeign          = "foreign"            // resource found on provider side without metadata, created by SafeScale for another tenant or installation; left alone
+)
+
+// Actions fixing the differences between metadata and provider side
+const (
+	ReconcileImport     = "import"      // registers in metadata a resource found on provider side
+	ReconcilePurge      = "purge"       // deletes the metadata of a resource not found on provider side
+	ReconcilePurgeCloud = "purge-cloud" // deletes on provider side a resource without metadata
+	ReconcileRepair     = "repair"      // fixes the differences of a resource found on both sides
+)
+
+// ReconcileOptions tells what kinds of resources to compare between metadata and provider side, and the actions to apply
+type ReconcileOptions struct {
+	Kinds      []string // kinds of resources to compare (name of their metadata folder); all if empty
+	DryRun     bool     // reports the actions without applying them
+	Import     bool     // imports the orphans in cloud
+	PurgeCloud bool     // deletes the orphans in cloud; cannot be used with Import
+	Purge      bool     // purges the orphans in metadata
+	Repair     bool     // repairs the mismatched resources
+	// MinAge is the age under which a resource on provider side without metadata is left alone, an operation in progress
+	// may be registering it; never less than the long operation timeout
+	MinAge time.Duration
+	// Only restricts the actions to the resources listed, identified by ReconcileEntry.Ref(); all the resources if empty
+	Only []string
+}
+
+// ReconcileEntry describes a resource whose metadata and provider side differ
+type ReconcileEntry struct {
+	Kind    string `json:"kind"`              // kind of resource (name of its metadata folder)
+	ID      string `json:"id"`                // ID of the resource
+	Name    string `json:"name,omitempty"`    // name of the resource
+	Status  string `json:"status"`            // one of the Reconcile* statuses
+	Details string `json:"details,omitempty"` // description of the differences
+	Action  string `json:"action,omitempty"`  // action requested for the resource, if any
+	Applied bool   `json:"applied,omitempty"` // tells if Action has been applied successfully
+	Error   string `json:"error,omitempty"`   // failure met applying Action
+}
+
+// Ref returns the reference of the resource of the entry, usable in ReconcileOptions.Only
+func (e ReconcileEntry) Ref() string {
+	return e.Kind + "/" + e.ID
+}
@@ -454,3 +454,25 @@ func (sg *SecurityGroup) GetID() string {
 	}
 	return sg.ID
 }
+
+// SecurityGroupRuleChange describes a rule registered in metadata whose provider rules have been modified
+type SecurityGroupRuleChange struct {
+	Expected *SecurityGroupRule `json:"expected"` // rule as registered in metadata
+	Actual   SecurityGroupRules `json:"actual"`   // provider rules sharing an ID with Expected
+}
+
+// SecurityGroupDrift describes the differences between the rules of a Security Group registered in metadata and the ones
+// on provider side
+type SecurityGroupDrift struct {
+	ID      string                    `json:"id"`
+	Name    string                    `json:"name"`
+	Added   SecurityGroupRules        `json:"added,omitempty"`   // rules found on provider side only
+	Removed SecurityGroupRules        `json:"removed,omitempty"` // rules registered in metadata missing on provider side
+	Changed []SecurityGroupRuleChange `json:"changed,omitempty"` // rules registered in metadata modified on provider side
+	Fixed   bool                      `json:"fixed,omitempty"`   // tells if the drift has been fixed
+}
+
+// IsEmpty tells if there is no difference between metadata and provider side
+func (d *SecurityGroupDrift) IsEmpty() bool {
+	return d == nil || (len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0)
+}
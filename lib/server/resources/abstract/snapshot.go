@@ -0,0 +1,122 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package abstract
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumestate"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// SnapshotRequest represents a request to snapshot a volume
+type SnapshotRequest struct {
+	Name        string `json:"name,omitempty"`
+	VolumeID    string `json:"volume_id,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Snapshot represents a point-in-time copy of a block volume
+type Snapshot struct {
+	ID          string           `json:"id,omitempty"`
+	Name        string           `json:"name,omitempty"`
+	Description string           `json:"description,omitempty"`
+	VolumeID    string           `json:"volume_id,omitempty"` // contains the ID of the volume the snapshot has been taken from
+	Size        int              `json:"size,omitempty"`      // size in GB of the source volume
+	State       volumestate.Enum `json:"state,omitempty"`
+	CreatedAt   time.Time        `json:"created_at,omitempty"`
+}
+
+// NewSnapshot ...
+func NewSnapshot() *Snapshot {
+	return &Snapshot{}
+}
+
+// IsNull tells if the instance is a null value
+func (s *Snapshot) IsNull() bool {
+	return s == nil || (s.ID == "" && s.Name == "")
+}
+
+// Clone ...
+//
+// satisfies interface data.Clonable
+func (s Snapshot) Clone() data.Clonable {
+	return NewSnapshot().Replace(&s)
+}
+
+// Replace ...
+//
+// satisfies interface data.Clonable
+func (s *Snapshot) Replace(p data.Clonable) data.Clonable {
+	// Do not test with isNull(), it's allowed to clone a null value...
+	if s == nil || p == nil {
+		return s
+	}
+
+	src := p.(*Snapshot)
+	*s = *src
+	return s
+}
+
+// OK ...
+func (s *Snapshot) OK() bool {
+	result := true
+	result = result && s != nil
+	result = result && s.ID != ""
+	result = result && s.Name != ""
+	result = result && s.VolumeID != ""
+	return result
+}
+
+// Serialize serializes Snapshot instance into bytes (output json code)
+func (s *Snapshot) Serialize() ([]byte, fail.Error) {
+	if s == nil {
+		return nil, fail.InvalidInstanceError()
+	}
+	r, err := json.Marshal(s)
+	return r, fail.ConvertError(err)
+}
+
+// Deserialize reads json code and restores a Snapshot
+func (s *Snapshot) Deserialize(buf []byte) (xerr fail.Error) {
+	if s == nil {
+		return fail.InvalidInstanceError()
+	}
+
+	defer fail.OnPanic(&xerr) // json.Unmarshal may panic
+	return fail.ConvertError(json.Unmarshal(buf, s))
+}
+
+// GetName returns the name of the snapshot
+// Satisfies interface data.Identifiable
+func (s *Snapshot) GetName() string {
+	if s == nil {
+		return ""
+	}
+	return s.Name
+}
+
+// GetID returns the ID of the snapshot
+// Satisfies interface data.Identifiable
+func (s *Snapshot) GetID() string {
+	if s == nil {
+		return ""
+	}
+	return s.ID
+}
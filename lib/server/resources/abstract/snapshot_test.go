@@ -0,0 +1,62 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package abstract
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshot_Clone(t *testing.T) {
+	s := NewSnapshot()
+	s.Name = "snap"
+	s.VolumeID = "volume"
+
+	sc, ok := s.Clone().(*Snapshot)
+	if !ok {
+		t.Fail()
+	}
+
+	assert.Equal(t, s, sc)
+	sc.CreatedAt = time.Now()
+
+	areEqual := reflect.DeepEqual(s, sc)
+	if areEqual {
+		t.Error("It's a shallow clone !")
+		t.Fail()
+	}
+}
+
+func TestSnapshot_Serialize(t *testing.T) {
+	s := NewSnapshot()
+	s.ID = "id"
+	s.Name = "snap"
+	s.VolumeID = "volume"
+	s.Size = 10
+	s.CreatedAt = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	buf, xerr := s.Serialize()
+	assert.Nil(t, xerr)
+
+	restored := NewSnapshot()
+	assert.Nil(t, restored.Deserialize(buf))
+	assert.Equal(t, s, restored)
+	assert.True(t, restored.OK())
+}
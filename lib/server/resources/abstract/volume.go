@@ -27,9 +27,10 @@ import (
 
 // VolumeRequest represents a volume request
 type VolumeRequest struct {
-	Name  string           `json:"name,omitempty"`
-	Size  int              `json:"size,omitempty"`
-	Speed volumespeed.Enum `json:"speed,omitempty"`
+	Name   string            `json:"name,omitempty"`
+	Size   int               `json:"size,omitempty"`
+	Speed  volumespeed.Enum  `json:"speed,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 // Volume represents a block volume
@@ -39,6 +40,8 @@ type Volume struct {
 	Size  int              `json:"size,omitempty"`
 	Speed volumespeed.Enum `json:"speed,omitempty"`
 	State volumestate.Enum `json:"state,omitempty"`
+
+	Tags map[string]string `json:"-"` // tags read on provider side, not kept in metadata
 }
 
 // NewVolume ...
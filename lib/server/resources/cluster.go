@@ -24,6 +24,7 @@ import (
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clustercomplexity"
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterflavor"
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterstate"
+	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
 	propertiesv3 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v3"
 	"github.com/CS-SI/SafeScale/lib/utils/data"
 	"github.com/CS-SI/SafeScale/lib/utils/data/cache"
@@ -41,39 +42,57 @@ type Cluster interface {
 	observer.Observable
 	cache.Cacheable
 
-	AddFeature(ctx context.Context, name string, vars data.Map, settings FeatureSettings) (Results, fail.Error)             // adds feature on cluster
-	AddNode(ctx context.Context, def abstract.HostSizingRequirements, keepOnFailure bool) (Host, fail.Error)                // adds a node
-	AddNodes(ctx context.Context, count uint, def abstract.HostSizingRequirements, keepOnFailure bool) ([]Host, fail.Error) // adds several nodes
-	Browse(ctx context.Context, callback func(*abstract.ClusterIdentity) fail.Error) fail.Error                             // browse in metadata clusters and execute a callback on each entry
-	CheckFeature(ctx context.Context, name string, vars data.Map, settings FeatureSettings) (Results, fail.Error)           // checks feature on cluster
-	CountNodes(ctx context.Context) (uint, fail.Error)                                                                      // counts the nodes of the cluster
-	Create(ctx context.Context, req abstract.ClusterRequest) fail.Error                                                     // creates a new cluster and save its metadata
-	DeleteLastNode(ctx context.Context) (*propertiesv3.ClusterNode, fail.Error)                                             // deletes the last added node and returns its name
-	DeleteSpecificNode(ctx context.Context, hostID string, selectedMasterID string) fail.Error                              // deletes a node identified by its ID
-	Delete(ctx context.Context, force bool) fail.Error                                                                      // deletes the cluster (Delete is not used to not collision with metadata)
-	FindAvailableMaster(ctx context.Context) (Host, fail.Error)                                                             // returns ID of the first master available to execute order
-	FindAvailableNode(ctx context.Context) (Host, fail.Error)                                                               // returns node instance of the first node available to execute order
-	GetIdentity() (abstract.ClusterIdentity, fail.Error)                                                                    // returns Cluster Identity
-	GetFlavor() (clusterflavor.Enum, fail.Error)                                                                            // returns the flavor of the cluster
-	GetComplexity() (clustercomplexity.Enum, fail.Error)                                                                    // returns the complexity of the cluster
-	GetAdminPassword() (string, fail.Error)                                                                                 // returns the password of the cluster admin account
-	GetKeyPair() (abstract.KeyPair, fail.Error)                                                                             // returns the key pair used in the cluster
-	GetNetworkConfig() (*propertiesv3.ClusterNetwork, fail.Error)                                                           // returns network configuration of the cluster
-	GetState() (clusterstate.Enum, fail.Error)                                                                              // returns the current state of the cluster
-	IsFeatureInstalled(ctx context.Context, name string) (found bool, xerr fail.Error)                                      // tells if a feature is installed in Cluster using only metadata
-	ListInstalledFeatures(ctx context.Context) ([]Feature, fail.Error)                                                      // returns the list of installed features
-	ListMasters(ctx context.Context) (IndexedListOfClusterNodes, fail.Error)                                                // lists the node instances corresponding to masters (if there is such masters in the flavor...)
-	ListMasterIDs(ctx context.Context) (data.IndexedListOfStrings, fail.Error)                                              // lists the IDs of masters (if there is such masters in the flavor...)
-	ListMasterIPs(ctx context.Context) (data.IndexedListOfStrings, fail.Error)                                              // lists the IPs of masters (if there is such masters in the flavor...)
-	ListMasterNames(ctx context.Context) (data.IndexedListOfStrings, fail.Error)                                            // lists the names of the master nodes in the Cluster
-	ListNodes(ctx context.Context) (IndexedListOfClusterNodes, fail.Error)                                                  // lists node instances corresponding to the nodes in the cluster
-	ListNodeIDs(ctx context.Context) (data.IndexedListOfStrings, fail.Error)                                                // lists the IDs of the nodes in the cluster
-	ListNodeIPs(ctx context.Context) (data.IndexedListOfStrings, fail.Error)                                                // lists the IPs of the nodes in the cluster
-	ListNodeNames(ctx context.Context) (data.IndexedListOfStrings, fail.Error)                                              // lists the names of the nodes in the Cluster
-	LookupNode(ctx context.Context, ref string) (bool, fail.Error)                                                          // tells if the ID of the host passed as parameter is a node
-	RemoveFeature(ctx context.Context, name string, vars data.Map, settings FeatureSettings) (Results, fail.Error)          // removes feature from cluster
-	Shrink(ctx context.Context, count uint) ([]*propertiesv3.ClusterNode, fail.Error)                                       // reduce the size of the cluster of 'count' nodes (the last created)
-	Start(ctx context.Context) fail.Error                                                                                   // starts the cluster
-	Stop(ctx context.Context) fail.Error                                                                                    // stops the cluster
+	AddFeature(ctx context.Context, name string, vars data.Map, settings FeatureSettings) (Results, fail.Error)                                     // adds feature on cluster
+	AddNode(ctx context.Context, def abstract.HostSizingRequirements, keepOnFailure bool) (Host, fail.Error)                                        // adds a node
+	AddNodes(ctx context.Context, count uint, def abstract.HostSizingRequirements, keepOnFailure bool) ([]Host, fail.Error)                         // adds several nodes
+	AddNodePool(ctx context.Context, pool propertiesv1.ClusterNodePool, count uint, keepOnFailure bool) (*propertiesv1.ClusterNodePool, fail.Error) // creates a node pool with 'count' nodes
+	Autoscale(ctx context.Context) (*propertiesv1.ClusterScaleDecision, fail.Error)                                                                 // adds or removes nodes depending on their load, if autoscaling is enabled
+	Backup(ctx context.Context, bucketName string) (*propertiesv1.ClusterBackup, fail.Error)                                                        // saves the control plane of the cluster in a bucket
+	Browse(ctx context.Context, callback func(*abstract.ClusterIdentity) fail.Error) fail.Error                                                     // browse in metadata clusters and execute a callback on each entry
+	CheckFeature(ctx context.Context, name string, vars data.Map, settings FeatureSettings) (Results, fail.Error)                                   // checks feature on cluster
+	CheckHealth(ctx context.Context) ([]*propertiesv1.ClusterNodeRepair, fail.Error)                                                                // checks the masters and nodes of the cluster, replacing failed nodes if auto repair is enabled
+	CountNodes(ctx context.Context) (uint, fail.Error)                                                                                              // counts the nodes of the cluster
+	Create(ctx context.Context, req abstract.ClusterRequest) fail.Error                                                                             // creates a new cluster and save its metadata
+	DeleteLastNode(ctx context.Context) (*propertiesv3.ClusterNode, fail.Error)                                                                     // deletes the last added node and returns its name
+	DeleteSpecificNode(ctx context.Context, hostID string, selectedMasterID string) fail.Error                                                      // deletes a node identified by its ID
+	DeleteNodePool(ctx context.Context, name string) fail.Error                                                                                     // deletes a node pool and its nodes
+	Delete(ctx context.Context, force bool) fail.Error                                                                                              // deletes the cluster (Delete is not used to not collision with metadata)
+	FindAvailableMaster(ctx context.Context) (Host, fail.Error)                                                                                     // returns ID of the first master available to execute order
+	FindAvailableNode(ctx context.Context) (Host, fail.Error)                                                                                       // returns node instance of the first node available to execute order
+	GetIdentity() (abstract.ClusterIdentity, fail.Error)                                                                                            // returns Cluster Identity
+	GetFlavor() (clusterflavor.Enum, fail.Error)                                                                                                    // returns the flavor of the cluster
+	GetComplexity() (clustercomplexity.Enum, fail.Error)                                                                                            // returns the complexity of the cluster
+	GetLabels() (map[string]string, fail.Error)                                                                                                     // returns the user-defined labels of the cluster
+	GetAutoscaling(ctx context.Context) (*propertiesv1.ClusterAutoscaling, fail.Error)                                                              // returns the autoscaling settings of the cluster and its last scale decisions
+	GetHealth(ctx context.Context) (*propertiesv1.ClusterHealth, *propertiesv3.ClusterNodes, fail.Error)                                            // returns the health check settings of the cluster, its last repairs and the health of its masters and nodes
+	GetUpgrade(ctx context.Context) (*propertiesv1.ClusterUpgrade, fail.Error)                                                                      // returns the progress of the last upgrade of the hosts of the cluster
+	GetAdminPassword() (string, fail.Error)                                                                                                         // returns the password of the cluster admin account
+	GetKeyPair() (abstract.KeyPair, fail.Error)                                                                                                     // returns the key pair used in the cluster
+	GetKubeconfig(ctx context.Context, server string) (*abstract.ClusterKubeconfig, fail.Error)                                                     // returns the kubeconfig of the cluster admin, using server (or the endpoint of the cluster if empty) as API server
+	GetNetworkConfig() (*propertiesv3.ClusterNetwork, fail.Error)                                                                                   // returns network configuration of the cluster
+	GetState() (clusterstate.Enum, fail.Error)                                                                                                      // returns the current state of the cluster
+	IsFeatureInstalled(ctx context.Context, name string) (found bool, xerr fail.Error)                                                              // tells if a feature is installed in Cluster using only metadata
+	ListInstalledFeatures(ctx context.Context) ([]Feature, fail.Error)                                                                              // returns the list of installed features
+	ListBackups(ctx context.Context) (*propertiesv1.ClusterBackups, fail.Error)                                                                     // lists the backups of the cluster recorded in metadata
+	ListMasters(ctx context.Context) (IndexedListOfClusterNodes, fail.Error)                                                                        // lists the node instances corresponding to masters (if there is such masters in the flavor...)
+	ListMasterIDs(ctx context.Context) (data.IndexedListOfStrings, fail.Error)                                                                      // lists the IDs of masters (if there is such masters in the flavor...)
+	ListMasterIPs(ctx context.Context) (data.IndexedListOfStrings, fail.Error)                                                                      // lists the IPs of masters (if there is such masters in the flavor...)
+	ListMasterNames(ctx context.Context) (data.IndexedListOfStrings, fail.Error)                                                                    // lists the names of the master nodes in the Cluster
+	ListNodePools(ctx context.Context) ([]*propertiesv1.ClusterNodePool, fail.Error)                                                                // lists the node pools of the cluster
+	ListNodes(ctx context.Context) (IndexedListOfClusterNodes, fail.Error)                                                                          // lists node instances corresponding to the nodes in the cluster
+	ListNodeIDs(ctx context.Context) (data.IndexedListOfStrings, fail.Error)                                                                        // lists the IDs of the nodes in the cluster
+	ListNodeIPs(ctx context.Context) (data.IndexedListOfStrings, fail.Error)                                                                        // lists the IPs of the nodes in the cluster
+	ListNodeNames(ctx context.Context) (data.IndexedListOfStrings, fail.Error)                                                                      // lists the names of the nodes in the Cluster
+	LookupNode(ctx context.Context, ref string) (bool, fail.Error)                                                                                  // tells if the ID of the host passed as parameter is a node
+	RemoveFeature(ctx context.Context, name string, vars data.Map, settings FeatureSettings) (Results, fail.Error)                                  // removes feature from cluster
+	ResizeNodePool(ctx context.Context, name string, count uint, keepOnFailure bool) (*propertiesv1.ClusterNodePool, fail.Error)                    // adds or removes nodes of a node pool to reach 'count' nodes
+	Restore(ctx context.Context, bucketName, id string) (*propertiesv1.ClusterBackup, fail.Error)                                                   // rebuilds the control plane of the cluster from a backup
+	Resume(ctx context.Context) fail.Error                                                                                                          // resumes an unfinished creation of the cluster from its last successful step
+	SetAutoscaling(ctx context.Context, settings propertiesv1.ClusterAutoscaling) (*propertiesv1.ClusterAutoscaling, fail.Error)                    // replaces the autoscaling settings of the cluster
+	SetHealth(ctx context.Context, settings propertiesv1.ClusterHealth) (*propertiesv1.ClusterHealth, fail.Error)                                   // replaces the health check settings of the cluster
+	Shrink(ctx context.Context, count uint) ([]*propertiesv3.ClusterNode, fail.Error)                                                               // reduce the size of the cluster of 'count' nodes (the last created)
+	Start(ctx context.Context) fail.Error                                                                                                           // starts the cluster
+	Stop(ctx context.Context) fail.Error                                                                                                            // stops the cluster
 	ToProtocol() (*protocol.ClusterResponse, fail.Error)
+	Upgrade(ctx context.Context, req abstract.ClusterUpgradeRequest, resume bool) (*propertiesv1.ClusterUpgrade, fail.Error) // upgrades the hosts of the cluster one after the other, or resumes the last upgrade
 }
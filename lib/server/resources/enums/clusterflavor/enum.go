@@ -42,6 +42,9 @@ const (
 
 	// OHPC for a OpenHPC cluster
 	// OHPC = 5
+
+	// K3S for a lightweight Kubernetes cluster, using K3s
+	K3S = 6
 )
 
 var (
@@ -51,6 +54,7 @@ var (
 		// "swarm": SWARM,
 		"boh": BOH,
 		// "ohpc":  OHPC,
+		"k3s": K3S,
 	}
 
 	enumMap = map[Enum]string{
@@ -59,6 +63,7 @@ var (
 		// SWARM: "SWARM",
 		BOH: "BOH",
 		// OHPC:  "OHPC",
+		K3S: "K3S",
 	}
 )
 
@@ -52,4 +52,18 @@ const (
 	NetworkV3 = "13"
 	// NodesV3 contains optional additional info about network of the cluster
 	NodesV3 = "14"
+	// LabelsV1 contains the user-defined labels of the cluster
+	LabelsV1 = "15"
+	// CreationV1 contains the progress of the creation of the cluster, to be able to resume it
+	CreationV1 = "16"
+	// NodePoolsV1 contains the named pools of nodes of the cluster, each with its own sizing
+	NodePoolsV1 = "17"
+	// AutoscalingV1 contains the autoscaling settings of the cluster and the last scale decisions taken
+	AutoscalingV1 = "18"
+	// UpgradeV1 contains the progress of the last rolling upgrade of the hosts of the cluster, to be able to resume it
+	UpgradeV1 = "19"
+	// BackupsV1 contains the backups of the control plane of the cluster saved in Object Storage, and the last restoration
+	BackupsV1 = "20"
+	// HealthV1 contains the health check settings of the cluster and the last repairs of failed nodes
+	HealthV1 = "21"
 )
@@ -47,4 +47,6 @@ const (
 
 	// Unknown ...
 	Unknown
+	// Upgrading the hosts of the cluster are being upgraded
+	Upgrading
 )
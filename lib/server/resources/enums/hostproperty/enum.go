@@ -29,4 +29,6 @@ const (
 	ClusterMembershipV1 = "10" // optional additional information about the cluster membership of the host
 	SecurityGroupsV1    = "11" // optional additional information about security groups binded to the host
 	NetworkV2           = "12" // NetworkV2 contains optional additional information about network of the host
+	PublicIPsV1         = "13" // optional additional information about public IPs bound to the host
+	LabelsV1            = "14" // optional user-defined labels of the host
 )
@@ -25,4 +25,5 @@ const (
 	SubnetsV1        = "3" // contains the subnets created in the Network
 	SingleHostsV1    = "4" // contains the CIDRs usable for single Hosts
 	SecurityGroupsV1 = "5" // contains the Security Groups owned by the Network
+	LabelsV1         = "6" // contains the user-defined labels of the Network
 )
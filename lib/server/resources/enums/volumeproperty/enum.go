@@ -24,4 +24,6 @@ const (
 	DescriptionV1 = "1"
 	// AttachedV1 contains additional information about hosts attaching the volume
 	AttachedV1 = "2"
+	// LabelsV1 contains the user-defined labels of the volume
+	LabelsV1 = "3"
 )
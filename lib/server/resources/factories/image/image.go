@@ -0,0 +1,68 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package image
+
+import (
+	"context"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/resources"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/operations"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// List returns a list of the custom images created with SafeScale
+func List(ctx context.Context, svc iaas.Service) ([]*abstract.Image, fail.Error) {
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if svc == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("svc")
+	}
+
+	imageInstance, xerr := New(svc)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	var list []*abstract.Image
+	xerr = imageInstance.Browse(ctx, func(ai *abstract.Image) fail.Error {
+		list = append(list, ai)
+		return nil
+	})
+	return list, xerr
+}
+
+// New creates an instance of resources.Image
+func New(svc iaas.Service) (_ resources.Image, xerr fail.Error) {
+	if svc == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("svc")
+	}
+
+	imageInstance, xerr := operations.NewImage(svc)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return imageInstance, nil
+}
+
+// Load loads the metadata of a custom image and returns an instance of resources.Image
+func Load(svc iaas.Service, ref string) (_ resources.Image, xerr fail.Error) {
+	return operations.LoadImage(svc, ref)
+}
@@ -74,3 +74,8 @@ func New(svc iaas.Service) (resources.Network, fail.Error) {
 func Load(svc iaas.Service, ref string) (resources.Network, fail.Error) {
 	return operations.LoadNetwork(svc, ref)
 }
+
+// Import registers in metadata an existing network of the provider, with its subnets, and returns an instance of resources.Network
+func Import(ctx context.Context, svc iaas.Service, ref string) (resources.Network, fail.Error) {
+	return operations.ImportNetworkWithSubnets(ctx, svc, ref)
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package publicip
+
+import (
+	"context"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/resources"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/operations"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// List returns a list of available Public IPs
+func List(ctx context.Context, svc iaas.Service, all bool) ([]*abstract.PublicIP, fail.Error) {
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if svc == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("svc")
+	}
+
+	if all {
+		return svc.ListPublicIPs()
+	}
+
+	pipInstance, xerr := New(svc)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	var list []*abstract.PublicIP
+	xerr = pipInstance.Browse(ctx, func(apip *abstract.PublicIP) fail.Error {
+		list = append(list, apip)
+		return nil
+	})
+	return list, xerr
+}
+
+// New creates an instance of resources.PublicIP
+func New(svc iaas.Service) (_ resources.PublicIP, xerr fail.Error) {
+	if svc == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("svc")
+	}
+
+	pipInstance, xerr := operations.NewPublicIP(svc)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return pipInstance, nil
+}
+
+// Load loads the metadata of Public IP and returns an instance of resources.PublicIP
+func Load(svc iaas.Service, ref string) (_ resources.PublicIP, xerr fail.Error) {
+	return operations.LoadPublicIP(svc, ref)
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package snapshot
+
+import (
+	"context"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/resources"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/operations"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// List returns a list of volume snapshots; if volumeID is not empty, only the snapshots of this volume are returned
+func List(ctx context.Context, svc iaas.Service, volumeID string, all bool) ([]*abstract.Snapshot, fail.Error) {
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if svc == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("svc")
+	}
+
+	if all {
+		return svc.ListVolumeSnapshots(volumeID)
+	}
+
+	snapshotInstance, xerr := New(svc)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	var list []*abstract.Snapshot
+	xerr = snapshotInstance.Browse(ctx, func(as *abstract.Snapshot) fail.Error {
+		if volumeID == "" || as.VolumeID == volumeID {
+			list = append(list, as)
+		}
+		return nil
+	})
+	return list, xerr
+}
+
+// New creates an instance of resources.Snapshot
+func New(svc iaas.Service) (_ resources.Snapshot, xerr fail.Error) {
+	if svc == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("svc")
+	}
+
+	snapshotInstance, xerr := operations.NewSnapshot(svc)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return snapshotInstance, nil
+}
+
+// Load loads the metadata of a volume snapshot and returns an instance of resources.Snapshot
+func Load(svc iaas.Service, ref string) (_ resources.Snapshot, xerr fail.Error) {
+	return operations.LoadSnapshot(svc, ref)
+}
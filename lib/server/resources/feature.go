@@ -33,6 +33,7 @@ type Targetable interface {
 	ComplementFeatureParameters(ctx context.Context, v data.Map) fail.Error        // adds parameters corresponding to the Target in preparation of feature installation
 	UnregisterFeature(f string) fail.Error                                         // unregisters a Feature from Target in metadata
 	InstalledFeatures() []string                                                   // returns a list of installed features
+	InstalledFeatureVersion(f string) string                                       // returns the version of an installed feature recorded in metadata, empty if unknown
 	InstallMethods() map[uint8]installmethod.Enum                                  // returns a list of installation methods useable on the target, ordered from upper to lower preference (1 = highest preference)
 	RegisterFeature(f Feature, requiredBy Feature, clusterContext bool) fail.Error // registers a feature on target in metadata
 	TargetType() featuretargettype.Enum                                            // returns the type of the target
@@ -43,13 +44,15 @@ type Feature interface {
 	data.Clonable
 	data.Identifiable
 
-	Add(ctx context.Context, t Targetable, v data.Map, fs FeatureSettings) (Results, fail.Error)    // Add installs the feature on the target
-	Applyable(Targetable) bool                                                                      // Applyable tells if the feature is installable on the target
-	GetDisplayFilename() string                                                                     // GetDisplayFilename displays the filename of display (optionally adding '[embedded]' for embedded features)
-	GetFilename() string                                                                            // GetFilename returns the filename of the feature
-	GetRequirements() (map[string]struct{}, fail.Error)                                             // GetRequirements returns the other features needed as requirements
-	Check(ctx context.Context, t Targetable, v data.Map, fs FeatureSettings) (Results, fail.Error)  // Check if feature is installed on target
-	Remove(ctx context.Context, t Targetable, v data.Map, fs FeatureSettings) (Results, fail.Error) // Remove uninstalls the feature from the target
+	Add(ctx context.Context, t Targetable, v data.Map, fs FeatureSettings) (Results, fail.Error)     // Add installs the feature on the target
+	Applyable(Targetable) bool                                                                       // Applyable tells if the feature is installable on the target
+	GetDisplayFilename() string                                                                      // GetDisplayFilename displays the filename of display (optionally adding '[embedded]' for embedded features)
+	GetFilename() string                                                                             // GetFilename returns the filename of the feature
+	GetRequirements() (map[string]struct{}, fail.Error)                                              // GetRequirements returns the other features needed as requirements
+	GetVersion() string                                                                              // GetVersion returns the version declared by the feature, empty if none
+	Check(ctx context.Context, t Targetable, v data.Map, fs FeatureSettings) (Results, fail.Error)   // Check if feature is installed on target
+	Remove(ctx context.Context, t Targetable, v data.Map, fs FeatureSettings) (Results, fail.Error)  // Remove uninstalls the feature from the target
+	Upgrade(ctx context.Context, t Targetable, v data.Map, fs FeatureSettings) (Results, fail.Error) // Upgrade updates the feature installed on the target if its version is older
 	ToProtocol() *protocol.FeatureResponse
 }
 
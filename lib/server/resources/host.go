@@ -50,6 +50,7 @@ type Host interface {
 	ForceGetState(ctx context.Context) (hoststate.Enum, fail.Error)                                                                              // returns the real current state of the host, with error handling
 	GetAccessIP() (string, fail.Error)                                                                                                           // returns the IP to reach the host, with error handling
 	GetDefaultSubnet() (Subnet, fail.Error)                                                                                                      // returns the resources.Subnet instance corresponding to the default subnet of the host, with error handling
+	GetLabels() (map[string]string, fail.Error)                                                                                                  // returns the user-defined labels of the host
 	GetMounts() (*propertiesv1.HostMounts, fail.Error)                                                                                           // returns the mounts on the host
 	GetPrivateIP() (ip string, err fail.Error)                                                                                                   // returns the IP address of the host on the default subnet, with error handling
 	GetPrivateIPOnSubnet(subnetID string) (ip string, err fail.Error)                                                                            // returns the IP address of the host on the requested subnet, with error handling
@@ -59,6 +60,7 @@ type Host interface {
 	GetSSHConfig() (*system.SSHConfig, fail.Error)                                                                                               // loads SSH configuration for host from metadata
 	GetState() hoststate.Enum                                                                                                                    // returns the current state of the host, with error handling
 	GetVolumes() (*propertiesv1.HostVolumes, fail.Error)                                                                                         // returns the volumes attached to the host
+	Import(ctx context.Context, req abstract.HostImportRequest) fail.Error                                                                       // registers in metadata an existing host of the provider
 	IsClusterMember() (bool, fail.Error)                                                                                                         // returns true if the host is member of a cluster
 	IsFeatureInstalled(f string) (bool, fail.Error)                                                                                              // tells if a feature is installed on Host, using only metadata
 	IsGateway() (bool, fail.Error)                                                                                                               // tells of  the host acts as a gateway
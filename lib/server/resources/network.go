@@ -40,6 +40,7 @@ type Network interface {
 	Browse(ctx context.Context, callback func(*abstract.Network) fail.Error) fail.Error // call the callback for each entry of the metadata folder of Networks
 	Create(ctx context.Context, req abstract.NetworkRequest) fail.Error                 // creates a Network
 	Delete(ctx context.Context) fail.Error
+	GetLabels() (map[string]string, fail.Error) // returns the user-defined labels of the Network
 	Import(ctx context.Context, ref string) fail.Error
 	InspectSubnet(subnetRef string) (Subnet, fail.Error) // returns the Subnet instance corresponding to Subnet reference (ID or name) provided (if Subnet is attached to the Network)
 	ToProtocol() (*protocol.Network, fail.Error)         // converts the network to protobuf message
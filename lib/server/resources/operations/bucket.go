@@ -369,7 +369,13 @@ func (instance *bucket) Delete(ctx context.Context) (xerr fail.Error) {
 	instance.lock.Lock()
 	defer instance.lock.Unlock()
 
-	return instance.GetService().DeleteBucket(instance.GetName())
+	xerr = instance.GetService().DeleteBucket(instance.GetName())
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	return instance.MetadataCore.Delete()
 }
 
 // Mount a bucket on an host on the given mount point
@@ -0,0 +1,48 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+func Test_bucket_DeleteRemovesMetadata(t *testing.T) {
+	svc := getMemoryService(t)
+	ctx := context.Background()
+
+	rb, xerr := NewBucket(svc)
+	require.Nil(t, xerr)
+	require.Nil(t, rb.Create(ctx, "manifest-bucket"))
+	require.Nil(t, rb.Delete(ctx))
+
+	_, xerr = svc.InspectBucket("manifest-bucket")
+	assert.NotNil(t, xerr)
+
+	// the metadata is gone too, so a Bucket with the same name can be created again
+	rb, xerr = NewBucket(svc)
+	require.Nil(t, xerr)
+	xerr = rb.Read("manifest-bucket")
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrNotFound{}, xerr)
+	assert.Nil(t, rb.Create(ctx, "manifest-bucket"))
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/CS-SI/SafeScale/lib/protocol"
+	"github.com/CS-SI/SafeScale/lib/server"
 	"github.com/CS-SI/SafeScale/lib/server/iaas"
 	"github.com/CS-SI/SafeScale/lib/server/resources"
 	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
@@ -40,9 +41,11 @@ import (
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusternodetype"
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterproperty"
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterstate"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/hoststate"
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/installmethod"
 	"github.com/CS-SI/SafeScale/lib/server/resources/operations/clusterflavors"
 	"github.com/CS-SI/SafeScale/lib/server/resources/operations/clusterflavors/boh"
+	"github.com/CS-SI/SafeScale/lib/server/resources/operations/clusterflavors/k3s"
 	"github.com/CS-SI/SafeScale/lib/server/resources/operations/clusterflavors/k8s"
 	"github.com/CS-SI/SafeScale/lib/server/resources/operations/converters"
 	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
@@ -289,6 +292,9 @@ func (instance *Cluster) Create(ctx context.Context, req abstract.ClusterRequest
 	if ctx == nil {
 		return fail.InvalidParameterCannotBeNilError("ctx")
 	}
+	if xerr := abstract.ValidateLabels(req.Labels); xerr != nil {
+		return xerr
+	}
 
 	task, xerr := concurrency.TaskFromContext(ctx)
 	xerr = debug.InjectPlannedFail(xerr)
@@ -366,6 +372,8 @@ func (instance *Cluster) bootstrap(flavor clusterflavor.Enum) (xerr fail.Error)
 		instance.makers = boh.Makers
 	case clusterflavor.K8S:
 		instance.makers = k8s.Makers
+	case clusterflavor.K3S:
+		instance.makers = k3s.Makers
 	default:
 		return fail.NotImplementedError("unknown Cluster Flavor '%d'", flavor)
 	}
@@ -463,6 +471,20 @@ func (instance *Cluster) GetComplexity() (_ clustercomplexity.Enum, xerr fail.Er
 	return instance.unsafeGetComplexity()
 }
 
+// GetLabels returns the user-defined labels of the Cluster
+func (instance *Cluster) GetLabels() (_ map[string]string, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	instance.lock.RLock()
+	defer instance.lock.RUnlock()
+
+	return instance.unsafeGetLabels()
+}
+
 // GetAdminPassword returns the password of the Cluster admin account
 // satisfies interface Cluster.Controller
 func (instance *Cluster) GetAdminPassword() (adminPassword string, xerr fail.Error) {
@@ -1101,6 +1123,12 @@ func (instance *Cluster) AddNodes(ctx context.Context, count uint, def abstract.
 	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.cluster"), "(%d)", count)
 	defer tracer.Entering().Exiting()
 
+	xerr = instance.beginNodesChange()
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer instance.endNodesChange()
+
 	// make sure no other parallel actions interferes
 	instance.lock.Lock()
 	defer instance.lock.Unlock()
@@ -1111,11 +1139,23 @@ func (instance *Cluster) AddNodes(ctx context.Context, count uint, def abstract.
 		return nil, xerr
 	}
 
+	hosts, _, xerr := instance.addNodes(ctx, task, count, def, keepOnFailure)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	server.ReportProgress(ctx, server.ProgressEvent{Phase: "done", Step: "nodes added", Percent: 100})
+	return hosts, nil
+}
+
+// addNodes creates, configures and joins count nodes to the Cluster, sized by def complemented with the default node sizing of the Cluster
+// Note: must be called with instance.lock held
+func (instance *Cluster) addNodes(ctx context.Context, task concurrency.Task, count uint, def abstract.HostSizingRequirements, keepOnFailure bool) (_ []resources.Host, _ []*propertiesv3.ClusterNode, ferr fail.Error) {
 	var (
 		hostImage             string
 		nodeDefaultDefinition *propertiesv2.HostSizingRequirements
 	)
-	xerr = instance.Inspect(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+	xerr := instance.Inspect(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
 		return props.Inspect(clusterproperty.DefaultsV2, func(clonable data.Clonable) fail.Error {
 			defaultsV2, ok := clonable.(*propertiesv2.ClusterDefaults)
 			if !ok {
@@ -1128,19 +1168,22 @@ func (instance *Cluster) AddNodes(ctx context.Context, count uint, def abstract.
 	})
 	xerr = debug.InjectPlannedFail(xerr)
 	if xerr != nil {
-		return nil, xerr
+		return nil, nil, xerr
 	}
 
 	if task.Aborted() {
-		return nil, fail.AbortedError(nil, "aborted")
+		return nil, nil, fail.AbortedError(nil, "aborted")
 	}
 
 	nodeDef := complementHostDefinition(def, *nodeDefaultDefinition)
+	if def.Image != "" {
+		hostImage = def.Image
+	}
 
 	svc := instance.GetService()
 	_, nodeDef.Image, xerr = determineImageID(svc, hostImage)
 	if xerr != nil {
-		return nil, xerr
+		return nil, nil, xerr
 	}
 
 	var (
@@ -1150,10 +1193,11 @@ func (instance *Cluster) AddNodes(ctx context.Context, count uint, def abstract.
 
 	timeout := 2 * temporal.GetHostCreationTimeout() // More than enough
 
+	server.ReportProgress(ctx, server.ProgressEvent{Phase: "hosts", Step: fmt.Sprintf("creating %d node%s", count, strprocess.Plural(count))})
 	tg, xerr := concurrency.NewTaskGroupWithParent(task, concurrency.InheritParentIDOption, concurrency.AmendID(fmt.Sprintf("/%d", count)))
 	xerr = debug.InjectPlannedFail(xerr)
 	if xerr != nil {
-		return nil, xerr
+		return nil, nil, xerr
 	}
 
 	for i := uint(1); i <= count; i++ {
@@ -1213,29 +1257,30 @@ func (instance *Cluster) AddNodes(ctx context.Context, count uint, def abstract.
 		}
 	}
 	if xerr != nil {
-		return nil, fail.NewErrorWithCause(xerr, "errors occurred on node%s addition", strprocess.Plural(uint(len(errors))))
+		return nil, nil, fail.NewErrorWithCause(xerr, "errors occurred on node%s addition", strprocess.Plural(uint(len(errors))))
 	}
 
 	// configure what has to be done Cluster-wide
 	if instance.makers.ConfigureCluster != nil {
 		xerr = instance.makers.ConfigureCluster(ctx, instance)
 		if xerr != nil {
-			return nil, xerr
+			return nil, nil, xerr
 		}
 	}
 
 	// Now configure new nodes
+	server.ReportProgress(ctx, server.ProgressEvent{Phase: "configuration", Step: "configuring new nodes", Percent: 70})
 	xerr = instance.configureNodesFromList(task, nodes)
 	xerr = debug.InjectPlannedFail(xerr)
 	if xerr != nil {
-		return nil, xerr
+		return nil, nil, xerr
 	}
 
 	// At last join nodes to Cluster
 	xerr = instance.joinNodesFromList(ctx, nodes)
 	xerr = debug.InjectPlannedFail(xerr)
 	if xerr != nil {
-		return nil, xerr
+		return nil, nil, xerr
 	}
 
 	hosts := make([]resources.Host, 0, len(nodes))
@@ -1243,11 +1288,12 @@ func (instance *Cluster) AddNodes(ctx context.Context, count uint, def abstract.
 		hostInstance, xerr := LoadHost(instance.GetService(), v.ID)
 		xerr = debug.InjectPlannedFail(xerr)
 		if xerr != nil {
-			return nil, xerr
+			return nil, nil, xerr
 		}
 		hosts = append(hosts, hostInstance)
 	}
-	return hosts, nil
+
+	return hosts, nodes, nil
 }
 
 // complementHostDefinition complements req with default values if needed
@@ -1408,6 +1454,12 @@ func (instance *Cluster) DeleteSpecificNode(ctx context.Context, hostID string,
 	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.cluster"), "(hostID=%s)", hostID).Entering()
 	defer tracer.Exiting()
 
+	xerr = instance.beginNodesChange()
+	if xerr != nil {
+		return xerr
+	}
+	defer instance.endNodesChange()
+
 	// make sure no other parallel actions interferes
 	instance.lock.Lock()
 	defer instance.lock.Unlock()
@@ -1779,6 +1831,85 @@ func (instance *Cluster) beingRemoved() fail.Error {
 	return nil
 }
 
+// upgradeActivity is the activity registered by the upgrade of a Cluster
+const upgradeActivity = "upgraded"
+
+// clusterActivities records the maintenance activity (upgrade, autoscaling, repair) running on each Cluster, indexed by
+// metadata bucket and Cluster name, to prevent them from acting on the same Cluster at the same time, and counts the
+// changes of the nodes (expand, shrink, node deletion, node pool resize, restoration) running on each Cluster, which
+// cannot run during an upgrade
+var clusterActivities = struct {
+	sync.Mutex
+	running map[string]string
+	changes map[string]uint
+}{running: map[string]string{}, changes: map[string]uint{}}
+
+// activityKey returns the key of the Cluster in clusterActivities
+func (instance *Cluster) activityKey() string {
+	return instance.GetService().GetMetadataBucket().Name + "/" + instance.GetName()
+}
+
+// beginActivity registers activity as the maintenance activity running on the Cluster
+// Returns fail.ErrNotAvailable if another one is already running
+func (instance *Cluster) beginActivity(activity string) fail.Error {
+	clusterActivities.Lock()
+	defer clusterActivities.Unlock()
+
+	key := instance.activityKey()
+	if running, ok := clusterActivities.running[key]; ok {
+		return fail.NotAvailableError("Cluster '%s' is being %s", instance.GetName(), running)
+	}
+	if activity == upgradeActivity && clusterActivities.changes[key] > 0 {
+		return fail.NotAvailableError("the nodes of Cluster '%s' are being changed", instance.GetName())
+	}
+	clusterActivities.running[key] = activity
+	return nil
+}
+
+// endActivity unregisters the maintenance activity running on the Cluster
+func (instance *Cluster) endActivity() {
+	clusterActivities.Lock()
+	defer clusterActivities.Unlock()
+
+	delete(clusterActivities.running, instance.activityKey())
+}
+
+// beginNodesChange registers a change of the nodes of the Cluster, preventing an upgrade from beginning until endNodesChange is called
+// Returns fail.ErrNotAvailable if the Cluster is being upgraded, or if its last upgrade has been interrupted
+// Must be called without holding instance.lock
+func (instance *Cluster) beginNodesChange() fail.Error {
+	state, xerr := instance.GetState()
+	if xerr != nil {
+		return xerr
+	}
+	if state == clusterstate.Upgrading {
+		return fail.NotAvailableError("the last upgrade of Cluster '%s' has been interrupted, resume it with 'safescale cluster upgrade --resume %s'", instance.GetName(), instance.GetName())
+	}
+
+	clusterActivities.Lock()
+	defer clusterActivities.Unlock()
+
+	key := instance.activityKey()
+	if clusterActivities.running[key] == upgradeActivity {
+		return fail.NotAvailableError("Cluster '%s' is being %s", instance.GetName(), upgradeActivity)
+	}
+	clusterActivities.changes[key]++
+	return nil
+}
+
+// endNodesChange unregisters a change of the nodes of the Cluster
+func (instance *Cluster) endNodesChange() {
+	clusterActivities.Lock()
+	defer clusterActivities.Unlock()
+
+	key := instance.activityKey()
+	if clusterActivities.changes[key] <= 1 {
+		delete(clusterActivities.changes, key)
+		return
+	}
+	clusterActivities.changes[key]--
+}
+
 // ListNodeNames lists the names of the nodes in the Cluster
 func (instance *Cluster) ListNodeNames(ctx context.Context) (list data.IndexedListOfStrings, xerr fail.Error) {
 	defer fail.OnPanic(&xerr)
@@ -2283,8 +2414,9 @@ func (instance *Cluster) deleteNode(ctx context.Context, node *propertiesv3.Clus
 	}
 
 	// Identify the node to delete and remove it preventively from metadata
+	var poolName string
 	xerr = instance.Alter(func(clonable data.Clonable, props *serialize.JSONProperties) fail.Error {
-		return props.Alter(clusterproperty.NodesV3, func(clonable data.Clonable) fail.Error {
+		innerXErr := props.Alter(clusterproperty.NodesV3, func(clonable data.Clonable) fail.Error {
 			nodesV3, ok := clonable.(*propertiesv3.ClusterNodes)
 			if !ok {
 				return fail.InconsistentError("'*propertiesv3.ClusterNodes' expected, '%s' provided", reflect.TypeOf(clonable).String())
@@ -2302,6 +2434,20 @@ func (instance *Cluster) deleteNode(ctx context.Context, node *propertiesv3.Clus
 			}
 			delete(nodesV3.PrivateNodeByID, node.ID)
 			delete(nodesV3.PrivateNodeByName, node.Name)
+			delete(nodesV3.Health, node.NumericalID)
+			return nil
+		})
+		if innerXErr != nil {
+			return innerXErr
+		}
+
+		return props.Alter(clusterproperty.NodePoolsV1, func(clonable data.Clonable) fail.Error {
+			poolsV1, ok := clonable.(*propertiesv1.ClusterNodePools)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterNodePools' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			poolName = poolsV1.RemoveNode(node.NumericalID)
 			return nil
 		})
 	})
@@ -2315,7 +2461,7 @@ func (instance *Cluster) deleteNode(ctx context.Context, node *propertiesv3.Clus
 		xerr = debug.InjectPlannedFail(xerr)
 		if xerr != nil {
 			derr := instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
-				return props.Alter(clusterproperty.NodesV3, func(clonable data.Clonable) fail.Error {
+				innerXErr := props.Alter(clusterproperty.NodesV3, func(clonable data.Clonable) fail.Error {
 					nodesV3, ok := clonable.(*propertiesv3.ClusterNodes)
 					if !ok {
 						return fail.InconsistentError("'*propertiesv3.ClusterNodes' expected, '%s' provided", reflect.TypeOf(clonable).String())
@@ -2331,6 +2477,21 @@ func (instance *Cluster) deleteNode(ctx context.Context, node *propertiesv3.Clus
 					nodesV3.ByNumericalID[node.NumericalID] = node
 					return nil
 				})
+				if innerXErr != nil || poolName == "" {
+					return innerXErr
+				}
+
+				return props.Alter(clusterproperty.NodePoolsV1, func(clonable data.Clonable) fail.Error {
+					poolsV1, ok := clonable.(*propertiesv1.ClusterNodePools)
+					if !ok {
+						return fail.InconsistentError("'*propertiesv1.ClusterNodePools' expected, '%s' provided", reflect.TypeOf(clonable).String())
+					}
+
+					if pool, ok := poolsV1.ByName[poolName]; ok {
+						pool.Nodes = append(pool.Nodes, node.NumericalID)
+					}
+					return nil
+				})
 			})
 			if derr != nil {
 				logrus.Errorf("failed to restore node ownership in Cluster")
@@ -2346,12 +2507,20 @@ func (instance *Cluster) deleteNode(ctx context.Context, node *propertiesv3.Clus
 		switch xerr.(type) {
 		case *fail.ErrNotFound:
 			// Host already deleted, consider as a success, continue
+			instance.forgetNode(ctx, node.Name, master)
 		default:
 			return xerr
 		}
 	} else {
-		// host still exists, leave it from Cluster, if master is not null
-		if master != nil && !master.IsNull() {
+		// host still exists; if it is not running, it cannot leave the Cluster by itself
+		running := true
+		if state, innerXErr := hostInstance.ForceGetState(ctx); innerXErr != nil || state != hoststate.Started {
+			running = false
+			instance.forgetNode(ctx, node.Name, master)
+		}
+
+		// leave it from Cluster, if master is not null
+		if running && master != nil && !master.IsNull() {
 			xerr = instance.leaveNodesFromList(ctx, []resources.Host{hostInstance}, master)
 			xerr = debug.InjectPlannedFail(xerr)
 			if xerr != nil {
@@ -2383,6 +2552,19 @@ func (instance *Cluster) deleteNode(ctx context.Context, node *propertiesv3.Clus
 	return nil
 }
 
+// forgetNode removes a node that cannot leave the Cluster by itself from the flavor, if the flavor supports it
+// Failure is only logged, the node being deleted anyway
+func (instance *Cluster) forgetNode(ctx context.Context, nodeName string, master *Host) {
+	if instance.makers.ForgetNode == nil || nodeName == "" || master == nil || master.IsNull() {
+		return
+	}
+
+	xerr := instance.makers.ForgetNode(ctx, instance, nodeName, master)
+	if xerr != nil {
+		logrus.Warnf("[cluster %s] failed to remove node '%s' from the flavor: %v", instance.GetName(), nodeName, xerr)
+	}
+}
+
 // Delete deletes the Cluster
 func (instance *Cluster) Delete(ctx context.Context, force bool) (xerr fail.Error) {
 	defer fail.OnPanic(&xerr)
@@ -2495,6 +2677,7 @@ func (instance *Cluster) delete(ctx context.Context) (xerr fail.Error) {
 
 	masterCount, nodeCount := len(masters), len(nodes)
 	if masterCount+nodeCount > 0 {
+		server.ReportProgress(ctx, server.ProgressEvent{Phase: "hosts", Step: fmt.Sprintf("deleting %d masters and %d nodes", masterCount, nodeCount)})
 		tg, xerr := concurrency.NewTaskGroupWithParent(task, concurrency.InheritParentIDOption)
 		xerr = debug.InjectPlannedFail(xerr)
 		if xerr != nil {
@@ -2609,6 +2792,7 @@ func (instance *Cluster) delete(ctx context.Context) (xerr fail.Error) {
 	}
 
 	// --- Deletes the Network, Subnet and gateway ---
+	server.ReportProgress(ctx, server.ProgressEvent{Phase: "network", Step: "deleting Network, Subnet and gateways", Percent: 60})
 	networkInstance, deleteNetwork, subnetInstance, xerr := instance.extractNetworkingInfo()
 	xerr = debug.InjectPlannedFail(xerr)
 	if xerr != nil {
@@ -2706,7 +2890,14 @@ func (instance *Cluster) delete(ctx context.Context) (xerr fail.Error) {
 	}
 
 	// --- Delete metadata ---
-	return instance.MetadataCore.Delete()
+	server.ReportProgress(ctx, server.ProgressEvent{Phase: "metadata", Step: "deleting Cluster metadata", Percent: 90})
+	xerr = instance.MetadataCore.Delete()
+	if xerr != nil {
+		return xerr
+	}
+
+	server.ReportProgress(ctx, server.ProgressEvent{Phase: "done", Step: "Cluster deleted", Percent: 100})
+	return nil
 }
 
 // extractNetworkingInfo returns the ID of the network from properties, taking care of ascending compatibility
@@ -2804,27 +2995,31 @@ func (instance *Cluster) configureCluster(ctx context.Context) (ferr fail.Error)
 		}
 	}()
 
-	// Install reverseproxy feature on Cluster (gateways)
-	xerr = instance.installReverseProxy(ctx)
-	xerr = debug.InjectPlannedFail(xerr)
-	if xerr != nil {
-		return xerr
-	}
+	xerr = instance.runCreationStep(creationStepFeatures, func() fail.Error {
+		// Install reverseproxy feature on Cluster (gateways)
+		xerr := instance.installReverseProxy(ctx)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return xerr
+		}
 
-	// Install remotedesktop feature on Cluster (all masters)
-	xerr = instance.installRemoteDesktop(ctx)
-	xerr = debug.InjectPlannedFail(xerr)
+		// Install remotedesktop feature on Cluster (all masters)
+		xerr = instance.installRemoteDesktop(ctx)
+		return debug.InjectPlannedFail(xerr)
+	})
 	if xerr != nil {
 		return xerr
 	}
 
 	// configure what has to be done Cluster-wide
-	if instance.makers.ConfigureCluster != nil {
-		return instance.makers.ConfigureCluster(ctx, instance)
-	}
+	return instance.runCreationStep(creationStepConfiguration, func() fail.Error {
+		if instance.makers.ConfigureCluster != nil {
+			return instance.makers.ConfigureCluster(ctx, instance)
+		}
 
-	// Not finding a callback isn't an error, so return nil in this case
-	return nil
+		// Not finding a callback isn't an error, so return nil in this case
+		return nil
+	})
 }
 
 func (instance *Cluster) determineRequiredNodes() (uint, uint, uint, fail.Error) {
@@ -2943,7 +3138,7 @@ func (instance *Cluster) joinNodesFromList(ctx context.Context, nodes []*propert
 
 	// Joins to Cluster is done sequentially, experience shows too many join at the same time
 	// may fail (depending of the Cluster Flavor)
-	if instance.makers.JoinMasterToCluster != nil {
+	if instance.makers.JoinNodeToCluster != nil {
 		for _, v := range nodes {
 			hostInstance, xerr := LoadHost(instance.GetService(), v.ID)
 			xerr = debug.InjectPlannedFail(xerr)
@@ -3065,7 +3260,12 @@ func (instance *Cluster) ToProtocol() (_ *protocol.ClusterResponse, xerr fail.Er
 		}
 		out.Identity = converters.ClusterIdentityFromAbstractToProtocol(*ci)
 
-		innerXErr := props.Inspect(clusterproperty.ControlPlaneV1, func(clonable data.Clonable) fail.Error {
+		var innerXErr fail.Error
+		if out.Labels, innerXErr = labelsFromProperties(props, clusterproperty.LabelsV1); innerXErr != nil {
+			return innerXErr
+		}
+
+		innerXErr = props.Inspect(clusterproperty.ControlPlaneV1, func(clonable data.Clonable) fail.Error {
 			controlplaneV1, ok := clonable.(*propertiesv1.ClusterControlplane)
 			if !ok {
 				return fail.InconsistentError("'*propertiesv1.ClusterControlplane' expected, '%s' provided", reflect.TypeOf(clonable).String())
@@ -3202,6 +3402,12 @@ func (instance *Cluster) Shrink(ctx context.Context, count uint) (_ []*propertie
 		return emptySlice, fail.AbortedError(nil, "aborted")
 	}
 
+	xerr = instance.beginNodesChange()
+	if xerr != nil {
+		return emptySlice, xerr
+	}
+	defer instance.endNodesChange()
+
 	// make sure no other parallel actions interferes
 	instance.lock.Lock()
 	defer instance.lock.Unlock()
@@ -3218,20 +3424,45 @@ func (instance *Cluster) Shrink(ctx context.Context, count uint) (_ []*propertie
 		toRemove     []uint
 	)
 	xerr = instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		// Nodes belonging to a node pool are not concerned by shrink (cf. ResizeNodePool)
+		var poolsV1 *propertiesv1.ClusterNodePools
+		innerXErr := props.Inspect(clusterproperty.NodePoolsV1, func(clonable data.Clonable) fail.Error {
+			var ok bool
+			poolsV1, ok = clonable.(*propertiesv1.ClusterNodePools)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterNodePools' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+			return nil
+		})
+		if innerXErr != nil {
+			return innerXErr
+		}
+
 		return props.Alter(clusterproperty.NodesV3, func(clonable data.Clonable) (innerXErr fail.Error) {
 			nodesV3, ok := clonable.(*propertiesv3.ClusterNodes)
 			if !ok {
 				return fail.InconsistentError("'*propertiesv3.ClusterNodes' expected, '%s' provided", reflect.TypeOf(clonable).String())
 			}
 
-			length := uint(len(nodesV3.PrivateNodes))
+			var general []uint
+			for _, v := range nodesV3.PrivateNodes {
+				if poolsV1.PoolOf(v) == "" {
+					general = append(general, v)
+				}
+			}
+			length := uint(len(general))
 			if length < count {
 				return fail.InvalidRequestError("cannot shrink by %d node%s, only %d node%s available", count, strprocess.Plural(count), length, strprocess.Plural(length))
 			}
 
-			first := length - count
-			toRemove = nodesV3.PrivateNodes[first:]
-			nodesV3.PrivateNodes = nodesV3.PrivateNodes[:first]
+			toRemove = general[length-count:]
+			remaining := make([]uint, 0, len(nodesV3.PrivateNodes)-len(toRemove))
+			for _, v := range nodesV3.PrivateNodes {
+				if found, _ := containsClusterNode(toRemove, v); !found {
+					remaining = append(remaining, v)
+				}
+			}
+			nodesV3.PrivateNodes = remaining
 			for _, v := range toRemove {
 				if node, ok := nodesV3.ByNumericalID[v]; ok {
 					removedNodes = append(removedNodes, node)
@@ -0,0 +1,602 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/resources"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterflavor"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterproperty"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterstate"
+	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
+	propertiesv3 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v3"
+	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/outputs"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/data/serialize"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+const (
+	// ScaleUpAction is the action of a scale decision adding nodes
+	ScaleUpAction = "expand"
+	// ScaleDownAction is the action of a scale decision removing nodes
+	ScaleDownAction = "shrink"
+
+	// nodeLoadCommand prints the count of CPU, the load average over 1 minute, the total and the available memory of a host
+	nodeLoadCommand = `echo $(nproc) $(cut -d' ' -f1 /proc/loadavg) $(awk '/^MemTotal:/ {t=$2} /^MemAvailable:/ {a=$2} END {print t, a}' /proc/meminfo)`
+	// kubectlTopNodesCommand prints the CPU and memory usage of Kubernetes nodes, as reported by metrics-server
+	kubectlTopNodesCommand = "sudo -u cladm -i kubectl top nodes --no-headers"
+)
+
+// nodeLoad contains the CPU and memory usage of a node, in percent
+type nodeLoad struct {
+	cpu    float64
+	memory float64
+}
+
+// validateAutoscaling checks the content of autoscaling settings
+func validateAutoscaling(settings *propertiesv1.ClusterAutoscaling) fail.Error {
+	if settings.MinNodes == 0 {
+		return fail.InvalidRequestError("invalid autoscaling settings: min nodes must be at least 1")
+	}
+	if settings.MaxNodes < settings.MinNodes {
+		return fail.InvalidRequestError("invalid autoscaling settings: max nodes (%d) cannot be lower than min nodes (%d)", settings.MaxNodes, settings.MinNodes)
+	}
+	if settings.ScaleUpThreshold <= 0 || settings.ScaleUpThreshold > 100 {
+		return fail.InvalidRequestError("invalid autoscaling settings: scale up threshold must be in ]0, 100]")
+	}
+	if settings.ScaleDownThreshold < 0 || settings.ScaleDownThreshold >= settings.ScaleUpThreshold {
+		return fail.InvalidRequestError("invalid autoscaling settings: scale down threshold must be in [0, %v[", settings.ScaleUpThreshold)
+	}
+	if settings.Cooldown < 0 {
+		return fail.InvalidRequestError("invalid autoscaling settings: cooldown cannot be negative")
+	}
+	return nil
+}
+
+// decideScaling decides, from autoscaling settings and the count and load of the general nodes, if nodes have to be added or removed
+// Returns nil if nothing has to be done
+func decideScaling(settings *propertiesv1.ClusterAutoscaling, nodes uint, load float64, now time.Time) *propertiesv1.ClusterScaleDecision {
+	if !settings.Enabled {
+		return nil
+	}
+	if !settings.LastScaleTime.IsZero() && now.Sub(settings.LastScaleTime) < settings.Cooldown {
+		return nil
+	}
+
+	step := settings.Step
+	if step == 0 {
+		step = 1
+	}
+	decision := &propertiesv1.ClusterScaleDecision{Time: now, Nodes: nodes, Load: load}
+	switch {
+	case nodes < settings.MinNodes:
+		decision.Action, decision.Count = ScaleUpAction, settings.MinNodes-nodes
+		decision.Reason = "count of nodes under the minimum"
+	case nodes > settings.MaxNodes:
+		decision.Action, decision.Count = ScaleDownAction, nodes-settings.MaxNodes
+		decision.Reason = "count of nodes over the maximum"
+	case load > settings.ScaleUpThreshold && nodes < settings.MaxNodes:
+		decision.Action, decision.Count = ScaleUpAction, minUint(step, settings.MaxNodes-nodes)
+		decision.Reason = "load over scale up threshold"
+	case load < settings.ScaleDownThreshold && nodes > settings.MinNodes:
+		decision.Action, decision.Count = ScaleDownAction, minUint(step, nodes-settings.MinNodes)
+		decision.Reason = "load under scale down threshold"
+	default:
+		return nil
+	}
+	return decision
+}
+
+func minUint(a, b uint) uint {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// parseNodeLoad converts the output of nodeLoadCommand to nodeLoad
+func parseNodeLoad(stdout string) (nodeLoad, fail.Error) {
+	fields := strings.Fields(stdout)
+	if len(fields) != 4 {
+		return nodeLoad{}, fail.SyntaxError("unexpected node load output '%s'", strings.TrimSpace(stdout))
+	}
+
+	values := make([]float64, 0, len(fields))
+	for _, v := range fields {
+		value, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nodeLoad{}, fail.SyntaxError("unexpected node load output '%s'", strings.TrimSpace(stdout))
+		}
+		values = append(values, value)
+	}
+	cpus, loadAverage, total, available := values[0], values[1], values[2], values[3]
+	if cpus <= 0 || total <= 0 {
+		return nodeLoad{}, fail.SyntaxError("unexpected node load output '%s'", strings.TrimSpace(stdout))
+	}
+
+	return nodeLoad{
+		cpu:    loadAverage / cpus * 100,
+		memory: (total - available) / total * 100,
+	}, nil
+}
+
+// parseKubectlTopNodes converts the output of kubectlTopNodesCommand to nodeLoad indexed by node name
+// Nodes without metrics yet are ignored
+func parseKubectlTopNodes(stdout string) (map[string]nodeLoad, fail.Error) {
+	out := map[string]nodeLoad{}
+	for _, line := range strings.Split(stdout, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 5 {
+			return nil, fail.SyntaxError("unexpected 'kubectl top nodes' output line '%s'", line)
+		}
+
+		cpu, err := strconv.ParseFloat(strings.TrimSuffix(fields[2], "%"), 64)
+		if err != nil {
+			continue
+		}
+		memory, err := strconv.ParseFloat(strings.TrimSuffix(fields[4], "%"), 64)
+		if err != nil {
+			continue
+		}
+		out[fields[0]] = nodeLoad{cpu: cpu, memory: memory}
+	}
+	return out, nil
+}
+
+// clusterLoad returns the load of a set of nodes: the highest of the average CPU usage and of the average memory usage
+func clusterLoad(loads []nodeLoad) float64 {
+	if len(loads) == 0 {
+		return 0
+	}
+
+	var cpu, memory float64
+	for _, v := range loads {
+		cpu += v.cpu
+		memory += v.memory
+	}
+	return math.Max(cpu, memory) / float64(len(loads))
+}
+
+// GetAutoscaling returns the autoscaling settings of the Cluster, with the last scale decisions
+func (instance *Cluster) GetAutoscaling(ctx context.Context) (_ *propertiesv1.ClusterAutoscaling, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	instance.lock.RLock()
+	defer instance.lock.RUnlock()
+
+	var settings *propertiesv1.ClusterAutoscaling
+	xerr = instance.Review(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Inspect(clusterproperty.AutoscalingV1, func(clonable data.Clonable) fail.Error {
+			autoscalingV1, ok := clonable.(*propertiesv1.ClusterAutoscaling)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterAutoscaling' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			settings = autoscalingV1.Clone().(*propertiesv1.ClusterAutoscaling)
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return settings, nil
+}
+
+// SetAutoscaling replaces the autoscaling settings of the Cluster; the history of scale decisions is kept
+func (instance *Cluster) SetAutoscaling(ctx context.Context, settings propertiesv1.ClusterAutoscaling) (_ *propertiesv1.ClusterAutoscaling, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if settings.Step == 0 {
+		settings.Step = 1
+	}
+	if settings.Enabled {
+		xerr = validateAutoscaling(&settings)
+		if xerr != nil {
+			return nil, xerr
+		}
+	}
+
+	// make sure no other parallel actions interferes
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	xerr = instance.beingRemoved()
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	var out *propertiesv1.ClusterAutoscaling
+	xerr = instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Alter(clusterproperty.AutoscalingV1, func(clonable data.Clonable) fail.Error {
+			autoscalingV1, ok := clonable.(*propertiesv1.ClusterAutoscaling)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterAutoscaling' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			settings.LastScaleTime = autoscalingV1.LastScaleTime
+			settings.Decisions = autoscalingV1.Decisions
+			_ = autoscalingV1.Replace(&settings)
+			out = autoscalingV1.Clone().(*propertiesv1.ClusterAutoscaling)
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return out, nil
+}
+
+// Autoscale collects the load of the general nodes of the Cluster then, if autoscaling is enabled and the load or the
+// bounds require it, expands or shrinks the Cluster.
+// The decision taken is recorded in Cluster metadata and returned; returns nil if nothing has been done
+func (instance *Cluster) Autoscale(ctx context.Context) (_ *propertiesv1.ClusterScaleDecision, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	tracer := debug.NewTracer(nil, tracing.ShouldTrace("resources.cluster"), "('%s')", instance.GetName()).Entering()
+	defer tracer.Exiting()
+
+	settings, xerr := instance.GetAutoscaling(ctx)
+	if xerr != nil {
+		return nil, xerr
+	}
+	if !settings.Enabled {
+		return nil, nil
+	}
+
+	state, nodes, xerr := instance.inspectAutoscalingTarget()
+	if xerr != nil {
+		return nil, xerr
+	}
+	if state != clusterstate.Nominal && state != clusterstate.Degraded {
+		logrus.Debugf("[cluster %s] not autoscaled, state is '%s'", instance.GetName(), state.String())
+		return nil, nil
+	}
+
+	load, xerr := instance.collectLoad(ctx, nodes)
+	if xerr != nil {
+		return nil, fail.Wrap(xerr, "failed to collect the load of the nodes of Cluster '%s'", instance.GetName())
+	}
+
+	decision := decideScaling(settings, uint(len(nodes)), load, time.Now())
+	if decision == nil {
+		return nil, nil
+	}
+
+	// an upgrade or a repair of the Cluster may have begun while the load was collected
+	xerr = instance.beginActivity("autoscaled")
+	if xerr != nil {
+		logrus.Debugf("[cluster %s] not autoscaled: %v", instance.GetName(), xerr)
+		return nil, nil
+	}
+	defer instance.endActivity()
+
+	logrus.Infof("[cluster %s] autoscaling: %s by %d node(s), %s (%d node(s), load %.1f%%)", instance.GetName(), decision.Action, decision.Count, decision.Reason, decision.Nodes, decision.Load)
+	switch decision.Action {
+	case ScaleUpAction:
+		var hosts []resources.Host
+		hosts, xerr = instance.AddNodes(ctx, decision.Count, abstract.HostSizingRequirements{}, false)
+		for _, v := range hosts {
+			v.Released()
+		}
+	case ScaleDownAction:
+		_, xerr = instance.Shrink(ctx, decision.Count)
+	}
+	if xerr != nil {
+		decision.Error = xerr.Error()
+	}
+
+	derr := instance.recordScaleDecision(*decision)
+	if derr != nil {
+		if xerr != nil {
+			_ = xerr.AddConsequence(derr)
+		} else {
+			xerr = derr
+		}
+	}
+	return decision, xerr
+}
+
+// inspectAutoscalingTarget returns the last known state of the Cluster and its general nodes, without refreshing the state
+func (instance *Cluster) inspectAutoscalingTarget() (state clusterstate.Enum, nodes []*propertiesv3.ClusterNode, xerr fail.Error) {
+	instance.lock.RLock()
+	defer instance.lock.RUnlock()
+
+	xerr = instance.Review(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		innerXErr := props.Inspect(clusterproperty.StateV1, func(clonable data.Clonable) fail.Error {
+			stateV1, ok := clonable.(*propertiesv1.ClusterState)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterState' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			state = stateV1.State
+			return nil
+		})
+		if innerXErr != nil {
+			return innerXErr
+		}
+
+		var poolsV1 *propertiesv1.ClusterNodePools
+		innerXErr = props.Inspect(clusterproperty.NodePoolsV1, func(clonable data.Clonable) fail.Error {
+			var ok bool
+			poolsV1, ok = clonable.(*propertiesv1.ClusterNodePools)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterNodePools' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+			return nil
+		})
+		if innerXErr != nil {
+			return innerXErr
+		}
+
+		return props.Inspect(clusterproperty.NodesV3, func(clonable data.Clonable) fail.Error {
+			nodesV3, ok := clonable.(*propertiesv3.ClusterNodes)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv3.ClusterNodes' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			for _, v := range nodesV3.PrivateNodes {
+				if node, ok := nodesV3.ByNumericalID[v]; ok && poolsV1.PoolOf(v) == "" {
+					item := *node
+					nodes = append(nodes, &item)
+				}
+			}
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return clusterstate.Unknown, nil, xerr
+	}
+
+	return state, nodes, nil
+}
+
+// collectLoad returns the load of the nodes, in percent
+// For flavors running Kubernetes, the metrics are requested to Kubernetes; if they are not available, they are collected on each node using SSH
+func (instance *Cluster) collectLoad(ctx context.Context, nodes []*propertiesv3.ClusterNode) (float64, fail.Error) {
+	if len(nodes) == 0 {
+		return 0, nil
+	}
+
+	if flavor, xerr := instance.GetFlavor(); xerr == nil && (flavor == clusterflavor.K8S || flavor == clusterflavor.K3S) {
+		loads, xerr := instance.collectKubernetesLoad(ctx, nodes)
+		if xerr == nil {
+			return clusterLoad(loads), nil
+		}
+		logrus.Debugf("[cluster %s] failed to get metrics from Kubernetes, collecting them using SSH: %v", instance.GetName(), xerr)
+	}
+
+	var (
+		mutex  sync.Mutex
+		wg     sync.WaitGroup
+		loads  []nodeLoad
+		errors []error
+	)
+	for _, v := range nodes {
+		wg.Add(1)
+		go func(node *propertiesv3.ClusterNode) {
+			defer wg.Done()
+
+			load, xerr := instance.collectNodeLoad(ctx, node)
+			mutex.Lock()
+			defer mutex.Unlock()
+			if xerr != nil {
+				errors = append(errors, fail.Wrap(xerr, "failed to collect load of node '%s'", node.Name))
+				return
+			}
+			loads = append(loads, load)
+		}(v)
+	}
+	wg.Wait()
+
+	// Unreachable nodes are ignored as long as the load of some nodes is known
+	if len(loads) == 0 {
+		return 0, fail.NewErrorList(errors)
+	}
+	for _, v := range errors {
+		logrus.Warnf("[cluster %s] %v", instance.GetName(), v)
+	}
+	return clusterLoad(loads), nil
+}
+
+// collectKubernetesLoad returns the load of the nodes as reported by 'kubectl top nodes'
+func (instance *Cluster) collectKubernetesLoad(ctx context.Context, nodes []*propertiesv3.ClusterNode) ([]nodeLoad, fail.Error) {
+	instance.lock.RLock()
+	master, xerr := instance.UnsafeFindAvailableMaster(ctx)
+	instance.lock.RUnlock()
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer master.Released()
+
+	retcode, stdout, stderr, xerr := master.Run(ctx, kubectlTopNodesCommand, outputs.COLLECT, temporal.GetConnectionTimeout(), temporal.GetExecutionTimeout())
+	if xerr != nil {
+		return nil, xerr
+	}
+	if retcode != 0 {
+		xerr := fail.ExecutionError(nil, "failed to get metrics of nodes")
+		_ = xerr.Annotate("retcode", retcode)
+		_ = xerr.Annotate("stdout", stdout)
+		_ = xerr.Annotate("stderr", stderr)
+		return nil, xerr
+	}
+
+	byName, xerr := parseKubectlTopNodes(stdout)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	loads := make([]nodeLoad, 0, len(nodes))
+	for _, v := range nodes {
+		if load, ok := byName[v.Name]; ok {
+			loads = append(loads, load)
+		}
+	}
+	if len(loads) == 0 {
+		return nil, fail.NotFoundError("no metrics available for the nodes")
+	}
+	return loads, nil
+}
+
+// collectNodeLoad returns the load of a node, collected using SSH
+func (instance *Cluster) collectNodeLoad(ctx context.Context, node *propertiesv3.ClusterNode) (nodeLoad, fail.Error) {
+	hostInstance, xerr := LoadHost(instance.GetService(), node.ID)
+	if xerr != nil {
+		return nodeLoad{}, xerr
+	}
+	defer hostInstance.Released()
+
+	retcode, stdout, stderr, xerr := hostInstance.Run(ctx, nodeLoadCommand, outputs.COLLECT, temporal.GetConnectionTimeout(), temporal.GetExecutionTimeout())
+	if xerr != nil {
+		return nodeLoad{}, xerr
+	}
+	if retcode != 0 {
+		xerr := fail.ExecutionError(nil, "failed to get load")
+		_ = xerr.Annotate("retcode", retcode)
+		_ = xerr.Annotate("stdout", stdout)
+		_ = xerr.Annotate("stderr", stderr)
+		return nodeLoad{}, xerr
+	}
+
+	return parseNodeLoad(stdout)
+}
+
+// recordScaleDecision records the scale decision in Cluster metadata
+func (instance *Cluster) recordScaleDecision(decision propertiesv1.ClusterScaleDecision) fail.Error {
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	xerr := instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Alter(clusterproperty.AutoscalingV1, func(clonable data.Clonable) fail.Error {
+			autoscalingV1, ok := clonable.(*propertiesv1.ClusterAutoscaling)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterAutoscaling' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			autoscalingV1.LastScaleTime = decision.Time
+			autoscalingV1.Record(decision)
+			return nil
+		})
+	})
+	return debug.InjectPlannedFail(xerr)
+}
+
+// AutoscaleClusters runs Autoscale on the Clusters of the tenant, in parallel
+// Returns when all the scale actions have ended
+func AutoscaleClusters(ctx context.Context, svc iaas.Service) fail.Error {
+	if ctx == nil {
+		return fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if svc == nil {
+		return fail.InvalidParameterCannotBeNilError("svc")
+	}
+
+	browser, xerr := NewCluster(svc)
+	if xerr != nil {
+		return xerr
+	}
+
+	var names []string
+	xerr = browser.Browse(ctx, func(identity *abstract.ClusterIdentity) fail.Error {
+		names = append(names, identity.Name)
+		return nil
+	})
+	if xerr != nil {
+		return xerr
+	}
+
+	var (
+		mutex  sync.Mutex
+		wg     sync.WaitGroup
+		errors []error
+	)
+	for _, v := range names {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+
+			xerr := autoscaleCluster(ctx, svc, name)
+			if xerr != nil {
+				mutex.Lock()
+				errors = append(errors, fail.Wrap(xerr, "failed to autoscale Cluster '%s'", name))
+				mutex.Unlock()
+			}
+		}(v)
+	}
+	wg.Wait()
+
+	if len(errors) > 0 {
+		return fail.NewErrorList(errors)
+	}
+	return nil
+}
+
+func autoscaleCluster(ctx context.Context, svc iaas.Service, name string) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	clusterInstance, xerr := LoadCluster(svc, name)
+	if xerr != nil {
+		return xerr
+	}
+	defer clusterInstance.Released()
+
+	_, xerr = clusterInstance.Autoscale(ctx)
+	return xerr
+}
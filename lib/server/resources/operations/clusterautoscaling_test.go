@@ -0,0 +1,163 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clustercomplexity"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterflavor"
+	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+func Test_validateAutoscaling(t *testing.T) {
+	valid := propertiesv1.ClusterAutoscaling{Enabled: true, MinNodes: 3, MaxNodes: 30, ScaleUpThreshold: 80, ScaleDownThreshold: 30, Cooldown: 5 * time.Minute}
+	assert.Nil(t, validateAutoscaling(&valid))
+
+	invalids := map[string]func(*propertiesv1.ClusterAutoscaling){
+		"no min":            func(s *propertiesv1.ClusterAutoscaling) { s.MinNodes = 0 },
+		"max under min":     func(s *propertiesv1.ClusterAutoscaling) { s.MaxNodes = 2 },
+		"no scale up":       func(s *propertiesv1.ClusterAutoscaling) { s.ScaleUpThreshold = 0 },
+		"scale up over 100": func(s *propertiesv1.ClusterAutoscaling) { s.ScaleUpThreshold = 120 },
+		"down over up":      func(s *propertiesv1.ClusterAutoscaling) { s.ScaleDownThreshold = 90 },
+		"negative cooldown": func(s *propertiesv1.ClusterAutoscaling) { s.Cooldown = -time.Second },
+	}
+	for name, alter := range invalids {
+		settings := *valid.Clone().(*propertiesv1.ClusterAutoscaling)
+		alter(&settings)
+		xerr := validateAutoscaling(&settings)
+		if assert.NotNil(t, xerr, name) {
+			assert.IsType(t, &fail.ErrInvalidRequest{}, xerr, name)
+		}
+	}
+}
+
+func Test_decideScaling(t *testing.T) {
+	now := time.Now()
+	settings := &propertiesv1.ClusterAutoscaling{Enabled: true, MinNodes: 3, MaxNodes: 30, ScaleUpThreshold: 80, ScaleDownThreshold: 30, Cooldown: 5 * time.Minute, Step: 4}
+
+	cases := []struct {
+		name   string
+		nodes  uint
+		load   float64
+		action string
+		count  uint
+	}{
+		{"under min", 1, 50, ScaleUpAction, 2},
+		{"over max", 32, 50, ScaleDownAction, 2},
+		{"overloaded", 10, 90, ScaleUpAction, 4},
+		{"overloaded near max", 28, 90, ScaleUpAction, 2},
+		{"overloaded at max", 30, 90, "", 0},
+		{"underloaded", 10, 10, ScaleDownAction, 4},
+		{"underloaded near min", 5, 10, ScaleDownAction, 2},
+		{"underloaded at min", 3, 10, "", 0},
+		{"in thresholds", 10, 50, "", 0},
+	}
+	for _, c := range cases {
+		decision := decideScaling(settings, c.nodes, c.load, now)
+		if c.action == "" {
+			assert.Nil(t, decision, c.name)
+			continue
+		}
+		if assert.NotNil(t, decision, c.name) {
+			assert.Equal(t, c.action, decision.Action, c.name)
+			assert.Equal(t, c.count, decision.Count, c.name)
+			assert.Equal(t, c.nodes, decision.Nodes, c.name)
+		}
+	}
+
+	settings.LastScaleTime = now.Add(-time.Minute)
+	assert.Nil(t, decideScaling(settings, 10, 90, now))
+	settings.LastScaleTime = now.Add(-10 * time.Minute)
+	assert.NotNil(t, decideScaling(settings, 10, 90, now))
+
+	settings.Enabled = false
+	assert.Nil(t, decideScaling(settings, 1, 90, now))
+}
+
+func Test_parseNodeLoad(t *testing.T) {
+	load, xerr := parseNodeLoad("4 3.00 8000000 2000000\n")
+	require.Nil(t, xerr)
+	assert.InDelta(t, 75, load.cpu, 0.001)
+	assert.InDelta(t, 75, load.memory, 0.001)
+
+	for _, v := range []string{"", "4 3.00 8000000", "0 3.00 8000000 2000000", "4 x 8000000 2000000"} {
+		_, xerr = parseNodeLoad(v)
+		assert.NotNil(t, xerr, v)
+	}
+}
+
+func Test_parseKubectlTopNodes(t *testing.T) {
+	out := `k8s-master-1   250m   12%   1024Mi   30%
+k8s-node-1     1900m  95%   3000Mi   75%
+k8s-node-2     <unknown>  <unknown>  <unknown>  <unknown>
+`
+	loads, xerr := parseKubectlTopNodes(out)
+	require.Nil(t, xerr)
+	require.Len(t, loads, 2)
+	assert.Equal(t, nodeLoad{cpu: 95, memory: 75}, loads["k8s-node-1"])
+
+	_, xerr = parseKubectlTopNodes("Error from server (ServiceUnavailable): the server is currently unable to handle the request")
+	assert.NotNil(t, xerr)
+}
+
+func Test_clusterLoad(t *testing.T) {
+	assert.Zero(t, clusterLoad(nil))
+	assert.InDelta(t, 60, clusterLoad([]nodeLoad{{cpu: 90, memory: 10}, {cpu: 30, memory: 20}}), 0.001)
+	assert.InDelta(t, 70, clusterLoad([]nodeLoad{{cpu: 10, memory: 80}, {cpu: 20, memory: 60}}), 0.001)
+}
+
+func Test_cluster_Autoscaling(t *testing.T) {
+	svc := getMemoryService(t)
+	ctx := context.Background()
+
+	instance, xerr := NewCluster(svc)
+	require.Nil(t, xerr)
+	req := abstract.ClusterRequest{Name: "autoscaled", Flavor: clusterflavor.BOH, Complexity: clustercomplexity.Small, InitialNodeCount: 1}
+	require.Nil(t, instance.firstLight(req))
+
+	settings, xerr := instance.GetAutoscaling(ctx)
+	require.Nil(t, xerr)
+	assert.False(t, settings.Enabled)
+
+	decision, xerr := instance.Autoscale(ctx)
+	require.Nil(t, xerr)
+	assert.Nil(t, decision)
+
+	_, xerr = instance.SetAutoscaling(ctx, propertiesv1.ClusterAutoscaling{Enabled: true, MinNodes: 3, MaxNodes: 2})
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrInvalidRequest{}, xerr)
+
+	require.Nil(t, instance.recordScaleDecision(propertiesv1.ClusterScaleDecision{Time: time.Now(), Action: ScaleUpAction, Count: 2}))
+	settings, xerr = instance.SetAutoscaling(ctx, propertiesv1.ClusterAutoscaling{Enabled: true, MinNodes: 3, MaxNodes: 30, ScaleUpThreshold: 80, ScaleDownThreshold: 30})
+	require.Nil(t, xerr)
+	assert.EqualValues(t, 1, settings.Step)
+	assert.False(t, settings.LastScaleTime.IsZero())
+	require.Len(t, settings.Decisions, 1)
+
+	settings, xerr = instance.GetAutoscaling(ctx)
+	require.Nil(t, xerr)
+	assert.True(t, settings.Enabled)
+	assert.EqualValues(t, 30, settings.MaxNodes)
+}
@@ -0,0 +1,866 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server"
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/resources"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterproperty"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterstate"
+	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
+	propertiesv2 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v2"
+	propertiesv3 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v3"
+	"github.com/CS-SI/SafeScale/lib/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/outputs"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/crypt"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/data/serialize"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+// Names of the objects of a backup of a Cluster, stored in the bucket under '<cluster name>/<backup id>/'
+// All of them are encrypted with the crypt key of the metadata of the tenant: they contain secrets (password of the admin
+// user and SSH keypair in metadata, parameters of the features, private keys of the certificate authorities, snapshot of etcd)
+const (
+	backupManifestObject     = "manifest.json"        // description of the backup, written last
+	backupMetadataObject     = "metadata.json"        // metadata of the Cluster when the backup has been saved
+	backupCertificatesObject = "certificates.tar.gz"  // certificate authority of SafeScale (feature certificateauthority)
+	backupControlPlaneObject = "control-plane.tar.gz" // state of the control plane, saved by the flavor (etcd and certificates for K8S)
+)
+
+// backupIDLayout is the layout of the time of creation used as id of a backup
+const backupIDLayout = "20060102T150405Z"
+
+// certificatesFolder is the folder of the certificate authority of SafeScale on the masters, relative to /
+var certificatesFolder = strings.TrimPrefix(utils.BaseFolder, "/") + "/etc/pki/ca"
+
+// clusterBackupManifest describes the content of a backup of a Cluster
+type clusterBackupManifest struct {
+	Cluster      string                       `json:"cluster"`
+	ID           string                       `json:"id"`
+	Flavor       string                       `json:"flavor"`
+	CreationTime time.Time                    `json:"creation_time"`
+	Masters      []string                     `json:"masters"`
+	Features     map[string]map[string]string `json:"features,omitempty"`  // parameters of the installed features, indexed by feature name
+	Objects      []string                     `json:"objects"`             // names of the objects of the backup, relative to the prefix of the backup
+	Encrypted    bool                         `json:"encrypted,omitempty"` // tells if the objects are encrypted (not set by older backups)
+}
+
+// has tells if the object 'name' is part of the backup
+func (m clusterBackupManifest) has(name string) bool {
+	for _, v := range m.Objects {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+// backupPrefix returns the prefix of the names of the objects of the backup 'id' of the Cluster 'clusterName'
+func backupPrefix(clusterName, id string) string {
+	return clusterName + "/" + id + "/"
+}
+
+// backupKey returns the key used to encrypt the backups of the Clusters of the tenant, the crypt key of its metadata
+// Returns fail.ErrInvalidRequest if the tenant has none, the backups being never saved in plaintext
+func backupKey(svc iaas.Service) (*crypt.Key, fail.Error) {
+	key, xerr := svc.GetMetadataKey()
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			return nil, fail.InvalidRequestError("a backup contains secrets and is encrypted with the crypt key of metadata; set 'CryptKey' in the 'metadata' section of the tenant")
+		default:
+			return nil, xerr
+		}
+	}
+	return key, nil
+}
+
+// writeBackupObject encrypts content with key, then writes it in the object 'objectName' of bucket 'bucketName'; returns
+// the size of the object
+func writeBackupObject(svc iaas.Service, bucketName, objectName string, content []byte, key *crypt.Key) (int64, fail.Error) {
+	ciphered, err := crypt.Encrypt(content, key)
+	if err != nil {
+		return 0, fail.Wrap(fail.ConvertError(err), "failed to encrypt object '%s'", objectName)
+	}
+
+	_, xerr := svc.WriteObject(bucketName, objectName, bytes.NewReader(ciphered), int64(len(ciphered)), nil)
+	if xerr != nil {
+		return 0, xerr
+	}
+	return int64(len(ciphered)), nil
+}
+
+// readBackupObject reads the object 'objectName' of bucket 'bucketName', then decrypts it with key if key is not nil
+func readBackupObject(svc iaas.Service, bucketName, objectName string, key *crypt.Key) ([]byte, fail.Error) {
+	var buffer bytes.Buffer
+	xerr := svc.ReadObject(bucketName, objectName, &buffer, 0, 0)
+	if xerr != nil {
+		return nil, xerr
+	}
+	if key == nil {
+		return buffer.Bytes(), nil
+	}
+
+	content, err := crypt.Decrypt(buffer.Bytes(), key)
+	if err != nil {
+		return nil, fail.Wrap(fail.ConvertError(err), "failed to decrypt object '%s'", objectName)
+	}
+	return content, nil
+}
+
+// defaultBackupBucket returns the name of the bucket used when none is given to save the backups of the Cluster 'clusterName'
+func defaultBackupBucket(clusterName string) string {
+	return strings.ToLower(clusterName) + "-backups"
+}
+
+// Backup saves in Object Storage the metadata of the Cluster, the parameters of its installed features, the certificate authority
+// of SafeScale and, if the flavor supports it, the state of the control plane (etcd and certificates of Kubernetes for K8S).
+// The backup is saved in bucket 'bucketName' (created if needed; '<cluster name>-backups' if empty) and recorded in Cluster metadata
+func (instance *Cluster) Backup(ctx context.Context, bucketName string) (_ *propertiesv1.ClusterBackup, ferr fail.Error) {
+	defer fail.OnPanic(&ferr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	task, xerr := taskFromContextOrVoid(ctx)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.cluster"), "('%s')", bucketName).Entering()
+	defer tracer.Exiting()
+	defer temporal.NewStopwatch().OnExitLogInfo(
+		fmt.Sprintf("Starting backup of Cluster '%s'...", instance.GetName()),
+		fmt.Sprintf("Ending backup of Cluster '%s'", instance.GetName()),
+	)()
+
+	flavor, xerr := instance.GetFlavor()
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	manifest, xerr := instance.prepareBackup()
+	if xerr != nil {
+		return nil, xerr
+	}
+	manifest.Flavor = flavor.String()
+	manifest.Encrypted = true
+
+	svc := instance.GetService()
+	key, xerr := backupKey(svc)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	// Metadata is serialized before saving anything, to be consistent with the manifest
+	metadata, xerr := instance.Serialize()
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	clusterName := instance.GetName()
+	if bucketName == "" {
+		bucketName = defaultBackupBucket(clusterName)
+	}
+	found, xerr := svc.FindBucket(bucketName)
+	if xerr != nil {
+		return nil, xerr
+	}
+	if !found {
+		if _, xerr = svc.CreateBucket(bucketName); xerr != nil {
+			return nil, fail.Wrap(xerr, "failed to create bucket '%s'", bucketName)
+		}
+	}
+
+	master, xerr := instance.FindAvailableMaster(ctx)
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer master.Released()
+
+	prefix := backupPrefix(clusterName, manifest.ID)
+	backup := &propertiesv1.ClusterBackup{ID: manifest.ID, Bucket: bucketName, CreationTime: manifest.CreationTime}
+	defer func() {
+		if ferr != nil {
+			for _, v := range backup.Objects {
+				if derr := svc.DeleteObject(bucketName, v); derr != nil {
+					_ = ferr.AddConsequence(fail.Wrap(derr, "cleaning up on failure, failed to delete object '%s' of bucket '%s'", v, bucketName))
+				}
+			}
+		}
+	}()
+	saved := func(name string, size int64) {
+		manifest.Objects = append(manifest.Objects, name)
+		backup.Objects = append(backup.Objects, prefix+name)
+		backup.Size += size
+	}
+
+	server.ReportProgress(ctx, server.ProgressEvent{Phase: "backup", Step: "saving metadata"})
+	size, xerr := writeBackupObject(svc, bucketName, prefix+backupMetadataObject, metadata, key)
+	if xerr != nil {
+		return nil, xerr
+	}
+	saved(backupMetadataObject, size)
+
+	server.ReportProgress(ctx, server.ProgressEvent{Phase: "backup", Step: "saving certificates", Host: master.GetName()})
+	retcode, _, _, xerr := master.Run(ctx, "sudo test -d /"+certificatesFolder, outputs.COLLECT, temporal.GetConnectionTimeout(), temporal.GetExecutionTimeout())
+	if xerr != nil {
+		return nil, xerr
+	}
+	if retcode == 0 {
+		remotePath := fmt.Sprintf("/tmp/safescale-backup-%s-%s", manifest.ID, backupCertificatesObject)
+		cmd := fmt.Sprintf("set -e; umask 077; sudo tar -C / -zcf %s %s; sudo chown $(id -u):$(id -g) %s", remotePath, certificatesFolder, remotePath)
+		xerr = runOnHost(ctx, master, cmd, "failed to archive certificates")
+		if xerr != nil {
+			return nil, xerr
+		}
+
+		size, xerr := uploadFromHost(ctx, master, remotePath, svc, bucketName, prefix+backupCertificatesObject, key)
+		if xerr != nil {
+			return nil, xerr
+		}
+		saved(backupCertificatesObject, size)
+	} else {
+		logrus.Debugf("[Cluster %s] no certificate authority found on master '%s', not saved", clusterName, master.GetName())
+	}
+
+	if instance.makers.SnapshotControlPlane != nil {
+		server.ReportProgress(ctx, server.ProgressEvent{Phase: "backup", Step: "saving control plane", Host: master.GetName()})
+		remotePath := fmt.Sprintf("/tmp/safescale-backup-%s-%s", manifest.ID, backupControlPlaneObject)
+		xerr = instance.makers.SnapshotControlPlane(ctx, instance, master, remotePath)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return nil, xerr
+		}
+
+		size, xerr := uploadFromHost(ctx, master, remotePath, svc, bucketName, prefix+backupControlPlaneObject, key)
+		if xerr != nil {
+			return nil, xerr
+		}
+		saved(backupControlPlaneObject, size)
+	}
+
+	// the manifest is written last: a backup without manifest is incomplete
+	content, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, fail.ConvertError(err)
+	}
+	size, xerr = writeBackupObject(svc, bucketName, prefix+backupManifestObject, content, key)
+	if xerr != nil {
+		return nil, xerr
+	}
+	saved(backupManifestObject, size)
+
+	xerr = instance.alterBackups(func(backupsV1 *propertiesv1.ClusterBackups) fail.Error {
+		backupsV1.Backups = append(backupsV1.Backups, backup)
+		return nil
+	})
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	server.ReportProgress(ctx, server.ProgressEvent{Phase: "done", Step: "cluster saved", Percent: 100})
+	return backup, nil
+}
+
+// prepareBackup checks the Cluster can be saved and returns the manifest of the backup, with the masters and features of the Cluster
+func (instance *Cluster) prepareBackup() (_ *clusterBackupManifest, xerr fail.Error) {
+	// make sure no other parallel actions interferes
+	instance.lock.RLock()
+	defer instance.lock.RUnlock()
+
+	xerr = instance.beingRemoved()
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	now := time.Now().UTC()
+	manifest := &clusterBackupManifest{
+		Cluster:      instance.GetName(),
+		ID:           now.Format(backupIDLayout),
+		CreationTime: now,
+		Masters:      []string{},
+		Features:     map[string]map[string]string{},
+		Objects:      []string{},
+	}
+	xerr = instance.Review(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		innerXErr := props.Inspect(clusterproperty.StateV1, func(clonable data.Clonable) fail.Error {
+			stateV1, ok := clonable.(*propertiesv1.ClusterState)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterState' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			if stateV1.State != clusterstate.Nominal && stateV1.State != clusterstate.Degraded {
+				return fail.InvalidRequestError("cannot save Cluster '%s' in state '%s'", instance.GetName(), stateV1.State.String())
+			}
+			return nil
+		})
+		if innerXErr != nil {
+			return innerXErr
+		}
+
+		innerXErr = props.Inspect(clusterproperty.NodesV3, func(clonable data.Clonable) fail.Error {
+			nodesV3, ok := clonable.(*propertiesv3.ClusterNodes)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv3.ClusterNodes' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			for _, v := range nodesV3.Masters {
+				if node, ok := nodesV3.ByNumericalID[v]; ok {
+					manifest.Masters = append(manifest.Masters, node.Name)
+				}
+			}
+			return nil
+		})
+		if innerXErr != nil {
+			return innerXErr
+		}
+
+		return props.Inspect(clusterproperty.FeaturesV1, func(clonable data.Clonable) fail.Error {
+			featuresV1, ok := clonable.(*propertiesv1.ClusterFeatures)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterFeatures' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			for k, v := range featuresV1.Installed {
+				params := make(map[string]string, len(v.Parameters))
+				for pk, pv := range v.Parameters {
+					params[pk] = pv
+				}
+				manifest.Features[k] = params
+			}
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return manifest, nil
+}
+
+// ListBackups returns the backups of the Cluster recorded in its metadata, and its last restoration
+func (instance *Cluster) ListBackups(ctx context.Context) (_ *propertiesv1.ClusterBackups, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	instance.lock.RLock()
+	defer instance.lock.RUnlock()
+
+	var out *propertiesv1.ClusterBackups
+	xerr = instance.Review(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Inspect(clusterproperty.BackupsV1, func(clonable data.Clonable) fail.Error {
+			backupsV1, ok := clonable.(*propertiesv1.ClusterBackups)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterBackups' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			out = backupsV1.Clone().(*propertiesv1.ClusterBackups)
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return out, nil
+}
+
+// alterBackups calls callback to update the backups recorded in Cluster metadata
+func (instance *Cluster) alterBackups(callback func(*propertiesv1.ClusterBackups) fail.Error) fail.Error {
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	return instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Alter(clusterproperty.BackupsV1, func(clonable data.Clonable) fail.Error {
+			backupsV1, ok := clonable.(*propertiesv1.ClusterBackups)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterBackups' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+			return callback(backupsV1)
+		})
+	})
+}
+
+// Restore rebuilds the control plane of the Cluster from the backup 'id' (the newest backup recorded in metadata if empty):
+// the masters lost (not found anymore) are created and configured again, the certificate authority of SafeScale is restored on
+// the masters, the features saved are added again with their parameters, then the flavor restores the state of its control plane
+// (etcd and certificates of Kubernetes for K8S).
+// bucketName is needed only if the backup is not recorded in Cluster metadata; the metadata saved in the backup is not
+// restored, and is kept in the bucket for reference
+func (instance *Cluster) Restore(ctx context.Context, bucketName, id string) (_ *propertiesv1.ClusterBackup, ferr fail.Error) {
+	defer fail.OnPanic(&ferr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	task, xerr := taskFromContextOrVoid(ctx)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.cluster"), "('%s', '%s')", bucketName, id).Entering()
+	defer tracer.Exiting()
+	defer temporal.NewStopwatch().OnExitLogInfo(
+		fmt.Sprintf("Starting restoration of Cluster '%s'...", instance.GetName()),
+		fmt.Sprintf("Ending restoration of Cluster '%s'", instance.GetName()),
+	)()
+
+	xerr = instance.beginNodesChange()
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer instance.endNodesChange()
+
+	instance.lock.RLock()
+	xerr = instance.beingRemoved()
+	instance.lock.RUnlock()
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	backup, xerr := instance.findBackup(bucketName, id)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	svc := instance.GetService()
+	clusterName := instance.GetName()
+	prefix := backupPrefix(clusterName, backup.ID)
+	manifest, xerr := readBackupManifest(svc, backup.Bucket, prefix)
+	if xerr != nil {
+		return nil, xerr
+	}
+	flavor, xerr := instance.GetFlavor()
+	if xerr != nil {
+		return nil, xerr
+	}
+	if manifest.Flavor != flavor.String() {
+		return nil, fail.InvalidRequestError("backup '%s' has been saved from a Cluster of flavor '%s', cannot restore it on Cluster '%s' of flavor '%s'", backup.ID, manifest.Flavor, clusterName, flavor.String())
+	}
+	var key *crypt.Key
+	if manifest.Encrypted {
+		key, xerr = backupKey(svc)
+		if xerr != nil {
+			return nil, xerr
+		}
+	}
+
+	server.ReportProgress(ctx, server.ProgressEvent{Phase: "restore", Step: "rebuilding lost masters"})
+	masters, xerr := instance.rebuildLostMasters(ctx, task)
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer func() {
+		for _, v := range masters {
+			v.Released()
+		}
+	}()
+	if len(masters) == 0 {
+		return nil, fail.InconsistentError("Cluster '%s' has no master", clusterName)
+	}
+
+	if manifest.has(backupCertificatesObject) {
+		server.ReportProgress(ctx, server.ProgressEvent{Phase: "restore", Step: "restoring certificates"})
+		remotePath := fmt.Sprintf("/tmp/safescale-restore-%s-%s", backup.ID, backupCertificatesObject)
+		xerr = downloadToHosts(ctx, svc, backup.Bucket, prefix+backupCertificatesObject, key, masters, remotePath)
+		if xerr != nil {
+			return nil, xerr
+		}
+		for _, v := range masters {
+			cmd := fmt.Sprintf("set -e; sudo tar -C / -zxf %s; rm -f %s", remotePath, remotePath)
+			xerr = runOnHost(ctx, v, cmd, fmt.Sprintf("failed to restore certificates on master '%s'", v.GetName()))
+			if xerr != nil {
+				return nil, xerr
+			}
+		}
+	}
+
+	// features are added again in a stable order; their requirements are added with them
+	names := make([]string, 0, len(manifest.Features))
+	for k := range manifest.Features {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, v := range names {
+		if task.Aborted() {
+			return nil, fail.AbortedError(nil, "aborted")
+		}
+
+		server.ReportProgress(ctx, server.ProgressEvent{Phase: "restore", Step: fmt.Sprintf("adding feature '%s'", v)})
+		vars := data.Map{}
+		for pk, pv := range manifest.Features[v] {
+			vars[pk] = pv
+		}
+		results, xerr := instance.AddFeature(ctx, v, vars, resources.FeatureSettings{})
+		if xerr != nil {
+			return nil, fail.Wrap(xerr, "failed to add feature '%s'", v)
+		}
+		if !results.Successful() {
+			return nil, fail.NewError("failed to add feature '%s': %s", v, results.AllErrorMessages())
+		}
+	}
+
+	if manifest.has(backupControlPlaneObject) {
+		if instance.makers.RestoreControlPlane == nil {
+			return nil, fail.NotImplementedError("flavor '%s' cannot restore the state of its control plane", flavor.String())
+		}
+
+		server.ReportProgress(ctx, server.ProgressEvent{Phase: "restore", Step: "restoring control plane"})
+		remotePath := fmt.Sprintf("/tmp/safescale-restore-%s-%s", backup.ID, backupControlPlaneObject)
+		xerr = downloadToHosts(ctx, svc, backup.Bucket, prefix+backupControlPlaneObject, key, masters, remotePath)
+		if xerr != nil {
+			return nil, xerr
+		}
+		xerr = instance.makers.RestoreControlPlane(ctx, instance, masters, remotePath)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return nil, xerr
+		}
+	}
+
+	xerr = instance.alterBackups(func(backupsV1 *propertiesv1.ClusterBackups) fail.Error {
+		if backupsV1.Find(backup.ID) == nil {
+			backupsV1.Backups = append(backupsV1.Backups, backup)
+		}
+		backupsV1.LastRestoreID = backup.ID
+		backupsV1.LastRestoreTime = time.Now()
+		return nil
+	})
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	server.ReportProgress(ctx, server.ProgressEvent{Phase: "done", Step: "cluster restored", Percent: 100})
+	return backup, nil
+}
+
+// findBackup returns the backup 'id' recorded in Cluster metadata (the newest one if id is empty); a backup not recorded
+// is looked for in bucket 'bucketName' ('<cluster name>-backups' if empty)
+func (instance *Cluster) findBackup(bucketName, id string) (*propertiesv1.ClusterBackup, fail.Error) {
+	backups, xerr := instance.ListBackups(context.Background())
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	if backup := backups.Find(id); backup != nil {
+		if bucketName != "" {
+			backup.Bucket = bucketName
+		}
+		return backup, nil
+	}
+	if id == "" {
+		return nil, fail.NotFoundError("no backup of Cluster '%s' recorded; use the id of the backup to restore", instance.GetName())
+	}
+
+	if bucketName == "" {
+		bucketName = defaultBackupBucket(instance.GetName())
+	}
+	return &propertiesv1.ClusterBackup{ID: id, Bucket: bucketName}, nil
+}
+
+// readBackupManifest reads the manifest of the backup stored in bucket 'bucketName' under 'prefix'
+// The manifest is decrypted with the crypt key of metadata, unless it has been saved in plaintext by an older backup
+func readBackupManifest(svc iaas.Service, bucketName, prefix string) (*clusterBackupManifest, fail.Error) {
+	content, xerr := readBackupObject(svc, bucketName, prefix+backupManifestObject, nil)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			return nil, fail.NotFoundError("failed to find a complete backup in bucket '%s' under '%s'", bucketName, prefix)
+		default:
+			return nil, xerr
+		}
+	}
+
+	manifest := &clusterBackupManifest{}
+	err := json.Unmarshal(content, manifest)
+	if err == nil {
+		return manifest, nil
+	}
+
+	key, xerr := backupKey(svc)
+	if xerr != nil {
+		return nil, fail.SyntaxError("invalid manifest of backup in bucket '%s' under '%s': %v", bucketName, prefix, err)
+	}
+	content, err = crypt.Decrypt(content, key)
+	if err != nil {
+		return nil, fail.SyntaxError("failed to decrypt manifest of backup in bucket '%s' under '%s': %v", bucketName, prefix, err)
+	}
+	err = json.Unmarshal(content, manifest)
+	if err != nil {
+		return nil, fail.SyntaxError("invalid manifest of backup in bucket '%s' under '%s': %v", bucketName, prefix, err)
+	}
+	return manifest, nil
+}
+
+// rebuildLostMasters creates and configures again the masters of the Cluster not found anymore, then returns all the masters
+// A master is lost if its metadata or its host on the provider is not found; the metadata left by a lost master is removed
+func (instance *Cluster) rebuildLostMasters(ctx context.Context, task concurrency.Task) (_ []resources.Host, ferr fail.Error) {
+	instance.lock.RLock()
+	list, xerr := instance.UnsafeListMasters()
+	instance.lock.RUnlock()
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	svc := instance.GetService()
+	var (
+		masters []resources.Host
+		lost    []*propertiesv3.ClusterNode
+	)
+	defer func() {
+		if ferr != nil {
+			for _, v := range masters {
+				v.Released()
+			}
+		}
+	}()
+	indexes := make([]uint, 0, len(list))
+	for k := range list {
+		indexes = append(indexes, k)
+	}
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i] < indexes[j] })
+	for _, k := range indexes {
+		v := list[k]
+		master, xerr := LoadHost(svc, v.ID)
+		if xerr != nil {
+			switch xerr.(type) {
+			case *fail.ErrNotFound:
+				lost = append(lost, v)
+				continue
+			default:
+				return nil, xerr
+			}
+		}
+
+		// metadata survives the host deleted outside of SafeScale
+		_, xerr = master.ForceGetState(ctx)
+		if xerr != nil {
+			switch xerr.(type) {
+			case *fail.ErrNotFound:
+				logrus.Infof("[Cluster %s] host of master '%s' not found on provider, removing its metadata", instance.GetName(), v.Name)
+				xerr = master.Delete(ctx)
+				master.Released()
+				if xerr != nil {
+					return nil, fail.Wrap(xerr, "failed to remove metadata of lost master '%s'", v.Name)
+				}
+				lost = append(lost, v)
+				continue
+			default:
+				master.Released()
+				return nil, xerr
+			}
+		}
+		masters = append(masters, master)
+	}
+	if len(lost) == 0 {
+		return masters, nil
+	}
+
+	var (
+		def   abstract.HostSizingRequirements
+		image string
+	)
+	instance.lock.Lock()
+	xerr = instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		innerXErr := props.Inspect(clusterproperty.DefaultsV2, func(clonable data.Clonable) fail.Error {
+			defaultsV2, ok := clonable.(*propertiesv2.ClusterDefaults)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv2.ClusterDefaults' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			def = complementHostDefinition(def, defaultsV2.MasterSizing)
+			image = defaultsV2.Image
+			return nil
+		})
+		if innerXErr != nil {
+			return innerXErr
+		}
+
+		// lost masters are forgotten; their replacements are recorded by their creation
+		return props.Alter(clusterproperty.NodesV3, func(clonable data.Clonable) fail.Error {
+			nodesV3, ok := clonable.(*propertiesv3.ClusterNodes)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv3.ClusterNodes' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			for _, v := range lost {
+				removeClusterNode(nodesV3, v)
+			}
+			return nil
+		})
+	})
+	instance.lock.Unlock()
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	_, def.Image, xerr = determineImageID(svc, image)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	for i, v := range lost {
+		logrus.Infof("[Cluster %s] master '%s' not found, creating a new one", instance.GetName(), v.Name)
+		result, xerr := instance.taskCreateMaster(task, taskCreateMasterParameters{
+			index:     uint(i + 1),
+			masterDef: def,
+			timeout:   2 * temporal.GetHostCreationTimeout(),
+		})
+		if xerr != nil {
+			return nil, fail.Wrap(xerr, "failed to create master replacing '%s'", v.Name)
+		}
+		master, ok := result.(resources.Host)
+		if !ok {
+			return nil, fail.InconsistentError("'resources.Host' expected, '%s' provided", reflect.TypeOf(result).String())
+		}
+		masters = append(masters, master)
+
+		_, xerr = instance.taskConfigureMaster(task, taskConfigureMasterParameters{Index: uint(i + 1), Host: master})
+		if xerr != nil {
+			return nil, xerr
+		}
+	}
+	return masters, nil
+}
+
+// runOnHost runs cmd on host, returning an error annotated with the outputs if it fails
+func runOnHost(ctx context.Context, host resources.Host, cmd string, msg string) fail.Error {
+	retcode, stdout, stderr, xerr := host.Run(ctx, cmd, outputs.COLLECT, temporal.GetConnectionTimeout(), temporal.GetLongOperationTimeout())
+	if xerr != nil {
+		return fail.Wrap(xerr, msg)
+	}
+	if retcode != 0 {
+		xerr := fail.ExecutionError(nil, msg)
+		_ = xerr.Annotate("retcode", retcode)
+		_ = xerr.Annotate("stdout", stdout)
+		_ = xerr.Annotate("stderr", stderr)
+		return xerr
+	}
+	return nil
+}
+
+// uploadFromHost copies the file 'remotePath' of host, encrypted with key, in the object 'objectName' of bucket 'bucketName',
+// then removes the file from host; returns the size of the object
+func uploadFromHost(ctx context.Context, host resources.Host, remotePath string, svc iaas.Service, bucketName, objectName string, key *crypt.Key) (_ int64, xerr fail.Error) {
+	defer func() {
+		_, _, _, derr := host.Run(ctx, "rm -f "+remotePath, outputs.COLLECT, temporal.GetConnectionTimeout(), temporal.GetExecutionTimeout())
+		if derr != nil {
+			logrus.Warnf("failed to remove '%s' from host '%s': %v", remotePath, host.GetName(), derr)
+		}
+	}()
+
+	f, err := ioutil.TempFile("", "safescale-backup-")
+	if err != nil {
+		return 0, fail.ConvertError(err)
+	}
+	localPath := f.Name()
+	_ = f.Close()
+	defer func() { _ = os.Remove(localPath) }()
+
+	retcode, _, stderr, xerr := host.Pull(ctx, remotePath, localPath, temporal.GetLongOperationTimeout())
+	if xerr != nil {
+		return 0, xerr
+	}
+	if retcode != 0 {
+		xerr := fail.ExecutionError(nil, "failed to copy '%s' from host '%s'", remotePath, host.GetName())
+		_ = xerr.Annotate("retcode", retcode)
+		_ = xerr.Annotate("stderr", stderr)
+		return 0, xerr
+	}
+
+	content, err := ioutil.ReadFile(localPath)
+	if err != nil {
+		return 0, fail.ConvertError(err)
+	}
+	return writeBackupObject(svc, bucketName, objectName, content, key)
+}
+
+// downloadToHosts copies the object 'objectName' of bucket 'bucketName', decrypted with key if key is not nil, in the file
+// 'remotePath' of each host
+func downloadToHosts(ctx context.Context, svc iaas.Service, bucketName, objectName string, key *crypt.Key, hosts []resources.Host, remotePath string) fail.Error {
+	content, xerr := readBackupObject(svc, bucketName, objectName, key)
+	if xerr != nil {
+		return xerr
+	}
+
+	f, err := ioutil.TempFile("", "safescale-restore-")
+	if err != nil {
+		return fail.ConvertError(err)
+	}
+	localPath := f.Name()
+	defer func() { _ = os.Remove(localPath) }()
+
+	_, err = f.Write(content)
+	_ = f.Close()
+	if err != nil {
+		return fail.ConvertError(err)
+	}
+
+	for _, v := range hosts {
+		retcode, _, stderr, xerr := v.Push(ctx, localPath, remotePath, "", "0600", temporal.GetLongOperationTimeout())
+		if xerr != nil {
+			return xerr
+		}
+		if retcode != 0 {
+			xerr := fail.ExecutionError(nil, "failed to copy '%s' to host '%s'", remotePath, v.GetName())
+			_ = xerr.Annotate("retcode", retcode)
+			_ = xerr.Annotate("stderr", stderr)
+			return xerr
+		}
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	memorystack "github.com/CS-SI/SafeScale/lib/server/iaas/stacks/memory"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clustercomplexity"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterflavor"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterproperty"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterstate"
+	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
+	"github.com/CS-SI/SafeScale/lib/utils/crypt"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/data/serialize"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+func Test_clusterBackupManifest(t *testing.T) {
+	manifest := clusterBackupManifest{Objects: []string{backupMetadataObject, backupManifestObject}}
+	assert.True(t, manifest.has(backupMetadataObject))
+	assert.False(t, manifest.has(backupControlPlaneObject))
+
+	assert.Equal(t, "k8s/20211014T090211Z/", backupPrefix("k8s", "20211014T090211Z"))
+	assert.Equal(t, "my-k8s-backups", defaultBackupBucket("My-K8S"))
+}
+
+func Test_cluster_Backup(t *testing.T) {
+	svc := getMemoryService(t)
+	ctx := context.Background()
+
+	instance, xerr := NewCluster(svc)
+	require.Nil(t, xerr)
+	req := abstract.ClusterRequest{Name: "saved", Flavor: clusterflavor.BOH, Complexity: clustercomplexity.Small, InitialNodeCount: 1}
+	require.Nil(t, instance.firstLight(req))
+
+	xerr = instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		innerXErr := props.Alter(clusterproperty.StateV1, func(clonable data.Clonable) fail.Error {
+			stateV1, ok := clonable.(*propertiesv1.ClusterState)
+			require.True(t, ok, reflect.TypeOf(clonable).String())
+			stateV1.State = clusterstate.Stopped
+			return nil
+		})
+		if innerXErr != nil {
+			return innerXErr
+		}
+
+		return props.Alter(clusterproperty.FeaturesV1, func(clonable data.Clonable) fail.Error {
+			featuresV1, ok := clonable.(*propertiesv1.ClusterFeatures)
+			require.True(t, ok, reflect.TypeOf(clonable).String())
+			featuresV1.Installed["docker"] = propertiesv1.NewClusterInstalledFeature()
+			return nil
+		})
+	})
+	require.Nil(t, xerr)
+
+	_, xerr = instance.Backup(ctx, "")
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrInvalidRequest{}, xerr)
+
+	// Parameters of the features are kept for the backup
+	require.Nil(t, instance.recordFeatureParameters("docker", data.Map{"Version": "20.10", "Swarm": false}))
+	require.Nil(t, instance.recordFeatureParameters("unknown", data.Map{"Version": "1"}))
+	xerr = instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Alter(clusterproperty.StateV1, func(clonable data.Clonable) fail.Error {
+			clonable.(*propertiesv1.ClusterState).State = clusterstate.Nominal
+			return nil
+		})
+	})
+	require.Nil(t, xerr)
+	manifest, xerr := instance.prepareBackup()
+	require.Nil(t, xerr)
+	assert.Equal(t, "saved", manifest.Cluster)
+	assert.Equal(t, map[string]map[string]string{"docker": {"Version": "20.10", "Swarm": "false"}}, manifest.Features)
+	assert.NotEmpty(t, manifest.ID)
+
+	backups, xerr := instance.ListBackups(ctx)
+	require.Nil(t, xerr)
+	assert.Empty(t, backups.Backups)
+
+	_, xerr = instance.Restore(ctx, "", "")
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrNotFound{}, xerr)
+
+	_, xerr = svc.CreateBucket("saved-backups")
+	require.Nil(t, xerr)
+	_, xerr = instance.Restore(ctx, "", "20211014T090211Z")
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrNotFound{}, xerr)
+
+	// A backup of another flavor is refused
+	manifest.Flavor = "K8S"
+	content, err := json.Marshal(manifest)
+	require.Nil(t, err)
+	_, xerr = svc.WriteObject("saved-backups", backupPrefix("saved", manifest.ID)+backupManifestObject, bytes.NewReader(content), int64(len(content)), nil)
+	require.Nil(t, xerr)
+	read, xerr := readBackupManifest(svc, "saved-backups", backupPrefix("saved", manifest.ID))
+	require.Nil(t, xerr)
+	assert.Equal(t, manifest.Features, read.Features)
+	_, xerr = instance.Restore(ctx, "", manifest.ID)
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrInvalidRequest{}, xerr)
+}
+
+func Test_backupObjects(t *testing.T) {
+	// without crypt key of metadata, backups are refused
+	_, xerr := backupKey(getMemoryService(t))
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrInvalidRequest{}, xerr)
+
+	memorystack.Forget("TestBackupObjects")
+	svc, xerr := iaas.BuildService(map[string]interface{}{
+		"name":   "TestBackupObjects",
+		"client": "memory",
+		"compute": map[string]interface{}{
+			"Region": "test",
+		},
+		"objectstorage": map[string]interface{}{
+			"Type":     "memory",
+			"Endpoint": "TestBackupObjects",
+		},
+		"metadata": map[string]interface{}{
+			"Type":     "memory",
+			"Endpoint": "TestBackupObjects",
+			"CryptKey": "backup-objects-key",
+		},
+	}, "v21.05.0")
+	require.Nil(t, xerr)
+	key, xerr := backupKey(svc)
+	require.Nil(t, xerr)
+	_, xerr = svc.CreateBucket("saved-backups")
+	require.Nil(t, xerr)
+
+	// objects are never stored in plaintext
+	secret := []byte(`{"admin_password":"secret"}`)
+	size, xerr := writeBackupObject(svc, "saved-backups", "saved/1/metadata.json", secret, key)
+	require.Nil(t, xerr)
+	stored, xerr := readBackupObject(svc, "saved-backups", "saved/1/metadata.json", nil)
+	require.Nil(t, xerr)
+	assert.EqualValues(t, len(stored), size)
+	assert.False(t, bytes.Contains(stored, []byte("secret")))
+	content, xerr := readBackupObject(svc, "saved-backups", "saved/1/metadata.json", key)
+	require.Nil(t, xerr)
+	assert.Equal(t, secret, content)
+
+	other, err := crypt.NewEncryptionKey([]byte("another-key"))
+	require.Nil(t, err)
+	_, xerr = readBackupObject(svc, "saved-backups", "saved/1/metadata.json", other)
+	require.NotNil(t, xerr)
+
+	// encrypted manifests and plaintext ones of older backups are both read
+	manifest := clusterBackupManifest{Cluster: "saved", ID: "1", Objects: []string{backupMetadataObject}, Encrypted: true}
+	content, err = json.Marshal(manifest)
+	require.Nil(t, err)
+	_, xerr = writeBackupObject(svc, "saved-backups", backupPrefix("saved", "1")+backupManifestObject, content, key)
+	require.Nil(t, xerr)
+	read, xerr := readBackupManifest(svc, "saved-backups", backupPrefix("saved", "1"))
+	require.Nil(t, xerr)
+	assert.Equal(t, manifest, *read)
+
+	manifest.Encrypted = false
+	content, err = json.Marshal(manifest)
+	require.Nil(t, err)
+	_, xerr = svc.WriteObject("saved-backups", backupPrefix("saved", "0")+backupManifestObject, bytes.NewReader(content), int64(len(content)), nil)
+	require.Nil(t, xerr)
+	read, xerr = readBackupManifest(svc, "saved-backups", backupPrefix("saved", "0"))
+	require.Nil(t, xerr)
+	assert.False(t, read.Encrypted)
+}
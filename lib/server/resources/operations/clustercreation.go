@@ -0,0 +1,578 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server"
+	"github.com/CS-SI/SafeScale/lib/server/resources"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterproperty"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterstate"
+	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
+	propertiesv3 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v3"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/data/serialize"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+// Steps of the creation of a Cluster, in order, recorded in property CreationV1 when done
+const (
+	creationStepNetwork               = "network"                // Network, Subnet and gateways created
+	creationStepGateways              = "gateways"               // gateways installed
+	creationStepMasters               = "masters"                // masters created
+	creationStepNodes                 = "nodes"                  // nodes created
+	creationStepGatewaysConfiguration = "gateways-configuration" // gateways configured
+	creationStepMastersConfiguration  = "masters-configuration"  // masters configured
+	creationStepNodesConfiguration    = "nodes-configuration"    // nodes configured
+	creationStepFeatures              = "features"               // default features installed
+	creationStepConfiguration         = "configuration"          // Cluster configured for its flavor
+)
+
+// States reached by the hosts during the creation of a Cluster
+const (
+	creationHostCreated    = "created"
+	creationHostConfigured = "configured"
+)
+
+// inspectCreation calls callback with the creation progress of the Cluster
+func (instance *Cluster) inspectCreation(callback func(*propertiesv1.ClusterCreation) fail.Error) fail.Error {
+	return instance.Inspect(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Inspect(clusterproperty.CreationV1, func(clonable data.Clonable) fail.Error {
+			creationV1, ok := clonable.(*propertiesv1.ClusterCreation)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterCreation' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+			return callback(creationV1)
+		})
+	})
+}
+
+// alterCreation calls callback to update the creation progress of the Cluster; does nothing if the creation is not tracked
+// (Cluster created before the tracking existed) or has already ended
+func (instance *Cluster) alterCreation(callback func(*propertiesv1.ClusterCreation) fail.Error) fail.Error {
+	return instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Alter(clusterproperty.CreationV1, func(clonable data.Clonable) fail.Error {
+			creationV1, ok := clonable.(*propertiesv1.ClusterCreation)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterCreation' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+			if creationV1.IsNull() || creationV1.Completed {
+				return nil
+			}
+			return callback(creationV1)
+		})
+	})
+}
+
+// startCreation records the request of the creation, with its sizings resolved, to be able to resume it
+func (instance *Cluster) startCreation(req abstract.ClusterRequest) fail.Error {
+	return instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Alter(clusterproperty.CreationV1, func(clonable data.Clonable) fail.Error {
+			creationV1, ok := clonable.(*propertiesv1.ClusterCreation)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterCreation' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			creationV1.Request = &req
+			creationV1.Steps = []string{}
+			creationV1.Hosts = map[string]string{}
+			creationV1.Error = ""
+			creationV1.Completed = false
+			return nil
+		})
+	})
+}
+
+// creationStepDone tells if a step of the creation has already been done
+func (instance *Cluster) creationStepDone(step string) (done bool, xerr fail.Error) {
+	xerr = instance.inspectCreation(func(creationV1 *propertiesv1.ClusterCreation) fail.Error {
+		done = creationV1.Done(step)
+		return nil
+	})
+	return done, xerr
+}
+
+// markCreationStep records that a step of the creation is done
+func (instance *Cluster) markCreationStep(step string) fail.Error {
+	return instance.alterCreation(func(creationV1 *propertiesv1.ClusterCreation) fail.Error {
+		if !creationV1.Done(step) {
+			creationV1.Steps = append(creationV1.Steps, step)
+		}
+		return nil
+	})
+}
+
+// markCreationHost records the state reached by a host during the creation
+// Failure to record is only logged: at worst, the host will be rebuilt by a resume
+func (instance *Cluster) markCreationHost(name, state string) {
+	xerr := instance.alterCreation(func(creationV1 *propertiesv1.ClusterCreation) fail.Error {
+		creationV1.Hosts[name] = state
+		return nil
+	})
+	if xerr != nil {
+		logrus.Warnf("[Cluster %s] failed to record creation state of host '%s': %v", instance.GetName(), name, xerr)
+	}
+}
+
+// creationHostState returns the state reached by a host during the creation ("" if not created)
+func (instance *Cluster) creationHostState(name string) (state string, xerr fail.Error) {
+	xerr = instance.inspectCreation(func(creationV1 *propertiesv1.ClusterCreation) fail.Error {
+		state = creationV1.Hosts[name]
+		return nil
+	})
+	return state, xerr
+}
+
+// runCreationStep runs the step of the creation if not already done, then records it as done
+func (instance *Cluster) runCreationStep(step string, fn func() fail.Error) fail.Error {
+	done, xerr := instance.creationStepDone(step)
+	if xerr != nil {
+		return xerr
+	}
+	if done {
+		logrus.Debugf("[Cluster %s] creation step '%s' already done, skipping", instance.GetName(), step)
+		return nil
+	}
+
+	xerr = fn()
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+	return instance.markCreationStep(step)
+}
+
+// failCreation records the failure of the creation; the Cluster is left in state Error, ready to be resumed
+func (instance *Cluster) failCreation(cause fail.Error) {
+	xerr := instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		innerXErr := props.Alter(clusterproperty.CreationV1, func(clonable data.Clonable) fail.Error {
+			creationV1, ok := clonable.(*propertiesv1.ClusterCreation)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterCreation' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+			creationV1.Error = cause.Error()
+			return nil
+		})
+		if innerXErr != nil {
+			return innerXErr
+		}
+
+		return props.Alter(clusterproperty.StateV1, func(clonable data.Clonable) fail.Error {
+			stateV1, ok := clonable.(*propertiesv1.ClusterState)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterState' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+			stateV1.State = clusterstate.Error
+			return nil
+		})
+	})
+	if xerr != nil {
+		_ = cause.AddConsequence(fail.Wrap(xerr, "failed to record failure of creation of Cluster '%s'", instance.GetName()))
+		return
+	}
+	logrus.Warnf("[Cluster %s] creation failed; resources have been kept, the creation can be resumed with 'safescale cluster resume %s'", instance.GetName(), instance.GetName())
+}
+
+// completeCreation sets the nominal state of the new Cluster in metadata
+func (instance *Cluster) completeCreation(req abstract.ClusterRequest) fail.Error {
+	return instance.Alter(func(clonable data.Clonable, props *serialize.JSONProperties) fail.Error {
+		// update metadata about disabled default features
+		innerXErr := props.Alter(clusterproperty.FeaturesV1, func(clonable data.Clonable) fail.Error {
+			featuresV1, ok := clonable.(*propertiesv1.ClusterFeatures)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterFeatures' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			featuresV1.Disabled = req.DisabledDefaultFeatures
+			return nil
+		})
+		if innerXErr != nil {
+			return innerXErr
+		}
+
+		innerXErr = props.Alter(clusterproperty.CreationV1, func(clonable data.Clonable) fail.Error {
+			creationV1, ok := clonable.(*propertiesv1.ClusterCreation)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterCreation' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			creationV1.Error = ""
+			creationV1.Completed = true
+			return nil
+		})
+		if innerXErr != nil {
+			return innerXErr
+		}
+
+		return props.Alter(clusterproperty.StateV1, func(clonable data.Clonable) fail.Error {
+			stateV1, ok := clonable.(*propertiesv1.ClusterState)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterState' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+			stateV1.State = clusterstate.Nominal
+			return nil
+		})
+	})
+}
+
+// Resume restarts the creation of a Cluster from the last step done, reusing the hosts already created
+// Resources are always kept on failure, to be able to resume again.
+func (instance *Cluster) Resume(ctx context.Context) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotAvailable:
+			task, xerr = concurrency.VoidTask()
+			if xerr != nil {
+				return xerr
+			}
+		default:
+			return xerr
+		}
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.cluster")).Entering()
+	defer tracer.Exiting()
+	defer temporal.NewStopwatch().OnExitLogInfo(
+		fmt.Sprintf("Resuming creation of Cluster '%s'...", instance.GetName()),
+		fmt.Sprintf("Ending resumed creation of Cluster '%s'", instance.GetName()),
+	)()
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	_, xerr = task.Run(instance.taskResumeCluster, nil)
+	return xerr
+}
+
+// taskResumeCluster is the TaskAction that resumes the creation of a Cluster
+func (instance *Cluster) taskResumeCluster(task concurrency.Task, _ concurrency.TaskParameters) (_ concurrency.TaskResult, ferr fail.Error) {
+	defer fail.OnPanic(&ferr)
+
+	ctx := task.Context()
+
+	var (
+		req   abstract.ClusterRequest
+		state clusterstate.Enum
+	)
+	xerr := instance.Inspect(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		innerXErr := props.Inspect(clusterproperty.CreationV1, func(clonable data.Clonable) fail.Error {
+			creationV1, ok := clonable.(*propertiesv1.ClusterCreation)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterCreation' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+			if creationV1.IsNull() {
+				return fail.InvalidRequestError("the creation of Cluster '%s' cannot be resumed: it has not been recorded", instance.GetName())
+			}
+			if creationV1.Completed {
+				return fail.InvalidRequestError("the creation of Cluster '%s' has already ended successfully", instance.GetName())
+			}
+
+			req = *creationV1.Request
+			return nil
+		})
+		if innerXErr != nil {
+			return innerXErr
+		}
+
+		return props.Inspect(clusterproperty.StateV1, func(clonable data.Clonable) fail.Error {
+			stateV1, ok := clonable.(*propertiesv1.ClusterState)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterState' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+			state = stateV1.State
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+	if state != clusterstate.Creating && state != clusterstate.Error {
+		return nil, fail.InvalidRequestError("the creation of Cluster '%s' cannot be resumed in state '%s'", instance.GetName(), state.String())
+	}
+
+	// a resumed creation always keeps what has been done, to be able to resume again
+	req.KeepOnFailure = true
+	defer func() {
+		if ferr != nil {
+			instance.failCreation(ferr)
+		}
+	}()
+
+	xerr = instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Alter(clusterproperty.StateV1, func(clonable data.Clonable) fail.Error {
+			stateV1, ok := clonable.(*propertiesv1.ClusterState)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterState' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+			stateV1.State = clusterstate.Creating
+			return nil
+		})
+	})
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	// Networking
+	done, xerr := instance.creationStepDone(creationStepNetwork)
+	if xerr != nil {
+		return nil, xerr
+	}
+	var subnetInstance resources.Subnet
+	if done {
+		_, _, subnetInstance, xerr = instance.extractNetworkingInfo()
+		if xerr != nil {
+			return nil, xerr
+		}
+	} else {
+		// what may have been created of the networking is incomplete, restarts from scratch
+		xerr = instance.dropUnfinishedNetworking()
+		if xerr != nil {
+			return nil, xerr
+		}
+
+		server.ReportProgress(ctx, server.ProgressEvent{Phase: "network", Step: "creating Network, Subnet and gateways", Percent: 5})
+		_, subnetInstance, xerr = instance.createNetworkingResources(task, req, &req.GatewaysDef)
+		if xerr != nil {
+			return nil, xerr
+		}
+		if xerr = instance.markCreationStep(creationStepNetwork); xerr != nil {
+			return nil, xerr
+		}
+	}
+
+	// Hosts
+	xerr = instance.dropUnfinishedHosts(task)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	server.ReportProgress(ctx, server.ProgressEvent{Phase: "hosts", Step: "creating and configuring missing masters and nodes", Percent: 30})
+	xerr = instance.createHostResources(task, subnetInstance, req.MastersDef, req.NodesDef, req.InitialNodeCount, req.KeepOnFailure)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	server.ReportProgress(ctx, server.ProgressEvent{Phase: "configuration", Step: "configuring Cluster", Percent: 80})
+	xerr = instance.configureCluster(ctx)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	xerr = instance.completeCreation(req)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	server.ReportProgress(ctx, server.ProgressEvent{Phase: "done", Step: "Cluster is ready", Percent: 100})
+	return nil, nil
+}
+
+// dropUnfinishedNetworking deletes the Subnet and the Network created by an unfinished networking step
+func (instance *Cluster) dropUnfinishedNetworking() fail.Error {
+	networkInstance, deleteNetwork, subnetInstance, xerr := instance.extractNetworkingInfo()
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			// what has been registered does not exist anymore; nothing to delete
+			debug.IgnoreError(xerr)
+		default:
+			return xerr
+		}
+	}
+
+	if subnetInstance != nil && !subnetInstance.IsNull() {
+		logrus.Debugf("[Cluster %s] deleting unfinished Subnet '%s'", instance.GetName(), subnetInstance.GetName())
+		if xerr = subnetInstance.Delete(context.Background()); xerr != nil {
+			if _, ok := xerr.(*fail.ErrNotFound); !ok {
+				return fail.Wrap(xerr, "failed to delete unfinished Subnet")
+			}
+		}
+	}
+	if deleteNetwork && networkInstance != nil && !networkInstance.IsNull() {
+		logrus.Debugf("[Cluster %s] deleting unfinished Network '%s'", instance.GetName(), networkInstance.GetName())
+		if xerr = networkInstance.Delete(context.Background()); xerr != nil {
+			if _, ok := xerr.(*fail.ErrNotFound); !ok {
+				return fail.Wrap(xerr, "failed to delete unfinished Network")
+			}
+		}
+	}
+
+	return instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Alter(clusterproperty.NetworkV3, func(clonable data.Clonable) fail.Error {
+			networkV3, ok := clonable.(*propertiesv3.ClusterNetwork)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv3.ClusterNetwork' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+			*networkV3 = propertiesv3.ClusterNetwork{}
+			return nil
+		})
+	})
+}
+
+// dropUnfinishedHosts deletes the masters and nodes whose creation has not ended, and removes them from metadata;
+// they will be created again
+func (instance *Cluster) dropUnfinishedHosts(task concurrency.Task) fail.Error {
+	var unfinished []*propertiesv3.ClusterNode
+	xerr := instance.Inspect(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		var hosts map[string]string
+		innerXErr := props.Inspect(clusterproperty.CreationV1, func(clonable data.Clonable) fail.Error {
+			creationV1, ok := clonable.(*propertiesv1.ClusterCreation)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterCreation' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+			hosts = creationV1.Hosts
+			return nil
+		})
+		if innerXErr != nil {
+			return innerXErr
+		}
+
+		return props.Inspect(clusterproperty.NodesV3, func(clonable data.Clonable) fail.Error {
+			nodesV3, ok := clonable.(*propertiesv3.ClusterNodes)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv3.ClusterNodes' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+			for _, v := range nodesV3.ByNumericalID {
+				if _, ok := hosts[v.Name]; !ok {
+					captured := *v
+					unfinished = append(unfinished, &captured)
+				}
+			}
+			return nil
+		})
+	})
+	if xerr != nil {
+		return xerr
+	}
+	if len(unfinished) == 0 {
+		return nil
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	for _, v := range unfinished {
+		logrus.Debugf("[Cluster %s] deleting unfinished host '%s'", instance.GetName(), v.Name)
+		// the ID may not have been recorded if the creation stopped just after the creation of the host
+		ref := v.ID
+		if ref == "" {
+			ref = v.Name
+		}
+		hostInstance, xerr := LoadHost(instance.GetService(), ref)
+		if xerr != nil {
+			if _, ok := xerr.(*fail.ErrNotFound); !ok {
+				return xerr
+			}
+		} else if xerr = deleteHostOnFailure(hostInstance); xerr != nil {
+			return fail.Wrap(xerr, "failed to delete unfinished host '%s'", v.Name)
+		}
+	}
+
+	return instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Alter(clusterproperty.NodesV3, func(clonable data.Clonable) fail.Error {
+			nodesV3, ok := clonable.(*propertiesv3.ClusterNodes)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv3.ClusterNodes' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+			for _, v := range unfinished {
+				removeClusterNode(nodesV3, v)
+			}
+			return nil
+		})
+	})
+}
+
+// removeClusterNode removes all the references to node in nodesV3
+func removeClusterNode(nodesV3 *propertiesv3.ClusterNodes, node *propertiesv3.ClusterNode) {
+	if found, idx := containsClusterNode(nodesV3.Masters, node.NumericalID); found {
+		nodesV3.Masters = append(nodesV3.Masters[:idx], nodesV3.Masters[idx+1:]...)
+	}
+	if found, idx := containsClusterNode(nodesV3.PrivateNodes, node.NumericalID); found {
+		nodesV3.PrivateNodes = append(nodesV3.PrivateNodes[:idx], nodesV3.PrivateNodes[idx+1:]...)
+	}
+	delete(nodesV3.MasterByName, node.Name)
+	delete(nodesV3.PrivateNodeByName, node.Name)
+	if node.ID != "" {
+		delete(nodesV3.MasterByID, node.ID)
+		delete(nodesV3.PrivateNodeByID, node.ID)
+	}
+	delete(nodesV3.ByNumericalID, node.NumericalID)
+}
+
+// countCreatedHosts returns the number of masters and nodes already created by the creation of the Cluster
+func (instance *Cluster) countCreatedHosts() (masters, nodes uint, xerr fail.Error) {
+	xerr = instance.Inspect(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		var hosts map[string]string
+		innerXErr := props.Inspect(clusterproperty.CreationV1, func(clonable data.Clonable) fail.Error {
+			creationV1, ok := clonable.(*propertiesv1.ClusterCreation)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterCreation' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+			hosts = creationV1.Hosts
+			return nil
+		})
+		if innerXErr != nil {
+			return innerXErr
+		}
+
+		return props.Inspect(clusterproperty.NodesV3, func(clonable data.Clonable) fail.Error {
+			nodesV3, ok := clonable.(*propertiesv3.ClusterNodes)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv3.ClusterNodes' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+			for _, v := range nodesV3.Masters {
+				if node, ok := nodesV3.ByNumericalID[v]; ok && hosts[node.Name] != "" {
+					masters++
+				}
+			}
+			for _, v := range nodesV3.PrivateNodes {
+				if node, ok := nodesV3.ByNumericalID[v]; ok && hosts[node.Name] != "" {
+					nodes++
+				}
+			}
+			return nil
+		})
+	})
+	return masters, nodes, xerr
+}
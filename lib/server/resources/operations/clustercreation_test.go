@@ -0,0 +1,132 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clustercomplexity"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterflavor"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterproperty"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterstate"
+	propertiesv3 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v3"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/data/serialize"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+func Test_cluster_Creation(t *testing.T) {
+	svc := getMemoryService(t)
+	ctx := context.Background()
+
+	instance, xerr := NewCluster(svc)
+	require.Nil(t, xerr)
+	req := abstract.ClusterRequest{Name: "resumable", Flavor: clusterflavor.BOH, Complexity: clustercomplexity.Small, InitialNodeCount: 1}
+	require.Nil(t, instance.firstLight(req))
+
+	// creation not recorded, cannot be resumed
+	xerr = instance.Resume(ctx)
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrInvalidRequest{}, xerr)
+
+	require.Nil(t, instance.startCreation(req))
+	require.Nil(t, instance.markCreationStep(creationStepNetwork))
+	done, xerr := instance.creationStepDone(creationStepNetwork)
+	require.Nil(t, xerr)
+	assert.True(t, done)
+	done, xerr = instance.creationStepDone(creationStepGateways)
+	require.Nil(t, xerr)
+	assert.False(t, done)
+
+	// runCreationStep does not run again a step already done
+	ran := false
+	require.Nil(t, instance.runCreationStep(creationStepNetwork, func() fail.Error { ran = true; return nil }))
+	assert.False(t, ran)
+	require.Nil(t, instance.runCreationStep(creationStepGateways, func() fail.Error { ran = true; return nil }))
+	assert.True(t, ran)
+
+	// one master created, one master whose creation did not end
+	xerr = instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Alter(clusterproperty.NodesV3, func(clonable data.Clonable) fail.Error {
+			nodesV3, ok := clonable.(*propertiesv3.ClusterNodes)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv3.ClusterNodes' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+			for i, name := range []string{"resumable-master-1", "resumable-master-2"} {
+				node := &propertiesv3.ClusterNode{NumericalID: uint(i + 1), Name: name}
+				nodesV3.ByNumericalID[node.NumericalID] = node
+				nodesV3.Masters = append(nodesV3.Masters, node.NumericalID)
+				nodesV3.MasterByName[node.Name] = node.NumericalID
+			}
+			return nil
+		})
+	})
+	require.Nil(t, xerr)
+	instance.markCreationHost("resumable-master-1", creationHostCreated)
+
+	masters, nodes, xerr := instance.countCreatedHosts()
+	require.Nil(t, xerr)
+	assert.EqualValues(t, 1, masters)
+	assert.EqualValues(t, 0, nodes)
+
+	instance.failCreation(fail.NewError("installworker failed"))
+	state, xerr := instance.GetState()
+	require.Nil(t, xerr)
+	assert.Equal(t, clusterstate.Error, state)
+
+	require.Nil(t, instance.completeCreation(req))
+	state, xerr = instance.GetState()
+	require.Nil(t, xerr)
+	assert.Equal(t, clusterstate.Nominal, state)
+
+	// hosts are not tracked anymore once the creation ended
+	instance.markCreationHost("resumable-master-2", creationHostCreated)
+	hostState, xerr := instance.creationHostState("resumable-master-2")
+	require.Nil(t, xerr)
+	assert.Empty(t, hostState)
+
+	xerr = instance.Resume(ctx)
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrInvalidRequest{}, xerr)
+}
+
+func Test_removeClusterNode(t *testing.T) {
+	nodesV3 := &propertiesv3.ClusterNodes{
+		ByNumericalID:     map[uint]*propertiesv3.ClusterNode{},
+		PrivateNodeByName: map[string]uint{},
+		PrivateNodeByID:   map[string]uint{},
+	}
+	for i, name := range []string{"node-1", "node-2", "node-3"} {
+		node := &propertiesv3.ClusterNode{ID: name + "-id", NumericalID: uint(i + 1), Name: name}
+		nodesV3.ByNumericalID[node.NumericalID] = node
+		nodesV3.PrivateNodes = append(nodesV3.PrivateNodes, node.NumericalID)
+		nodesV3.PrivateNodeByName[node.Name] = node.NumericalID
+		nodesV3.PrivateNodeByID[node.ID] = node.NumericalID
+	}
+
+	removeClusterNode(nodesV3, nodesV3.ByNumericalID[2])
+	assert.Equal(t, []uint{1, 3}, nodesV3.PrivateNodes)
+	assert.NotContains(t, nodesV3.ByNumericalID, uint(2))
+	assert.NotContains(t, nodesV3.PrivateNodeByName, "node-2")
+	assert.NotContains(t, nodesV3.PrivateNodeByID, "node-2-id")
+}
@@ -0,0 +1,236 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package k3s
+
+/*
+ * Implements a lightweight Kubernetes cluster using K3s, with embedded etcd for HA
+ */
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clustercomplexity"
+	"github.com/CS-SI/SafeScale/lib/server/resources/operations/clusterflavors"
+	"github.com/CS-SI/SafeScale/lib/server/resources/operations/consts"
+	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/outputs"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+const (
+	// nodeTokenFile is the file on K3s servers containing the token used to join the cluster
+	nodeTokenFile = "/var/lib/rancher/k3s/server/node-token"
+	// agentUninstallScript is the script installed by K3s on agents to remove it
+	agentUninstallScript = "/usr/local/bin/k3s-agent-uninstall.sh"
+)
+
+var (
+	// Makers initializes a control.Makers struct to construct a K3S Cluster
+	Makers = clusterflavors.Makers{
+		MinimumRequiredServers: minimumRequiredServers,
+		DefaultGatewaySizing:   gatewaySizing,
+		DefaultMasterSizing:    masterSizing,
+		DefaultNodeSizing:      nodeSizing,
+		DefaultImage:           defaultImage,
+		ConfigureCluster:       configureCluster,
+		JoinNodeToCluster:      joinNodeToCluster,
+		LeaveNodeFromCluster:   leaveNodeFromCluster,
+		LabelNode:              clusterflavors.LabelKubernetesNode,
+		DrainNode:              clusterflavors.DrainKubernetesNode,
+		UncordonNode:           clusterflavors.UncordonKubernetesNode,
+		NodesReadiness:         clusterflavors.KubernetesNodesReadiness,
+		ForgetNode:             clusterflavors.ForgetKubernetesNode,
+	}
+)
+
+// minimumRequiredServers returns the count of masters and nodes; the count of masters is always odd, to keep the quorum of embedded etcd
+func minimumRequiredServers(clusterIdentity abstract.ClusterIdentity) (uint, uint, uint, fail.Error) {
+	var masterCount uint
+	var privateNodeCount uint
+	var publicNodeCount uint
+
+	switch clusterIdentity.Complexity {
+	case clustercomplexity.Small:
+		masterCount = 1
+		privateNodeCount = 1
+	case clustercomplexity.Normal:
+		masterCount = 3
+		privateNodeCount = 2
+	case clustercomplexity.Large:
+		masterCount = 3
+		privateNodeCount = 5
+	}
+	return masterCount, privateNodeCount, publicNodeCount, nil
+}
+
+func gatewaySizing(_ resources.Cluster) abstract.HostSizingRequirements {
+	return abstract.HostSizingRequirements{
+		MinCores:    1,
+		MaxCores:    2,
+		MinRAMSize:  2.0,
+		MaxRAMSize:  4.0,
+		MinDiskSize: 20,
+		MinGPU:      -1,
+	}
+}
+
+func masterSizing(_ resources.Cluster) abstract.HostSizingRequirements {
+	return abstract.HostSizingRequirements{
+		MinCores:    2,
+		MaxCores:    4,
+		MinRAMSize:  4.0,
+		MaxRAMSize:  8.0,
+		MinDiskSize: 40,
+		MinGPU:      -1,
+	}
+}
+
+func nodeSizing(_ resources.Cluster) abstract.HostSizingRequirements {
+	return abstract.HostSizingRequirements{
+		MinCores:    2,
+		MaxCores:    4,
+		MinRAMSize:  4.0,
+		MaxRAMSize:  8.0,
+		MinDiskSize: 40,
+		MinGPU:      -1,
+	}
+}
+
+func defaultImage(_ resources.Cluster) string {
+	return consts.DEFAULTOS
+}
+
+func configureCluster(ctx context.Context, c resources.Cluster) fail.Error {
+	clusterName := c.GetName()
+	logrus.Println(fmt.Sprintf("[cluster %s] adding feature 'k3s'...", clusterName))
+
+	results, xerr := c.AddFeature(ctx, "k3s", map[string]interface{}{}, resources.FeatureSettings{})
+	if xerr != nil {
+		return fail.Wrap(xerr, "[cluster %s] failed to add feature 'k3s'", clusterName)
+	}
+
+	if !results.Successful() {
+		xerr = fail.NewError(fmt.Errorf(results.AllErrorMessages()), nil, "failed to add feature 'k3s' to cluster '%s'", clusterName)
+		logrus.Errorf("[cluster %s] failed to add feature 'k3s': %s", clusterName, xerr.Error())
+		return xerr
+	}
+
+	logrus.Infof("[cluster %s] feature 'k3s' addition successful.", clusterName)
+	return nil
+}
+
+// joinNodeToCluster installs the K3s agent on a node added to the Cluster, using the token and the version of an available master
+func joinNodeToCluster(clusterInstance resources.Cluster, node resources.Host) (xerr fail.Error) {
+	if clusterInstance == nil {
+		return fail.InvalidParameterCannotBeNilError("clusterInstance")
+	}
+	if node == nil {
+		return fail.InvalidParameterCannotBeNilError("node")
+	}
+
+	ctx := context.Background()
+	master, xerr := clusterInstance.FindAvailableMaster(ctx)
+	if xerr != nil {
+		return xerr
+	}
+	defer master.Released()
+
+	masterIP, xerr := master.GetPrivateIP()
+	if xerr != nil {
+		return xerr
+	}
+
+	token, xerr := runOnHost(ctx, master, "sudo cat "+nodeTokenFile, "failed to read K3s token")
+	if xerr != nil {
+		return xerr
+	}
+	version, xerr := runOnHost(ctx, master, "k3s --version | head -1 | awk '{print $3}'", "failed to get K3s version")
+	if xerr != nil {
+		return xerr
+	}
+
+	cmd := fmt.Sprintf("[ -f %s ] || curl -sfL https://get.k3s.io | sudo INSTALL_K3S_VERSION='%s' K3S_URL='https://%s:6443' K3S_TOKEN='%s' sh -s - agent", agentUninstallScript, version, masterIP, token)
+	_, xerr = runOnHost(ctx, node, cmd, "failed to install K3s agent")
+	if xerr != nil {
+		return fail.Wrap(xerr, "failed to join node '%s' to cluster '%s'", node.GetName(), clusterInstance.GetName())
+	}
+
+	return nil
+}
+
+// leaveNodeFromCluster is called to remove a node from a Cluster
+func leaveNodeFromCluster(ctx context.Context, clusterInstance resources.Cluster, node resources.Host, selectedMaster resources.Host) (xerr fail.Error) {
+	if clusterInstance == nil {
+		return fail.InvalidParameterCannotBeNilError("clusterInstance")
+	}
+	if node == nil {
+		return fail.InvalidParameterCannotBeNilError("node")
+	}
+
+	if selectedMaster == nil {
+		selectedMaster, xerr = clusterInstance.FindAvailableMaster(ctx)
+		if xerr != nil {
+			return xerr
+		}
+
+		defer selectedMaster.Released()
+	}
+
+	// Drain pods from node, then delete it from Kubernetes
+	for _, cmd := range []string{
+		fmt.Sprintf("sudo k3s kubectl drain %s --ignore-daemonsets --delete-emptydir-data", node.GetName()),
+		fmt.Sprintf("sudo k3s kubectl delete node %s", node.GetName()),
+	} {
+		retcode, stdout, stderr, xerr := selectedMaster.Run(ctx, cmd, outputs.COLLECT, temporal.GetConnectionTimeout(), temporal.GetExecutionTimeout())
+		if xerr != nil {
+			return fail.Wrap(xerr, "failed to execute removal of node '%s' from cluster '%s'", node.GetName(), clusterInstance.GetName())
+		}
+		if retcode != 0 && !strings.Contains(stderr, "(NotFound)") {
+			xerr := fail.ExecutionError(nil, "failed to remove node '%s' from cluster '%s'", node.GetName(), clusterInstance.GetName())
+			_ = xerr.Annotate("retcode", retcode)
+			_ = xerr.Annotate("stdout", stdout)
+			_ = xerr.Annotate("stderr", stderr)
+			return xerr
+		}
+	}
+
+	// Finally, uninstall K3s agent from node
+	_, xerr = runOnHost(ctx, node, fmt.Sprintf("[ ! -f %s ] || sudo %s", agentUninstallScript, agentUninstallScript), "failed to uninstall K3s agent")
+	return xerr
+}
+
+// runOnHost runs cmd on host and returns its trimmed output
+func runOnHost(ctx context.Context, host resources.Host, cmd string, msg string) (string, fail.Error) {
+	retcode, stdout, stderr, xerr := host.Run(ctx, cmd, outputs.COLLECT, temporal.GetConnectionTimeout(), temporal.GetExecutionTimeout())
+	if xerr != nil {
+		return "", fail.Wrap(xerr, "%s on Host '%s'", msg, host.GetName())
+	}
+	if retcode != 0 {
+		xerr := fail.ExecutionError(nil, "%s on Host '%s'", msg, host.GetName())
+		_ = xerr.Annotate("retcode", retcode)
+		_ = xerr.Annotate("stdout", stdout)
+		_ = xerr.Annotate("stderr", stderr)
+		return "", xerr
+	}
+	return strings.TrimSpace(stdout), nil
+}
@@ -46,6 +46,13 @@ var (
 		// GetNodeInstallationScript: getNodeInstallationScript,
 		ConfigureCluster:     configureCluster,
 		LeaveNodeFromCluster: leaveNodeFromCluster,
+		LabelNode:            clusterflavors.LabelKubernetesNode,
+		DrainNode:            clusterflavors.DrainKubernetesNode,
+		UncordonNode:         clusterflavors.UncordonKubernetesNode,
+		NodesReadiness:       clusterflavors.KubernetesNodesReadiness,
+		ForgetNode:           clusterflavors.ForgetKubernetesNode,
+		SnapshotControlPlane: snapshotControlPlane,
+		RestoreControlPlane:  restoreControlPlane,
 	}
 )
 
@@ -202,3 +209,97 @@ func leaveNodeFromCluster(ctx context.Context, clusterInstance resources.Cluster
 
 	return nil
 }
+
+const (
+	// etcdSnapshotFile is the path of the snapshot of etcd, on the host and in the etcd pod (which mounts /var/lib/etcd)
+	etcdSnapshotFile = "/var/lib/etcd/safescale-snapshot.db"
+	// etcdctlOptions are the options of etcdctl to reach the local member of etcd
+	etcdctlOptions = "--endpoints=https://127.0.0.1:2379 --cacert=/etc/kubernetes/pki/etcd/ca.crt --cert=/etc/kubernetes/pki/etcd/server.crt --key=/etc/kubernetes/pki/etcd/server.key"
+	// etcdManifestsBackup is the folder where the manifests of the static pods are moved to stop them during a restoration
+	etcdManifestsBackup = "/etc/kubernetes/manifests.safescale-restore"
+)
+
+// snapshotControlPlane saves in the archive 'path' on master a snapshot of etcd and the certificates of Kubernetes
+func snapshotControlPlane(ctx context.Context, c resources.Cluster, master resources.Host, path string) fail.Error {
+	if c == nil {
+		return fail.InvalidParameterCannotBeNilError("c")
+	}
+	if master == nil {
+		return fail.InvalidParameterCannotBeNilError("master")
+	}
+	if path == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("path")
+	}
+
+	cmd := fmt.Sprintf("set -e; "+
+		"sudo -u cladm -i kubectl -n kube-system exec etcd-%s -- etcdctl %s snapshot save %s; "+
+		"sudo tar -C / -zcf %s %s etc/kubernetes/pki; "+
+		"sudo rm -f %s; sudo chown $(id -u):$(id -g) %s",
+		master.GetName(), etcdctlOptions, etcdSnapshotFile, path, strings.TrimPrefix(etcdSnapshotFile, "/"), etcdSnapshotFile, path)
+	return runScript(ctx, master, cmd, fmt.Sprintf("failed to save snapshot of etcd on master '%s'", master.GetName()))
+}
+
+// restoreControlPlane restores on each master the certificates of Kubernetes and the snapshot of etcd contained in archive 'path',
+// then waits for the API server to answer again
+func restoreControlPlane(ctx context.Context, c resources.Cluster, masters []resources.Host, path string) fail.Error {
+	if c == nil {
+		return fail.InvalidParameterCannotBeNilError("c")
+	}
+	if len(masters) == 0 {
+		return fail.InvalidParameterError("masters", "cannot be empty slice")
+	}
+	if path == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("path")
+	}
+
+	// every member of etcd is restored from the same snapshot, with the list of all the members
+	members := make([]string, 0, len(masters))
+	ips := make([]string, 0, len(masters))
+	for _, v := range masters {
+		ip, xerr := v.GetPrivateIP()
+		if xerr != nil {
+			return xerr
+		}
+		ips = append(ips, ip)
+		members = append(members, fmt.Sprintf("%s=https://%s:2380", v.GetName(), ip))
+	}
+	initialCluster := strings.Join(members, ",")
+
+	for i, v := range masters {
+		cmd := fmt.Sprintf("set -e; DIR=$(mktemp -d); "+
+			"sudo tar -C ${DIR} -zxf %[1]s; "+
+			"sudo mkdir -p %[2]s; sudo sh -c 'mv /etc/kubernetes/manifests/*.yaml %[2]s/'; "+
+			"timeout 300 sh -c 'while sudo docker ps -q --filter name=k8s_etcd | grep -q .; do sleep 5; done'; "+
+			"sudo cp -a ${DIR}/etc/kubernetes/pki/. /etc/kubernetes/pki/; "+
+			"IMAGE=$(sudo awk '/image:/ {print $2}' %[2]s/etcd.yaml); "+
+			"[ ! -d /var/lib/etcd ] || sudo mv /var/lib/etcd /var/lib/etcd.before-restore.$(date +%%s); "+
+			"sudo docker run --rm -v /var/lib:/var/lib -v ${DIR}:/restore --entrypoint etcdctl ${IMAGE} snapshot restore /restore%[3]s "+
+			"--name %[4]s --initial-cluster %[5]s --initial-cluster-token safescale-restore --initial-advertise-peer-urls https://%[6]s:2380 --data-dir /var/lib/etcd; "+
+			"sudo sh -c 'mv %[2]s/*.yaml /etc/kubernetes/manifests/'; sudo systemctl restart kubelet; "+
+			"sudo rm -rf ${DIR} %[1]s",
+			path, etcdManifestsBackup, etcdSnapshotFile, v.GetName(), initialCluster, ips[i])
+		xerr := runScript(ctx, v, cmd, fmt.Sprintf("failed to restore etcd on master '%s'", v.GetName()))
+		if xerr != nil {
+			return xerr
+		}
+	}
+
+	cmd := "for i in $(seq 60); do sudo -u cladm -i kubectl get nodes >/dev/null 2>&1 && exit 0; sleep 5; done; exit 1"
+	return runScript(ctx, masters[0], cmd, fmt.Sprintf("Kubernetes API of cluster '%s' does not answer after restoration", c.GetName()))
+}
+
+// runScript runs cmd on host, returning an error annotated with the outputs if it fails
+func runScript(ctx context.Context, host resources.Host, cmd string, msg string) fail.Error {
+	retcode, stdout, stderr, xerr := host.Run(ctx, cmd, outputs.COLLECT, temporal.GetConnectionTimeout(), temporal.GetLongOperationTimeout())
+	if xerr != nil {
+		return fail.Wrap(xerr, msg)
+	}
+	if retcode != 0 {
+		xerr := fail.ExecutionError(nil, msg)
+		_ = xerr.Annotate("retcode", retcode)
+		_ = xerr.Annotate("stdout", stdout)
+		_ = xerr.Annotate("stderr", stderr)
+		return xerr
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package clusterflavors
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources"
+	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/outputs"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+// LabelKubernetesNode sets labels and taints on a Kubernetes node, using kubectl on selectedMaster
+// Used as LabelNode maker by the flavors running Kubernetes
+func LabelKubernetesNode(ctx context.Context, c resources.Cluster, node resources.Host, selectedMaster resources.Host, labels map[string]string, taints []string) (xerr fail.Error) {
+	if c == nil {
+		return fail.InvalidParameterCannotBeNilError("c")
+	}
+	if node == nil {
+		return fail.InvalidParameterCannotBeNilError("node")
+	}
+
+	if selectedMaster == nil {
+		selectedMaster, xerr = c.FindAvailableMaster(ctx)
+		if xerr != nil {
+			return xerr
+		}
+
+		defer selectedMaster.Released()
+	}
+
+	if len(labels) > 0 {
+		keys := make([]string, 0, len(labels))
+		for k := range labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		args := make([]string, 0, len(keys))
+		for _, k := range keys {
+			args = append(args, shellQuote(k+"="+labels[k]))
+		}
+		cmd := fmt.Sprintf("sudo -u cladm -i kubectl label node %s %s --overwrite", shellQuote(node.GetName()), strings.Join(args, " "))
+		xerr = runKubectl(ctx, selectedMaster, cmd, fmt.Sprintf("failed to label node '%s'", node.GetName()))
+		if xerr != nil {
+			return xerr
+		}
+	}
+
+	if len(taints) > 0 {
+		args := make([]string, 0, len(taints))
+		for _, v := range taints {
+			args = append(args, shellQuote(v))
+		}
+		cmd := fmt.Sprintf("sudo -u cladm -i kubectl taint node %s %s --overwrite", shellQuote(node.GetName()), strings.Join(args, " "))
+		xerr = runKubectl(ctx, selectedMaster, cmd, fmt.Sprintf("failed to taint node '%s'", node.GetName()))
+		if xerr != nil {
+			return xerr
+		}
+	}
+
+	return nil
+}
+
+// shellQuote quotes s to be passed as a single word to the shell, whatever its content
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// kubectlWaitTimeout is the maximum duration of kubectl commands waiting for the cluster, kept under the execution timeout of a command
+const kubectlWaitTimeout = "5m"
+
+// DrainKubernetesNode marks a Kubernetes node as unschedulable then evicts its pods, using kubectl on selectedMaster
+// Used as DrainNode maker by the flavors running Kubernetes
+func DrainKubernetesNode(ctx context.Context, c resources.Cluster, node resources.Host, selectedMaster resources.Host) fail.Error {
+	if c == nil {
+		return fail.InvalidParameterCannotBeNilError("c")
+	}
+	if node == nil {
+		return fail.InvalidParameterCannotBeNilError("node")
+	}
+	if selectedMaster == nil {
+		return fail.InvalidParameterCannotBeNilError("selectedMaster")
+	}
+
+	cmd := fmt.Sprintf("sudo -u cladm -i kubectl drain %s --ignore-daemonsets --delete-emptydir-data --timeout=%s", node.GetName(), kubectlWaitTimeout)
+	return runKubectl(ctx, selectedMaster, cmd, fmt.Sprintf("failed to drain node '%s'", node.GetName()))
+}
+
+// UncordonKubernetesNode marks a Kubernetes node as schedulable, using kubectl on selectedMaster; the readiness of the node
+// has to be checked by the caller
+// Used as UncordonNode maker by the flavors running Kubernetes
+func UncordonKubernetesNode(ctx context.Context, c resources.Cluster, node resources.Host, selectedMaster resources.Host) fail.Error {
+	if c == nil {
+		return fail.InvalidParameterCannotBeNilError("c")
+	}
+	if node == nil {
+		return fail.InvalidParameterCannotBeNilError("node")
+	}
+	if selectedMaster == nil {
+		return fail.InvalidParameterCannotBeNilError("selectedMaster")
+	}
+
+	cmd := fmt.Sprintf("sudo -u cladm -i kubectl uncordon %s", node.GetName())
+	return runKubectl(ctx, selectedMaster, cmd, fmt.Sprintf("failed to uncordon node '%s'", node.GetName()))
+}
+
+// KubernetesNodesReadiness returns the readiness of the Kubernetes nodes, indexed by name, using kubectl on selectedMaster
+// Used as NodesReadiness maker by the flavors running Kubernetes
+func KubernetesNodesReadiness(ctx context.Context, c resources.Cluster, selectedMaster resources.Host) (map[string]bool, fail.Error) {
+	if c == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("c")
+	}
+	if selectedMaster == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("selectedMaster")
+	}
+
+	cmd := "sudo -u cladm -i kubectl get nodes --no-headers"
+	retcode, stdout, stderr, xerr := selectedMaster.Run(ctx, cmd, outputs.COLLECT, temporal.GetConnectionTimeout(), temporal.GetExecutionTimeout())
+	if xerr != nil {
+		return nil, fail.Wrap(xerr, "failed to list Kubernetes nodes")
+	}
+	if retcode != 0 {
+		xerr := fail.ExecutionError(nil, "failed to list Kubernetes nodes")
+		_ = xerr.Annotate("retcode", retcode)
+		_ = xerr.Annotate("stdout", stdout)
+		_ = xerr.Annotate("stderr", stderr)
+		return nil, xerr
+	}
+
+	return parseKubectlGetNodes(stdout), nil
+}
+
+// parseKubectlGetNodes converts the output of 'kubectl get nodes --no-headers' to the readiness of the nodes indexed by name
+// A node is ready when its status is 'Ready', possibly followed by other conditions (like 'Ready,SchedulingDisabled')
+func parseKubectlGetNodes(out string) map[string]bool {
+	readiness := map[string]bool{}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		status := strings.Split(fields[1], ",")
+		readiness[fields[0]] = status[0] == "Ready"
+	}
+	return readiness
+}
+
+// ForgetKubernetesNode removes a node from Kubernetes without contacting it, using kubectl on selectedMaster
+// Used as ForgetNode maker by the flavors running Kubernetes
+func ForgetKubernetesNode(ctx context.Context, c resources.Cluster, nodeName string, selectedMaster resources.Host) fail.Error {
+	if c == nil {
+		return fail.InvalidParameterCannotBeNilError("c")
+	}
+	if nodeName == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("nodeName")
+	}
+	if selectedMaster == nil {
+		return fail.InvalidParameterCannotBeNilError("selectedMaster")
+	}
+
+	cmd := fmt.Sprintf("sudo -u cladm -i kubectl delete node %s --ignore-not-found", nodeName)
+	return runKubectl(ctx, selectedMaster, cmd, fmt.Sprintf("failed to remove node '%s' from Kubernetes", nodeName))
+}
+
+func runKubectl(ctx context.Context, master resources.Host, cmd string, msg string) fail.Error {
+	retcode, stdout, stderr, xerr := master.Run(ctx, cmd, outputs.COLLECT, temporal.GetConnectionTimeout(), temporal.GetExecutionTimeout())
+	if xerr != nil {
+		return fail.Wrap(xerr, msg)
+	}
+	if retcode != 0 {
+		xerr := fail.ExecutionError(nil, msg)
+		_ = xerr.Annotate("retcode", retcode)
+		_ = xerr.Annotate("stdout", stdout)
+		_ = xerr.Annotate("stderr", stderr)
+		return xerr
+	}
+	return nil
+}
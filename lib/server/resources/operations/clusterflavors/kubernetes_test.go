@@ -0,0 +1,52 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package clusterflavors
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseKubectlGetNodes(t *testing.T) {
+	out := `k8s-master-1   Ready                      control-plane,master   12d   v1.21.2
+k8s-node-1     Ready                      <none>                 12d   v1.21.2
+k8s-node-2     NotReady                   <none>                 12d   v1.21.2
+k8s-node-3     Ready,SchedulingDisabled   <none>                 2h    v1.21.2
+
+`
+	readiness := parseKubectlGetNodes(out)
+	assert.Equal(t, map[string]bool{
+		"k8s-master-1": true,
+		"k8s-node-1":   true,
+		"k8s-node-2":   false,
+		"k8s-node-3":   true,
+	}, readiness)
+
+	assert.Empty(t, parseKubectlGetNodes(""))
+}
+
+func Test_shellQuote(t *testing.T) {
+	assert.Equal(t, "'tier=front'", shellQuote("tier=front"))
+	assert.Equal(t, `'a'\''; reboot; '\'''`, shellQuote("a'; reboot; '"))
+	assert.Equal(t, "''", shellQuote(""))
+	out, err := exec.Command("sh", "-c", "printf %s "+shellQuote("x'$(id)`id`\"y")).Output()
+	if assert.Nil(t, err) {
+		assert.Equal(t, "x'$(id)`id`\"y", string(out))
+	}
+}
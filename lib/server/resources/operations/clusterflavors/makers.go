@@ -53,6 +53,13 @@ type Makers struct {
 	JoinNodeToCluster      func(c resources.Cluster, host resources.Host) fail.Error
 	LeaveMasterFromCluster func(c resources.Cluster, host resources.Host) fail.Error
 	LeaveNodeFromCluster   func(ctx context.Context, c resources.Cluster, host resources.Host, selectedMaster resources.Host) fail.Error
+	LabelNode              func(ctx context.Context, c resources.Cluster, host resources.Host, selectedMaster resources.Host, labels map[string]string, taints []string) fail.Error
+	DrainNode              func(ctx context.Context, c resources.Cluster, host resources.Host, selectedMaster resources.Host) fail.Error
+	UncordonNode           func(ctx context.Context, c resources.Cluster, host resources.Host, selectedMaster resources.Host) fail.Error
+	SnapshotControlPlane   func(ctx context.Context, c resources.Cluster, master resources.Host, path string) fail.Error               // saves in the archive 'path' on master the state of the control plane
+	RestoreControlPlane    func(ctx context.Context, c resources.Cluster, masters []resources.Host, path string) fail.Error            // restores on masters the state of the control plane from the archive 'path' present on each of them
+	NodesReadiness         func(ctx context.Context, c resources.Cluster, selectedMaster resources.Host) (map[string]bool, fail.Error) // returns the readiness of the nodes known by the flavor, indexed by name
+	ForgetNode             func(ctx context.Context, c resources.Cluster, nodeName string, selectedMaster resources.Host) fail.Error   // removes from the flavor a node that cannot leave the Cluster by itself
 	GetState               func(c resources.Cluster) (clusterstate.Enum, fail.Error)
 }
 
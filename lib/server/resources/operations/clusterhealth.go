@@ -0,0 +1,808 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server"
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/resources"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterproperty"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterstate"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/hostproperty"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/hoststate"
+	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
+	propertiesv2 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v2"
+	propertiesv3 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v3"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/data/serialize"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+// evaluateNodeHealth returns the health of a node from its previous health and the result of the last check
+// 'reason' is empty if the node passed the check; the node is considered as failed after 'threshold' consecutive failed checks
+func evaluateNodeHealth(previous *propertiesv3.ClusterNodeHealth, reason string, threshold uint, now time.Time) *propertiesv3.ClusterNodeHealth {
+	health := &propertiesv3.ClusterNodeHealth{Status: propertiesv3.NodeHealthy, LastCheck: now, Since: now}
+	if reason != "" {
+		health.Reason = reason
+		health.Failures = 1
+		if previous != nil {
+			health.Failures += previous.Failures
+		}
+		health.Status = propertiesv3.NodeDegraded
+		if threshold > 0 && health.Failures >= threshold {
+			health.Status = propertiesv3.NodeFailed
+		}
+	}
+	if previous != nil && previous.Status == health.Status {
+		health.Since = previous.Since
+	}
+	return health
+}
+
+// healthTarget contains what is needed to check the health of a Cluster
+type healthTarget struct {
+	state    clusterstate.Enum
+	settings *propertiesv1.ClusterHealth
+	masters  []*propertiesv3.ClusterNode
+	nodes    []*propertiesv3.ClusterNode
+}
+
+// GetHealth returns the health check settings of the Cluster with its last repairs, and its masters and nodes with their health
+func (instance *Cluster) GetHealth(ctx context.Context) (_ *propertiesv1.ClusterHealth, _ *propertiesv3.ClusterNodes, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	instance.lock.RLock()
+	defer instance.lock.RUnlock()
+
+	var (
+		settings *propertiesv1.ClusterHealth
+		nodes    *propertiesv3.ClusterNodes
+	)
+	xerr = instance.Review(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		innerXErr := props.Inspect(clusterproperty.HealthV1, func(clonable data.Clonable) fail.Error {
+			healthV1, ok := clonable.(*propertiesv1.ClusterHealth)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterHealth' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			settings = healthV1.Clone().(*propertiesv1.ClusterHealth)
+			return nil
+		})
+		if innerXErr != nil {
+			return innerXErr
+		}
+
+		return props.Inspect(clusterproperty.NodesV3, func(clonable data.Clonable) fail.Error {
+			nodesV3, ok := clonable.(*propertiesv3.ClusterNodes)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv3.ClusterNodes' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			nodes = nodesV3.Clone().(*propertiesv3.ClusterNodes)
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, nil, xerr
+	}
+
+	return settings, nodes, nil
+}
+
+// SetHealth replaces the health check settings of the Cluster; the history of repairs is kept
+func (instance *Cluster) SetHealth(ctx context.Context, settings propertiesv1.ClusterHealth) (_ *propertiesv1.ClusterHealth, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if settings.FailureThreshold == 0 {
+		settings.FailureThreshold = propertiesv1.DefaultClusterHealthFailureThreshold
+	}
+
+	// make sure no other parallel actions interferes
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	xerr = instance.beingRemoved()
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	var out *propertiesv1.ClusterHealth
+	xerr = instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Alter(clusterproperty.HealthV1, func(clonable data.Clonable) fail.Error {
+			healthV1, ok := clonable.(*propertiesv1.ClusterHealth)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterHealth' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			settings.LastCheck = healthV1.LastCheck
+			settings.Repairs = healthV1.Repairs
+			_ = healthV1.Replace(&settings)
+			out = healthV1.Clone().(*propertiesv1.ClusterHealth)
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return out, nil
+}
+
+// CheckHealth checks the masters and the nodes of the Cluster: the host must exist and be started, must be reachable
+// using SSH and, if the flavor supports it, must be ready in the flavor (like a Kubernetes node).
+// The health of each host is recorded in Cluster metadata and the state of the Cluster switches between Nominal and Degraded.
+// If auto repair is enabled, the nodes considered as failed are replaced (masters are never replaced automatically), one
+// per check at most; repairs are skipped when no gateway is reachable or when most of the nodes fail at once.
+// Returns the repairs done; returns nil if the Cluster has not been checked because it is not running
+func (instance *Cluster) CheckHealth(ctx context.Context) (_ []*propertiesv1.ClusterNodeRepair, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	task, xerr := taskFromContextOrVoid(ctx)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.cluster"), "('%s')", instance.GetName()).Entering()
+	defer tracer.Exiting()
+
+	target, xerr := instance.inspectHealthTarget()
+	if xerr != nil {
+		return nil, xerr
+	}
+	if target.state != clusterstate.Nominal && target.state != clusterstate.Degraded {
+		logrus.Debugf("[cluster %s] health not checked, state is '%s'", instance.GetName(), target.state.String())
+		return nil, nil
+	}
+
+	readiness := instance.collectReadiness(ctx)
+	hosts := append(append([]*propertiesv3.ClusterNode{}, target.masters...), target.nodes...)
+	reasons := make(map[uint]string, len(hosts))
+	var (
+		mutex sync.Mutex
+		wg    sync.WaitGroup
+	)
+	for _, v := range hosts {
+		wg.Add(1)
+		go func(node *propertiesv3.ClusterNode) {
+			defer wg.Done()
+
+			reason := instance.checkNodeHealth(ctx, node, readiness)
+			mutex.Lock()
+			reasons[node.NumericalID] = reason
+			mutex.Unlock()
+		}(v)
+	}
+	wg.Wait()
+
+	if task.Aborted() {
+		return nil, fail.AbortedError(nil, "aborted")
+	}
+
+	failed, xerr := instance.recordHealth(reasons, target.settings.FailureThreshold, time.Now())
+	if xerr != nil {
+		return nil, xerr
+	}
+	if !target.settings.AutoRepair || len(failed) == 0 {
+		return []*propertiesv1.ClusterNodeRepair{}, nil
+	}
+
+	failing := 0
+	for _, v := range target.nodes {
+		if reasons[v.NumericalID] != "" {
+			failing++
+		}
+	}
+	failed, skipped := selectRepairs(failed, failing, len(target.nodes))
+	if skipped == "" {
+		skipped = instance.checkGatewaysHealth(ctx)
+	}
+	if skipped != "" {
+		logrus.Warnf("[cluster %s] repair of failed nodes skipped: %s", instance.GetName(), skipped)
+		return []*propertiesv1.ClusterNodeRepair{}, nil
+	}
+
+	// an upgrade or an autoscaling of the Cluster may have begun while the health was checked
+	xerr = instance.beginActivity("repaired")
+	if xerr != nil {
+		logrus.Debugf("[cluster %s] failed nodes not replaced: %v", instance.GetName(), xerr)
+		return []*propertiesv1.ClusterNodeRepair{}, nil
+	}
+	defer instance.endActivity()
+
+	// failed nodes are replaced one after the other, to keep the Cluster usable during the repairs
+	var errors []error
+	repairs := make([]*propertiesv1.ClusterNodeRepair, 0, len(failed))
+	for _, v := range failed {
+		if task.Aborted() {
+			errors = append(errors, fail.AbortedError(nil, "aborted"))
+			break
+		}
+
+		repair := propertiesv1.ClusterNodeRepair{Time: time.Now(), Node: v.Name, Reason: reasons[v.NumericalID]}
+		logrus.Infof("[cluster %s] replacing failed node '%s': %s", instance.GetName(), v.Name, repair.Reason)
+		server.ReportProgress(ctx, server.ProgressEvent{Phase: "repair", Step: fmt.Sprintf("replacing node '%s'", v.Name), Host: v.Name})
+		repair.Replacement, xerr = instance.replaceNode(ctx, v)
+		if xerr != nil {
+			repair.Error = xerr.Error()
+			errors = append(errors, fail.Wrap(xerr, "failed to replace node '%s'", v.Name))
+		}
+
+		derr := instance.recordNodeRepair(repair)
+		if derr != nil {
+			errors = append(errors, derr)
+		}
+		repairs = append(repairs, &repair)
+	}
+	if len(errors) > 0 {
+		return repairs, fail.NewErrorList(errors)
+	}
+
+	return repairs, nil
+}
+
+// maxRepairsPerCheck is the number of failed nodes replaced at most by a health check; the other ones are replaced by the
+// next checks, so that a wrong diagnosis cannot destroy many nodes at once
+const maxRepairsPerCheck = 1
+
+// selectRepairs returns the failed nodes to replace, at most maxRepairsPerCheck of them, or why the repairs are skipped
+// 'failing' is the number of nodes failing the last check among 'total': when most of them fail at once, the cause is
+// more likely outside of the nodes (network, provider, ...) and replacing them would not help
+func selectRepairs(failed []*propertiesv3.ClusterNode, failing, total int) ([]*propertiesv3.ClusterNode, string) {
+	if total > 1 && failing*2 > total {
+		return nil, fmt.Sprintf("%d of the %d nodes failed the check at once", failing, total)
+	}
+	if len(failed) > maxRepairsPerCheck {
+		failed = failed[:maxRepairsPerCheck]
+	}
+	return failed, ""
+}
+
+// checkGatewaysHealth checks that at least one gateway of the Cluster is reachable; returns why none is, or an empty string
+// The nodes being reached through the gateways, the nodes cannot be told failed when no gateway responds.
+func (instance *Cluster) checkGatewaysHealth(ctx context.Context) string {
+	netCfg, xerr := instance.GetNetworkConfig()
+	if xerr != nil {
+		return fmt.Sprintf("failed to get network configuration: %v", xerr)
+	}
+
+	var reasons []string
+	for _, id := range []string{netCfg.GatewayID, netCfg.SecondaryGatewayID} {
+		if id == "" {
+			continue
+		}
+		reason := instance.checkNodeHealth(ctx, &propertiesv3.ClusterNode{ID: id, Name: id}, nil)
+		if reason == "" {
+			return ""
+		}
+		reasons = append(reasons, fmt.Sprintf("gateway '%s': %s", id, reason))
+	}
+	if len(reasons) == 0 {
+		return "no gateway found"
+	}
+	return strings.Join(reasons, ", ")
+}
+
+// inspectHealthTarget returns the last known state of the Cluster, its health check settings, its masters and its nodes
+func (instance *Cluster) inspectHealthTarget() (target healthTarget, xerr fail.Error) {
+	instance.lock.RLock()
+	defer instance.lock.RUnlock()
+
+	xerr = instance.Review(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		innerXErr := props.Inspect(clusterproperty.StateV1, func(clonable data.Clonable) fail.Error {
+			stateV1, ok := clonable.(*propertiesv1.ClusterState)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterState' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			target.state = stateV1.State
+			return nil
+		})
+		if innerXErr != nil {
+			return innerXErr
+		}
+
+		innerXErr = props.Inspect(clusterproperty.HealthV1, func(clonable data.Clonable) fail.Error {
+			healthV1, ok := clonable.(*propertiesv1.ClusterHealth)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterHealth' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			target.settings = healthV1.Clone().(*propertiesv1.ClusterHealth)
+			return nil
+		})
+		if innerXErr != nil {
+			return innerXErr
+		}
+
+		return props.Inspect(clusterproperty.NodesV3, func(clonable data.Clonable) fail.Error {
+			nodesV3, ok := clonable.(*propertiesv3.ClusterNodes)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv3.ClusterNodes' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			for _, v := range nodesV3.Masters {
+				if node, ok := nodesV3.ByNumericalID[v]; ok {
+					item := *node
+					target.masters = append(target.masters, &item)
+				}
+			}
+			for _, v := range nodesV3.PrivateNodes {
+				if node, ok := nodesV3.ByNumericalID[v]; ok {
+					item := *node
+					target.nodes = append(target.nodes, &item)
+				}
+			}
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return healthTarget{state: clusterstate.Unknown}, xerr
+	}
+
+	return target, nil
+}
+
+// collectReadiness returns the readiness of the nodes as known by the flavor, or nil if the flavor cannot tell it
+func (instance *Cluster) collectReadiness(ctx context.Context) map[string]bool {
+	if instance.makers.NodesReadiness == nil {
+		return nil
+	}
+
+	master, xerr := instance.FindAvailableMaster(ctx)
+	if xerr != nil {
+		logrus.Warnf("[cluster %s] readiness of nodes not checked, no master available: %v", instance.GetName(), xerr)
+		return nil
+	}
+	defer master.Released()
+
+	readiness, xerr := instance.makers.NodesReadiness(ctx, instance, master)
+	if xerr != nil {
+		logrus.Warnf("[cluster %s] readiness of nodes not checked: %v", instance.GetName(), xerr)
+		return nil
+	}
+	return readiness
+}
+
+// checkNodeHealth checks the health of a master or a node; returns why the check failed, or an empty string if the check passed
+func (instance *Cluster) checkNodeHealth(ctx context.Context, node *propertiesv3.ClusterNode, readiness map[string]bool) string {
+	hostInstance, xerr := LoadHost(instance.GetService(), node.ID)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			return "host not found"
+		default:
+			return fmt.Sprintf("failed to load host: %v", xerr)
+		}
+	}
+	defer hostInstance.Released()
+
+	state, xerr := hostInstance.ForceGetState(ctx)
+	if xerr != nil {
+		return fmt.Sprintf("failed to get state of host: %v", xerr)
+	}
+	if state != hoststate.Started {
+		return fmt.Sprintf("host is in state '%s'", state.String())
+	}
+
+	_, xerr = hostInstance.WaitSSHReady(ctx, temporal.GetConnectionTimeout())
+	if xerr != nil {
+		return fmt.Sprintf("host is unreachable using SSH: %v", xerr)
+	}
+
+	if readiness != nil {
+		ready, ok := readiness[node.Name]
+		if !ok {
+			return "host is not registered in the Cluster"
+		}
+		if !ready {
+			return "host is not ready in the Cluster"
+		}
+	}
+	return ""
+}
+
+// recordHealth records in Cluster metadata the health of the checked hosts and updates the state of the Cluster
+// Returns the nodes (not the masters) considered as failed
+func (instance *Cluster) recordHealth(reasons map[uint]string, threshold uint, now time.Time) (failed []*propertiesv3.ClusterNode, xerr fail.Error) {
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	xerr = instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		healthy := true
+		innerXErr := props.Alter(clusterproperty.NodesV3, func(clonable data.Clonable) fail.Error {
+			nodesV3, ok := clonable.(*propertiesv3.ClusterNodes)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv3.ClusterNodes' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			if nodesV3.Health == nil {
+				nodesV3.Health = map[uint]*propertiesv3.ClusterNodeHealth{}
+			}
+			for k, v := range reasons {
+				node, ok := nodesV3.ByNumericalID[k]
+				if !ok {
+					// removed during the check
+					continue
+				}
+
+				health := evaluateNodeHealth(nodesV3.Health[k], v, threshold, now)
+				if health.Status != propertiesv3.NodeHealthy {
+					healthy = false
+				}
+				if previous, ok := nodesV3.Health[k]; (!ok || previous.Status != health.Status) && health.Status != propertiesv3.NodeHealthy {
+					logrus.Warnf("[cluster %s] host '%s' is %s: %s", instance.GetName(), node.Name, health.Status, health.Reason)
+				}
+				nodesV3.Health[k] = health
+
+				if _, ok := nodesV3.PrivateNodeByID[node.ID]; ok && health.Status == propertiesv3.NodeFailed {
+					item := *node
+					failed = append(failed, &item)
+				}
+			}
+			return nil
+		})
+		if innerXErr != nil {
+			return innerXErr
+		}
+
+		innerXErr = props.Alter(clusterproperty.StateV1, func(clonable data.Clonable) fail.Error {
+			stateV1, ok := clonable.(*propertiesv1.ClusterState)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterState' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			switch {
+			case !healthy && stateV1.State == clusterstate.Nominal:
+				stateV1.State = clusterstate.Degraded
+			case healthy && stateV1.State == clusterstate.Degraded:
+				stateV1.State = clusterstate.Nominal
+			}
+			return nil
+		})
+		if innerXErr != nil {
+			return innerXErr
+		}
+
+		return props.Alter(clusterproperty.HealthV1, func(clonable data.Clonable) fail.Error {
+			healthV1, ok := clonable.(*propertiesv1.ClusterHealth)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterHealth' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			healthV1.LastCheck = now
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	sort.Slice(failed, func(i, j int) bool { return failed[i].NumericalID < failed[j].NumericalID })
+	return failed, nil
+}
+
+// replaceNode deletes a failed node then creates a new one with the same sizing, in the same node pool if the failed node
+// belongs to one, and adds again the features installed on the Cluster
+// Returns the name of the new node
+func (instance *Cluster) replaceNode(ctx context.Context, node *propertiesv3.ClusterNode) (string, fail.Error) {
+	var poolName string
+	instance.lock.RLock()
+	xerr := instance.Review(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Inspect(clusterproperty.NodePoolsV1, func(clonable data.Clonable) fail.Error {
+			poolsV1, ok := clonable.(*propertiesv1.ClusterNodePools)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterNodePools' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			poolName = poolsV1.PoolOf(node.NumericalID)
+			return nil
+		})
+	})
+	instance.lock.RUnlock()
+	if xerr != nil {
+		return "", xerr
+	}
+
+	var (
+		count  uint
+		sizing abstract.HostSizingRequirements
+	)
+	if poolName != "" {
+		instance.lock.RLock()
+		pool, xerr := instance.unsafeInspectNodePool(poolName)
+		instance.lock.RUnlock()
+		if xerr != nil {
+			return "", xerr
+		}
+		count = uint(len(pool.Nodes))
+	} else {
+		// the sizing of the node is recorded before its deletion, to create its replacement alike
+		sizing, xerr = instance.nodeSizing(node)
+		if xerr != nil {
+			switch xerr.(type) {
+			case *fail.ErrNotFound:
+				logrus.Warnf("failed to find the sizing of node '%s', its replacement uses the default node sizing of the cluster: %v", node.Name, xerr)
+				debug.IgnoreError(xerr)
+			default:
+				return "", xerr
+			}
+		}
+	}
+
+	xerr = instance.DeleteSpecificNode(ctx, node.ID, "")
+	if xerr != nil {
+		return "", fail.Wrap(xerr, "failed to delete node")
+	}
+
+	var replacement string
+	if poolName != "" {
+		// the node pool is resized back to its previous count of nodes, the new node being the one not known before
+		instance.lock.RLock()
+		before, xerr := instance.unsafeInspectNodePool(poolName)
+		instance.lock.RUnlock()
+		if xerr != nil {
+			return "", xerr
+		}
+
+		after, xerr := instance.ResizeNodePool(ctx, poolName, count, false)
+		if xerr != nil {
+			return "", fail.Wrap(xerr, "failed to add a node to node pool '%s'", poolName)
+		}
+
+		replacement, xerr = instance.findNewNode(before.Nodes, after.Nodes)
+		if xerr != nil {
+			return "", xerr
+		}
+	} else {
+		hostInstance, xerr := instance.AddNode(ctx, sizing, false)
+		if xerr != nil {
+			return "", fail.Wrap(xerr, "failed to add a node")
+		}
+		replacement = hostInstance.GetName()
+		hostInstance.Released()
+	}
+
+	return replacement, instance.addInstalledFeatures(ctx)
+}
+
+// nodeSizing returns the sizing requested for a node, with the template used to create it
+func (instance *Cluster) nodeSizing(node *propertiesv3.ClusterNode) (abstract.HostSizingRequirements, fail.Error) {
+	var out abstract.HostSizingRequirements
+	hostInstance, xerr := LoadHost(instance.GetService(), node.ID)
+	if xerr != nil {
+		return out, xerr
+	}
+	defer hostInstance.Released()
+
+	xerr = hostInstance.Inspect(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Inspect(hostproperty.SizingV2, func(clonable data.Clonable) fail.Error {
+			sizingV2, ok := clonable.(*propertiesv2.HostSizing)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv2.HostSizing' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			if sizingV2.RequestedSize != nil {
+				out = abstract.HostSizingRequirements{
+					MinCores:    sizingV2.RequestedSize.MinCores,
+					MaxCores:    sizingV2.RequestedSize.MaxCores,
+					MinRAMSize:  sizingV2.RequestedSize.MinRAMSize,
+					MaxRAMSize:  sizingV2.RequestedSize.MaxRAMSize,
+					MinDiskSize: sizingV2.RequestedSize.MinDiskSize,
+					MinGPU:      sizingV2.RequestedSize.MinGPU,
+					MinCPUFreq:  sizingV2.RequestedSize.MinCPUFreq,
+					Replaceable: sizingV2.RequestedSize.Replaceable,
+				}
+			}
+			out.Template = sizingV2.Template
+			return nil
+		})
+	})
+	return out, xerr
+}
+
+// findNewNode returns the name of the node present in 'after' but not in 'before'
+func (instance *Cluster) findNewNode(before, after []uint) (string, fail.Error) {
+	known := make(map[uint]struct{}, len(before))
+	for _, v := range before {
+		known[v] = struct{}{}
+	}
+
+	var name string
+	xerr := instance.Review(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Inspect(clusterproperty.NodesV3, func(clonable data.Clonable) fail.Error {
+			nodesV3, ok := clonable.(*propertiesv3.ClusterNodes)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv3.ClusterNodes' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			for _, v := range after {
+				if _, ok := known[v]; ok {
+					continue
+				}
+				if node, ok := nodesV3.ByNumericalID[v]; ok {
+					name = node.Name
+					return nil
+				}
+			}
+			return fail.NotFoundError("failed to find the new node")
+		})
+	})
+	return name, xerr
+}
+
+// addInstalledFeatures adds again the features installed on the Cluster, with their parameters, to install them on new nodes
+func (instance *Cluster) addInstalledFeatures(ctx context.Context) fail.Error {
+	features := map[string]data.Map{}
+	xerr := instance.Review(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Inspect(clusterproperty.FeaturesV1, func(clonable data.Clonable) fail.Error {
+			featuresV1, ok := clonable.(*propertiesv1.ClusterFeatures)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterFeatures' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			for k, v := range featuresV1.Installed {
+				vars := data.Map{}
+				for pk, pv := range v.Parameters {
+					vars[pk] = pv
+				}
+				features[k] = vars
+			}
+			return nil
+		})
+	})
+	if xerr != nil {
+		return xerr
+	}
+
+	// features are added again in a stable order; their requirements are added with them
+	names := make([]string, 0, len(features))
+	for k := range features {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, v := range names {
+		results, xerr := instance.AddFeature(ctx, v, features[v], resources.FeatureSettings{})
+		if xerr != nil {
+			return fail.Wrap(xerr, "failed to add feature '%s'", v)
+		}
+		if !results.Successful() {
+			return fail.NewError("failed to add feature '%s': %s", v, results.AllErrorMessages())
+		}
+	}
+	return nil
+}
+
+// recordNodeRepair records the repair in Cluster metadata
+func (instance *Cluster) recordNodeRepair(repair propertiesv1.ClusterNodeRepair) fail.Error {
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	xerr := instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Alter(clusterproperty.HealthV1, func(clonable data.Clonable) fail.Error {
+			healthV1, ok := clonable.(*propertiesv1.ClusterHealth)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterHealth' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			healthV1.Record(repair)
+			return nil
+		})
+	})
+	return debug.InjectPlannedFail(xerr)
+}
+
+// CheckClustersHealth runs CheckHealth on the Clusters of the tenant, in parallel
+// Returns when all the checks and repairs have ended
+func CheckClustersHealth(ctx context.Context, svc iaas.Service) fail.Error {
+	if ctx == nil {
+		return fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if svc == nil {
+		return fail.InvalidParameterCannotBeNilError("svc")
+	}
+
+	browser, xerr := NewCluster(svc)
+	if xerr != nil {
+		return xerr
+	}
+
+	var names []string
+	xerr = browser.Browse(ctx, func(identity *abstract.ClusterIdentity) fail.Error {
+		names = append(names, identity.Name)
+		return nil
+	})
+	if xerr != nil {
+		return xerr
+	}
+
+	var (
+		mutex  sync.Mutex
+		wg     sync.WaitGroup
+		errors []error
+	)
+	for _, v := range names {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+
+			xerr := checkClusterHealth(ctx, svc, name)
+			if xerr != nil {
+				mutex.Lock()
+				errors = append(errors, fail.Wrap(xerr, "failed to check health of Cluster '%s'", name))
+				mutex.Unlock()
+			}
+		}(v)
+	}
+	wg.Wait()
+
+	if len(errors) > 0 {
+		return fail.NewErrorList(errors)
+	}
+	return nil
+}
+
+func checkClusterHealth(ctx context.Context, svc iaas.Service, name string) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	clusterInstance, xerr := LoadCluster(svc, name)
+	if xerr != nil {
+		return xerr
+	}
+	defer clusterInstance.Released()
+
+	_, xerr = clusterInstance.CheckHealth(ctx)
+	return xerr
+}
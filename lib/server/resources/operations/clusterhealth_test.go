@@ -0,0 +1,201 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clustercomplexity"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterflavor"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterproperty"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterstate"
+	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
+	propertiesv3 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v3"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/data/serialize"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+func Test_evaluateNodeHealth(t *testing.T) {
+	start := time.Now()
+	health := evaluateNodeHealth(nil, "", 3, start)
+	assert.Equal(t, propertiesv3.NodeHealthy, health.Status)
+	assert.EqualValues(t, 0, health.Failures)
+	assert.Equal(t, start, health.Since)
+
+	later := start.Add(5 * time.Minute)
+	health = evaluateNodeHealth(health, "host is in state 'Terminated'", 3, later)
+	assert.Equal(t, propertiesv3.NodeDegraded, health.Status)
+	assert.EqualValues(t, 1, health.Failures)
+	assert.Equal(t, later, health.Since)
+
+	health = evaluateNodeHealth(health, "host is in state 'Terminated'", 3, later.Add(5*time.Minute))
+	assert.Equal(t, propertiesv3.NodeDegraded, health.Status)
+	assert.Equal(t, later, health.Since)
+
+	health = evaluateNodeHealth(health, "host not found", 3, later.Add(10*time.Minute))
+	assert.Equal(t, propertiesv3.NodeFailed, health.Status)
+	assert.EqualValues(t, 3, health.Failures)
+	assert.Equal(t, "host not found", health.Reason)
+
+	health = evaluateNodeHealth(health, "", 3, later.Add(15*time.Minute))
+	assert.Equal(t, propertiesv3.NodeHealthy, health.Status)
+	assert.EqualValues(t, 0, health.Failures)
+	assert.Empty(t, health.Reason)
+}
+
+func Test_selectRepairs(t *testing.T) {
+	failed := []*propertiesv3.ClusterNode{{Name: "node-1"}, {Name: "node-2"}}
+
+	// repairs are capped per check
+	out, skipped := selectRepairs(failed, 2, 5)
+	assert.Empty(t, skipped)
+	require.Len(t, out, maxRepairsPerCheck)
+	assert.Equal(t, "node-1", out[0].Name)
+
+	// most nodes failing at once: no repair
+	out, skipped = selectRepairs(failed, 3, 5)
+	assert.Empty(t, out)
+	assert.Equal(t, "3 of the 5 nodes failed the check at once", skipped)
+
+	// a Cluster with a single node can still have it replaced
+	out, skipped = selectRepairs(failed[:1], 1, 1)
+	assert.Empty(t, skipped)
+	assert.Len(t, out, 1)
+}
+
+func Test_cluster_Health(t *testing.T) {
+	svc := getMemoryService(t)
+	ctx := context.Background()
+
+	instance, xerr := NewCluster(svc)
+	require.Nil(t, xerr)
+	req := abstract.ClusterRequest{Name: "checked", Flavor: clusterflavor.BOH, Complexity: clustercomplexity.Small, InitialNodeCount: 1}
+	require.Nil(t, instance.firstLight(req))
+
+	settings, nodes, xerr := instance.GetHealth(ctx)
+	require.Nil(t, xerr)
+	assert.False(t, settings.AutoRepair)
+	assert.EqualValues(t, propertiesv1.DefaultClusterHealthFailureThreshold, settings.FailureThreshold)
+	assert.Empty(t, nodes.Health)
+
+	// a Cluster not running is not checked
+	repairs, xerr := instance.CheckHealth(ctx)
+	require.Nil(t, xerr)
+	assert.Nil(t, repairs)
+
+	xerr = instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		innerXErr := props.Alter(clusterproperty.StateV1, func(clonable data.Clonable) fail.Error {
+			stateV1, ok := clonable.(*propertiesv1.ClusterState)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterState' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+			stateV1.State = clusterstate.Nominal
+			return nil
+		})
+		if innerXErr != nil {
+			return innerXErr
+		}
+
+		return props.Alter(clusterproperty.NodesV3, func(clonable data.Clonable) fail.Error {
+			nodesV3, ok := clonable.(*propertiesv3.ClusterNodes)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv3.ClusterNodes' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+			nodesV3.Masters = append(nodesV3.Masters, 11)
+			nodesV3.MasterByID["master-id"] = 11
+			nodesV3.ByNumericalID[11] = &propertiesv3.ClusterNode{ID: "master-id", NumericalID: 11, Name: "checked-master-1"}
+			nodesV3.PrivateNodes = append(nodesV3.PrivateNodes, 12)
+			nodesV3.PrivateNodeByID["node-id"] = 12
+			nodesV3.ByNumericalID[12] = &propertiesv3.ClusterNode{ID: "node-id", NumericalID: 12, Name: "checked-node-1"}
+			return nil
+		})
+	})
+	require.Nil(t, xerr)
+
+	settings, xerr = instance.SetHealth(ctx, propertiesv1.ClusterHealth{AutoRepair: true, FailureThreshold: 2})
+	require.Nil(t, xerr)
+	assert.True(t, settings.AutoRepair)
+
+	// the failures of the masters and nodes degrade the Cluster; only nodes are failed and may be replaced
+	reasons := map[uint]string{11: "host is unreachable using SSH", 12: "host not found"}
+	failed, xerr := instance.recordHealth(reasons, settings.FailureThreshold, time.Now())
+	require.Nil(t, xerr)
+	assert.Empty(t, failed)
+	state, xerr := instance.GetState()
+	require.Nil(t, xerr)
+	assert.Equal(t, clusterstate.Degraded, state)
+
+	failed, xerr = instance.recordHealth(reasons, settings.FailureThreshold, time.Now())
+	require.Nil(t, xerr)
+	require.Len(t, failed, 1)
+	assert.Equal(t, "checked-node-1", failed[0].Name)
+
+	_, nodes, xerr = instance.GetHealth(ctx)
+	require.Nil(t, xerr)
+	require.Contains(t, nodes.Health, uint(11))
+	assert.Equal(t, propertiesv3.NodeFailed, nodes.Health[11].Status)
+	assert.Equal(t, propertiesv3.NodeFailed, nodes.Health[12].Status)
+
+	failed, xerr = instance.recordHealth(map[uint]string{11: "", 12: ""}, settings.FailureThreshold, time.Now())
+	require.Nil(t, xerr)
+	assert.Empty(t, failed)
+	state, xerr = instance.GetState()
+	require.Nil(t, xerr)
+	assert.Equal(t, clusterstate.Nominal, state)
+
+	require.Nil(t, instance.recordNodeRepair(propertiesv1.ClusterNodeRepair{Time: time.Now(), Node: "checked-node-1", Replacement: "checked-node-2"}))
+	settings, xerr = instance.SetHealth(ctx, propertiesv1.ClusterHealth{})
+	require.Nil(t, xerr)
+	assert.False(t, settings.AutoRepair)
+	assert.EqualValues(t, propertiesv1.DefaultClusterHealthFailureThreshold, settings.FailureThreshold)
+	assert.False(t, settings.LastCheck.IsZero())
+	require.Len(t, settings.Repairs, 1)
+}
+
+func Test_cluster_nodeSizing(t *testing.T) {
+	svc := getMemoryService(t)
+	ctx := context.Background()
+
+	hostInstance, xerr := NewHost(svc)
+	require.Nil(t, xerr)
+	hostReq := abstract.HostRequest{ResourceName: "sized-node-1", HostName: "sized-node-1", Single: true}
+	_, xerr = hostInstance.Create(ctx, hostReq, abstract.HostSizingRequirements{MinCores: 8, MaxCores: 16, MinRAMSize: 32, MinDiskSize: 100, MinGPU: -1})
+	require.Nil(t, xerr)
+	id := hostInstance.GetID()
+	hostInstance.Released()
+
+	instance, xerr := NewCluster(svc)
+	require.Nil(t, xerr)
+	sizing, xerr := instance.nodeSizing(&propertiesv3.ClusterNode{ID: id, Name: "sized-node-1"})
+	require.Nil(t, xerr)
+	assert.Equal(t, 8, sizing.MinCores)
+	assert.Equal(t, 16, sizing.MaxCores)
+	assert.EqualValues(t, 32, sizing.MinRAMSize)
+	assert.Equal(t, 100, sizing.MinDiskSize)
+	assert.NotEmpty(t, sizing.Template)
+
+	_, xerr = instance.nodeSizing(&propertiesv3.ClusterNode{ID: "unknown", Name: "lost-node"})
+	assert.IsType(t, &fail.ErrNotFound{}, xerr)
+}
@@ -126,6 +126,35 @@ func (instance *Cluster) InstalledFeatures() []string {
 	return out
 }
 
+// InstalledFeatureVersion returns the version of the installed Feature recorded in metadata, empty string if unknown
+// satisfies interface resources.Targetable
+func (instance *Cluster) InstalledFeatureVersion(feat string) string {
+	if instance == nil || feat == "" {
+		return ""
+	}
+
+	var out string
+	xerr := instance.Review(func(clonable data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Inspect(clusterproperty.FeaturesV1, func(clonable data.Clonable) fail.Error {
+			featuresV1, ok := clonable.(*propertiesv1.ClusterFeatures)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterFeatures' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			if item, ok := featuresV1.Installed[feat]; ok && item != nil {
+				out = item.Version
+			}
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		logrus.Error(xerr.Error())
+		return ""
+	}
+	return out
+}
+
 // ComplementFeatureParameters configures parameters that are implicitly defined, based on target
 // satisfies interface resources.Targetable
 func (instance *Cluster) ComplementFeatureParameters(ctx context.Context, v data.Map) fail.Error {
@@ -266,6 +295,12 @@ func (instance *Cluster) RegisterFeature(feat resources.Feature, requiredBy reso
 		return fail.InvalidParameterError("feat", "cannot be null value of 'resources.Feature'")
 	}
 
+	// requirements may change with the version, so they are refreshed on upgrade too
+	requirements, xerr := feat.GetRequirements()
+	if xerr != nil {
+		return xerr
+	}
+
 	return instance.Alter(func(clonable data.Clonable, props *serialize.JSONProperties) fail.Error {
 		return props.Alter(clusterproperty.FeaturesV1, func(clonable data.Clonable) fail.Error {
 			featuresV1, ok := clonable.(*propertiesv1.ClusterFeatures)
@@ -275,17 +310,13 @@ func (instance *Cluster) RegisterFeature(feat resources.Feature, requiredBy reso
 
 			var item *propertiesv1.ClusterInstalledFeature
 			if item, ok = featuresV1.Installed[feat.GetName()]; !ok {
-				requirements, innerXErr := feat.GetRequirements()
-				if innerXErr != nil {
-					return innerXErr
-				}
-
 				item = propertiesv1.NewClusterInstalledFeature()
 				item.Name = feat.GetName()
-				item.FileName = feat.GetDisplayFilename()
-				item.Requires = requirements
 				featuresV1.Installed[item.Name] = item
 			}
+			item.FileName = feat.GetDisplayFilename()
+			item.Requires = requirements
+			item.Version = feat.GetVersion()
 			if rf, ok := requiredBy.(*Feature); ok && rf != nil && !rf.IsNull() {
 				item.RequiredBy[rf.GetName()] = struct{}{}
 			}
@@ -383,7 +414,40 @@ func (instance *Cluster) AddFeature(ctx context.Context, name string, vars data.
 		return nil, xerr
 	}
 
-	return feat.Add(ctx, instance, vars, settings)
+	results, xerr := feat.Add(ctx, instance, vars, settings)
+	if xerr != nil {
+		return nil, xerr
+	}
+	if results.Successful() {
+		// Parameters are kept to be able to install the feature again, when restoring a backup of the Cluster
+		xerr = instance.recordFeatureParameters(feat.GetName(), vars)
+		if xerr != nil {
+			logrus.Warnf("[Cluster %s] failed to record parameters of feature '%s': %v", instance.GetName(), feat.GetName(), xerr)
+		}
+	}
+	return results, nil
+}
+
+// recordFeatureParameters records in Cluster metadata the parameters used to add the installed feature 'name'
+func (instance *Cluster) recordFeatureParameters(name string, vars data.Map) fail.Error {
+	return instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Alter(clusterproperty.FeaturesV1, func(clonable data.Clonable) fail.Error {
+			featuresV1, ok := clonable.(*propertiesv1.ClusterFeatures)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterFeatures' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			item, ok := featuresV1.Installed[name]
+			if !ok {
+				return nil
+			}
+			item.Parameters = make(map[string]string, len(vars))
+			for k, v := range vars {
+				item.Parameters[k] = fmt.Sprintf("%v", v)
+			}
+			return nil
+		})
+	})
 }
 
 // CheckFeature tells if a feature is installed on the Cluster
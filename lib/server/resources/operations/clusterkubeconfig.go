@@ -0,0 +1,239 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterflavor"
+	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/outputs"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+const (
+	// kubeconfigCommand prints the kubeconfig of the Cluster admin on a master
+	kubeconfigCommand = "sudo cat ~cladm/.kube/config"
+	// defaultAPIServerPort is the port of the Kubernetes API server if the kubeconfig does not tell it
+	defaultAPIServerPort = "6443"
+	// apiServerTLSName is a name always present in the certificate of the Kubernetes API server, used to validate it
+	// whatever the address used to reach it
+	apiServerTLSName = "kubernetes"
+)
+
+// kubeconfigClusters returns the entries 'cluster' of the list 'clusters' of a kubeconfig decoded as yaml.MapSlice
+func kubeconfigClusters(doc yaml.MapSlice) []*yaml.MapItem {
+	var out []*yaml.MapItem
+	for i := range doc {
+		if doc[i].Key != "clusters" {
+			continue
+		}
+
+		list, ok := doc[i].Value.([]interface{})
+		if !ok {
+			continue
+		}
+		for _, v := range list {
+			entry, ok := v.(yaml.MapSlice)
+			if !ok {
+				continue
+			}
+			for j := range entry {
+				if entry[j].Key == "cluster" {
+					out = append(out, &entry[j])
+				}
+			}
+		}
+	}
+	return out
+}
+
+// parseKubeconfigServer returns the URL of the API server set in the kubeconfig 'content'
+func parseKubeconfigServer(content string) (*url.URL, fail.Error) {
+	var doc yaml.MapSlice
+	err := yaml.Unmarshal([]byte(content), &doc)
+	if err != nil {
+		return nil, fail.SyntaxError("invalid kubeconfig: %v", err)
+	}
+
+	for _, v := range kubeconfigClusters(doc) {
+		cluster, ok := v.Value.(yaml.MapSlice)
+		if !ok {
+			continue
+		}
+		for _, item := range cluster {
+			if item.Key != "server" {
+				continue
+			}
+			server, ok := item.Value.(string)
+			if !ok {
+				continue
+			}
+			u, err := url.Parse(server)
+			if err != nil || u.Host == "" {
+				return nil, fail.SyntaxError("invalid server '%s' in kubeconfig", server)
+			}
+			return u, nil
+		}
+	}
+	return nil, fail.SyntaxError("no server found in kubeconfig")
+}
+
+// rewriteKubeconfig replaces the server of the clusters of the kubeconfig 'content' by 'server'; the API server is then
+// validated using the name apiServerTLSName, the address in 'server' being probably not in its certificate
+func rewriteKubeconfig(content string, server string) (string, fail.Error) {
+	var doc yaml.MapSlice
+	err := yaml.Unmarshal([]byte(content), &doc)
+	if err != nil {
+		return "", fail.SyntaxError("invalid kubeconfig: %v", err)
+	}
+
+	clusters := kubeconfigClusters(doc)
+	if len(clusters) == 0 {
+		return "", fail.SyntaxError("no cluster found in kubeconfig")
+	}
+	for _, v := range clusters {
+		cluster, _ := v.Value.(yaml.MapSlice)
+		cluster = setMapSliceItem(cluster, "server", server)
+		cluster = setMapSliceItem(cluster, "tls-server-name", apiServerTLSName)
+		v.Value = cluster
+	}
+
+	out, err := yaml.Marshal(doc)
+	if err != nil {
+		return "", fail.ConvertError(err)
+	}
+	return string(out), nil
+}
+
+// setMapSliceItem sets the value of 'key' in 'in', adding it if needed
+func setMapSliceItem(in yaml.MapSlice, key string, value interface{}) yaml.MapSlice {
+	for i := range in {
+		if in[i].Key == key {
+			in[i].Value = value
+			return in
+		}
+	}
+	return append(in, yaml.MapItem{Key: key, Value: value})
+}
+
+// GetKubeconfig returns the kubeconfig of the Cluster admin, to use the Kubernetes API server from outside of the Cluster.
+// The server of the kubeconfig is replaced by 'server' if not empty, otherwise by the address of the endpoint of the Cluster
+// (public VIP or primary gateway), which must then let pass the port of the API server
+func (instance *Cluster) GetKubeconfig(ctx context.Context, server string) (_ *abstract.ClusterKubeconfig, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	task, xerr := taskFromContextOrVoid(ctx)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.cluster"), "('%s')", server).Entering()
+	defer tracer.Exiting()
+
+	flavor, xerr := instance.GetFlavor()
+	if xerr != nil {
+		return nil, xerr
+	}
+	if flavor != clusterflavor.K8S && flavor != clusterflavor.K3S {
+		return nil, fail.InvalidRequestError("Cluster '%s' of flavor '%s' does not run Kubernetes", instance.GetName(), flavor.String())
+	}
+
+	netCfg, xerr := instance.GetNetworkConfig()
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	master, xerr := instance.FindAvailableMaster(ctx)
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer master.Released()
+
+	retcode, stdout, stderr, xerr := master.Run(ctx, kubeconfigCommand, outputs.COLLECT, temporal.GetConnectionTimeout(), temporal.GetExecutionTimeout())
+	if xerr != nil {
+		return nil, fail.Wrap(xerr, "failed to read kubeconfig on master '%s'", master.GetName())
+	}
+	if retcode != 0 {
+		xerr := fail.ExecutionError(nil, "failed to read kubeconfig on master '%s'", master.GetName())
+		_ = xerr.Annotate("retcode", retcode)
+		_ = xerr.Annotate("stdout", stdout)
+		_ = xerr.Annotate("stderr", stderr)
+		return nil, xerr
+	}
+
+	u, xerr := parseKubeconfigServer(stdout)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	// the API server listening on loopback (like K3S), is reached using the private IP of the master
+	host, port := u.Hostname(), u.Port()
+	if port == "" {
+		port = defaultAPIServerPort
+	}
+	if ip := net.ParseIP(host); host == "localhost" || (ip != nil && ip.IsLoopback()) {
+		host, xerr = master.GetPrivateIP()
+		if xerr != nil {
+			return nil, xerr
+		}
+	}
+
+	gateway, xerr := LoadHost(instance.GetService(), netCfg.GatewayID)
+	if xerr != nil {
+		return nil, fail.Wrap(xerr, "failed to load primary gateway of Cluster '%s'", instance.GetName())
+	}
+	defer gateway.Released()
+
+	if server = strings.TrimSpace(server); server == "" {
+		endpoint := netCfg.EndpointIP
+		if endpoint == "" {
+			endpoint = netCfg.PrimaryPublicIP
+		}
+		if endpoint == "" {
+			return nil, fail.NotFoundError("Cluster '%s' has no public endpoint", instance.GetName())
+		}
+		server = "https://" + net.JoinHostPort(endpoint, port)
+	}
+
+	content, xerr := rewriteKubeconfig(stdout, server)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return &abstract.ClusterKubeconfig{
+		Content:   content,
+		Server:    server,
+		APIServer: net.JoinHostPort(host, port),
+		Gateway:   gateway.GetName(),
+	}, nil
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+const testKubeconfig = `apiVersion: v1
+clusters:
+- cluster:
+    certificate-authority-data: Q0EK
+    server: https://127.0.0.1:6443
+  name: default
+contexts:
+- context:
+    cluster: default
+    user: default
+  name: default
+current-context: default
+kind: Config
+users:
+- name: default
+  user:
+    client-certificate-data: Q0VSVAo=
+`
+
+func Test_parseKubeconfigServer(t *testing.T) {
+	u, xerr := parseKubeconfigServer(testKubeconfig)
+	require.Nil(t, xerr)
+	assert.Equal(t, "127.0.0.1", u.Hostname())
+	assert.Equal(t, "6443", u.Port())
+
+	_, xerr = parseKubeconfigServer("apiVersion: v1\nclusters: []\n")
+	assert.NotNil(t, xerr)
+
+	_, xerr = parseKubeconfigServer("clusters:\n- cluster:\n    server: 'not an url'\n")
+	assert.NotNil(t, xerr)
+
+	_, xerr = parseKubeconfigServer(":")
+	assert.NotNil(t, xerr)
+}
+
+func Test_rewriteKubeconfig(t *testing.T) {
+	out, xerr := rewriteKubeconfig(testKubeconfig, "https://10.0.0.1:6443")
+	require.Nil(t, xerr)
+
+	u, xerr := parseKubeconfigServer(out)
+	require.Nil(t, xerr)
+	assert.Equal(t, "https://10.0.0.1:6443", u.String())
+
+	var doc struct {
+		Clusters []struct {
+			Cluster map[string]string `yaml:"cluster"`
+		} `yaml:"clusters"`
+		Users []struct {
+			User map[string]string `yaml:"user"`
+		} `yaml:"users"`
+		CurrentContext string `yaml:"current-context"`
+	}
+	require.Nil(t, yaml.Unmarshal([]byte(out), &doc))
+	require.Len(t, doc.Clusters, 1)
+	assert.Equal(t, "kubernetes", doc.Clusters[0].Cluster["tls-server-name"])
+	assert.Equal(t, "Q0EK", doc.Clusters[0].Cluster["certificate-authority-data"])
+	require.Len(t, doc.Users, 1)
+	assert.Equal(t, "Q0VSVAo=", doc.Users[0].User["client-certificate-data"])
+	assert.Equal(t, "default", doc.CurrentContext)
+
+	_, xerr = rewriteKubeconfig("apiVersion: v1\n", "https://10.0.0.1:6443")
+	assert.NotNil(t, xerr)
+}
@@ -0,0 +1,556 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server"
+	"github.com/CS-SI/SafeScale/lib/server/resources"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterproperty"
+	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
+	propertiesv3 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v3"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/data/serialize"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/strprocess"
+)
+
+var (
+	nodePoolNameRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+	// kubernetesNameRegexp is the syntax of Kubernetes label values and of the name part of label and taint keys
+	kubernetesNameRegexp = regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?$`)
+	// kubernetesPrefixRegexp is the syntax of the optional prefix of Kubernetes label and taint keys (a DNS subdomain)
+	kubernetesPrefixRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+)
+
+// isKubernetesKey tells if key is a valid Kubernetes label or taint key, '[prefix/]name'
+func isKubernetesKey(key string) bool {
+	name := key
+	if index := strings.LastIndex(key, "/"); index >= 0 {
+		prefix := key[:index]
+		if len(prefix) > 253 || !kubernetesPrefixRegexp.MatchString(prefix) {
+			return false
+		}
+		name = key[index+1:]
+	}
+	return len(name) <= 63 && kubernetesNameRegexp.MatchString(name)
+}
+
+// isKubernetesValue tells if value is a valid Kubernetes label or taint value, possibly empty
+func isKubernetesValue(value string) bool {
+	return value == "" || (len(value) <= 63 && kubernetesNameRegexp.MatchString(value))
+}
+
+// validateNodePool checks the content of a node pool definition
+func validateNodePool(pool propertiesv1.ClusterNodePool) fail.Error {
+	if !nodePoolNameRegexp.MatchString(pool.Name) || len(pool.Name) > 63 {
+		return fail.InvalidRequestError("invalid node pool name '%s': must contain only lowercase alphanumeric characters or '-', and start and end with an alphanumeric character", pool.Name)
+	}
+	if pool.MaxCount > 0 && pool.MinCount > pool.MaxCount {
+		return fail.InvalidRequestError("invalid node pool '%s': min count (%d) cannot be greater than max count (%d)", pool.Name, pool.MinCount, pool.MaxCount)
+	}
+	for k, v := range pool.Labels {
+		if k == propertiesv1.NodePoolLabel {
+			return fail.InvalidRequestError("invalid node pool '%s': label '%s' is reserved", pool.Name, k)
+		}
+		if !isKubernetesKey(k) {
+			return fail.InvalidRequestError("invalid node pool '%s': label key '%s' must be [prefix/]name, name being at most 63 alphanumeric characters, '-', '_' or '.', starting and ending with an alphanumeric character, and prefix a DNS subdomain", pool.Name, k)
+		}
+		if !isKubernetesValue(v) {
+			return fail.InvalidRequestError("invalid node pool '%s': value of label '%s' must be empty or at most 63 alphanumeric characters, '-', '_' or '.', starting and ending with an alphanumeric character", pool.Name, k)
+		}
+	}
+	for _, v := range pool.Taints {
+		splitted := strings.Split(v, ":")
+		if len(splitted) != 2 {
+			return fail.InvalidRequestError("invalid taint '%s': expected key[=value]:Effect", v)
+		}
+		keyValue := strings.SplitN(splitted[0], "=", 2)
+		if !isKubernetesKey(keyValue[0]) {
+			return fail.InvalidRequestError("invalid taint '%s': key must be [prefix/]name, name being at most 63 alphanumeric characters, '-', '_' or '.', starting and ending with an alphanumeric character, and prefix a DNS subdomain", v)
+		}
+		if len(keyValue) == 2 && !isKubernetesValue(keyValue[1]) {
+			return fail.InvalidRequestError("invalid taint '%s': value must be empty or at most 63 alphanumeric characters, '-', '_' or '.', starting and ending with an alphanumeric character", v)
+		}
+		switch splitted[1] {
+		case "NoSchedule", "PreferNoSchedule", "NoExecute":
+		default:
+			return fail.InvalidRequestError("invalid taint '%s': Effect must be NoSchedule, PreferNoSchedule or NoExecute", v)
+		}
+	}
+	return nil
+}
+
+// AddNodePool creates a node pool in the Cluster, then adds count nodes in it
+func (instance *Cluster) AddNodePool(ctx context.Context, pool propertiesv1.ClusterNodePool, count uint, keepOnFailure bool) (_ *propertiesv1.ClusterNodePool, ferr fail.Error) {
+	defer fail.OnPanic(&ferr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	xerr := validateNodePool(pool)
+	if xerr != nil {
+		return nil, xerr
+	}
+	if !pool.Accepts(count) {
+		return nil, fail.InvalidRequestError("cannot create node pool '%s' with %d node%s: count must be between %d and %s", pool.Name, count, strprocess.Plural(count), pool.MinCount, maxCountString(pool.MaxCount))
+	}
+
+	task, xerr := taskFromContextOrVoid(ctx)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.cluster"), "('%s', %d)", pool.Name, count).Entering()
+	defer tracer.Exiting()
+
+	// make sure no other parallel actions interferes
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	xerr = instance.beingRemoved()
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	pool.Nodes = []uint{}
+	xerr = instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Alter(clusterproperty.NodePoolsV1, func(clonable data.Clonable) fail.Error {
+			poolsV1, ok := clonable.(*propertiesv1.ClusterNodePools)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterNodePools' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			if _, ok := poolsV1.ByName[pool.Name]; ok {
+				return fail.DuplicateError("a node pool named '%s' already exists in Cluster '%s'", pool.Name, instance.GetName())
+			}
+
+			poolsV1.ByName[pool.Name] = pool.Clone().(*propertiesv1.ClusterNodePool)
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	// Starting from here, removes the node pool and the nodes already recorded in it if exiting with error
+	defer func() {
+		if ferr != nil && !keepOnFailure {
+			// Disable abort signal during the clean up
+			defer task.DisarmAbortSignal()()
+
+			if recorded, derr := instance.unsafeInspectNodePool(pool.Name); derr == nil {
+				derr = instance.deleteNodePoolNodes(ctx, task, recorded.Nodes)
+				if derr != nil {
+					_ = ferr.AddConsequence(fail.Wrap(derr, "cleaning up on %s, failed to delete nodes of node pool", ActionFromError(ferr)))
+				}
+			}
+
+			derr := instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+				return props.Alter(clusterproperty.NodePoolsV1, func(clonable data.Clonable) fail.Error {
+					poolsV1, ok := clonable.(*propertiesv1.ClusterNodePools)
+					if !ok {
+						return fail.InconsistentError("'*propertiesv1.ClusterNodePools' expected, '%s' provided", reflect.TypeOf(clonable).String())
+					}
+
+					delete(poolsV1.ByName, pool.Name)
+					return nil
+				})
+			})
+			if derr != nil {
+				_ = ferr.AddConsequence(fail.Wrap(derr, "cleaning up on %s, failed to remove node pool from Cluster metadata", ActionFromError(ferr)))
+			}
+		}
+	}()
+
+	if count > 0 {
+		xerr = instance.growNodePool(ctx, task, &pool, count, keepOnFailure)
+		if xerr != nil {
+			return nil, xerr
+		}
+	}
+
+	server.ReportProgress(ctx, server.ProgressEvent{Phase: "done", Step: "node pool created", Percent: 100})
+	return instance.unsafeInspectNodePool(pool.Name)
+}
+
+// ListNodePools returns the node pools of the Cluster, sorted by name
+func (instance *Cluster) ListNodePools(ctx context.Context) (_ []*propertiesv1.ClusterNodePool, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	instance.lock.RLock()
+	defer instance.lock.RUnlock()
+
+	var list []*propertiesv1.ClusterNodePool
+	xerr = instance.Review(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Inspect(clusterproperty.NodePoolsV1, func(clonable data.Clonable) fail.Error {
+			poolsV1, ok := clonable.(*propertiesv1.ClusterNodePools)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterNodePools' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			list = make([]*propertiesv1.ClusterNodePool, 0, len(poolsV1.ByName))
+			for _, v := range poolsV1.Names() {
+				list = append(list, poolsV1.ByName[v].Clone().(*propertiesv1.ClusterNodePool))
+			}
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return list, nil
+}
+
+// ResizeNodePool adds or removes nodes of a node pool, to reach count nodes in the pool
+// Nodes are removed from the last created
+func (instance *Cluster) ResizeNodePool(ctx context.Context, name string, count uint, keepOnFailure bool) (_ *propertiesv1.ClusterNodePool, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if name = strings.TrimSpace(name); name == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("name")
+	}
+
+	task, xerr := taskFromContextOrVoid(ctx)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.cluster"), "('%s', %d)", name, count).Entering()
+	defer tracer.Exiting()
+
+	xerr = instance.beginNodesChange()
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer instance.endNodesChange()
+
+	// make sure no other parallel actions interferes
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	xerr = instance.beingRemoved()
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	pool, xerr := instance.unsafeInspectNodePool(name)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	if !pool.Accepts(count) {
+		return nil, fail.InvalidRequestError("cannot resize node pool '%s' to %d node%s: count must be between %d and %s", name, count, strprocess.Plural(count), pool.MinCount, maxCountString(pool.MaxCount))
+	}
+
+	current := uint(len(pool.Nodes))
+	switch {
+	case count > current:
+		xerr = instance.growNodePool(ctx, task, pool, count-current, keepOnFailure)
+	case count < current:
+		xerr = instance.deleteNodePoolNodes(ctx, task, pool.Nodes[count:])
+	}
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	server.ReportProgress(ctx, server.ProgressEvent{Phase: "done", Step: "node pool resized", Percent: 100})
+	return instance.unsafeInspectNodePool(name)
+}
+
+// DeleteNodePool deletes the nodes of a node pool, then the node pool itself
+func (instance *Cluster) DeleteNodePool(ctx context.Context, name string) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if name = strings.TrimSpace(name); name == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("name")
+	}
+
+	task, xerr := taskFromContextOrVoid(ctx)
+	if xerr != nil {
+		return xerr
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.cluster"), "('%s')", name).Entering()
+	defer tracer.Exiting()
+
+	// make sure no other parallel actions interferes
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	xerr = instance.beingRemoved()
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	pool, xerr := instance.unsafeInspectNodePool(name)
+	if xerr != nil {
+		return xerr
+	}
+
+	xerr = instance.deleteNodePoolNodes(ctx, task, pool.Nodes)
+	if xerr != nil {
+		return xerr
+	}
+
+	return instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Alter(clusterproperty.NodePoolsV1, func(clonable data.Clonable) fail.Error {
+			poolsV1, ok := clonable.(*propertiesv1.ClusterNodePools)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterNodePools' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			delete(poolsV1.ByName, name)
+			return nil
+		})
+	})
+}
+
+// unsafeInspectNodePool returns a copy of the node pool named 'name'
+func (instance *Cluster) unsafeInspectNodePool(name string) (pool *propertiesv1.ClusterNodePool, xerr fail.Error) {
+	xerr = instance.Review(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Inspect(clusterproperty.NodePoolsV1, func(clonable data.Clonable) fail.Error {
+			poolsV1, ok := clonable.(*propertiesv1.ClusterNodePools)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterNodePools' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			item, ok := poolsV1.ByName[name]
+			if !ok {
+				return fail.NotFoundError("failed to find a node pool named '%s' in Cluster '%s'", name, instance.GetName())
+			}
+
+			pool = item.Clone().(*propertiesv1.ClusterNodePool)
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return pool, nil
+}
+
+// growNodePool adds count nodes sized as defined by the pool, records them in the pool then applies labels and taints of the pool on them
+// Note: must be called with instance.lock held
+func (instance *Cluster) growNodePool(ctx context.Context, task concurrency.Task, pool *propertiesv1.ClusterNodePool, count uint, keepOnFailure bool) fail.Error {
+	def := pool.Sizing
+	if pool.Image != "" {
+		def.Image = pool.Image
+	}
+
+	hosts, nodes, xerr := instance.addNodes(ctx, task, count, def, keepOnFailure)
+	if xerr != nil {
+		return xerr
+	}
+	defer func() {
+		for _, v := range hosts {
+			v.Released()
+		}
+	}()
+
+	xerr = instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Alter(clusterproperty.NodePoolsV1, func(clonable data.Clonable) fail.Error {
+			poolsV1, ok := clonable.(*propertiesv1.ClusterNodePools)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterNodePools' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			item, ok := poolsV1.ByName[pool.Name]
+			if !ok {
+				return fail.InconsistentError("node pool '%s' vanished from metadata", pool.Name)
+			}
+
+			for _, v := range nodes {
+				item.Nodes = append(item.Nodes, v.NumericalID)
+			}
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	return instance.labelNodePoolNodes(ctx, pool, hosts)
+}
+
+// labelNodePoolNodes applies the labels and taints of the pool on its nodes, if the flavor of the Cluster supports it
+func (instance *Cluster) labelNodePoolNodes(ctx context.Context, pool *propertiesv1.ClusterNodePool, hosts []resources.Host) fail.Error {
+	if instance.makers.LabelNode == nil {
+		if len(pool.Labels) > 0 || len(pool.Taints) > 0 {
+			logrus.Warnf("[cluster %s] flavor does not support labels and taints on nodes, those of node pool '%s' are ignored", instance.GetName(), pool.Name)
+		}
+		return nil
+	}
+
+	labels := make(map[string]string, len(pool.Labels)+1)
+	for k, v := range pool.Labels {
+		labels[k] = v
+	}
+	labels[propertiesv1.NodePoolLabel] = pool.Name
+
+	selectedMaster, xerr := instance.UnsafeFindAvailableMaster(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	server.ReportProgress(ctx, server.ProgressEvent{Phase: "configuration", Step: fmt.Sprintf("labeling nodes of node pool '%s'", pool.Name), Percent: 90})
+	for _, v := range hosts {
+		xerr = instance.makers.LabelNode(ctx, instance, v, selectedMaster, labels, pool.Taints)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return xerr
+		}
+	}
+	return nil
+}
+
+// deleteNodePoolNodes deletes the nodes identified by their numerical IDs
+// Note: must be called with instance.lock held
+func (instance *Cluster) deleteNodePoolNodes(ctx context.Context, task concurrency.Task, numericalIDs []uint) fail.Error {
+	if len(numericalIDs) == 0 {
+		return nil
+	}
+
+	var nodes []*propertiesv3.ClusterNode
+	xerr := instance.Review(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Inspect(clusterproperty.NodesV3, func(clonable data.Clonable) fail.Error {
+			nodesV3, ok := clonable.(*propertiesv3.ClusterNodes)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv3.ClusterNodes' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			for _, v := range numericalIDs {
+				if node, ok := nodesV3.ByNumericalID[v]; ok {
+					nodes = append(nodes, node)
+				}
+			}
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	selectedMaster, xerr := instance.UnsafeFindAvailableMaster(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	server.ReportProgress(ctx, server.ProgressEvent{Phase: "hosts", Step: fmt.Sprintf("deleting %d node%s", len(nodes), strprocess.Plural(uint(len(nodes))))})
+	tg, xerr := concurrency.NewTaskGroupWithParent(task, concurrency.InheritParentIDOption, concurrency.AmendID(fmt.Sprintf("/nodepool/%d", len(nodes))))
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	var errors []error
+	for _, v := range nodes {
+		_, xerr = tg.Start(instance.taskDeleteNode, taskDeleteNodeParameters{node: v, nodeLoadMethod: HostFullOption, master: selectedMaster.(*Host)}, concurrency.InheritParentIDOption, concurrency.AmendID(fmt.Sprintf("/node/%s/delete", v.Name)))
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			errors = append(errors, xerr)
+			abErr := tg.Abort()
+			if abErr != nil {
+				logrus.Errorf("there was an error trying to abort TaskGroup: %s", spew.Sdump(abErr))
+			}
+			break
+		}
+	}
+	_, xerr = tg.WaitGroup()
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		errors = append(errors, xerr)
+	}
+	if len(errors) > 0 {
+		return fail.NewErrorList(errors)
+	}
+	return nil
+}
+
+// taskFromContextOrVoid returns the task stored in ctx, or a void task if there is none
+func taskFromContextOrVoid(ctx context.Context) (concurrency.Task, fail.Error) {
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotAvailable:
+			return concurrency.VoidTask()
+		default:
+			return nil, xerr
+		}
+	}
+
+	if task.Aborted() {
+		return nil, fail.AbortedError(nil, "aborted")
+	}
+	return task, nil
+}
+
+func maxCountString(maxCount uint) string {
+	if maxCount == 0 {
+		return "unlimited"
+	}
+	return fmt.Sprintf("%d", maxCount)
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clustercomplexity"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterflavor"
+	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+func Test_validateNodePool(t *testing.T) {
+	valid := propertiesv1.ClusterNodePool{
+		Name:     "gpu",
+		MinCount: 1,
+		MaxCount: 3,
+		Labels:   map[string]string{"accelerator": "nvidia", "example.com/tier": "", "Zone_1": "eu-west.a"},
+		Taints:   []string{"nvidia.com/gpu=true:NoSchedule", "dedicated:NoExecute"},
+	}
+	assert.Nil(t, validateNodePool(valid))
+
+	invalids := map[string]func(*propertiesv1.ClusterNodePool){
+		"uppercase name":   func(p *propertiesv1.ClusterNodePool) { p.Name = "GPU" },
+		"empty name":       func(p *propertiesv1.ClusterNodePool) { p.Name = "" },
+		"min over max":     func(p *propertiesv1.ClusterNodePool) { p.MinCount = 4 },
+		"reserved label":   func(p *propertiesv1.ClusterNodePool) { p.Labels = map[string]string{propertiesv1.NodePoolLabel: "x"} },
+		"taint w/o effect": func(p *propertiesv1.ClusterNodePool) { p.Taints = []string{"nvidia.com/gpu=true"} },
+		"unknown effect":   func(p *propertiesv1.ClusterNodePool) { p.Taints = []string{"nvidia.com/gpu=true:Never"} },
+		"taint w/o key":    func(p *propertiesv1.ClusterNodePool) { p.Taints = []string{"=true:NoSchedule"} },
+		"label key quote":  func(p *propertiesv1.ClusterNodePool) { p.Labels = map[string]string{"a'; reboot; '": "x"} },
+		"label value":      func(p *propertiesv1.ClusterNodePool) { p.Labels = map[string]string{"tier": "$(reboot)"} },
+		"label prefix":     func(p *propertiesv1.ClusterNodePool) { p.Labels = map[string]string{"Example.COM/tier": "x"} },
+		"long label key":   func(p *propertiesv1.ClusterNodePool) { p.Labels = map[string]string{strings.Repeat("a", 64): "x"} },
+		"taint key space":  func(p *propertiesv1.ClusterNodePool) { p.Taints = []string{"a b=true:NoSchedule"} },
+		"taint value":      func(p *propertiesv1.ClusterNodePool) { p.Taints = []string{"dedicated=`id`:NoSchedule"} },
+	}
+	for name, alter := range invalids {
+		pool := *valid.Clone().(*propertiesv1.ClusterNodePool)
+		alter(&pool)
+		xerr := validateNodePool(pool)
+		if assert.NotNil(t, xerr, name) {
+			assert.IsType(t, &fail.ErrInvalidRequest{}, xerr, name)
+		}
+	}
+}
+
+func Test_cluster_NodePools(t *testing.T) {
+	svc := getMemoryService(t)
+	ctx := context.Background()
+
+	instance, xerr := NewCluster(svc)
+	require.Nil(t, xerr)
+	req := abstract.ClusterRequest{Name: "pools", Flavor: clusterflavor.BOH, Complexity: clustercomplexity.Small, InitialNodeCount: 1}
+	require.Nil(t, instance.firstLight(req))
+
+	pool := propertiesv1.ClusterNodePool{Name: "highmem", MaxCount: 2, Sizing: abstract.HostSizingRequirements{MinRAMSize: 64}}
+	created, xerr := instance.AddNodePool(ctx, pool, 0, false)
+	require.Nil(t, xerr)
+	assert.Equal(t, "highmem", created.Name)
+	assert.Empty(t, created.Nodes)
+
+	_, xerr = instance.AddNodePool(ctx, pool, 0, false)
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrDuplicate{}, xerr)
+
+	_, xerr = instance.AddNodePool(ctx, propertiesv1.ClusterNodePool{Name: "gpu", MaxCount: 1}, 2, false)
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrInvalidRequest{}, xerr)
+
+	list, xerr := instance.ListNodePools(ctx)
+	require.Nil(t, xerr)
+	require.Len(t, list, 1)
+	assert.EqualValues(t, 64, list[0].Sizing.MinRAMSize)
+
+	_, xerr = instance.ResizeNodePool(ctx, "highmem", 3, false)
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrInvalidRequest{}, xerr)
+
+	_, xerr = instance.ResizeNodePool(ctx, "unknown", 1, false)
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrNotFound{}, xerr)
+
+	require.Nil(t, instance.DeleteNodePool(ctx, "highmem"))
+	list, xerr = instance.ListNodePools(ctx)
+	require.Nil(t, xerr)
+	assert.Empty(t, list)
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/davecgh/go-spew/spew"
 	"github.com/sirupsen/logrus"
 
+	"github.com/CS-SI/SafeScale/lib/server"
 	"github.com/CS-SI/SafeScale/lib/server/resources"
 	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clustercomplexity"
@@ -73,6 +74,7 @@ func (instance *Cluster) taskCreateCluster(task concurrency.Task, params concurr
 	}
 
 	// Create first metadata of Cluster after initialization
+	server.ReportProgress(ctx, server.ProgressEvent{Phase: "metadata", Step: "initializing Cluster metadata"})
 	xerr = instance.firstLight(req)
 	xerr = debug.InjectPlannedFail(xerr)
 	if xerr != nil {
@@ -94,6 +96,13 @@ func (instance *Cluster) taskCreateCluster(task concurrency.Task, params concurr
 		}
 	}()
 
+	// if resources are kept on failure, records the failure to allow to resume the creation
+	defer func() {
+		if ferr != nil && req.KeepOnFailure {
+			instance.failCreation(ferr)
+		}
+	}()
+
 	if task.Aborted() {
 		if lerr, err := task.LastError(); err == nil {
 			return nil, fail.AbortedError(lerr, "parent task killed")
@@ -132,13 +141,29 @@ func (instance *Cluster) taskCreateCluster(task concurrency.Task, params concurr
 		return nil, xerr
 	}
 
+	// Records the request with the sizings resolved, to be able to resume the creation
+	recorded := req
+	recorded.GatewaysDef, recorded.MastersDef, recorded.NodesDef = *gatewaysDef, *mastersDef, *nodesDef
+	xerr = instance.startCreation(recorded)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
 	// Create the Network and Subnet
+	server.ReportProgress(ctx, server.ProgressEvent{Phase: "network", Step: "creating Network, Subnet and gateways", Percent: 5})
 	networkInstance, subnetInstance, xerr := instance.createNetworkingResources(task, req, gatewaysDef)
 	xerr = debug.InjectPlannedFail(xerr)
 	if xerr != nil {
 		return nil, xerr
 	}
 
+	xerr = instance.markCreationStep(creationStepNetwork)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
 	defer func() {
 		if ferr != nil && !req.KeepOnFailure {
 			logrus.Debugf("Cleaning up on failure, deleting Subnet '%s'...", subnetInstance.GetName())
@@ -175,6 +200,7 @@ func (instance *Cluster) taskCreateCluster(task concurrency.Task, params concurr
 	}()
 
 	// Creates and configures hosts
+	server.ReportProgress(ctx, server.ProgressEvent{Phase: "hosts", Step: "creating masters and nodes", Percent: 30})
 	xerr = instance.createHostResources(task, subnetInstance, *mastersDef, *nodesDef, req.InitialNodeCount, req.KeepOnFailure)
 	xerr = debug.InjectPlannedFail(xerr)
 	if xerr != nil {
@@ -234,6 +260,7 @@ func (instance *Cluster) taskCreateCluster(task concurrency.Task, params concurr
 	}()
 
 	// configure Cluster as a whole
+	server.ReportProgress(ctx, server.ProgressEvent{Phase: "configuration", Step: "configuring Cluster", Percent: 80})
 	xerr = instance.configureCluster(ctx)
 	xerr = debug.InjectPlannedFail(xerr)
 	if xerr != nil {
@@ -241,32 +268,14 @@ func (instance *Cluster) taskCreateCluster(task concurrency.Task, params concurr
 	}
 
 	// Sets nominal state of the new Cluster in metadata
-	xerr = instance.Alter(func(clonable data.Clonable, props *serialize.JSONProperties) fail.Error {
-		// update metadata about disabled default features
-		innerXErr := props.Alter(clusterproperty.FeaturesV1, func(clonable data.Clonable) fail.Error {
-			featuresV1, ok := clonable.(*propertiesv1.ClusterFeatures)
-			if !ok {
-				return fail.InconsistentError("'*propertiesv1.ClusterFeatures' expected, '%s' provided", reflect.TypeOf(clonable).String())
-			}
-
-			featuresV1.Disabled = req.DisabledDefaultFeatures
-			return nil
-		})
-		if innerXErr != nil {
-			return innerXErr
-		}
-
-		return props.Alter(clusterproperty.StateV1, func(clonable data.Clonable) fail.Error {
-			stateV1, ok := clonable.(*propertiesv1.ClusterState)
-			if !ok {
-				return fail.InconsistentError("'*propertiesv1.ClusterState' expected, '%s' provided", reflect.TypeOf(clonable).String())
-			}
-			stateV1.State = clusterstate.Nominal
-			return nil
-		})
-	})
+	xerr = instance.completeCreation(req)
 	xerr = debug.InjectPlannedFail(xerr)
-	return nil, xerr
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	server.ReportProgress(ctx, server.ProgressEvent{Phase: "done", Step: "Cluster is ready", Percent: 100})
+	return nil, nil
 }
 
 // firstLight contains the code leading to Cluster first metadata written
@@ -340,6 +349,12 @@ func (instance *Cluster) firstLight(req abstract.ClusterRequest) fail.Error {
 			return innerXErr
 		}
 
+		// sets the user-defined labels of the Cluster
+		innerXErr = labelsToProperties(props, clusterproperty.LabelsV1, req.Labels)
+		if innerXErr != nil {
+			return innerXErr
+		}
+
 		// FUTURE: sets the Cluster composition (when we will be able to manage Cluster spread on several tenants...)
 		innerXErr = props.Alter(clusterproperty.CompositeV1, func(clonable data.Clonable) fail.Error {
 			compositeV1, ok := clonable.(*propertiesv1.ClusterComposite)
@@ -583,6 +598,7 @@ func (instance *Cluster) createNetworkingResources(task concurrency.Task, req ab
 			Name:          req.Name,
 			CIDR:          req.CIDR,
 			KeepOnFailure: req.KeepOnFailure,
+			Labels:        req.Labels,
 		}
 
 		networkInstance, xerr = NewNetwork(instance.GetService())
@@ -646,6 +662,7 @@ func (instance *Cluster) createNetworkingResources(task concurrency.Task, req ab
 		HA:            !gwFailoverDisabled,
 		ImageRef:      gatewaysDef.Image,
 		KeepOnFailure: false, // We consider subnet and its gateways as a whole; if any error occurs during the creation of the whole, do keep nothing
+		GatewayLabels: req.Labels,
 	}
 
 	subnetInstance, xerr := NewSubnet(instance.GetService())
@@ -879,34 +896,49 @@ func (instance *Cluster) createHostResources(
 		return fail.AbortedError(nil, "parent task killed")
 	}
 
-	gwInstallTasks, xerr := concurrency.NewTaskGroupWithParent(task, concurrency.InheritParentIDOption, concurrency.AmendID("/gateway"))
-	xerr = debug.InjectPlannedFail(xerr)
+	// When resuming a creation, the steps already done are skipped and only the missing hosts are created
+	gatewaysInstalled, xerr := instance.creationStepDone(creationStepGateways)
 	if xerr != nil {
 		return xerr
 	}
 
-	// Step 1: starts gateway installation plus masters creation plus nodes creation
-	_, xerr = gwInstallTasks.Start(instance.taskInstallGateway, taskInstallGatewayParameters{primaryGateway}, concurrency.InheritParentIDOption, concurrency.AmendID(fmt.Sprintf("/%s/install", primaryGateway.GetName())))
-	xerr = debug.InjectPlannedFail(xerr)
+	createdMasters, createdNodes, xerr := instance.countCreatedHosts()
 	if xerr != nil {
 		return xerr
 	}
 
-	startedTasks = append(startedTasks, gwInstallTasks)
-
-	if task.Aborted() {
-		if lerr, err := task.LastError(); err == nil {
-			return fail.AbortedError(lerr, "parent task killed")
+	// Step 1: starts gateway installation plus masters creation plus nodes creation
+	var gwInstallTasks concurrency.TaskGroupGuard
+	if !gatewaysInstalled {
+		tg, xerr := concurrency.NewTaskGroupWithParent(task, concurrency.InheritParentIDOption, concurrency.AmendID("/gateway"))
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return xerr
 		}
-		return fail.AbortedError(nil, "parent task killed")
-	}
 
-	if haveSecondaryGateway {
-		_, xerr = gwInstallTasks.Start(instance.taskInstallGateway, taskInstallGatewayParameters{secondaryGateway}, concurrency.InheritParentIDOption, concurrency.AmendID(fmt.Sprintf("/%s/install", secondaryGateway.GetName())))
+		_, xerr = tg.Start(instance.taskInstallGateway, taskInstallGatewayParameters{primaryGateway}, concurrency.InheritParentIDOption, concurrency.AmendID(fmt.Sprintf("/%s/install", primaryGateway.GetName())))
 		xerr = debug.InjectPlannedFail(xerr)
-		if xerr != nil { // no need to abort and wait, the previous defer takes care of that
+		if xerr != nil {
 			return xerr
 		}
+
+		gwInstallTasks = tg
+		startedTasks = append(startedTasks, tg)
+
+		if task.Aborted() {
+			if lerr, err := task.LastError(); err == nil {
+				return fail.AbortedError(lerr, "parent task killed")
+			}
+			return fail.AbortedError(nil, "parent task killed")
+		}
+
+		if haveSecondaryGateway {
+			_, xerr = tg.Start(instance.taskInstallGateway, taskInstallGatewayParameters{secondaryGateway}, concurrency.InheritParentIDOption, concurrency.AmendID(fmt.Sprintf("/%s/install", secondaryGateway.GetName())))
+			xerr = debug.InjectPlannedFail(xerr)
+			if xerr != nil { // no need to abort and wait, the previous defer takes care of that
+				return xerr
+			}
+		}
 	}
 
 	if task.Aborted() {
@@ -951,23 +983,27 @@ func (instance *Cluster) createHostResources(
 		}
 	}()
 
-	mastersCreateTasks, xerr := concurrency.NewTaskGroupWithParent(task, concurrency.InheritParentIDOption, concurrency.AmendID("/masters"))
-	xerr = debug.InjectPlannedFail(xerr)
-	if xerr != nil {
-		return xerr
-	}
+	var mastersCreateTasks concurrency.TaskGroupGuard
+	if createdMasters < masterCount {
+		tg, xerr := concurrency.NewTaskGroupWithParent(task, concurrency.InheritParentIDOption, concurrency.AmendID("/masters"))
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return xerr
+		}
 
-	_, xerr = mastersCreateTasks.Start(instance.taskCreateMasters, taskCreateMastersParameters{
-		count:         masterCount,
-		mastersDef:    mastersDef,
-		keepOnFailure: keepOnFailure,
-	}, concurrency.InheritParentIDOption)
-	xerr = debug.InjectPlannedFail(xerr)
-	if xerr != nil { // no need to abort and wait, the previous defer takes care of that
-		return xerr
-	}
+		_, xerr = tg.Start(instance.taskCreateMasters, taskCreateMastersParameters{
+			count:         masterCount - createdMasters,
+			mastersDef:    mastersDef,
+			keepOnFailure: keepOnFailure,
+		}, concurrency.InheritParentIDOption)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil { // no need to abort and wait, the previous defer takes care of that
+			return xerr
+		}
 
-	startedTasks = append(startedTasks, mastersCreateTasks)
+		mastersCreateTasks = tg
+		startedTasks = append(startedTasks, tg)
+	}
 
 	if task.Aborted() {
 		if lerr, err := task.LastError(); err == nil {
@@ -1011,24 +1047,28 @@ func (instance *Cluster) createHostResources(
 		}
 	}()
 
-	privateNodesCreateTasks, xerr := concurrency.NewTaskGroupWithParent(task, concurrency.InheritParentIDOption, concurrency.AmendID("/nodes"))
-	xerr = debug.InjectPlannedFail(xerr)
-	if xerr != nil {
-		return xerr
-	}
+	var privateNodesCreateTasks concurrency.TaskGroupGuard
+	if createdNodes < initialNodeCount {
+		tg, xerr := concurrency.NewTaskGroupWithParent(task, concurrency.InheritParentIDOption, concurrency.AmendID("/nodes"))
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return xerr
+		}
 
-	_, xerr = privateNodesCreateTasks.Start(instance.taskCreateNodes, taskCreateNodesParameters{
-		count:         initialNodeCount,
-		public:        false,
-		nodesDef:      nodesDef,
-		keepOnFailure: keepOnFailure,
-	}, concurrency.InheritParentIDOption)
-	xerr = debug.InjectPlannedFail(xerr)
-	if xerr != nil { // no need to abort and wait, the previous defer takes care of that
-		return xerr
-	}
+		_, xerr = tg.Start(instance.taskCreateNodes, taskCreateNodesParameters{
+			count:         initialNodeCount - createdNodes,
+			public:        false,
+			nodesDef:      nodesDef,
+			keepOnFailure: keepOnFailure,
+		}, concurrency.InheritParentIDOption)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil { // no need to abort and wait, the previous defer takes care of that
+			return xerr
+		}
 
-	startedTasks = append(startedTasks, privateNodesCreateTasks)
+		privateNodesCreateTasks = tg
+		startedTasks = append(startedTasks, tg)
+	}
 
 	if task.Aborted() {
 		if lerr, err := task.LastError(); err == nil {
@@ -1038,19 +1078,30 @@ func (instance *Cluster) createHostResources(
 	}
 
 	// Step 2: awaits gateway installation end and masters installation end
-	var gatewayInstallResult concurrency.TaskGroupResult
-	if gatewayInstallResult, gatewayInstallStatus = gwInstallTasks.WaitGroup(); gatewayInstallStatus != nil {
-		// no need to abort and wait, the previous defer takes care of that
-		return gatewayInstallStatus
+	if gwInstallTasks != nil {
+		var gatewayInstallResult concurrency.TaskGroupResult
+		if gatewayInstallResult, gatewayInstallStatus = gwInstallTasks.WaitGroup(); gatewayInstallStatus != nil {
+			// no need to abort and wait, the previous defer takes care of that
+			return gatewayInstallStatus
+		}
+		logrus.Debugf("gateway install returned: %v", gatewayInstallResult)
+
+		if xerr = instance.markCreationStep(creationStepGateways); xerr != nil {
+			return xerr
+		}
 	}
-	logrus.Debugf("gateway install returned: %v", gatewayInstallResult)
 
-	var masterCreationResult concurrency.TaskGroupResult
-	if masterCreationResult, mastersStatus = mastersCreateTasks.WaitGroup(); mastersStatus != nil {
-		// no need to abort and wait, the previous defer takes care of that
-		return mastersStatus
+	if mastersCreateTasks != nil {
+		var masterCreationResult concurrency.TaskGroupResult
+		if masterCreationResult, mastersStatus = mastersCreateTasks.WaitGroup(); mastersStatus != nil {
+			// no need to abort and wait, the previous defer takes care of that
+			return mastersStatus
+		}
+		logrus.Debugf("master creation returned: %v", masterCreationResult)
+	}
+	if xerr = instance.markCreationStep(creationStepMasters); xerr != nil {
+		return xerr
 	}
-	logrus.Debugf("master creation returned: %v", masterCreationResult)
 
 	if task.Aborted() {
 		if lerr, err := task.LastError(); err == nil {
@@ -1061,58 +1112,71 @@ func (instance *Cluster) createHostResources(
 
 	// Step 3: start gateway configuration (needs MasterIPs so masters must be installed first)
 	// Configure gateway(s) and waits for the result
+	xerr = instance.runCreationStep(creationStepGatewaysConfiguration, func() fail.Error {
+		gwCfgTasks, xerr := concurrency.NewTaskGroupWithParent(task, concurrency.InheritParentIDOption, concurrency.AmendID("/configuregateways"))
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			// no need to abort and wait, the previous defer takes care of that
+			return xerr
+		}
 
-	gwCfgTasks, xerr := concurrency.NewTaskGroupWithParent(task, concurrency.InheritParentIDOption, concurrency.AmendID("/configuregateways"))
-	xerr = debug.InjectPlannedFail(xerr)
-	if xerr != nil {
-		// no need to abort and wait, the previous defer takes care of that
-		return xerr
-	}
-
-	_, xerr = gwCfgTasks.Start(instance.taskConfigureGateway, taskConfigureGatewayParameters{Host: primaryGateway}, concurrency.InheritParentIDOption, concurrency.AmendID(fmt.Sprintf("/host/%s/configure", primaryGateway.GetName())))
-	xerr = debug.InjectPlannedFail(xerr)
-	if xerr != nil {
-		// no need to abort and wait, the previous defer takes care of that
-		return xerr
-	}
-
-	startedTasks = append(startedTasks, gwCfgTasks)
-
-	if haveSecondaryGateway {
-		_, xerr = gwCfgTasks.Start(instance.taskConfigureGateway, taskConfigureGatewayParameters{Host: secondaryGateway}, concurrency.InheritParentIDOption)
+		_, xerr = gwCfgTasks.Start(instance.taskConfigureGateway, taskConfigureGatewayParameters{Host: primaryGateway}, concurrency.InheritParentIDOption, concurrency.AmendID(fmt.Sprintf("/host/%s/configure", primaryGateway.GetName())))
 		xerr = debug.InjectPlannedFail(xerr)
 		if xerr != nil {
 			// no need to abort and wait, the previous defer takes care of that
 			return xerr
 		}
-	}
 
-	var gatewayCfgResult concurrency.TaskGroupResult
-	gatewayCfgResult, gatewayConfigurationStatus = gwCfgTasks.WaitGroup()
-	if gatewayConfigurationStatus != nil {
-		return gatewayConfigurationStatus
-	}
-	logrus.Debugf("gateway cfg returned: %v", gatewayCfgResult)
+		startedTasks = append(startedTasks, gwCfgTasks)
 
-	// Step 4: configure masters (if masters created successfully and gateways configured successfully)
-	mastersCfgTask, xerr := concurrency.NewTaskWithParent(task, concurrency.InheritParentIDOption, concurrency.AmendID("/masters"))
-	xerr = debug.InjectPlannedFail(xerr)
+		if haveSecondaryGateway {
+			_, xerr = gwCfgTasks.Start(instance.taskConfigureGateway, taskConfigureGatewayParameters{Host: secondaryGateway}, concurrency.InheritParentIDOption)
+			xerr = debug.InjectPlannedFail(xerr)
+			if xerr != nil {
+				// no need to abort and wait, the previous defer takes care of that
+				return xerr
+			}
+		}
+
+		var gatewayCfgResult concurrency.TaskGroupResult
+		gatewayCfgResult, gatewayConfigurationStatus = gwCfgTasks.WaitGroup()
+		if gatewayConfigurationStatus != nil {
+			return gatewayConfigurationStatus
+		}
+		logrus.Debugf("gateway cfg returned: %v", gatewayCfgResult)
+		return nil
+	})
 	if xerr != nil {
 		return xerr
 	}
 
-	_, mastersStatus = mastersCfgTask.Run(instance.taskConfigureMasters, nil, concurrency.InheritParentIDOption, concurrency.AmendID("/configure"))
-	if mastersStatus != nil {
+	// Step 4: configure masters (if masters created successfully and gateways configured successfully)
+	xerr = instance.runCreationStep(creationStepMastersConfiguration, func() fail.Error {
+		mastersCfgTask, xerr := concurrency.NewTaskWithParent(task, concurrency.InheritParentIDOption, concurrency.AmendID("/masters"))
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return xerr
+		}
+
+		_, mastersStatus = mastersCfgTask.Run(instance.taskConfigureMasters, nil, concurrency.InheritParentIDOption, concurrency.AmendID("/configure"))
 		return mastersStatus
+	})
+	if xerr != nil {
+		return xerr
 	}
 
 	// Step 5: awaits nodes creation
-	var privateNodesResult concurrency.TaskGroupResult
-	privateNodesResult, privateNodesStatus = privateNodesCreateTasks.WaitGroup()
-	if privateNodesStatus != nil {
-		return privateNodesStatus
+	if privateNodesCreateTasks != nil {
+		var privateNodesResult concurrency.TaskGroupResult
+		privateNodesResult, privateNodesStatus = privateNodesCreateTasks.WaitGroup()
+		if privateNodesStatus != nil {
+			return privateNodesStatus
+		}
+		logrus.Debugf("private node creation returned: %v", privateNodesResult)
+	}
+	if xerr = instance.markCreationStep(creationStepNodes); xerr != nil {
+		return xerr
 	}
-	logrus.Debugf("private node creation returned: %v", privateNodesResult)
 
 	if task.Aborted() {
 		if lerr, err := task.LastError(); err == nil {
@@ -1122,18 +1186,16 @@ func (instance *Cluster) createHostResources(
 	}
 
 	// Step 6: Starts nodes configuration, if all masters and nodes have been created and gateway has been configured with success
-	privateNodesCfgTask, xerr := concurrency.NewTaskGroupWithParent(task, concurrency.InheritParentIDOption, concurrency.AmendID("/nodes"))
-	xerr = debug.InjectPlannedFail(xerr)
-	if xerr != nil {
-		return xerr
-	}
+	return instance.runCreationStep(creationStepNodesConfiguration, func() fail.Error {
+		privateNodesCfgTask, xerr := concurrency.NewTaskGroupWithParent(task, concurrency.InheritParentIDOption, concurrency.AmendID("/nodes"))
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return xerr
+		}
 
-	_, privateNodesStatus = privateNodesCfgTask.Run(instance.taskConfigureNodes, nil, concurrency.InheritParentIDOption, concurrency.AmendID("/configure"))
-	if privateNodesStatus != nil {
+		_, privateNodesStatus = privateNodesCfgTask.Run(instance.taskConfigureNodes, nil, concurrency.InheritParentIDOption, concurrency.AmendID("/configure"))
 		return privateNodesStatus
-	}
-
-	return nil
+	})
 }
 
 // complementSizingRequirements complements req with default values if needed
@@ -1613,6 +1675,11 @@ func (instance *Cluster) taskCreateMaster(task concurrency.Task, params concurre
 
 	hostReq.PublicIP = false
 	hostReq.KeepOnFailure = p.keepOnFailure
+	hostReq.Labels, xerr = instance.unsafeGetLabels()
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
 	if p.masterDef.Image != "" {
 		hostReq.ImageID = p.masterDef.Image
 	}
@@ -1626,6 +1693,7 @@ func (instance *Cluster) taskCreateMaster(task concurrency.Task, params concurre
 		return nil, xerr
 	}
 
+	server.ReportProgress(task.Context(), server.ProgressEvent{Phase: "hosts", Step: "creating master", Host: hostReq.ResourceName})
 	_, xerr = hostInstance.Create(task.Context(), hostReq, p.masterDef)
 	xerr = debug.InjectPlannedFail(xerr)
 	if xerr != nil {
@@ -1701,6 +1769,8 @@ func (instance *Cluster) taskCreateMaster(task concurrency.Task, params concurre
 	}
 
 	logrus.Debugf("[%s] Host creation successful.", hostLabel)
+	instance.markCreationHost(hostInstance.GetName(), creationHostCreated)
+	server.ReportProgress(task.Context(), server.ProgressEvent{Phase: "hosts", Step: "master created", Host: hostInstance.GetName()})
 	return hostInstance, nil
 }
 
@@ -1785,6 +1855,11 @@ func (instance *Cluster) taskConfigureMasters(task concurrency.Task, _ concurren
 	for i, master := range masters {
 		captured := i
 		capturedMaster := master
+		if state, xerr := instance.creationHostState(capturedMaster.Name); xerr == nil && state == creationHostConfigured {
+			logrus.Debugf("[Cluster %s] master '%s' already configured, skipping", instance.GetName(), capturedMaster.Name)
+			continue
+		}
+
 		host, xerr := LoadHost(instance.GetService(), capturedMaster.ID)
 		xerr = debug.InjectPlannedFail(xerr)
 		if xerr != nil {
@@ -1907,10 +1982,13 @@ func (instance *Cluster) taskConfigureMaster(task concurrency.Task, params concu
 		}
 
 		logrus.Debugf("[%s] configuration successful in [%s].", hostLabel, temporal.FormatDuration(time.Since(started)))
+		instance.markCreationHost(p.Host.GetName(), creationHostConfigured)
+		server.ReportProgress(task.Context(), server.ProgressEvent{Phase: "configuration", Step: "master configured", Host: p.Host.GetName()})
 		return nil, nil
 	}
 
 	// Not finding a callback isn't an error, so return nil in this case
+	instance.markCreationHost(p.Host.GetName(), creationHostConfigured)
 	return nil, nil
 }
 
@@ -2129,6 +2207,11 @@ func (instance *Cluster) taskCreateNode(task concurrency.Task, params concurrenc
 
 	hostReq.PublicIP = false
 	hostReq.KeepOnFailure = p.keepOnFailure
+	hostReq.Labels, xerr = instance.unsafeGetLabels()
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
 
 	if p.nodeDef.Image != "" {
 		hostReq.ImageID = p.nodeDef.Image
@@ -2143,6 +2226,7 @@ func (instance *Cluster) taskCreateNode(task concurrency.Task, params concurrenc
 		return nil, xerr
 	}
 
+	server.ReportProgress(task.Context(), server.ProgressEvent{Phase: "hosts", Step: "creating node", Host: hostReq.ResourceName})
 	_, xerr = hostInstance.Create(task.Context(), hostReq, p.nodeDef)
 	xerr = debug.InjectPlannedFail(xerr)
 	if xerr != nil {
@@ -2246,6 +2330,8 @@ func (instance *Cluster) taskCreateNode(task concurrency.Task, params concurrenc
 	}
 
 	logrus.Debugf("[%s] Host creation successful.", hostLabel)
+	instance.markCreationHost(hostInstance.GetName(), creationHostCreated)
+	server.ReportProgress(task.Context(), server.ProgressEvent{Phase: "hosts", Step: "node created", Host: hostInstance.GetName()})
 	return node, nil
 }
 
@@ -2314,6 +2400,11 @@ func (instance *Cluster) taskConfigureNodes(task concurrency.Task, _ concurrency
 	for i, node := range list {
 		captured := i
 		capturedNode := node
+		if state, xerr := instance.creationHostState(capturedNode.Name); xerr == nil && state == creationHostConfigured {
+			logrus.Debugf("[Cluster %s] node '%s' already configured, skipping", clusterName, capturedNode.Name)
+			continue
+		}
+
 		_, xerr = tg.Start(instance.taskConfigureNode, taskConfigureNodeParameters{
 			Index: captured + 1,
 			Node:  capturedNode,
@@ -2416,6 +2507,7 @@ func (instance *Cluster) taskConfigureNode(task concurrency.Task, params concurr
 
 	// Now configures node specifically for Cluster flavor
 	if instance.makers.ConfigureNode == nil {
+		instance.markCreationHost(hostInstance.GetName(), creationHostConfigured)
 		return nil, nil
 	}
 	xerr = instance.makers.ConfigureNode(instance, p.Index, hostInstance)
@@ -2426,6 +2518,8 @@ func (instance *Cluster) taskConfigureNode(task concurrency.Task, params concurr
 	}
 
 	logrus.Debugf("[%s] configuration successful.", hostLabel)
+	instance.markCreationHost(hostInstance.GetName(), creationHostConfigured)
+	server.ReportProgress(task.Context(), server.ProgressEvent{Phase: "configuration", Step: "node configured", Host: hostInstance.GetName()})
 	return nil, nil
 }
 
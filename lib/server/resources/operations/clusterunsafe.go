@@ -79,6 +79,17 @@ func (instance *Cluster) unsafeGetComplexity() (_ clustercomplexity.Enum, xerr f
 	return aci.Complexity, nil
 }
 
+// unsafeGetLabels returns the user-defined labels of the Cluster
+func (instance *Cluster) unsafeGetLabels() (labels map[string]string, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	xerr = instance.Inspect(func(_ data.Clonable, props *serialize.JSONProperties) (innerXErr fail.Error) {
+		labels, innerXErr = labelsFromProperties(props, clusterproperty.LabelsV1)
+		return innerXErr
+	})
+	return labels, xerr
+}
+
 // unsafeGetState returns the current state of the Cluster
 // Uses the "maker" ForceGetState
 func (instance *Cluster) unsafeGetState() (state clusterstate.Enum, xerr fail.Error) {
@@ -0,0 +1,651 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server"
+	"github.com/CS-SI/SafeScale/lib/server/resources"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterproperty"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterstate"
+	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
+	propertiesv3 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v3"
+	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/outputs"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/data/serialize"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/retry"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+// States reached by the hosts during an upgrade of a Cluster
+const (
+	upgradeHostUpgraded = "upgraded"
+	upgradeHostFailed   = "failed"
+)
+
+const (
+	// osUpgradeScript applies the updates of the operating system, using the package manager available
+	osUpgradeScript = `if command -v apt-get >/dev/null; then ` +
+		`sudo DEBIAN_FRONTEND=noninteractive apt-get update -q && sudo DEBIAN_FRONTEND=noninteractive apt-get -y -q -o Dpkg::Options::=--force-confold upgrade --with-new-pkgs; ` +
+		`elif command -v dnf >/dev/null; then sudo dnf -y -q upgrade; ` +
+		`elif command -v yum >/dev/null; then sudo yum -y -q update; ` +
+		`else echo "no supported package manager found" >&2; exit 1; fi`
+	// rebootRequiredCommand succeeds if the host has to be rebooted to complete the updates
+	rebootRequiredCommand = `test -f /var/run/reboot-required || (command -v needs-restarting >/dev/null && ! sudo needs-restarting -r >/dev/null)`
+)
+
+// Upgrade upgrades the hosts of the Cluster one after the other, the masters first then the nodes by groups of req.Parallelism:
+// each host is drained (flavors running Kubernetes), updated (operating system, or req.Feature installed again with req.Params),
+// rebooted if needed, checked then made schedulable again.
+// The upgrade stops on the first failure; its progress is recorded in Cluster metadata, allowing to resume it with resume set
+// to true (req is then ignored, the recorded one is used, and the hosts already upgraded are skipped)
+func (instance *Cluster) Upgrade(ctx context.Context, req abstract.ClusterUpgradeRequest, resume bool) (_ *propertiesv1.ClusterUpgrade, ferr fail.Error) {
+	defer fail.OnPanic(&ferr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	task, xerr := taskFromContextOrVoid(ctx)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.cluster"), "('%s', %v)", req.Feature, resume).Entering()
+	defer tracer.Exiting()
+	defer temporal.NewStopwatch().OnExitLogInfo(
+		fmt.Sprintf("Starting upgrade of Cluster '%s'...", instance.GetName()),
+		fmt.Sprintf("Ending upgrade of Cluster '%s'", instance.GetName()),
+	)()
+
+	// Note: instance.lock is not held during the upgrade: the hosts are only updated, and the features installed on them
+	//       may need to inspect the Cluster. The upgrade is registered as the activity of the Cluster instead, and the Cluster
+	//       stays in state Upgrading until the end of the attempt, keeping the autoscaler and the health checker away
+	xerr = instance.beginActivity(upgradeActivity)
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer instance.endActivity()
+
+	upgrade, xerr := instance.prepareUpgrade(req, resume)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	defer func() {
+		derr := instance.endUpgrade(ferr)
+		if derr != nil {
+			if ferr != nil {
+				_ = ferr.AddConsequence(fail.Wrap(derr, "failed to record the end of upgrade of Cluster '%s'", instance.GetName()))
+			} else {
+				ferr = derr
+			}
+		}
+		if ferr != nil {
+			logrus.Warnf("[Cluster %s] upgrade failed; it can be resumed with 'safescale cluster upgrade --resume %s'", instance.GetName(), instance.GetName())
+		}
+	}()
+
+	total := len(upgrade.Masters) + len(upgrade.Nodes)
+	var batches [][]string
+	for _, v := range upgrade.Masters {
+		if upgrade.Hosts[v] != upgradeHostUpgraded {
+			batches = append(batches, []string{v})
+		}
+	}
+	var pending []string
+	for _, v := range upgrade.Nodes {
+		if upgrade.Hosts[v] != upgradeHostUpgraded {
+			pending = append(pending, v)
+		}
+	}
+	parallelism := int(upgrade.Request.Parallelism)
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	for len(pending) > 0 {
+		count := parallelism
+		if count > len(pending) {
+			count = len(pending)
+		}
+		batches = append(batches, pending[:count])
+		pending = pending[count:]
+	}
+
+	done := total
+	for _, v := range batches {
+		done -= len(v)
+	}
+	for _, v := range batches {
+		if task.Aborted() {
+			return nil, fail.AbortedError(nil, "aborted")
+		}
+
+		server.ReportProgress(ctx, server.ProgressEvent{Phase: "upgrade", Step: fmt.Sprintf("upgrading %s", strings.Join(v, ", ")), Percent: uint32(done * 100 / total)})
+		xerr = instance.upgradeHosts(ctx, upgrade.Request, v)
+		if xerr != nil {
+			return nil, xerr
+		}
+		done += len(v)
+	}
+
+	xerr = instance.alterUpgrade(func(upgradeV1 *propertiesv1.ClusterUpgrade) fail.Error {
+		upgradeV1.Error = ""
+		upgradeV1.EndTime = time.Now()
+		upgradeV1.Completed = true
+		upgrade = upgradeV1.Clone().(*propertiesv1.ClusterUpgrade)
+		return nil
+	})
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	server.ReportProgress(ctx, server.ProgressEvent{Phase: "done", Step: "cluster upgraded", Percent: 100})
+	return upgrade, nil
+}
+
+// prepareUpgrade records a new upgrade of the Cluster or, if resume is true, returns the recorded unfinished one, and puts the
+// Cluster in state Upgrading
+// A new upgrade is refused while the last one is unfinished; hosts not belonging to the Cluster anymore are removed from a
+// resumed upgrade
+func (instance *Cluster) prepareUpgrade(req abstract.ClusterUpgradeRequest, resume bool) (_ *propertiesv1.ClusterUpgrade, xerr fail.Error) {
+	if !resume {
+		if req.Parallelism == 0 {
+			req.Parallelism = 1
+		}
+		if req.Feature != "" {
+			_, xerr = NewFeature(instance.GetService(), req.Feature)
+			if xerr != nil {
+				return nil, xerr
+			}
+		}
+	}
+
+	// make sure no other parallel actions interferes
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	xerr = instance.beingRemoved()
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	var upgrade *propertiesv1.ClusterUpgrade
+	xerr = instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		var state clusterstate.Enum
+		innerXErr := props.Inspect(clusterproperty.StateV1, func(clonable data.Clonable) fail.Error {
+			stateV1, ok := clonable.(*propertiesv1.ClusterState)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterState' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			state = stateV1.State
+			return nil
+		})
+		if innerXErr != nil {
+			return innerXErr
+		}
+		switch state {
+		case clusterstate.Nominal, clusterstate.Degraded:
+		case clusterstate.Upgrading:
+			// left by an attempt interrupted before its end (the activity of the Cluster guarantees none is running), the
+			// state recorded by the upgrade is restored at the end of the resumed attempt
+			if !resume {
+				return fail.InvalidRequestError("the last upgrade of Cluster '%s' has been interrupted, resume it with 'safescale cluster upgrade --resume %s'", instance.GetName(), instance.GetName())
+			}
+		default:
+			return fail.InvalidRequestError("cannot upgrade Cluster '%s' in state '%s'", instance.GetName(), state.String())
+		}
+
+		var masters, nodes []string
+		innerXErr = props.Inspect(clusterproperty.NodesV3, func(clonable data.Clonable) fail.Error {
+			nodesV3, ok := clonable.(*propertiesv3.ClusterNodes)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv3.ClusterNodes' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			for _, v := range nodesV3.Masters {
+				if node, ok := nodesV3.ByNumericalID[v]; ok {
+					masters = append(masters, node.Name)
+				}
+			}
+			for _, v := range nodesV3.PrivateNodes {
+				if node, ok := nodesV3.ByNumericalID[v]; ok {
+					nodes = append(nodes, node.Name)
+				}
+			}
+			return nil
+		})
+		if innerXErr != nil {
+			return innerXErr
+		}
+
+		innerXErr = props.Alter(clusterproperty.UpgradeV1, func(clonable data.Clonable) fail.Error {
+			upgradeV1, ok := clonable.(*propertiesv1.ClusterUpgrade)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterUpgrade' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			if resume {
+				if upgradeV1.IsNull() {
+					return fail.InvalidRequestError("there is no upgrade of Cluster '%s' to resume", instance.GetName())
+				}
+				if upgradeV1.Completed {
+					return fail.InvalidRequestError("the last upgrade of Cluster '%s' has already ended successfully", instance.GetName())
+				}
+
+				upgradeV1.Masters = keepMembers(upgradeV1.Masters, masters)
+				upgradeV1.Nodes = keepMembers(upgradeV1.Nodes, nodes)
+				upgradeV1.Error = ""
+			} else {
+				if !upgradeV1.IsNull() && !upgradeV1.Completed {
+					return fail.InvalidRequestError("the last upgrade of Cluster '%s' has not ended, resume it with 'safescale cluster upgrade --resume %s'", instance.GetName(), instance.GetName())
+				}
+
+				if req.SkipMasters {
+					masters = []string{}
+				}
+				_ = upgradeV1.Replace(&propertiesv1.ClusterUpgrade{
+					Request:   &req,
+					Masters:   masters,
+					Nodes:     nodes,
+					Hosts:     map[string]string{},
+					StartTime: time.Now(),
+				})
+			}
+			if state != clusterstate.Upgrading {
+				upgradeV1.PreviousState = state
+			}
+			upgrade = upgradeV1.Clone().(*propertiesv1.ClusterUpgrade)
+			return nil
+		})
+		if innerXErr != nil {
+			return innerXErr
+		}
+
+		return props.Alter(clusterproperty.StateV1, func(clonable data.Clonable) fail.Error {
+			stateV1, ok := clonable.(*propertiesv1.ClusterState)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterState' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			stateV1.State = clusterstate.Upgrading
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return upgrade, nil
+}
+
+// endUpgrade records the error of the attempt to upgrade the Cluster, if any, and restores the state the Cluster had before
+// the upgrade
+func (instance *Cluster) endUpgrade(attemptErr fail.Error) fail.Error {
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	return instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		state := clusterstate.Nominal
+		innerXErr := props.Alter(clusterproperty.UpgradeV1, func(clonable data.Clonable) fail.Error {
+			upgradeV1, ok := clonable.(*propertiesv1.ClusterUpgrade)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterUpgrade' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			if attemptErr != nil {
+				upgradeV1.Error = attemptErr.Error()
+			}
+			if upgradeV1.PreviousState != 0 {
+				state = upgradeV1.PreviousState
+			}
+			return nil
+		})
+		if innerXErr != nil {
+			return innerXErr
+		}
+
+		return props.Alter(clusterproperty.StateV1, func(clonable data.Clonable) fail.Error {
+			stateV1, ok := clonable.(*propertiesv1.ClusterState)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterState' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			if stateV1.State == clusterstate.Upgrading {
+				stateV1.State = state
+			}
+			return nil
+		})
+	})
+}
+
+// keepMembers returns the names of list present in members, keeping the order of list
+func keepMembers(list []string, members []string) []string {
+	out := make([]string, 0, len(list))
+	for _, v := range list {
+		for _, m := range members {
+			if v == m {
+				out = append(out, v)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// alterUpgrade calls callback to update the progress of the upgrade of the Cluster
+func (instance *Cluster) alterUpgrade(callback func(*propertiesv1.ClusterUpgrade) fail.Error) fail.Error {
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	return instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Alter(clusterproperty.UpgradeV1, func(clonable data.Clonable) fail.Error {
+			upgradeV1, ok := clonable.(*propertiesv1.ClusterUpgrade)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterUpgrade' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+			return callback(upgradeV1)
+		})
+	})
+}
+
+// upgradeHosts upgrades in parallel the hosts named in names, and records the state reached by each of them
+func (instance *Cluster) upgradeHosts(ctx context.Context, req *abstract.ClusterUpgradeRequest, names []string) fail.Error {
+	var (
+		mutex  sync.Mutex
+		wg     sync.WaitGroup
+		errors []error
+	)
+	for _, v := range names {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+
+			state := upgradeHostUpgraded
+			xerr := instance.upgradeHost(ctx, req, name)
+			if xerr != nil {
+				state = upgradeHostFailed
+				mutex.Lock()
+				errors = append(errors, fail.Wrap(xerr, "failed to upgrade host '%s'", name))
+				mutex.Unlock()
+			}
+
+			derr := instance.alterUpgrade(func(upgradeV1 *propertiesv1.ClusterUpgrade) fail.Error {
+				upgradeV1.Hosts[name] = state
+				return nil
+			})
+			if derr != nil {
+				// at worst, the host will be upgraded again on resume
+				logrus.Warnf("[Cluster %s] failed to record upgrade state of host '%s': %v", instance.GetName(), name, derr)
+			}
+		}(v)
+	}
+	wg.Wait()
+
+	switch len(errors) {
+	case 0:
+		return nil
+	case 1:
+		return fail.ConvertError(errors[0])
+	default:
+		return fail.NewErrorList(errors)
+	}
+}
+
+// upgradeHost drains the host, updates it, reboots it if needed, checks it then makes it schedulable again
+func (instance *Cluster) upgradeHost(ctx context.Context, req *abstract.ClusterUpgradeRequest, name string) fail.Error {
+	hostInstance, xerr := LoadHost(instance.GetService(), name)
+	if xerr != nil {
+		return xerr
+	}
+	defer hostInstance.Released()
+
+	var controller resources.Host
+	if instance.makers.DrainNode != nil || instance.makers.UncordonNode != nil {
+		controller, xerr = instance.findUpgradeController(ctx, name)
+		if xerr != nil {
+			return xerr
+		}
+		defer controller.Released()
+	}
+
+	server.ReportProgress(ctx, server.ProgressEvent{Phase: "upgrade", Step: "draining", Host: name})
+	if instance.makers.DrainNode != nil {
+		xerr = instance.makers.DrainNode(ctx, instance, hostInstance, controller)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return xerr
+		}
+	}
+
+	server.ReportProgress(ctx, server.ProgressEvent{Phase: "upgrade", Step: "updating", Host: name})
+	xerr = instance.applyUpgrade(ctx, hostInstance.(*Host), req)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	xerr = instance.rebootIfNeeded(ctx, hostInstance)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	server.ReportProgress(ctx, server.ProgressEvent{Phase: "upgrade", Step: "checking", Host: name})
+	_, xerr = hostInstance.WaitSSHReady(ctx, temporal.GetHostTimeout())
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	if instance.makers.UncordonNode != nil {
+		// the node must not receive workloads again before being back in the cluster
+		xerr = instance.waitNodeReady(ctx, name, controller)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return xerr
+		}
+
+		xerr = instance.makers.UncordonNode(ctx, instance, hostInstance, controller)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return xerr
+		}
+	}
+	return nil
+}
+
+// waitNodeReady waits for the flavor of the Cluster to report the host named 'name' as ready, using controller to get the
+// readiness of the nodes
+// Returns fail.ErrTimeout if the host is still not ready after the host timeout
+func (instance *Cluster) waitNodeReady(ctx context.Context, name string, controller resources.Host) fail.Error {
+	if instance.makers.NodesReadiness == nil {
+		return nil
+	}
+
+	server.ReportProgress(ctx, server.ProgressEvent{Phase: "upgrade", Step: "waiting for readiness", Host: name})
+	xerr := retry.WhileUnsuccessful(
+		func() error {
+			readiness, innerXErr := instance.makers.NodesReadiness(ctx, instance, controller)
+			if innerXErr != nil {
+				return innerXErr
+			}
+			if !readiness[name] {
+				return fail.NotAvailableError("node '%s' is not ready", name)
+			}
+			return nil
+		},
+		temporal.GetDefaultDelay(),
+		temporal.GetHostTimeout(),
+	)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *retry.ErrTimeout:
+			return fail.TimeoutError(fail.Cause(xerr), temporal.GetHostTimeout(), "node '%s' is still not ready, not made schedulable again", name)
+		case *retry.ErrStopRetry:
+			return fail.Wrap(fail.Cause(xerr), "stopping retries")
+		default:
+			return xerr
+		}
+	}
+	return nil
+}
+
+// findUpgradeController returns a master able to run orders on the Cluster while the host named 'upgraded' is upgraded:
+// the first master available other than this host, or this host if it is the only master available
+func (instance *Cluster) findUpgradeController(ctx context.Context, upgraded string) (resources.Host, fail.Error) {
+	instance.lock.RLock()
+	masters, xerr := instance.UnsafeListMasters()
+	instance.lock.RUnlock()
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	var fallback resources.Host
+	var lastError fail.Error = fail.NotFoundError("no master found")
+	for _, v := range masters {
+		master, xerr := LoadHost(instance.GetService(), v.ID)
+		if xerr != nil {
+			return nil, xerr
+		}
+
+		_, xerr = master.WaitSSHReady(ctx, temporal.GetConnectSSHTimeout())
+		if xerr != nil {
+			master.Released()
+			switch xerr.(type) {
+			case *retry.ErrTimeout:
+				lastError = xerr
+				continue
+			default:
+				return nil, xerr
+			}
+		}
+
+		if master.GetName() != upgraded {
+			if fallback != nil {
+				fallback.Released()
+			}
+			return master, nil
+		}
+		fallback = master
+	}
+	if fallback != nil {
+		return fallback, nil
+	}
+	return nil, lastError
+}
+
+// applyUpgrade installs again the feature of the request on the host or, if there is none, applies the updates of the operating system
+func (instance *Cluster) applyUpgrade(ctx context.Context, hostInstance *Host, req *abstract.ClusterUpgradeRequest) fail.Error {
+	if req.Feature != "" {
+		vars := data.Map{}
+		for k, v := range req.Params {
+			vars[k] = v
+		}
+		results, xerr := hostInstance.AddFeature(ctx, req.Feature, vars, resources.FeatureSettings{AddUnconditionally: true, SkipFeatureRequirements: true})
+		if xerr != nil {
+			return xerr
+		}
+		if !results.Successful() {
+			return fail.ExecutionError(nil, "failed to install Feature '%s' on host '%s': %s", req.Feature, hostInstance.GetName(), results.AllErrorMessages())
+		}
+		return nil
+	}
+
+	retcode, stdout, stderr, xerr := hostInstance.Run(ctx, osUpgradeScript, outputs.COLLECT, temporal.GetConnectionTimeout(), temporal.GetLongOperationTimeout())
+	if xerr != nil {
+		return xerr
+	}
+	if retcode != 0 {
+		xerr := fail.ExecutionError(nil, "failed to apply updates of operating system")
+		_ = xerr.Annotate("retcode", retcode)
+		_ = xerr.Annotate("stdout", stdout)
+		_ = xerr.Annotate("stderr", stderr)
+		return xerr
+	}
+	return nil
+}
+
+// rebootIfNeeded reboots the host if the updates applied require it
+func (instance *Cluster) rebootIfNeeded(ctx context.Context, hostInstance resources.Host) fail.Error {
+	retcode, _, _, xerr := hostInstance.Run(ctx, rebootRequiredCommand, outputs.COLLECT, temporal.GetConnectionTimeout(), temporal.GetExecutionTimeout())
+	if xerr != nil {
+		return xerr
+	}
+	if retcode != 0 {
+		return nil
+	}
+
+	logrus.Infof("[Cluster %s] rebooting host '%s' to complete its upgrade", instance.GetName(), hostInstance.GetName())
+	server.ReportProgress(ctx, server.ProgressEvent{Phase: "upgrade", Step: "rebooting", Host: hostInstance.GetName()})
+	return hostInstance.Reboot(ctx)
+}
+
+// GetUpgrade returns the progress of the last upgrade of the Cluster
+func (instance *Cluster) GetUpgrade(ctx context.Context) (_ *propertiesv1.ClusterUpgrade, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	instance.lock.RLock()
+	defer instance.lock.RUnlock()
+
+	var upgrade *propertiesv1.ClusterUpgrade
+	xerr = instance.Review(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Inspect(clusterproperty.UpgradeV1, func(clonable data.Clonable) fail.Error {
+			upgradeV1, ok := clonable.(*propertiesv1.ClusterUpgrade)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.ClusterUpgrade' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+			if upgradeV1.IsNull() {
+				return fail.NotFoundError("Cluster '%s' has never been upgraded", instance.GetName())
+			}
+
+			upgrade = upgradeV1.Clone().(*propertiesv1.ClusterUpgrade)
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return upgrade, nil
+}
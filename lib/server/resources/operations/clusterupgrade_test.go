@@ -0,0 +1,231 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clustercomplexity"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterflavor"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterproperty"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterstate"
+	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/data/serialize"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+func Test_keepMembers(t *testing.T) {
+	assert.Equal(t, []string{"node-3", "node-1"}, keepMembers([]string{"node-3", "node-2", "node-1"}, []string{"node-1", "node-3", "node-4"}))
+	assert.Empty(t, keepMembers([]string{"node-1"}, nil))
+}
+
+func Test_cluster_Upgrade(t *testing.T) {
+	svc := getMemoryService(t)
+	ctx := context.Background()
+
+	instance, xerr := NewCluster(svc)
+	require.Nil(t, xerr)
+	req := abstract.ClusterRequest{Name: "upgraded", Flavor: clusterflavor.BOH, Complexity: clustercomplexity.Small, InitialNodeCount: 1}
+	require.Nil(t, instance.firstLight(req))
+
+	setState := func(state clusterstate.Enum) {
+		xerr := instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+			return props.Alter(clusterproperty.StateV1, func(clonable data.Clonable) fail.Error {
+				stateV1, ok := clonable.(*propertiesv1.ClusterState)
+				require.True(t, ok, reflect.TypeOf(clonable).String())
+				stateV1.State = state
+				return nil
+			})
+		})
+		require.Nil(t, xerr)
+	}
+
+	setState(clusterstate.Stopped)
+	_, xerr = instance.Upgrade(ctx, abstract.ClusterUpgradeRequest{}, false)
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrInvalidRequest{}, xerr)
+
+	setState(clusterstate.Nominal)
+	_, xerr = instance.GetUpgrade(ctx)
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrNotFound{}, xerr)
+
+	_, xerr = instance.Upgrade(ctx, abstract.ClusterUpgradeRequest{}, true)
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrInvalidRequest{}, xerr)
+
+	_, xerr = instance.Upgrade(ctx, abstract.ClusterUpgradeRequest{Feature: "unknown-feature"}, false)
+	require.NotNil(t, xerr)
+
+	// Without hosts recorded, the upgrade ends immediately
+	upgrade, xerr := instance.Upgrade(ctx, abstract.ClusterUpgradeRequest{}, false)
+	require.Nil(t, xerr)
+	assert.True(t, upgrade.Completed)
+	assert.EqualValues(t, 1, upgrade.Request.Parallelism)
+
+	upgrade, xerr = instance.GetUpgrade(ctx)
+	require.Nil(t, xerr)
+	assert.True(t, upgrade.Completed)
+	assert.False(t, upgrade.EndTime.IsZero())
+
+	_, xerr = instance.Upgrade(ctx, abstract.ClusterUpgradeRequest{}, true)
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrInvalidRequest{}, xerr)
+
+	// Another activity running on the Cluster prevents the upgrade
+	require.Nil(t, instance.beginActivity("repaired"))
+	_, xerr = instance.Upgrade(ctx, abstract.ClusterUpgradeRequest{}, false)
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrNotAvailable{}, xerr)
+	assert.Contains(t, xerr.Error(), "being repaired")
+	instance.endActivity()
+
+	// An unfinished upgrade, interrupted in state Upgrading, can only be resumed
+	xerr = instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Alter(clusterproperty.UpgradeV1, func(clonable data.Clonable) fail.Error {
+			upgradeV1, ok := clonable.(*propertiesv1.ClusterUpgrade)
+			require.True(t, ok, reflect.TypeOf(clonable).String())
+			upgradeV1.Completed = false
+			upgradeV1.PreviousState = clusterstate.Degraded
+			return nil
+		})
+	})
+	require.Nil(t, xerr)
+	setState(clusterstate.Upgrading)
+	_, xerr = instance.Upgrade(ctx, abstract.ClusterUpgradeRequest{}, false)
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrInvalidRequest{}, xerr)
+	assert.Contains(t, xerr.Error(), "--resume")
+
+	upgrade, xerr = instance.Upgrade(ctx, abstract.ClusterUpgradeRequest{}, true)
+	require.Nil(t, xerr)
+	assert.True(t, upgrade.Completed)
+	state, xerr := instance.GetState()
+	require.Nil(t, xerr)
+	assert.Equal(t, clusterstate.Degraded, state)
+}
+
+func Test_cluster_beginActivity(t *testing.T) {
+	svc := getMemoryService(t)
+
+	instance, xerr := NewCluster(svc)
+	require.Nil(t, xerr)
+	require.Nil(t, instance.firstLight(abstract.ClusterRequest{Name: "busy", Flavor: clusterflavor.BOH, Complexity: clustercomplexity.Small}))
+	other, xerr := NewCluster(svc)
+	require.Nil(t, xerr)
+	require.Nil(t, other.firstLight(abstract.ClusterRequest{Name: "idle", Flavor: clusterflavor.BOH, Complexity: clustercomplexity.Small}))
+
+	require.Nil(t, instance.beginActivity(upgradeActivity))
+	xerr = instance.beginActivity("autoscaled")
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrNotAvailable{}, xerr)
+	assert.Contains(t, xerr.Error(), "being upgraded")
+
+	require.Nil(t, other.beginActivity("autoscaled"))
+	other.endActivity()
+
+	instance.endActivity()
+	require.Nil(t, instance.beginActivity("autoscaled"))
+	instance.endActivity()
+}
+
+func Test_cluster_beginNodesChange(t *testing.T) {
+	svc := getMemoryService(t)
+	ctx := context.Background()
+
+	instance, xerr := NewCluster(svc)
+	require.Nil(t, xerr)
+	require.Nil(t, instance.firstLight(abstract.ClusterRequest{Name: "changed", Flavor: clusterflavor.BOH, Complexity: clustercomplexity.Small}))
+
+	// the nodes cannot be changed while the Cluster is being upgraded
+	require.Nil(t, instance.beginActivity(upgradeActivity))
+	changes := map[string]func() fail.Error{
+		"expand": func() fail.Error {
+			_, xerr := instance.AddNodes(ctx, 1, abstract.HostSizingRequirements{}, false)
+			return xerr
+		},
+		"shrink": func() fail.Error {
+			_, xerr := instance.Shrink(ctx, 1)
+			return xerr
+		},
+		"delete node": func() fail.Error {
+			return instance.DeleteSpecificNode(ctx, "node-1", "")
+		},
+		"resize node pool": func() fail.Error {
+			_, xerr := instance.ResizeNodePool(ctx, "pool", 1, false)
+			return xerr
+		},
+		"restore": func() fail.Error {
+			_, xerr := instance.Restore(ctx, "bucket", "backup")
+			return xerr
+		},
+	}
+	for name, change := range changes {
+		xerr = change()
+		require.NotNil(t, xerr, name)
+		assert.IsType(t, &fail.ErrNotAvailable{}, xerr, name)
+		assert.Contains(t, xerr.Error(), "being upgraded", name)
+	}
+	instance.endActivity()
+
+	// nor while its last upgrade is interrupted
+	xerr = instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Alter(clusterproperty.StateV1, func(clonable data.Clonable) fail.Error {
+			stateV1, ok := clonable.(*propertiesv1.ClusterState)
+			require.True(t, ok, reflect.TypeOf(clonable).String())
+			stateV1.State = clusterstate.Upgrading
+			return nil
+		})
+	})
+	require.Nil(t, xerr)
+	xerr = instance.beginNodesChange()
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrNotAvailable{}, xerr)
+	assert.Contains(t, xerr.Error(), "--resume")
+	xerr = instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Alter(clusterproperty.StateV1, func(clonable data.Clonable) fail.Error {
+			stateV1, ok := clonable.(*propertiesv1.ClusterState)
+			require.True(t, ok, reflect.TypeOf(clonable).String())
+			stateV1.State = clusterstate.Nominal
+			return nil
+		})
+	})
+	require.Nil(t, xerr)
+
+	// the upgrade cannot begin until all the changes of the nodes, nested or not, are done; the other activities can
+	require.Nil(t, instance.beginNodesChange())
+	require.Nil(t, instance.beginNodesChange())
+	require.Nil(t, instance.beginActivity("autoscaled"))
+	instance.endActivity()
+	_, xerr = instance.Upgrade(ctx, abstract.ClusterUpgradeRequest{}, false)
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrNotAvailable{}, xerr)
+	assert.Contains(t, xerr.Error(), "being changed")
+	instance.endNodesChange()
+	xerr = instance.beginActivity(upgradeActivity)
+	require.NotNil(t, xerr)
+	instance.endNodesChange()
+	require.Nil(t, instance.beginActivity(upgradeActivity))
+	instance.endActivity()
+}
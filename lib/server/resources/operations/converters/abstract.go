@@ -17,7 +17,12 @@
 package converters
 
 import (
+	"encoding/json"
+	"time"
+
 	"github.com/CS-SI/SafeScale/lib/protocol"
+	"github.com/CS-SI/SafeScale/lib/server/audit"
+	"github.com/CS-SI/SafeScale/lib/server/manifest"
 	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterstate"
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/hoststate"
@@ -82,10 +87,15 @@ func HostTemplateFromAbstractToProtocol(in abstract.HostTemplate) *protocol.Host
 
 // ImageFromAbstractToProtocol ...
 func ImageFromAbstractToProtocol(in *abstract.Image) *protocol.Image {
-	return &protocol.Image{
-		Id:   in.ID,
-		Name: in.Name,
+	out := &protocol.Image{
+		Id:          in.ID,
+		Name:        in.Name,
+		Description: in.Description,
+	}
+	if in.HostID != "" {
+		out.Host = &protocol.Reference{Id: in.HostID}
 	}
+	return out
 }
 
 // NetworkFromAbstractToProtocol ...
@@ -355,9 +365,126 @@ func SecurityGroupFromAbstractToProtocol(in abstract.SecurityGroup) *protocol.Se
 	}
 }
 
+// ClusterKubeconfigFromAbstractToProtocol converts an abstract.ClusterKubeconfig of the cluster named 'name' to protocol.ClusterKubeconfig
+func ClusterKubeconfigFromAbstractToProtocol(name string, in abstract.ClusterKubeconfig) *protocol.ClusterKubeconfig {
+	return &protocol.ClusterKubeconfig{
+		Name:      name,
+		Content:   in.Content,
+		Server:    in.Server,
+		ApiServer: in.APIServer,
+		Gateway:   in.Gateway,
+	}
+}
+
+// SecurityGroupDriftFromAbstractToProtocol converts an *abstract.SecurityGroupDrift to a *protocol.SecurityGroupDrift
+func SecurityGroupDriftFromAbstractToProtocol(in *abstract.SecurityGroupDrift) *protocol.SecurityGroupDrift {
+	out := &protocol.SecurityGroupDrift{
+		Id:      in.ID,
+		Name:    in.Name,
+		Added:   SecurityGroupRulesFromAbstractToProtocol(in.Added),
+		Removed: SecurityGroupRulesFromAbstractToProtocol(in.Removed),
+		Changed: make([]*protocol.SecurityGroupRuleChange, 0, len(in.Changed)),
+		InSync:  in.IsEmpty(),
+		Fixed:   in.Fixed,
+	}
+	for _, v := range in.Changed {
+		out.Changed = append(out.Changed, &protocol.SecurityGroupRuleChange{
+			Expected: SecurityGroupRuleFromAbstractToProtocol(*v.Expected),
+			Actual:   SecurityGroupRulesFromAbstractToProtocol(v.Actual),
+		})
+	}
+	return out
+}
+
+// ReconcileEntryFromAbstractToProtocol converts an *abstract.ReconcileEntry to a *protocol.TenantReconcileEntry
+func ReconcileEntryFromAbstractToProtocol(in *abstract.ReconcileEntry) *protocol.TenantReconcileEntry {
+	return &protocol.TenantReconcileEntry{
+		Kind:    in.Kind,
+		Id:      in.ID,
+		Name:    in.Name,
+		Status:  in.Status,
+		Details: in.Details,
+		Action:  in.Action,
+		Applied: in.Applied,
+		Error:   in.Error,
+	}
+}
+
 // ClusterStateFromAbstractToProtocol ...
 func ClusterStateFromAbstractToProtocol(in clusterstate.Enum) *protocol.ClusterStateResponse {
 	return &protocol.ClusterStateResponse{
 		State: protocol.ClusterState(in),
 	}
 }
+
+// PublicIPFromAbstractToProtocol converts an *abstract.PublicIP to a *protocol.PublicIPResponse
+func PublicIPFromAbstractToProtocol(in *abstract.PublicIP) *protocol.PublicIPResponse {
+	out := &protocol.PublicIPResponse{
+		Id:          in.ID,
+		Name:        in.Name,
+		Type:        in.Type,
+		Description: in.Description,
+		IpAddress:   in.IPAddress,
+		MacAddress:  in.MacAddress,
+	}
+	if in.HostID != "" {
+		out.Host = &protocol.Reference{Id: in.HostID}
+	}
+	return out
+}
+
+// SnapshotFromAbstractToProtocol converts an *abstract.Snapshot to a *protocol.VolumeSnapshotResponse
+func SnapshotFromAbstractToProtocol(in *abstract.Snapshot) *protocol.VolumeSnapshotResponse {
+	out := &protocol.VolumeSnapshotResponse{
+		Id:          in.ID,
+		Name:        in.Name,
+		Description: in.Description,
+		Volume:      &protocol.Reference{Id: in.VolumeID},
+		Size:        int32(in.Size),
+		State:       in.State.String(),
+	}
+	if !in.CreatedAt.IsZero() {
+		out.CreatedAt = in.CreatedAt.Format(time.RFC3339)
+	}
+	return out
+}
+
+// ManifestPlanFromAbstractToProtocol converts a manifest.Plan to protocol.ManifestPlan
+func ManifestPlanFromAbstractToProtocol(in manifest.Plan) *protocol.ManifestPlan {
+	out := &protocol.ManifestPlan{Steps: make([]*protocol.ManifestStep, 0, len(in))}
+	for _, v := range in {
+		out.Steps = append(out.Steps, &protocol.ManifestStep{
+			Kind:   v.Kind,
+			Name:   v.Name,
+			Action: string(v.Action),
+			Detail: v.Detail,
+			Drift:  v.Drift,
+		})
+	}
+	return out
+}
+
+// AuditRecordsFromAbstractToProtocol converts a slice of *audit.Record to protocol.AuditListResponse
+func AuditRecordsFromAbstractToProtocol(in []*audit.Record) *protocol.AuditListResponse {
+	out := &protocol.AuditListResponse{Records: make([]*protocol.AuditRecord, 0, len(in))}
+	for _, v := range in {
+		item := &protocol.AuditRecord{
+			Time:       v.Time.Format(time.RFC3339Nano),
+			Identity:   v.Identity,
+			Tenant:     v.Tenant,
+			Action:     v.Action,
+			Resource:   v.Resource,
+			JobId:      v.JobID,
+			DurationMs: v.DurationMs,
+			Outcome:    v.Outcome,
+			Error:      v.Error,
+		}
+		if len(v.Parameters) > 0 {
+			if jsoned, err := json.Marshal(v.Parameters); err == nil {
+				item.Parameters = string(jsoned)
+			}
+		}
+		out.Records = append(out.Records, item)
+	}
+	return out
+}
@@ -20,6 +20,7 @@ package converters
 
 import (
 	"strings"
+	"time"
 
 	"github.com/CS-SI/SafeScale/lib/protocol"
 	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
@@ -147,6 +148,7 @@ func ClusterFeaturesFromPropertyToProtocol(in propertiesv1.ClusterFeatures) (*pr
 			FileName:   v.FileName,
 			RequiredBy: requiredBy,
 			Requires:   requires,
+			Version:    v.Version,
 		}
 		installed.Features = append(installed.Features, item)
 	}
@@ -246,3 +248,168 @@ func ClusterDefaultsPropertyV1ToV2(in *propertiesv1.ClusterDefaults) *properties
 	}
 	return out
 }
+
+// ClusterNodePoolFromPropertyToProtocol converts a node pool to protocol message, using nodes to describe the members of the pool
+func ClusterNodePoolFromPropertyToProtocol(in propertiesv1.ClusterNodePool, nodes map[uint]*propertiesv3.ClusterNode) *protocol.ClusterNodePool {
+	sizing := HostSizingRequirementsFromAbstractToProtocol(in.Sizing)
+	out := &protocol.ClusterNodePool{
+		Name:     in.Name,
+		Sizing:   &sizing,
+		ImageId:  in.Image,
+		MinCount: uint32(in.MinCount),
+		MaxCount: uint32(in.MaxCount),
+		Labels:   make(map[string]string, len(in.Labels)),
+		Taints:   make([]string, len(in.Taints)),
+		Nodes:    make([]*protocol.Host, 0, len(in.Nodes)),
+	}
+	for k, v := range in.Labels {
+		out.Labels[k] = v
+	}
+	copy(out.Taints, in.Taints)
+	for _, v := range in.Nodes {
+		if node, ok := nodes[v]; ok {
+			out.Nodes = append(out.Nodes, ClusterNodeFromPropertyToProtocol(*node))
+		}
+	}
+	return out
+}
+
+// ClusterAutoscalingFromPropertyToProtocol converts autoscaling settings of the cluster named 'name' to protocol message
+func ClusterAutoscalingFromPropertyToProtocol(name string, in propertiesv1.ClusterAutoscaling) *protocol.ClusterAutoscaling {
+	out := &protocol.ClusterAutoscaling{
+		Name:               name,
+		Enabled:            in.Enabled,
+		MinNodes:           uint32(in.MinNodes),
+		MaxNodes:           uint32(in.MaxNodes),
+		ScaleUpThreshold:   in.ScaleUpThreshold,
+		ScaleDownThreshold: in.ScaleDownThreshold,
+		Cooldown:           uint32(in.Cooldown / time.Second),
+		Step:               uint32(in.Step),
+		Decisions:          make([]*protocol.ClusterScaleDecision, 0, len(in.Decisions)),
+	}
+	if !in.LastScaleTime.IsZero() {
+		out.LastScaleTime = in.LastScaleTime.Format(time.RFC3339)
+	}
+	for _, v := range in.Decisions {
+		out.Decisions = append(out.Decisions, &protocol.ClusterScaleDecision{
+			Time:   v.Time.Format(time.RFC3339),
+			Action: v.Action,
+			Count:  uint32(v.Count),
+			Nodes:  uint32(v.Nodes),
+			Load:   v.Load,
+			Reason: v.Reason,
+			Error:  v.Error,
+		})
+	}
+	return out
+}
+
+// ClusterUpgradeFromPropertyToProtocol converts the progress of the upgrade of the cluster named 'name' to protocol message
+func ClusterUpgradeFromPropertyToProtocol(name string, in propertiesv1.ClusterUpgrade) *protocol.ClusterUpgradeResponse {
+	out := &protocol.ClusterUpgradeResponse{
+		Name:      name,
+		Masters:   make([]string, len(in.Masters)),
+		Nodes:     make([]string, len(in.Nodes)),
+		Hosts:     make(map[string]string, len(in.Hosts)),
+		Error:     in.Error,
+		Completed: in.Completed,
+	}
+	if in.Request != nil {
+		out.Feature = in.Request.Feature
+		out.Params = make(map[string]string, len(in.Request.Params))
+		for k, v := range in.Request.Params {
+			out.Params[k] = v
+		}
+		out.Parallelism = uint32(in.Request.Parallelism)
+	}
+	copy(out.Masters, in.Masters)
+	copy(out.Nodes, in.Nodes)
+	for k, v := range in.Hosts {
+		out.Hosts[k] = v
+	}
+	if !in.StartTime.IsZero() {
+		out.StartTime = in.StartTime.Format(time.RFC3339)
+	}
+	if !in.EndTime.IsZero() {
+		out.EndTime = in.EndTime.Format(time.RFC3339)
+	}
+	return out
+}
+
+// ClusterBackupFromPropertyToProtocol converts a backup of a cluster to protocol message
+func ClusterBackupFromPropertyToProtocol(in propertiesv1.ClusterBackup) *protocol.ClusterBackup {
+	out := &protocol.ClusterBackup{
+		Id:      in.ID,
+		Bucket:  in.Bucket,
+		Objects: make([]string, len(in.Objects)),
+		Size:    in.Size,
+	}
+	copy(out.Objects, in.Objects)
+	if !in.CreationTime.IsZero() {
+		out.CreationTime = in.CreationTime.Format(time.RFC3339)
+	}
+	return out
+}
+
+// ClusterBackupsFromPropertyToProtocol converts the backups of the cluster named 'name' to protocol message
+func ClusterBackupsFromPropertyToProtocol(name string, in propertiesv1.ClusterBackups) *protocol.ClusterBackupList {
+	out := &protocol.ClusterBackupList{
+		Name:          name,
+		Backups:       make([]*protocol.ClusterBackup, 0, len(in.Backups)),
+		LastRestoreId: in.LastRestoreID,
+	}
+	for _, v := range in.Backups {
+		out.Backups = append(out.Backups, ClusterBackupFromPropertyToProtocol(*v))
+	}
+	if !in.LastRestoreTime.IsZero() {
+		out.LastRestoreTime = in.LastRestoreTime.Format(time.RFC3339)
+	}
+	return out
+}
+
+// ClusterHealthFromPropertyToProtocol converts the health check settings of the cluster named 'name', its last repairs and
+// the health of its masters and nodes to protocol message
+func ClusterHealthFromPropertyToProtocol(name string, in propertiesv1.ClusterHealth, nodes propertiesv3.ClusterNodes) *protocol.ClusterHealth {
+	out := &protocol.ClusterHealth{
+		Name:             name,
+		AutoRepair:       in.AutoRepair,
+		FailureThreshold: uint32(in.FailureThreshold),
+		Hosts:            make([]*protocol.ClusterNodeHealth, 0, len(nodes.Masters)+len(nodes.PrivateNodes)),
+		Repairs:          make([]*protocol.ClusterNodeRepair, 0, len(in.Repairs)),
+	}
+	if !in.LastCheck.IsZero() {
+		out.LastCheck = in.LastCheck.Format(time.RFC3339)
+	}
+
+	convertNodes := func(list []uint, master bool) {
+		for _, v := range list {
+			node, ok := nodes.ByNumericalID[v]
+			if !ok {
+				continue
+			}
+
+			item := &protocol.ClusterNodeHealth{Name: node.Name, Master: master}
+			if health, ok := nodes.Health[v]; ok {
+				item.Status = health.Status
+				item.Reason = health.Reason
+				item.Failures = uint32(health.Failures)
+				item.Since = health.Since.Format(time.RFC3339)
+				item.LastCheck = health.LastCheck.Format(time.RFC3339)
+			}
+			out.Hosts = append(out.Hosts, item)
+		}
+	}
+	convertNodes(nodes.Masters, true)
+	convertNodes(nodes.PrivateNodes, false)
+
+	for _, v := range in.Repairs {
+		out.Repairs = append(out.Repairs, &protocol.ClusterNodeRepair{
+			Time:        v.Time.Format(time.RFC3339),
+			Node:        v.Node,
+			Replacement: v.Replacement,
+			Reason:      v.Reason,
+			Error:       v.Error,
+		})
+	}
+	return out
+}
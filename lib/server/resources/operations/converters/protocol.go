@@ -20,8 +20,10 @@ package converters
 
 import (
 	"strings"
+	"time"
 
 	"github.com/CS-SI/SafeScale/lib/protocol"
+	"github.com/CS-SI/SafeScale/lib/server/manifest"
 	"github.com/CS-SI/SafeScale/lib/server/resources"
 	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clustercomplexity"
@@ -29,6 +31,7 @@ import (
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/hoststate"
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/ipversion"
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/securitygroupruledirection"
+	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
 	"github.com/CS-SI/SafeScale/lib/system"
 	"github.com/CS-SI/SafeScale/lib/utils/fail"
 )
@@ -178,6 +181,7 @@ func ClusterRequestFromProtocolToAbstract(in *protocol.ClusterCreateRequest) (_
 		Force:                   in.Force,
 		DisabledDefaultFeatures: disabled,
 		InitialNodeCount:        uint(nodeCount),
+		Labels:                  in.GetLabels(),
 	}
 	return out, nil
 }
@@ -256,3 +260,55 @@ func HostStateFromProtocolToEnum(in protocol.HostState) hoststate.Enum {
 	}
 	return hoststate.Unknown
 }
+
+// ManifestPlanFromProtocolToAbstract converts a protocol.ManifestPlan to manifest.Plan
+func ManifestPlanFromProtocolToAbstract(in *protocol.ManifestPlan) manifest.Plan {
+	out := make(manifest.Plan, 0, len(in.GetSteps()))
+	for _, v := range in.GetSteps() {
+		out = append(out, manifest.Step{
+			Kind:   v.GetKind(),
+			Name:   v.GetName(),
+			Action: manifest.Action(v.GetAction()),
+			Detail: v.GetDetail(),
+			Drift:  v.GetDrift(),
+		})
+	}
+	return out
+}
+
+// ClusterAutoscalingFromProtocolToProperty converts autoscaling settings from protocol message; history of scale decisions is ignored
+func ClusterAutoscalingFromProtocolToProperty(in *protocol.ClusterAutoscaling) propertiesv1.ClusterAutoscaling {
+	return propertiesv1.ClusterAutoscaling{
+		Enabled:            in.GetEnabled(),
+		MinNodes:           uint(in.GetMinNodes()),
+		MaxNodes:           uint(in.GetMaxNodes()),
+		ScaleUpThreshold:   in.GetScaleUpThreshold(),
+		ScaleDownThreshold: in.GetScaleDownThreshold(),
+		Cooldown:           time.Duration(in.GetCooldown()) * time.Second,
+		Step:               uint(in.GetStep()),
+	}
+}
+
+// ClusterHealthFromProtocolToProperty converts health check settings of a cluster from protocol message
+func ClusterHealthFromProtocolToProperty(in *protocol.ClusterHealth) propertiesv1.ClusterHealth {
+	return propertiesv1.ClusterHealth{
+		AutoRepair:       in.GetAutoRepair(),
+		FailureThreshold: uint(in.GetFailureThreshold()),
+	}
+}
+
+// ClusterUpgradeRequestFromProtocolToAbstract converts an upgrade request from protocol message
+func ClusterUpgradeRequestFromProtocolToAbstract(in *protocol.ClusterUpgradeRequest) abstract.ClusterUpgradeRequest {
+	out := abstract.ClusterUpgradeRequest{
+		Feature:     in.GetFeature(),
+		Parallelism: uint(in.GetParallelism()),
+		SkipMasters: in.GetSkipMasters(),
+	}
+	if len(in.GetParams()) > 0 {
+		out.Params = make(map[string]string, len(in.GetParams()))
+		for k, v := range in.GetParams() {
+			out.Params[k] = v
+		}
+	}
+	return out
+}
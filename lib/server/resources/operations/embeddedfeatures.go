@@ -236,6 +236,22 @@ func kubernetesFeature() *Feature {
 	}
 }
 
+// k3sFeature ...
+func k3sFeature() *Feature {
+	name := "k3s"
+	filename, specs, err := loadSpecFile(name)
+	err = debug.InjectPlannedError(err)
+	if err != nil {
+		panic(err.Error())
+	}
+	return &Feature{
+		displayName: name,
+		fileName:    filename,
+		embedded:    true,
+		specs:       specs,
+	}
+}
+
 // helm2Feature ...
 func helm2Feature() *Feature {
 	name := "helm2"
@@ -22,6 +22,7 @@ import (
 	"io/ioutil"
 	"strings"
 
+	"github.com/Masterminds/semver"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 
@@ -105,7 +106,7 @@ func ListFeatures(svc iaas.Service, suitableFor string) (_ []interface{}, xerr f
 			yamlKey := "feature.suitableFor.cluster"
 			if feat.Specs().IsSet(yamlKey) {
 				values := strings.Split(strings.ToLower(feat.Specs().GetString(yamlKey)), ",")
-				if values[0] == "all" || values[0] == "k8s" || values[0] == "boh" {
+				if values[0] == "all" || values[0] == "k8s" || values[0] == "k3s" || values[0] == "boh" {
 					cfg := struct {
 						FeatureName    string   `json:"feature"`
 						ClusterFlavors []string `json:"available-cluster-flavors"`
@@ -607,7 +608,135 @@ func (f *Feature) Remove(ctx context.Context, target resources.Targetable, v dat
 	return results, target.UnregisterFeature(f.GetName())
 }
 
-const yamlKey = "feature.requirements.features"
+// Upgrade updates the Feature installed on the target to the version of its specification file
+// Upgrade succeeds if error == nil and Results.Successful() is true; nothing is done if the installed version is not older
+func (f *Feature) Upgrade(ctx context.Context, target resources.Targetable, v data.Map, s resources.FeatureSettings) (_ resources.Results, ferr fail.Error) {
+	defer fail.OnPanic(&ferr)
+
+	if f.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if target == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("target")
+	}
+
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotAvailable:
+			task, xerr = concurrency.VoidTask()
+			if xerr != nil {
+				return nil, xerr
+			}
+		default:
+			return nil, xerr
+		}
+	}
+
+	featureName := f.GetName()
+	targetName := target.GetName()
+	targetType := target.TargetType().String()
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.features"), "(): '%s' on %s '%s'", featureName, targetType, targetName).WithStopwatch().Entering()
+	defer tracer.Exiting()
+	defer fail.OnExitLogError(&ferr, tracer.TraceMessage(""))
+
+	version := f.GetVersion()
+	if version == "" {
+		return nil, fail.InvalidRequestError("Feature '%s' does not declare a version, it cannot be upgraded", featureName)
+	}
+
+	installer, xerr := f.findInstallerForTarget(target, "upgrade")
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	results, xerr := f.Check(ctx, target, v, s)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, fail.Wrap(xerr, "failed to check Feature '%s'", featureName)
+	}
+	if !results.Successful() {
+		return nil, fail.NotFoundError("Feature '%s' is not installed on %s '%s'", featureName, targetType, targetName)
+	}
+
+	installedVersion := target.InstalledFeatureVersion(featureName)
+	older, xerr := isVersionOlder(installedVersion, version)
+	if xerr != nil {
+		return nil, xerr
+	}
+	if !older {
+		logrus.Infof("Feature '%s' is already up to date on %s '%s' (version %s).", featureName, targetType, targetName, installedVersion)
+		return results, nil
+	}
+
+	defer temporal.NewStopwatch().OnExitLogInfo(
+		fmt.Sprintf("Starting upgrade of Feature '%s' on %s '%s' to version %s...", featureName, targetType, targetName, version),
+		fmt.Sprintf("Ending upgrade of Feature '%s' on %s '%s'", featureName, targetType, targetName),
+	)()
+
+	// 'v' may be updated by concurrent tasks, so use copy of it
+	myV := v.Clone()
+
+	// Inits target parameters
+	xerr = target.ComplementFeatureParameters(ctx, myV)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	// Checks required parameters have value
+	xerr = checkParameters(*f, myV)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	if !s.SkipFeatureRequirements {
+		xerr = f.installRequirements(ctx, target, v, s)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return nil, fail.Wrap(xerr, "failed to install requirements")
+		}
+	}
+
+	results, xerr = installer.Upgrade(ctx, f, target, myV, s)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return results, xerr
+	}
+
+	// Records the new version only if the upgrade succeeded everywhere
+	if !results.Successful() {
+		return results, nil
+	}
+
+	xerr = registerOnSuccessfulHostsInCluster(f.svc, target, f, nil, results)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return results, target.RegisterFeature(f, nil, target.TargetType() == featuretargettype.Cluster)
+}
+
+const (
+	yamlKey        = "feature.requirements.features"
+	yamlVersionKey = "feature.version"
+)
+
+// GetVersion returns the version declared in the specification file of the Feature, empty string if none
+func (f *Feature) GetVersion() string {
+	if f.IsNull() {
+		return ""
+	}
+	return strings.TrimSpace(f.specs.GetString(yamlVersionKey))
+}
 
 // GetRequirements returns a list of features needed as requirements
 func (f *Feature) GetRequirements() (map[string]struct{}, fail.Error) {
@@ -616,66 +745,248 @@ func (f *Feature) GetRequirements() (map[string]struct{}, fail.Error) {
 		return emptyMap, fail.InvalidInstanceError()
 	}
 
-	out := make(map[string]struct{}, len(f.specs.GetStringSlice(yamlKey)))
-	for _, r := range f.specs.GetStringSlice(yamlKey) {
-		out[r] = struct{}{}
+	requirements, xerr := f.parseRequirements()
+	if xerr != nil {
+		return emptyMap, xerr
+	}
+
+	out := make(map[string]struct{}, len(requirements))
+	for _, r := range requirements {
+		out[r.name] = struct{}{}
 	}
 	return out, nil
 }
 
-// installRequirements walks through requirements and installs them if needed
-func (f *Feature) installRequirements(ctx context.Context, t resources.Targetable, v data.Map, s resources.FeatureSettings) fail.Error {
-	if f.specs.IsSet(yamlKey) {
-		{
-			msgHead := fmt.Sprintf("Checking requirements of Feature '%s'", f.GetName())
-			var msgTail string
-			switch t.TargetType() {
-			case featuretargettype.Host:
-				msgTail = fmt.Sprintf("on host '%s'", t.(data.Identifiable).GetName())
-			case featuretargettype.Node:
-				msgTail = fmt.Sprintf("on cluster node '%s'", t.(data.Identifiable).GetName())
-			case featuretargettype.Cluster:
-				msgTail = fmt.Sprintf("on cluster '%s'", t.(data.Identifiable).GetName())
+// featureRequirement describes a Feature required by another one, with an optional constraint on its version
+type featureRequirement struct {
+	name       string
+	constraint string
+}
+
+// parseFeatureRequirement parses an entry of 'feature.requirements.features', in the form '<name>[ <version constraint>]'
+// (for example 'docker', 'docker >= 19.03' or 'kubernetes >=1.20, <1.22')
+func parseFeatureRequirement(entry string) (featureRequirement, fail.Error) {
+	entry = strings.TrimSpace(entry)
+	idx := strings.IndexAny(entry, " \t<>=!~^")
+	if idx == -1 {
+		return featureRequirement{name: entry}, nil
+	}
+
+	out := featureRequirement{
+		name:       entry[:idx],
+		constraint: strings.TrimSpace(entry[idx:]),
+	}
+	if out.name == "" {
+		return featureRequirement{}, fail.SyntaxError("invalid requirement '%s': missing Feature name", entry)
+	}
+	if _, err := semver.NewConstraint(out.constraint); err != nil {
+		return featureRequirement{}, fail.SyntaxError("invalid version constraint '%s' for required Feature '%s': %s", out.constraint, out.name, err.Error())
+	}
+	return out, nil
+}
+
+// satisfiedBy tells if version satisfies the constraint of the requirement; an unknown version never satisfies a constraint
+func (r featureRequirement) satisfiedBy(version string) (bool, fail.Error) {
+	if r.constraint == "" {
+		return true, nil
+	}
+	if version == "" {
+		return false, nil
+	}
+
+	c, err := semver.NewConstraint(r.constraint)
+	if err != nil {
+		return false, fail.SyntaxError("invalid version constraint '%s' for required Feature '%s': %s", r.constraint, r.name, err.Error())
+	}
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return false, fail.SyntaxError("invalid version '%s' of Feature '%s': %s", version, r.name, err.Error())
+	}
+	return c.Check(v), nil
+}
+
+// needsUpgrade tells if the required Feature installed in version 'installed' has to be upgraded to satisfy the constraint
+// An installed Feature whose version is unknown (installed before versions were recorded) is never upgraded implicitly,
+// the upgrade has to be requested explicitly
+func (r featureRequirement) needsUpgrade(installed string) (bool, fail.Error) {
+	if r.constraint != "" && installed == "" {
+		return false, fail.InvalidRequestError("Feature '%s' is required in version %s, but the version installed is unknown; upgrade it explicitly first", r.name, r.constraint)
+	}
+
+	ok, xerr := r.satisfiedBy(installed)
+	if xerr != nil {
+		return false, xerr
+	}
+	return !ok, nil
+}
+
+// isVersionOlder tells if installed is older than available; an unknown installed version is considered as older
+func isVersionOlder(installed, available string) (bool, fail.Error) {
+	av, err := semver.NewVersion(available)
+	if err != nil {
+		return false, fail.SyntaxError("invalid version '%s': %s", available, err.Error())
+	}
+	if installed == "" {
+		return true, nil
+	}
+
+	iv, err := semver.NewVersion(installed)
+	if err != nil {
+		return false, fail.SyntaxError("invalid installed version '%s': %s", installed, err.Error())
+	}
+	return iv.LessThan(av), nil
+}
+
+// parseRequirements returns the requirements of the Feature, in the order of the specification file
+func (f *Feature) parseRequirements() ([]featureRequirement, fail.Error) {
+	entries := f.specs.GetStringSlice(yamlKey)
+	out := make([]featureRequirement, 0, len(entries))
+	for _, e := range entries {
+		r, xerr := parseFeatureRequirement(e)
+		if xerr != nil {
+			return nil, fail.Wrap(xerr, "syntax error in Feature '%s' specification file (%s)", f.GetName(), f.GetDisplayFilename())
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+// checkRequirementsGraph walks through the graph of requirements of the Feature, failing on dependency cycle
+// or if the version of a required Feature does not satisfy the constraint set on it
+func (f *Feature) checkRequirementsGraph() fail.Error {
+	return f.walkRequirements([]string{f.GetName()}, map[string]struct{}{})
+}
+
+// walkRequirements is the recursive part of checkRequirementsGraph
+// 'path' contains the Features being walked through from the root, 'done' the Features whose requirements are already validated
+func (f *Feature) walkRequirements(path []string, done map[string]struct{}) fail.Error {
+	requirements, xerr := f.parseRequirements()
+	if xerr != nil {
+		return xerr
+	}
+
+	for _, r := range requirements {
+		for _, p := range path {
+			if p == r.name {
+				return fail.InvalidRequestError("dependency cycle between Features: %s", strings.Join(append(path, r.name), " -> "))
 			}
-			logrus.Debugf("%s %s...", msgHead, msgTail)
 		}
 
-		targetIsCluster := t.TargetType() == featuretargettype.Cluster
+		needed, xerr := NewFeature(f.svc, r.name)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return fail.Wrap(xerr, "failed to find required Feature '%s'", r.name)
+		}
 
-		// clone FeatureSettings to set DoNotUpdateHostMetadataInClusterContext
-		for _, requirement := range f.specs.GetStringSlice(yamlKey) {
-			needed, xerr := NewFeature(f.svc, requirement)
-			xerr = debug.InjectPlannedFail(xerr)
-			if xerr != nil {
-				return fail.Wrap(xerr, "failed to find required Feature '%s'", requirement)
+		neededVersion := needed.GetVersion()
+		ok, xerr := r.satisfiedBy(neededVersion)
+		if xerr != nil {
+			return xerr
+		}
+		if !ok {
+			if neededVersion == "" {
+				return fail.InvalidRequestError("Feature '%s' requires Feature '%s' %s, which does not declare a version", f.GetName(), r.name, r.constraint)
 			}
+			return fail.InvalidRequestError("Feature '%s' requires Feature '%s' %s, but version %s is available", f.GetName(), r.name, r.constraint, neededVersion)
+		}
+
+		if _, ok := done[r.name]; ok {
+			continue
+		}
+		xerr = needed.(*Feature).walkRequirements(append(path[:len(path):len(path)], r.name), done)
+		if xerr != nil {
+			return xerr
+		}
+		done[r.name] = struct{}{}
+	}
+	return nil
+}
+
+// installRequirements walks through requirements and installs them if needed; a required Feature already installed
+// is upgraded if its installed version does not satisfy the constraint set on it
+func (f *Feature) installRequirements(ctx context.Context, t resources.Targetable, v data.Map, s resources.FeatureSettings) fail.Error {
+	requirements, xerr := f.parseRequirements()
+	if xerr != nil {
+		return xerr
+	}
+	if len(requirements) == 0 {
+		return nil
+	}
+
+	{
+		msgHead := fmt.Sprintf("Checking requirements of Feature '%s'", f.GetName())
+		var msgTail string
+		switch t.TargetType() {
+		case featuretargettype.Host:
+			msgTail = fmt.Sprintf("on host '%s'", t.(data.Identifiable).GetName())
+		case featuretargettype.Node:
+			msgTail = fmt.Sprintf("on cluster node '%s'", t.(data.Identifiable).GetName())
+		case featuretargettype.Cluster:
+			msgTail = fmt.Sprintf("on cluster '%s'", t.(data.Identifiable).GetName())
+		}
+		logrus.Debugf("%s %s...", msgHead, msgTail)
+	}
+
+	// Validates the whole graph before installing anything
+	xerr = f.checkRequirementsGraph()
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	targetIsCluster := t.TargetType() == featuretargettype.Cluster
 
-			results, xerr := needed.Check(ctx, t, v, s)
+	// clone FeatureSettings to set DoNotUpdateHostMetadataInClusterContext
+	for _, requirement := range requirements {
+		needed, xerr := NewFeature(f.svc, requirement.name)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return fail.Wrap(xerr, "failed to find required Feature '%s'", requirement.name)
+		}
+
+		results, xerr := needed.Check(ctx, t, v, s)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return fail.Wrap(xerr, "failed to check required Feature '%s' for Feature '%s'", requirement.name, f.GetName())
+		}
+
+		if !results.Successful() {
+			results, xerr := needed.Add(ctx, t, v, s)
 			xerr = debug.InjectPlannedFail(xerr)
 			if xerr != nil {
-				return fail.Wrap(xerr, "failed to check required Feature '%s' for Feature '%s'", requirement, f.GetName())
+				return fail.Wrap(xerr, "failed to install required Feature '%s'", requirement.name)
 			}
 
 			if !results.Successful() {
-				results, xerr := needed.Add(ctx, t, v, s)
-				xerr = debug.InjectPlannedFail(xerr)
-				if xerr != nil {
-					return fail.Wrap(xerr, "failed to install required Feature '%s'", requirement)
-				}
-
-				if !results.Successful() {
-					return fail.NewError("failed to install required Feature '%s':\n%s", requirement, results.AllErrorMessages())
-				}
+				return fail.NewError("failed to install required Feature '%s':\n%s", requirement.name, results.AllErrorMessages())
+			}
 
-				// Register the needed Feature as a requirement for f
-				xerr = t.RegisterFeature(needed, f, targetIsCluster)
-				xerr = debug.InjectPlannedFail(xerr)
-				if xerr != nil {
-					return xerr
-				}
+			// Register the needed Feature as a requirement for f
+			xerr = t.RegisterFeature(needed, f, targetIsCluster)
+			xerr = debug.InjectPlannedFail(xerr)
+			if xerr != nil {
+				return xerr
 			}
+			continue
+		}
+
+		installedVersion := t.InstalledFeatureVersion(requirement.name)
+		upgrade, xerr := requirement.needsUpgrade(installedVersion)
+		if xerr != nil {
+			return fail.Wrap(xerr, "cannot satisfy requirements of Feature '%s' on %s '%s'", f.GetName(), t.TargetType().String(), t.GetName())
 		}
+		if upgrade {
+			logrus.Infof("Feature '%s' requires Feature '%s' %s, upgrading it from version '%s' to %s", f.GetName(), requirement.name, requirement.constraint, installedVersion, needed.GetVersion())
+			results, xerr := needed.Upgrade(ctx, t, v, s)
+			xerr = debug.InjectPlannedFail(xerr)
+			if xerr != nil {
+				return fail.Wrap(xerr, "failed to upgrade required Feature '%s'", requirement.name)
+			}
 
+			if !results.Successful() {
+				return fail.NewError("failed to upgrade required Feature '%s':\n%s", requirement.name, results.AllErrorMessages())
+			}
+		}
 	}
 	return nil
 }
@@ -743,6 +1054,7 @@ func (f Feature) ToProtocol() *protocol.FeatureResponse {
 	out := &protocol.FeatureResponse{
 		Name:     f.GetName(),
 		FileName: f.GetDisplayFilename(),
+		Version:  f.GetVersion(),
 	}
 	return out
 }
@@ -0,0 +1,198 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clustercomplexity"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterflavor"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterproperty"
+	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/data/serialize"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// registerTestFeature makes available a Feature named 'name' with the specification 'content', for the duration of the test
+func registerTestFeature(t *testing.T, svc iaas.Service, name, content string) *Feature {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	require.Nil(t, v.ReadConfig(strings.NewReader(content)))
+
+	f := &Feature{
+		displayName:     name,
+		fileName:        name + ".yml",
+		displayFileName: name + ".yml [test]",
+		specs:           v,
+		svc:             svc,
+	}
+	allEmbeddedFeaturesMap[name] = f
+	t.Cleanup(func() { delete(allEmbeddedFeaturesMap, name) })
+	return f
+}
+
+func Test_parseFeatureRequirement(t *testing.T) {
+	r, xerr := parseFeatureRequirement("docker")
+	require.Nil(t, xerr)
+	assert.Equal(t, featureRequirement{name: "docker"}, r)
+
+	r, xerr = parseFeatureRequirement(" docker >= 19.03")
+	require.Nil(t, xerr)
+	assert.Equal(t, featureRequirement{name: "docker", constraint: ">= 19.03"}, r)
+
+	r, xerr = parseFeatureRequirement("kubernetes>=1.20, <1.22")
+	require.Nil(t, xerr)
+	assert.Equal(t, "kubernetes", r.name)
+	ok, xerr := r.satisfiedBy("1.21.3")
+	require.Nil(t, xerr)
+	assert.True(t, ok)
+	ok, xerr = r.satisfiedBy("1.22.0")
+	require.Nil(t, xerr)
+	assert.False(t, ok)
+	ok, xerr = r.satisfiedBy("")
+	require.Nil(t, xerr)
+	assert.False(t, ok)
+
+	_, xerr = parseFeatureRequirement(">= 1.0")
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrSyntax{}, xerr)
+
+	_, xerr = parseFeatureRequirement("docker >= not-a-version")
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrSyntax{}, xerr)
+}
+
+func Test_featureRequirement_needsUpgrade(t *testing.T) {
+	r := featureRequirement{name: "docker", constraint: ">= 19.03"}
+
+	upgrade, xerr := r.needsUpgrade("18.09.1")
+	require.Nil(t, xerr)
+	assert.True(t, upgrade)
+
+	upgrade, xerr = r.needsUpgrade("20.10.0")
+	require.Nil(t, xerr)
+	assert.False(t, upgrade)
+
+	// a Feature installed before versions were recorded is not upgraded implicitly
+	_, xerr = r.needsUpgrade("")
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrInvalidRequest{}, xerr)
+
+	upgrade, xerr = featureRequirement{name: "docker"}.needsUpgrade("")
+	require.Nil(t, xerr)
+	assert.False(t, upgrade)
+}
+
+func Test_isVersionOlder(t *testing.T) {
+	older, xerr := isVersionOlder("", "1.0.0")
+	require.Nil(t, xerr)
+	assert.True(t, older)
+
+	older, xerr = isVersionOlder("1.0.0", "1.1.0")
+	require.Nil(t, xerr)
+	assert.True(t, older)
+
+	older, xerr = isVersionOlder("1.1.0", "1.1.0")
+	require.Nil(t, xerr)
+	assert.False(t, older)
+
+	older, xerr = isVersionOlder("2.0.0", "1.1.0")
+	require.Nil(t, xerr)
+	assert.False(t, older)
+
+	_, xerr = isVersionOlder("1.0.0", "")
+	assert.NotNil(t, xerr)
+}
+
+func Test_Feature_checkRequirementsGraph(t *testing.T) {
+	svc := getMemoryService(t)
+
+	registerTestFeature(t, svc, "test-base", "feature:\n  version: 1.2.0\n")
+	registerTestFeature(t, svc, "test-unversioned", "feature:\n  suitableFor:\n    host: yes\n")
+	registerTestFeature(t, svc, "test-left", "feature:\n  version: 1.0.0\n  requirements:\n    features:\n      - test-base >= 1.1\n")
+	registerTestFeature(t, svc, "test-right", "feature:\n  requirements:\n    features:\n      - test-base\n")
+	diamond := registerTestFeature(t, svc, "test-diamond", "feature:\n  requirements:\n    features:\n      - test-left\n      - test-right\n")
+
+	assert.Nil(t, diamond.checkRequirementsGraph())
+	requirements, xerr := diamond.GetRequirements()
+	require.Nil(t, xerr)
+	assert.Equal(t, map[string]struct{}{"test-left": {}, "test-right": {}}, requirements)
+
+	tooOld := registerTestFeature(t, svc, "test-too-old", "feature:\n  requirements:\n    features:\n      - test-base >= 2.0\n")
+	xerr = tooOld.checkRequirementsGraph()
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrInvalidRequest{}, xerr)
+	assert.Contains(t, xerr.Error(), "version 1.2.0 is available")
+
+	noVersion := registerTestFeature(t, svc, "test-no-version", "feature:\n  requirements:\n    features:\n      - test-unversioned ~1\n")
+	xerr = noVersion.checkRequirementsGraph()
+	require.NotNil(t, xerr)
+	assert.Contains(t, xerr.Error(), "does not declare a version")
+
+	registerTestFeature(t, svc, "test-cycle-b", "feature:\n  requirements:\n    features:\n      - test-cycle-c\n")
+	registerTestFeature(t, svc, "test-cycle-c", "feature:\n  requirements:\n    features:\n      - test-base\n      - test-cycle-a\n")
+	cycle := registerTestFeature(t, svc, "test-cycle-a", "feature:\n  requirements:\n    features:\n      - test-cycle-b\n")
+	xerr = cycle.checkRequirementsGraph()
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrInvalidRequest{}, xerr)
+	assert.Contains(t, xerr.Error(), "test-cycle-a -> test-cycle-b -> test-cycle-c -> test-cycle-a")
+}
+
+func Test_Cluster_RegisterFeature(t *testing.T) {
+	svc := getMemoryService(t)
+
+	instance, xerr := NewCluster(svc)
+	require.Nil(t, xerr)
+	req := abstract.ClusterRequest{Name: "featured", Flavor: clusterflavor.BOH, Complexity: clustercomplexity.Small}
+	require.Nil(t, instance.firstLight(req))
+
+	requires := func() map[string]struct{} {
+		var out map[string]struct{}
+		xerr := instance.Inspect(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+			return props.Inspect(clusterproperty.FeaturesV1, func(clonable data.Clonable) fail.Error {
+				featuresV1, ok := clonable.(*propertiesv1.ClusterFeatures)
+				require.True(t, ok, reflect.TypeOf(clonable).String())
+				out = featuresV1.Installed["test-upgraded"].Requires
+				return nil
+			})
+		})
+		require.Nil(t, xerr)
+		return out
+	}
+
+	registerTestFeature(t, svc, "test-base", "feature:\n  version: 1.2.0\n")
+	registerTestFeature(t, svc, "test-other", "feature:\n  version: 1.0.0\n")
+	v1 := registerTestFeature(t, svc, "test-upgraded", "feature:\n  version: 1.0.0\n  requirements:\n    features:\n      - test-base\n")
+	require.Nil(t, instance.RegisterFeature(v1, nil, true))
+	assert.Equal(t, "1.0.0", instance.InstalledFeatureVersion("test-upgraded"))
+	assert.Equal(t, map[string]struct{}{"test-base": {}}, requires())
+
+	// the requirements of the new version replace the ones of the previous version
+	v2 := registerTestFeature(t, svc, "test-upgraded", "feature:\n  version: 2.0.0\n  requirements:\n    features:\n      - test-other\n")
+	require.Nil(t, instance.RegisterFeature(v2, nil, true))
+	assert.Equal(t, "2.0.0", instance.InstalledFeatureVersion("test-upgraded"))
+	assert.Equal(t, map[string]struct{}{"test-other": {}}, requires())
+}
@@ -691,6 +691,9 @@ func (instance *Host) Create(ctx context.Context, hostReq abstract.HostRequest,
 	if ctx == nil {
 		return nil, fail.InvalidParameterCannotBeNilError("ctx")
 	}
+	if xerr := abstract.ValidateLabels(hostReq.Labels); xerr != nil {
+		return nil, xerr
+	}
 
 	task, xerr := concurrency.TaskFromContext(ctx)
 	xerr = debug.InjectPlannedFail(xerr)
@@ -922,6 +925,7 @@ func (instance *Host) Create(ctx context.Context, hostReq abstract.HostRequest,
 
 			hostSizingV2.AllocatedSize = converters.HostEffectiveSizingFromAbstractToPropertyV2(ahf.Sizing)
 			hostSizingV2.RequestedSize = converters.HostSizingRequirementsFromAbstractToPropertyV2(hostDef)
+			hostSizingV2.Template = hostDef.Template
 			return nil
 		})
 		if innerXErr != nil {
@@ -936,26 +940,19 @@ func (instance *Host) Create(ctx context.Context, hostReq abstract.HostRequest,
 			}
 
 			_ = hostDescriptionV1.Replace(converters.HostDescriptionFromAbstractToPropertyV1(*ahf.Description))
-			creator := ""
-			hostname, _ := os.Hostname()
-			if curUser, err := user.Current(); err == nil {
-				creator = curUser.Username
-				if hostname != "" {
-					creator += "@" + hostname
-				}
-				if curUser.Name != "" {
-					creator += " (" + curUser.Name + ")"
-				}
-			} else {
-				creator = "unknown@" + hostname
-			}
-			hostDescriptionV1.Creator = creator
+			hostDescriptionV1.Creator = currentCreator()
 			return nil
 		})
 		if innerXErr != nil {
 			return innerXErr
 		}
 
+		// Sets Host extension LabelsV1
+		innerXErr = labelsToProperties(props, hostproperty.LabelsV1, hostReq.Labels)
+		if innerXErr != nil {
+			return innerXErr
+		}
+
 		// Updates Host property propertiesv2.HostNetworking
 		return props.Alter(hostproperty.NetworkV2, func(clonable data.Clonable) fail.Error {
 			hnV2, ok := clonable.(*propertiesv2.HostNetworking)
@@ -1074,6 +1071,24 @@ func (instance *Host) Create(ctx context.Context, hostReq abstract.HostRequest,
 	return userdataContent, nil
 }
 
+// currentCreator returns the description of the user running the daemon, recorded as creator of Hosts
+func currentCreator() string {
+	creator := ""
+	hostname, _ := os.Hostname()
+	if curUser, err := user.Current(); err == nil {
+		creator = curUser.Username
+		if hostname != "" {
+			creator += "@" + hostname
+		}
+		if curUser.Name != "" {
+			creator += " (" + curUser.Name + ")"
+		}
+	} else {
+		creator = "unknown@" + hostname
+	}
+	return creator
+}
+
 func determineImageID(svc iaas.Service, imageRef string) (string, string, fail.Error) {
 	if imageRef == "" {
 		cfg, xerr := svc.GetConfigurationOptions()
@@ -1489,6 +1504,8 @@ func (instance *Host) runInstallPhase(ctx context.Context, phase userdata.Phase,
 	return nil
 }
 
+// waitInstallPhase waits until the install phase 'phase' is done on the Host, and returns the status reported by the Host
+// (formatted as "<phase>,<os type>,<os flavor>")
 func (instance *Host) waitInstallPhase(ctx context.Context, phase userdata.Phase, timeout time.Duration) (string, fail.Error) {
 	givenTimeout := int(timeout.Minutes())
 	sshDefaultTimeout := int(temporal.GetHostTimeout().Minutes())
@@ -2113,6 +2130,62 @@ func (instance *Host) RelaxedDeleteHost(ctx context.Context) (xerr fail.Error) {
 		return xerr
 	}
 
+	// Unbind Public IPs from the Host, to prevent the provider to release them with the Host
+	var publicIPs []string
+	xerr = instance.Inspect(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Inspect(hostproperty.PublicIPsV1, func(clonable data.Clonable) fail.Error {
+			hpiV1, ok := clonable.(*propertiesv1.HostPublicIPs)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.HostPublicIPs' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			for k := range hpiV1.ByID {
+				publicIPs = append(publicIPs, k)
+			}
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+	for _, v := range publicIPs {
+		pipInstance, xerr := LoadPublicIP(svc, v)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			switch xerr.(type) {
+			case *fail.ErrNotFound:
+				// Public IP metadata not found, only clean up Host metadata
+				debug.IgnoreError(xerr)
+				if xerr = removePublicIPFromHostMetadata(instance, v); xerr != nil {
+					return xerr
+				}
+				continue
+			default:
+				return xerr
+			}
+		}
+
+		defer func(pip resources.PublicIP) { // nolint
+			pip.Released()
+		}(pipInstance)
+
+		xerr = pipInstance.UnbindFromHost(ctx, instance)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			switch xerr.(type) {
+			case *fail.ErrNotFound:
+				// Public IP metadata do not reference the Host, only clean up Host metadata
+				debug.IgnoreError(xerr)
+				if xerr = removePublicIPFromHostMetadata(instance, v); xerr != nil {
+					return xerr
+				}
+			default:
+				return fail.Wrap(xerr, "failed to unbind Public IP '%s' from Host '%s'", v, instance.GetName())
+			}
+		}
+	}
+
 	var (
 		single         bool
 		singleSubnetID string
@@ -3140,6 +3213,7 @@ func (instance *Host) ToProtocol() (ph *protocol.Host, xerr fail.Error) {
 		hostSizingV1  *propertiesv1.HostSizing
 		hostVolumesV1 *propertiesv1.HostVolumes
 		volumes       []string
+		labels        map[string]string
 	)
 
 	publicIP := instance.publicIP
@@ -3152,6 +3226,11 @@ func (instance *Host) ToProtocol() (ph *protocol.Host, xerr fail.Error) {
 			return fail.InconsistentError("'*abstract.HostCore' expected, '%s' provided", reflect.TypeOf(clonable).String())
 		}
 
+		var innerXErr fail.Error
+		if labels, innerXErr = labelsFromProperties(props, hostproperty.LabelsV1); innerXErr != nil {
+			return innerXErr
+		}
+
 		return props.Inspect(hostproperty.SizingV1, func(clonable data.Clonable) fail.Error {
 			hostSizingV1, ok = clonable.(*propertiesv1.HostSizing)
 			if !ok {
@@ -3189,10 +3268,29 @@ func (instance *Host) ToProtocol() (ph *protocol.Host, xerr fail.Error) {
 		State:               protocol.HostState(ahc.LastState),
 		StateLabel:          ahc.LastState.String(),
 		AttachedVolumeNames: volumes,
+		Labels:              labels,
 	}
 	return ph, nil
 }
 
+// GetLabels returns the user-defined labels of the Host
+func (instance *Host) GetLabels() (labels map[string]string, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	instance.lock.RLock()
+	defer instance.lock.RUnlock()
+
+	xerr = instance.Inspect(func(_ data.Clonable, props *serialize.JSONProperties) (innerXErr fail.Error) {
+		labels, innerXErr = labelsFromProperties(props, hostproperty.LabelsV1)
+		return innerXErr
+	})
+	return labels, xerr
+}
+
 // BindSecurityGroup binds a security group to the Host; if enabled is true, apply it immediately
 func (instance *Host) BindSecurityGroup(ctx context.Context, sgInstance resources.SecurityGroup, enable resources.SecurityGroupActivation) (xerr fail.Error) {
 	defer fail.OnPanic(&xerr)
@@ -0,0 +1,434 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/resources"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/hostproperty"
+	"github.com/CS-SI/SafeScale/lib/server/resources/operations/converters"
+	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
+	propertiesv2 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v2"
+	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/outputs"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/data/serialize"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+const (
+	// safescaleBinariesFeature is the feature installing SafeScale binaries on a Host
+	safescaleBinariesFeature = "safescale-binaries"
+	// detectSystemCommand outputs the type and the flavor of the system of a Host
+	detectSystemCommand = `uname -s | tr '[:upper:]' '[:lower:]' && . /etc/os-release && echo "$ID"`
+)
+
+// injectKeyCommand returns the command run on an imported Host to create the operator user if needed, and to authorize its new public key
+func injectKeyCommand(user, publicKey string) string {
+	return fmt.Sprintf(`sudo bash -c 'id -u %[1]s >/dev/null 2>&1 || useradd -m -s /bin/bash %[1]s
+echo "%[1]s ALL=(ALL) NOPASSWD:ALL" >/etc/sudoers.d/10-%[1]s && chmod 0440 /etc/sudoers.d/10-%[1]s
+home=$(getent passwd %[1]s | cut -d: -f6)
+mkdir -p $home/.ssh && echo "%[2]s" >>$home/.ssh/authorized_keys
+chown -R %[1]s: $home/.ssh && chmod 0700 $home/.ssh && chmod 0600 $home/.ssh/authorized_keys'`, user, strings.TrimSpace(publicKey))
+}
+
+// parseDetectedSystem returns the type and the flavor of the system from the output of detectSystemCommand
+func parseDetectedSystem(out string) (string, string, fail.Error) {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) < 2 {
+		return "", "", fail.SyntaxError("unexpected output '%s' detecting system", out)
+	}
+	return strings.TrimSpace(lines[0]), strings.Trim(strings.TrimSpace(lines[1]), `"`), nil
+}
+
+// Import registers in metadata an existing Host of the provider, then handles it as a Host created by SafeScale
+// SSH access (and so installation of safescale-binaries) is available only if req.SSHPrivateKey is provided
+func (instance *Host) Import(ctx context.Context, req abstract.HostImportRequest) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil {
+		return fail.InvalidInstanceError()
+	}
+	if !instance.IsNull() {
+		return fail.InvalidInstanceContentError("instance", "is not null value, cannot overwrite")
+	}
+	if ctx == nil {
+		return fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if req.Ref = strings.TrimSpace(req.Ref); req.Ref == "" {
+		return fail.InvalidParameterError("req.Ref", "cannot be empty string")
+	}
+	if req.InjectKey && req.SSHPrivateKey == "" {
+		return fail.InvalidRequestError("cannot inject SafeScale SSH key without SSH access to the Host")
+	}
+	if req.InstallBinaries && req.SSHPrivateKey == "" {
+		return fail.InvalidRequestError("cannot install '%s' without SSH access to the Host", safescaleBinariesFeature)
+	}
+
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotAvailable:
+			task, xerr = concurrency.VoidTask()
+			if xerr != nil {
+				return xerr
+			}
+		default:
+			return xerr
+		}
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.host"), "('%s')", req.Ref).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	xerr = instance.unsafeImport(ctx, req)
+	if xerr != nil {
+		return xerr
+	}
+
+	// From here, the Host is imported; the following steps need the Host to be unlocked
+	hostName := instance.GetName()
+	for _, v := range req.SecurityGroups {
+		sgInstance, xerr := LoadSecurityGroup(instance.GetService(), v)
+		if xerr != nil {
+			return fail.Wrap(xerr, "Host '%s' imported, but failed to load Security Group '%s'", hostName, v)
+		}
+
+		xerr = instance.BindSecurityGroup(ctx, sgInstance, resources.SecurityGroupEnable)
+		sgInstance.Released()
+		if xerr != nil {
+			return fail.Wrap(xerr, "Host '%s' imported, but failed to bind Security Group '%s'", hostName, v)
+		}
+	}
+
+	if req.InstallBinaries {
+		results, xerr := instance.AddFeature(ctx, safescaleBinariesFeature, data.Map{}, resources.FeatureSettings{})
+		if xerr != nil {
+			return fail.Wrap(xerr, "Host '%s' imported, but failed to install '%s'", hostName, safescaleBinariesFeature)
+		}
+		if !results.Successful() {
+			return fail.ExecutionError(nil, "Host '%s' imported, but failed to install '%s': %s", hostName, safescaleBinariesFeature, results.AllErrorMessages())
+		}
+	}
+
+	logrus.Infof("Host '%s' imported successfully", hostName)
+	return nil
+}
+
+// unsafeImport creates the metadata of the Host to import, removing it on failure
+func (instance *Host) unsafeImport(ctx context.Context, req abstract.HostImportRequest) (ferr fail.Error) {
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	svc := instance.GetService()
+	if existing, xerr := LoadHost(svc, req.Ref); xerr == nil {
+		existing.Released()
+		return fail.DuplicateError("cannot import Host '%s': there is already such a Host in metadata", req.Ref)
+	}
+
+	ahf, xerr := svc.InspectHost(req.Ref)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+	if ahf.Core.Name == "" {
+		return fail.InconsistentError("cannot import Host '%s': the provider does not give it a name", req.Ref)
+	}
+	if ahf.Core.ID != req.Ref {
+		if existing, xerr := LoadHost(svc, ahf.Core.ID); xerr == nil {
+			existing.Released()
+			return fail.DuplicateError("cannot import Host '%s': there is already such a Host in metadata", req.Ref)
+		}
+	}
+	if existing, xerr := LoadHost(svc, ahf.Core.Name); xerr == nil {
+		existing.Released()
+		return fail.DuplicateError("cannot import Host '%s': there is already a Host named '%s' in metadata", req.Ref, ahf.Core.Name)
+	}
+
+	opUser, xerr := getOperatorUsernameFromCfg(svc)
+	if xerr != nil {
+		return xerr
+	}
+	if req.SSHUser == "" {
+		req.SSHUser = opUser
+	}
+
+	// The Host will be reached with the operator user, using either the key given or a new one injected
+	var keyPair *abstract.KeyPair
+	if req.InjectKey {
+		keyPair, xerr = abstract.NewKeyPair("")
+		if xerr != nil {
+			return xerr
+		}
+		ahf.Core.PrivateKey = keyPair.PrivateKey
+	} else {
+		if req.SSHPrivateKey != "" && req.SSHUser != opUser {
+			return fail.InvalidRequestError("the SSH key of user '%s' cannot be used by SafeScale without injecting its own key for user '%s'", req.SSHUser, opUser)
+		}
+		ahf.Core.PrivateKey = req.SSHPrivateKey
+	}
+	if req.SSHPort > 0 {
+		ahf.Core.SSHPort = req.SSHPort
+	} else {
+		ahf.Core.SSHPort = 22
+	}
+	ahf.Core.LastState = ahf.CurrentState
+
+	// Infers the Subnets of the Host known by SafeScale; the Host is single if its default Subnet has no gateway created by SafeScale
+	subnets, defaultSubnetID, xerr := inferImportedHostSubnets(svc, ahf.Networking)
+	if xerr != nil {
+		return xerr
+	}
+	var defaultSubnet *abstract.Subnet
+	for _, v := range subnets {
+		if v.ID == defaultSubnetID {
+			defaultSubnet = v
+		}
+	}
+	single := defaultSubnet == nil || len(defaultSubnet.GatewayIDs) == 0
+
+	xerr = instance.carry(ahf.Core)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	defer func() {
+		if ferr != nil {
+			if derr := instance.MetadataCore.Delete(); derr != nil {
+				_ = ferr.AddConsequence(fail.Wrap(derr, "cleaning up on %s, failed to delete Host '%s' metadata", ActionFromError(ferr), ahf.Core.Name))
+			}
+		}
+	}()
+
+	xerr = instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		innerXErr := props.Alter(hostproperty.SizingV2, func(clonable data.Clonable) fail.Error {
+			hostSizingV2, ok := clonable.(*propertiesv2.HostSizing)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv2.HostSizing' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			hostSizingV2.AllocatedSize = converters.HostEffectiveSizingFromAbstractToPropertyV2(ahf.Sizing)
+			return nil
+		})
+		if innerXErr != nil {
+			return innerXErr
+		}
+
+		innerXErr = props.Alter(hostproperty.DescriptionV1, func(clonable data.Clonable) fail.Error {
+			hostDescriptionV1, ok := clonable.(*propertiesv1.HostDescription)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.HostDescription' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			_ = hostDescriptionV1.Replace(converters.HostDescriptionFromAbstractToPropertyV1(*ahf.Description))
+			hostDescriptionV1.Creator = currentCreator() + " (imported)"
+			return nil
+		})
+		if innerXErr != nil {
+			return innerXErr
+		}
+
+		return props.Alter(hostproperty.NetworkV2, func(clonable data.Clonable) fail.Error {
+			hnV2, ok := clonable.(*propertiesv2.HostNetworking)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv2.HostNetworking' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			_ = hnV2.Replace(converters.HostNetworkingFromAbstractToPropertyV2(*ahf.Networking))
+			hnV2.DefaultSubnetID = defaultSubnetID
+			hnV2.Single = single
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	xerr = instance.updateCachedInformation()
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	// -- binds the Host to the Security Groups of its Subnets and registers it in them, as done for a new Host --
+	if !single {
+		hostReq := abstract.HostRequest{
+			ResourceName: ahf.Core.Name,
+			Subnets:      subnets,
+			PublicIP:     ahf.Networking.PublicIPv4 != "" || ahf.Networking.PublicIPv6 != "",
+		}
+		defaultSubnetInstance, xerr := LoadSubnet(svc, "", defaultSubnetID)
+		if xerr != nil {
+			return xerr
+		}
+		defer defaultSubnetInstance.Released()
+
+		xerr = instance.setSecurityGroups(ctx, hostReq, defaultSubnetInstance)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return xerr
+		}
+		defer instance.undoSetSecurityGroups(&ferr, false)
+
+		task, xerr := concurrency.TaskFromContext(ctx)
+		if xerr != nil {
+			if task, xerr = concurrency.VoidTask(); xerr != nil {
+				return xerr
+			}
+		}
+		xerr = instance.updateSubnets(task, hostReq)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return xerr
+		}
+		defer func() {
+			if ferr != nil {
+				instance.undoUpdateSubnets(hostReq, &ferr)
+			}
+		}()
+	}
+
+	if ahf.Core.PrivateKey == "" {
+		logrus.Warnf("no SSH access given for imported Host '%s', remote commands will not be available", ahf.Core.Name)
+		return nil
+	}
+
+	// -- authorizes the SafeScale key for the operator user, using the access given --
+	if keyPair != nil {
+		access := *instance.sshProfile
+		access.User = req.SSHUser
+		access.PrivateKey = req.SSHPrivateKey
+		retcode, stdout, stderr, xerr := run(ctx, &access, injectKeyCommand(opUser, keyPair.PublicKey), outputs.COLLECT, temporal.GetExecutionTimeout())
+		if xerr != nil {
+			return fail.Wrap(xerr, "failed to inject SafeScale SSH key in Host '%s'", ahf.Core.Name)
+		}
+		if retcode != 0 {
+			xerr = fail.ExecutionError(nil, "failed to inject SafeScale SSH key in Host '%s'", ahf.Core.Name)
+			_ = xerr.Annotate("retcode", retcode).Annotate("stdout", stdout).Annotate("stderr", stderr)
+			return xerr
+		}
+	}
+
+	// -- detects the system of the Host, used to choose the install methods of features --
+	retcode, stdout, stderr, xerr := instance.UnsafeRun(ctx, detectSystemCommand, outputs.COLLECT, temporal.GetConnectSSHTimeout(), temporal.GetExecutionTimeout())
+	if xerr != nil {
+		return fail.Wrap(xerr, "failed to reach imported Host '%s' by SSH", ahf.Core.Name)
+	}
+	if retcode != 0 {
+		xerr = fail.ExecutionError(nil, "failed to detect the system of imported Host '%s'", ahf.Core.Name)
+		_ = xerr.Annotate("retcode", retcode).Annotate("stdout", stdout).Annotate("stderr", stderr)
+		return xerr
+	}
+	systemType, systemFlavor, xerr := parseDetectedSystem(stdout)
+	if xerr != nil {
+		return xerr
+	}
+
+	xerr = instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Alter(hostproperty.SystemV1, func(clonable data.Clonable) fail.Error {
+			systemV1, ok := clonable.(*propertiesv1.HostSystem)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.HostSystem' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			systemV1.Type = systemType
+			systemV1.Flavor = systemFlavor
+			systemV1.Image = ahf.Sizing.ImageID
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	return instance.updateCachedInformation()
+}
+
+// inferImportedHostSubnets returns the Subnets registered in metadata among the ones of an imported Host, and the ID of
+// its default Subnet
+func inferImportedHostSubnets(svc iaas.Service, networking *abstract.HostNetworking) ([]*abstract.Subnet, string, fail.Error) {
+	ids := make([]string, 0, len(networking.SubnetsByID))
+	for k := range networking.SubnetsByID {
+		ids = append(ids, k)
+	}
+	sort.Strings(ids)
+
+	var subnets []*abstract.Subnet
+	for _, id := range ids {
+		subnetInstance, xerr := LoadSubnet(svc, "", id)
+		if xerr != nil {
+			switch xerr.(type) {
+			case *fail.ErrNotFound:
+				logrus.Debugf("Subnet '%s' of imported Host is not registered in metadata", id)
+				continue
+			default:
+				return nil, "", xerr
+			}
+		}
+
+		xerr = subnetInstance.Review(func(clonable data.Clonable, _ *serialize.JSONProperties) fail.Error {
+			as, ok := clonable.(*abstract.Subnet)
+			if !ok {
+				return fail.InconsistentError("'*abstract.Subnet' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			subnets = append(subnets, as)
+			return nil
+		})
+		subnetInstance.Released()
+		if xerr != nil {
+			return nil, "", xerr
+		}
+	}
+
+	return subnets, chooseDefaultSubnetID(networking.DefaultSubnetID, ids, subnets), nil
+}
+
+// chooseDefaultSubnetID returns the default Subnet of an imported Host: the one given by the provider if any, else the first
+// Subnet registered in metadata, else the first of the Host
+func chooseDefaultSubnetID(providerDefault string, ids []string, known []*abstract.Subnet) string {
+	switch {
+	case providerDefault != "":
+		return providerDefault
+	case len(known) > 0:
+		return known[0].ID
+	case len(ids) > 0:
+		return ids[0]
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+func Test_injectKeyCommand(t *testing.T) {
+	cmd := injectKeyCommand("safescale", "ssh-rsa AAAAB3Nza safescale@host\n")
+	assert.Contains(t, cmd, "useradd -m -s /bin/bash safescale")
+	assert.Contains(t, cmd, "/etc/sudoers.d/10-safescale")
+	assert.Contains(t, cmd, `echo "ssh-rsa AAAAB3Nza safescale@host" >>$home/.ssh/authorized_keys`)
+}
+
+func Test_parseDetectedSystem(t *testing.T) {
+	kind, flavor, xerr := parseDetectedSystem("linux\n\"ubuntu\"\n")
+	require.Nil(t, xerr)
+	assert.Equal(t, "linux", kind)
+	assert.Equal(t, "ubuntu", flavor)
+
+	_, _, xerr = parseDetectedSystem("linux")
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrSyntax{}, xerr)
+}
+
+func Test_chooseDefaultSubnetID(t *testing.T) {
+	known := []*abstract.Subnet{{ID: "s2"}}
+	assert.Equal(t, "s1", chooseDefaultSubnetID("s1", []string{"s3", "s2"}, known))
+	assert.Equal(t, "s2", chooseDefaultSubnetID("", []string{"s3", "s2"}, known))
+	assert.Equal(t, "s3", chooseDefaultSubnetID("", []string{"s3", "s2"}, nil))
+	assert.Empty(t, chooseDefaultSubnetID("", nil, nil))
+}
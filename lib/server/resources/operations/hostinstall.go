@@ -102,6 +102,7 @@ func (instance *Host) AddFeature(ctx context.Context, name string, vars data.Map
 			hostFeaturesV1.Installed[name] = &propertiesv1.HostInstalledFeature{
 				HostContext: true,
 				Requires:    requires,
+				Version:     feat.GetVersion(),
 			}
 			return nil
 		})
@@ -262,6 +263,12 @@ func (instance *Host) RegisterFeature(feat resources.Feature, requiredBy resourc
 		return fail.InvalidParameterCannotBeNilError("feat")
 	}
 
+	// requirements may change with the version, so they are refreshed on upgrade too
+	requirements, xerr := feat.GetRequirements()
+	if xerr != nil {
+		return xerr
+	}
+
 	return instance.Alter(func(clonable data.Clonable, props *serialize.JSONProperties) fail.Error {
 		return props.Alter(hostproperty.FeaturesV1, func(clonable data.Clonable) fail.Error {
 			featuresV1, ok := clonable.(*propertiesv1.HostFeatures)
@@ -271,16 +278,12 @@ func (instance *Host) RegisterFeature(feat resources.Feature, requiredBy resourc
 
 			var item *propertiesv1.HostInstalledFeature
 			if item, ok = featuresV1.Installed[feat.GetName()]; !ok {
-				requirements, innerXErr := feat.GetRequirements()
-				if innerXErr != nil {
-					return innerXErr
-				}
-
 				item = propertiesv1.NewHostInstalledFeature()
-				item.Requires = requirements
 				item.HostContext = !clusterContext
 				featuresV1.Installed[feat.GetName()] = item
 			}
+			item.Requires = requirements
+			item.Version = feat.GetVersion()
 			if rf, ok := requiredBy.(*Feature); ok && !rf.IsNull() {
 				item.RequiredBy[rf.GetName()] = struct{}{}
 			}
@@ -345,6 +348,34 @@ func (instance *Host) InstalledFeatures() []string {
 
 }
 
+// InstalledFeatureVersion returns the version of the installed Feature recorded in metadata, empty string if unknown
+// satisfies interface install.Targetable
+func (instance *Host) InstalledFeatureVersion(feat string) string {
+	if instance == nil || feat == "" {
+		return ""
+	}
+
+	var out string
+	xerr := instance.Review(func(clonable data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Inspect(hostproperty.FeaturesV1, func(clonable data.Clonable) fail.Error {
+			featuresV1, ok := clonable.(*propertiesv1.HostFeatures)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.HostFeatures' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			if item, ok := featuresV1.Installed[feat]; ok && item != nil {
+				out = item.Version
+			}
+			return nil
+		})
+	})
+	if xerr != nil {
+		logrus.Error(xerr.Error())
+		return ""
+	}
+	return out
+}
+
 // ComplementFeatureParameters configures parameters that are appropriate for the target
 // satisfies interface install.Targetable
 func (instance *Host) ComplementFeatureParameters(_ context.Context, v data.Map) (xerr fail.Error) {
@@ -0,0 +1,488 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/protocol"
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/resources"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/hoststate"
+	"github.com/CS-SI/SafeScale/lib/server/resources/operations/converters"
+	"github.com/CS-SI/SafeScale/lib/utils"
+	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/outputs"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/data/cache"
+	"github.com/CS-SI/SafeScale/lib/utils/data/serialize"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+const (
+	imageKind        = "image"
+	imagesFolderName = iaas.ImagesMetadataFolder // is the name of the Object Storage MetadataFolder used to store custom image info
+)
+
+// image links Object Storage MetadataFolder and custom images
+type image struct {
+	*MetadataCore
+
+	lock sync.RWMutex
+}
+
+// ImageNullValue returns an instance of image corresponding to its null value.
+// The idea is to avoid nil pointer using ImageNullValue()
+func ImageNullValue() *image {
+	return &image{MetadataCore: NullCore()}
+}
+
+// NewImage creates an instance of Image
+func NewImage(svc iaas.Service) (_ resources.Image, xerr fail.Error) {
+	if svc == nil {
+		return ImageNullValue(), fail.InvalidParameterCannotBeNilError("svc")
+	}
+
+	coreInstance, xerr := NewCore(svc, imageKind, imagesFolderName, &abstract.Image{})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return ImageNullValue(), xerr
+	}
+
+	instance := &image{
+		MetadataCore: coreInstance,
+	}
+	return instance, nil
+}
+
+// LoadImage loads the metadata of a custom image
+func LoadImage(svc iaas.Service, ref string) (rs resources.Image, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if svc == nil {
+		return ImageNullValue(), fail.InvalidParameterCannotBeNilError("svc")
+	}
+	if ref = strings.TrimSpace(ref); ref == "" {
+		return ImageNullValue(), fail.InvalidParameterCannotBeEmptyStringError("ref")
+	}
+
+	imageCache, xerr := svc.GetCache(imageKind)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return ImageNullValue(), xerr
+	}
+
+	options := iaas.CacheMissOption(
+		func() (cache.Cacheable, fail.Error) { return onImageCacheMiss(svc, ref) },
+		temporal.GetMetadataTimeout(),
+	)
+	cacheEntry, xerr := imageCache.Get(ref, options...)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			// rewrite NotFoundError, user does not bother about metadata stuff
+			return ImageNullValue(), fail.NotFoundError("failed to find Image '%s'", ref)
+		default:
+			return ImageNullValue(), xerr
+		}
+	}
+
+	if rs = cacheEntry.Content().(resources.Image); rs == nil {
+		return nil, fail.InconsistentError("nil value in cache for Image with key '%s'", ref)
+	}
+	_ = cacheEntry.LockContent()
+	defer func() {
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			_ = cacheEntry.UnlockContent()
+		}
+	}()
+
+	return rs, nil
+}
+
+// onImageCacheMiss is called when there is no instance in cache of Image 'ref'
+func onImageCacheMiss(svc iaas.Service, ref string) (cache.Cacheable, fail.Error) {
+	imageInstance, innerXErr := NewImage(svc)
+	if innerXErr != nil {
+		return nil, innerXErr
+	}
+
+	if innerXErr = imageInstance.Read(ref); innerXErr != nil {
+		return nil, innerXErr
+	}
+
+	return imageInstance, nil
+}
+
+// IsNull tells if the instance is a null value
+func (instance *image) IsNull() bool {
+	return instance == nil || instance.MetadataCore == nil || instance.MetadataCore.IsNull()
+}
+
+// carry overloads rv.core.Carry() to add Image to service cache
+func (instance *image) carry(clonable data.Clonable) (xerr fail.Error) {
+	if instance == nil {
+		return fail.InvalidInstanceError()
+	}
+	if !instance.IsNull() {
+		return fail.InvalidInstanceContentError("instance", "is not null value, cannot overwrite")
+	}
+	if clonable == nil {
+		return fail.InvalidParameterCannotBeNilError("clonable")
+	}
+	identifiable, ok := clonable.(data.Identifiable)
+	if !ok {
+		return fail.InvalidParameterError("clonable", "must also satisfy interface 'data.Identifiable'")
+	}
+
+	kindCache, xerr := instance.GetService().GetCache(instance.MetadataCore.GetKind())
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	xerr = kindCache.ReserveEntry(identifiable.GetID(), temporal.GetMetadataTimeout())
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+	defer func() {
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			if derr := kindCache.FreeEntry(identifiable.GetID()); derr != nil {
+				_ = xerr.AddConsequence(fail.Wrap(derr, "cleaning up on failure, failed to free %s cache entry for key '%s'", instance.MetadataCore.GetKind(), identifiable.GetID()))
+			}
+		}
+	}()
+
+	// Note: do not validate parameters, this call will do it
+	xerr = instance.MetadataCore.Carry(clonable)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	cacheEntry, xerr := kindCache.CommitEntry(identifiable.GetID(), instance)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	cacheEntry.LockContent()
+	return nil
+}
+
+// imageTaskFromContext returns the task contained in ctx, or a void task if there is none
+func imageTaskFromContext(ctx context.Context) (concurrency.Task, fail.Error) {
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotAvailable:
+			return concurrency.VoidTask()
+		default:
+			return nil, xerr
+		}
+	}
+	return task, nil
+}
+
+// Browse walks through Image MetadataFolder and executes a callback for each entry
+func (instance *image) Browse(ctx context.Context, callback func(*abstract.Image) fail.Error) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	// Note: Browse is intended to be callable from null value, so do not validate instance with .IsNull()
+	if instance == nil {
+		return fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return fail.InvalidParameterError("ctx", "cannot be nil")
+	}
+	if callback == nil {
+		return fail.InvalidParameterError("callback", "cannot be nil")
+	}
+
+	task, xerr := imageTaskFromContext(ctx)
+	if xerr != nil {
+		return xerr
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.image")).Entering()
+	defer tracer.Exiting()
+
+	instance.lock.RLock()
+	defer instance.lock.RUnlock()
+
+	return instance.MetadataCore.BrowseFolder(func(buf []byte) fail.Error {
+		if task.Aborted() {
+			return fail.AbortedError(nil, "aborted")
+		}
+
+		ai := abstract.NewImage()
+		xerr = ai.Deserialize(buf)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return xerr
+		}
+
+		return callback(ai)
+	})
+}
+
+// Create creates a custom image from the host identified by req.HostID, and records it in metadata
+func (instance *image) Create(ctx context.Context, req abstract.ImageRequest) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	// note: do not test IsNull() here, it's expected to be IsNull() actually
+	if instance == nil {
+		return fail.InvalidInstanceError()
+	}
+	if !instance.IsNull() {
+		imageName := instance.GetName()
+		if imageName != "" {
+			return fail.NotAvailableError("already carrying Image '%s'", imageName)
+		}
+		return fail.InvalidInstanceContentError("instance", "is not null value")
+	}
+	if ctx == nil {
+		return fail.InvalidParameterError("ctx", "cannot be nil")
+	}
+	if req.Name == "" {
+		return fail.InvalidParameterError("req.Name", "cannot be empty string")
+	}
+	if req.HostID == "" {
+		return fail.InvalidParameterError("req.HostID", "cannot be empty string")
+	}
+
+	task, xerr := imageTaskFromContext(ctx)
+	if xerr != nil {
+		return xerr
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.image"), "('%s', '%s')", req.Name, req.HostID).Entering()
+	defer tracer.Exiting()
+
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	// Check if Image exists and is managed by SafeScale
+	svc := instance.GetService()
+	existing, xerr := LoadImage(svc, req.Name)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			// continue
+			debug.IgnoreError(xerr)
+		default:
+			return fail.Wrap(xerr, "failed to check if Image '%s' already exists", req.Name)
+		}
+	} else {
+		existing.Released()
+		return fail.DuplicateError("there is already an Image named '%s'", req.Name)
+	}
+
+	hostInstance, xerr := LoadHost(svc, req.HostID)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+	defer hostInstance.Released()
+
+	// The provisioning state of the source host must not leak into the image, otherwise hosts created from it
+	// would consider their provisioning phases as already done
+	state, xerr := hostInstance.ForceGetState(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+	if state == hoststate.Started {
+		cmd := fmt.Sprintf("sudo rm -f %s/state/user_data.*.done /var/tmp/user_data.done && sync", utils.VarFolder)
+		retcode, _, stderr, xerr := hostInstance.Run(ctx, cmd, outputs.COLLECT, temporal.GetConnectionTimeout(), temporal.GetExecutionTimeout())
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return fail.Wrap(xerr, "failed to prepare Host '%s' for Image creation", hostInstance.GetName())
+		}
+		if retcode != 0 {
+			return fail.ExecutionError(nil, "failed to prepare Host '%s' for Image creation: %s", hostInstance.GetName(), stderr)
+		}
+	} else {
+		logrus.Warnf("Host '%s' is not started, its provisioning state cannot be cleaned before creating Image '%s'", hostInstance.GetName(), req.Name)
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	req.HostID = hostInstance.GetID()
+	ai, xerr := svc.CreateImage(req)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	// Starting from here, delete Image if exiting with error
+	imageID := ai.ID
+	defer func() {
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			if derr := svc.DeleteImage(imageID); derr != nil {
+				_ = xerr.AddConsequence(fail.Wrap(derr, "cleaning up on %s, failed to delete Image '%s'", ActionFromError(xerr), req.Name))
+			}
+		}
+	}()
+
+	if ai.Description == "" {
+		ai.Description = req.Description
+	}
+	return instance.carry(ai)
+}
+
+// Delete deletes the custom image and its metadata
+func (instance *image) Delete(ctx context.Context) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return fail.InvalidParameterError("ctx", "cannot be nil")
+	}
+
+	task, xerr := imageTaskFromContext(ctx)
+	if xerr != nil {
+		return xerr
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.image")).Entering()
+	defer tracer.Exiting()
+
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	xerr = instance.GetService().DeleteImage(instance.GetID())
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			logrus.Debugf("Unable to find the Image on provider side, cleaning up metadata")
+			debug.IgnoreError(xerr)
+		default:
+			return xerr
+		}
+	}
+
+	// remove metadata
+	return instance.MetadataCore.Delete()
+}
+
+// GetHostID returns the ID of the host the image has been created from
+func (instance *image) GetHostID() (_ string, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return "", fail.InvalidInstanceError()
+	}
+
+	instance.lock.RLock()
+	defer instance.lock.RUnlock()
+
+	var hostID string
+	xerr = instance.Inspect(func(clonable data.Clonable, _ *serialize.JSONProperties) fail.Error {
+		ai, ok := clonable.(*abstract.Image)
+		if !ok {
+			return fail.InconsistentError("'*abstract.Image' expected, '%s' provided", reflect.TypeOf(clonable).String())
+		}
+
+		hostID = ai.HostID
+		return nil
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return "", xerr
+	}
+	return hostID, nil
+}
+
+// ToProtocol converts the Image to protocol message Image
+func (instance *image) ToProtocol() (_ *protocol.Image, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	instance.lock.RLock()
+	defer instance.lock.RUnlock()
+
+	var out *protocol.Image
+	xerr = instance.Inspect(func(clonable data.Clonable, _ *serialize.JSONProperties) fail.Error {
+		ai, ok := clonable.(*abstract.Image)
+		if !ok {
+			return fail.InconsistentError("'*abstract.Image' expected, '%s' provided", reflect.TypeOf(clonable).String())
+		}
+
+		out = converters.ImageFromAbstractToProtocol(ai)
+		return nil
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	// the source host may have been deleted since the image has been created; the image remains usable
+	if out.Host != nil {
+		hostInstance, xerr := LoadHost(instance.GetService(), out.Host.Id)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			switch xerr.(type) {
+			case *fail.ErrNotFound:
+				debug.IgnoreError(xerr)
+			default:
+				return nil, xerr
+			}
+		} else {
+			out.Host.Name = hostInstance.GetName()
+			hostInstance.Released()
+		}
+	}
+	return out, nil
+}
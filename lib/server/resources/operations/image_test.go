@@ -0,0 +1,92 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+func Test_image_IsNull(t *testing.T) {
+	var ri *image
+	//goland:noinspection GoNilness
+	require.True(t, ri.IsNull())
+	require.True(t, ImageNullValue().IsNull())
+}
+
+func Test_image_CreateDelete(t *testing.T) {
+	svc := getMemoryService(t)
+	ctx := context.Background()
+
+	templates, xerr := svc.ListTemplates(true)
+	require.Nil(t, xerr)
+	require.NotEmpty(t, templates)
+	images, xerr := svc.ListImages(true)
+	require.Nil(t, xerr)
+	require.NotEmpty(t, images)
+
+	// the source host is stopped, so no SSH connection is attempted
+	ahf, _, xerr := svc.CreateHost(abstract.HostRequest{ResourceName: "golden", TemplateID: templates[0].ID, ImageID: images[0].ID, PublicIP: true})
+	require.Nil(t, xerr)
+	require.Nil(t, svc.StopHost(ahf.Core.ID, false))
+	rh, xerr := NewHost(svc)
+	require.Nil(t, xerr)
+	require.Nil(t, rh.carry(ahf.Core))
+
+	ri, xerr := NewImage(svc)
+	require.Nil(t, xerr)
+	require.Nil(t, ri.Create(ctx, abstract.ImageRequest{Name: "golden-image", HostID: ahf.Core.ID, Description: "golden"}))
+	imageID := ri.GetID()
+	ri.Released()
+
+	other, xerr := NewImage(svc)
+	require.Nil(t, xerr)
+	xerr = other.Create(ctx, abstract.ImageRequest{Name: "golden-image", HostID: ahf.Core.ID})
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrDuplicate{}, xerr)
+
+	ri, xerr = LoadImage(svc, "golden-image")
+	require.Nil(t, xerr)
+	hostID, xerr := ri.GetHostID()
+	require.Nil(t, xerr)
+	assert.Equal(t, ahf.Core.ID, hostID)
+	pb, xerr := ri.ToProtocol()
+	require.Nil(t, xerr)
+	assert.Equal(t, "golden", pb.GetHost().GetName())
+	assert.Equal(t, "golden", pb.GetDescription())
+
+	// custom image must be usable to create hosts
+	found, xerr := svc.SearchImage("golden-image")
+	require.Nil(t, xerr)
+	assert.Equal(t, imageID, found.ID)
+	filtered, xerr := svc.FilterImages("golden")
+	require.Nil(t, xerr)
+	assert.NotEmpty(t, filtered)
+
+	require.Nil(t, ri.Delete(ctx))
+	_, xerr = LoadImage(svc, "golden-image")
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrNotFound{}, xerr)
+	_, xerr = svc.InspectImage(imageID)
+	require.NotNil(t, xerr)
+}
@@ -169,6 +169,55 @@ func (i *bashInstaller) Remove(ctx context.Context, f resources.Feature, t resou
 	return r, nil
 }
 
+// Upgrade updates the Feature installed to the version of its specification file, using the upgrade script in Specs
+func (i *bashInstaller) Upgrade(ctx context.Context, f resources.Feature, t resources.Targetable, v data.Map, s resources.FeatureSettings) (r resources.Results, xerr fail.Error) {
+	r = nil
+	defer fail.OnPanic(&xerr)
+
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if f == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("f")
+	}
+	if t == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("t")
+	}
+
+	if !f.(*Feature).Specs().IsSet("feature.install.bash.upgrade") {
+		msg := `syntax error in Feature '%s' specification file (%s):
+				no key 'feature.install.bash.upgrade' found`
+		return nil, fail.SyntaxError(msg, f.GetName(), f.GetDisplayFilename())
+	}
+
+	w, xerr := newWorker(f, t, installmethod.Bash, installaction.Upgrade, nil)
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer w.Terminate()
+
+	xerr = w.CanProceed(ctx, s)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		logrus.Info(xerr.Error())
+		return nil, xerr
+	}
+
+	if !w.ConcernsCluster() {
+		if _, ok := v["Username"]; !ok {
+			v["Username"] = "safescale"
+		}
+	}
+
+	r, xerr = w.Proceed(ctx, v, s)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return r, fail.Wrap(xerr, "failed to upgrade Feature '%s' on %s '%s'", f.GetName(), t.TargetType(), t.GetName())
+	}
+
+	return r, nil
+}
+
 // newBashInstaller creates a new instance of Installer using script
 func newBashInstaller() Installer {
 	return &bashInstaller{}
@@ -128,6 +128,30 @@ func (i *noneInstaller) Remove(ctx context.Context, f resources.Feature, t resou
 	return r, nil
 }
 
+// Upgrade does nothing, there is nothing installed to update
+func (i *noneInstaller) Upgrade(_ context.Context, f resources.Feature, t resources.Targetable, _ data.Map, _ resources.FeatureSettings) (r resources.Results, xerr fail.Error) {
+	r = nil
+	defer fail.OnPanic(&xerr)
+
+	if f == nil {
+		return nil, fail.InvalidParameterError("f", "cannot be null value of 'resources.Feature'")
+	}
+	if t == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("t")
+	}
+
+	// Forge a completed and successful results
+	out := &results{
+		t.GetName(): &unitResults{
+			"none": &stepResult{
+				completed: true,
+				success:   true,
+			},
+		},
+	}
+	return out, nil
+}
+
 // newNoneInstaller creates a new instance
 func newNoneInstaller() Installer {
 	return &noneInstaller{}
@@ -34,11 +34,12 @@ import (
 // genericPackager is an object implementing the OS package management
 // It handles package management on single host or entire cluster
 type genericPackager struct {
-	keyword       string
-	method        installmethod.Enum
-	checkCommand  alterCommandCB
-	addCommand    alterCommandCB
-	removeCommand alterCommandCB
+	keyword        string
+	method         installmethod.Enum
+	checkCommand   alterCommandCB
+	addCommand     alterCommandCB
+	removeCommand  alterCommandCB
+	upgradeCommand alterCommandCB
 }
 
 // Check checks if the Feature is installed
@@ -173,6 +174,50 @@ func (g *genericPackager) Remove(ctx context.Context, f resources.Feature, t res
 	return r, nil
 }
 
+// Upgrade updates the packages of the Feature
+func (g *genericPackager) Upgrade(ctx context.Context, f resources.Feature, t resources.Targetable, v data.Map, s resources.FeatureSettings) (r resources.Results, xerr fail.Error) {
+	r = nil
+	defer fail.OnPanic(&xerr)
+
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if f == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("f")
+	}
+	if t == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("t")
+	}
+
+	yamlKey := "feature.install." + g.keyword + ".upgrade"
+	if !f.(*Feature).Specs().IsSet(yamlKey) {
+		msg := `syntax error in Feature '%s' specification file (%s):
+				no key '%s' found`
+		return nil, fail.SyntaxError(msg, f.GetName(), f.GetDisplayFilename(), yamlKey)
+	}
+
+	worker, xerr := newWorker(f, t, g.method, installaction.Upgrade, g.upgradeCommand)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer worker.Terminate()
+
+	xerr = worker.CanProceed(ctx, s)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		logrus.Info(xerr.Error())
+		return nil, xerr
+	}
+
+	r, xerr = worker.Proceed(ctx, v, s)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return r, fail.Wrap(xerr, "failed to upgrade Feature '%s' on %s '%s'", f.GetName(), t.TargetType(), t.GetName())
+	}
+	return r, nil
+}
+
 // aptInstaller is an installer using script to add and remove a Feature
 type aptInstaller struct {
 	genericPackager
@@ -193,6 +238,9 @@ func NewAptInstaller() Installer {
 			removeCommand: func(pkg string) string {
 				return fmt.Sprintf("sudo apt-get remove -y '%s'", pkg)
 			},
+			upgradeCommand: func(pkg string) string {
+				return fmt.Sprintf("sudo apt-get install -y --only-upgrade '%s'", pkg)
+			},
 		},
 	}
 }
@@ -217,6 +265,9 @@ func NewYumInstaller() Installer {
 			removeCommand: func(pkg string) string {
 				return fmt.Sprintf("sudo yum remove -y %s", pkg)
 			},
+			upgradeCommand: func(pkg string) string {
+				return fmt.Sprintf("sudo yum update -y %s", pkg)
+			},
 		},
 	}
 }
@@ -241,6 +292,9 @@ func NewDnfInstaller() Installer {
 			removeCommand: func(pkg string) string {
 				return fmt.Sprintf("sudo dnf uninstall -y %s", pkg)
 			},
+			upgradeCommand: func(pkg string) string {
+				return fmt.Sprintf("sudo dnf upgrade -y %s", pkg)
+			},
 		},
 	}
 }
@@ -113,6 +113,7 @@ func NewKongController(ctx context.Context, svc iaas.Service, subnet resources.S
 
 					item := propertiesv1.NewHostInstalledFeature()
 					item.HostContext = true
+					item.Version = featureInstance.GetVersion()
 					item.Requires, innerXErr = featureInstance.GetRequirements()
 					if innerXErr != nil {
 						return innerXErr
@@ -0,0 +1,61 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"reflect"
+
+	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/data/serialize"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// labelsFromProperties returns a copy of the labels stored in the property 'key' of props
+func labelsFromProperties(props *serialize.JSONProperties, key string) (map[string]string, fail.Error) {
+	labels := map[string]string{}
+	xerr := props.Inspect(key, func(clonable data.Clonable) fail.Error {
+		labelsV1, ok := clonable.(*propertiesv1.ResourceLabels)
+		if !ok {
+			return fail.InconsistentError("'*propertiesv1.ResourceLabels' expected, '%s' provided", reflect.TypeOf(clonable).String())
+		}
+
+		for k, v := range labelsV1.ByKey {
+			labels[k] = v
+		}
+		return nil
+	})
+	if xerr != nil {
+		return nil, xerr
+	}
+	return labels, nil
+}
+
+// labelsToProperties stores labels in the property 'key' of props
+func labelsToProperties(props *serialize.JSONProperties, key string, labels map[string]string) fail.Error {
+	return props.Alter(key, func(clonable data.Clonable) fail.Error {
+		labelsV1, ok := clonable.(*propertiesv1.ResourceLabels)
+		if !ok {
+			return fail.InconsistentError("'*propertiesv1.ResourceLabels' expected, '%s' provided", reflect.TypeOf(clonable).String())
+		}
+
+		for k, v := range labels {
+			labelsV1.ByKey[k] = v
+		}
+		return nil
+	})
+}
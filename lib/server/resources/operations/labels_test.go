@@ -0,0 +1,78 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumespeed"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+func Test_volume_Labels(t *testing.T) {
+	svc := getMemoryService(t)
+	ctx := context.Background()
+
+	rv, xerr := NewVolume(svc)
+	require.Nil(t, xerr)
+	xerr = rv.Create(ctx, abstract.VolumeRequest{Name: "invalid", Size: 10, Speed: volumespeed.Hdd, Labels: map[string]string{"name": "forbidden"}})
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrInvalidRequest{}, xerr)
+
+	labels := map[string]string{"cost-center": "CC042", "env": "prod"}
+	require.Nil(t, rv.Create(ctx, abstract.VolumeRequest{Name: "billing", Size: 10, Speed: volumespeed.Hdd, Labels: labels}))
+	rv.Released()
+
+	rv, xerr = LoadVolume(svc, "billing")
+	require.Nil(t, xerr)
+	got, xerr := rv.GetLabels()
+	require.Nil(t, xerr)
+	assert.Equal(t, labels, got)
+
+	// returned labels must be a copy
+	got["env"] = "dev"
+	pb, xerr := rv.ToProtocol()
+	require.Nil(t, xerr)
+	assert.Equal(t, labels, pb.GetLabels())
+	assert.True(t, abstract.MatchLabels(pb.GetLabels(), map[string]string{"cost-center": "CC042"}))
+	assert.False(t, abstract.MatchLabels(pb.GetLabels(), map[string]string{"env": "dev"}))
+}
+
+func Test_network_Labels(t *testing.T) {
+	svc := getMemoryService(t)
+	ctx := context.Background()
+
+	rn, xerr := NewNetwork(svc)
+	require.Nil(t, xerr)
+	labels := map[string]string{"cost-center": "CC042"}
+	require.Nil(t, rn.Create(ctx, abstract.NetworkRequest{Name: "billing", CIDR: "192.168.0.0/23", Labels: labels}))
+	rn.Released()
+
+	rn, xerr = LoadNetwork(svc, "billing")
+	require.Nil(t, xerr)
+	got, xerr := rn.GetLabels()
+	require.Nil(t, xerr)
+	assert.Equal(t, labels, got)
+	pb, xerr := rn.ToProtocol()
+	require.Nil(t, xerr)
+	assert.Equal(t, labels, pb.GetLabels())
+}
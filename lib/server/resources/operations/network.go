@@ -162,6 +162,9 @@ func (instance *Network) Create(ctx context.Context, req abstract.NetworkRequest
 	if ctx == nil {
 		return fail.InvalidParameterCannotBeNilError("ctx")
 	}
+	if xerr := abstract.ValidateLabels(req.Labels); xerr != nil {
+		return xerr
+	}
 
 	task, xerr := concurrency.TaskFromContext(ctx)
 	xerr = debug.InjectPlannedFail(xerr)
@@ -255,7 +258,23 @@ func (instance *Network) Create(ctx context.Context, req abstract.NetworkRequest
 	// Write subnet object metadata
 	logrus.Debugf("Saving subnet metadata '%s' ...", abstractNetwork.Name)
 	abstractNetwork.Imported = false
-	return instance.carry(abstractNetwork)
+	xerr = instance.carry(abstractNetwork)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	defer func() {
+		if ferr != nil && !req.KeepOnFailure {
+			if derr := instance.MetadataCore.Delete(); derr != nil {
+				_ = ferr.AddConsequence(fail.Wrap(derr, "cleaning up on failure, failed to delete Network metadata"))
+			}
+		}
+	}()
+
+	return instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return labelsToProperties(props, networkproperty.LabelsV1, req.Labels)
+	})
 }
 
 // carry registers clonable as core value and deals with cache
@@ -383,6 +402,57 @@ func (instance *Network) Import(ctx context.Context, ref string) (xerr fail.Erro
 	return instance.carry(abstractNetwork)
 }
 
+// ImportNetworkWithSubnets imports an existing Network in SafeScale metadata, with the Subnets it contains
+func ImportNetworkWithSubnets(ctx context.Context, svc iaas.Service, ref string) (_ resources.Network, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if svc == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("svc")
+	}
+
+	networkInstance, xerr := NewNetwork(svc)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	xerr = networkInstance.Import(ctx, ref)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	subnets, xerr := svc.ListSubnets(networkInstance.GetID())
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return networkInstance, fail.Wrap(xerr, "failed to list Subnets of imported Network '%s'", networkInstance.GetName())
+	}
+
+	var errors []error
+	for _, as := range subnets {
+		if existing, xerr := LoadSubnet(svc, "", as.ID); xerr == nil {
+			existing.Released()
+			continue
+		}
+
+		subnetInstance, xerr := NewSubnet(svc)
+		if xerr != nil {
+			return networkInstance, xerr
+		}
+
+		xerr = subnetInstance.Import(ctx, as.ID)
+		if xerr != nil {
+			errors = append(errors, fail.Wrap(xerr, "failed to import Subnet '%s'", as.Name))
+			continue
+		}
+		subnetInstance.Released()
+	}
+	if len(errors) > 0 {
+		return networkInstance, fail.NewErrorList(errors)
+	}
+
+	return networkInstance, nil
+}
+
 // Browse walks through all the metadata objects in subnet
 func (instance *Network) Browse(ctx context.Context, callback func(*abstract.Network) fail.Error) (xerr fail.Error) {
 	defer fail.OnPanic(&xerr)
@@ -709,6 +779,11 @@ func (instance *Network) ToProtocol() (_ *protocol.Network, xerr fail.Error) {
 			Cidr: an.CIDR,
 		}
 
+		var innerXErr fail.Error
+		if pn.Labels, innerXErr = labelsFromProperties(props, networkproperty.LabelsV1); innerXErr != nil {
+			return innerXErr
+		}
+
 		return props.Inspect(networkproperty.SubnetsV1, func(clonable data.Clonable) fail.Error {
 			nsV1, ok := clonable.(*propertiesv1.NetworkSubnets)
 			if !ok {
@@ -728,6 +803,24 @@ func (instance *Network) ToProtocol() (_ *protocol.Network, xerr fail.Error) {
 	return pn, nil
 }
 
+// GetLabels returns the user-defined labels of the Network
+func (instance *Network) GetLabels() (labels map[string]string, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	instance.lock.RLock()
+	defer instance.lock.RUnlock()
+
+	xerr = instance.Inspect(func(_ data.Clonable, props *serialize.JSONProperties) (innerXErr fail.Error) {
+		labels, innerXErr = labelsFromProperties(props, networkproperty.LabelsV1)
+		return innerXErr
+	})
+	return labels, xerr
+}
+
 // InspectSubnet returns the instance of resources.Subnet corresponding to the subnet referenced by 'ref' attached to
 // the subnet
 func (instance *Network) InspectSubnet(ref string) (_ resources.Subnet, xerr fail.Error) {
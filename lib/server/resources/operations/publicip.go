@@ -0,0 +1,797 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/protocol"
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/resources"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/hostproperty"
+	"github.com/CS-SI/SafeScale/lib/server/resources/operations/converters"
+	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
+	propertiesv2 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v2"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/data/cache"
+	"github.com/CS-SI/SafeScale/lib/utils/data/serialize"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+const (
+	publicIPKind        = "publicip"
+	publicIPsFolderName = "publicips" // is the name of the Object Storage MetadataFolder used to store public IP info
+)
+
+// publicIP links Object Storage MetadataFolder and public IPs
+type publicIP struct {
+	*MetadataCore
+
+	lock sync.RWMutex
+}
+
+// PublicIPNullValue returns an instance of publicIP corresponding to its null value.
+// The idea is to avoid nil pointer using PublicIPNullValue()
+func PublicIPNullValue() *publicIP {
+	return &publicIP{MetadataCore: NullCore()}
+}
+
+// NewPublicIP creates an instance of PublicIP
+func NewPublicIP(svc iaas.Service) (_ resources.PublicIP, xerr fail.Error) {
+	if svc == nil {
+		return PublicIPNullValue(), fail.InvalidParameterCannotBeNilError("svc")
+	}
+
+	coreInstance, xerr := NewCore(svc, publicIPKind, publicIPsFolderName, &abstract.PublicIP{})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return PublicIPNullValue(), xerr
+	}
+
+	instance := &publicIP{
+		MetadataCore: coreInstance,
+	}
+	return instance, nil
+}
+
+// LoadPublicIP loads the metadata of a public IP
+func LoadPublicIP(svc iaas.Service, ref string) (rp resources.PublicIP, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if svc == nil {
+		return PublicIPNullValue(), fail.InvalidParameterCannotBeNilError("svc")
+	}
+	if ref = strings.TrimSpace(ref); ref == "" {
+		return PublicIPNullValue(), fail.InvalidParameterCannotBeEmptyStringError("ref")
+	}
+
+	publicIPCache, xerr := svc.GetCache(publicIPKind)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return PublicIPNullValue(), xerr
+	}
+
+	options := iaas.CacheMissOption(
+		func() (cache.Cacheable, fail.Error) { return onPublicIPCacheMiss(svc, ref) },
+		temporal.GetMetadataTimeout(),
+	)
+	cacheEntry, xerr := publicIPCache.Get(ref, options...)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			// rewrite NotFoundError, user does not bother about metadata stuff
+			return PublicIPNullValue(), fail.NotFoundError("failed to find Public IP '%s'", ref)
+		default:
+			return PublicIPNullValue(), xerr
+		}
+	}
+
+	if rp = cacheEntry.Content().(resources.PublicIP); rp == nil {
+		return nil, fail.InconsistentError("nil value in cache for Public IP with key '%s'", ref)
+	}
+	_ = cacheEntry.LockContent()
+	defer func() {
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			_ = cacheEntry.UnlockContent()
+		}
+	}()
+
+	return rp, nil
+}
+
+// onPublicIPCacheMiss is called when there is no instance in cache of Public IP 'ref'
+func onPublicIPCacheMiss(svc iaas.Service, ref string) (cache.Cacheable, fail.Error) {
+	publicIPInstance, innerXErr := NewPublicIP(svc)
+	if innerXErr != nil {
+		return nil, innerXErr
+	}
+
+	if innerXErr = publicIPInstance.Read(ref); innerXErr != nil {
+		return nil, innerXErr
+	}
+
+	return publicIPInstance, nil
+}
+
+// IsNull tells if the instance is a null value
+func (instance *publicIP) IsNull() bool {
+	return instance == nil || instance.MetadataCore == nil || instance.MetadataCore.IsNull()
+}
+
+// carry overloads rv.core.Carry() to add Public IP to service cache
+func (instance *publicIP) carry(clonable data.Clonable) (xerr fail.Error) {
+	if instance == nil {
+		return fail.InvalidInstanceError()
+	}
+	if !instance.IsNull() {
+		return fail.InvalidInstanceContentError("instance", "is not null value, cannot overwrite")
+	}
+	if clonable == nil {
+		return fail.InvalidParameterCannotBeNilError("clonable")
+	}
+	identifiable, ok := clonable.(data.Identifiable)
+	if !ok {
+		return fail.InvalidParameterError("clonable", "must also satisfy interface 'data.Identifiable'")
+	}
+
+	kindCache, xerr := instance.GetService().GetCache(instance.MetadataCore.GetKind())
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	xerr = kindCache.ReserveEntry(identifiable.GetID(), temporal.GetMetadataTimeout())
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+	defer func() {
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			if derr := kindCache.FreeEntry(identifiable.GetID()); derr != nil {
+				_ = xerr.AddConsequence(fail.Wrap(derr, "cleaning up on failure, failed to free %s cache entry for key '%s'", instance.MetadataCore.GetKind(), identifiable.GetID()))
+			}
+		}
+	}()
+
+	// Note: do not validate parameters, this call will do it
+	xerr = instance.MetadataCore.Carry(clonable)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	cacheEntry, xerr := kindCache.CommitEntry(identifiable.GetID(), instance)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	cacheEntry.LockContent()
+	return nil
+}
+
+// publicIPTaskFromContext returns the task contained in ctx, or a void task if there is none
+func publicIPTaskFromContext(ctx context.Context) (concurrency.Task, fail.Error) {
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotAvailable:
+			return concurrency.VoidTask()
+		default:
+			return nil, xerr
+		}
+	}
+	return task, nil
+}
+
+// Browse walks through Public IP MetadataFolder and executes a callback for each entry
+func (instance *publicIP) Browse(ctx context.Context, callback func(*abstract.PublicIP) fail.Error) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	// Note: Browse is intended to be callable from null value, so do not validate instance with .IsNull()
+	if instance == nil {
+		return fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return fail.InvalidParameterError("ctx", "cannot be nil")
+	}
+	if callback == nil {
+		return fail.InvalidParameterError("callback", "cannot be nil")
+	}
+
+	task, xerr := publicIPTaskFromContext(ctx)
+	if xerr != nil {
+		return xerr
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.publicip")).Entering()
+	defer tracer.Exiting()
+
+	instance.lock.RLock()
+	defer instance.lock.RUnlock()
+
+	return instance.MetadataCore.BrowseFolder(func(buf []byte) fail.Error {
+		if task.Aborted() {
+			return fail.AbortedError(nil, "aborted")
+		}
+
+		apip := abstract.NewPublicIP()
+		xerr = apip.Deserialize(buf)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return xerr
+		}
+
+		return callback(apip)
+	})
+}
+
+// Create allocates a public IP
+func (instance *publicIP) Create(ctx context.Context, req abstract.PublicIPRequest) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	// note: do not test IsNull() here, it's expected to be IsNull() actually
+	if instance == nil {
+		return fail.InvalidInstanceError()
+	}
+	if !instance.IsNull() {
+		pipName := instance.GetName()
+		if pipName != "" {
+			return fail.NotAvailableError("already carrying Public IP '%s'", pipName)
+		}
+		return fail.InvalidInstanceContentError("instance", "is not null value")
+	}
+	if ctx == nil {
+		return fail.InvalidParameterError("ctx", "cannot be nil")
+	}
+	if req.Name == "" {
+		return fail.InvalidParameterError("req.Name", "cannot be empty string")
+	}
+
+	task, xerr := publicIPTaskFromContext(ctx)
+	if xerr != nil {
+		return xerr
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.publicip"), "('%s', '%s')", req.Name, req.Type).Entering()
+	defer tracer.Exiting()
+
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	// Check if Public IP exists and is managed by SafeScale
+	svc := instance.GetService()
+	existing, xerr := LoadPublicIP(svc, req.Name)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			// continue
+			debug.IgnoreError(xerr)
+		default:
+			return fail.Wrap(xerr, "failed to check if Public IP '%s' already exists", req.Name)
+		}
+	} else {
+		existing.Released()
+		return fail.DuplicateError("there is already a Public IP named '%s'", req.Name)
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	apip, xerr := svc.CreatePublicIP(req)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	// Starting from here, release Public IP if exiting with error
+	defer func() {
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			if derr := svc.DeletePublicIP(apip.ID); derr != nil {
+				_ = xerr.AddConsequence(fail.Wrap(derr, "cleaning up on %s, failed to release Public IP '%s'", ActionFromError(xerr), req.Name))
+			}
+		}
+	}()
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	return instance.carry(apip)
+}
+
+// Delete releases the Public IP and deletes its metadata
+// If the Public IP is bound to a host and force is false, returns *fail.ErrNotAvailable
+func (instance *publicIP) Delete(ctx context.Context, force bool) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return fail.InvalidParameterError("ctx", "cannot be nil")
+	}
+
+	task, xerr := publicIPTaskFromContext(ctx)
+	if xerr != nil {
+		return xerr
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.publicip"), "(%v)", force).Entering()
+	defer tracer.Exiting()
+
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	hostID, xerr := instance.unsafeGetBoundHost()
+	if xerr != nil {
+		return xerr
+	}
+
+	svc := instance.GetService()
+	if hostID != "" {
+		if !force {
+			return fail.NotAvailableError("Public IP '%s' is still bound to Host '%s'", instance.GetName(), hostID)
+		}
+
+		xerr = instance.unsafeUnbindFromHostID(hostID)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return xerr
+		}
+	}
+
+	xerr = svc.DeletePublicIP(instance.GetID())
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			logrus.Debugf("Unable to find the Public IP on provider side, cleaning up metadata")
+			debug.IgnoreError(xerr)
+		default:
+			return xerr
+		}
+	}
+
+	// remove metadata
+	return instance.MetadataCore.Delete()
+}
+
+// GetAddress returns the IP address of the Public IP
+func (instance *publicIP) GetAddress() (_ string, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return "", fail.InvalidInstanceError()
+	}
+
+	instance.lock.RLock()
+	defer instance.lock.RUnlock()
+
+	var address string
+	xerr = instance.Inspect(func(clonable data.Clonable, _ *serialize.JSONProperties) fail.Error {
+		apip, ok := clonable.(*abstract.PublicIP)
+		if !ok {
+			return fail.InconsistentError("'*abstract.PublicIP' expected, '%s' provided", reflect.TypeOf(clonable).String())
+		}
+
+		address = apip.IPAddress
+		return nil
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return "", xerr
+	}
+	return address, nil
+}
+
+// GetBoundHost returns the ID of the Host the Public IP is bound to, or empty string if not bound
+func (instance *publicIP) GetBoundHost() (_ string, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return "", fail.InvalidInstanceError()
+	}
+
+	instance.lock.RLock()
+	defer instance.lock.RUnlock()
+
+	return instance.unsafeGetBoundHost()
+}
+
+// unsafeGetBoundHost is the non goroutine-safe implementation of GetBoundHost
+func (instance *publicIP) unsafeGetBoundHost() (string, fail.Error) {
+	var hostID string
+	xerr := instance.Inspect(func(clonable data.Clonable, _ *serialize.JSONProperties) fail.Error {
+		apip, ok := clonable.(*abstract.PublicIP)
+		if !ok {
+			return fail.InconsistentError("'*abstract.PublicIP' expected, '%s' provided", reflect.TypeOf(clonable).String())
+		}
+
+		hostID = apip.HostID
+		return nil
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return "", xerr
+	}
+	return hostID, nil
+}
+
+// BindToHost binds the Public IP to the Host
+// If the Public IP is currently bound to another Host, it is moved to the new one.
+// A Host can be bound to only one Public IP, and cannot be bound if it already has a public IP of its own.
+func (instance *publicIP) BindToHost(ctx context.Context, host resources.Host) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return fail.InvalidParameterError("ctx", "cannot be nil")
+	}
+	if host == nil {
+		return fail.InvalidParameterCannotBeNilError("host")
+	}
+
+	task, xerr := publicIPTaskFromContext(ctx)
+	if xerr != nil {
+		return xerr
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	hostID := host.GetID()
+	hostName := host.GetName()
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.publicip"), "(%s)", hostName).Entering()
+	defer tracer.Exiting()
+
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	currentHostID, xerr := instance.unsafeGetBoundHost()
+	if xerr != nil {
+		return xerr
+	}
+	if currentHostID == hostID {
+		// already bound to this Host, nothing to do
+		return nil
+	}
+
+	pipID := instance.GetID()
+	pipName := instance.GetName()
+
+	// -- checks the target Host can receive the Public IP --
+	xerr = host.Inspect(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		innerXErr := props.Inspect(hostproperty.NetworkV2, func(clonable data.Clonable) fail.Error {
+			hnV2, ok := clonable.(*propertiesv2.HostNetworking)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv2.HostNetworking' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			if hnV2.PublicIPv4 != "" {
+				return fail.NotAvailableError("Host '%s' already has a public IP", hostName)
+			}
+			return nil
+		})
+		if innerXErr != nil {
+			return innerXErr
+		}
+
+		return props.Inspect(hostproperty.PublicIPsV1, func(clonable data.Clonable) fail.Error {
+			hpiV1, ok := clonable.(*propertiesv1.HostPublicIPs)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.HostPublicIPs' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			if len(hpiV1.ByID) > 0 {
+				return fail.NotAvailableError("Host '%s' is already bound to a Public IP", hostName)
+			}
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	// -- if bound to another Host, unbind from it first --
+	if currentHostID != "" {
+		xerr = instance.unsafeUnbindFromHostID(currentHostID)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return fail.Wrap(xerr, "failed to move Public IP '%s' from Host '%s'", pipName, currentHostID)
+		}
+	}
+
+	var apip *abstract.PublicIP
+	xerr = instance.Inspect(func(clonable data.Clonable, _ *serialize.JSONProperties) fail.Error {
+		var ok bool
+		apip, ok = clonable.(*abstract.PublicIP)
+		if !ok {
+			return fail.InconsistentError("'*abstract.PublicIP' expected, '%s' provided", reflect.TypeOf(clonable).String())
+		}
+
+		apip = apip.Clone().(*abstract.PublicIP)
+		return nil
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	svc := instance.GetService()
+	xerr = svc.BindPublicIPToHost(apip, hostID)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	// Starting from here, unbind Public IP from Host if exiting with error
+	defer func() {
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			if derr := svc.UnbindPublicIPFromHost(apip, hostID); derr != nil {
+				_ = xerr.AddConsequence(fail.Wrap(derr, "cleaning up on %s, failed to unbind Public IP '%s' from Host '%s'", ActionFromError(xerr), pipName, hostName))
+			}
+		}
+	}()
+
+	xerr = host.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Alter(hostproperty.PublicIPsV1, func(clonable data.Clonable) fail.Error {
+			hpiV1, ok := clonable.(*propertiesv1.HostPublicIPs)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.HostPublicIPs' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			hpiV1.ByID[pipID] = apip.IPAddress
+			hpiV1.ByName[pipName] = pipID
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	// Starting from here, remove Public IP from Host metadata if exiting with error
+	defer func() {
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			if derr := removePublicIPFromHostMetadata(host, pipID); derr != nil {
+				_ = xerr.AddConsequence(fail.Wrap(derr, "cleaning up on %s, failed to remove Public IP '%s' from metadata of Host '%s'", ActionFromError(xerr), pipName, hostName))
+			}
+		}
+	}()
+
+	return instance.Alter(func(clonable data.Clonable, _ *serialize.JSONProperties) fail.Error {
+		apip, ok := clonable.(*abstract.PublicIP)
+		if !ok {
+			return fail.InconsistentError("'*abstract.PublicIP' expected, '%s' provided", reflect.TypeOf(clonable).String())
+		}
+
+		apip.HostID = hostID
+		return nil
+	})
+}
+
+// UnbindFromHost unbinds the Public IP from the Host
+// Returns *fail.ErrNotFound if the Public IP is not bound to the Host
+func (instance *publicIP) UnbindFromHost(ctx context.Context, host resources.Host) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return fail.InvalidParameterError("ctx", "cannot be nil")
+	}
+	if host == nil {
+		return fail.InvalidParameterCannotBeNilError("host")
+	}
+
+	task, xerr := publicIPTaskFromContext(ctx)
+	if xerr != nil {
+		return xerr
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	hostID := host.GetID()
+	hostName := host.GetName()
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.publicip"), "(%s)", hostName).Entering()
+	defer tracer.Exiting()
+
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	currentHostID, xerr := instance.unsafeGetBoundHost()
+	if xerr != nil {
+		return xerr
+	}
+	if currentHostID != hostID {
+		return fail.NotFoundError("Public IP '%s' is not bound to Host '%s'", instance.GetName(), hostName)
+	}
+
+	return instance.unsafeUnbindFromHostID(hostID)
+}
+
+// unsafeUnbindFromHostID unbinds the Public IP from the Host identified by hostID, on provider side and in metadata
+// Note: the Host may have been deleted on provider side; in this case, the metadata are updated anyway
+func (instance *publicIP) unsafeUnbindFromHostID(hostID string) (xerr fail.Error) {
+	var apip *abstract.PublicIP
+	xerr = instance.Inspect(func(clonable data.Clonable, _ *serialize.JSONProperties) fail.Error {
+		var ok bool
+		apip, ok = clonable.(*abstract.PublicIP)
+		if !ok {
+			return fail.InconsistentError("'*abstract.PublicIP' expected, '%s' provided", reflect.TypeOf(clonable).String())
+		}
+
+		apip = apip.Clone().(*abstract.PublicIP)
+		return nil
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	svc := instance.GetService()
+	xerr = svc.UnbindPublicIPFromHost(apip, hostID)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			// Public IP not bound to Host on provider side, consider unbind as a success
+			debug.IgnoreError(xerr)
+		default:
+			return xerr
+		}
+	}
+
+	hostInstance, xerr := LoadHost(svc, hostID)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			// Host metadata not found, nothing to update on this side
+			debug.IgnoreError(xerr)
+		default:
+			return xerr
+		}
+	} else {
+		defer hostInstance.Released()
+
+		xerr = removePublicIPFromHostMetadata(hostInstance, apip.ID)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return xerr
+		}
+	}
+
+	return instance.Alter(func(clonable data.Clonable, _ *serialize.JSONProperties) fail.Error {
+		apip, ok := clonable.(*abstract.PublicIP)
+		if !ok {
+			return fail.InconsistentError("'*abstract.PublicIP' expected, '%s' provided", reflect.TypeOf(clonable).String())
+		}
+
+		apip.HostID = ""
+		return nil
+	})
+}
+
+// removePublicIPFromHostMetadata removes the reference to Public IP identified by pipID from Host metadata
+func removePublicIPFromHostMetadata(host resources.Host, pipID string) fail.Error {
+	return host.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Alter(hostproperty.PublicIPsV1, func(clonable data.Clonable) fail.Error {
+			hpiV1, ok := clonable.(*propertiesv1.HostPublicIPs)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.HostPublicIPs' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			delete(hpiV1.ByID, pipID)
+			for k, v := range hpiV1.ByName {
+				if v == pipID {
+					delete(hpiV1.ByName, k)
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// ToProtocol converts the Public IP to protocol message PublicIPResponse
+func (instance *publicIP) ToProtocol() (_ *protocol.PublicIPResponse, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	instance.lock.RLock()
+	defer instance.lock.RUnlock()
+
+	var out *protocol.PublicIPResponse
+	xerr = instance.Inspect(func(clonable data.Clonable, _ *serialize.JSONProperties) fail.Error {
+		apip, ok := clonable.(*abstract.PublicIP)
+		if !ok {
+			return fail.InconsistentError("'*abstract.PublicIP' expected, '%s' provided", reflect.TypeOf(clonable).String())
+		}
+
+		out = converters.PublicIPFromAbstractToProtocol(apip)
+		return nil
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	if out.Host != nil {
+		hostInstance, xerr := LoadHost(instance.GetService(), out.Host.Id)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			switch xerr.(type) {
+			case *fail.ErrNotFound:
+				debug.IgnoreError(xerr)
+			default:
+				return nil, xerr
+			}
+		} else {
+			out.Host.Name = hostInstance.GetName()
+			hostInstance.Released()
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	_ "github.com/CS-SI/SafeScale/lib/server/iaas/providers/memory"
+	memorystack "github.com/CS-SI/SafeScale/lib/server/iaas/stacks/memory"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// getMemoryService returns a service using a fresh in-memory tenant
+func getMemoryService(t *testing.T) iaas.Service {
+	memorystack.Forget("TestOperations")
+	// the hosts of the memory stack are rebooted at once, there is no need to wait for them
+	require.Nil(t, os.Setenv("SAFESCALE_DEFAULT_DELAY", "100ms"))
+
+	tenant := map[string]interface{}{
+		"name":   "TestOperations",
+		"client": "memory",
+		"compute": map[string]interface{}{
+			"Region": "test",
+		},
+		"objectstorage": map[string]interface{}{
+			"Type":     "memory",
+			"Endpoint": "TestOperations",
+		},
+	}
+	svc, xerr := iaas.BuildService(tenant, "v21.05.0")
+	require.Nil(t, xerr)
+	return svc
+}
+
+func Test_publicIP_IsNull_Empty(t *testing.T) {
+	rp := &publicIP{}
+	itis := rp.IsNull()
+	require.True(t, itis)
+}
+
+func Test_publicIP_IsNull_Nil(t *testing.T) {
+	var rp *publicIP
+	//goland:noinspection GoNilness
+	itis := rp.IsNull()
+	require.True(t, itis)
+}
+
+func Test_publicIP_NullValue(t *testing.T) {
+	rp := PublicIPNullValue()
+	require.True(t, rp.IsNull())
+
+	_, xerr := rp.GetAddress()
+	require.NotNil(t, xerr)
+}
+
+func Test_publicIP_CreateLoadDelete(t *testing.T) {
+	svc := getMemoryService(t)
+	ctx := context.Background()
+
+	rp, xerr := NewPublicIP(svc)
+	require.Nil(t, xerr)
+	require.Nil(t, rp.Create(ctx, abstract.PublicIPRequest{Name: "front", Description: "stable front IP"}))
+	address, xerr := rp.GetAddress()
+	require.Nil(t, xerr)
+	assert.NotEmpty(t, address)
+	rp.Released()
+
+	other, xerr := NewPublicIP(svc)
+	require.Nil(t, xerr)
+	xerr = other.Create(ctx, abstract.PublicIPRequest{Name: "front"})
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrDuplicate{}, xerr)
+
+	rp, xerr = LoadPublicIP(svc, "front")
+	require.Nil(t, xerr)
+	hostID, xerr := rp.GetBoundHost()
+	require.Nil(t, xerr)
+	assert.Empty(t, hostID)
+	pb, xerr := rp.ToProtocol()
+	require.Nil(t, xerr)
+	assert.Equal(t, address, pb.GetIpAddress())
+	assert.Nil(t, pb.GetHost())
+
+	require.Nil(t, rp.Delete(ctx, false))
+	_, xerr = LoadPublicIP(svc, "front")
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrNotFound{}, xerr)
+	list, xerr := svc.ListPublicIPs()
+	require.Nil(t, xerr)
+	assert.Empty(t, list)
+}
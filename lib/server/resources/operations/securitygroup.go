@@ -453,6 +453,117 @@ func (instance *SecurityGroup) Create(ctx context.Context, networkID, name, desc
 	return nil
 }
 
+// Import registers in metadata an existing Security Group of the provider, owned by the Network referenced by 'networkRef'
+func (instance *SecurityGroup) Import(ctx context.Context, networkRef, ref string) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil {
+		return fail.InvalidInstanceError()
+	}
+	if !instance.IsNull() {
+		return fail.InvalidInstanceContentError("instance", "is not null value, cannot overwrite")
+	}
+	if ctx == nil {
+		return fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if networkRef = strings.TrimSpace(networkRef); networkRef == "" {
+		return fail.InvalidParameterError("networkRef", "cannot be empty string")
+	}
+	if ref = strings.TrimSpace(ref); ref == "" {
+		return fail.InvalidParameterError("ref", "cannot be empty string")
+	}
+
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotAvailable:
+			task, xerr = concurrency.VoidTask()
+			if xerr != nil {
+				return xerr
+			}
+		default:
+			return xerr
+		}
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.security-group"), "('%s', '%s')", networkRef, ref).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	svc := instance.GetService()
+	networkInstance, xerr := LoadNetwork(svc, networkRef)
+	if xerr != nil {
+		return xerr
+	}
+	defer networkInstance.Released()
+
+	asg, xerr := svc.InspectSecurityGroup(ref)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	for _, v := range []string{asg.ID, asg.Name} {
+		if v == "" {
+			continue
+		}
+		found, xerr := lookupSecurityGroup(svc, v)
+		if xerr != nil {
+			return fail.Wrap(xerr, "failed to check if Security Group '%s' already exists", v)
+		}
+		if found {
+			return fail.DuplicateError("cannot import Security Group '%s': there is already such a Security Group in metadata", ref)
+		}
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	// make sure Network ID is stored in Security Group abstract
+	asg.Network = networkInstance.GetID()
+	xerr = instance.carry(asg)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	defer func() {
+		if xerr != nil {
+			if derr := instance.MetadataCore.Delete(); derr != nil {
+				_ = xerr.AddConsequence(fail.Wrap(derr, "cleaning up on %s, failed to delete Security Group '%s' metadata", ActionFromError(xerr), asg.Name))
+			}
+		}
+	}()
+
+	// -- update SecurityGroups in Network metadata
+	xerr = networkInstance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Alter(networkproperty.SecurityGroupsV1, func(clonable data.Clonable) fail.Error {
+			nsgV1, ok := clonable.(*propertiesv1.NetworkSecurityGroups)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.NetworkSecurityGroups' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			nsgV1.ByID[asg.ID] = asg.Name
+			nsgV1.ByName[asg.Name] = asg.ID
+			return nil
+		})
+	})
+	if xerr != nil {
+		return xerr
+	}
+
+	logrus.Infof("Security Group '%s' imported successfully", asg.Name)
+	return nil
+}
+
 // Delete deletes a Security Group
 func (instance *SecurityGroup) Delete(ctx context.Context, force bool) (xerr fail.Error) {
 	defer fail.OnPanic(&xerr)
@@ -1060,11 +1171,6 @@ func (instance *SecurityGroup) GetBoundSubnets(ctx context.Context) (list []*pro
 	return list, xerr
 }
 
-// CheckConsistency checks the rules in the security group on provider side are identical to the ones registered in metadata
-func (instance *SecurityGroup) CheckConsistency(_ context.Context) fail.Error {
-	return fail.NotImplementedError()
-}
-
 // ToProtocol converts a Security Group to protobuf message
 func (instance *SecurityGroup) ToProtocol() (_ *protocol.SecurityGroupResponse, xerr fail.Error) {
 	defer fail.OnPanic(&xerr)
@@ -0,0 +1,487 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/ipversion"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/securitygroupruledirection"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/data/serialize"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// securityGroupRuleAtom is a rule reduced to a single source or target, normalized to be compared whatever the way
+// the provider stores it
+type securityGroupRuleAtom struct {
+	key  string
+	rule *abstract.SecurityGroupRule // the atom as a rule, carrying the IDs of the rule it comes from
+}
+
+// normalizeSecurityGroupProtocol returns the protocol name used to compare rules; empty means any protocol
+func normalizeSecurityGroupProtocol(in string) string {
+	switch proto := strings.ToLower(strings.TrimSpace(in)); proto {
+	case "-1", "any", "all":
+		return ""
+	case "1":
+		return "icmp"
+	case "6":
+		return "tcp"
+	case "17":
+		return "udp"
+	default:
+		return proto
+	}
+}
+
+// normalizeSecurityGroupPorts returns the port range used to compare rules, reproducing the way providers store it
+func normalizeSecurityGroupPorts(protocol string, from, to int32) (int32, int32) {
+	switch protocol {
+	case "":
+		return 0, 0
+	case "tcp", "udp":
+		if from <= 1 && to == 65535 {
+			return 0, 0
+		}
+	}
+	if from == 0 && to != 0 {
+		from = to
+	}
+	if from != 0 && to == 0 {
+		to = from
+	}
+	if to < from {
+		from, to = to, from
+	}
+	return from, to
+}
+
+// normalizeSecurityGroupInvolved returns the source or target used to compare rules: CIDR in canonical form,
+// or Security Group ID
+func normalizeSecurityGroupInvolved(etherType ipversion.Enum, in string) string {
+	in = strings.TrimSpace(in)
+	if in == "" {
+		if etherType == ipversion.IPv6 {
+			return "::/0"
+		}
+		return "0.0.0.0/0"
+	}
+	if _, ipnet, err := net.ParseCIDR(in); err == nil {
+		return ipnet.String()
+	}
+	if ip := net.ParseIP(in); ip != nil {
+		if ip.To4() != nil {
+			return ip.String() + "/32"
+		}
+		return ip.String() + "/128"
+	}
+	return in
+}
+
+// splitSecurityGroupRule splits a rule in atoms, one per source (ingress) or target (egress)
+func splitSecurityGroupRule(rule *abstract.SecurityGroupRule) []securityGroupRuleAtom {
+	etherType := rule.EtherType
+	if etherType != ipversion.IPv6 {
+		etherType = ipversion.IPv4
+	}
+	protocol := normalizeSecurityGroupProtocol(rule.Protocol)
+	from, to := normalizeSecurityGroupPorts(protocol, rule.PortFrom, rule.PortTo)
+
+	involved := rule.Sources
+	if rule.Direction == securitygroupruledirection.Egress {
+		involved = rule.Targets
+	}
+	if len(involved) == 0 {
+		involved = []string{""}
+	}
+
+	out := make([]securityGroupRuleAtom, 0, len(involved))
+	for _, v := range involved {
+		atom := rule.Clone().(*abstract.SecurityGroupRule)
+		if rule.Direction == securitygroupruledirection.Egress {
+			atom.Sources, atom.Targets = nil, []string{v}
+		} else {
+			atom.Sources, atom.Targets = []string{v}, nil
+		}
+		out = append(out, securityGroupRuleAtom{
+			key:  fmt.Sprintf("%d|%d|%s|%d|%d|%s", rule.Direction, etherType, protocol, from, to, normalizeSecurityGroupInvolved(etherType, v)),
+			rule: atom,
+		})
+	}
+	return out
+}
+
+// shareSecurityGroupRuleID tells if the 2 lists of provider rule IDs have an ID in common
+func shareSecurityGroupRuleID(a, b []string) bool {
+	for _, v := range a {
+		for _, w := range b {
+			if v == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// compareSecurityGroupRules compares the rules registered in metadata ('expected') with the ones on provider side ('actual')
+// A rule of 'expected' is in sync if each of its atoms has an equivalent atom in 'actual'; otherwise, it is reported as
+// changed if provider rules still use some of its IDs, and as removed if not. The atoms of 'actual' not used by 'expected'
+// are reported as added.
+// Rules of 'expected' are returned as is (not cloned) in 'removed' and 'changed'
+func compareSecurityGroupRules(expected, actual abstract.SecurityGroupRules) (added, removed abstract.SecurityGroupRules, changed []abstract.SecurityGroupRuleChange) {
+	var actualAtoms []securityGroupRuleAtom
+	for _, v := range actual {
+		if v != nil {
+			actualAtoms = append(actualAtoms, splitSecurityGroupRule(v)...)
+		}
+	}
+	byKey := make(map[string][]int, len(actualAtoms))
+	for k, v := range actualAtoms {
+		byKey[v.key] = append(byKey[v.key], k)
+	}
+
+	// owner contains the index in 'expected' of the rule using the atom, -1 if none
+	owner := make([]int, len(actualAtoms))
+	for k := range owner {
+		owner[k] = -1
+	}
+
+	var drifted []int
+	for r, rule := range expected {
+		if rule == nil {
+			continue
+		}
+
+		inSync := true
+		for _, atom := range splitSecurityGroupRule(rule) {
+			found := false
+			for _, k := range byKey[atom.key] {
+				if owner[k] == -1 {
+					owner[k] = r
+					found = true
+					break
+				}
+			}
+			if !found {
+				inSync = false
+			}
+		}
+		if !inSync {
+			drifted = append(drifted, r)
+		}
+	}
+
+	for _, r := range drifted {
+		var current abstract.SecurityGroupRules
+		for k, v := range actualAtoms {
+			if (owner[k] == -1 || owner[k] == r) && shareSecurityGroupRuleID(v.rule.IDs, expected[r].IDs) {
+				owner[k] = r
+				current = append(current, v.rule)
+			}
+		}
+		if len(current) > 0 {
+			changed = append(changed, abstract.SecurityGroupRuleChange{Expected: expected[r], Actual: current})
+		} else {
+			removed = append(removed, expected[r])
+		}
+	}
+
+	for k, v := range actualAtoms {
+		if owner[k] == -1 {
+			added = append(added, v.rule)
+		}
+	}
+	return added, removed, changed
+}
+
+// newSecurityGroupDrift returns the drift between the rules of 'asg' and the ones of 'providerASG', with clones of the rules
+func newSecurityGroupDrift(asg, providerASG *abstract.SecurityGroup) *abstract.SecurityGroupDrift {
+	added, removed, changed := compareSecurityGroupRules(asg.Rules, providerASG.Rules)
+	out := &abstract.SecurityGroupDrift{
+		ID:      asg.ID,
+		Name:    asg.Name,
+		Added:   added,
+		Removed: make(abstract.SecurityGroupRules, 0, len(removed)),
+		Changed: make([]abstract.SecurityGroupRuleChange, 0, len(changed)),
+	}
+	for _, v := range removed {
+		out.Removed = append(out.Removed, v.Clone().(*abstract.SecurityGroupRule))
+	}
+	for _, v := range changed {
+		out.Changed = append(out.Changed, abstract.SecurityGroupRuleChange{Expected: v.Expected.Clone().(*abstract.SecurityGroupRule), Actual: v.Actual})
+	}
+	return out
+}
+
+// CheckConsistency checks the rules in the security group on provider side are identical to the ones registered in metadata,
+// and returns the differences found
+func (instance *SecurityGroup) CheckConsistency(ctx context.Context) (_ *abstract.SecurityGroupDrift, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	task, xerr := taskFromContextOrVoid(ctx)
+	if xerr != nil {
+		return nil, xerr
+	}
+	if task.Aborted() {
+		return nil, fail.AbortedError(nil, "aborted")
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.security-group"), "").Entering()
+	defer tracer.Exiting()
+
+	instance.lock.RLock()
+	defer instance.lock.RUnlock()
+
+	var drift *abstract.SecurityGroupDrift
+	xerr = instance.Inspect(func(clonable data.Clonable, _ *serialize.JSONProperties) fail.Error {
+		asg, ok := clonable.(*abstract.SecurityGroup)
+		if !ok {
+			return fail.InconsistentError("'*abstract.SecurityGroup' expected, '%s' provided", reflect.TypeOf(clonable).String())
+		}
+
+		providerASG, innerXErr := instance.GetService().InspectSecurityGroup(asg.ID)
+		if innerXErr != nil {
+			return innerXErr
+		}
+
+		drift = newSecurityGroupDrift(asg, providerASG)
+		return nil
+	})
+	if xerr != nil {
+		return nil, xerr
+	}
+	return drift, nil
+}
+
+// Reconcile checks the consistency of the rules of the Security Group and fixes the differences found, either by
+// re-applying on provider side the rules registered in metadata, or, if 'adopt' is true, by registering in metadata
+// the rules found on provider side. Returns the differences found before the fix
+func (instance *SecurityGroup) Reconcile(ctx context.Context, adopt bool) (_ *abstract.SecurityGroupDrift, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	task, xerr := taskFromContextOrVoid(ctx)
+	if xerr != nil {
+		return nil, xerr
+	}
+	if task.Aborted() {
+		return nil, fail.AbortedError(nil, "aborted")
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.security-group"), "(%v)", adopt).Entering()
+	defer tracer.Exiting()
+
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	var drift *abstract.SecurityGroupDrift
+	xerr = instance.Alter(func(clonable data.Clonable, _ *serialize.JSONProperties) fail.Error {
+		asg, ok := clonable.(*abstract.SecurityGroup)
+		if !ok {
+			return fail.InconsistentError("'*abstract.SecurityGroup' expected, '%s' provided", reflect.TypeOf(clonable).String())
+		}
+
+		svc := instance.GetService()
+		providerASG, innerXErr := svc.InspectSecurityGroup(asg.ID)
+		if innerXErr != nil {
+			return innerXErr
+		}
+
+		drift = newSecurityGroupDrift(asg, providerASG)
+		if drift.IsEmpty() {
+			return fail.AlteredNothingError()
+		}
+
+		added, removed, changed := compareSecurityGroupRules(asg.Rules, providerASG.Rules)
+		if adopt {
+			asg.Rules = adoptSecurityGroupRules(asg.Rules, added, removed, changed)
+		} else {
+			innerXErr = reapplySecurityGroupRules(svc, asg, added, removed, changed)
+			if innerXErr != nil {
+				return innerXErr
+			}
+		}
+		drift.Fixed = true
+		return nil
+	})
+	if xerr != nil {
+		return nil, xerr
+	}
+	return drift, nil
+}
+
+// adoptSecurityGroupRules returns the rules of the Security Group after adoption of the differences found on provider side
+func adoptSecurityGroupRules(rules, added, removed abstract.SecurityGroupRules, changed []abstract.SecurityGroupRuleChange) abstract.SecurityGroupRules {
+	out := make(abstract.SecurityGroupRules, 0, len(rules)+len(added))
+	for _, v := range rules {
+		replaced := false
+		for _, w := range removed {
+			if v == w {
+				replaced = true
+				break
+			}
+		}
+		for _, w := range changed {
+			if v == w.Expected {
+				out = append(out, w.Actual...)
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			out = append(out, v)
+		}
+	}
+	return append(out, added...)
+}
+
+// reapplySecurityGroupRules deletes on provider side the rules not registered in metadata, and recreates the rules
+// of metadata removed or changed on provider side, updating their IDs in 'asg'
+func reapplySecurityGroupRules(svc iaas.Service, asg *abstract.SecurityGroup, added, removed abstract.SecurityGroupRules, changed []abstract.SecurityGroupRuleChange) fail.Error {
+	single := func(rule *abstract.SecurityGroupRule) *abstract.SecurityGroup {
+		return &abstract.SecurityGroup{ID: asg.ID, Name: asg.Name, Network: asg.Network, Rules: abstract.SecurityGroupRules{rule}}
+	}
+
+	for _, v := range added {
+		_, xerr := svc.DeleteRuleFromSecurityGroup(single(v), v)
+		if xerr != nil {
+			switch xerr.(type) {
+			case *fail.ErrNotFound:
+				debug.IgnoreError(xerr)
+			default:
+				return fail.Wrap(xerr, "failed to delete unexpected rule of Security Group '%s'", asg.Name)
+			}
+		}
+	}
+
+	drifted := make(abstract.SecurityGroupRules, 0, len(removed)+len(changed))
+	drifted = append(drifted, removed...)
+	for _, v := range changed {
+		drifted = append(drifted, v.Expected)
+	}
+	for _, v := range drifted {
+		// removes what remains of the rule on provider side before recreating it
+		_, xerr := svc.DeleteRuleFromSecurityGroup(single(v), v)
+		if xerr != nil {
+			switch xerr.(type) {
+			case *fail.ErrNotFound:
+				debug.IgnoreError(xerr)
+			default:
+				return fail.Wrap(xerr, "failed to delete changed rule of Security Group '%s'", asg.Name)
+			}
+		}
+
+		rule := v.Clone().(*abstract.SecurityGroupRule)
+		rule.IDs = nil
+		providerASG, xerr := svc.AddRuleToSecurityGroup(&abstract.SecurityGroup{ID: asg.ID, Name: asg.Name, Network: asg.Network}, rule)
+		if xerr != nil {
+			return fail.Wrap(xerr, "failed to recreate rule of Security Group '%s'", asg.Name)
+		}
+		if providerASG != nil && len(providerASG.Rules) > 0 {
+			rule = providerASG.Rules[len(providerASG.Rules)-1]
+		}
+		for k, w := range asg.Rules {
+			if w == v {
+				asg.Rules[k] = rule
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// CheckSecurityGroupsConsistency checks the consistency of all the Security Groups of the tenant, fixing them if
+// 'fix' is true (see SecurityGroup.Reconcile for the meaning of 'adopt')
+// A failure on one Security Group does not stop the check of the others; the failures are returned as a fail.ErrorList
+func CheckSecurityGroupsConsistency(ctx context.Context, svc iaas.Service, fix, adopt bool) ([]*abstract.SecurityGroupDrift, fail.Error) {
+	if svc == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("svc")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+
+	browser, xerr := NewSecurityGroup(svc)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	var refs []string
+	xerr = browser.Browse(ctx, func(asg *abstract.SecurityGroup) fail.Error {
+		refs = append(refs, asg.ID)
+		return nil
+	})
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	var (
+		out    []*abstract.SecurityGroupDrift
+		errors []error
+	)
+	for _, ref := range refs {
+		drift, xerr := checkSecurityGroupConsistency(ctx, svc, ref, fix, adopt)
+		if xerr != nil {
+			logrus.Warnf("failed to check consistency of Security Group '%s': %v", ref, xerr)
+			errors = append(errors, fail.Wrap(xerr, "failed to check consistency of Security Group '%s'", ref))
+			continue
+		}
+		out = append(out, drift)
+	}
+	if len(errors) > 0 {
+		return out, fail.NewErrorList(errors)
+	}
+	return out, nil
+}
+
+// checkSecurityGroupConsistency checks and optionally fixes the Security Group identified by 'ref'
+func checkSecurityGroupConsistency(ctx context.Context, svc iaas.Service, ref string, fix, adopt bool) (*abstract.SecurityGroupDrift, fail.Error) {
+	sgInstance, xerr := LoadSecurityGroup(svc, ref)
+	if xerr != nil {
+		return nil, xerr
+	}
+	defer sgInstance.Released()
+
+	if fix {
+		return sgInstance.Reconcile(ctx, adopt)
+	}
+	return sgInstance.CheckConsistency(ctx)
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/ipversion"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/securitygroupruledirection"
+)
+
+func newTestIngressRule(ids []string, protocol string, from, to int32, sources ...string) *abstract.SecurityGroupRule {
+	return &abstract.SecurityGroupRule{
+		IDs:       ids,
+		EtherType: ipversion.IPv4,
+		Direction: securitygroupruledirection.Ingress,
+		Protocol:  protocol,
+		PortFrom:  from,
+		PortTo:    to,
+		Sources:   sources,
+	}
+}
+
+func Test_compareSecurityGroupRules(t *testing.T) {
+	ssh := newTestIngressRule([]string{"1", "2"}, "tcp", 22, 0, "10.0.0.0/24", "192.168.1.1")
+	https := newTestIngressRule([]string{"3"}, "tcp", 443, 443, "0.0.0.0/0")
+	icmp := newTestIngressRule([]string{"4"}, "icmp", 0, 0, "10.0.0.0/24")
+	expected := abstract.SecurityGroupRules{ssh, https, icmp}
+
+	// provider splits rules per source, with its own descriptions, port ranges and address formats
+	inSync := abstract.SecurityGroupRules{
+		newTestIngressRule([]string{"1"}, "TCP", 22, 22, "10.0.0.0/24"),
+		newTestIngressRule([]string{"2"}, "tcp", 22, 22, "192.168.1.1/32"),
+		newTestIngressRule([]string{"3"}, "tcp", 443, 443, ""),
+		newTestIngressRule([]string{"4"}, "icmp", 0, 0, "10.0.0.0/24"),
+	}
+	inSync[0].Description = "ssh (10.0.0.0/24)"
+	added, removed, changed := compareSecurityGroupRules(expected, inSync)
+	assert.Empty(t, added)
+	assert.Empty(t, removed)
+	assert.Empty(t, changed)
+
+	// port of ssh changed for one source, https removed, rule added
+	drifted := abstract.SecurityGroupRules{
+		newTestIngressRule([]string{"1"}, "tcp", 22, 22, "10.0.0.0/24"),
+		newTestIngressRule([]string{"2"}, "tcp", 2222, 2222, "192.168.1.1/32"),
+		newTestIngressRule([]string{"4"}, "icmp", 0, 0, "10.0.0.0/24"),
+		newTestIngressRule([]string{"5"}, "", 0, 0, "0.0.0.0/0"),
+	}
+	added, removed, changed = compareSecurityGroupRules(expected, drifted)
+	require.Len(t, added, 1)
+	assert.Equal(t, []string{"5"}, added[0].IDs)
+	require.Len(t, removed, 1)
+	assert.True(t, removed[0] == https)
+	require.Len(t, changed, 1)
+	assert.True(t, changed[0].Expected == ssh)
+	require.Len(t, changed[0].Actual, 2)
+	assert.Equal(t, []string{"1"}, changed[0].Actual[0].IDs)
+	assert.Equal(t, int32(2222), changed[0].Actual[1].PortFrom)
+
+	// adoption replaces changed rules with provider ones, drops removed ones and appends added ones
+	adopted := adoptSecurityGroupRules(expected, added, removed, changed)
+	require.Len(t, adopted, 4)
+	added, removed, changed = compareSecurityGroupRules(adopted, drifted)
+	assert.Empty(t, added)
+	assert.Empty(t, removed)
+	assert.Empty(t, changed)
+}
+
+func Test_splitSecurityGroupRule(t *testing.T) {
+	egress := &abstract.SecurityGroupRule{
+		IDs:       []string{"1"},
+		Direction: securitygroupruledirection.Egress,
+		Protocol:  "-1",
+		PortFrom:  1,
+		PortTo:    65535,
+		Targets:   []string{"10.0.0.1", "fd00::/64"},
+	}
+	atoms := splitSecurityGroupRule(egress)
+	require.Len(t, atoms, 2)
+	assert.Equal(t, atoms[0].key, splitSecurityGroupRule(&abstract.SecurityGroupRule{Direction: securitygroupruledirection.Egress, EtherType: ipversion.IPv4, Targets: []string{"10.0.0.1/32"}})[0].key)
+	assert.Equal(t, []string{"fd00::/64"}, atoms[1].rule.Targets)
+	assert.Equal(t, []string{"1"}, atoms[1].rule.IDs)
+
+	from, to := normalizeSecurityGroupPorts("tcp", 0, 80)
+	assert.Equal(t, int32(80), from)
+	assert.Equal(t, int32(80), to)
+	from, to = normalizeSecurityGroupPorts("udp", 1, 65535)
+	assert.Equal(t, int32(0), from)
+	assert.Equal(t, int32(0), to)
+}
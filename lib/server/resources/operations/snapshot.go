@@ -0,0 +1,642 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/protocol"
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/resources"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumeproperty"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumestate"
+	"github.com/CS-SI/SafeScale/lib/server/resources/operations/converters"
+	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
+	"github.com/CS-SI/SafeScale/lib/utils/concurrency"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/data/cache"
+	"github.com/CS-SI/SafeScale/lib/utils/data/serialize"
+	"github.com/CS-SI/SafeScale/lib/utils/debug"
+	"github.com/CS-SI/SafeScale/lib/utils/debug/tracing"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/retry"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+const (
+	snapshotKind        = "snapshot"
+	snapshotsFolderName = "snapshots" // is the name of the Object Storage MetadataFolder used to store volume snapshot info
+)
+
+// snapshot links Object Storage MetadataFolder and volume snapshots
+type snapshot struct {
+	*MetadataCore
+
+	lock sync.RWMutex
+}
+
+// SnapshotNullValue returns an instance of snapshot corresponding to its null value.
+// The idea is to avoid nil pointer using SnapshotNullValue()
+func SnapshotNullValue() *snapshot {
+	return &snapshot{MetadataCore: NullCore()}
+}
+
+// NewSnapshot creates an instance of Snapshot
+func NewSnapshot(svc iaas.Service) (_ resources.Snapshot, xerr fail.Error) {
+	if svc == nil {
+		return SnapshotNullValue(), fail.InvalidParameterCannotBeNilError("svc")
+	}
+
+	coreInstance, xerr := NewCore(svc, snapshotKind, snapshotsFolderName, &abstract.Snapshot{})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return SnapshotNullValue(), xerr
+	}
+
+	instance := &snapshot{
+		MetadataCore: coreInstance,
+	}
+	return instance, nil
+}
+
+// LoadSnapshot loads the metadata of a volume snapshot
+func LoadSnapshot(svc iaas.Service, ref string) (rs resources.Snapshot, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if svc == nil {
+		return SnapshotNullValue(), fail.InvalidParameterCannotBeNilError("svc")
+	}
+	if ref = strings.TrimSpace(ref); ref == "" {
+		return SnapshotNullValue(), fail.InvalidParameterCannotBeEmptyStringError("ref")
+	}
+
+	snapshotCache, xerr := svc.GetCache(snapshotKind)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return SnapshotNullValue(), xerr
+	}
+
+	options := iaas.CacheMissOption(
+		func() (cache.Cacheable, fail.Error) { return onSnapshotCacheMiss(svc, ref) },
+		temporal.GetMetadataTimeout(),
+	)
+	cacheEntry, xerr := snapshotCache.Get(ref, options...)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			// rewrite NotFoundError, user does not bother about metadata stuff
+			return SnapshotNullValue(), fail.NotFoundError("failed to find Snapshot '%s'", ref)
+		default:
+			return SnapshotNullValue(), xerr
+		}
+	}
+
+	if rs = cacheEntry.Content().(resources.Snapshot); rs == nil {
+		return nil, fail.InconsistentError("nil value in cache for Snapshot with key '%s'", ref)
+	}
+	_ = cacheEntry.LockContent()
+	defer func() {
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			_ = cacheEntry.UnlockContent()
+		}
+	}()
+
+	return rs, nil
+}
+
+// onSnapshotCacheMiss is called when there is no instance in cache of Snapshot 'ref'
+func onSnapshotCacheMiss(svc iaas.Service, ref string) (cache.Cacheable, fail.Error) {
+	snapshotInstance, innerXErr := NewSnapshot(svc)
+	if innerXErr != nil {
+		return nil, innerXErr
+	}
+
+	if innerXErr = snapshotInstance.Read(ref); innerXErr != nil {
+		return nil, innerXErr
+	}
+
+	return snapshotInstance, nil
+}
+
+// IsNull tells if the instance is a null value
+func (instance *snapshot) IsNull() bool {
+	return instance == nil || instance.MetadataCore == nil || instance.MetadataCore.IsNull()
+}
+
+// carry overloads rv.core.Carry() to add Snapshot to service cache
+func (instance *snapshot) carry(clonable data.Clonable) (xerr fail.Error) {
+	if instance == nil {
+		return fail.InvalidInstanceError()
+	}
+	if !instance.IsNull() {
+		return fail.InvalidInstanceContentError("instance", "is not null value, cannot overwrite")
+	}
+	if clonable == nil {
+		return fail.InvalidParameterCannotBeNilError("clonable")
+	}
+	identifiable, ok := clonable.(data.Identifiable)
+	if !ok {
+		return fail.InvalidParameterError("clonable", "must also satisfy interface 'data.Identifiable'")
+	}
+
+	kindCache, xerr := instance.GetService().GetCache(instance.MetadataCore.GetKind())
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	xerr = kindCache.ReserveEntry(identifiable.GetID(), temporal.GetMetadataTimeout())
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+	defer func() {
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			if derr := kindCache.FreeEntry(identifiable.GetID()); derr != nil {
+				_ = xerr.AddConsequence(fail.Wrap(derr, "cleaning up on failure, failed to free %s cache entry for key '%s'", instance.MetadataCore.GetKind(), identifiable.GetID()))
+			}
+		}
+	}()
+
+	// Note: do not validate parameters, this call will do it
+	xerr = instance.MetadataCore.Carry(clonable)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	cacheEntry, xerr := kindCache.CommitEntry(identifiable.GetID(), instance)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	cacheEntry.LockContent()
+	return nil
+}
+
+// snapshotTaskFromContext returns the task contained in ctx, or a void task if there is none
+func snapshotTaskFromContext(ctx context.Context) (concurrency.Task, fail.Error) {
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotAvailable:
+			return concurrency.VoidTask()
+		default:
+			return nil, xerr
+		}
+	}
+	return task, nil
+}
+
+// Browse walks through Snapshot MetadataFolder and executes a callback for each entry
+func (instance *snapshot) Browse(ctx context.Context, callback func(*abstract.Snapshot) fail.Error) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	// Note: Browse is intended to be callable from null value, so do not validate instance with .IsNull()
+	if instance == nil {
+		return fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return fail.InvalidParameterError("ctx", "cannot be nil")
+	}
+	if callback == nil {
+		return fail.InvalidParameterError("callback", "cannot be nil")
+	}
+
+	task, xerr := snapshotTaskFromContext(ctx)
+	if xerr != nil {
+		return xerr
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.snapshot")).Entering()
+	defer tracer.Exiting()
+
+	instance.lock.RLock()
+	defer instance.lock.RUnlock()
+
+	return instance.MetadataCore.BrowseFolder(func(buf []byte) fail.Error {
+		if task.Aborted() {
+			return fail.AbortedError(nil, "aborted")
+		}
+
+		as := abstract.NewSnapshot()
+		xerr = as.Deserialize(buf)
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			return xerr
+		}
+
+		return callback(as)
+	})
+}
+
+// Create takes a snapshot of the volume identified by req.VolumeID, and waits until the snapshot is usable
+func (instance *snapshot) Create(ctx context.Context, req abstract.SnapshotRequest) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	// note: do not test IsNull() here, it's expected to be IsNull() actually
+	if instance == nil {
+		return fail.InvalidInstanceError()
+	}
+	if !instance.IsNull() {
+		snapshotName := instance.GetName()
+		if snapshotName != "" {
+			return fail.NotAvailableError("already carrying Snapshot '%s'", snapshotName)
+		}
+		return fail.InvalidInstanceContentError("instance", "is not null value")
+	}
+	if ctx == nil {
+		return fail.InvalidParameterError("ctx", "cannot be nil")
+	}
+	if req.Name == "" {
+		return fail.InvalidParameterError("req.Name", "cannot be empty string")
+	}
+	if req.VolumeID == "" {
+		return fail.InvalidParameterError("req.VolumeID", "cannot be empty string")
+	}
+
+	task, xerr := snapshotTaskFromContext(ctx)
+	if xerr != nil {
+		return xerr
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.snapshot"), "('%s', '%s')", req.Name, req.VolumeID).Entering()
+	defer tracer.Exiting()
+
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	// Check if Snapshot exists and is managed by SafeScale
+	svc := instance.GetService()
+	existing, xerr := LoadSnapshot(svc, req.Name)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			// continue
+			debug.IgnoreError(xerr)
+		default:
+			return fail.Wrap(xerr, "failed to check if Snapshot '%s' already exists", req.Name)
+		}
+	} else {
+		existing.Released()
+		return fail.DuplicateError("there is already a Snapshot named '%s'", req.Name)
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	as, xerr := svc.CreateVolumeSnapshot(req)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	// Starting from here, delete Snapshot if exiting with error
+	snapshotID := as.ID
+	defer func() {
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			if derr := svc.DeleteVolumeSnapshot(snapshotID); derr != nil {
+				_ = xerr.AddConsequence(fail.Wrap(derr, "cleaning up on %s, failed to delete Snapshot '%s'", ActionFromError(xerr), req.Name))
+			}
+		}
+	}()
+
+	as, xerr = waitSnapshotAvailable(task, svc, as)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	// Some providers do not keep the name of the snapshot; SafeScale metadata does
+	as.Name = req.Name
+	if as.Description == "" {
+		as.Description = req.Description
+	}
+	return instance.carry(as)
+}
+
+// waitSnapshotAvailable waits until the snapshot is usable, which may take some time depending on the size of the volume
+func waitSnapshotAvailable(task concurrency.Task, svc iaas.Service, as *abstract.Snapshot) (*abstract.Snapshot, fail.Error) {
+	if as.State == volumestate.Available {
+		return as, nil
+	}
+
+	xerr := retry.WhileUnsuccessful(
+		func() error {
+			if task.Aborted() {
+				return retry.StopRetryError(fail.AbortedError(nil, "aborted"))
+			}
+
+			current, innerXErr := svc.InspectVolumeSnapshot(as.ID)
+			if innerXErr != nil {
+				return innerXErr
+			}
+
+			switch current.State {
+			case volumestate.Available:
+				as = current
+				return nil
+			case volumestate.Error:
+				return retry.StopRetryError(fail.NewError("snapshot '%s' is in error state", as.Name))
+			default:
+				return fail.NotAvailableError("snapshot '%s' is not available yet (state: %s)", as.Name, current.State.String())
+			}
+		},
+		temporal.GetDefaultDelay(),
+		temporal.GetLongOperationTimeout(),
+	)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *retry.ErrStopRetry:
+			return nil, fail.ConvertError(fail.Cause(xerr))
+		case *retry.ErrTimeout:
+			return nil, fail.Wrap(fail.Cause(xerr), "timeout waiting for snapshot '%s' to become available", as.Name)
+		default:
+			return nil, xerr
+		}
+	}
+	return as, nil
+}
+
+// Delete deletes the snapshot and its metadata
+func (instance *snapshot) Delete(ctx context.Context) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return fail.InvalidParameterError("ctx", "cannot be nil")
+	}
+
+	task, xerr := snapshotTaskFromContext(ctx)
+	if xerr != nil {
+		return xerr
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.snapshot")).Entering()
+	defer tracer.Exiting()
+
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	xerr = instance.GetService().DeleteVolumeSnapshot(instance.GetID())
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			logrus.Debugf("Unable to find the Snapshot on provider side, cleaning up metadata")
+			debug.IgnoreError(xerr)
+		default:
+			return xerr
+		}
+	}
+
+	// remove metadata
+	return instance.MetadataCore.Delete()
+}
+
+// GetVolumeID returns the ID of the volume the snapshot has been taken from
+func (instance *snapshot) GetVolumeID() (_ string, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return "", fail.InvalidInstanceError()
+	}
+
+	instance.lock.RLock()
+	defer instance.lock.RUnlock()
+
+	var volumeID string
+	xerr = instance.Inspect(func(clonable data.Clonable, _ *serialize.JSONProperties) fail.Error {
+		as, ok := clonable.(*abstract.Snapshot)
+		if !ok {
+			return fail.InconsistentError("'*abstract.Snapshot' expected, '%s' provided", reflect.TypeOf(clonable).String())
+		}
+
+		volumeID = as.VolumeID
+		return nil
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return "", xerr
+	}
+	return volumeID, nil
+}
+
+// Restore creates a new volume filled with the content of the snapshot
+// If req.Size is 0, the size of the snapshot is used
+func (instance *snapshot) Restore(ctx context.Context, req abstract.VolumeRequest) (_ resources.Volume, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterError("ctx", "cannot be nil")
+	}
+	if req.Name == "" {
+		return nil, fail.InvalidParameterError("req.Name", "cannot be empty string")
+	}
+	if req.Size < 0 {
+		return nil, fail.InvalidParameterError("req.Size", "cannot be negative integer")
+	}
+
+	task, xerr := snapshotTaskFromContext(ctx)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	if task.Aborted() {
+		return nil, fail.AbortedError(nil, "aborted")
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.snapshot"), "('%s', %d, %s)", req.Name, req.Size, req.Speed.String()).Entering()
+	defer tracer.Exiting()
+
+	instance.lock.RLock()
+	defer instance.lock.RUnlock()
+
+	// Check if Volume exists and is managed by SafeScale
+	svc := instance.GetService()
+	existing, xerr := LoadVolume(svc, req.Name)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			// continue
+			debug.IgnoreError(xerr)
+		default:
+			return nil, fail.Wrap(xerr, "failed to check if Volume '%s' already exists", req.Name)
+		}
+	} else {
+		existing.Released()
+		return nil, fail.DuplicateError("there is already a Volume named '%s'", req.Name)
+	}
+
+	if task.Aborted() {
+		return nil, fail.AbortedError(nil, "aborted")
+	}
+
+	av, xerr := svc.CreateVolumeFromSnapshot(instance.GetID(), req)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	// Starting from here, remove volume if exiting with error
+	defer func() {
+		xerr = debug.InjectPlannedFail(xerr)
+		if xerr != nil {
+			if derr := svc.DeleteVolume(av.ID); derr != nil {
+				_ = xerr.AddConsequence(fail.Wrap(derr, "cleaning up on %s, failed to delete volume '%s'", ActionFromError(xerr), req.Name))
+			}
+		}
+	}()
+
+	if task.Aborted() {
+		return nil, fail.AbortedError(nil, "aborted")
+	}
+
+	// the Volume is usable only when available
+	restored, xerr := svc.WaitVolumeState(av.ID, volumestate.Available, temporal.GetOperationTimeout())
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, fail.Wrap(xerr, "failed to wait for Volume '%s' to become available", req.Name)
+	}
+
+	if task.Aborted() {
+		return nil, fail.AbortedError(nil, "aborted")
+	}
+
+	volumeInstance, xerr := NewVolume(svc)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	xerr = volumeInstance.(*volume).carry(restored)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	// Starting from here, remove metadata if exiting with error
+	defer func() {
+		if xerr != nil {
+			if derr := volumeInstance.(*volume).MetadataCore.Delete(); derr != nil {
+				_ = xerr.AddConsequence(fail.Wrap(derr, "cleaning up on %s, failed to delete Volume '%s' metadata", ActionFromError(xerr), req.Name))
+			}
+		}
+	}()
+
+	snapshotName := instance.GetName()
+	xerr = volumeInstance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		innerXErr := props.Alter(volumeproperty.DescriptionV1, func(clonable data.Clonable) fail.Error {
+			descriptionV1, ok := clonable.(*propertiesv1.VolumeDescription)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.VolumeDescription' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			descriptionV1.Purpose = fmt.Sprintf("restored from Snapshot '%s'", snapshotName)
+			descriptionV1.Created = time.Now()
+			return nil
+		})
+		if innerXErr != nil {
+			return innerXErr
+		}
+
+		return labelsToProperties(props, volumeproperty.LabelsV1, req.Labels)
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return volumeInstance, nil
+}
+
+// ToProtocol converts the Snapshot to protocol message VolumeSnapshotResponse
+func (instance *snapshot) ToProtocol() (_ *protocol.VolumeSnapshotResponse, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	instance.lock.RLock()
+	defer instance.lock.RUnlock()
+
+	var out *protocol.VolumeSnapshotResponse
+	xerr = instance.Inspect(func(clonable data.Clonable, _ *serialize.JSONProperties) fail.Error {
+		as, ok := clonable.(*abstract.Snapshot)
+		if !ok {
+			return fail.InconsistentError("'*abstract.Snapshot' expected, '%s' provided", reflect.TypeOf(clonable).String())
+		}
+
+		out = converters.SnapshotFromAbstractToProtocol(as)
+		return nil
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	// the volume may have been deleted since the snapshot has been taken; the snapshot remains usable
+	volumeInstance, xerr := LoadVolume(instance.GetService(), out.Volume.Id)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			debug.IgnoreError(xerr)
+		default:
+			return nil, xerr
+		}
+	} else {
+		out.Volume.Name = volumeInstance.GetName()
+		volumeInstance.Released()
+	}
+	return out, nil
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumeproperty"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumespeed"
+	propertiesv1 "github.com/CS-SI/SafeScale/lib/server/resources/properties/v1"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/data/serialize"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+func Test_snapshot_IsNull(t *testing.T) {
+	var rs *snapshot
+	//goland:noinspection GoNilness
+	require.True(t, rs.IsNull())
+	require.True(t, SnapshotNullValue().IsNull())
+}
+
+func Test_snapshot_CreateRestoreDelete(t *testing.T) {
+	svc := getMemoryService(t)
+	ctx := context.Background()
+
+	rv, xerr := NewVolume(svc)
+	require.Nil(t, xerr)
+	require.Nil(t, rv.Create(ctx, abstract.VolumeRequest{Name: "pgdata", Size: 20, Speed: volumespeed.Ssd}))
+	volumeID := rv.GetID()
+
+	rs, xerr := NewSnapshot(svc)
+	require.Nil(t, xerr)
+	require.Nil(t, rs.Create(ctx, abstract.SnapshotRequest{Name: "pgdata-nightly", VolumeID: volumeID, Description: "nightly"}))
+	rs.Released()
+
+	other, xerr := NewSnapshot(svc)
+	require.Nil(t, xerr)
+	xerr = other.Create(ctx, abstract.SnapshotRequest{Name: "pgdata-nightly", VolumeID: volumeID})
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrDuplicate{}, xerr)
+
+	rs, xerr = LoadSnapshot(svc, "pgdata-nightly")
+	require.Nil(t, xerr)
+	id, xerr := rs.GetVolumeID()
+	require.Nil(t, xerr)
+	assert.Equal(t, volumeID, id)
+	pb, xerr := rs.ToProtocol()
+	require.Nil(t, xerr)
+	assert.Equal(t, "pgdata", pb.GetVolume().GetName())
+	assert.EqualValues(t, 20, pb.GetSize())
+
+	_, xerr = rs.Restore(ctx, abstract.VolumeRequest{Name: "pgdata-small", Size: 10, Speed: volumespeed.Ssd})
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrInvalidRequest{}, xerr)
+
+	restored, xerr := rs.Restore(ctx, abstract.VolumeRequest{Name: "pgdata-restored", Speed: volumespeed.Ssd})
+	require.Nil(t, xerr)
+	size, xerr := restored.GetSize()
+	require.Nil(t, xerr)
+	assert.Equal(t, 20, size)
+	loaded, xerr := LoadVolume(svc, "pgdata-restored")
+	require.Nil(t, xerr)
+	xerr = loaded.Inspect(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Inspect(volumeproperty.DescriptionV1, func(clonable data.Clonable) fail.Error {
+			descriptionV1, ok := clonable.(*propertiesv1.VolumeDescription)
+			require.True(t, ok)
+			assert.Equal(t, "restored from Snapshot 'pgdata-nightly'", descriptionV1.Purpose)
+			assert.False(t, descriptionV1.Created.IsZero())
+			return nil
+		})
+	})
+	require.Nil(t, xerr)
+
+	require.Nil(t, rs.Delete(ctx))
+	_, xerr = LoadSnapshot(svc, "pgdata-nightly")
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrNotFound{}, xerr)
+	list, xerr := svc.ListVolumeSnapshots(volumeID)
+	require.Nil(t, xerr)
+	assert.Empty(t, list)
+}
@@ -807,6 +807,7 @@ func (instance *Subnet) unsafeCreateGateways(ctx context.Context, req abstract.S
 		KeepOnFailure:    req.KeepOnFailure,
 		SecurityGroupIDs: sgs,
 		IsGateway:        true,
+		Labels:           req.GatewayLabels,
 	}
 
 	var (
@@ -2816,3 +2817,138 @@ func (instance *Subnet) CreateSubnetWithoutGateway(ctx context.Context, req abst
 	xerr = debug.InjectPlannedFail(xerr)
 	return xerr
 }
+
+// Import registers in metadata an existing Subnet of the provider, importing its Network if needed
+// The imported Subnet has no gateway and no Security Group managed by SafeScale
+func (instance *Subnet) Import(ctx context.Context, ref string) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil {
+		return fail.InvalidInstanceError()
+	}
+	if !instance.IsNull() {
+		return fail.InvalidInstanceContentError("instance", "is not null value, cannot overwrite")
+	}
+	if ctx == nil {
+		return fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if ref = strings.TrimSpace(ref); ref == "" {
+		return fail.InvalidParameterError("ref", "cannot be empty string")
+	}
+
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotAvailable:
+			task, xerr = concurrency.VoidTask()
+			if xerr != nil {
+				return xerr
+			}
+		default:
+			return xerr
+		}
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.subnet"), "('%s')", ref).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	svc := instance.GetService()
+	if existing, xerr := LoadSubnet(svc, "", ref); xerr == nil {
+		existing.Released()
+		return fail.DuplicateError("cannot import Subnet '%s': there is already such a Subnet in metadata", ref)
+	}
+
+	abstractSubnet, xerr := svc.InspectSubnet(ref)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	// The Network of the Subnet has to be known by SafeScale
+	networkInstance, xerr := LoadNetwork(svc, abstractSubnet.Network)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotFound:
+			debug.IgnoreError(xerr)
+			networkInstance, xerr = NewNetwork(svc)
+			if xerr != nil {
+				return xerr
+			}
+
+			xerr = networkInstance.Import(ctx, abstractSubnet.Network)
+			if xerr != nil {
+				return fail.Wrap(xerr, "failed to import Network of Subnet '%s'", abstractSubnet.Name)
+			}
+		default:
+			return xerr
+		}
+	}
+	defer networkInstance.Released()
+
+	xerr = networkInstance.Inspect(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Inspect(networkproperty.SubnetsV1, func(clonable data.Clonable) fail.Error {
+			nsV1, ok := clonable.(*propertiesv1.NetworkSubnets)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.NetworkSubnets' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			if _, ok := nsV1.ByName[abstractSubnet.Name]; ok {
+				return fail.DuplicateError("cannot import Subnet '%s': there is already a Subnet named '%s' in Network '%s'", ref, abstractSubnet.Name, networkInstance.GetName())
+			}
+			return nil
+		})
+	})
+	if xerr != nil {
+		return xerr
+	}
+
+	abstractSubnet.Network = networkInstance.GetID()
+	abstractSubnet.State = subnetstate.Ready
+	xerr = instance.Carry(abstractSubnet)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	// Starting from here, remove metadata if exiting with error
+	defer func() {
+		if xerr != nil {
+			if derr := instance.MetadataCore.Delete(); derr != nil {
+				_ = xerr.AddConsequence(fail.Wrap(derr, "cleaning up on %s, failed to delete Subnet '%s' metadata", ActionFromError(xerr), abstractSubnet.Name))
+			}
+		}
+	}()
+
+	// attach Subnet to Network
+	xerr = networkInstance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Alter(networkproperty.SubnetsV1, func(clonable data.Clonable) fail.Error {
+			nsV1, ok := clonable.(*propertiesv1.NetworkSubnets)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.NetworkSubnets' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			nsV1.ByID[abstractSubnet.ID] = abstractSubnet.Name
+			nsV1.ByName[abstractSubnet.Name] = abstractSubnet.ID
+			return nil
+		})
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	logrus.Infof("Subnet '%s' imported successfully", abstractSubnet.Name)
+	return nil
+}
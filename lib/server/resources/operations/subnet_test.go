@@ -0,0 +1,95 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/hoststate"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/data/serialize"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+func Test_subnet_CreateAndDeleteWithHost(t *testing.T) {
+	svc := getMemoryService(t)
+	ctx := context.Background()
+
+	networkInstance, xerr := NewNetwork(svc)
+	require.Nil(t, xerr)
+	require.Nil(t, networkInstance.Create(ctx, abstract.NetworkRequest{Name: "net", CIDR: "192.168.0.0/16"}))
+
+	subnetInstance, xerr := NewSubnet(svc)
+	require.Nil(t, xerr)
+	req := abstract.SubnetRequest{NetworkID: networkInstance.GetID(), Name: "front", CIDR: "192.168.1.0/24"}
+	xerr = subnetInstance.Create(ctx, req, "", &abstract.HostSizingRequirements{MinGPU: -1})
+	require.Nil(t, xerr)
+
+	gw, xerr := subnetInstance.InspectGateway(true)
+	require.Nil(t, xerr)
+	assert.Equal(t, "gw-front", gw.GetName())
+	gw.Released()
+
+	var as *abstract.Subnet
+	xerr = subnetInstance.Review(func(clonable data.Clonable, _ *serialize.JSONProperties) fail.Error {
+		var ok bool
+		as, ok = clonable.(*abstract.Subnet)
+		if !ok {
+			return fail.InconsistentError("'*abstract.Subnet' expected, '%s' provided", reflect.TypeOf(clonable).String())
+		}
+		return nil
+	})
+	require.Nil(t, xerr)
+
+	hostInstance, xerr := NewHost(svc)
+	require.Nil(t, xerr)
+	hostReq := abstract.HostRequest{ResourceName: "web", HostName: "web", Subnets: []*abstract.Subnet{as}}
+	_, xerr = hostInstance.Create(ctx, hostReq, abstract.HostSizingRequirements{MinCores: 1, MinGPU: -1})
+	require.Nil(t, xerr)
+	hostInstance.Released()
+
+	loaded, xerr := LoadHost(svc, "web")
+	require.Nil(t, xerr)
+	state, xerr := loaded.ForceGetState(ctx)
+	require.Nil(t, xerr)
+	assert.Equal(t, hoststate.Started, state)
+
+	xerr = subnetInstance.Delete(ctx)
+	require.NotNil(t, xerr, "a Subnet with Hosts attached cannot be deleted")
+
+	require.Nil(t, loaded.Delete(ctx))
+	loaded.Released()
+	_, xerr = LoadHost(svc, "web")
+	assert.IsType(t, &fail.ErrNotFound{}, xerr)
+
+	subnetID := subnetInstance.GetID()
+	require.Nil(t, subnetInstance.Delete(ctx))
+	_, xerr = LoadHost(svc, "gw-front")
+	assert.IsType(t, &fail.ErrNotFound{}, xerr)
+
+	require.Nil(t, networkInstance.AbandonSubnet(ctx, subnetID))
+	require.Nil(t, networkInstance.Delete(ctx))
+	hosts, xerr := svc.ListHosts(true)
+	require.Nil(t, xerr)
+	assert.Empty(t, hosts)
+}
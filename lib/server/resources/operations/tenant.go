@@ -81,6 +81,15 @@ func SetCurrentTenant(tenantName string) error {
 	return nil
 }
 
+// LoadTenant returns the tenant named tenantName, without changing the current tenant
+func LoadTenant(tenantName string) (*Tenant, fail.Error) {
+	service, xerr := loadTenant(tenantName)
+	if xerr != nil {
+		return nil, xerr
+	}
+	return &Tenant{Name: tenantName, Service: service}, nil
+}
+
 func loadTenant(tenantName string) (iaas.Service, fail.Error) {
 	service, xerr := iaas.UseService(tenantName, MinimumMetadataVersion)
 	xerr = debug.InjectPlannedFail(xerr)
@@ -0,0 +1,877 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/CS-SI/SafeScale/lib/server/iaas"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/data/serialize"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+	"github.com/CS-SI/SafeScale/lib/utils/temporal"
+)
+
+// reconcileResource is a resource as known by metadata or provider side
+type reconcileResource struct {
+	ID    string
+	Name  string
+	Value interface{} // abstract content of the resource, used to compare both sides
+
+	Management abstract.ManagementInfo // what the tags of the resource on provider side tell about its management by SafeScale
+}
+
+// reconcileOwner identifies the tenant and the installation (by its metadata bucket) being reconciled
+type reconcileOwner struct {
+	tenant string
+	bucket string
+}
+
+// reconcileInventory lists resources of a kind, indexed by ID
+type reconcileInventory map[string]*reconcileResource
+
+// tenantReconciler carries what is shared by the reconciliation of all the kinds of resources of a tenant
+type tenantReconciler struct {
+	ctx        context.Context
+	svc        iaas.Service
+	opts       abstract.ReconcileOptions
+	cloudHosts reconcileInventory
+}
+
+// reconcileKind describes how to reconcile a kind of resource; a nil function means the feature is not available for the kind
+type reconcileKind struct {
+	name string
+	// metadata lists the resources registered in metadata
+	metadata func(r *tenantReconciler) (reconcileInventory, fail.Error)
+	// cloud lists the resources on provider side; nil if the kind has no direct counterpart on provider side
+	cloud func(r *tenantReconciler) (reconcileInventory, fail.Error)
+	// compare returns the status and the details of a resource known by metadata (and provider side if the kind has one)
+	compare func(r *tenantReconciler, meta, cloud *reconcileResource) (string, string, fail.Error)
+	// purge deletes the metadata of a resource
+	purge func(r *tenantReconciler, meta *reconcileResource) fail.Error
+	// purgeCloud deletes a resource on provider side; only proposed for resources tagged as created by SafeScale
+	purgeCloud func(r *tenantReconciler, cloud *reconcileResource) fail.Error
+	// importCloud registers in metadata a resource found on provider side
+	importCloud func(r *tenantReconciler, cloud *reconcileResource) fail.Error
+	// repair fixes the differences of a resource
+	repair func(r *tenantReconciler, meta, cloud *reconcileResource) fail.Error
+}
+
+// reconcileKinds lists the kinds of resources in the order they are reconciled: resources using others come first,
+// so purging their metadata releases what they use
+var reconcileKinds = []*reconcileKind{
+	{
+		name:     clustersFolderName,
+		metadata: listClustersMetadata,
+		compare:  compareCluster,
+		purge: func(r *tenantReconciler, meta *reconcileResource) fail.Error {
+			clusterInstance, xerr := LoadCluster(r.svc, meta.ID)
+			if xerr != nil {
+				return xerr
+			}
+			defer clusterInstance.Released()
+
+			return clusterInstance.Delete(r.ctx, true)
+		},
+		repair: repairCluster,
+	},
+	{
+		name:     sharesFolderName,
+		metadata: listSharesMetadata,
+		compare:  compareShare,
+		purge: func(r *tenantReconciler, meta *reconcileResource) fail.Error {
+			shareInstance, xerr := LoadShare(r.svc, meta.ID)
+			if xerr != nil {
+				return xerr
+			}
+			defer shareInstance.Released()
+
+			// the server of the Share is gone, only the metadata remains to be deleted
+			castedShare, ok := shareInstance.(*Share)
+			if !ok {
+				return fail.InconsistentError("'*operations.Share' expected, '%s' provided", reflect.TypeOf(shareInstance).String())
+			}
+			return castedShare.MetadataCore.Delete()
+		},
+	},
+	{
+		name:     hostsFolderName,
+		metadata: listHostsMetadata,
+		cloud:    listHostsCloud,
+		purge: func(r *tenantReconciler, meta *reconcileResource) fail.Error {
+			hostInstance, xerr := LoadHost(r.svc, meta.ID)
+			if xerr != nil {
+				return xerr
+			}
+			defer hostInstance.Released()
+
+			return hostInstance.Delete(r.ctx)
+		},
+		purgeCloud: func(r *tenantReconciler, cloud *reconcileResource) fail.Error {
+			return r.svc.DeleteHost(cloud.ID)
+		},
+		importCloud: func(r *tenantReconciler, cloud *reconcileResource) fail.Error {
+			hostInstance, xerr := NewHost(r.svc)
+			if xerr != nil {
+				return xerr
+			}
+
+			return hostInstance.Import(r.ctx, abstract.HostImportRequest{Ref: cloud.ID})
+		},
+	},
+	{
+		name:     volumesFolderName,
+		metadata: listVolumesMetadata,
+		cloud:    listVolumesCloud,
+		compare:  compareVolume,
+		purge: func(r *tenantReconciler, meta *reconcileResource) fail.Error {
+			volumeInstance, xerr := LoadVolume(r.svc, meta.ID)
+			if xerr != nil {
+				return xerr
+			}
+			defer volumeInstance.Released()
+
+			return volumeInstance.Delete(r.ctx)
+		},
+		purgeCloud: func(r *tenantReconciler, cloud *reconcileResource) fail.Error {
+			return r.svc.DeleteVolume(cloud.ID)
+		},
+		importCloud: func(r *tenantReconciler, cloud *reconcileResource) fail.Error {
+			volumeInstance, xerr := NewVolume(r.svc)
+			if xerr != nil {
+				return xerr
+			}
+
+			return volumeInstance.Import(r.ctx, cloud.ID)
+		},
+		repair: repairVolume,
+	},
+	{
+		name:     subnetsFolderName,
+		metadata: listSubnetsMetadata,
+		cloud:    listSubnetsCloud,
+		purge: func(r *tenantReconciler, meta *reconcileResource) fail.Error {
+			subnetInstance, xerr := LoadSubnet(r.svc, "", meta.ID)
+			if xerr != nil {
+				return xerr
+			}
+			defer subnetInstance.Released()
+
+			return subnetInstance.Delete(r.ctx)
+		},
+		purgeCloud: func(r *tenantReconciler, cloud *reconcileResource) fail.Error {
+			return r.svc.DeleteSubnet(cloud.ID)
+		},
+		importCloud: func(r *tenantReconciler, cloud *reconcileResource) fail.Error {
+			subnetInstance, xerr := NewSubnet(r.svc)
+			if xerr != nil {
+				return xerr
+			}
+
+			return subnetInstance.Import(r.ctx, cloud.ID)
+		},
+	},
+	{
+		name:     securityGroupsFolderName,
+		metadata: listSecurityGroupsMetadata,
+		cloud:    listSecurityGroupsCloud,
+		compare:  compareSecurityGroup,
+		purge: func(r *tenantReconciler, meta *reconcileResource) fail.Error {
+			sgInstance, xerr := LoadSecurityGroup(r.svc, meta.ID)
+			if xerr != nil {
+				return xerr
+			}
+			defer sgInstance.Released()
+
+			return sgInstance.Delete(r.ctx, true)
+		},
+		purgeCloud: func(r *tenantReconciler, cloud *reconcileResource) fail.Error {
+			asg, ok := cloud.Value.(*abstract.SecurityGroup)
+			if !ok {
+				return fail.InconsistentError("'*abstract.SecurityGroup' expected, '%s' provided", reflect.TypeOf(cloud.Value).String())
+			}
+			return r.svc.DeleteSecurityGroup(asg)
+		},
+		importCloud: func(r *tenantReconciler, cloud *reconcileResource) fail.Error {
+			asg, ok := cloud.Value.(*abstract.SecurityGroup)
+			if !ok {
+				return fail.InconsistentError("'*abstract.SecurityGroup' expected, '%s' provided", reflect.TypeOf(cloud.Value).String())
+			}
+			if asg.Network == "" {
+				return fail.InvalidRequestError("cannot import Security Group '%s': the provider does not tell its Network", cloud.Name)
+			}
+
+			sgInstance, xerr := NewSecurityGroup(r.svc)
+			if xerr != nil {
+				return xerr
+			}
+
+			return sgInstance.Import(r.ctx, asg.Network, cloud.ID)
+		},
+		repair: func(r *tenantReconciler, meta, _ *reconcileResource) fail.Error {
+			_, xerr := checkSecurityGroupConsistency(r.ctx, r.svc, meta.ID, true, false)
+			return xerr
+		},
+	},
+	{
+		name:     networksFolderName,
+		metadata: listNetworksMetadata,
+		cloud:    listNetworksCloud,
+		purge: func(r *tenantReconciler, meta *reconcileResource) fail.Error {
+			networkInstance, xerr := LoadNetwork(r.svc, meta.ID)
+			if xerr != nil {
+				return xerr
+			}
+			defer networkInstance.Released()
+
+			return networkInstance.Delete(r.ctx)
+		},
+		purgeCloud: func(r *tenantReconciler, cloud *reconcileResource) fail.Error {
+			return r.svc.DeleteNetwork(cloud.ID)
+		},
+		importCloud: func(r *tenantReconciler, cloud *reconcileResource) fail.Error {
+			networkInstance, xerr := NewNetwork(r.svc)
+			if xerr != nil {
+				return xerr
+			}
+
+			return networkInstance.Import(r.ctx, cloud.ID)
+		},
+	},
+}
+
+// ReconcileKinds returns the names of the kinds of resources that can be reconciled, in the order they are processed
+func ReconcileKinds() []string {
+	out := make([]string, 0, len(reconcileKinds))
+	for _, v := range reconcileKinds {
+		out = append(out, v.name)
+	}
+	return out
+}
+
+// ReconcileTenant compares the metadata of the tenant with the resources on provider side, and applies the actions
+// requested by 'opts' on the differences found
+// Failures met applying actions are reported in the entries; the error returned concerns the kinds that could not be compared
+func ReconcileTenant(ctx context.Context, svc iaas.Service, opts abstract.ReconcileOptions) ([]*abstract.ReconcileEntry, fail.Error) {
+	if svc == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("svc")
+	}
+	if ctx == nil {
+		return nil, fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if opts.Import && opts.PurgeCloud {
+		return nil, fail.InvalidRequestError("cannot import and purge orphans in cloud at the same time")
+	}
+
+	kinds, xerr := selectReconcileKinds(opts.Kinds)
+	if xerr != nil {
+		return nil, xerr
+	}
+	if minAge := temporal.GetLongOperationTimeout(); opts.MinAge < minAge {
+		opts.MinAge = minAge
+	}
+
+	r := &tenantReconciler{ctx: ctx, svc: svc, opts: opts}
+	var (
+		out    []*abstract.ReconcileEntry
+		errors []error
+	)
+	for _, kind := range kinds {
+		entries, xerr := r.reconcile(kind)
+		if xerr != nil {
+			logrus.Warnf("failed to reconcile %s: %v", kind.name, xerr)
+			errors = append(errors, fail.Wrap(xerr, "failed to reconcile %s", kind.name))
+			continue
+		}
+		out = append(out, entries...)
+	}
+	if len(errors) > 0 {
+		return out, fail.NewErrorList(errors)
+	}
+	return out, nil
+}
+
+// selectReconcileKinds returns the kinds of resources corresponding to 'names', all of them if 'names' is empty
+func selectReconcileKinds(names []string) ([]*reconcileKind, fail.Error) {
+	if len(names) == 0 {
+		return reconcileKinds, nil
+	}
+
+	wanted := make(map[string]bool, len(names))
+	for _, v := range names {
+		name := strings.ToLower(strings.TrimSpace(v))
+		found := false
+		for _, kind := range reconcileKinds {
+			if kind.name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fail.InvalidParameterError("kinds", fmt.Sprintf("unknown kind '%s' (expecting one of %s)", v, strings.Join(ReconcileKinds(), ", ")))
+		}
+		wanted[name] = true
+	}
+
+	var out []*reconcileKind
+	for _, kind := range reconcileKinds {
+		if wanted[kind.name] {
+			out = append(out, kind)
+		}
+	}
+	return out, nil
+}
+
+// reconcile compares and reconciles a kind of resource
+func (r *tenantReconciler) reconcile(kind *reconcileKind) ([]*abstract.ReconcileEntry, fail.Error) {
+	meta, xerr := kind.metadata(r)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	var cloud reconcileInventory
+	if kind.cloud != nil {
+		cloud, xerr = kind.cloud(r)
+		if xerr != nil {
+			return nil, xerr
+		}
+	}
+
+	owner := reconcileOwner{tenant: r.svc.GetName(), bucket: r.svc.GetMetadataBucket().GetName()}
+	entries, common := classifyReconcileResources(kind.name, meta, cloud, owner, r.opts.MinAge)
+	if kind.compare != nil {
+		for _, id := range common {
+			var cloudResource *reconcileResource
+			if cloud != nil {
+				cloudResource = cloud[id]
+			}
+			status, details, xerr := kind.compare(r, meta[id], cloudResource)
+			if xerr != nil {
+				return nil, fail.Wrap(xerr, "failed to compare %s '%s'", kind.name, id)
+			}
+			if status != "" {
+				entries = append(entries, &abstract.ReconcileEntry{Kind: kind.name, ID: id, Name: meta[id].Name, Status: status, Details: details})
+			}
+		}
+	}
+
+	for _, entry := range entries {
+		r.apply(kind, entry, meta[entry.ID], cloud[entry.ID])
+	}
+	return entries, nil
+}
+
+// apply decides the action to take on 'entry' and applies it if not in dry run
+func (r *tenantReconciler) apply(kind *reconcileKind, entry *abstract.ReconcileEntry, meta, cloud *reconcileResource) {
+	if !r.selected(entry) {
+		return
+	}
+
+	var action func() fail.Error
+	switch entry.Status {
+	case abstract.ReconcileUnmanaged:
+		// not created by SafeScale: never deleted, only imported on request
+		if r.opts.Import {
+			entry.Action = abstract.ReconcileImport
+			if kind.importCloud != nil {
+				action = func() fail.Error { return kind.importCloud(r, cloud) }
+			}
+		}
+	case abstract.ReconcileForeign, abstract.ReconcileRecent:
+		// owned by another tenant or installation, or maybe by an operation in progress: left alone
+	case abstract.ReconcileOrphanInCloud:
+		switch {
+		case r.opts.Import:
+			entry.Action = abstract.ReconcileImport
+			if kind.importCloud != nil {
+				action = func() fail.Error { return kind.importCloud(r, cloud) }
+			}
+		case r.opts.PurgeCloud:
+			entry.Action = abstract.ReconcilePurgeCloud
+			if kind.purgeCloud != nil {
+				action = func() fail.Error { return kind.purgeCloud(r, cloud) }
+			}
+		}
+	case abstract.ReconcileOrphanInMetadata:
+		if r.opts.Purge {
+			entry.Action = abstract.ReconcilePurge
+			if kind.purge != nil {
+				action = func() fail.Error { return kind.purge(r, meta) }
+			}
+		}
+	case abstract.ReconcileMismatched:
+		if r.opts.Repair {
+			entry.Action = abstract.ReconcileRepair
+			if kind.repair != nil {
+				action = func() fail.Error { return kind.repair(r, meta, cloud) }
+			}
+		}
+	}
+	if entry.Action == "" || r.opts.DryRun {
+		return
+	}
+	if action == nil {
+		entry.Error = fmt.Sprintf("%s of %s not available", entry.Action, kind.name)
+		return
+	}
+
+	if xerr := action(); xerr != nil {
+		logrus.Warnf("failed to %s %s '%s': %v", entry.Action, kind.name, entry.ID, xerr)
+		entry.Error = xerr.Error()
+		return
+	}
+	entry.Applied = true
+}
+
+// selected tells if the actions are allowed on the resource of 'entry' by ReconcileOptions.Only
+func (r *tenantReconciler) selected(entry *abstract.ReconcileEntry) bool {
+	if len(r.opts.Only) == 0 {
+		return true
+	}
+	ref := entry.Ref()
+	for _, v := range r.opts.Only {
+		if v == ref {
+			return true
+		}
+	}
+	return false
+}
+
+// classifyReconcileResources returns the entries of the resources found on one side only, and the IDs of the resources
+// registered in metadata that remain to be compared (all of them if 'cloud' is nil, the kind having no counterpart on provider side)
+// A resource found on provider side only is an orphan if tagged as created by SafeScale for 'owner' for more than 'minAge'
+func classifyReconcileResources(kind string, meta, cloud reconcileInventory, owner reconcileOwner, minAge time.Duration) ([]*abstract.ReconcileEntry, []string) {
+	var (
+		entries []*abstract.ReconcileEntry
+		common  []string
+	)
+	for _, id := range meta.sortedIDs() {
+		if cloud == nil {
+			common = append(common, id)
+			continue
+		}
+		if _, ok := cloud[id]; ok {
+			common = append(common, id)
+			continue
+		}
+		entries = append(entries, &abstract.ReconcileEntry{
+			Kind:    kind,
+			ID:      id,
+			Name:    meta[id].Name,
+			Status:  abstract.ReconcileOrphanInMetadata,
+			Details: "not found on provider side",
+		})
+	}
+	now := time.Now()
+	for _, id := range cloud.sortedIDs() {
+		if _, ok := meta[id]; ok {
+			continue
+		}
+
+		entry := &abstract.ReconcileEntry{Kind: kind, ID: id, Name: cloud[id].Name}
+		mi := cloud[id].Management
+		switch {
+		case !mi.Managed:
+			entry.Status = abstract.ReconcileUnmanaged
+			entry.Details = "not registered in metadata, not tagged as created by SafeScale"
+		case !mi.OwnedBy(owner.tenant, owner.bucket):
+			entry.Status = abstract.ReconcileForeign
+			if mi.Tenant == "" {
+				entry.Details = "not registered in metadata, created by SafeScale for an unknown tenant"
+			} else {
+				entry.Details = fmt.Sprintf("not registered in metadata, created by SafeScale for tenant '%s' with metadata bucket '%s'", mi.Tenant, mi.MetadataBucket)
+			}
+		case !mi.Created.IsZero() && now.Sub(mi.Created) < minAge:
+			entry.Status = abstract.ReconcileRecent
+			entry.Details = fmt.Sprintf("not registered in metadata, created %s ago: may belong to an operation in progress", now.Sub(mi.Created).Round(time.Second))
+		default:
+			entry.Status = abstract.ReconcileOrphanInCloud
+			entry.Details = "not registered in metadata"
+		}
+		entries = append(entries, entry)
+	}
+	return entries, common
+}
+
+// sortedIDs returns the IDs of the inventory, sorted to get reproducible reports
+func (inv reconcileInventory) sortedIDs() []string {
+	out := make([]string, 0, len(inv))
+	for k := range inv {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// add registers a resource in the inventory
+func (inv reconcileInventory) add(id, name string, value interface{}) {
+	inv[id] = &reconcileResource{ID: id, Name: name, Value: value}
+}
+
+// addTagged registers a resource of provider side in the inventory, with what its tags tell about its management by SafeScale
+func (inv reconcileInventory) addTagged(id, name string, value interface{}, tags map[string]string) {
+	inv[id] = &reconcileResource{ID: id, Name: name, Value: value, Management: abstract.ParseManagementTags(tags)}
+}
+
+// hosts returns the inventory of the hosts on provider side, listed once for all the kinds needing it
+func (r *tenantReconciler) hosts() (reconcileInventory, fail.Error) {
+	return listHostsCloud(r)
+}
+
+func listHostsMetadata(r *tenantReconciler) (reconcileInventory, fail.Error) {
+	browser, xerr := NewHost(r.svc)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	inv := reconcileInventory{}
+	xerr = browser.Browse(r.ctx, func(ahc *abstract.HostCore) fail.Error {
+		inv.add(ahc.ID, ahc.Name, ahc)
+		return nil
+	})
+	return inv, xerr
+}
+
+// listHostsCloud lists the hosts on provider side; the list is kept to be reused by the other kinds
+func listHostsCloud(r *tenantReconciler) (reconcileInventory, fail.Error) {
+	if r.cloudHosts != nil {
+		return r.cloudHosts, nil
+	}
+
+	list, xerr := r.svc.ListHosts(false)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	inv := reconcileInventory{}
+	for _, v := range list {
+		if v != nil && v.Core != nil {
+			inv.addTagged(v.Core.ID, v.Core.Name, v, v.Core.Tags)
+		}
+	}
+	r.cloudHosts = inv
+	return inv, nil
+}
+
+func listNetworksMetadata(r *tenantReconciler) (reconcileInventory, fail.Error) {
+	browser, xerr := NewNetwork(r.svc)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	inv := reconcileInventory{}
+	xerr = browser.Browse(r.ctx, func(an *abstract.Network) fail.Error {
+		inv.add(an.ID, an.Name, an)
+		return nil
+	})
+	return inv, xerr
+}
+
+func listNetworksCloud(r *tenantReconciler) (reconcileInventory, fail.Error) {
+	list, xerr := r.svc.ListNetworks()
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	inv := reconcileInventory{}
+	for _, v := range list {
+		inv.addTagged(v.ID, v.Name, v, v.Tags)
+	}
+	return inv, nil
+}
+
+func listSubnetsMetadata(r *tenantReconciler) (reconcileInventory, fail.Error) {
+	browser, xerr := NewSubnet(r.svc)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	inv := reconcileInventory{}
+	xerr = browser.Browse(r.ctx, func(as *abstract.Subnet) fail.Error {
+		inv.add(as.ID, as.Name, as)
+		return nil
+	})
+	return inv, xerr
+}
+
+// listSubnetsCloud lists the Subnets on provider side; the stacks do not report tags of Subnets, so none is considered as
+// created by SafeScale and the orphans can only be imported
+func listSubnetsCloud(r *tenantReconciler) (reconcileInventory, fail.Error) {
+	list, xerr := r.svc.ListSubnets("")
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	inv := reconcileInventory{}
+	for _, v := range list {
+		inv.add(v.ID, v.Name, v)
+	}
+	return inv, nil
+}
+
+func listVolumesMetadata(r *tenantReconciler) (reconcileInventory, fail.Error) {
+	browser, xerr := NewVolume(r.svc)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	inv := reconcileInventory{}
+	xerr = browser.Browse(r.ctx, func(av *abstract.Volume) fail.Error {
+		inv.add(av.ID, av.Name, av)
+		return nil
+	})
+	return inv, xerr
+}
+
+func listVolumesCloud(r *tenantReconciler) (reconcileInventory, fail.Error) {
+	list, xerr := r.svc.ListVolumes()
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	inv := reconcileInventory{}
+	for k := range list {
+		inv.addTagged(list[k].ID, list[k].Name, &list[k], list[k].Tags)
+	}
+	return inv, nil
+}
+
+func listSecurityGroupsMetadata(r *tenantReconciler) (reconcileInventory, fail.Error) {
+	browser, xerr := NewSecurityGroup(r.svc)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	inv := reconcileInventory{}
+	xerr = browser.Browse(r.ctx, func(asg *abstract.SecurityGroup) fail.Error {
+		inv.add(asg.ID, asg.Name, asg)
+		return nil
+	})
+	return inv, xerr
+}
+
+// listSecurityGroupsCloud lists the Security Groups on provider side; as for Subnets, the orphans can only be imported
+func listSecurityGroupsCloud(r *tenantReconciler) (reconcileInventory, fail.Error) {
+	list, xerr := r.svc.ListSecurityGroups("")
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	inv := reconcileInventory{}
+	for _, v := range list {
+		inv.add(v.ID, v.Name, v)
+	}
+	return inv, nil
+}
+
+// listSharesMetadata lists the Shares registered in metadata; the Share Browse only gives names, so the folder is walked directly
+func listSharesMetadata(r *tenantReconciler) (reconcileInventory, fail.Error) {
+	browser, xerr := NewShare(r.svc)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	inv := reconcileInventory{}
+	xerr = browser.BrowseFolder(func(buf []byte) fail.Error {
+		si := &ShareIdentity{}
+		if xerr := si.Deserialize(buf); xerr != nil {
+			return xerr
+		}
+		inv.add(si.ShareID, si.ShareName, si)
+		return nil
+	})
+	return inv, xerr
+}
+
+// listClustersMetadata lists the Clusters registered in metadata; a Cluster is identified by its name
+func listClustersMetadata(r *tenantReconciler) (reconcileInventory, fail.Error) {
+	browser, xerr := NewCluster(r.svc)
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	inv := reconcileInventory{}
+	xerr = browser.Browse(r.ctx, func(aci *abstract.ClusterIdentity) fail.Error {
+		inv.add(aci.Name, aci.Name, aci)
+		return nil
+	})
+	return inv, xerr
+}
+
+// compareVolume tells if the size of the volume differs between metadata and provider side
+func compareVolume(_ *tenantReconciler, meta, cloud *reconcileResource) (string, string, fail.Error) {
+	metaVolume, ok := meta.Value.(*abstract.Volume)
+	if !ok {
+		return "", "", fail.InconsistentError("'*abstract.Volume' expected, '%s' provided", reflect.TypeOf(meta.Value).String())
+	}
+	cloudVolume, ok := cloud.Value.(*abstract.Volume)
+	if !ok {
+		return "", "", fail.InconsistentError("'*abstract.Volume' expected, '%s' provided", reflect.TypeOf(cloud.Value).String())
+	}
+
+	if metaVolume.Size != cloudVolume.Size {
+		return abstract.ReconcileMismatched, fmt.Sprintf("size is %d GB in metadata, %d GB on provider side", metaVolume.Size, cloudVolume.Size), nil
+	}
+	return "", "", nil
+}
+
+// repairVolume updates the size of the volume in metadata with the one on provider side
+func repairVolume(r *tenantReconciler, meta, cloud *reconcileResource) fail.Error {
+	cloudVolume, ok := cloud.Value.(*abstract.Volume)
+	if !ok {
+		return fail.InconsistentError("'*abstract.Volume' expected, '%s' provided", reflect.TypeOf(cloud.Value).String())
+	}
+
+	volumeInstance, xerr := LoadVolume(r.svc, meta.ID)
+	if xerr != nil {
+		return xerr
+	}
+	defer volumeInstance.Released()
+
+	return volumeInstance.Alter(func(clonable data.Clonable, _ *serialize.JSONProperties) fail.Error {
+		av, ok := clonable.(*abstract.Volume)
+		if !ok {
+			return fail.InconsistentError("'*abstract.Volume' expected, '%s' provided", reflect.TypeOf(clonable).String())
+		}
+
+		if av.Size == cloudVolume.Size {
+			return fail.AlteredNothingError()
+		}
+		av.Size = cloudVolume.Size
+		return nil
+	})
+}
+
+// compareSecurityGroup tells if the rules of the Security Group drifted on provider side
+func compareSecurityGroup(r *tenantReconciler, meta, _ *reconcileResource) (string, string, fail.Error) {
+	drift, xerr := checkSecurityGroupConsistency(r.ctx, r.svc, meta.ID, false, false)
+	if xerr != nil {
+		return "", "", xerr
+	}
+
+	if drift.IsEmpty() {
+		return "", "", nil
+	}
+	return abstract.ReconcileMismatched, fmt.Sprintf("%d rule(s) added, %d removed and %d changed on provider side", len(drift.Added), len(drift.Removed), len(drift.Changed)), nil
+}
+
+// compareShare tells if the host serving the Share still exists on provider side
+func compareShare(r *tenantReconciler, meta, _ *reconcileResource) (string, string, fail.Error) {
+	si, ok := meta.Value.(*ShareIdentity)
+	if !ok {
+		return "", "", fail.InconsistentError("'*operations.ShareIdentity' expected, '%s' provided", reflect.TypeOf(meta.Value).String())
+	}
+
+	hosts, xerr := r.hosts()
+	if xerr != nil {
+		return "", "", xerr
+	}
+
+	if _, ok := hosts[si.HostID]; !ok {
+		return abstract.ReconcileOrphanInMetadata, fmt.Sprintf("server host '%s' not found on provider side", si.HostName), nil
+	}
+	return "", "", nil
+}
+
+// missingClusterMembers returns the IDs of the masters and nodes of the Cluster not found on provider side
+func (r *tenantReconciler) missingClusterMembers(name string) (masters, nodes []string, total int, xerr fail.Error) {
+	hosts, xerr := r.hosts()
+	if xerr != nil {
+		return nil, nil, 0, xerr
+	}
+
+	clusterInstance, xerr := LoadCluster(r.svc, name)
+	if xerr != nil {
+		return nil, nil, 0, xerr
+	}
+	defer clusterInstance.Released()
+
+	masterIDs, xerr := clusterInstance.ListMasterIDs(r.ctx)
+	if xerr != nil {
+		return nil, nil, 0, xerr
+	}
+	nodeIDs, xerr := clusterInstance.ListNodeIDs(r.ctx)
+	if xerr != nil {
+		return nil, nil, 0, xerr
+	}
+
+	for _, id := range masterIDs.Values() {
+		if _, ok := hosts[id]; !ok {
+			masters = append(masters, id)
+		}
+	}
+	for _, id := range nodeIDs.Values() {
+		if _, ok := hosts[id]; !ok {
+			nodes = append(nodes, id)
+		}
+	}
+	return masters, nodes, len(masterIDs) + len(nodeIDs), nil
+}
+
+// compareCluster tells if the hosts of the Cluster still exist on provider side
+func compareCluster(r *tenantReconciler, meta, _ *reconcileResource) (string, string, fail.Error) {
+	masters, nodes, total, xerr := r.missingClusterMembers(meta.ID)
+	if xerr != nil {
+		return "", "", xerr
+	}
+
+	missing := len(masters) + len(nodes)
+	switch {
+	case missing == 0:
+		return "", "", nil
+	case missing == total:
+		return abstract.ReconcileOrphanInMetadata, "no host of the cluster found on provider side", nil
+	default:
+		return abstract.ReconcileMismatched, fmt.Sprintf("%d master(s) and %d node(s) not found on provider side", len(masters), len(nodes)), nil
+	}
+}
+
+// repairCluster removes from the Cluster the nodes not found on provider side; missing masters cannot be repaired this way
+func repairCluster(r *tenantReconciler, meta, _ *reconcileResource) fail.Error {
+	masters, nodes, _, xerr := r.missingClusterMembers(meta.ID)
+	if xerr != nil {
+		return xerr
+	}
+
+	clusterInstance, xerr := LoadCluster(r.svc, meta.ID)
+	if xerr != nil {
+		return xerr
+	}
+	defer clusterInstance.Released()
+
+	var errors []error
+	for _, id := range nodes {
+		if xerr := clusterInstance.DeleteSpecificNode(r.ctx, id, ""); xerr != nil {
+			errors = append(errors, fail.Wrap(xerr, "failed to remove node '%s'", id))
+		}
+	}
+	if len(masters) > 0 {
+		errors = append(errors, fail.InvalidRequestError("cannot repair missing masters %s, the cluster has to be restored", strings.Join(masters, ", ")))
+	}
+	if len(errors) > 0 {
+		return fail.NewErrorList(errors)
+	}
+	return nil
+}
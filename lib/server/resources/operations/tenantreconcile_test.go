@@ -0,0 +1,182 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+func Test_classifyReconcileResources(t *testing.T) {
+	meta := reconcileInventory{}
+	meta.add("h2", "host-2", nil)
+	meta.add("h1", "host-1", nil)
+	meta.add("h3", "host-3", nil)
+	cloud := reconcileInventory{}
+	cloud.add("h1", "host-1", nil)
+	cloud.add("h4", "manual", nil)
+	owner := reconcileOwner{tenant: "TestOperations", bucket: "0.safescale-test"}
+	cloud.addTagged("h5", "host-5", nil, abstract.ManagementTags(nil, owner.tenant, owner.bucket))
+	oldTags := map[string]string{
+		abstract.ManagedByTag:      abstract.ManagedByValue,
+		abstract.CreationDateTag:   strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10),
+		abstract.TenantTag:         owner.tenant,
+		abstract.MetadataBucketTag: owner.bucket,
+	}
+	cloud.addTagged("h6", "host-6", nil, oldTags)
+	otherTenant := map[string]string{}
+	otherInstallation := map[string]string{}
+	for k, v := range oldTags {
+		otherTenant[k] = v
+		otherInstallation[k] = v
+	}
+	otherTenant[abstract.TenantTag] = "other"
+	otherInstallation[abstract.MetadataBucketTag] = "0.safescale-other"
+	cloud.addTagged("h7", "host-7", nil, otherTenant)
+	cloud.addTagged("h8", "host-8", nil, otherInstallation)
+	// created by SafeScale before the owner was tagged
+	cloud.addTagged("h9", "host-9", nil, map[string]string{abstract.ManagedByTag: abstract.ManagedByValue})
+
+	entries, common := classifyReconcileResources(hostsFolderName, meta, cloud, owner, 15*time.Minute)
+	assert.Equal(t, []string{"h1"}, common)
+	require.Len(t, entries, 8)
+	assert.Equal(t, "h2", entries[0].ID)
+	assert.Equal(t, abstract.ReconcileOrphanInMetadata, entries[0].Status)
+	assert.Equal(t, "h3", entries[1].ID)
+	assert.Equal(t, abstract.ReconcileOrphanInMetadata, entries[1].Status)
+	assert.Equal(t, "h4", entries[2].ID)
+	assert.Equal(t, "manual", entries[2].Name)
+	assert.Equal(t, hostsFolderName, entries[2].Kind)
+	// not tagged by SafeScale
+	assert.Equal(t, abstract.ReconcileUnmanaged, entries[2].Status)
+	// tagged, but created too recently to tell if an operation in progress owns it
+	assert.Equal(t, "h5", entries[3].ID)
+	assert.Equal(t, abstract.ReconcileRecent, entries[3].Status)
+	assert.Equal(t, "h6", entries[4].ID)
+	assert.Equal(t, abstract.ReconcileOrphanInCloud, entries[4].Status)
+	// created by SafeScale for another tenant, another installation or an unknown owner
+	for i, id := range []string{"h7", "h8", "h9"} {
+		assert.Equal(t, id, entries[5+i].ID)
+		assert.Equal(t, abstract.ReconcileForeign, entries[5+i].Status)
+	}
+	assert.Contains(t, entries[5].Details, "tenant 'other'")
+
+	// kinds without counterpart on provider side are only compared
+	entries, common = classifyReconcileResources(clustersFolderName, meta, nil, owner, 0)
+	assert.Empty(t, entries)
+	assert.Equal(t, []string{"h1", "h2", "h3"}, common)
+}
+
+func Test_selectReconcileKinds(t *testing.T) {
+	kinds, xerr := selectReconcileKinds(nil)
+	require.Nil(t, xerr)
+	assert.Len(t, kinds, len(reconcileKinds))
+
+	kinds, xerr = selectReconcileKinds([]string{"networks", " Hosts "})
+	require.Nil(t, xerr)
+	require.Len(t, kinds, 2)
+	assert.Equal(t, hostsFolderName, kinds[0].name)
+	assert.Equal(t, networksFolderName, kinds[1].name)
+
+	_, xerr = selectReconcileKinds([]string{"buckets"})
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrInvalidParameter{}, xerr)
+}
+
+func Test_tenantReconciler_apply(t *testing.T) {
+	var purged []string
+	kind := &reconcileKind{
+		name: volumesFolderName,
+		purge: func(_ *tenantReconciler, meta *reconcileResource) fail.Error {
+			purged = append(purged, meta.ID)
+			return nil
+		},
+		repair: func(_ *tenantReconciler, _, _ *reconcileResource) fail.Error {
+			return fail.NewError("provider refused")
+		},
+	}
+	resource := &reconcileResource{ID: "v1"}
+
+	// no action requested: report only
+	r := &tenantReconciler{}
+	entry := &abstract.ReconcileEntry{ID: "v1", Status: abstract.ReconcileOrphanInMetadata}
+	r.apply(kind, entry, resource, nil)
+	assert.Empty(t, entry.Action)
+	assert.False(t, entry.Applied)
+
+	// dry run: action reported but not applied
+	r.opts = abstract.ReconcileOptions{Purge: true, DryRun: true}
+	r.apply(kind, entry, resource, nil)
+	assert.Equal(t, abstract.ReconcilePurge, entry.Action)
+	assert.False(t, entry.Applied)
+	assert.Empty(t, purged)
+
+	r.opts.DryRun = false
+	r.apply(kind, entry, resource, nil)
+	assert.True(t, entry.Applied)
+	assert.Equal(t, []string{"v1"}, purged)
+
+	r.opts = abstract.ReconcileOptions{Repair: true}
+	entry = &abstract.ReconcileEntry{ID: "v1", Status: abstract.ReconcileMismatched}
+	r.apply(kind, entry, resource, resource)
+	assert.Equal(t, abstract.ReconcileRepair, entry.Action)
+	assert.False(t, entry.Applied)
+	assert.Contains(t, entry.Error, "provider refused")
+
+	r.opts = abstract.ReconcileOptions{Import: true}
+	entry = &abstract.ReconcileEntry{ID: "v2", Status: abstract.ReconcileOrphanInCloud}
+	r.apply(kind, entry, nil, resource)
+	assert.Equal(t, abstract.ReconcileImport, entry.Action)
+	assert.Equal(t, "import of volumes not available", entry.Error)
+
+	// resources not created by SafeScale for the tenant or too recent are never deleted
+	var deleted []string
+	kind.purgeCloud = func(_ *tenantReconciler, cloud *reconcileResource) fail.Error {
+		deleted = append(deleted, cloud.ID)
+		return nil
+	}
+	r.opts = abstract.ReconcileOptions{PurgeCloud: true}
+	for _, status := range []string{abstract.ReconcileUnmanaged, abstract.ReconcileRecent, abstract.ReconcileForeign} {
+		entry = &abstract.ReconcileEntry{ID: "v3", Status: status}
+		r.apply(kind, entry, nil, resource)
+		assert.Empty(t, entry.Action)
+	}
+	assert.Empty(t, deleted)
+
+	// resources of another tenant or installation are not imported either
+	r.opts = abstract.ReconcileOptions{Import: true}
+	entry = &abstract.ReconcileEntry{ID: "v3", Status: abstract.ReconcileForeign}
+	r.apply(kind, entry, nil, resource)
+	assert.Empty(t, entry.Action)
+
+	// actions restricted to the resources previously confirmed
+	r.opts = abstract.ReconcileOptions{PurgeCloud: true, Only: []string{volumesFolderName + "/v4"}}
+	entry = &abstract.ReconcileEntry{Kind: volumesFolderName, ID: "v3", Status: abstract.ReconcileOrphanInCloud}
+	r.apply(kind, entry, nil, resource)
+	assert.Empty(t, entry.Action)
+	entry = &abstract.ReconcileEntry{Kind: volumesFolderName, ID: "v4", Status: abstract.ReconcileOrphanInCloud}
+	r.apply(kind, entry, nil, &reconcileResource{ID: "v4"})
+	assert.True(t, entry.Applied)
+	assert.Equal(t, []string{"v4"}, deleted)
+}
@@ -434,6 +434,9 @@ func (instance *volume) Create(ctx context.Context, req abstract.VolumeRequest)
 	if req.Size <= 0 {
 		return fail.InvalidParameterError("req.Size", "must be an integer > 0")
 	}
+	if xerr := abstract.ValidateLabels(req.Labels); xerr != nil {
+		return xerr
+	}
 
 	task, xerr := concurrency.TaskFromContext(ctx)
 	xerr = debug.InjectPlannedFail(xerr)
@@ -517,8 +520,388 @@ func (instance *volume) Create(ctx context.Context, req abstract.VolumeRequest)
 		return fail.AbortedError(nil, "aborted")
 	}
 
+	xerr = instance.carry(av)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	// Starting from here, remove metadata if exiting with error
+	defer func() {
+		if xerr != nil {
+			if derr := instance.MetadataCore.Delete(); derr != nil {
+				_ = xerr.AddConsequence(fail.Wrap(derr, "cleaning up on %s, failed to delete Volume '%s' metadata", ActionFromError(xerr), req.Name))
+			}
+		}
+	}()
+
 	// Sets err to possibly trigger defer calls
-	return instance.carry(av)
+	return instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return labelsToProperties(props, volumeproperty.LabelsV1, req.Labels)
+	})
+}
+
+// Import registers in metadata an existing Volume of the provider, with its attachments to Hosts registered in metadata
+func (instance *volume) Import(ctx context.Context, ref string) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil {
+		return fail.InvalidInstanceError()
+	}
+	if !instance.IsNull() {
+		return fail.InvalidInstanceContentError("instance", "is not null value, cannot overwrite")
+	}
+	if ctx == nil {
+		return fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if ref = strings.TrimSpace(ref); ref == "" {
+		return fail.InvalidParameterError("ref", "cannot be empty string")
+	}
+
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotAvailable:
+			task, xerr = concurrency.VoidTask()
+			if xerr != nil {
+				return xerr
+			}
+		default:
+			return xerr
+		}
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.volume"), "('%s')", ref).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	svc := instance.GetService()
+	if existing, xerr := LoadVolume(svc, ref); xerr == nil {
+		existing.Released()
+		return fail.DuplicateError("cannot import Volume '%s': there is already such a Volume in metadata", ref)
+	}
+
+	av, xerr := svc.InspectVolume(ref)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+	if av.Name == "" {
+		return fail.InconsistentError("cannot import Volume '%s': the provider does not give it a name", ref)
+	}
+	if existing, xerr := LoadVolume(svc, av.Name); xerr == nil {
+		existing.Released()
+		return fail.DuplicateError("cannot import Volume '%s': there is already a Volume named '%s' in metadata", ref, av.Name)
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	xerr = instance.carry(av)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return xerr
+	}
+
+	// Starting from here, remove metadata if exiting with error
+	defer func() {
+		if xerr != nil {
+			if derr := instance.MetadataCore.Delete(); derr != nil {
+				_ = xerr.AddConsequence(fail.Wrap(derr, "cleaning up on %s, failed to delete Volume '%s' metadata", ActionFromError(xerr), av.Name))
+			}
+		}
+	}()
+
+	// -- registers the attachments of the Volume to the Hosts known by SafeScale --
+	browser, xerr := NewHost(svc)
+	if xerr != nil {
+		return xerr
+	}
+	var hosts []*abstract.HostCore
+	xerr = browser.Browse(ctx, func(ahc *abstract.HostCore) fail.Error {
+		hosts = append(hosts, ahc)
+		return nil
+	})
+	if xerr != nil {
+		return xerr
+	}
+
+	for _, ahc := range hosts {
+		attachments, xerr := svc.ListVolumeAttachments(ahc.ID)
+		if xerr != nil {
+			switch xerr.(type) {
+			case *fail.ErrNotFound:
+				debug.IgnoreError(xerr)
+				continue
+			default:
+				return fail.Wrap(xerr, "failed to list the Volume attachments of Host '%s'", ahc.Name)
+			}
+		}
+
+		for _, va := range attachments {
+			if va.VolumeID != av.ID {
+				continue
+			}
+
+			xerr = instance.importAttachment(ctx, av, ahc, va)
+			if xerr != nil {
+				return xerr
+			}
+		}
+	}
+
+	logrus.Infof("Volume '%s' imported successfully", av.Name)
+	return nil
+}
+
+// importAttachment registers in metadata the attachment of the imported Volume to a Host
+// The mount is found on the Host when SSH is available; otherwise only the device given by the provider is known
+func (instance *volume) importAttachment(ctx context.Context, av *abstract.Volume, ahc *abstract.HostCore, va abstract.VolumeAttachment) fail.Error {
+	hostInstance, xerr := LoadHost(instance.GetService(), ahc.ID)
+	if xerr != nil {
+		return xerr
+	}
+	defer hostInstance.Released()
+
+	device, mountPoint, fileSystem := va.Device, "", ""
+	if ahc.PrivateKey != "" && va.Device != "" {
+		cmd := fmt.Sprintf("sudo lsblk -rno UUID,MOUNTPOINT,FSTYPE %s", va.Device)
+		retcode, stdout, _, xerr := hostInstance.Run(ctx, cmd, outputs.COLLECT, temporal.GetConnectSSHTimeout(), temporal.GetExecutionTimeout())
+		if xerr == nil && retcode == 0 {
+			if fields := strings.Fields(stdout); len(fields) == 3 {
+				device, mountPoint, fileSystem = fields[0], fields[1], fields[2]
+			}
+		} else {
+			logrus.Warnf("failed to find the mount of Volume '%s' on Host '%s'", av.Name, ahc.Name)
+		}
+	}
+
+	xerr = hostInstance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		innerXErr := props.Alter(hostproperty.VolumesV1, func(clonable data.Clonable) fail.Error {
+			hostVolumesV1, ok := clonable.(*propertiesv1.HostVolumes)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.HostVolumes' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			hostVolumesV1.VolumesByID[av.ID] = &propertiesv1.HostVolume{
+				AttachID: va.ID,
+				Device:   device,
+			}
+			hostVolumesV1.VolumesByName[av.Name] = av.ID
+			hostVolumesV1.VolumesByDevice[device] = av.ID
+			hostVolumesV1.DevicesByID[av.ID] = device
+			return nil
+		})
+		if innerXErr != nil || mountPoint == "" {
+			return innerXErr
+		}
+
+		return props.Alter(hostproperty.MountsV1, func(clonable data.Clonable) fail.Error {
+			hostMountsV1, ok := clonable.(*propertiesv1.HostMounts)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.HostMounts' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			hostMountsV1.LocalMountsByPath[mountPoint] = &propertiesv1.HostLocalMount{
+				Device:     device,
+				Path:       mountPoint,
+				FileSystem: fileSystem,
+			}
+			hostMountsV1.LocalMountsByDevice[device] = mountPoint
+			return nil
+		})
+	})
+	if xerr != nil {
+		return xerr
+	}
+
+	return instance.Alter(func(_ data.Clonable, props *serialize.JSONProperties) fail.Error {
+		return props.Alter(volumeproperty.AttachedV1, func(clonable data.Clonable) fail.Error {
+			volumeAttachedV1, ok := clonable.(*propertiesv1.VolumeAttachments)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.VolumeAttachments' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			volumeAttachedV1.Hosts[ahc.ID] = ahc.Name
+			return nil
+		})
+	})
+}
+
+// growFilesystemCommand returns the command growing the file system mounted on mountPoint to the size of its device
+// The device is rescanned first when the kernel does not notice the new size by itself (SCSI devices). mountPoint is
+// passed to the script as argument, quoted, so that its content is never interpreted by the shell.
+func growFilesystemCommand(mountPoint string) string {
+	return `sudo bash -c 'dev=$(findmnt -no SOURCE "$1") && fs=$(findmnt -no FSTYPE "$1") || exit 1
+rescan=/sys/class/block/$(basename $(readlink -f $dev))/device/rescan
+[ -w $rescan ] && echo 1 >$rescan
+case $fs in
+ext2|ext3|ext4) resize2fs $dev ;;
+xfs) xfs_growfs "$1" ;;
+*) echo "cannot grow file system of type $fs" >&2; exit 1 ;;
+esac' grow-filesystem ` + shellQuote(mountPoint)
+}
+
+// shellQuote quotes s to be passed as a single word to the shell, whatever its content
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// Resize grows the Volume to newSize GB; the file systems of the Volume mounted on Hosts are grown accordingly
+func (instance *volume) Resize(ctx context.Context, newSize int) (xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return fail.InvalidInstanceError()
+	}
+	if ctx == nil {
+		return fail.InvalidParameterCannotBeNilError("ctx")
+	}
+	if newSize <= 0 {
+		return fail.InvalidParameterError("newSize", "must be greater than 0")
+	}
+
+	task, xerr := concurrency.TaskFromContext(ctx)
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		switch xerr.(type) {
+		case *fail.ErrNotAvailable:
+			task, xerr = concurrency.VoidTask()
+			if xerr != nil {
+				return xerr
+			}
+		default:
+			return xerr
+		}
+	}
+
+	if task.Aborted() {
+		return fail.AbortedError(nil, "aborted")
+	}
+
+	tracer := debug.NewTracer(task, tracing.ShouldTrace("resources.volume"), "(%d)", newSize).WithStopwatch().Entering()
+	defer tracer.Exiting()
+
+	attachedHosts, xerr := instance.unsafeResize(newSize)
+	if xerr != nil {
+		return xerr
+	}
+
+	// -- grows the file systems, the Volume being unlocked --
+	svc := instance.GetService()
+	volumeID := instance.GetID()
+	var errors []error
+	for hostID, hostName := range attachedHosts {
+		if task.Aborted() {
+			return fail.AbortedError(nil, "aborted")
+		}
+
+		xerr = growVolumeFilesystem(ctx, svc, hostID, volumeID)
+		if xerr != nil {
+			errors = append(errors, fail.Wrap(xerr, "failed to grow the file system of Volume '%s' on Host '%s'", instance.GetName(), hostName))
+		}
+	}
+	if len(errors) > 0 {
+		return fail.NewErrorList(errors)
+	}
+
+	logrus.Infof("Volume '%s' successfully resized to %d GB", instance.GetName(), newSize)
+	return nil
+}
+
+// unsafeResize grows the Volume on provider side and updates the metadata, returning the Hosts the Volume is attached to
+func (instance *volume) unsafeResize(newSize int) (map[string]string, fail.Error) {
+	instance.lock.Lock()
+	defer instance.lock.Unlock()
+
+	attachedHosts := map[string]string{}
+	xerr := instance.Alter(func(clonable data.Clonable, props *serialize.JSONProperties) fail.Error {
+		av, ok := clonable.(*abstract.Volume)
+		if !ok {
+			return fail.InconsistentError("'*abstract.Volume' expected, '%s' provided", reflect.TypeOf(clonable).String())
+		}
+
+		if newSize <= av.Size {
+			return fail.InvalidRequestError("cannot resize Volume '%s' to %d GB: a Volume can only grow (current size is %d GB)", av.Name, newSize, av.Size)
+		}
+
+		innerXErr := props.Inspect(volumeproperty.AttachedV1, func(clonable data.Clonable) fail.Error {
+			volumeAttachedV1, ok := clonable.(*propertiesv1.VolumeAttachments)
+			if !ok {
+				return fail.InconsistentError("'*propertiesv1.VolumeAttachments' expected, '%s' provided", reflect.TypeOf(clonable).String())
+			}
+
+			for k, v := range volumeAttachedV1.Hosts {
+				attachedHosts[k] = v
+			}
+			return nil
+		})
+		if innerXErr != nil {
+			return innerXErr
+		}
+
+		resized, innerXErr := instance.GetService().ResizeVolume(av.ID, newSize)
+		innerXErr = debug.InjectPlannedFail(innerXErr)
+		if innerXErr != nil {
+			return fail.Wrap(innerXErr, "failed to resize Volume '%s'", av.Name)
+		}
+
+		av.Size = resized.Size
+		return nil
+	})
+	if xerr != nil {
+		return nil, xerr
+	}
+
+	return attachedHosts, nil
+}
+
+// growVolumeFilesystem grows the file system of the Volume identified by volumeID, mounted on the Host identified by hostID
+func growVolumeFilesystem(ctx context.Context, svc iaas.Service, hostID, volumeID string) fail.Error {
+	hostInstance, xerr := LoadHost(svc, hostID)
+	if xerr != nil {
+		return xerr
+	}
+	defer hostInstance.Released()
+
+	hostVolumes, xerr := hostInstance.GetVolumes()
+	if xerr != nil {
+		return xerr
+	}
+	hostMounts, xerr := hostInstance.GetMounts()
+	if xerr != nil {
+		return xerr
+	}
+
+	device, ok := hostVolumes.DevicesByID[volumeID]
+	if !ok {
+		return fail.InconsistentError("failed to find the device of Volume on Host '%s'", hostInstance.GetName())
+	}
+	mountPoint, ok := hostMounts.LocalMountsByDevice[device]
+	if !ok {
+		return fail.NotFoundError("failed to find the mount point of Volume on Host '%s'", hostInstance.GetName())
+	}
+
+	retcode, stdout, stderr, xerr := hostInstance.Run(ctx, growFilesystemCommand(mountPoint), outputs.COLLECT, temporal.GetConnectSSHTimeout(), temporal.GetExecutionTimeout())
+	if xerr != nil {
+		return xerr
+	}
+	if retcode != 0 {
+		xerr := fail.ExecutionError(nil, "failed to grow the file system mounted on '%s'", mountPoint)
+		_ = xerr.Annotate("retcode", retcode)
+		_ = xerr.Annotate("stdout", stdout)
+		_ = xerr.Annotate("stderr", stderr)
+		return xerr
+	}
+	return nil
 }
 
 // Attach a volume to an host
@@ -1244,6 +1627,15 @@ func (instance *volume) ToProtocol() (*protocol.VolumeInspectResponse, fail.Erro
 		Attachments: []*protocol.VolumeAttachmentResponse{},
 	}
 
+	xerr := instance.Inspect(func(_ data.Clonable, props *serialize.JSONProperties) (innerXErr fail.Error) {
+		out.Labels, innerXErr = labelsFromProperties(props, volumeproperty.LabelsV1)
+		return innerXErr
+	})
+	xerr = debug.InjectPlannedFail(xerr)
+	if xerr != nil {
+		return nil, xerr
+	}
+
 	attachments, xerr := instance.GetAttachments()
 	xerr = debug.InjectPlannedFail(xerr)
 	if xerr != nil {
@@ -1294,3 +1686,21 @@ func (instance *volume) ToProtocol() (*protocol.VolumeInspectResponse, fail.Erro
 	}
 	return out, nil
 }
+
+// GetLabels returns the user-defined labels of the volume
+func (instance *volume) GetLabels() (labels map[string]string, xerr fail.Error) {
+	defer fail.OnPanic(&xerr)
+
+	if instance == nil || instance.IsNull() {
+		return nil, fail.InvalidInstanceError()
+	}
+
+	instance.lock.RLock()
+	defer instance.lock.RUnlock()
+
+	xerr = instance.Inspect(func(_ data.Clonable, props *serialize.JSONProperties) (innerXErr fail.Error) {
+		labels, innerXErr = labelsFromProperties(props, volumeproperty.LabelsV1)
+		return innerXErr
+	})
+	return labels, xerr
+}
@@ -0,0 +1,70 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumespeed"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+func Test_growFilesystemCommand(t *testing.T) {
+	cmd := growFilesystemCommand("/data/vol1")
+	assert.Contains(t, cmd, `findmnt -no SOURCE "$1"`)
+	assert.Contains(t, cmd, `findmnt -no FSTYPE "$1"`)
+	assert.Contains(t, cmd, "resize2fs $dev")
+	assert.Contains(t, cmd, `xfs_growfs "$1"`)
+	assert.True(t, strings.HasSuffix(cmd, `' grow-filesystem '/data/vol1'`))
+
+	// the mount point is passed as a single word, whatever its content
+	cmd = growFilesystemCommand("/data/x'; reboot; '")
+	assert.True(t, strings.HasSuffix(cmd, `' grow-filesystem '/data/x'\''; reboot; '\'''`))
+}
+
+func Test_volume_Resize(t *testing.T) {
+	svc := getMemoryService(t)
+	ctx := context.Background()
+
+	rv, xerr := NewVolume(svc)
+	require.Nil(t, xerr)
+	require.Nil(t, rv.Create(ctx, abstract.VolumeRequest{Name: "data", Size: 10, Speed: volumespeed.Hdd}))
+	rv.Released()
+
+	rv, xerr = LoadVolume(svc, "data")
+	require.Nil(t, xerr)
+	defer rv.Released()
+
+	xerr = rv.Resize(ctx, 10)
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrInvalidRequest{}, xerr)
+
+	require.Nil(t, rv.Resize(ctx, 20))
+	size, xerr := rv.GetSize()
+	require.Nil(t, xerr)
+	assert.Equal(t, 20, size)
+
+	av, xerr := svc.InspectVolume(rv.GetID())
+	require.Nil(t, xerr)
+	assert.Equal(t, 20, av.Size)
+}
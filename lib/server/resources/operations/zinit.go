@@ -44,6 +44,7 @@ func init() {
 		edgeproxy4subnetFeature(),
 		// keycloak4platformFeature(),
 		kubernetesFeature(),
+		k3sFeature(),
 		proxycacheServerFeature(),
 		proxycacheClientFeature(),
 		// apacheIgniteFeature(),
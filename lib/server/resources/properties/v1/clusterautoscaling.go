@@ -0,0 +1,101 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package propertiesv1
+
+import (
+	"time"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterproperty"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/data/serialize"
+)
+
+// MaxClusterScaleDecisions is the count of scale decisions kept in the metadata of the cluster
+const MaxClusterScaleDecisions = 20
+
+// ClusterScaleDecision records a scale decision taken by the autoscaler
+// !!!FROZEN!!!
+// Note: if tagged as FROZEN, must not be changed ever.
+//       Create a new version instead with needed supplemental fields
+type ClusterScaleDecision struct {
+	Time   time.Time `json:"time"`            // when the decision has been taken
+	Action string    `json:"action"`          // "expand" or "shrink"
+	Count  uint      `json:"count"`           // count of nodes added or removed
+	Nodes  uint      `json:"nodes"`           // count of general nodes before the action
+	Load   float64   `json:"load"`            // load of the general nodes (percent) when the decision has been taken
+	Reason string    `json:"reason"`          // why the decision has been taken
+	Error  string    `json:"error,omitempty"` // error that occurred applying the decision, if any
+}
+
+// ClusterAutoscaling contains the autoscaling settings of the cluster
+// The autoscaler of safescaled expands or shrinks the general nodes of the cluster (the nodes not belonging to a node pool)
+// depending on their CPU and memory load
+// !!!FROZEN!!!
+// Note: if tagged as FROZEN, must not be changed ever.
+//       Create a new version instead with needed supplemental fields
+type ClusterAutoscaling struct {
+	Enabled            bool                    `json:"enabled"`                   // tells if the autoscaler handles the cluster
+	MinNodes           uint                    `json:"min_nodes"`                 // minimum count of general nodes
+	MaxNodes           uint                    `json:"max_nodes"`                 // maximum count of general nodes
+	ScaleUpThreshold   float64                 `json:"scale_up_threshold"`        // load (percent) above which nodes are added
+	ScaleDownThreshold float64                 `json:"scale_down_threshold"`      // load (percent) under which nodes are removed
+	Cooldown           time.Duration           `json:"cooldown"`                  // minimum delay between 2 scale actions
+	Step               uint                    `json:"step"`                      // count of nodes added or removed by a scale action
+	LastScaleTime      time.Time               `json:"last_scale_time,omitempty"` // when the last scale action has been started
+	Decisions          []*ClusterScaleDecision `json:"decisions,omitempty"`       // last scale decisions, the most recent last
+}
+
+// NewClusterAutoscaling ...
+func NewClusterAutoscaling() *ClusterAutoscaling {
+	return &ClusterAutoscaling{
+		Decisions: []*ClusterScaleDecision{},
+	}
+}
+
+// Clone ... (data.Clonable interface)
+func (ca ClusterAutoscaling) Clone() data.Clonable {
+	return NewClusterAutoscaling().Replace(&ca)
+}
+
+// Replace ... (data.Clonable interface)
+func (ca *ClusterAutoscaling) Replace(p data.Clonable) data.Clonable {
+	// Do not test with isNull(), it's allowed to clone a null value...
+	if ca == nil || p == nil {
+		return ca
+	}
+
+	src := p.(*ClusterAutoscaling)
+	*ca = *src
+	ca.Decisions = make([]*ClusterScaleDecision, 0, len(src.Decisions))
+	for _, v := range src.Decisions {
+		item := *v
+		ca.Decisions = append(ca.Decisions, &item)
+	}
+	return ca
+}
+
+// Record adds a decision to the history, forgetting the oldest ones beyond MaxClusterScaleDecisions
+func (ca *ClusterAutoscaling) Record(decision ClusterScaleDecision) {
+	ca.Decisions = append(ca.Decisions, &decision)
+	if len(ca.Decisions) > MaxClusterScaleDecisions {
+		ca.Decisions = ca.Decisions[len(ca.Decisions)-MaxClusterScaleDecisions:]
+	}
+}
+
+func init() {
+	serialize.PropertyTypeRegistry.Register("resources.cluster", string(clusterproperty.AutoscalingV1), NewClusterAutoscaling())
+}
@@ -0,0 +1,52 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package propertiesv1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterAutoscaling_Clone(t *testing.T) {
+	ca := NewClusterAutoscaling()
+	ca.Enabled = true
+	ca.MinNodes, ca.MaxNodes = 3, 30
+	ca.Cooldown = 5 * time.Minute
+	ca.Record(ClusterScaleDecision{Action: "expand", Count: 2, Nodes: 3, Load: 91.5})
+
+	cloned, ok := ca.Clone().(*ClusterAutoscaling)
+	require.True(t, ok)
+	assert.Equal(t, ca, cloned)
+
+	cloned.Decisions[0].Error = "failed"
+	cloned.Record(ClusterScaleDecision{Action: "shrink", Count: 1})
+	assert.Len(t, ca.Decisions, 1)
+	assert.Empty(t, ca.Decisions[0].Error)
+}
+
+func TestClusterAutoscaling_Record(t *testing.T) {
+	ca := NewClusterAutoscaling()
+	for i := uint(0); i < MaxClusterScaleDecisions+5; i++ {
+		ca.Record(ClusterScaleDecision{Action: "expand", Count: i})
+	}
+	require.Len(t, ca.Decisions, MaxClusterScaleDecisions)
+	assert.Equal(t, uint(5), ca.Decisions[0].Count)
+	assert.Equal(t, uint(MaxClusterScaleDecisions+4), ca.Decisions[MaxClusterScaleDecisions-1].Count)
+}
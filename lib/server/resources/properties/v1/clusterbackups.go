@@ -0,0 +1,114 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package propertiesv1
+
+import (
+	"time"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterproperty"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/data/serialize"
+)
+
+// ClusterBackup describes a backup of the control plane of the cluster saved in Object Storage
+// !!!FROZEN!!!
+// Note: if tagged as FROZEN, must not be changed ever.
+//       Create a new version instead with needed supplemental fields
+type ClusterBackup struct {
+	ID           string    `json:"id"`                      // identifies the backup; objects of the backup are stored in the bucket under '<cluster name>/<id>/'
+	Bucket       string    `json:"bucket"`                  // name of the bucket containing the backup
+	CreationTime time.Time `json:"creation_time,omitempty"` // when the backup has been saved
+	Objects      []string  `json:"objects,omitempty"`       // names of the objects of the backup in the bucket
+	Size         int64     `json:"size,omitempty"`          // total size of the objects of the backup, in bytes
+}
+
+// Clone ... (data.Clonable interface)
+func (cb ClusterBackup) Clone() data.Clonable {
+	return (&ClusterBackup{}).Replace(&cb)
+}
+
+// Replace ... (data.Clonable interface)
+func (cb *ClusterBackup) Replace(p data.Clonable) data.Clonable {
+	// Do not test with isNull(), it's allowed to clone a null value...
+	if cb == nil || p == nil {
+		return cb
+	}
+
+	src := p.(*ClusterBackup)
+	*cb = *src
+	cb.Objects = make([]string, len(src.Objects))
+	copy(cb.Objects, src.Objects)
+	return cb
+}
+
+// ClusterBackups contains the backups of the control plane of the cluster, and the last restoration done
+// !!!FROZEN!!!
+// Note: if tagged as FROZEN, must not be changed ever.
+//       Create a new version instead with needed supplemental fields
+type ClusterBackups struct {
+	Backups         []*ClusterBackup `json:"backups,omitempty"`           // backups of the cluster, from the oldest to the newest
+	LastRestoreID   string           `json:"last_restore_id,omitempty"`   // id of the backup last restored
+	LastRestoreTime time.Time        `json:"last_restore_time,omitempty"` // when the last restoration has ended successfully
+}
+
+// NewClusterBackups ...
+func NewClusterBackups() *ClusterBackups {
+	return &ClusterBackups{
+		Backups: []*ClusterBackup{},
+	}
+}
+
+// Find returns the backup identified by id, or the newest one if id is empty; returns nil if not found
+func (cb *ClusterBackups) Find(id string) *ClusterBackup {
+	if cb == nil || len(cb.Backups) == 0 {
+		return nil
+	}
+	if id == "" {
+		return cb.Backups[len(cb.Backups)-1]
+	}
+	for _, v := range cb.Backups {
+		if v.ID == id {
+			return v
+		}
+	}
+	return nil
+}
+
+// Clone ... (data.Clonable interface)
+func (cb ClusterBackups) Clone() data.Clonable {
+	return NewClusterBackups().Replace(&cb)
+}
+
+// Replace ... (data.Clonable interface)
+func (cb *ClusterBackups) Replace(p data.Clonable) data.Clonable {
+	// Do not test with isNull(), it's allowed to clone a null value...
+	if cb == nil || p == nil {
+		return cb
+	}
+
+	src := p.(*ClusterBackups)
+	*cb = *src
+	cb.Backups = make([]*ClusterBackup, 0, len(src.Backups))
+	for _, v := range src.Backups {
+		cb.Backups = append(cb.Backups, v.Clone().(*ClusterBackup))
+	}
+	return cb
+}
+
+func init() {
+	serialize.PropertyTypeRegistry.Register("resources.cluster", string(clusterproperty.BackupsV1), NewClusterBackups())
+}
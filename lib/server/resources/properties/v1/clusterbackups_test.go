@@ -0,0 +1,49 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package propertiesv1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterBackups_Clone(t *testing.T) {
+	cb := NewClusterBackups()
+	cb.Backups = append(cb.Backups, &ClusterBackup{ID: "20211014T090211Z", Bucket: "k8s-backups", CreationTime: time.Now(), Objects: []string{"k8s/20211014T090211Z/manifest.json"}, Size: 1024})
+
+	cloned, ok := cb.Clone().(*ClusterBackups)
+	require.True(t, ok)
+	assert.Equal(t, cb, cloned)
+
+	cloned.Backups[0].Objects[0] = "changed"
+	cloned.Backups = append(cloned.Backups, &ClusterBackup{ID: "20211015T090211Z"})
+	assert.Len(t, cb.Backups, 1)
+	assert.Equal(t, "k8s/20211014T090211Z/manifest.json", cb.Backups[0].Objects[0])
+}
+
+func TestClusterBackups_Find(t *testing.T) {
+	cb := NewClusterBackups()
+	assert.Nil(t, cb.Find(""))
+
+	cb.Backups = append(cb.Backups, &ClusterBackup{ID: "20211014T090211Z"}, &ClusterBackup{ID: "20211015T090211Z"})
+	assert.Equal(t, "20211015T090211Z", cb.Find("").ID)
+	assert.Equal(t, "20211014T090211Z", cb.Find("20211014T090211Z").ID)
+	assert.Nil(t, cb.Find("unknown"))
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package propertiesv1
+
+import (
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterproperty"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/data/serialize"
+)
+
+// ClusterCreation contains the progress of the creation of the cluster, allowing to resume it after a failure
+// !!!FROZEN!!!
+// Note: if tagged as FROZEN, must not be changed ever.
+//       Create a new version instead with needed supplemental fields
+type ClusterCreation struct {
+	Request   *abstract.ClusterRequest `json:"request,omitempty"`   // request of creation, with sizings resolved; nil if the cluster has been created without tracking
+	Steps     []string                 `json:"steps,omitempty"`     // steps of the creation done, in order
+	Hosts     map[string]string        `json:"hosts,omitempty"`     // state reached by each host during the creation ("created" or "configured"), indexed by host name
+	Error     string                   `json:"error,omitempty"`     // error of the last attempt, if it failed
+	Completed bool                     `json:"completed,omitempty"` // set when the creation has ended successfully
+}
+
+// NewClusterCreation ...
+func NewClusterCreation() *ClusterCreation {
+	return &ClusterCreation{
+		Hosts: map[string]string{},
+	}
+}
+
+// IsNull ...
+// satisfies interface data.Clonable
+func (cc *ClusterCreation) IsNull() bool {
+	return cc == nil || cc.Request == nil
+}
+
+// Clone ... (data.Clonable interface)
+func (cc ClusterCreation) Clone() data.Clonable {
+	return NewClusterCreation().Replace(&cc)
+}
+
+// Replace ... (data.Clonable interface)
+func (cc *ClusterCreation) Replace(p data.Clonable) data.Clonable {
+	// Do not test with isNull(), it's allowed to clone a null value...
+	if cc == nil || p == nil {
+		return cc
+	}
+
+	src := p.(*ClusterCreation)
+	*cc = *src
+	if src.Request != nil {
+		req := *src.Request
+		if src.Request.DisabledDefaultFeatures != nil {
+			req.DisabledDefaultFeatures = make(map[string]struct{}, len(src.Request.DisabledDefaultFeatures))
+			for k := range src.Request.DisabledDefaultFeatures {
+				req.DisabledDefaultFeatures[k] = struct{}{}
+			}
+		}
+		if src.Request.Labels != nil {
+			req.Labels = make(map[string]string, len(src.Request.Labels))
+			for k, v := range src.Request.Labels {
+				req.Labels[k] = v
+			}
+		}
+		cc.Request = &req
+	}
+	cc.Steps = make([]string, len(src.Steps))
+	copy(cc.Steps, src.Steps)
+	cc.Hosts = make(map[string]string, len(src.Hosts))
+	for k, v := range src.Hosts {
+		cc.Hosts[k] = v
+	}
+	return cc
+}
+
+// Done tells if the step has been done
+func (cc *ClusterCreation) Done(step string) bool {
+	if cc == nil {
+		return false
+	}
+	for _, v := range cc.Steps {
+		if v == step {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	serialize.PropertyTypeRegistry.Register("resources.cluster", string(clusterproperty.CreationV1), NewClusterCreation())
+}
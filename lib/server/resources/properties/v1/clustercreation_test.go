@@ -0,0 +1,47 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package propertiesv1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+)
+
+func TestClusterCreation_Clone(t *testing.T) {
+	cc := NewClusterCreation()
+	assert.True(t, cc.IsNull())
+
+	cc.Request = &abstract.ClusterRequest{Name: "k8s", InitialNodeCount: 3, Labels: map[string]string{"team": "a"}}
+	cc.Steps = []string{"network"}
+	cc.Hosts["k8s-master-1"] = "created"
+
+	cloned, ok := cc.Clone().(*ClusterCreation)
+	require.True(t, ok)
+	assert.Equal(t, cc, cloned)
+
+	cloned.Steps = append(cloned.Steps, "gateways")
+	cloned.Hosts["k8s-node-1"] = "created"
+	cloned.Request.Labels["team"] = "b"
+	assert.True(t, cloned.Done("gateways"))
+	assert.False(t, cc.Done("gateways"))
+	assert.Len(t, cc.Hosts, 1)
+	assert.Equal(t, "a", cc.Request.Labels["team"])
+}
@@ -31,6 +31,8 @@ type ClusterInstalledFeature struct {
 	FileName   string              `json:"filename"`              // contains name of file used
 	RequiredBy map[string]struct{} `json:"required_by,omitempty"` // tells what feature(s) needs this one
 	Requires   map[string]struct{} `json:"requires,omitempty"`    // tells what feature(s) is(are) required by this one
+	Parameters map[string]string   `json:"parameters,omitempty"`  // contains the parameters given when the feature has been added
+	Version    string              `json:"version,omitempty"`     // contains the version of the feature installed, if the feature declares one
 }
 
 // NewClusterInstalledFeature ...
@@ -56,6 +58,9 @@ func (cif *ClusterInstalledFeature) Replace(p data.Clonable) data.Clonable {
 	}
 
 	src := p.(*ClusterInstalledFeature)
+	cif.Name = src.Name
+	cif.FileName = src.FileName
+	cif.Version = src.Version
 	cif.RequiredBy = make(map[string]struct{}, len(src.RequiredBy))
 	for k := range src.RequiredBy {
 		cif.RequiredBy[k] = struct{}{}
@@ -64,6 +69,12 @@ func (cif *ClusterInstalledFeature) Replace(p data.Clonable) data.Clonable {
 	for k := range src.Requires {
 		cif.Requires[k] = struct{}{}
 	}
+	if src.Parameters != nil {
+		cif.Parameters = make(map[string]string, len(src.Parameters))
+		for k, v := range src.Parameters {
+			cif.Parameters[k] = v
+		}
+	}
 	return cif
 }
 
@@ -25,6 +25,8 @@ import (
 
 func TestClusterInstalledFeature_Clone(t *testing.T) {
 	ct := NewClusterInstalledFeature()
+	ct.Name = "something-else"
+	ct.Version = "1.1.0"
 	ct.Requires["something"] = struct{}{}
 
 	clonedCt, ok := ct.Clone().(*ClusterInstalledFeature)
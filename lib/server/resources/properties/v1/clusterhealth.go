@@ -0,0 +1,99 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package propertiesv1
+
+import (
+	"time"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterproperty"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/data/serialize"
+)
+
+const (
+	// MaxClusterNodeRepairs is the count of repairs kept in the metadata of the cluster
+	MaxClusterNodeRepairs = 20
+	// DefaultClusterHealthFailureThreshold is the default count of consecutive failed health checks after which a node is considered as failed
+	DefaultClusterHealthFailureThreshold = 3
+)
+
+// ClusterNodeRepair records the replacement of a failed node by the health checker
+// !!!FROZEN!!!
+// Note: if tagged as FROZEN, must not be changed ever.
+//       Create a new version instead with needed supplemental fields
+type ClusterNodeRepair struct {
+	Time        time.Time `json:"time"`                  // when the repair has been started
+	Node        string    `json:"node"`                  // name of the failed node
+	Replacement string    `json:"replacement,omitempty"` // name of the node created to replace the failed one
+	Reason      string    `json:"reason"`                // why the node has been considered as failed
+	Error       string    `json:"error,omitempty"`       // error that occurred during the repair, if any
+}
+
+// ClusterHealth contains the health check settings of the cluster
+// The health checker of safescaled checks periodically the masters and the nodes of the cluster; when AutoRepair is set,
+// the nodes failing FailureThreshold consecutive checks are replaced
+// !!!FROZEN!!!
+// Note: if tagged as FROZEN, must not be changed ever.
+//       Create a new version instead with needed supplemental fields
+type ClusterHealth struct {
+	AutoRepair       bool                 `json:"auto_repair"`          // tells if failed nodes are replaced automatically
+	FailureThreshold uint                 `json:"failure_threshold"`    // count of consecutive failed checks after which a node is considered as failed
+	LastCheck        time.Time            `json:"last_check,omitempty"` // when the last health check has been done
+	Repairs          []*ClusterNodeRepair `json:"repairs,omitempty"`    // last repairs, the most recent last
+}
+
+// NewClusterHealth ...
+func NewClusterHealth() *ClusterHealth {
+	return &ClusterHealth{
+		FailureThreshold: DefaultClusterHealthFailureThreshold,
+		Repairs:          []*ClusterNodeRepair{},
+	}
+}
+
+// Clone ... (data.Clonable interface)
+func (ch ClusterHealth) Clone() data.Clonable {
+	return NewClusterHealth().Replace(&ch)
+}
+
+// Replace ... (data.Clonable interface)
+func (ch *ClusterHealth) Replace(p data.Clonable) data.Clonable {
+	// Do not test with isNull(), it's allowed to clone a null value...
+	if ch == nil || p == nil {
+		return ch
+	}
+
+	src := p.(*ClusterHealth)
+	*ch = *src
+	ch.Repairs = make([]*ClusterNodeRepair, 0, len(src.Repairs))
+	for _, v := range src.Repairs {
+		item := *v
+		ch.Repairs = append(ch.Repairs, &item)
+	}
+	return ch
+}
+
+// Record adds a repair to the history, forgetting the oldest ones beyond MaxClusterNodeRepairs
+func (ch *ClusterHealth) Record(repair ClusterNodeRepair) {
+	ch.Repairs = append(ch.Repairs, &repair)
+	if len(ch.Repairs) > MaxClusterNodeRepairs {
+		ch.Repairs = ch.Repairs[len(ch.Repairs)-MaxClusterNodeRepairs:]
+	}
+}
+
+func init() {
+	serialize.PropertyTypeRegistry.Register("resources.cluster", string(clusterproperty.HealthV1), NewClusterHealth())
+}
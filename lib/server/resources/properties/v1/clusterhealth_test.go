@@ -0,0 +1,51 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package propertiesv1
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterHealth_Clone(t *testing.T) {
+	ch := NewClusterHealth()
+	ch.AutoRepair = true
+	ch.Record(ClusterNodeRepair{Node: "k8s-node-2", Replacement: "k8s-node-4", Reason: "host is in state 'Terminated'"})
+
+	cloned, ok := ch.Clone().(*ClusterHealth)
+	require.True(t, ok)
+	assert.Equal(t, ch, cloned)
+
+	cloned.Repairs[0].Error = "failed"
+	cloned.Record(ClusterNodeRepair{Node: "k8s-node-3"})
+	assert.Len(t, ch.Repairs, 1)
+	assert.Empty(t, ch.Repairs[0].Error)
+}
+
+func TestClusterHealth_Record(t *testing.T) {
+	ch := NewClusterHealth()
+	assert.EqualValues(t, DefaultClusterHealthFailureThreshold, ch.FailureThreshold)
+	for i := 0; i < MaxClusterNodeRepairs+5; i++ {
+		ch.Record(ClusterNodeRepair{Node: fmt.Sprintf("node-%d", i)})
+	}
+	require.Len(t, ch.Repairs, MaxClusterNodeRepairs)
+	assert.Equal(t, "node-5", ch.Repairs[0].Node)
+	assert.Equal(t, fmt.Sprintf("node-%d", MaxClusterNodeRepairs+4), ch.Repairs[MaxClusterNodeRepairs-1].Node)
+}
@@ -0,0 +1,157 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package propertiesv1
+
+import (
+	"sort"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterproperty"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/data/serialize"
+)
+
+// NodePoolLabel is the Kubernetes label set on every node of a node pool, with the name of the pool as value
+const NodePoolLabel = "safescale.io/nodepool"
+
+// ClusterNodePool describes a named pool of nodes sharing the same sizing
+// !!!FROZEN!!!
+// Note: if tagged as FROZEN, must not be changed ever.
+//       Create a new version instead with needed supplemental fields
+type ClusterNodePool struct {
+	Name     string                          `json:"name"`             // name of the pool
+	Sizing   abstract.HostSizingRequirements `json:"sizing"`           // sizing of the nodes of the pool
+	Image    string                          `json:"image,omitempty"`  // image of the nodes of the pool; if empty, the default image of the cluster is used
+	MinCount uint                            `json:"min_count"`        // minimum count of nodes in the pool
+	MaxCount uint                            `json:"max_count"`        // maximum count of nodes in the pool; 0 means no limit
+	Labels   map[string]string               `json:"labels,omitempty"` // Kubernetes labels set on the nodes of the pool (flavors K8S and K3S)
+	Taints   []string                        `json:"taints,omitempty"` // Kubernetes taints set on the nodes of the pool, in the form key=value:Effect (flavors K8S and K3S)
+	Nodes    []uint                          `json:"nodes,omitempty"`  // numerical IDs of the nodes of the pool, in order of creation (cf. propertiesv3.ClusterNodes)
+}
+
+// NewClusterNodePool ...
+func NewClusterNodePool() *ClusterNodePool {
+	return &ClusterNodePool{
+		Labels: map[string]string{},
+		Taints: []string{},
+		Nodes:  []uint{},
+	}
+}
+
+// Clone ... (data.Clonable interface)
+func (np ClusterNodePool) Clone() data.Clonable {
+	return NewClusterNodePool().Replace(&np)
+}
+
+// Replace ... (data.Clonable interface)
+func (np *ClusterNodePool) Replace(p data.Clonable) data.Clonable {
+	// Do not test with isNull(), it's allowed to clone a null value...
+	if np == nil || p == nil {
+		return np
+	}
+
+	src := p.(*ClusterNodePool)
+	*np = *src
+	np.Labels = make(map[string]string, len(src.Labels))
+	for k, v := range src.Labels {
+		np.Labels[k] = v
+	}
+	np.Taints = make([]string, len(src.Taints))
+	copy(np.Taints, src.Taints)
+	np.Nodes = make([]uint, len(src.Nodes))
+	copy(np.Nodes, src.Nodes)
+	return np
+}
+
+// Accepts tells if the pool can contain count nodes
+func (np *ClusterNodePool) Accepts(count uint) bool {
+	return count >= np.MinCount && (np.MaxCount == 0 || count <= np.MaxCount)
+}
+
+// ClusterNodePools contains the node pools of the cluster; nodes not belonging to a pool are the general nodes of the cluster
+// !!!FROZEN!!!
+// Note: if tagged as FROZEN, must not be changed ever.
+//       Create a new version instead with needed supplemental fields
+type ClusterNodePools struct {
+	ByName map[string]*ClusterNodePool `json:"by_name,omitempty"` // node pools indexed by name
+}
+
+// NewClusterNodePools ...
+func NewClusterNodePools() *ClusterNodePools {
+	return &ClusterNodePools{
+		ByName: map[string]*ClusterNodePool{},
+	}
+}
+
+// Clone ... (data.Clonable interface)
+func (nps ClusterNodePools) Clone() data.Clonable {
+	return NewClusterNodePools().Replace(&nps)
+}
+
+// Replace ... (data.Clonable interface)
+func (nps *ClusterNodePools) Replace(p data.Clonable) data.Clonable {
+	// Do not test with isNull(), it's allowed to clone a null value...
+	if nps == nil || p == nil {
+		return nps
+	}
+
+	src := p.(*ClusterNodePools)
+	nps.ByName = make(map[string]*ClusterNodePool, len(src.ByName))
+	for k, v := range src.ByName {
+		nps.ByName[k] = v.Clone().(*ClusterNodePool)
+	}
+	return nps
+}
+
+// Names returns the names of the pools, sorted
+func (nps *ClusterNodePools) Names() []string {
+	list := make([]string, 0, len(nps.ByName))
+	for k := range nps.ByName {
+		list = append(list, k)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// PoolOf returns the name of the pool containing the node identified by its numerical ID, or "" if the node is a general node
+func (nps *ClusterNodePools) PoolOf(numericalID uint) string {
+	for k, v := range nps.ByName {
+		for _, n := range v.Nodes {
+			if n == numericalID {
+				return k
+			}
+		}
+	}
+	return ""
+}
+
+// RemoveNode removes the node identified by its numerical ID from the pool containing it, and returns the name of this pool
+func (nps *ClusterNodePools) RemoveNode(numericalID uint) string {
+	for k, v := range nps.ByName {
+		for i, n := range v.Nodes {
+			if n == numericalID {
+				v.Nodes = append(v.Nodes[:i], v.Nodes[i+1:]...)
+				return k
+			}
+		}
+	}
+	return ""
+}
+
+func init() {
+	serialize.PropertyTypeRegistry.Register("resources.cluster", string(clusterproperty.NodePoolsV1), NewClusterNodePools())
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package propertiesv1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+)
+
+func TestClusterNodePools_Clone(t *testing.T) {
+	nps := NewClusterNodePools()
+	nps.ByName["gpu"] = &ClusterNodePool{
+		Name:     "gpu",
+		Sizing:   abstract.HostSizingRequirements{MinCores: 8, MinGPU: 1},
+		MinCount: 1,
+		MaxCount: 4,
+		Labels:   map[string]string{"accelerator": "nvidia"},
+		Taints:   []string{"nvidia.com/gpu=true:NoSchedule"},
+		Nodes:    []uint{12, 13},
+	}
+
+	cloned, ok := nps.Clone().(*ClusterNodePools)
+	require.True(t, ok)
+	assert.Equal(t, nps, cloned)
+
+	cloned.ByName["gpu"].Nodes = append(cloned.ByName["gpu"].Nodes, 14)
+	cloned.ByName["gpu"].Labels["accelerator"] = "amd"
+	cloned.ByName["highmem"] = NewClusterNodePool()
+	assert.Len(t, nps.ByName, 1)
+	assert.Len(t, nps.ByName["gpu"].Nodes, 2)
+	assert.Equal(t, "nvidia", nps.ByName["gpu"].Labels["accelerator"])
+	assert.Equal(t, []string{"gpu", "highmem"}, cloned.Names())
+}
+
+func TestClusterNodePools_Nodes(t *testing.T) {
+	nps := NewClusterNodePools()
+	nps.ByName["highmem"] = &ClusterNodePool{Name: "highmem", Nodes: []uint{11, 15, 16}}
+
+	assert.Equal(t, "highmem", nps.PoolOf(15))
+	assert.Equal(t, "", nps.PoolOf(12))
+
+	assert.Equal(t, "highmem", nps.RemoveNode(15))
+	assert.Equal(t, []uint{11, 16}, nps.ByName["highmem"].Nodes)
+	assert.Equal(t, "", nps.RemoveNode(15))
+}
+
+func TestClusterNodePool_Accepts(t *testing.T) {
+	np := &ClusterNodePool{MinCount: 1, MaxCount: 3}
+	assert.False(t, np.Accepts(0))
+	assert.True(t, np.Accepts(1))
+	assert.True(t, np.Accepts(3))
+	assert.False(t, np.Accepts(4))
+
+	np.MaxCount = 0
+	assert.True(t, np.Accepts(100))
+}
@@ -0,0 +1,95 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package propertiesv1
+
+import (
+	"time"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterproperty"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterstate"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/data/serialize"
+)
+
+// ClusterUpgrade contains the progress of the last rolling upgrade of the hosts of the cluster, allowing to resume it after a failure
+// !!!FROZEN!!!
+// Note: if tagged as FROZEN, must not be changed ever.
+//       Create a new version instead with needed supplemental fields
+type ClusterUpgrade struct {
+	Request       *abstract.ClusterUpgradeRequest `json:"request,omitempty"`        // request of the upgrade
+	Masters       []string                        `json:"masters,omitempty"`        // names of the masters to upgrade, in order
+	Nodes         []string                        `json:"nodes,omitempty"`          // names of the nodes to upgrade, in order
+	Hosts         map[string]string               `json:"hosts,omitempty"`          // state reached by each host ("upgraded" or "failed"), indexed by host name
+	Error         string                          `json:"error,omitempty"`          // error of the last attempt, if it failed
+	StartTime     time.Time                       `json:"start_time,omitempty"`     // when the upgrade has been started
+	EndTime       time.Time                       `json:"end_time,omitempty"`       // when the upgrade has ended successfully
+	Completed     bool                            `json:"completed,omitempty"`      // set when the upgrade has ended successfully
+	PreviousState clusterstate.Enum               `json:"previous_state,omitempty"` // state of the cluster before it entered state Upgrading, restored when the attempt ends
+}
+
+// NewClusterUpgrade ...
+func NewClusterUpgrade() *ClusterUpgrade {
+	return &ClusterUpgrade{
+		Hosts: map[string]string{},
+	}
+}
+
+// IsNull ...
+// satisfies interface data.Clonable
+func (cu *ClusterUpgrade) IsNull() bool {
+	return cu == nil || cu.Request == nil
+}
+
+// Clone ... (data.Clonable interface)
+func (cu ClusterUpgrade) Clone() data.Clonable {
+	return NewClusterUpgrade().Replace(&cu)
+}
+
+// Replace ... (data.Clonable interface)
+func (cu *ClusterUpgrade) Replace(p data.Clonable) data.Clonable {
+	// Do not test with isNull(), it's allowed to clone a null value...
+	if cu == nil || p == nil {
+		return cu
+	}
+
+	src := p.(*ClusterUpgrade)
+	*cu = *src
+	if src.Request != nil {
+		req := *src.Request
+		if src.Request.Params != nil {
+			req.Params = make(map[string]string, len(src.Request.Params))
+			for k, v := range src.Request.Params {
+				req.Params[k] = v
+			}
+		}
+		cu.Request = &req
+	}
+	cu.Masters = make([]string, len(src.Masters))
+	copy(cu.Masters, src.Masters)
+	cu.Nodes = make([]string, len(src.Nodes))
+	copy(cu.Nodes, src.Nodes)
+	cu.Hosts = make(map[string]string, len(src.Hosts))
+	for k, v := range src.Hosts {
+		cu.Hosts[k] = v
+	}
+	return cu
+}
+
+func init() {
+	serialize.PropertyTypeRegistry.Register("resources.cluster", string(clusterproperty.UpgradeV1), NewClusterUpgrade())
+}
@@ -0,0 +1,47 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package propertiesv1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+)
+
+func TestClusterUpgrade_Clone(t *testing.T) {
+	cu := NewClusterUpgrade()
+	assert.True(t, cu.IsNull())
+
+	cu.Request = &abstract.ClusterUpgradeRequest{Feature: "docker", Params: map[string]string{"Version": "20.10"}, Parallelism: 2}
+	cu.Masters = []string{"k8s-master-1"}
+	cu.Nodes = []string{"k8s-node-1", "k8s-node-2"}
+	cu.Hosts["k8s-master-1"] = "upgraded"
+
+	cloned, ok := cu.Clone().(*ClusterUpgrade)
+	require.True(t, ok)
+	assert.Equal(t, cu, cloned)
+
+	cloned.Nodes[0] = "k8s-node-3"
+	cloned.Hosts["k8s-node-1"] = "failed"
+	cloned.Request.Params["Version"] = "20.11"
+	assert.Equal(t, "k8s-node-1", cu.Nodes[0])
+	assert.Len(t, cu.Hosts, 1)
+	assert.Equal(t, "20.10", cu.Request.Params["Version"])
+}
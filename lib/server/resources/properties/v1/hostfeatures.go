@@ -30,6 +30,7 @@ type HostInstalledFeature struct {
 	HostContext bool                `json:"host_context,omitempty"` // tells if the feature has been explicitly installed for host (opposed to for cluster)
 	RequiredBy  map[string]struct{} `json:"required_by,omitempty"`  // tells what feature(s) needs this one
 	Requires    map[string]struct{} `json:"requires,omitempty"`
+	Version     string              `json:"version,omitempty"` // contains the version of the feature installed, if the feature declares one
 }
 
 // NewHostInstalledFeature ...
@@ -51,7 +52,7 @@ func (hif *HostInstalledFeature) Reset() {
 // Clone ...
 // satisfies interface data.Clonable
 func (hif HostInstalledFeature) Clone() data.Clonable {
-	return NewClusterInstalledFeature().Replace(&hif)
+	return NewHostInstalledFeature().Replace(&hif)
 }
 
 // Replace ...
@@ -64,6 +65,7 @@ func (hif *HostInstalledFeature) Replace(p data.Clonable) data.Clonable {
 
 	src := p.(*HostInstalledFeature)
 	hif.HostContext = src.HostContext
+	hif.Version = src.Version
 	hif.RequiredBy = make(map[string]struct{}, len(src.RequiredBy))
 	for k := range src.RequiredBy {
 		hif.RequiredBy[k] = struct{}{}
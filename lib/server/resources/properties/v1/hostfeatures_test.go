@@ -0,0 +1,46 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package propertiesv1
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHostInstalledFeature_Clone(t *testing.T) {
+	hif := NewHostInstalledFeature()
+	hif.HostContext = true
+	hif.Version = "1.1.0"
+	hif.Requires["something"] = struct{}{}
+
+	clonedHif, ok := hif.Clone().(*HostInstalledFeature)
+	if !ok {
+		t.Fail()
+	}
+
+	assert.Equal(t, hif, clonedHif)
+
+	clonedHif.RequiredBy["other"] = struct{}{}
+
+	areEqual := reflect.DeepEqual(hif, clonedHif)
+	if areEqual {
+		t.Error("It's a shallow clone !")
+		t.Fail()
+	}
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package propertiesv1
+
+import (
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/hostproperty"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/data/serialize"
+)
+
+// HostPublicIPs contains the public IPs (managed by SafeScale as resources) bound to the host
+// not FROZEN yet
+// Note: if tagged as FROZEN, must not be changed ever.
+//       Create a new version instead with needed supplemental fields
+type HostPublicIPs struct {
+	ByID   map[string]string `json:"by_id,omitempty"`   // contains the address of the public IPs, indexed by public IP ID
+	ByName map[string]string `json:"by_name,omitempty"` // contains the ID of the public IPs, indexed by public IP name
+}
+
+// NewHostPublicIPs ...
+func NewHostPublicIPs() *HostPublicIPs {
+	return &HostPublicIPs{
+		ByID:   map[string]string{},
+		ByName: map[string]string{},
+	}
+}
+
+// Reset ...
+func (hpi *HostPublicIPs) Reset() {
+	*hpi = HostPublicIPs{
+		ByID:   map[string]string{},
+		ByName: map[string]string{},
+	}
+}
+
+// Clone ...
+func (hpi HostPublicIPs) Clone() data.Clonable {
+	return NewHostPublicIPs().Replace(&hpi)
+}
+
+// Replace ...
+func (hpi *HostPublicIPs) Replace(p data.Clonable) data.Clonable {
+	// Do not test with isNull(), it's allowed to clone a null value...
+	if hpi == nil || p == nil {
+		return hpi
+	}
+
+	src := p.(*HostPublicIPs)
+	hpi.ByID = make(map[string]string, len(src.ByID))
+	for k, v := range src.ByID {
+		hpi.ByID[k] = v
+	}
+	hpi.ByName = make(map[string]string, len(src.ByName))
+	for k, v := range src.ByName {
+		hpi.ByName[k] = v
+	}
+	return hpi
+}
+
+func init() {
+	serialize.PropertyTypeRegistry.Register("resources.host", hostproperty.PublicIPsV1, NewHostPublicIPs())
+}
@@ -0,0 +1,80 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package propertiesv1
+
+import (
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterproperty"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/hostproperty"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/networkproperty"
+	"github.com/CS-SI/SafeScale/lib/server/resources/enums/volumeproperty"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/data/serialize"
+)
+
+// ResourceLabels contains the user-defined labels of a resource (host, volume, network or cluster), in V1
+// !!!FROZEN!!!
+// Note: if tagged as FROZEN, must not be changed ever.
+//       Create a new version instead with needed supplemental fields
+type ResourceLabels struct {
+	ByKey map[string]string `json:"by_key,omitempty"` // contains the value of the labels, indexed by key
+}
+
+// NewResourceLabels ...
+func NewResourceLabels() *ResourceLabels {
+	return &ResourceLabels{
+		ByKey: map[string]string{},
+	}
+}
+
+// Reset resets the content of the property
+func (rl *ResourceLabels) Reset() {
+	*rl = ResourceLabels{
+		ByKey: map[string]string{},
+	}
+}
+
+// Content ... (data.Clonable interface)
+func (rl *ResourceLabels) Content() interface{} {
+	return rl
+}
+
+// Clone ... (data.Clonable interface)
+func (rl ResourceLabels) Clone() data.Clonable {
+	return NewResourceLabels().Replace(&rl)
+}
+
+// Replace ... (data.Clonable interface)
+func (rl *ResourceLabels) Replace(p data.Clonable) data.Clonable {
+	// Do not test with isNull(), it's allowed to clone a null value...
+	if rl == nil || p == nil {
+		return rl
+	}
+
+	src := p.(*ResourceLabels)
+	rl.ByKey = make(map[string]string, len(src.ByKey))
+	for k, v := range src.ByKey {
+		rl.ByKey[k] = v
+	}
+	return rl
+}
+
+func init() {
+	serialize.PropertyTypeRegistry.Register("resources.host", hostproperty.LabelsV1, NewResourceLabels())
+	serialize.PropertyTypeRegistry.Register("resources.volume", volumeproperty.LabelsV1, NewResourceLabels())
+	serialize.PropertyTypeRegistry.Register("resources.network", networkproperty.LabelsV1, NewResourceLabels())
+	serialize.PropertyTypeRegistry.Register("resources.cluster", string(clusterproperty.LabelsV1), NewResourceLabels())
+}
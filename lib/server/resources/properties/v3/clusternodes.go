@@ -17,6 +17,8 @@
 package propertiesv3
 
 import (
+	"time"
+
 	"github.com/CS-SI/SafeScale/lib/server/resources/enums/clusterproperty"
 	"github.com/CS-SI/SafeScale/lib/utils/data"
 	"github.com/CS-SI/SafeScale/lib/utils/data/serialize"
@@ -32,23 +34,41 @@ type ClusterNode struct {
 	PrivateIP   string `json:"private_ip"` // private ip of the node
 }
 
+// Health status of a node of the cluster
+const (
+	NodeHealthy  = "healthy"  // the node passed the last health check
+	NodeDegraded = "degraded" // the node failed the last health check(s)
+	NodeFailed   = "failed"   // the node failed enough consecutive health checks to be considered as lost
+)
+
+// ClusterNodeHealth describes the result of the health checks of a node of the cluster
+// Not frozen yet
+type ClusterNodeHealth struct {
+	Status    string    `json:"status"`           // NodeHealthy, NodeDegraded or NodeFailed
+	Reason    string    `json:"reason,omitempty"` // why the last health check failed
+	Failures  uint      `json:"failures"`         // count of consecutive failed health checks
+	Since     time.Time `json:"since"`            // when the node entered the current status
+	LastCheck time.Time `json:"last_check"`       // when the last health check has been done
+}
+
 // ClusterNodes contains all the nodes created in the cluster
 // Not frozen yet
 type ClusterNodes struct {
-	Masters           []uint                `json:"masters,omitempty"`
-	MasterByName      map[string]uint       `json:"master_by_name,omitempty"`
-	MasterByID        map[string]uint       `json:"master_by_id,omitempty"`
-	PrivateNodes      []uint                `json:"private_nodes,omitempty"`
-	PrivateNodeByName map[string]uint       `json:"private_node_by_name,omitempty"`
-	PrivateNodeByID   map[string]uint       `json:"private_node_by_id,omitempty"`
-	PublicNodes       []uint                `json:"public_nodes,omitempty"`
-	PublicNodeByName  map[string]uint       `json:"public_node_by_name,omitempty"`
-	PublicNodeByID    map[string]uint       `json:"public_node_by_id,omitempty"`
-	ByNumericalID     map[uint]*ClusterNode `json:"host_by_numeric_id,omitempty"` // maps *ClusterNode with NumericalID
-	MasterLastIndex   int                   `json:"master_last_index,omitempty"`  // is used to keep the index associated to the name of the last created master
-	PrivateLastIndex  int                   `json:"private_last_index,omitempty"` // is used to keep the index associated to the name of the last created private node
-	PublicLastIndex   int                   `json:"public_last_index,omitempty"`  // is used to keep the index associated to the name of the last created public node
-	GlobalLastIndex   uint                  `json:"global_last_index,omitempty"`  // is used to keep the index associated to the last created ClusterNode (being master or node)
+	Masters           []uint                      `json:"masters,omitempty"`
+	MasterByName      map[string]uint             `json:"master_by_name,omitempty"`
+	MasterByID        map[string]uint             `json:"master_by_id,omitempty"`
+	PrivateNodes      []uint                      `json:"private_nodes,omitempty"`
+	PrivateNodeByName map[string]uint             `json:"private_node_by_name,omitempty"`
+	PrivateNodeByID   map[string]uint             `json:"private_node_by_id,omitempty"`
+	PublicNodes       []uint                      `json:"public_nodes,omitempty"`
+	PublicNodeByName  map[string]uint             `json:"public_node_by_name,omitempty"`
+	PublicNodeByID    map[string]uint             `json:"public_node_by_id,omitempty"`
+	ByNumericalID     map[uint]*ClusterNode       `json:"host_by_numeric_id,omitempty"` // maps *ClusterNode with NumericalID
+	MasterLastIndex   int                         `json:"master_last_index,omitempty"`  // is used to keep the index associated to the name of the last created master
+	PrivateLastIndex  int                         `json:"private_last_index,omitempty"` // is used to keep the index associated to the name of the last created private node
+	PublicLastIndex   int                         `json:"public_last_index,omitempty"`  // is used to keep the index associated to the name of the last created public node
+	GlobalLastIndex   uint                        `json:"global_last_index,omitempty"`  // is used to keep the index associated to the last created ClusterNode (being master or node)
+	Health            map[uint]*ClusterNodeHealth `json:"health,omitempty"`             // health of the masters and nodes, indexed by NumericalID
 }
 
 func newClusterNodes() *ClusterNodes {
@@ -63,6 +83,7 @@ func newClusterNodes() *ClusterNodes {
 		PrivateNodeByName: map[string]uint{},
 		PrivateNodeByID:   map[string]uint{},
 		ByNumericalID:     map[uint]*ClusterNode{},
+		Health:            map[uint]*ClusterNodeHealth{},
 		GlobalLastIndex:   10, // Keep some places for special cases, like gateways NumericalID
 	}
 }
@@ -116,6 +137,12 @@ func (n *ClusterNodes) Replace(p data.Clonable) data.Clonable {
 		n.ByNumericalID[k] = &node
 	}
 
+	n.Health = make(map[uint]*ClusterNodeHealth, len(src.Health))
+	for k, v := range src.Health {
+		health := *v
+		n.Health[k] = &health
+	}
+
 	return n
 }
 
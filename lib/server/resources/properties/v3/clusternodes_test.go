@@ -72,3 +72,21 @@ func TestNodes_Clone2(t *testing.T) {
 		t.FailNow()
 	}
 }
+
+func TestNodes_CloneHealth(t *testing.T) {
+	ct := newClusterNodes()
+	ct.Health[11] = &ClusterNodeHealth{Status: NodeDegraded, Reason: "SSH unreachable", Failures: 1}
+	clonedCt, ok := ct.Clone().(*ClusterNodes)
+	if !ok {
+		t.Fail()
+	}
+
+	assert.Equal(t, ct, clonedCt)
+	clonedCt.Health[11].Failures++
+
+	areEqual := reflect.DeepEqual(ct, clonedCt)
+	if areEqual {
+		t.Error("It's a shallow clone !")
+		t.FailNow()
+	}
+}
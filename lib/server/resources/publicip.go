@@ -0,0 +1,45 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resources
+
+import (
+	"context"
+
+	"github.com/CS-SI/SafeScale/lib/protocol"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/data/cache"
+	"github.com/CS-SI/SafeScale/lib/utils/data/observer"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// PublicIP links Object Storage folder and public IPs
+type PublicIP interface {
+	Metadata
+	data.Identifiable
+	observer.Observable
+	cache.Cacheable
+
+	BindToHost(ctx context.Context, host Host) fail.Error                                // binds the public IP to a host, moving it from the host currently using it if needed
+	Browse(ctx context.Context, callback func(*abstract.PublicIP) fail.Error) fail.Error // walks through all the metadata objects in public IP folder
+	Create(ctx context.Context, req abstract.PublicIPRequest) fail.Error                 // allocates a public IP
+	Delete(ctx context.Context, force bool) fail.Error                                   // releases the public IP; if force is true, unbinds it from host first
+	GetAddress() (string, fail.Error)                                                    // returns the IP address
+	GetBoundHost() (string, fail.Error)                                                  // returns the ID of the host the public IP is bound to, empty string if not bound
+	ToProtocol() (*protocol.PublicIPResponse, fail.Error)                                // converts public IP to equivalent protocol message
+	UnbindFromHost(ctx context.Context, host Host) fail.Error                            // unbinds the public IP from the host
+}
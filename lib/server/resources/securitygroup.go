@@ -58,12 +58,15 @@ type SecurityGroup interface {
 	BindToHost(ctx context.Context, host Host, _ SecurityGroupActivation, _ SecurityGroupMark) fail.Error          // binds a security group to a host
 	BindToSubnet(ctx context.Context, _ Subnet, _ SecurityGroupActivation, _ SecurityGroupMark) fail.Error         // binds a security group to a network
 	Browse(ctx context.Context, callback func(*abstract.SecurityGroup) fail.Error) fail.Error                      // browses the metadata folder of Security Groups and call the callback on each entry
+	CheckConsistency(ctx context.Context) (*abstract.SecurityGroupDrift, fail.Error)                               // checks the rules on provider side are the ones registered in metadata, returning the differences
 	Clear(ctx context.Context) fail.Error                                                                          // removes rules from the security group
 	Create(ctx context.Context, networkID, name, description string, rules abstract.SecurityGroupRules) fail.Error // creates a new host and its metadata
 	Delete(ctx context.Context, force bool) fail.Error                                                             // deletes the Security Group
 	DeleteRule(ctx context.Context, rule *abstract.SecurityGroupRule) fail.Error                                   // deletes a rule from a Security Group
 	GetBoundHosts(ctx context.Context) ([]*propertiesv1.SecurityGroupBond, fail.Error)                             // returns a slice of bonds corresponding to hosts bound to the security group
 	GetBoundSubnets(ctx context.Context) ([]*propertiesv1.SecurityGroupBond, fail.Error)                           // returns a slice of bonds corresponding to networks bound to the security group
+	Import(ctx context.Context, networkRef, ref string) fail.Error                                                 // registers in metadata an existing Security Group of the provider
+	Reconcile(ctx context.Context, adopt bool) (*abstract.SecurityGroupDrift, fail.Error)                          // fixes the differences between the rules on provider side and the ones in metadata
 	Reset(ctx context.Context) fail.Error                                                                          // resets the rules of the security group from the ones registered in metadata
 	ToProtocol() (*protocol.SecurityGroupResponse, fail.Error)                                                     // converts a SecurityGroup to equivalent gRPC message
 	UnbindFromHost(ctx context.Context, _ Host) fail.Error                                                         // unbinds a Security Group from Host
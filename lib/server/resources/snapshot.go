@@ -0,0 +1,43 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resources
+
+import (
+	"context"
+
+	"github.com/CS-SI/SafeScale/lib/protocol"
+	"github.com/CS-SI/SafeScale/lib/server/resources/abstract"
+	"github.com/CS-SI/SafeScale/lib/utils/data"
+	"github.com/CS-SI/SafeScale/lib/utils/data/cache"
+	"github.com/CS-SI/SafeScale/lib/utils/data/observer"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// Snapshot links Object Storage folder and volume snapshots
+type Snapshot interface {
+	Metadata
+	data.Identifiable
+	observer.Observable
+	cache.Cacheable
+
+	Browse(ctx context.Context, callback func(*abstract.Snapshot) fail.Error) fail.Error // walks through all the metadata objects in snapshot folder
+	Create(ctx context.Context, req abstract.SnapshotRequest) fail.Error                 // takes a snapshot of a volume
+	Delete(ctx context.Context) fail.Error                                               // deletes the snapshot
+	GetVolumeID() (string, fail.Error)                                                   // returns the ID of the volume the snapshot has been taken from
+	Restore(ctx context.Context, req abstract.VolumeRequest) (Volume, fail.Error)        // creates a new volume from the snapshot
+	ToProtocol() (*protocol.VolumeSnapshotResponse, fail.Error)                          // converts snapshot to equivalent protocol message
+}
@@ -52,6 +52,7 @@ type Subnet interface {
 	GetEndpointIP() (string, fail.Error)                                                                                   // returns the public IP to reach the Subnet from Internet
 	GetState() (subnetstate.Enum, fail.Error)                                                                              // gives the current state of the Subnet
 	HasVirtualIP() (bool, fail.Error)                                                                                      // tells if the Subnet is using a VIP as default route
+	Import(ctx context.Context, ref string) fail.Error                                                                     // registers in metadata an existing Subnet of the provider
 	InspectGateway(primary bool) (Host, fail.Error)                                                                        // returns the gateway related to Subnet
 	InspectGatewaySecurityGroup() (SecurityGroup, fail.Error)                                                              // returns the SecurityGroup responsible of network security on Gateway
 	InspectInternalSecurityGroup() (SecurityGroup, fail.Error)                                                             // returns the SecurityGroup responsible of internal network security
@@ -42,7 +42,10 @@ type Volume interface {
 	Delete(ctx context.Context) fail.Error                                                   // deletes a volume
 	Detach(ctx context.Context, host Host) fail.Error                                        // detaches the volume identified by ref, ref can be the name or the id
 	GetAttachments() (*propertiesv1.VolumeAttachments, fail.Error)                           // returns the property containing where the volume is attached
+	GetLabels() (map[string]string, fail.Error)                                              // returns the user-defined labels of the volume
 	GetSize() (int, fail.Error)                                                              // returns the size of volume in GB
 	GetSpeed() (volumespeed.Enum, fail.Error)                                                // returns the speed of the volume (more or less the type of hardware)
+	Import(ctx context.Context, ref string) fail.Error                                       // registers in metadata an existing volume of the provider
+	Resize(ctx context.Context, newSize int) fail.Error                                      // grows the volume to newSize GB, and its file system on the hosts where it is attached
 	ToProtocol() (*protocol.VolumeInspectResponse, fail.Error)                               // converts volume to equivalent protocol message
 }
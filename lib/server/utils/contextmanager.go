@@ -44,6 +44,15 @@ func GetContext(storeUUID bool) (context.Context, fail.Error) {
 	return clientContext, nil
 }
 
+// AsyncMetadataKey is the key of gRPC metadata asking safescaled to run a call asynchronously
+const AsyncMetadataKey = "async"
+
+// WithAsync returns a copy of the client context asking safescaled to run the call asynchronously
+// (supported only by long operations, the others ignore it)
+func WithAsync(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, AsyncMetadataKey, "true")
+}
+
 // GetTimeoutContext return a context for gRPC commands
 func GetTimeoutContext(parentCtx context.Context, timeout time.Duration) (context.Context, context.CancelFunc, fail.Error) {
 	if parentCtx != context.TODO() {
@@ -17,6 +17,7 @@
 package system
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -384,6 +385,7 @@ type SSHCommand struct {
 	cmd          *exec.Cmd
 	tunnels      SSHTunnels
 	keyFile      *os.File
+	nativeRun    nativeRunner // set when command is executed by the native SSH executor
 }
 
 // Wait waits for the command to exit and waits for any copying to stdin or copying from stdout or stderr to complete.
@@ -483,6 +485,14 @@ func (scmd *SSHCommand) Output() ([]byte, fail.Error) {
 	if scmd == nil {
 		return nil, fail.InvalidInstanceError()
 	}
+	if scmd.nativeRun != nil {
+		var stdout, stderr bytes.Buffer
+		xerr := scmd.runNativeOutput(&stdout, &stderr)
+		if xerr != nil {
+			return nil, xerr
+		}
+		return stdout.Bytes(), nil
+	}
 	if scmd.cmd == nil {
 		return nil, fail.InvalidInstanceContentError("scmd.cmd", "cannot be nil")
 	}
@@ -500,6 +510,14 @@ func (scmd *SSHCommand) CombinedOutput() ([]byte, fail.Error) {
 	if scmd == nil {
 		return nil, fail.InvalidInstanceError()
 	}
+	if scmd.nativeRun != nil {
+		var combined bytes.Buffer
+		xerr := scmd.runNativeOutput(&combined, &combined)
+		if xerr != nil {
+			return nil, xerr
+		}
+		return combined.Bytes(), nil
+	}
 	if scmd.cmd == nil {
 		return nil, fail.InvalidInstanceContentError("scmd.cmd", "cannot be nil")
 	}
@@ -682,6 +700,10 @@ func (scmd *SSHCommand) taskExecute(task concurrency.Task, p concurrency.TaskPar
 		return nil, fail.InvalidParameterError("p", "must be a 'taskExecuteParameters'")
 	}
 
+	if scmd.nativeRun != nil {
+		return scmd.taskExecuteNative(task, params)
+	}
+
 	var (
 		stdoutBridge, stderrBridge cli.PipeBridge
 		pipeBridgeCtrl             *cli.PipeBridgeController
@@ -798,14 +820,79 @@ func (scmd *SSHCommand) taskExecute(task concurrency.Task, p concurrency.TaskPar
 	return result, nil
 }
 
+// taskExecuteNative executes the command with the native SSH executor
+// Outside of collect mode, the outputs are displayed through pipe bridges, as done for the openssh executor
+func (scmd *SSHCommand) taskExecuteNative(task concurrency.Task, params taskExecuteParameters) (concurrency.TaskResult, fail.Error) {
+	var stdoutBuf, stderrBuf bytes.Buffer
+	if params.collectOutputs {
+		retcode, xerr := scmd.nativeRun(task.Context(), &stdoutBuf, &stderrBuf)
+		result := data.Map{
+			"retcode": retcode,
+			"stdout":  stdoutBuf.String(),
+			"stderr":  stderrBuf.String(),
+		}
+		return result, xerr
+	}
+
+	result := data.Map{
+		"retcode": -1,
+		"stdout":  "",
+		"stderr":  "",
+	}
+
+	stdoutReader, stdoutWriter := io.Pipe()
+	stderrReader, stderrWriter := io.Pipe()
+	stdoutBridge, xerr := cli.NewStdoutBridge(stdoutReader)
+	if xerr != nil {
+		return result, xerr
+	}
+	stderrBridge, xerr := cli.NewStderrBridge(stderrReader)
+	if xerr != nil {
+		return result, xerr
+	}
+	pipeBridgeCtrl, xerr := cli.NewPipeBridgeController(stdoutBridge, stderrBridge)
+	if xerr != nil {
+		return result, xerr
+	}
+	if xerr = pipeBridgeCtrl.Start(task); xerr != nil {
+		return result, xerr
+	}
+
+	retcode, xerr := scmd.nativeRun(task.Context(), stdoutWriter, stderrWriter)
+	_ = stdoutWriter.Close()
+	_ = stderrWriter.Close()
+	if pbcErr := pipeBridgeCtrl.Wait(); pbcErr != nil {
+		logrus.Error(pbcErr.Error())
+	}
+	_ = stdoutReader.Close()
+	_ = stderrReader.Close()
+
+	result["retcode"] = retcode
+	return result, xerr
+}
+
+// runNativeOutput executes the command with the native SSH executor, with the same error semantic as exec.Cmd.Output()
+func (scmd *SSHCommand) runNativeOutput(stdout, stderr io.Writer) fail.Error {
+	retcode, xerr := scmd.nativeRun(context.Background(), stdout, stderr)
+	if xerr != nil {
+		return xerr
+	}
+	if retcode != 0 {
+		return fail.NewError("exit status %d", retcode)
+	}
+	return nil
+}
+
 // Close is called to clean SSHCommand (close tunnel(s), remove temporary files, ...)
 func (scmd *SSHCommand) Close() fail.Error {
-	var err1 error
+	var err1, err2 error
 
 	if len(scmd.tunnels) > 0 {
 		err1 = scmd.tunnels.Close()
 	}
-	err2 := utils.LazyRemove(scmd.keyFile.Name())
+	if scmd.keyFile != nil {
+		err2 = utils.LazyRemove(scmd.keyFile.Name())
+	}
 	if err1 != nil {
 		logrus.Errorf("SSHCommand.closeTunnels() failed: %s (%s)", err1.Error(), reflect.TypeOf(err1).String())
 		return fail.Wrap(err1, "failed to close SSH tunnels")
@@ -986,6 +1073,15 @@ func (sconf *SSHConfig) newCommand(ctx context.Context, cmdString string, withTt
 		return nil, fail.AbortedError(nil, "aborted")
 	}
 
+	if sconf.useNative() {
+		sshCommand := SSHCommand{
+			hostname:     sconf.Hostname,
+			runCmdString: cmdString,
+			nativeRun:    sconf.newNativeCommandRunner(cmdString, withTty, withSudo),
+		}
+		return &sshCommand, nil
+	}
+
 	tunnels, sshConfig, xerr := sconf.CreateTunneling()
 	if xerr != nil {
 		return nil, fail.Wrap(xerr, "unable to create SSH tunnel")
@@ -1042,6 +1138,19 @@ func (sconf *SSHConfig) newCopyCommand(ctx context.Context, localPath, remotePat
 		return nil, fail.AbortedError(nil, "aborted")
 	}
 
+	if sconf.useNative() {
+		direction := "download"
+		if isUpload {
+			direction = "upload"
+		}
+		sshCommand := SSHCommand{
+			hostname:     sconf.Hostname,
+			runCmdString: fmt.Sprintf("sftp %s '%s' <-> '%s'", direction, localPath, remotePath),
+			nativeRun:    sconf.newNativeCopyRunner(localPath, remotePath, isUpload),
+		}
+		return &sshCommand, nil
+	}
+
 	tunnels, sshConfig, xerr := sconf.CreateTunneling()
 	if xerr != nil {
 		return nil, xerr
@@ -0,0 +1,491 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/sftp"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/CS-SI/SafeScale/lib/system/sshtunnel"
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+const (
+	// SSHExecutorOpenSSH designates the executor running the ssh/scp binaries installed on the system (default)
+	SSHExecutorOpenSSH = "openssh"
+	// SSHExecutorNative designates the executor using the native Go implementation of SSH, with no external binaries nor key files on disk
+	SSHExecutorNative = "native"
+
+	sshExecutorEnvVar       = "SAFESCALE_SSH_EXECUTOR"
+	nativeSSHConnectTimeout = 60 * time.Second
+
+	// exit codes used by the native executor, mimicking the ones of ssh and scp binaries
+	nativeSSHConnectionFailed = 255
+	nativeSCPGeneralError     = 1
+	nativeSCPConnectionFailed = 4
+	nativeSCPFileNotFound     = 6
+	nativeSCPPermissionDenied = 7
+	nativeSCPProtocolError    = 8
+)
+
+var (
+	sshExecutorLock     sync.RWMutex
+	sshExecutorOverride string
+)
+
+// SetSSHExecutor selects the executor used by SSHConfig to run commands and copy files
+// An empty string restores the default behavior, where the executor is read from environment variable SAFESCALE_SSH_EXECUTOR
+func SetSSHExecutor(executor string) fail.Error {
+	switch executor {
+	case "", SSHExecutorOpenSSH, SSHExecutorNative:
+	default:
+		return fail.InvalidParameterError("executor", "must be '%s' or '%s'", SSHExecutorOpenSSH, SSHExecutorNative)
+	}
+
+	sshExecutorLock.Lock()
+	defer sshExecutorLock.Unlock()
+
+	sshExecutorOverride = executor
+	return nil
+}
+
+// GetSSHExecutor returns the executor currently used by SSHConfig
+func GetSSHExecutor() string {
+	sshExecutorLock.RLock()
+	defer sshExecutorLock.RUnlock()
+
+	if sshExecutorOverride != "" {
+		return sshExecutorOverride
+	}
+	if executor := os.Getenv(sshExecutorEnvVar); executor != "" {
+		switch executor = strings.ToLower(strings.TrimSpace(executor)); executor {
+		case SSHExecutorOpenSSH, SSHExecutorNative:
+			return executor
+		default:
+			logrus.Warnf("ignoring invalid value '%s' of %s, using '%s'", executor, sshExecutorEnvVar, SSHExecutorOpenSSH)
+		}
+	}
+	return SSHExecutorOpenSSH
+}
+
+// useNativeSSH tells if the native SSH executor has to be used
+func useNativeSSH() bool {
+	return GetSSHExecutor() == SSHExecutorNative
+}
+
+// SSHDialer reaches SSH servers without TCP connection, like the ones of the hosts simulated in process by a provider
+type SSHDialer interface {
+	// Serves tells if the dialer reaches the SSH server listening on address ('<ip>:<port>')
+	Serves(address string) bool
+	// DialSSH opens a connection to the SSH server listening on address
+	DialSSH(ctx context.Context, address string) (net.Conn, error)
+}
+
+var (
+	sshDialersLock sync.RWMutex
+	sshDialers     []SSHDialer
+)
+
+// RegisterSSHDialer registers a dialer; the hosts it serves are always reached with the native SSH executor, directly
+// (without going through their gateways)
+func RegisterSSHDialer(dialer SSHDialer) {
+	if dialer == nil {
+		return
+	}
+
+	sshDialersLock.Lock()
+	defer sshDialersLock.Unlock()
+
+	sshDialers = append(sshDialers, dialer)
+}
+
+// sshDialerFor returns the registered dialer serving the host described by sconf, or nil
+func sshDialerFor(sconf *SSHConfig) SSHDialer {
+	sshDialersLock.RLock()
+	defer sshDialersLock.RUnlock()
+
+	if len(sshDialers) == 0 {
+		return nil
+	}
+	address := nativeSSHAddress(sconf)
+	for _, v := range sshDialers {
+		if v.Serves(address) {
+			return v
+		}
+	}
+	return nil
+}
+
+// useNative tells if the host described by sconf has to be reached with the native SSH executor
+func (sconf *SSHConfig) useNative() bool {
+	return useNativeSSH() || sshDialerFor(sconf) != nil
+}
+
+// nativeRunner executes a remote action with the native SSH executor, writing outputs to stdout and stderr
+// returns the exit code of the action, and an error if the action could not be completed for reasons unrelated to remote
+type nativeRunner func(ctx context.Context, stdout, stderr io.Writer) (int, fail.Error)
+
+// nativeSSHConnection is a chain of SSH clients reaching a host, through its gateways if any
+type nativeSSHConnection struct {
+	clients []*ssh.Client // ordered from the outermost gateway to the target host
+}
+
+// dialNativeSSH connects to the host described by sconf, jumping through its gateways if any
+func dialNativeSSH(ctx context.Context, sconf *SSHConfig) (*nativeSSHConnection, fail.Error) {
+	conn := &nativeSSHConnection{}
+	if xerr := conn.dial(ctx, sconf); xerr != nil {
+		_ = conn.Close()
+		return nil, xerr
+	}
+	return conn, nil
+}
+
+// dial appends to the chain the SSH clients needed to reach the host described by sconf
+// If the primary gateway cannot be reached, the secondary gateway is tried if defined
+func (conn *nativeSSHConnection) dial(ctx context.Context, sconf *SSHConfig) fail.Error {
+	dialer := sshDialerFor(sconf)
+	if sconf.GatewayConfig != nil && dialer == nil {
+		count := len(conn.clients)
+		xerr := conn.dial(ctx, sconf.GatewayConfig)
+		if xerr != nil {
+			if sconf.SecondaryGatewayConfig == nil {
+				return xerr
+			}
+
+			conn.truncate(count)
+			logrus.Debugf("failed to reach primary gateway of '%s', trying secondary gateway: %v", sconf.Hostname, xerr)
+			if xerr = conn.dial(ctx, sconf.SecondaryGatewayConfig); xerr != nil {
+				return xerr
+			}
+		}
+	}
+
+	config, xerr := nativeSSHClientConfig(sconf)
+	if xerr != nil {
+		return xerr
+	}
+
+	address := nativeSSHAddress(sconf)
+	var (
+		netConn net.Conn
+		err     error
+	)
+	switch {
+	case dialer != nil:
+		netConn, err = dialer.DialSSH(ctx, address)
+	case len(conn.clients) == 0:
+		tcpDialer := net.Dialer{Timeout: config.Timeout}
+		netConn, err = tcpDialer.DialContext(ctx, "tcp", address)
+	default:
+		netConn, err = conn.clients[len(conn.clients)-1].Dial("tcp", address)
+	}
+	if err != nil {
+		return fail.NotAvailableError("failed to connect to '%s' (%s): %v", sconf.Hostname, address, err)
+	}
+
+	client, err := nativeSSHHandshake(ctx, netConn, address, config)
+	if err != nil {
+		return fail.NotAvailableError("failed to establish SSH session with '%s' (%s): %v", sconf.Hostname, address, err)
+	}
+
+	conn.clients = append(conn.clients, client)
+	return nil
+}
+
+// nativeSSHHandshake establishes the SSH session on netConn, closing netConn if the handshake fails, lasts more than
+// config.Timeout or ctx is done
+// Note: the deadline of netConn cannot be used, connections forwarded by a gateway do not support it
+func nativeSSHHandshake(ctx context.Context, netConn net.Conn, address string, config *ssh.ClientConfig) (*ssh.Client, error) {
+	type result struct {
+		client *ssh.Client
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		clientConn, chans, reqs, err := ssh.NewClientConn(netConn, address, config)
+		if err != nil {
+			done <- result{err: err}
+			return
+		}
+		done <- result{client: ssh.NewClient(clientConn, chans, reqs)}
+	}()
+
+	timer := time.NewTimer(config.Timeout)
+	defer timer.Stop()
+
+	var err error
+	select {
+	case r := <-done:
+		if r.err == nil {
+			return r.client, nil
+		}
+		err = r.err
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-timer.C:
+		err = fmt.Errorf("handshake timed out after %v", config.Timeout)
+	}
+
+	// closing netConn makes the handshake fail if it is still running
+	_ = netConn.Close()
+	return nil, err
+}
+
+// target returns the client connected to the target host
+func (conn *nativeSSHConnection) target() *ssh.Client {
+	return conn.clients[len(conn.clients)-1]
+}
+
+// truncate closes the clients after the count first ones
+func (conn *nativeSSHConnection) truncate(count int) {
+	for i := len(conn.clients) - 1; i >= count; i-- {
+		_ = conn.clients[i].Close()
+	}
+	conn.clients = conn.clients[:count]
+}
+
+// Close closes all the clients of the chain, starting from the target host
+func (conn *nativeSSHConnection) Close() error {
+	if conn == nil {
+		return nil
+	}
+	conn.truncate(0)
+	return nil
+}
+
+// nativeSSHClientConfig builds the ssh.ClientConfig corresponding to sconf
+func nativeSSHClientConfig(sconf *SSHConfig) (*ssh.ClientConfig, fail.Error) {
+	auth, err := sshtunnel.AuthMethodFromPrivateKey([]byte(sconf.PrivateKey), nil)
+	if err != nil {
+		return nil, fail.Wrap(err, "failed to parse private key of '%s'", sconf.Hostname)
+	}
+
+	return &ssh.ClientConfig{
+		User: sconf.User,
+		Auth: []ssh.AuthMethod{auth},
+		// same behavior as '-oStrictHostKeyChecking=no -oUserKnownHostsFile=/dev/null' of openssh executor
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(), // nolint
+		Timeout:         nativeSSHConnectTimeout,
+	}, nil
+}
+
+// nativeSSHAddress returns the address to use to reach the host described by sconf
+func nativeSSHAddress(sconf *SSHConfig) string {
+	port := sconf.Port
+	if port == 0 {
+		port = 22
+	}
+	return net.JoinHostPort(sconf.IPAddress, strconv.Itoa(port))
+}
+
+// newNativeCommandRunner returns a nativeRunner executing cmdString on remote host
+// The command is streamed to the stdin of the remote shell, as done by openssh executor with heredoc
+func (sconf *SSHConfig) newNativeCommandRunner(cmdString string, withTty, withSudo bool) nativeRunner {
+	config := *sconf
+	return func(ctx context.Context, stdout, stderr io.Writer) (int, fail.Error) {
+		conn, xerr := dialNativeSSH(ctx, &config)
+		if xerr != nil {
+			_, _ = fmt.Fprintln(stderr, xerr.Error())
+			return nativeSSHConnectionFailed, nil
+		}
+		defer func() { _ = conn.Close() }()
+
+		session, err := conn.target().NewSession()
+		if err != nil {
+			_, _ = fmt.Fprintln(stderr, err.Error())
+			return nativeSSHConnectionFailed, nil
+		}
+		defer func() { _ = session.Close() }()
+
+		// server may refuse to set the variable, as ssh client does it is not considered as an error
+		_ = session.Setenv("IAM", config.Hostname)
+		if withTty {
+			if err = session.RequestPty("xterm", 40, 80, ssh.TerminalModes{}); err != nil {
+				_, _ = fmt.Fprintln(stderr, err.Error())
+				return nativeSSHConnectionFailed, nil
+			}
+		}
+
+		session.Stdin = strings.NewReader(cmdString + "\n")
+		session.Stdout = stdout
+		session.Stderr = stderr
+
+		shell := "bash"
+		if withSudo {
+			shell = "sudo bash"
+		}
+		if err = session.Start(shell); err != nil {
+			_, _ = fmt.Fprintln(stderr, err.Error())
+			return nativeSSHConnectionFailed, nil
+		}
+
+		done := make(chan error, 1)
+		go func() {
+			done <- session.Wait()
+		}()
+
+		select {
+		case err = <-done:
+		case <-ctx.Done():
+			_ = session.Signal(ssh.SIGKILL)
+			_ = conn.Close()
+			<-done
+			return -1, fail.AbortedError(ctx.Err(), "aborted")
+		}
+
+		switch cerr := err.(type) {
+		case nil:
+			return 0, nil
+		case *ssh.ExitError:
+			return cerr.ExitStatus(), nil
+		default:
+			// connection lost or command killed without exit status, ssh client returns 255 in this case
+			_, _ = fmt.Fprintln(stderr, err.Error())
+			return nativeSSHConnectionFailed, nil
+		}
+	}
+}
+
+// newNativeCopyRunner returns a nativeRunner copying a file from/to local to/from remote using SFTP
+// Exit codes are the ones of scp
+func (sconf *SSHConfig) newNativeCopyRunner(localPath, remotePath string, isUpload bool) nativeRunner {
+	config := *sconf
+	return func(ctx context.Context, _, stderr io.Writer) (int, fail.Error) {
+		conn, xerr := dialNativeSSH(ctx, &config)
+		if xerr != nil {
+			_, _ = fmt.Fprintln(stderr, xerr.Error())
+			return nativeSCPConnectionFailed, nil
+		}
+		defer func() { _ = conn.Close() }()
+
+		client, err := sftp.NewClient(conn.target())
+		if err != nil {
+			_, _ = fmt.Fprintln(stderr, err.Error())
+			return nativeSCPProtocolError, nil
+		}
+		defer func() { _ = client.Close() }()
+
+		done := make(chan error, 1)
+		go func() {
+			if isUpload {
+				done <- nativeUpload(client, localPath, remotePath)
+			} else {
+				done <- nativeDownload(client, remotePath, localPath)
+			}
+		}()
+
+		select {
+		case err = <-done:
+		case <-ctx.Done():
+			_ = conn.Close()
+			<-done
+			return -1, fail.AbortedError(ctx.Err(), "aborted")
+		}
+		if err != nil {
+			_, _ = fmt.Fprintln(stderr, err.Error())
+			switch {
+			case os.IsNotExist(err):
+				return nativeSCPFileNotFound, nil
+			case os.IsPermission(err):
+				return nativeSCPPermissionDenied, nil
+			default:
+				return nativeSCPGeneralError, nil
+			}
+		}
+		return 0, nil
+	}
+}
+
+// nativeUpload copies local file localPath to remotePath, keeping permissions
+func nativeUpload(client *sftp.Client, localPath, remotePath string) error {
+	src, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = src.Close() }()
+
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	dst, err := client.OpenFile(remotePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+	if err != nil {
+		return nativeSFTPError("open", remotePath, err)
+	}
+	if _, err = io.Copy(dst, src); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	if err = dst.Close(); err != nil {
+		return err
+	}
+	if err = client.Chmod(remotePath, info.Mode().Perm()); err != nil {
+		return nativeSFTPError("chmod", remotePath, err)
+	}
+	return nil
+}
+
+// nativeDownload copies remote file remotePath to localPath, keeping permissions
+func nativeDownload(client *sftp.Client, remotePath, localPath string) error {
+	src, err := client.Open(remotePath)
+	if err != nil {
+		return nativeSFTPError("open", remotePath, err)
+	}
+	defer func() { _ = src.Close() }()
+
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	dst, err := os.OpenFile(localPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(dst, src); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	return dst.Close()
+}
+
+// nativeSFTPError converts an error returned by the SFTP server on path to an *os.PathError, mapping the SFTP status
+// codes "no such file" and "permission denied" to os.ErrNotExist and os.ErrPermission to satisfy os.IsNotExist()
+// and os.IsPermission()
+func nativeSFTPError(op, path string, err error) error {
+	if statusErr, ok := err.(*sftp.StatusError); ok {
+		switch statusErr.FxCode() {
+		case sftp.ErrSSHFxNoSuchFile:
+			err = os.ErrNotExist
+		case sftp.ErrSSHFxPermissionDenied:
+			err = os.ErrPermission
+		}
+	}
+	return &os.PathError{Op: op, Path: path, Err: err}
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+// silentConn returns a connection to a server never answering the SSH handshake
+func silentConn(t *testing.T) net.Conn {
+	client, server := net.Pipe()
+	go func() {
+		// reads what the client sends, to not block it, but never writes
+		buf := make([]byte, 1024)
+		for {
+			if _, err := server.Read(buf); err != nil {
+				return
+			}
+		}
+	}()
+	t.Cleanup(func() { _ = server.Close() })
+	return client
+}
+
+func Test_nativeSSHHandshake(t *testing.T) {
+	config := &ssh.ClientConfig{User: "safescale", HostKeyCallback: ssh.InsecureIgnoreHostKey(), Timeout: time.Minute} // nolint
+
+	// interrupted when context is done
+	conn := silentConn(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	begin := time.Now()
+	_, err := nativeSSHHandshake(ctx, conn, "silent:22", config)
+	require.NotNil(t, err)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.True(t, time.Since(begin) < 10*time.Second)
+	_, err = conn.Write([]byte("x"))
+	assert.NotNil(t, err, "connection must be closed")
+
+	// interrupted when connection timeout is reached
+	conn = silentConn(t)
+	config.Timeout = 200 * time.Millisecond
+	begin = time.Now()
+	_, err = nativeSSHHandshake(context.Background(), conn, "silent:22", config)
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "timed out")
+	assert.True(t, time.Since(begin) < 10*time.Second)
+	_, err = conn.Write([]byte("x"))
+	assert.NotNil(t, err, "connection must be closed")
+}
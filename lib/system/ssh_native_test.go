@@ -0,0 +1,361 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package system_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pkg/sftp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/CS-SI/SafeScale/lib/system"
+	"github.com/CS-SI/SafeScale/lib/system/sshtunnel"
+	"github.com/CS-SI/SafeScale/lib/utils/cli/enums/outputs"
+)
+
+// testSSHServer is a minimal SSH server executing commands locally, serving SFTP and forwarding TCP connections
+type testSSHServer struct {
+	listener net.Listener
+	config   *ssh.ServerConfig
+}
+
+func newTestSSHServer(t *testing.T, authorized ssh.PublicKey) *testSSHServer {
+	_, hostKey, err := ed25519.GenerateKey(rand.Reader)
+	require.Nil(t, err)
+	signer, err := ssh.NewSignerFromKey(hostKey)
+	require.Nil(t, err)
+
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if bytes.Equal(key.Marshal(), authorized.Marshal()) {
+				return nil, nil
+			}
+			return nil, errors.New("unauthorized key")
+		},
+	}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+
+	server := &testSSHServer{listener: listener, config: config}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	t.Cleanup(func() { _ = listener.Close() })
+	return server
+}
+
+func (s *testSSHServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *testSSHServer) serve(conn net.Conn) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, s.config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	for newChannel := range chans {
+		switch newChannel.ChannelType() {
+		case "session":
+			go s.handleSession(newChannel)
+		case "direct-tcpip":
+			go s.handleDirectTCPIP(newChannel)
+		default:
+			_ = newChannel.Reject(ssh.UnknownChannelType, "unsupported channel type")
+		}
+	}
+}
+
+func (s *testSSHServer) handleSession(newChannel ssh.NewChannel) {
+	channel, reqs, err := newChannel.Accept()
+	if err != nil {
+		return
+	}
+
+	for req := range reqs {
+		switch req.Type {
+		case "env":
+			_ = req.Reply(true, nil)
+		case "exec":
+			var payload struct{ Command string }
+			if err = ssh.Unmarshal(req.Payload, &payload); err != nil {
+				_ = req.Reply(false, nil)
+				continue
+			}
+			_ = req.Reply(true, nil)
+
+			go func() {
+				cmd := exec.Command("sh", "-c", payload.Command)
+				cmd.Stdin = channel
+				cmd.Stdout = channel
+				cmd.Stderr = channel.Stderr()
+				status := 0
+				if err := cmd.Run(); err != nil {
+					status = 1
+					if ee, ok := err.(*exec.ExitError); ok {
+						status = ee.ExitCode()
+					}
+				}
+				_, _ = channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{uint32(status)}))
+				_ = channel.Close()
+			}()
+		case "subsystem":
+			_ = req.Reply(true, nil)
+			go func() {
+				server, err := sftp.NewServer(channel)
+				if err == nil {
+					_ = server.Serve()
+				}
+				_ = channel.Close()
+			}()
+		default:
+			_ = req.Reply(false, nil)
+		}
+	}
+}
+
+func (s *testSSHServer) handleDirectTCPIP(newChannel ssh.NewChannel) {
+	var payload struct {
+		Host     string
+		Port     uint32
+		OrigHost string
+		OrigPort uint32
+	}
+	if err := ssh.Unmarshal(newChannel.ExtraData(), &payload); err != nil {
+		_ = newChannel.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+
+	target, err := net.Dial("tcp", net.JoinHostPort(payload.Host, strconv.Itoa(int(payload.Port))))
+	if err != nil {
+		_ = newChannel.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+
+	channel, reqs, err := newChannel.Accept()
+	if err != nil {
+		_ = target.Close()
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	go func() {
+		_, _ = io.Copy(target, channel)
+		_ = target.Close()
+	}()
+	go func() {
+		_, _ = io.Copy(channel, target)
+		_ = channel.Close()
+	}()
+}
+
+// unusedPort returns a port on which nothing listens
+func unusedPort(t *testing.T) int {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	require.Nil(t, listener.Close())
+	return port
+}
+
+// setupNativeSSH starts a test SSH server and selects the native executor
+func setupNativeSSH(t *testing.T) system.SSHConfig {
+	privateKey, publicKey, err := sshtunnel.GenerateRSAKeyPair(2048)
+	require.Nil(t, err)
+	authorized, _, _, _, err := ssh.ParseAuthorizedKey([]byte(publicKey))
+	require.Nil(t, err)
+
+	server := newTestSSHServer(t, authorized)
+
+	require.Nil(t, system.SetSSHExecutor(system.SSHExecutorNative))
+	t.Cleanup(func() { _ = system.SetSSHExecutor("") })
+
+	return system.SSHConfig{
+		Hostname:   "target",
+		IPAddress:  "127.0.0.1",
+		Port:       server.port(),
+		User:       "safescale",
+		PrivateKey: privateKey,
+	}
+}
+
+func Test_SSHExecutorSelection(t *testing.T) {
+	assert.NotNil(t, system.SetSSHExecutor("putty"))
+
+	require.Nil(t, os.Setenv("SAFESCALE_SSH_EXECUTOR", "native"))
+	defer func() { _ = os.Unsetenv("SAFESCALE_SSH_EXECUTOR") }()
+	assert.Equal(t, system.SSHExecutorNative, system.GetSSHExecutor())
+
+	require.Nil(t, system.SetSSHExecutor(system.SSHExecutorOpenSSH))
+	assert.Equal(t, system.SSHExecutorOpenSSH, system.GetSSHExecutor())
+
+	require.Nil(t, system.SetSSHExecutor(""))
+	require.Nil(t, os.Setenv("SAFESCALE_SSH_EXECUTOR", "unknown"))
+	assert.Equal(t, system.SSHExecutorOpenSSH, system.GetSSHExecutor())
+}
+
+func Test_NativeSSH_Run(t *testing.T) {
+	sconf := setupNativeSSH(t)
+	ctx := context.Background()
+
+	cmd, xerr := sconf.NewCommand(ctx, "echo hello\necho oops >&2\nexit 3")
+	require.Nil(t, xerr)
+	defer func() { _ = cmd.Close() }()
+
+	retcode, stdout, stderr, xerr := cmd.RunWithTimeout(ctx, outputs.COLLECT, 10*time.Second)
+	require.Nil(t, xerr)
+	assert.Equal(t, 3, retcode)
+	assert.Equal(t, "hello\n", stdout)
+	assert.Equal(t, "oops\n", stderr)
+
+	cmd, xerr = sconf.NewCommand(ctx, "echo -n whoami")
+	require.Nil(t, xerr)
+	out, xerr := cmd.Output()
+	require.Nil(t, xerr)
+	assert.Equal(t, "whoami", string(out))
+}
+
+func Test_NativeSSH_Gateways(t *testing.T) {
+	sconf := setupNativeSSH(t)
+	ctx := context.Background()
+
+	gateway := sconf
+	gateway.Hostname = "gateway"
+	target := sconf
+	target.GatewayConfig = &gateway
+
+	cmd, xerr := target.NewCommand(ctx, "echo $((40+2))")
+	require.Nil(t, xerr)
+	retcode, stdout, _, xerr := cmd.RunWithTimeout(ctx, outputs.COLLECT, 10*time.Second)
+	require.Nil(t, xerr)
+	assert.Equal(t, 0, retcode)
+	assert.Equal(t, "42\n", stdout)
+
+	// primary gateway unreachable, secondary gateway is used
+	unreachable := gateway
+	unreachable.Port = unusedPort(t)
+	target.GatewayConfig = &unreachable
+	target.SecondaryGatewayConfig = &gateway
+
+	cmd, xerr = target.NewCommand(ctx, "echo through secondary")
+	require.Nil(t, xerr)
+	retcode, stdout, _, xerr = cmd.RunWithTimeout(ctx, outputs.COLLECT, 10*time.Second)
+	require.Nil(t, xerr)
+	assert.Equal(t, 0, retcode)
+	assert.Equal(t, "through secondary\n", stdout)
+}
+
+func Test_NativeSSH_ConnectionFailure(t *testing.T) {
+	sconf := setupNativeSSH(t)
+	ctx := context.Background()
+
+	sconf.Port = unusedPort(t)
+	cmd, xerr := sconf.NewCommand(ctx, "true")
+	require.Nil(t, xerr)
+	retcode, _, stderr, xerr := cmd.RunWithTimeout(ctx, outputs.COLLECT, 10*time.Second)
+	require.Nil(t, xerr)
+	assert.Equal(t, 255, retcode, "connection failure must be reported as ssh binary does")
+	assert.NotEmpty(t, stderr)
+
+	retcode, _, _, xerr = sconf.CopyWithTimeout(ctx, "/tmp/remote", "/tmp/local", true, 10*time.Second)
+	require.Nil(t, xerr)
+	assert.True(t, system.IsSCPRetryable(retcode))
+}
+
+func Test_NativeSSH_Timeout(t *testing.T) {
+	sconf := setupNativeSSH(t)
+	ctx := context.Background()
+
+	cmd, xerr := sconf.NewCommand(ctx, "sleep 30")
+	require.Nil(t, xerr)
+
+	begin := time.Now()
+	_, _, _, xerr = cmd.RunWithTimeout(ctx, outputs.COLLECT, time.Second)
+	assert.NotNil(t, xerr)
+	assert.True(t, time.Since(begin) < 10*time.Second, "command must be interrupted when timeout is reached")
+}
+
+func Test_NativeSSH_Copy(t *testing.T) {
+	sconf := setupNativeSSH(t)
+	ctx := context.Background()
+
+	dir, err := ioutil.TempDir("", "safescale-native-ssh")
+	require.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	local := filepath.Join(dir, "local")
+	remote := filepath.Join(dir, "remote")
+	back := filepath.Join(dir, "back")
+	require.Nil(t, ioutil.WriteFile(local, []byte("content to copy"), 0640))
+
+	retcode, _, stderr, xerr := sconf.CopyWithTimeout(ctx, remote, local, true, 10*time.Second)
+	require.Nil(t, xerr)
+	require.Equal(t, 0, retcode, stderr)
+	content, err := ioutil.ReadFile(remote)
+	require.Nil(t, err)
+	assert.Equal(t, "content to copy", string(content))
+	info, err := os.Stat(remote)
+	require.Nil(t, err)
+	assert.Equal(t, os.FileMode(0640), info.Mode().Perm())
+
+	retcode, _, stderr, xerr = sconf.CopyWithTimeout(ctx, remote, back, false, 10*time.Second)
+	require.Nil(t, xerr)
+	require.Equal(t, 0, retcode, stderr)
+	content, err = ioutil.ReadFile(back)
+	require.Nil(t, err)
+	assert.Equal(t, "content to copy", string(content))
+
+	retcode, _, _, xerr = sconf.CopyWithTimeout(ctx, filepath.Join(dir, "missing"), back, false, 10*time.Second)
+	require.Nil(t, xerr)
+	assert.Equal(t, "File does not exist", system.SCPErrorString(retcode))
+
+	retcode, _, _, xerr = sconf.CopyWithTimeout(ctx, filepath.Join(dir, "missing", "remote"), local, true, 10*time.Second)
+	require.Nil(t, xerr)
+	assert.Equal(t, "File does not exist", system.SCPErrorString(retcode))
+
+	if os.Geteuid() != 0 {
+		readOnly := filepath.Join(dir, "readonly")
+		require.Nil(t, os.Mkdir(readOnly, 0500))
+		retcode, _, _, xerr = sconf.CopyWithTimeout(ctx, filepath.Join(readOnly, "remote"), local, true, 10*time.Second)
+		require.Nil(t, xerr)
+		assert.Equal(t, "No permission to access file", system.SCPErrorString(retcode))
+	}
+}
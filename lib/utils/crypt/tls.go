@@ -0,0 +1,330 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package crypt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// Names of the files created by BootstrapTLS
+const (
+	TLSCAFile         = "ca.crt"
+	TLSCAKeyFile      = "ca.key"
+	TLSServerCertFile = "server.crt"
+	TLSServerKeyFile  = "server.key"
+	TLSClientCertFile = "client.crt"
+	TLSClientKeyFile  = "client.key"
+)
+
+// tlsValidity is the validity of the certificates generated
+const tlsValidity = 10 * 365 * 24 * time.Hour
+
+// GenerateCA creates a self-signed certificate authority, returned as PEM-encoded certificate and key
+func GenerateCA(commonName string) (certPEM []byte, keyPEM []byte, xerr fail.Error) {
+	if commonName == "" {
+		return nil, nil, fail.InvalidParameterCannotBeEmptyStringError("commonName")
+	}
+
+	template, xerr := newCertificateTemplate(commonName)
+	if xerr != nil {
+		return nil, nil, xerr
+	}
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, fail.ConvertError(err)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, fail.ConvertError(err)
+	}
+	return encodeCertificateAndKey(der, key)
+}
+
+// GenerateCertificate creates a certificate signed by the CA, returned as PEM-encoded certificate and key
+// If client is true, the certificate is usable for client authentication, otherwise for server authentication
+// of the names and IP addresses in hosts.
+func GenerateCertificate(caCertPEM, caKeyPEM []byte, commonName string, hosts []string, client bool) (certPEM []byte, keyPEM []byte, xerr fail.Error) {
+	if commonName == "" {
+		return nil, nil, fail.InvalidParameterCannotBeEmptyStringError("commonName")
+	}
+
+	ca, err := tls.X509KeyPair(caCertPEM, caKeyPEM)
+	if err != nil {
+		return nil, nil, fail.Wrap(err, "invalid CA")
+	}
+	caCert, err := x509.ParseCertificate(ca.Certificate[0])
+	if err != nil {
+		return nil, nil, fail.Wrap(err, "invalid CA certificate")
+	}
+
+	template, xerr := newCertificateTemplate(commonName)
+	if xerr != nil {
+		return nil, nil, xerr
+	}
+	template.KeyUsage = x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment
+	if client {
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	} else {
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+		for _, v := range hosts {
+			if ip := net.ParseIP(v); ip != nil {
+				template.IPAddresses = append(template.IPAddresses, ip)
+			} else {
+				template.DNSNames = append(template.DNSNames, v)
+			}
+		}
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, fail.ConvertError(err)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, ca.PrivateKey)
+	if err != nil {
+		return nil, nil, fail.ConvertError(err)
+	}
+	return encodeCertificateAndKey(der, key)
+}
+
+// BootstrapTLS makes sure folder dir contains a self-signed CA, a server certificate for hosts and a client
+// certificate signed by this CA, generating what is missing; existing files are kept untouched, unless the CA
+// has to be generated (in which case the certificates are generated again).
+// The client certificate has the common name "safescale": every user presenting it has the same identity. Use
+// IssueClientCertificate to give each user its own identity.
+func BootstrapTLS(dir string, hosts []string) (xerr fail.Error) {
+	if dir == "" {
+		return fail.InvalidParameterCannotBeEmptyStringError("dir")
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fail.Wrap(err, "failed to create folder '%s'", dir)
+	}
+
+	caCertPEM, caKeyPEM, newCA, xerr := bootstrapPair(dir, TLSCAFile, TLSCAKeyFile, false, func() ([]byte, []byte, fail.Error) {
+		return GenerateCA("SafeScale CA")
+	})
+	if xerr != nil {
+		return xerr
+	}
+
+	_, _, _, xerr = bootstrapPair(dir, TLSServerCertFile, TLSServerKeyFile, newCA, func() ([]byte, []byte, fail.Error) {
+		return GenerateCertificate(caCertPEM, caKeyPEM, "safescaled", hosts, false)
+	})
+	if xerr != nil {
+		return xerr
+	}
+
+	_, _, _, xerr = bootstrapPair(dir, TLSClientCertFile, TLSClientKeyFile, newCA, func() ([]byte, []byte, fail.Error) {
+		return GenerateCertificate(caCertPEM, caKeyPEM, "safescale", nil, true)
+	})
+	return xerr
+}
+
+// IssueClientCertificate generates a client certificate with common name identity, signed by the CA bootstrapped
+// in folder dir, and writes it in dir as 'client-<identity>.crt' and 'client-<identity>.key'; an existing certificate
+// of the same identity is replaced.
+// Returns the paths of the certificate and key files.
+func IssueClientCertificate(dir, identity string) (certPath string, keyPath string, xerr fail.Error) {
+	if dir == "" {
+		return "", "", fail.InvalidParameterCannotBeEmptyStringError("dir")
+	}
+	if identity == "" {
+		return "", "", fail.InvalidParameterCannotBeEmptyStringError("identity")
+	}
+	if !validIdentity.MatchString(identity) {
+		return "", "", fail.InvalidParameterError("identity", "must contain only letters, digits, '.', '_', '@' and '-'")
+	}
+
+	caCertPEM, err := ioutil.ReadFile(filepath.Join(dir, TLSCAFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", "", fail.NotFoundError("no CA found in '%s', TLS has to be bootstrapped first", dir)
+		}
+		return "", "", fail.Wrap(err, "failed to read CA certificate")
+	}
+	caKeyPEM, err := ioutil.ReadFile(filepath.Join(dir, TLSCAKeyFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", "", fail.NotFoundError("no CA key found in '%s', TLS has to be bootstrapped first", dir)
+		}
+		return "", "", fail.Wrap(err, "failed to read CA key")
+	}
+
+	certFile := "client-" + identity + ".crt"
+	keyFile := "client-" + identity + ".key"
+	_, _, _, xerr = bootstrapPair(dir, certFile, keyFile, true, func() ([]byte, []byte, fail.Error) {
+		return GenerateCertificate(caCertPEM, caKeyPEM, identity, nil, true)
+	})
+	if xerr != nil {
+		return "", "", xerr
+	}
+	return filepath.Join(dir, certFile), filepath.Join(dir, keyFile), nil
+}
+
+// validIdentity is the syntax of the identities accepted by IssueClientCertificate (used in file names)
+var validIdentity = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._@-]*$`)
+
+// bootstrapPair reads the certificate and key files in dir, or generates and writes them if they do not exist or if force is true
+func bootstrapPair(dir, certFile, keyFile string, force bool, generate func() ([]byte, []byte, fail.Error)) (_ []byte, _ []byte, generated bool, xerr fail.Error) {
+	certPath := filepath.Join(dir, certFile)
+	keyPath := filepath.Join(dir, keyFile)
+
+	if !force {
+		certPEM, certErr := ioutil.ReadFile(certPath)
+		keyPEM, keyErr := ioutil.ReadFile(keyPath)
+		if certErr == nil && keyErr == nil {
+			return certPEM, keyPEM, false, nil
+		}
+		if certErr != nil && !os.IsNotExist(certErr) {
+			return nil, nil, false, fail.Wrap(certErr, "failed to read '%s'", certPath)
+		}
+		if keyErr != nil && !os.IsNotExist(keyErr) {
+			return nil, nil, false, fail.Wrap(keyErr, "failed to read '%s'", keyPath)
+		}
+	}
+
+	certPEM, keyPEM, xerr := generate()
+	if xerr != nil {
+		return nil, nil, false, xerr
+	}
+	if err := ioutil.WriteFile(certPath, certPEM, 0644); err != nil {
+		return nil, nil, false, fail.Wrap(err, "failed to write '%s'", certPath)
+	}
+	if err := ioutil.WriteFile(keyPath, keyPEM, 0600); err != nil {
+		return nil, nil, false, fail.Wrap(err, "failed to write '%s'", keyPath)
+	}
+	return certPEM, keyPEM, true, nil
+}
+
+// ServerTLSConfig returns the TLS configuration of a server using certFile and keyFile
+// If clientCAFile is not empty, clients have to present a certificate signed by this CA (mutual TLS).
+func ServerTLSConfig(certFile, keyFile, clientCAFile string) (*tls.Config, fail.Error) {
+	if certFile == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("certFile")
+	}
+	if keyFile == "" {
+		return nil, fail.InvalidParameterCannotBeEmptyStringError("keyFile")
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fail.Wrap(err, "failed to load server certificate")
+	}
+
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if clientCAFile != "" {
+		pool, xerr := loadCertPool(clientCAFile)
+		if xerr != nil {
+			return nil, xerr
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
+
+// ClientTLSConfig returns the TLS configuration of a client
+// If caFile is empty, the server certificate is verified using the CAs of the system.
+// If certFile and keyFile are not empty, the client presents this certificate to the server (mutual TLS).
+// If serverName is not empty, it overrides the name expected in the server certificate.
+func ClientTLSConfig(caFile, certFile, keyFile, serverName string) (*tls.Config, fail.Error) {
+	if (certFile == "") != (keyFile == "") {
+		return nil, fail.InvalidRequestError("client certificate and key must be both set or both empty")
+	}
+
+	config := &tls.Config{
+		ServerName: serverName,
+		MinVersion: tls.VersionTLS12,
+	}
+	if caFile != "" {
+		pool, xerr := loadCertPool(caFile)
+		if xerr != nil {
+			return nil, xerr
+		}
+		config.RootCAs = pool
+	}
+	if certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fail.Wrap(err, "failed to load client certificate")
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
+
+// loadCertPool returns a certificate pool containing the certificates of a PEM file
+func loadCertPool(file string) (*x509.CertPool, fail.Error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fail.Wrap(err, "failed to read CA file '%s'", file)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(content) {
+		return nil, fail.SyntaxError("no valid certificate found in CA file '%s'", file)
+	}
+	return pool, nil
+}
+
+// newCertificateTemplate returns a certificate template with a random serial number
+func newCertificateTemplate(commonName string) (*x509.Certificate, fail.Error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fail.ConvertError(err)
+	}
+
+	now := time.Now()
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName, Organization: []string{"SafeScale"}},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(tlsValidity),
+	}, nil
+}
+
+// encodeCertificateAndKey PEM-encodes a DER certificate and its key
+func encodeCertificateAndKey(der []byte, key *ecdsa.PrivateKey) ([]byte, []byte, fail.Error) {
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, fail.ConvertError(err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM, nil
+}
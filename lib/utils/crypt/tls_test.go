@@ -0,0 +1,144 @@
+/*
+ * Copyright 2018-2021, CS Systemes d'Information, http://csgroup.eu
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package crypt
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/CS-SI/SafeScale/lib/utils/fail"
+)
+
+// handshake connects a client to a server using the given configurations, and returns the error of the client side
+func handshake(t *testing.T, serverConfig, clientConfig *tls.Config) error {
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", serverConfig)
+	require.Nil(t, err)
+	defer func() { _ = listener.Close() }()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		_ = conn.(*tls.Conn).Handshake()
+		_, _ = conn.Write([]byte("ok"))
+		_ = conn.Close()
+	}()
+
+	conn, err := tls.Dial("tcp", listener.Addr().String(), clientConfig)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+
+	// with TLS 1.3, a rejected client certificate is reported on first read
+	_, err = ioutil.ReadAll(conn)
+	return err
+}
+
+func TestBootstrapTLS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "safescale-tls")
+	require.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	xerr := BootstrapTLS(dir, []string{"localhost", "127.0.0.1"})
+	require.Nil(t, xerr)
+
+	path := func(name string) string { return filepath.Join(dir, name) }
+	serverCert, err := ioutil.ReadFile(path(TLSServerCertFile))
+	require.Nil(t, err)
+
+	// Existing files are kept
+	xerr = BootstrapTLS(dir, []string{"localhost", "127.0.0.1"})
+	require.Nil(t, xerr)
+	again, err := ioutil.ReadFile(path(TLSServerCertFile))
+	require.Nil(t, err)
+	assert.Equal(t, serverCert, again)
+
+	info, err := os.Stat(path(TLSCAKeyFile))
+	require.Nil(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	serverConfig, xerr := ServerTLSConfig(path(TLSServerCertFile), path(TLSServerKeyFile), path(TLSCAFile))
+	require.Nil(t, xerr)
+
+	// mutual TLS succeeds with the client certificate
+	clientConfig, xerr := ClientTLSConfig(path(TLSCAFile), path(TLSClientCertFile), path(TLSClientKeyFile), "")
+	require.Nil(t, xerr)
+	clientConfig.ServerName = "127.0.0.1"
+	assert.Nil(t, handshake(t, serverConfig, clientConfig))
+
+	// ... and fails without
+	clientConfig, xerr = ClientTLSConfig(path(TLSCAFile), "", "", "localhost")
+	require.Nil(t, xerr)
+	assert.NotNil(t, handshake(t, serverConfig, clientConfig))
+
+	// server authentication fails without the CA
+	clientConfig, xerr = ClientTLSConfig("", path(TLSClientCertFile), path(TLSClientKeyFile), "localhost")
+	require.Nil(t, xerr)
+	assert.NotNil(t, handshake(t, serverConfig, clientConfig))
+}
+
+func TestIssueClientCertificate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "safescale-tls")
+	require.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	_, _, xerr := IssueClientCertificate(dir, "alice")
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrNotFound{}, xerr)
+
+	require.Nil(t, BootstrapTLS(dir, []string{"localhost", "127.0.0.1"}))
+
+	_, _, xerr = IssueClientCertificate(dir, "../alice")
+	require.NotNil(t, xerr)
+	assert.IsType(t, &fail.ErrInvalidParameter{}, xerr)
+
+	certPath, keyPath, xerr := IssueClientCertificate(dir, "alice")
+	require.Nil(t, xerr)
+	assert.Equal(t, filepath.Join(dir, "client-alice.crt"), certPath)
+	info, err := os.Stat(keyPath)
+	require.Nil(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	pair, err := tls.LoadX509KeyPair(certPath, keyPath)
+	require.Nil(t, err)
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	require.Nil(t, err)
+	assert.Equal(t, "alice", cert.Subject.CommonName)
+
+	serverConfig, xerr := ServerTLSConfig(filepath.Join(dir, TLSServerCertFile), filepath.Join(dir, TLSServerKeyFile), filepath.Join(dir, TLSCAFile))
+	require.Nil(t, xerr)
+	clientConfig, xerr := ClientTLSConfig(filepath.Join(dir, TLSCAFile), certPath, keyPath, "localhost")
+	require.Nil(t, xerr)
+	assert.Nil(t, handshake(t, serverConfig, clientConfig))
+}
+
+func TestClientTLSConfig_Invalid(t *testing.T) {
+	_, xerr := ClientTLSConfig("", "client.crt", "", "")
+	assert.NotNil(t, xerr)
+
+	_, xerr = ClientTLSConfig("/nonexistent/ca.crt", "", "", "")
+	assert.NotNil(t, xerr)
+}